@@ -68,6 +68,38 @@ func updateAllDatabaseTablesStructure() error {
 
 	log.BootInfof("[database.updateAllDatabaseTablesStructure] two factor recovery code table maintained successfully")
 
+	err = datastore.Container.UserStore.SyncStructs(new(models.BookMember))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof("[database.updateAllDatabaseTablesStructure] book member table maintained successfully")
+
+	err = datastore.Container.UserStore.SyncStructs(new(models.UserExternalAuth))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof("[database.updateAllDatabaseTablesStructure] user external auth table maintained successfully")
+
+	err = datastore.Container.UserStore.SyncStructs(new(models.UserWebAuthnCredential))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof("[database.updateAllDatabaseTablesStructure] user webauthn credential table maintained successfully")
+
+	err = datastore.Container.UserStore.SyncStructs(new(models.UserWebAuthnChallenge))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof("[database.updateAllDatabaseTablesStructure] user webauthn challenge table maintained successfully")
+
 	err = datastore.Container.TokenStore.SyncStructs(new(models.TokenRecord))
 
 	if err != nil {
@@ -116,5 +148,125 @@ func updateAllDatabaseTablesStructure() error {
 
 	log.BootInfof("[database.updateAllDatabaseTablesStructure] transaction tag index table maintained successfully")
 
+	err = datastore.Container.UserDataStore.SyncStructs(new(models.TransactionExternalIndex))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof("[database.updateAllDatabaseTablesStructure] transaction external index table maintained successfully")
+
+	err = datastore.Container.UserDataStore.SyncStructs(new(models.TransactionSplit))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof("[database.updateAllDatabaseTablesStructure] transaction split table maintained successfully")
+
+	err = datastore.Container.UserDataStore.SyncStructs(new(models.TransactionAttachment))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof("[database.updateAllDatabaseTablesStructure] transaction attachment table maintained successfully")
+
+	err = datastore.Container.UserDataStore.SyncStructs(new(models.TransactionSchedule))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof("[database.updateAllDatabaseTablesStructure] transaction schedule table maintained successfully")
+
+	err = datastore.Container.UserDataStore.SyncStructs(new(models.TransactionRule))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof("[database.updateAllDatabaseTablesStructure] transaction rule table maintained successfully")
+
+	err = datastore.Container.UserDataStore.SyncStructs(new(models.DataRevision))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof("[database.updateAllDatabaseTablesStructure] data revision table maintained successfully")
+
+	err = datastore.Container.UserDataStore.SyncStructs(new(models.BookLock))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof("[database.updateAllDatabaseTablesStructure] book lock table maintained successfully")
+
+	err = datastore.Container.UserDataStore.SyncStructs(new(models.AccountReconciliation))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof("[database.updateAllDatabaseTablesStructure] account reconciliation table maintained successfully")
+
+	err = datastore.Container.UserDataStore.SyncStructs(new(models.Loan))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof("[database.updateAllDatabaseTablesStructure] loan table maintained successfully")
+
+	err = datastore.Container.UserDataStore.SyncStructs(new(models.InvestmentHolding))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof("[database.updateAllDatabaseTablesStructure] investment holding table maintained successfully")
+
+	err = datastore.Container.UserDataStore.SyncStructs(new(models.InvestmentTrade))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof("[database.updateAllDatabaseTablesStructure] investment trade table maintained successfully")
+
+	err = datastore.Container.UserDataStore.SyncStructs(new(models.InvestmentPrice))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof("[database.updateAllDatabaseTablesStructure] investment price table maintained successfully")
+
+	err = datastore.Container.UserDataStore.SyncStructs(new(models.SavingsGoal))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof("[database.updateAllDatabaseTablesStructure] savings goal table maintained successfully")
+
+	err = datastore.Container.UserDataStore.SyncStructs(new(models.Budget))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof("[database.updateAllDatabaseTablesStructure] budget table maintained successfully")
+
+	err = datastore.Container.UserStore.SyncStructs(new(models.ExchangeRate))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof("[database.updateAllDatabaseTablesStructure] exchange rate table maintained successfully")
+
 	return nil
 }
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	clis "github.com/f97/gofire/pkg/cli"
+	"github.com/f97/gofire/pkg/errs"
+	"github.com/f97/gofire/pkg/log"
+)
+
+// ExchangeRates represents the exchange rates command
+var ExchangeRates = &cli.Command{
+	Name:  "exchangerates",
+	Usage: "gofire exchange rates maintenance",
+	Subcommands: []*cli.Command{
+		{
+			Name:   "history-backfill",
+			Usage:  "Request historical exchange rates from current data source and save them",
+			Action: backfillExchangeRates,
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:     "start-date",
+					Aliases:  []string{"s"},
+					Required: true,
+					Usage:    "Start date (format: yyyy-mm-dd)",
+				},
+				&cli.StringFlag{
+					Name:    "end-date",
+					Aliases: []string{"e"},
+					Usage:   "End date (format: yyyy-mm-dd, default is today)",
+				},
+			},
+		},
+	},
+}
+
+func backfillExchangeRates(c *cli.Context) error {
+	_, err := initializeSystem(c)
+
+	if err != nil {
+		return err
+	}
+
+	startDate, err := time.Parse("2006-01-02", c.String("start-date"))
+
+	if err != nil {
+		log.BootErrorf("[exchange_rates.backfillExchangeRates] start date is invalid")
+		return errs.ErrExchangeRateDateInvalid
+	}
+
+	endDate := time.Now().UTC()
+
+	if c.String("end-date") != "" {
+		endDate, err = time.Parse("2006-01-02", c.String("end-date"))
+
+		if err != nil {
+			log.BootErrorf("[exchange_rates.backfillExchangeRates] end date is invalid")
+			return errs.ErrExchangeRateDateInvalid
+		}
+	}
+
+	log.BootInfof("[exchange_rates.backfillExchangeRates] starting backfilling exchange rates from %s to %s", startDate.Format("2006-01-02"), endDate.Format("2006-01-02"))
+
+	count, err := clis.ExchangeRates.BackfillExchangeRates(c, startDate, endDate)
+
+	if err != nil {
+		log.BootErrorf("[exchange_rates.backfillExchangeRates] error occurs when backfilling exchange rates")
+		return err
+	}
+
+	log.BootInfof("[exchange_rates.backfillExchangeRates] exchange rates of %d days have been saved", count)
+
+	return nil
+}
@@ -10,9 +10,12 @@ import (
 	"github.com/f97/gofire/pkg/exchangerates"
 	"github.com/f97/gofire/pkg/log"
 	"github.com/f97/gofire/pkg/mail"
+	"github.com/f97/gofire/pkg/oidc"
 	"github.com/f97/gofire/pkg/settings"
+	"github.com/f97/gofire/pkg/storage"
 	"github.com/f97/gofire/pkg/utils"
 	"github.com/f97/gofire/pkg/uuid"
+	"github.com/f97/gofire/pkg/webauthn"
 )
 
 func initializeSystem(c *cli.Context) (*settings.Config, error) {
@@ -102,6 +105,33 @@ func initializeSystem(c *cli.Context) (*settings.Config, error) {
 		return nil, err
 	}
 
+	err = oidc.InitializeOIDCProvider(config)
+
+	if err != nil {
+		if !isDisableBootLog {
+			log.BootErrorf("[initializer.initializeSystem] initializes openid connect provider failed, because %s", err.Error())
+		}
+		return nil, err
+	}
+
+	err = webauthn.InitializeWebAuthnRelyingParty(config)
+
+	if err != nil {
+		if !isDisableBootLog {
+			log.BootErrorf("[initializer.initializeSystem] initializes webauthn relying party failed, because %s", err.Error())
+		}
+		return nil, err
+	}
+
+	err = storage.InitializeStorageContainer(config)
+
+	if err != nil {
+		if !isDisableBootLog {
+			log.BootErrorf("[initializer.initializeSystem] initializes object storage failed, because %s", err.Error())
+		}
+		return nil, err
+	}
+
 	cfgJson, _ := json.Marshal(getConfigWithoutSensitiveData(config))
 
 	if !isDisableBootLog {
@@ -121,7 +151,9 @@ func getConfigWithoutSensitiveData(config *settings.Config) *settings.Config {
 
 	clonedConfig.DatabaseConfig.DatabasePassword = "****"
 	clonedConfig.SMTPConfig.SMTPPasswd = "****"
+	clonedConfig.S3Config.SecretAccessKey = "****"
 	clonedConfig.SecretKey = "****"
+	clonedConfig.OIDCClientSecret = "****"
 
 	return clonedConfig
 }
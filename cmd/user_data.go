@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/f97/gofire/pkg/models"
 	"os"
+	"strings"
 
 	"github.com/urfave/cli/v2"
 
@@ -231,7 +232,7 @@ var UserData = &cli.Command{
 		},
 		{
 			Name:   "transaction-export",
-			Usage:  "Export user all transactions to csv file",
+			Usage:  "Export user all transactions to csv, ledger-cli, hledger or beancount file",
 			Action: exportUserTransaction,
 			Flags: []cli.Flag{
 				&cli.StringFlag{
@@ -246,6 +247,55 @@ var UserData = &cli.Command{
 					Required: true,
 					Usage:    "Specific exported file path (e.g. transaction.csv)",
 				},
+				&cli.StringFlag{
+					Name:  "format",
+					Value: "csv",
+					Usage: "Exported file format (csv, ledger, hledger or beancount)",
+				},
+			},
+		},
+		{
+			Name:   "transaction-import",
+			Usage:  "Import transactions from csv file to user",
+			Action: importUserTransaction,
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:     "username",
+					Aliases:  []string{"n"},
+					Required: true,
+					Usage:    "Specific user name",
+				},
+				&cli.StringFlag{
+					Name:     "file",
+					Aliases:  []string{"f"},
+					Required: true,
+					Usage:    "Specific imported file path (e.g. transaction.csv)",
+				},
+				&cli.BoolFlag{
+					Name:  "create-missing",
+					Usage: "Create the accounts, categories and tags which do not exist",
+				},
+				&cli.BoolFlag{
+					Name:  "dry-run",
+					Usage: "Only check the imported file and do not save any data",
+				},
+			},
+		},
+		{
+			Name:   "transaction-purge-trash",
+			Usage:  "Permanently delete user deleted transactions which exceed the trash retention days",
+			Action: purgeUserTrashTransactions,
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:     "username",
+					Aliases:  []string{"n"},
+					Required: true,
+					Usage:    "Specific user name",
+				},
+				&cli.BoolFlag{
+					Name:  "all",
+					Usage: "Purge all deleted transactions regardless of the trash retention days",
+				},
 			},
 		},
 	},
@@ -570,7 +620,7 @@ func exportUserTransaction(c *cli.Context) error {
 
 	log.BootInfof("[user_data.exportUserTransaction] starting exporting user \"%s\" data", username)
 
-	content, err := clis.UserData.ExportTransaction(c, username)
+	content, err := clis.UserData.ExportTransaction(c, username, c.String("format"))
 
 	if err != nil {
 		log.BootErrorf("[user_data.exportUserTransaction] error occurs when exporting user data")
@@ -589,6 +639,78 @@ func exportUserTransaction(c *cli.Context) error {
 	return nil
 }
 
+func importUserTransaction(c *cli.Context) error {
+	_, err := initializeSystem(c)
+
+	if err != nil {
+		return err
+	}
+
+	username := c.String("username")
+	filePath := c.String("file")
+
+	if filePath == "" {
+		log.BootErrorf("[user_data.importUserTransaction] import file path is not specified")
+		return os.ErrNotExist
+	}
+
+	content, err := os.ReadFile(filePath)
+
+	if err != nil {
+		log.BootErrorf("[user_data.importUserTransaction] failed to read %s", filePath)
+		return err
+	}
+
+	log.BootInfof("[user_data.importUserTransaction] starting importing user \"%s\" data", username)
+
+	result, err := clis.UserData.ImportTransaction(c, username, content, c.Bool("create-missing"), c.Bool("dry-run"))
+
+	if err != nil {
+		log.BootErrorf("[user_data.importUserTransaction] error occurs when importing user data")
+		return err
+	}
+
+	for i := 0; i < len(result.Conflicts); i++ {
+		conflict := result.Conflicts[i]
+		log.BootWarnf("[user_data.importUserTransaction] transaction in line %d has the same time as existed transactions (%s)", conflict.LineNumber, strings.Join(conflict.ExistedTransactionIds, ","))
+	}
+
+	if len(result.MissingAccountNames) > 0 || len(result.MissingCategoryNames) > 0 || len(result.MissingTagNames) > 0 {
+		log.BootWarnf("[user_data.importUserTransaction] missing accounts: %s, missing categories: %s, missing tags: %s", strings.Join(result.MissingAccountNames, ","), strings.Join(result.MissingCategoryNames, ","), strings.Join(result.MissingTagNames, ","))
+	}
+
+	if result.DryRun {
+		log.BootInfof("[user_data.importUserTransaction] %d of %d transactions can be imported (dry run)", result.ImportedCount, result.TotalCount)
+	} else {
+		log.BootInfof("[user_data.importUserTransaction] %d transactions have been imported, %d new accounts, %d new categories and %d new tags have been created", result.ImportedCount, len(result.NewAccountNames), len(result.NewCategoryNames), len(result.NewTagNames))
+	}
+
+	return nil
+}
+
+func purgeUserTrashTransactions(c *cli.Context) error {
+	_, err := initializeSystem(c)
+
+	if err != nil {
+		return err
+	}
+
+	username := c.String("username")
+
+	log.BootInfof("[user_data.purgeUserTrashTransactions] starting purging user \"%s\" deleted transactions", username)
+
+	purgedCount, err := clis.UserData.PurgeTrashTransactions(c, username, c.Bool("all"))
+
+	if err != nil {
+		log.BootErrorf("[user_data.purgeUserTrashTransactions] error occurs when purging user deleted transactions")
+		return err
+	}
+
+	log.BootInfof("[user_data.purgeUserTrashTransactions] %d deleted transactions of user \"%s\" have been purged", purgedCount, username)
+
+	return nil
+}
+
 func printUserInfo(user *models.User) {
 	fmt.Printf("[Uid] %d\n", user.Uid)
 	fmt.Printf("[Username] %s\n", user.Username)
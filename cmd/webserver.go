@@ -2,7 +2,9 @@ package cmd
 
 import (
 	"fmt"
+	"net/http"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/gin-contrib/cache"
@@ -15,6 +17,7 @@ import (
 
 	"github.com/f97/gofire/pkg/api"
 	"github.com/f97/gofire/pkg/core"
+	"github.com/f97/gofire/pkg/cron"
 	"github.com/f97/gofire/pkg/errs"
 	"github.com/f97/gofire/pkg/log"
 	"github.com/f97/gofire/pkg/middlewares"
@@ -70,6 +73,13 @@ func startWebServer(c *cli.Context) error {
 
 	log.BootInfof("[server.startWebServer] %s%s", serverInfo, uuidServerInfo)
 
+	err = cron.InitializeCronJobs(config)
+
+	if err != nil {
+		log.BootErrorf("[server.startWebServer] initializes cron jobs failed, because %s", err.Error())
+		return err
+	}
+
 	if config.Mode == settings.MODE_PRODUCTION {
 		gin.SetMode(gin.ReleaseMode)
 	}
@@ -194,9 +204,24 @@ func startWebServer(c *cli.Context) error {
 			{
 				twoFactorRoute.POST("/authorize.json", bindApiWithTokenUpdate(api.Authorizations.TwoFactorAuthorizeHandler, config))
 				twoFactorRoute.POST("/recovery.json", bindApiWithTokenUpdate(api.Authorizations.TwoFactorAuthorizeByRecoveryCodeHandler, config))
+
+				if config.EnableWebAuthn {
+					twoFactorRoute.GET("/webauthn/options.json", bindApi(api.WebAuthnAuthorizations.TwoFactorAuthorizeOptionsHandler))
+					twoFactorRoute.POST("/webauthn/authorize.json", bindApiWithTokenUpdate(api.WebAuthnAuthorizations.TwoFactorAuthorizeHandler, config))
+				}
 			}
 		}
 
+		if config.EnableWebAuthn {
+			apiRoute.GET("/webauthn/authorize/options.json", bindApi(api.WebAuthnAuthorizations.AuthorizeOptionsHandler))
+			apiRoute.POST("/webauthn/authorize.json", bindApiWithTokenUpdate(api.WebAuthnAuthorizations.AuthorizeHandler, config))
+		}
+
+		if config.EnableOIDC {
+			apiRoute.GET("/oidc/authorize.json", bindApi(api.OIDCAuthorizations.AuthorizeHandler))
+			apiRoute.POST("/oidc/callback.json", bindApiWithTokenUpdate(api.OIDCAuthorizations.CallbackHandler, config))
+		}
+
 		if config.EnableUserRegister {
 			apiRoute.POST("/register.json", bindApiWithTokenUpdate(api.Users.UserRegisterHandler, config))
 		}
@@ -225,12 +250,15 @@ func startWebServer(c *cli.Context) error {
 
 		apiV1Route := apiRoute.Group("/v1")
 		apiV1Route.Use(bindMiddleware(middlewares.JWTAuthorization))
+		apiV1Route.Use(bindMiddleware(middlewares.BookMembership))
 		{
 			// Tokens
 			apiV1Route.GET("/tokens/list.json", bindApi(api.Tokens.TokenListHandler))
 			apiV1Route.POST("/tokens/revoke.json", bindApi(api.Tokens.TokenRevokeHandler))
 			apiV1Route.POST("/tokens/revoke_all.json", bindApi(api.Tokens.TokenRevokeAllHandler))
 			apiV1Route.POST("/tokens/refresh.json", bindApiWithTokenUpdate(api.Tokens.TokenRefreshHandler, config))
+			apiV1Route.GET("/tokens/personal/list.json", bindApi(api.Tokens.PersonalAccessTokenListHandler))
+			apiV1Route.POST("/tokens/personal/add.json", bindApi(api.Tokens.PersonalAccessTokenCreateHandler))
 
 			// Users
 			apiV1Route.GET("/users/profile/get.json", bindApi(api.Users.UserProfileHandler))
@@ -249,23 +277,82 @@ func startWebServer(c *cli.Context) error {
 				apiV1Route.POST("/users/2fa/recovery/regenerate.json", bindApi(api.TwoFactorAuthorizations.TwoFactorRecoveryCodeRegenerateHandler))
 			}
 
+			// WebAuthn Credentials
+			if config.EnableWebAuthn {
+				apiV1Route.GET("/users/webauthn/list.json", bindApi(api.WebAuthnAuthorizations.CredentialListHandler))
+				apiV1Route.POST("/users/webauthn/register/options.json", bindApi(api.WebAuthnAuthorizations.CredentialRegisterOptionsHandler))
+				apiV1Route.POST("/users/webauthn/register.json", bindApi(api.WebAuthnAuthorizations.CredentialRegisterHandler))
+				apiV1Route.POST("/users/webauthn/revoke.json", bindApi(api.WebAuthnAuthorizations.CredentialRevokeHandler))
+			}
+
 			// Data
 			apiV1Route.GET("/data/statistics.json", bindApi(api.DataManagements.DataStatisticsHandler))
 			apiV1Route.POST("/data/clear.json", bindApi(api.DataManagements.ClearDataHandler))
 
 			if config.EnableDataExport {
 				apiV1Route.GET("/data/export.csv", bindCsv(api.DataManagements.ExportDataHandler))
+				apiV1Route.GET("/data/export", bindExportedFile(api.DataManagements.ExportDataHandler))
+			}
+
+			if config.EnableDataImport {
+				apiV1Route.POST("/data/import.json", bindApi(api.DataManagements.ImportDataHandler))
+				apiV1Route.POST("/data/import_statement.json", bindApi(api.DataManagements.ImportStatementHandler))
 			}
 
 			// Accounts
 			apiV1Route.GET("/accounts/list.json", bindApi(api.Accounts.AccountListHandler))
 			apiV1Route.GET("/accounts/get.json", bindApi(api.Accounts.AccountGetHandler))
+			apiV1Route.GET("/accounts/net_worth.json", bindApi(api.Accounts.AccountNetWorthHandler))
 			apiV1Route.POST("/accounts/add.json", bindApi(api.Accounts.AccountCreateHandler))
 			apiV1Route.POST("/accounts/modify.json", bindApi(api.Accounts.AccountModifyHandler))
 			apiV1Route.POST("/accounts/hide.json", bindApi(api.Accounts.AccountHideHandler))
 			apiV1Route.POST("/accounts/move.json", bindApi(api.Accounts.AccountMoveHandler))
 			apiV1Route.POST("/accounts/delete.json", bindApi(api.Accounts.AccountDeleteHandler))
 
+			// Credit Cards
+			apiV1Route.GET("/accounts/credit_card/cycle.json", bindApi(api.CreditCards.CreditCardCycleHandler))
+			apiV1Route.POST("/accounts/credit_card/pay.json", bindApi(api.CreditCards.CreditCardPaymentHandler))
+
+			// Loans
+			apiV1Route.GET("/loans/list.json", bindApi(api.Loans.LoanListHandler))
+			apiV1Route.GET("/loans/get.json", bindApi(api.Loans.LoanGetHandler))
+			apiV1Route.GET("/loans/schedule.json", bindApi(api.Loans.LoanScheduleHandler))
+			apiV1Route.POST("/loans/add.json", bindApi(api.Loans.LoanCreateHandler))
+			apiV1Route.POST("/loans/modify.json", bindApi(api.Loans.LoanModifyHandler))
+			apiV1Route.POST("/loans/delete.json", bindApi(api.Loans.LoanDeleteHandler))
+
+			// Investments
+			apiV1Route.GET("/investments/holdings/list.json", bindApi(api.Investments.HoldingListHandler))
+			apiV1Route.GET("/investments/holdings/get.json", bindApi(api.Investments.HoldingGetHandler))
+			apiV1Route.POST("/investments/holdings/add.json", bindApi(api.Investments.HoldingCreateHandler))
+			apiV1Route.POST("/investments/holdings/modify.json", bindApi(api.Investments.HoldingModifyHandler))
+			apiV1Route.POST("/investments/holdings/delete.json", bindApi(api.Investments.HoldingDeleteHandler))
+			apiV1Route.GET("/investments/trades/list.json", bindApi(api.Investments.TradeListHandler))
+			apiV1Route.POST("/investments/trades/add.json", bindApi(api.Investments.TradeCreateHandler))
+			apiV1Route.POST("/investments/trades/delete.json", bindApi(api.Investments.TradeDeleteHandler))
+			apiV1Route.GET("/investments/prices/list.json", bindApi(api.Investments.PriceListHandler))
+			apiV1Route.POST("/investments/prices/add.json", bindApi(api.Investments.PriceCreateHandler))
+			apiV1Route.POST("/investments/prices/delete.json", bindApi(api.Investments.PriceDeleteHandler))
+			apiV1Route.POST("/investments/prices/import.json", bindApi(api.Investments.PriceImportHandler))
+			apiV1Route.GET("/investments/performance.json", bindApi(api.Investments.PerformanceHandler))
+
+			// Shared Books
+			apiV1Route.GET("/books/list.json", bindApi(api.Books.BookListHandler))
+			apiV1Route.GET("/books/members/list.json", bindApi(api.Books.MemberListHandler))
+			apiV1Route.POST("/books/members/invite.json", bindApi(api.Books.MemberInviteHandler))
+			apiV1Route.POST("/books/members/modify.json", bindApi(api.Books.MemberModifyHandler))
+			apiV1Route.POST("/books/members/remove.json", bindApi(api.Books.MemberRemoveHandler))
+			apiV1Route.POST("/books/accept.json", bindApi(api.Books.BookAcceptHandler))
+			apiV1Route.POST("/books/leave.json", bindApi(api.Books.BookLeaveHandler))
+
+			// Savings Goals
+			apiV1Route.GET("/savings_goals/list.json", bindApi(api.SavingsGoals.GoalListHandler))
+			apiV1Route.GET("/savings_goals/get.json", bindApi(api.SavingsGoals.GoalGetHandler))
+			apiV1Route.GET("/savings_goals/progress.json", bindApi(api.SavingsGoals.GoalProgressHandler))
+			apiV1Route.POST("/savings_goals/add.json", bindApi(api.SavingsGoals.GoalCreateHandler))
+			apiV1Route.POST("/savings_goals/modify.json", bindApi(api.SavingsGoals.GoalModifyHandler))
+			apiV1Route.POST("/savings_goals/delete.json", bindApi(api.SavingsGoals.GoalDeleteHandler))
+
 			// Transactions
 			apiV1Route.GET("/transactions/count.json", bindApi(api.Transactions.TransactionCountHandler))
 			apiV1Route.GET("/transactions/list.json", bindApi(api.Transactions.TransactionListHandler))
@@ -274,10 +361,29 @@ func startWebServer(c *cli.Context) error {
 			apiV1Route.GET("/transactions/amounts.json", bindApi(api.Transactions.TransactionAmountsHandler))
 			apiV1Route.GET("/transactions/amounts/by_month.json", bindApi(api.Transactions.TransactionMonthAmountsHandler))
 			apiV1Route.GET("/transactions/get.json", bindApi(api.Transactions.TransactionGetHandler))
+			apiV1Route.GET("/transactions/history.json", bindApi(api.Transactions.TransactionHistoryHandler))
 			apiV1Route.POST("/transactions/add.json", bindApi(api.Transactions.TransactionCreateHandler))
 			apiV1Route.POST("/transactions/modify.json", bindApi(api.Transactions.TransactionModifyHandler))
 			apiV1Route.POST("/transactions/delete.json", bindApi(api.Transactions.TransactionDeleteHandler))
 
+			// Transaction Trash
+			apiV1Route.GET("/transactions/trash/list.json", bindApi(api.Transactions.TransactionTrashListHandler))
+			apiV1Route.POST("/transactions/trash/restore.json", bindApi(api.Transactions.TransactionTrashRestoreHandler))
+			apiV1Route.POST("/transactions/trash/purge.json", bindApi(api.Transactions.TransactionTrashPurgeHandler))
+
+			// Transaction Attachments
+			apiV1Route.GET("/transactions/attachments/list.json", bindApi(api.TransactionAttachments.AttachmentListHandler))
+			apiV1Route.GET("/transactions/attachments/get", bindAttachmentFile(api.TransactionAttachments.AttachmentGetHandler))
+			apiV1Route.POST("/transactions/attachments/upload.json", bindApi(api.TransactionAttachments.AttachmentUploadHandler))
+			apiV1Route.POST("/transactions/attachments/delete.json", bindApi(api.TransactionAttachments.AttachmentDeleteHandler))
+
+			// Transaction Schedules
+			apiV1Route.GET("/transactions/schedules/list.json", bindApi(api.TransactionSchedules.ScheduleListHandler))
+			apiV1Route.GET("/transactions/schedules/get.json", bindApi(api.TransactionSchedules.ScheduleGetHandler))
+			apiV1Route.POST("/transactions/schedules/add.json", bindApi(api.TransactionSchedules.ScheduleCreateHandler))
+			apiV1Route.POST("/transactions/schedules/modify.json", bindApi(api.TransactionSchedules.ScheduleModifyHandler))
+			apiV1Route.POST("/transactions/schedules/delete.json", bindApi(api.TransactionSchedules.ScheduleDeleteHandler))
+
 			// Transaction Categories
 			apiV1Route.GET("/transaction/categories/list.json", bindApi(api.TransactionCategories.CategoryListHandler))
 			apiV1Route.GET("/transaction/categories/get.json", bindApi(api.TransactionCategories.CategoryGetHandler))
@@ -297,8 +403,41 @@ func startWebServer(c *cli.Context) error {
 			apiV1Route.POST("/transaction/tags/move.json", bindApi(api.TransactionTags.TagMoveHandler))
 			apiV1Route.POST("/transaction/tags/delete.json", bindApi(api.TransactionTags.TagDeleteHandler))
 
+			// Transaction Rules
+			apiV1Route.GET("/transaction/rules/list.json", bindApi(api.TransactionRules.RuleListHandler))
+			apiV1Route.GET("/transaction/rules/get.json", bindApi(api.TransactionRules.RuleGetHandler))
+			apiV1Route.POST("/transaction/rules/add.json", bindApi(api.TransactionRules.RuleCreateHandler))
+			apiV1Route.POST("/transaction/rules/modify.json", bindApi(api.TransactionRules.RuleModifyHandler))
+			apiV1Route.POST("/transaction/rules/move.json", bindApi(api.TransactionRules.RuleMoveHandler))
+			apiV1Route.POST("/transaction/rules/delete.json", bindApi(api.TransactionRules.RuleDeleteHandler))
+			apiV1Route.POST("/transaction/rules/apply.json", bindApi(api.TransactionRules.RuleApplyHandler))
+
+			// Account Reconciliations
+			apiV1Route.GET("/accounts/reconciliations/list.json", bindApi(api.AccountReconciliations.ReconciliationListHandler))
+			apiV1Route.GET("/accounts/reconciliations/get.json", bindApi(api.AccountReconciliations.ReconciliationGetHandler))
+			apiV1Route.GET("/accounts/reconciliations/transactions.json", bindApi(api.AccountReconciliations.ReconciliationTransactionsHandler))
+			apiV1Route.POST("/accounts/reconciliations/start.json", bindApi(api.AccountReconciliations.ReconciliationStartHandler))
+			apiV1Route.POST("/accounts/reconciliations/clear.json", bindApi(api.AccountReconciliations.ReconciliationClearHandler))
+			apiV1Route.POST("/accounts/reconciliations/complete.json", bindApi(api.AccountReconciliations.ReconciliationCompleteHandler))
+			apiV1Route.POST("/accounts/reconciliations/delete.json", bindApi(api.AccountReconciliations.ReconciliationDeleteHandler))
+
+			// Book Locks
+			apiV1Route.GET("/book_locks/list.json", bindApi(api.BookLocks.BookLockListHandler))
+			apiV1Route.GET("/book_locks/history.json", bindApi(api.BookLocks.BookLockHistoryHandler))
+			apiV1Route.POST("/book_locks/close.json", bindApi(api.BookLocks.BookLockCloseHandler))
+			apiV1Route.POST("/book_locks/reopen.json", bindApi(api.BookLocks.BookLockReopenHandler))
+
+			// Budgets
+			apiV1Route.GET("/budgets/list.json", bindApi(api.Budgets.BudgetListHandler))
+			apiV1Route.GET("/budgets/get.json", bindApi(api.Budgets.BudgetGetHandler))
+			apiV1Route.GET("/budgets/progress.json", bindApi(api.Budgets.BudgetProgressHandler))
+			apiV1Route.POST("/budgets/add.json", bindApi(api.Budgets.BudgetCreateHandler))
+			apiV1Route.POST("/budgets/modify.json", bindApi(api.Budgets.BudgetModifyHandler))
+			apiV1Route.POST("/budgets/delete.json", bindApi(api.Budgets.BudgetDeleteHandler))
+
 			// Exchange Rates
 			apiV1Route.GET("/exchange_rates/latest.json", bindApi(api.ExchangeRates.LatestExchangeRateHandler))
+			apiV1Route.GET("/exchange_rates/history.json", bindApi(api.ExchangeRates.ExchangeRateHistoryHandler))
 		}
 	}
 
@@ -374,6 +513,36 @@ func bindCsv(fn core.DataHandlerFunc) gin.HandlerFunc {
 	}
 }
 
+func bindExportedFile(fn core.DataHandlerFunc) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		c := core.WrapContext(ginCtx)
+		result, fileName, err := fn(c)
+
+		if err != nil {
+			utils.PrintDataErrorResult(c, "text/text", err)
+		} else if strings.HasSuffix(fileName, ".csv") {
+			utils.PrintDataSuccessResult(c, "text/csv", fileName, result)
+		} else if strings.HasSuffix(fileName, ".zip") {
+			utils.PrintDataSuccessResult(c, "application/zip", fileName, result)
+		} else {
+			utils.PrintDataSuccessResult(c, "text/plain", fileName, result)
+		}
+	}
+}
+
+func bindAttachmentFile(fn core.DataHandlerFunc) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		c := core.WrapContext(ginCtx)
+		result, fileName, err := fn(c)
+
+		if err != nil {
+			utils.PrintDataErrorResult(c, "text/text", err)
+		} else {
+			utils.PrintDataSuccessResult(c, http.DetectContentType(result), fileName, result)
+		}
+	}
+}
+
 func bindCachedPngImage(fn core.DataHandlerFunc, store persistence.CacheStore) gin.HandlerFunc {
 	return cache.CachePage(store, time.Minute, func(ginCtx *gin.Context) {
 		c := core.WrapContext(ginCtx)
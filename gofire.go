@@ -38,6 +38,7 @@ func main() {
 			cmd.UserData,
 			cmd.SecurityUtils,
 			cmd.Utilities,
+			cmd.ExchangeRates,
 		},
 		Flags: []cli.Flag{
 			&cli.StringFlag{
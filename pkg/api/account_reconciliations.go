@@ -0,0 +1,273 @@
+package api
+
+import (
+	"github.com/f97/gofire/pkg/core"
+	"github.com/f97/gofire/pkg/errs"
+	"github.com/f97/gofire/pkg/log"
+	"github.com/f97/gofire/pkg/models"
+	"github.com/f97/gofire/pkg/services"
+	"github.com/f97/gofire/pkg/utils"
+)
+
+// AccountReconciliationsApi represents account reconciliation api
+type AccountReconciliationsApi struct {
+	reconciliations *services.AccountReconciliationService
+	transactionTags *services.TransactionTagService
+}
+
+// Initialize an account reconciliation api singleton instance
+var (
+	AccountReconciliations = &AccountReconciliationsApi{
+		reconciliations: services.AccountReconciliations,
+		transactionTags: services.TransactionTags,
+	}
+)
+
+// ReconciliationListHandler returns reconciliation list of the specified account of current user
+func (a *AccountReconciliationsApi) ReconciliationListHandler(c *core.Context) (interface{}, *errs.Error) {
+	var reconciliationListReq models.AccountReconciliationListRequest
+	err := c.ShouldBindQuery(&reconciliationListReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[account_reconciliations.ReconciliationListHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentBookUid()
+	reconciliations, err := a.reconciliations.GetAllReconciliationsByAccountId(c, uid, reconciliationListReq.AccountId)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[account_reconciliations.ReconciliationListHandler] failed to get reconciliations of account \"id:%d\" for user \"uid:%d\", because %s", reconciliationListReq.AccountId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	reconciliationResps := make([]*models.AccountReconciliationInfoResponse, len(reconciliations))
+
+	for i := 0; i < len(reconciliations); i++ {
+		reconciliationResps[i], err = a.getReconciliationInfoResponse(c, reconciliations[i])
+
+		if err != nil {
+			log.ErrorfWithRequestId(c, "[account_reconciliations.ReconciliationListHandler] failed to get balances of reconciliation \"id:%d\" for user \"uid:%d\", because %s", reconciliations[i].ReconciliationId, uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+	}
+
+	return reconciliationResps, nil
+}
+
+// ReconciliationGetHandler returns one specific reconciliation of current user
+func (a *AccountReconciliationsApi) ReconciliationGetHandler(c *core.Context) (interface{}, *errs.Error) {
+	var reconciliationGetReq models.AccountReconciliationGetRequest
+	err := c.ShouldBindQuery(&reconciliationGetReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[account_reconciliations.ReconciliationGetHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentBookUid()
+	reconciliation, err := a.reconciliations.GetReconciliationById(c, uid, reconciliationGetReq.Id)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[account_reconciliations.ReconciliationGetHandler] failed to get reconciliation \"id:%d\" for user \"uid:%d\", because %s", reconciliationGetReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	reconciliationResp, err := a.getReconciliationInfoResponse(c, reconciliation)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[account_reconciliations.ReconciliationGetHandler] failed to get balances of reconciliation \"id:%d\" for user \"uid:%d\", because %s", reconciliationGetReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return reconciliationResp, nil
+}
+
+// ReconciliationTransactionsHandler returns the transactions which need to be reconciled in one specific reconciliation of current user
+func (a *AccountReconciliationsApi) ReconciliationTransactionsHandler(c *core.Context) (interface{}, *errs.Error) {
+	var reconciliationGetReq models.AccountReconciliationGetRequest
+	err := c.ShouldBindQuery(&reconciliationGetReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[account_reconciliations.ReconciliationTransactionsHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentBookUid()
+	reconciliation, err := a.reconciliations.GetReconciliationById(c, uid, reconciliationGetReq.Id)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[account_reconciliations.ReconciliationTransactionsHandler] failed to get reconciliation \"id:%d\" for user \"uid:%d\", because %s", reconciliationGetReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	transactions, err := a.reconciliations.GetReconciliationTransactions(c, reconciliation)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[account_reconciliations.ReconciliationTransactionsHandler] failed to get transactions of reconciliation \"id:%d\" for user \"uid:%d\", because %s", reconciliationGetReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	transactionIds := make([]int64, len(transactions))
+
+	for i := 0; i < len(transactions); i++ {
+		transactionIds[i] = a.getTagOwnerTransactionId(transactions[i])
+	}
+
+	allTransactionTagIds, err := a.transactionTags.GetAllTagIdsOfTransactions(c, uid, transactionIds)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[account_reconciliations.ReconciliationTransactionsHandler] failed to get transactions tag ids for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	transactionResps := make(models.TransactionInfoResponseSlice, len(transactions))
+
+	for i := 0; i < len(transactions); i++ {
+		transaction := transactions[i]
+		transactionResps[i] = transaction.ToTransactionInfoResponse(allTransactionTagIds[a.getTagOwnerTransactionId(transaction)], false)
+	}
+
+	return transactionResps, nil
+}
+
+// ReconciliationStartHandler starts a new reconciliation of the specified account by current user
+func (a *AccountReconciliationsApi) ReconciliationStartHandler(c *core.Context) (interface{}, *errs.Error) {
+	var reconciliationStartReq models.AccountReconciliationStartRequest
+	err := c.ShouldBindJSON(&reconciliationStartReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[account_reconciliations.ReconciliationStartHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentBookUid()
+	reconciliation := &models.AccountReconciliation{
+		Uid:               uid,
+		AccountId:         reconciliationStartReq.AccountId,
+		StatementUnixTime: reconciliationStartReq.StatementTime,
+		StatementBalance:  reconciliationStartReq.StatementBalance,
+	}
+
+	err = a.reconciliations.StartReconciliation(c, c.GetCurrentUid(), reconciliation)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[account_reconciliations.ReconciliationStartHandler] failed to start reconciliation of account \"id:%d\" for user \"uid:%d\", because %s", reconciliationStartReq.AccountId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.InfofWithRequestId(c, "[account_reconciliations.ReconciliationStartHandler] user \"uid:%d\" has started a new reconciliation \"id:%d\" of account \"id:%d\" successfully", uid, reconciliation.ReconciliationId, reconciliation.AccountId)
+
+	reconciliationResp, err := a.getReconciliationInfoResponse(c, reconciliation)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[account_reconciliations.ReconciliationStartHandler] failed to get balances of reconciliation \"id:%d\" for user \"uid:%d\", because %s", reconciliation.ReconciliationId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return reconciliationResp, nil
+}
+
+// ReconciliationClearHandler marks the specified transactions as cleared or uncleared in one specific reconciliation of current user
+func (a *AccountReconciliationsApi) ReconciliationClearHandler(c *core.Context) (interface{}, *errs.Error) {
+	var reconciliationClearReq models.AccountReconciliationClearRequest
+	err := c.ShouldBindJSON(&reconciliationClearReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[account_reconciliations.ReconciliationClearHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	transactionIds, err := utils.StringArrayToInt64Array(reconciliationClearReq.TransactionIds)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[account_reconciliations.ReconciliationClearHandler] parse transaction ids failed, because %s", err.Error())
+		return nil, errs.ErrTransactionIdInvalid
+	}
+
+	uid := c.GetCurrentBookUid()
+	err = a.reconciliations.SetTransactionsCleared(c, uid, c.GetCurrentUid(), reconciliationClearReq.Id, transactionIds, reconciliationClearReq.Cleared)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[account_reconciliations.ReconciliationClearHandler] failed to update cleared status of transactions in reconciliation \"id:%d\" for user \"uid:%d\", because %s", reconciliationClearReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	reconciliation, err := a.reconciliations.GetReconciliationById(c, uid, reconciliationClearReq.Id)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[account_reconciliations.ReconciliationClearHandler] failed to get reconciliation \"id:%d\" for user \"uid:%d\", because %s", reconciliationClearReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	reconciliationResp, err := a.getReconciliationInfoResponse(c, reconciliation)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[account_reconciliations.ReconciliationClearHandler] failed to get balances of reconciliation \"id:%d\" for user \"uid:%d\", because %s", reconciliationClearReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return reconciliationResp, nil
+}
+
+// ReconciliationCompleteHandler completes one specific reconciliation of current user
+func (a *AccountReconciliationsApi) ReconciliationCompleteHandler(c *core.Context) (interface{}, *errs.Error) {
+	var reconciliationCompleteReq models.AccountReconciliationCompleteRequest
+	err := c.ShouldBindJSON(&reconciliationCompleteReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[account_reconciliations.ReconciliationCompleteHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentBookUid()
+	err = a.reconciliations.CompleteReconciliation(c, uid, c.GetCurrentUid(), reconciliationCompleteReq.Id)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[account_reconciliations.ReconciliationCompleteHandler] failed to complete reconciliation \"id:%d\" for user \"uid:%d\", because %s", reconciliationCompleteReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.InfofWithRequestId(c, "[account_reconciliations.ReconciliationCompleteHandler] user \"uid:%d\" has completed reconciliation \"id:%d\"", uid, reconciliationCompleteReq.Id)
+	return true, nil
+}
+
+// ReconciliationDeleteHandler deletes an existed reconciliation by request parameters for current user
+func (a *AccountReconciliationsApi) ReconciliationDeleteHandler(c *core.Context) (interface{}, *errs.Error) {
+	var reconciliationDeleteReq models.AccountReconciliationDeleteRequest
+	err := c.ShouldBindJSON(&reconciliationDeleteReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[account_reconciliations.ReconciliationDeleteHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentBookUid()
+	err = a.reconciliations.DeleteReconciliation(c, uid, c.GetCurrentUid(), reconciliationDeleteReq.Id)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[account_reconciliations.ReconciliationDeleteHandler] failed to delete reconciliation \"id:%d\" for user \"uid:%d\", because %s", reconciliationDeleteReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.InfofWithRequestId(c, "[account_reconciliations.ReconciliationDeleteHandler] user \"uid:%d\" has deleted reconciliation \"id:%d\"", uid, reconciliationDeleteReq.Id)
+	return true, nil
+}
+
+func (a *AccountReconciliationsApi) getReconciliationInfoResponse(c *core.Context, reconciliation *models.AccountReconciliation) (*models.AccountReconciliationInfoResponse, error) {
+	bookBalance, clearedBalance, err := a.reconciliations.GetReconciliationBalances(c, reconciliation)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return reconciliation.ToAccountReconciliationInfoResponse(bookBalance, clearedBalance), nil
+}
+
+func (a *AccountReconciliationsApi) getTagOwnerTransactionId(transaction *models.Transaction) int64 {
+	if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+		return transaction.RelatedId
+	}
+
+	return transaction.TransactionId
+}
@@ -1,7 +1,9 @@
 package api
 
 import (
+	"math"
 	"sort"
+	"time"
 
 	"github.com/f97/gofire/pkg/core"
 	"github.com/f97/gofire/pkg/errs"
@@ -11,15 +13,23 @@ import (
 	"github.com/f97/gofire/pkg/validators"
 )
 
+const maxAccountNetWorthDataPoints = 400
+
 // AccountsApi represents account api
 type AccountsApi struct {
-	accounts *services.AccountService
+	accounts      *services.AccountService
+	users         *services.UserService
+	exchangeRates *services.ExchangeRateService
+	investments   *services.InvestmentService
 }
 
 // Initialize an account api singleton instance
 var (
 	Accounts = &AccountsApi{
-		accounts: services.Accounts,
+		accounts:      services.Accounts,
+		users:         services.Users,
+		exchangeRates: services.ExchangeRates,
+		investments:   services.Investments,
 	}
 )
 
@@ -33,7 +43,7 @@ func (a *AccountsApi) AccountListHandler(c *core.Context) (interface{}, *errs.Er
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
+	uid := c.GetCurrentBookUid()
 	accounts, err := a.accounts.GetAllAccountsByUid(c, uid)
 
 	if err != nil {
@@ -93,7 +103,7 @@ func (a *AccountsApi) AccountGetHandler(c *core.Context) (interface{}, *errs.Err
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
+	uid := c.GetCurrentBookUid()
 	accountAndSubAccounts, err := a.accounts.GetAccountAndSubAccountsByAccountId(c, uid, accountGetReq.Id)
 
 	if err != nil {
@@ -192,7 +202,21 @@ func (a *AccountsApi) AccountCreateHandler(c *core.Context) (interface{}, *errs.
 		return nil, errs.ErrAccountTypeInvalid
 	}
 
-	uid := c.GetCurrentUid()
+	if !a.isCreditCardSettingsValid(accountCreateReq.Category, accountCreateReq.Type, accountCreateReq.StatementClosingDay, accountCreateReq.PaymentDueDay, accountCreateReq.CreditLimit) {
+		log.WarnfWithRequestId(c, "[accounts.AccountCreateHandler] account cannot set credit card settings")
+		return nil, errs.ErrAccountCannotSetCreditCardSettings
+	}
+
+	for i := 0; i < len(accountCreateReq.SubAccounts); i++ {
+		subAccount := accountCreateReq.SubAccounts[i]
+
+		if !a.isCreditCardSettingsValid(subAccount.Category, subAccount.Type, subAccount.StatementClosingDay, subAccount.PaymentDueDay, subAccount.CreditLimit) {
+			log.WarnfWithRequestId(c, "[accounts.AccountCreateHandler] sub account cannot set credit card settings")
+			return nil, errs.ErrAccountCannotSetCreditCardSettings
+		}
+	}
+
+	uid := c.GetCurrentBookUid()
 	maxOrderId, err := a.accounts.GetMaxDisplayOrder(c, uid, accountCreateReq.Category)
 
 	if err != nil {
@@ -203,7 +227,7 @@ func (a *AccountsApi) AccountCreateHandler(c *core.Context) (interface{}, *errs.
 	mainAccount := a.createNewAccountModel(uid, &accountCreateReq, maxOrderId+1)
 	childrenAccounts := a.createSubAccountModels(uid, &accountCreateReq)
 
-	err = a.accounts.CreateAccounts(c, mainAccount, childrenAccounts, utcOffset)
+	err = a.accounts.CreateAccounts(c, c.GetCurrentUid(), mainAccount, childrenAccounts, utcOffset)
 
 	if err != nil {
 		log.ErrorfWithRequestId(c, "[accounts.AccountCreateHandler] failed to create account \"id:%d\" for user \"uid:%d\", because %s", mainAccount.AccountId, uid, err.Error())
@@ -235,7 +259,7 @@ func (a *AccountsApi) AccountModifyHandler(c *core.Context) (interface{}, *errs.
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
+	uid := c.GetCurrentBookUid()
 	accountAndSubAccounts, err := a.accounts.GetAccountAndSubAccountsByAccountId(c, uid, accountModifyReq.Id)
 
 	if err != nil {
@@ -253,6 +277,13 @@ func (a *AccountsApi) AccountModifyHandler(c *core.Context) (interface{}, *errs.
 		return nil, errs.ErrCannotAddOrDeleteSubAccountsWhenModify
 	}
 
+	oldAccount := accountMap[accountModifyReq.Id]
+
+	if !a.isCreditCardSettingsValid(accountModifyReq.Category, oldAccount.Type, accountModifyReq.StatementClosingDay, accountModifyReq.PaymentDueDay, accountModifyReq.CreditLimit) {
+		log.WarnfWithRequestId(c, "[accounts.AccountModifyHandler] account cannot set credit card settings")
+		return nil, errs.ErrAccountCannotSetCreditCardSettings
+	}
+
 	anythingUpdate := false
 	var toUpdateAccounts []*models.Account
 
@@ -270,6 +301,11 @@ func (a *AccountsApi) AccountModifyHandler(c *core.Context) (interface{}, *errs.
 			return nil, errs.ErrAccountNotFound
 		}
 
+		if !a.isCreditCardSettingsValid(subAccountReq.Category, accountMap[subAccountReq.Id].Type, subAccountReq.StatementClosingDay, subAccountReq.PaymentDueDay, subAccountReq.CreditLimit) {
+			log.WarnfWithRequestId(c, "[accounts.AccountModifyHandler] sub account cannot set credit card settings")
+			return nil, errs.ErrAccountCannotSetCreditCardSettings
+		}
+
 		toUpdateSubAccount := a.getToUpdateAccount(uid, subAccountReq, accountMap[subAccountReq.Id])
 
 		if toUpdateSubAccount != nil {
@@ -282,7 +318,7 @@ func (a *AccountsApi) AccountModifyHandler(c *core.Context) (interface{}, *errs.
 		return nil, errs.ErrNothingWillBeUpdated
 	}
 
-	err = a.accounts.ModifyAccounts(c, uid, toUpdateAccounts)
+	err = a.accounts.ModifyAccounts(c, uid, c.GetCurrentUid(), toUpdateAccounts)
 
 	if err != nil {
 		log.ErrorfWithRequestId(c, "[accounts.AccountModifyHandler] failed to update account \"id:%d\" for user \"uid:%d\", because %s", accountModifyReq.Id, uid, err.Error())
@@ -341,8 +377,8 @@ func (a *AccountsApi) AccountHideHandler(c *core.Context) (interface{}, *errs.Er
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
-	err = a.accounts.HideAccount(c, uid, []int64{accountHideReq.Id}, accountHideReq.Hidden)
+	uid := c.GetCurrentBookUid()
+	err = a.accounts.HideAccount(c, uid, c.GetCurrentUid(), []int64{accountHideReq.Id}, accountHideReq.Hidden)
 
 	if err != nil {
 		log.ErrorfWithRequestId(c, "[accounts.AccountHideHandler] failed to hide account \"id:%d\" for user \"uid:%d\", because %s", accountHideReq.Id, uid, err.Error())
@@ -363,7 +399,7 @@ func (a *AccountsApi) AccountMoveHandler(c *core.Context) (interface{}, *errs.Er
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
+	uid := c.GetCurrentBookUid()
 	accounts := make([]*models.Account, len(accountMoveReq.NewDisplayOrders))
 
 	for i := 0; i < len(accountMoveReq.NewDisplayOrders); i++ {
@@ -377,7 +413,7 @@ func (a *AccountsApi) AccountMoveHandler(c *core.Context) (interface{}, *errs.Er
 		accounts[i] = account
 	}
 
-	err = a.accounts.ModifyAccountDisplayOrders(c, uid, accounts)
+	err = a.accounts.ModifyAccountDisplayOrders(c, uid, c.GetCurrentUid(), accounts)
 
 	if err != nil {
 		log.ErrorfWithRequestId(c, "[accounts.AccountMoveHandler] failed to move accounts for user \"uid:%d\", because %s", uid, err.Error())
@@ -398,8 +434,8 @@ func (a *AccountsApi) AccountDeleteHandler(c *core.Context) (interface{}, *errs.
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
-	err = a.accounts.DeleteAccount(c, uid, accountDeleteReq.Id)
+	uid := c.GetCurrentBookUid()
+	err = a.accounts.DeleteAccount(c, uid, c.GetCurrentUid(), accountDeleteReq.Id)
 
 	if err != nil {
 		log.ErrorfWithRequestId(c, "[accounts.AccountDeleteHandler] failed to delete account \"id:%d\" for user \"uid:%d\", because %s", accountDeleteReq.Id, uid, err.Error())
@@ -410,18 +446,232 @@ func (a *AccountsApi) AccountDeleteHandler(c *core.Context) (interface{}, *errs.
 	return true, nil
 }
 
+// AccountNetWorthHandler returns the total assets, liabilities and net worth series of current user
+func (a *AccountsApi) AccountNetWorthHandler(c *core.Context) (interface{}, *errs.Error) {
+	var netWorthReq models.AccountNetWorthRequest
+	err := c.ShouldBindQuery(&netWorthReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[accounts.AccountNetWorthHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	utcOffset, err := c.GetClientTimezoneOffset()
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[accounts.AccountNetWorthHandler] cannot get client timezone offset, because %s", err.Error())
+		return nil, errs.ErrClientTimezoneOffsetInvalid
+	}
+
+	uid := c.GetCurrentBookUid()
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		if !errs.IsCustomError(err) {
+			log.ErrorfWithRequestId(c, "[accounts.AccountNetWorthHandler] failed to get user, because %s", err.Error())
+		}
+
+		return nil, errs.ErrUserNotFound
+	}
+
+	if netWorthReq.Interval == 0 {
+		netWorthReq.Interval = models.ACCOUNT_NET_WORTH_INTERVAL_MONTHLY
+	}
+
+	if netWorthReq.ConvertTo == "" {
+		netWorthReq.ConvertTo = user.DefaultCurrency
+	}
+
+	unixTimes := a.getNetWorthUnixTimes(netWorthReq, time.FixedZone("Client Timezone", int(utcOffset)*60), user.FirstDayOfWeek)
+
+	if len(unixTimes) > maxAccountNetWorthDataPoints {
+		log.WarnfWithRequestId(c, "[accounts.AccountNetWorthHandler] there are too many data points (%d) for user \"uid:%d\"", len(unixTimes), uid)
+		return nil, errs.ErrAccountNetWorthTooManyDataPoints
+	}
+
+	accounts, err := a.accounts.GetAllAccountsByUid(c, uid)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[accounts.AccountNetWorthHandler] failed to get all accounts for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	allBalances, err := a.accounts.GetAccountsBalancesByUnixTimes(c, uid, accounts, unixTimes, pageCountForLoadTransactionAmounts)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[accounts.AccountNetWorthHandler] failed to get accounts balances for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	allMarketValues, err := a.investments.GetAccountsMarketValuesByUnixTimes(c, uid, unixTimes)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[accounts.AccountNetWorthHandler] failed to get market values of investment holdings for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	netWorthResp := &models.AccountNetWorthResponse{
+		Currency: netWorthReq.ConvertTo,
+		Items:    make([]*models.AccountNetWorthResponseItem, len(unixTimes)),
+	}
+
+	accountRates := a.getAccountExchangeRates(c, accounts, netWorthResp)
+
+	for i := 0; i < len(unixTimes); i++ {
+		item := &models.AccountNetWorthResponseItem{
+			Time: unixTimes[i],
+		}
+
+		for j := 0; j < len(accounts); j++ {
+			account := accounts[j]
+			rate, exists := accountRates[account.AccountId]
+
+			if !exists {
+				continue
+			}
+
+			// the balance of investment account which has holdings is treated as uninvested cash
+			balance := int64(math.Round(float64(allBalances[i][account.AccountId]+allMarketValues[i][account.AccountId]) * rate))
+
+			if account.IsAsset() {
+				item.TotalAssets += balance
+			} else if account.IsLiability() {
+				item.TotalLiabilities -= balance
+			}
+		}
+
+		item.NetWorth = item.TotalAssets - item.TotalLiabilities
+		netWorthResp.Items[len(unixTimes)-1-i] = item
+	}
+
+	return netWorthResp, nil
+}
+
+// getNetWorthUnixTimes returns the end unix time of every period in descending order
+func (a *AccountsApi) getNetWorthUnixTimes(netWorthReq models.AccountNetWorthRequest, location *time.Location, firstDayOfWeek models.WeekDay) []int64 {
+	endTime := time.Now().Unix()
+
+	if netWorthReq.EndTime > 0 && netWorthReq.EndTime < endTime {
+		endTime = netWorthReq.EndTime
+	}
+
+	startTime := netWorthReq.StartTime
+	endDateTime := time.Unix(endTime, 0).In(location)
+
+	if startTime <= 0 || startTime > endTime {
+		if netWorthReq.Interval == models.ACCOUNT_NET_WORTH_INTERVAL_DAILY {
+			startTime = endDateTime.AddDate(0, 0, -30).Unix()
+		} else if netWorthReq.Interval == models.ACCOUNT_NET_WORTH_INTERVAL_WEEKLY {
+			startTime = endDateTime.AddDate(0, 0, -7*26).Unix()
+		} else {
+			startTime = endDateTime.AddDate(-1, 0, 0).Unix()
+		}
+	}
+
+	startDateTime := time.Unix(startTime, 0).In(location)
+	periodStartTime := time.Date(startDateTime.Year(), startDateTime.Month(), startDateTime.Day(), 0, 0, 0, 0, location)
+
+	if netWorthReq.Interval == models.ACCOUNT_NET_WORTH_INTERVAL_WEEKLY {
+		periodStartTime = periodStartTime.AddDate(0, 0, -((int(periodStartTime.Weekday()) - int(firstDayOfWeek) + 7) % 7))
+	} else if netWorthReq.Interval == models.ACCOUNT_NET_WORTH_INTERVAL_MONTHLY {
+		periodStartTime = time.Date(startDateTime.Year(), startDateTime.Month(), 1, 0, 0, 0, 0, location)
+	}
+
+	unixTimes := make([]int64, 0)
+
+	for len(unixTimes) <= maxAccountNetWorthDataPoints {
+		var nextPeriodStartTime time.Time
+
+		if netWorthReq.Interval == models.ACCOUNT_NET_WORTH_INTERVAL_DAILY {
+			nextPeriodStartTime = periodStartTime.AddDate(0, 0, 1)
+		} else if netWorthReq.Interval == models.ACCOUNT_NET_WORTH_INTERVAL_WEEKLY {
+			nextPeriodStartTime = periodStartTime.AddDate(0, 0, 7)
+		} else {
+			nextPeriodStartTime = periodStartTime.AddDate(0, 1, 0)
+		}
+
+		periodEndTime := nextPeriodStartTime.Unix() - 1
+
+		if periodEndTime >= endTime {
+			unixTimes = append(unixTimes, endTime)
+			break
+		}
+
+		unixTimes = append(unixTimes, periodEndTime)
+		periodStartTime = nextPeriodStartTime
+	}
+
+	for i, j := 0, len(unixTimes)-1; i < j; i, j = i+1, j-1 {
+		unixTimes[i], unixTimes[j] = unixTimes[j], unixTimes[i]
+	}
+
+	return unixTimes
+}
+
+// getAccountExchangeRates returns the exchange rate of every account whose balance can be converted into the currency of net worth
+func (a *AccountsApi) getAccountExchangeRates(c *core.Context, accounts []*models.Account, netWorthResp *models.AccountNetWorthResponse) map[int64]float64 {
+	accountRates := make(map[int64]float64, len(accounts))
+	unconvertibleCurrencies := make(map[string]bool)
+
+	var exchangeRates *models.ExchangeRateHistoryResponse
+	exchangeRatesLoaded := false
+
+	for i := 0; i < len(accounts); i++ {
+		account := accounts[i]
+
+		if account.Type == models.ACCOUNT_TYPE_MULTI_SUB_ACCOUNTS {
+			continue
+		}
+
+		if account.Currency == netWorthResp.Currency {
+			accountRates[account.AccountId] = 1
+			continue
+		}
+
+		if !exchangeRatesLoaded {
+			var err error
+			exchangeRates, err = a.exchangeRates.GetExchangeRatesByUnixTime(c, time.Now().Unix())
+			exchangeRatesLoaded = true
+
+			if err != nil {
+				log.WarnfWithRequestId(c, "[accounts.getAccountExchangeRates] failed to get latest exchange rates, because %s", err.Error())
+				exchangeRates = nil
+			} else {
+				netWorthResp.ExchangeRateDate = exchangeRates.Date
+			}
+		}
+
+		if exchangeRates != nil {
+			if rate, exists := exchangeRates.GetExchangeRate(account.Currency, netWorthResp.Currency); exists {
+				accountRates[account.AccountId] = rate
+				continue
+			}
+		}
+
+		if !unconvertibleCurrencies[account.Currency] {
+			unconvertibleCurrencies[account.Currency] = true
+			netWorthResp.UnconvertibleCurrencies = append(netWorthResp.UnconvertibleCurrencies, account.Currency)
+		}
+	}
+
+	return accountRates
+}
+
 func (a *AccountsApi) createNewAccountModel(uid int64, accountCreateReq *models.AccountCreateRequest, order int32) *models.Account {
 	return &models.Account{
-		Uid:          uid,
-		Name:         accountCreateReq.Name,
-		DisplayOrder: order,
-		Category:     accountCreateReq.Category,
-		Type:         accountCreateReq.Type,
-		Icon:         accountCreateReq.Icon,
-		Color:        accountCreateReq.Color,
-		Currency:     accountCreateReq.Currency,
-		Balance:      accountCreateReq.Balance,
-		Comment:      accountCreateReq.Comment,
+		Uid:                 uid,
+		Name:                accountCreateReq.Name,
+		DisplayOrder:        order,
+		Category:            accountCreateReq.Category,
+		Type:                accountCreateReq.Type,
+		Icon:                accountCreateReq.Icon,
+		Color:               accountCreateReq.Color,
+		Currency:            accountCreateReq.Currency,
+		Balance:             accountCreateReq.Balance,
+		Comment:             accountCreateReq.Comment,
+		StatementClosingDay: accountCreateReq.StatementClosingDay,
+		PaymentDueDay:       accountCreateReq.PaymentDueDay,
+		CreditLimit:         accountCreateReq.CreditLimit,
 	}
 }
 
@@ -441,14 +691,17 @@ func (a *AccountsApi) createSubAccountModels(uid int64, accountCreateReq *models
 
 func (a *AccountsApi) getToUpdateAccount(uid int64, accountModifyReq *models.AccountModifyRequest, oldAccount *models.Account) *models.Account {
 	newAccount := &models.Account{
-		AccountId: oldAccount.AccountId,
-		Uid:       uid,
-		Name:      accountModifyReq.Name,
-		Category:  accountModifyReq.Category,
-		Icon:      accountModifyReq.Icon,
-		Color:     accountModifyReq.Color,
-		Comment:   accountModifyReq.Comment,
-		Hidden:    accountModifyReq.Hidden,
+		AccountId:           oldAccount.AccountId,
+		Uid:                 uid,
+		Name:                accountModifyReq.Name,
+		Category:            accountModifyReq.Category,
+		Icon:                accountModifyReq.Icon,
+		Color:               accountModifyReq.Color,
+		Comment:             accountModifyReq.Comment,
+		Hidden:              accountModifyReq.Hidden,
+		StatementClosingDay: accountModifyReq.StatementClosingDay,
+		PaymentDueDay:       accountModifyReq.PaymentDueDay,
+		CreditLimit:         accountModifyReq.CreditLimit,
 	}
 
 	if newAccount.Name != oldAccount.Name ||
@@ -456,9 +709,20 @@ func (a *AccountsApi) getToUpdateAccount(uid int64, accountModifyReq *models.Acc
 		newAccount.Icon != oldAccount.Icon ||
 		newAccount.Color != oldAccount.Color ||
 		newAccount.Comment != oldAccount.Comment ||
-		newAccount.Hidden != oldAccount.Hidden {
+		newAccount.Hidden != oldAccount.Hidden ||
+		newAccount.StatementClosingDay != oldAccount.StatementClosingDay ||
+		newAccount.PaymentDueDay != oldAccount.PaymentDueDay ||
+		newAccount.CreditLimit != oldAccount.CreditLimit {
 		return newAccount
 	}
 
 	return nil
 }
+
+func (a *AccountsApi) isCreditCardSettingsValid(category models.AccountCategory, accountType models.AccountType, statementClosingDay int32, paymentDueDay int32, creditLimit int64) bool {
+	if statementClosingDay == 0 && paymentDueDay == 0 && creditLimit == 0 {
+		return true
+	}
+
+	return category == models.ACCOUNT_CATEGORY_CREDIT_CARD && accountType == models.ACCOUNT_TYPE_SINGLE_ACCOUNT
+}
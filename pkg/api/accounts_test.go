@@ -0,0 +1,56 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/f97/gofire/pkg/models"
+)
+
+func TestGetNetWorthUnixTimes_MonthlyInterval(t *testing.T) {
+	netWorthReq := models.AccountNetWorthRequest{
+		Interval:  models.ACCOUNT_NET_WORTH_INTERVAL_MONTHLY,
+		StartTime: time.Date(2023, time.November, 15, 0, 0, 0, 0, time.UTC).Unix(),
+		EndTime:   time.Date(2024, time.February, 10, 0, 0, 0, 0, time.UTC).Unix(),
+	}
+
+	unixTimes := Accounts.getNetWorthUnixTimes(netWorthReq, time.UTC, models.WEEKDAY_SUNDAY)
+	assert.Equal(t, []int64{
+		time.Date(2024, time.February, 10, 0, 0, 0, 0, time.UTC).Unix(),
+		time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC).Unix() - 1,
+		time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC).Unix() - 1,
+		time.Date(2023, time.December, 1, 0, 0, 0, 0, time.UTC).Unix() - 1,
+	}, unixTimes)
+}
+
+func TestGetNetWorthUnixTimes_WeeklyIntervalFromFirstDayOfWeek(t *testing.T) {
+	netWorthReq := models.AccountNetWorthRequest{
+		Interval:  models.ACCOUNT_NET_WORTH_INTERVAL_WEEKLY,
+		StartTime: time.Date(2024, time.January, 3, 12, 0, 0, 0, time.UTC).Unix(),
+		EndTime:   time.Date(2024, time.January, 16, 12, 0, 0, 0, time.UTC).Unix(),
+	}
+
+	unixTimes := Accounts.getNetWorthUnixTimes(netWorthReq, time.UTC, models.WEEKDAY_MONDAY)
+	assert.Equal(t, []int64{
+		time.Date(2024, time.January, 16, 12, 0, 0, 0, time.UTC).Unix(),
+		time.Date(2024, time.January, 15, 0, 0, 0, 0, time.UTC).Unix() - 1,
+		time.Date(2024, time.January, 8, 0, 0, 0, 0, time.UTC).Unix() - 1,
+	}, unixTimes)
+}
+
+func TestGetNetWorthUnixTimes_DailyIntervalInClientTimezone(t *testing.T) {
+	timezone := time.FixedZone("Client Timezone", 8*60*60)
+	netWorthReq := models.AccountNetWorthRequest{
+		Interval:  models.ACCOUNT_NET_WORTH_INTERVAL_DAILY,
+		StartTime: time.Date(2024, time.January, 1, 10, 0, 0, 0, timezone).Unix(),
+		EndTime:   time.Date(2024, time.January, 2, 10, 0, 0, 0, timezone).Unix(),
+	}
+
+	unixTimes := Accounts.getNetWorthUnixTimes(netWorthReq, timezone, models.WEEKDAY_SUNDAY)
+	assert.Equal(t, []int64{
+		time.Date(2024, time.January, 2, 10, 0, 0, 0, timezone).Unix(),
+		time.Date(2024, time.January, 2, 0, 0, 0, 0, timezone).Unix() - 1,
+	}, unixTimes)
+}
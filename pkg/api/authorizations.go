@@ -16,6 +16,7 @@ type AuthorizationsApi struct {
 	users                   *services.UserService
 	tokens                  *services.TokenService
 	twoFactorAuthorizations *services.TwoFactorAuthorizationService
+	webAuthnCredentials     *services.UserWebAuthnCredentialService
 }
 
 // Initialize a authorization api singleton instance
@@ -24,6 +25,7 @@ var (
 		users:                   services.Users,
 		tokens:                  services.Tokens,
 		twoFactorAuthorizations: services.TwoFactorAuthorizations,
+		webAuthnCredentials:     services.UserWebAuthnCredentials,
 	}
 )
 
@@ -62,17 +64,15 @@ func (a *AuthorizationsApi) AuthorizeHandler(c *core.Context) (interface{}, *err
 		log.WarnfWithRequestId(c, "[authorizations.AuthorizeHandler] failed to update last login time for user \"uid:%d\", because %s", user.Uid, err.Error())
 	}
 
-	twoFactorEnable := a.tokens.CurrentConfig().EnableTwoFactor
+	twoFactorMethods, err := a.getTwoFactorMethods(c, user.Uid)
 
-	if twoFactorEnable {
-		twoFactorEnable, err = a.twoFactorAuthorizations.ExistsTwoFactorSetting(c, user.Uid)
-
-		if err != nil {
-			log.ErrorfWithRequestId(c, "[authorizations.AuthorizeHandler] failed to check two factor setting for user \"uid:%d\", because %s", user.Uid, err.Error())
-			return nil, errs.Or(err, errs.ErrSystemError)
-		}
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[authorizations.AuthorizeHandler] failed to check two factor setting for user \"uid:%d\", because %s", user.Uid, err.Error())
+		return nil, errs.Or(err, errs.ErrSystemError)
 	}
 
+	twoFactorEnable := len(twoFactorMethods) > 0
+
 	var token string
 	var claims *core.UserTokenClaims
 
@@ -96,6 +96,7 @@ func (a *AuthorizationsApi) AuthorizeHandler(c *core.Context) (interface{}, *err
 	log.InfofWithRequestId(c, "[authorizations.AuthorizeHandler] user \"uid:%d\" has logined, token type is %d, token will be expired at %d", user.Uid, claims.Type, claims.ExpiresAt)
 
 	authResp := a.getAuthResponse(token, twoFactorEnable, user)
+	authResp.TwoFactorMethods = twoFactorMethods
 	return authResp, nil
 }
 
@@ -231,6 +232,37 @@ func (a *AuthorizationsApi) TwoFactorAuthorizeByRecoveryCodeHandler(c *core.Cont
 	return authResp, nil
 }
 
+// getTwoFactorMethods returns the methods which user can use to authorize two factor, two factor authorization is enabled
+// when user has passcode setting or at least one webauthn credential
+func (a *AuthorizationsApi) getTwoFactorMethods(c *core.Context, uid int64) ([]string, error) {
+	config := a.tokens.CurrentConfig()
+
+	if !config.EnableTwoFactor {
+		return nil, nil
+	}
+
+	var twoFactorMethods []string
+	passcodeEnable, err := a.twoFactorAuthorizations.ExistsTwoFactorSetting(c, uid)
+
+	if err != nil {
+		return nil, err
+	} else if passcodeEnable {
+		twoFactorMethods = append(twoFactorMethods, models.TWO_FACTOR_METHOD_PASSCODE)
+	}
+
+	if config.EnableWebAuthn {
+		webAuthnEnable, err := a.webAuthnCredentials.ExistsCredentials(c, uid)
+
+		if err != nil {
+			return nil, err
+		} else if webAuthnEnable {
+			twoFactorMethods = append(twoFactorMethods, models.TWO_FACTOR_METHOD_WEBAUTHN)
+		}
+	}
+
+	return twoFactorMethods, nil
+}
+
 func (a *AuthorizationsApi) getAuthResponse(token string, need2FA bool, user *models.User) *models.AuthResponse {
 	return &models.AuthResponse{
 		Token:           token,
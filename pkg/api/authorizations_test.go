@@ -0,0 +1,88 @@
+package api
+
+import (
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/f97/gofire/pkg/core"
+	"github.com/f97/gofire/pkg/datastore"
+	"github.com/f97/gofire/pkg/models"
+	"github.com/f97/gofire/pkg/services"
+	"github.com/f97/gofire/pkg/settings"
+	"github.com/f97/gofire/pkg/uuid"
+	"github.com/f97/gofire/pkg/validators"
+)
+
+func TestAuthorizeHandler_WebAuthnOnlyUser(t *testing.T) {
+	config := &settings.Config{
+		DatabaseConfig: &settings.DatabaseConfig{
+			DatabaseType:          settings.Sqlite3DbType,
+			DatabasePath:          filepath.Join(t.TempDir(), "gofire.db"),
+			MaxIdleConnection:     1,
+			MaxOpenConnection:     1,
+			ConnectionMaxLifeTime: 3600,
+		},
+		UuidGeneratorType:                 settings.InternalUuidGeneratorType,
+		EnableTwoFactor:                   true,
+		EnableWebAuthn:                    true,
+		TemporaryTokenExpiredTimeDuration: 5 * time.Minute,
+	}
+
+	settings.SetCurrentConfig(config)
+
+	err := datastore.InitializeDataStore(config)
+	assert.Equal(t, nil, err)
+
+	err = uuid.InitializeUuidGenerator(config)
+	assert.Equal(t, nil, err)
+
+	err = datastore.Container.UserStore.SyncStructs(new(models.User), new(models.TwoFactor), new(models.UserWebAuthnCredential), new(models.TokenRecord))
+	assert.Equal(t, nil, err)
+
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		_ = v.RegisterValidation("notBlank", validators.NotBlank)
+		_ = v.RegisterValidation("validUsername", validators.ValidUsername)
+		_ = v.RegisterValidation("validEmail", validators.ValidEmail)
+	}
+
+	ginContext, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ginContext.Request = httptest.NewRequest("POST", "/api/authorize.json", strings.NewReader(`{"loginName":"test","password":"password"}`))
+	ginContext.Request.Header.Set("Content-Type", "application/json")
+	c := &core.Context{Context: ginContext}
+
+	user := &models.User{
+		Username: "test",
+		Email:    "test@example.com",
+		Nickname: "test",
+		Password: "password",
+	}
+
+	err = services.Users.CreateUser(c, user)
+	assert.Equal(t, nil, err)
+
+	err = services.UserWebAuthnCredentials.CreateCredential(c, &models.UserWebAuthnCredential{
+		Uid:       user.Uid,
+		Name:      "Security Key",
+		RawId:     "raw-id",
+		PublicKey: "public-key",
+	})
+	assert.Equal(t, nil, err)
+
+	result, handlerErr := Authorizations.AuthorizeHandler(c)
+	assert.Nil(t, handlerErr)
+
+	authResp := result.(*models.AuthResponse)
+	assert.Equal(t, true, authResp.Need2FA)
+
+	expectedValue := []string{models.TWO_FACTOR_METHOD_WEBAUTHN}
+	actualValue := authResp.TwoFactorMethods
+	assert.Equal(t, expectedValue, actualValue)
+}
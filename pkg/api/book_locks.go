@@ -0,0 +1,117 @@
+package api
+
+import (
+	"sort"
+
+	"github.com/f97/gofire/pkg/core"
+	"github.com/f97/gofire/pkg/errs"
+	"github.com/f97/gofire/pkg/log"
+	"github.com/f97/gofire/pkg/models"
+	"github.com/f97/gofire/pkg/services"
+)
+
+// BookLocksApi represents book lock api
+type BookLocksApi struct {
+	bookLocks *services.BookLockService
+	revisions *services.DataRevisionService
+}
+
+// Initialize a book lock api singleton instance
+var (
+	BookLocks = &BookLocksApi{
+		bookLocks: services.BookLocks,
+		revisions: services.DataRevisions,
+	}
+)
+
+// BookLockListHandler returns book lock list of current user
+func (a *BookLocksApi) BookLockListHandler(c *core.Context) (interface{}, *errs.Error) {
+	uid := c.GetCurrentBookUid()
+	bookLocks, err := a.bookLocks.GetAllBookLocksByUid(c, uid)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[book_locks.BookLockListHandler] failed to get book locks for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	bookLockResps := make([]*models.BookLockInfoResponse, len(bookLocks))
+
+	for i := 0; i < len(bookLocks); i++ {
+		bookLockResps[i] = bookLocks[i].ToBookLockInfoResponse()
+	}
+
+	return bookLockResps, nil
+}
+
+// BookLockCloseHandler closes the books of current user (or the specified account) through the specified time
+func (a *BookLocksApi) BookLockCloseHandler(c *core.Context) (interface{}, *errs.Error) {
+	var bookLockCloseReq models.BookLockCloseRequest
+	err := c.ShouldBindJSON(&bookLockCloseReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[book_locks.BookLockCloseHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentBookUid()
+	err = a.bookLocks.CloseBooks(c, uid, c.GetCurrentUid(), bookLockCloseReq.AccountId, bookLockCloseReq.ClosedTime)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[book_locks.BookLockCloseHandler] failed to close books of account \"id:%d\" for user \"uid:%d\", because %s", bookLockCloseReq.AccountId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.InfofWithRequestId(c, "[book_locks.BookLockCloseHandler] user \"uid:%d\" has closed books of account \"id:%d\" through \"%d\"", uid, bookLockCloseReq.AccountId, bookLockCloseReq.ClosedTime)
+	return true, nil
+}
+
+// BookLockReopenHandler reopens the closed books of current user (or the specified account) after the specified time
+func (a *BookLocksApi) BookLockReopenHandler(c *core.Context) (interface{}, *errs.Error) {
+	var bookLockReopenReq models.BookLockReopenRequest
+	err := c.ShouldBindJSON(&bookLockReopenReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[book_locks.BookLockReopenHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentBookUid()
+	err = a.bookLocks.ReopenBooks(c, uid, c.GetCurrentUid(), bookLockReopenReq.AccountId, bookLockReopenReq.ClosedTime)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[book_locks.BookLockReopenHandler] failed to reopen books of account \"id:%d\" for user \"uid:%d\", because %s", bookLockReopenReq.AccountId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.InfofWithRequestId(c, "[book_locks.BookLockReopenHandler] user \"uid:%d\" has reopened books of account \"id:%d\" after \"%d\"", uid, bookLockReopenReq.AccountId, bookLockReopenReq.ClosedTime)
+	return true, nil
+}
+
+// BookLockHistoryHandler returns the closing and reopening history of the books of current user (or the specified account)
+func (a *BookLocksApi) BookLockHistoryHandler(c *core.Context) (interface{}, *errs.Error) {
+	var bookLockHistoryReq models.BookLockHistoryRequest
+	err := c.ShouldBindQuery(&bookLockHistoryReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[book_locks.BookLockHistoryHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentBookUid()
+	revisions, err := a.revisions.GetRevisionsByObjectId(c, uid, models.DATA_REVISION_OBJECT_TYPE_BOOK_LOCK, bookLockHistoryReq.AccountId)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[book_locks.BookLockHistoryHandler] failed to get book lock revisions of account \"id:%d\" for user \"uid:%d\", because %s", bookLockHistoryReq.AccountId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	revisionResps := make(models.DataRevisionInfoResponseSlice, len(revisions))
+
+	for i := 0; i < len(revisions); i++ {
+		revisionResps[i] = revisions[i].ToDataRevisionInfoResponse()
+	}
+
+	sort.Sort(revisionResps)
+
+	return revisionResps, nil
+}
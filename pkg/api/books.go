@@ -0,0 +1,244 @@
+package api
+
+import (
+	"github.com/f97/gofire/pkg/core"
+	"github.com/f97/gofire/pkg/errs"
+	"github.com/f97/gofire/pkg/log"
+	"github.com/f97/gofire/pkg/models"
+	"github.com/f97/gofire/pkg/services"
+)
+
+// BooksApi represents shared book api
+type BooksApi struct {
+	members *services.BookMemberService
+	users   *services.UserService
+}
+
+// Initialize a shared book api singleton instance
+var (
+	Books = &BooksApi{
+		members: services.BookMembers,
+		users:   services.Users,
+	}
+)
+
+// BookListHandler returns the personal book and all shared books which current user has joined or been invited to
+func (a *BooksApi) BookListHandler(c *core.Context) (interface{}, *errs.Error) {
+	uid := c.GetCurrentUid()
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[books.BookListHandler] failed to get user \"uid:%d\" info, because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	memberships, err := a.members.GetAllMembershipsByUid(c, uid)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[books.BookListHandler] failed to get book memberships for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	personalBook := &models.BookMember{
+		BookUid:  uid,
+		Uid:      uid,
+		Role:     models.BOOK_MEMBER_ROLE_OWNER,
+		Accepted: true,
+	}
+
+	bookResps := make([]*models.BookInfoResponse, 0, len(memberships)+1)
+	bookResps = append(bookResps, personalBook.ToBookInfoResponse(user))
+
+	for i := 0; i < len(memberships); i++ {
+		membership := memberships[i]
+		owner, err := a.users.GetUserById(c, membership.BookUid)
+
+		if err != nil {
+			log.WarnfWithRequestId(c, "[books.BookListHandler] failed to get owner of book \"id:%d\", because %s", membership.BookUid, err.Error())
+			continue
+		}
+
+		bookResps = append(bookResps, membership.ToBookInfoResponse(owner))
+	}
+
+	return bookResps, nil
+}
+
+// MemberListHandler returns all members of the shared book which current user is accessing
+func (a *BooksApi) MemberListHandler(c *core.Context) (interface{}, *errs.Error) {
+	bookUid := c.GetCurrentBookUid()
+	owner, err := a.users.GetUserById(c, bookUid)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[books.MemberListHandler] failed to get owner of book \"id:%d\", because %s", bookUid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	members, err := a.members.GetAllMembersByBookUid(c, bookUid)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[books.MemberListHandler] failed to get members of book \"id:%d\", because %s", bookUid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	ownerMember := &models.BookMember{
+		BookUid:  bookUid,
+		Uid:      bookUid,
+		Role:     models.BOOK_MEMBER_ROLE_OWNER,
+		Accepted: true,
+	}
+
+	memberResps := make([]*models.BookMemberInfoResponse, 0, len(members)+1)
+	memberResps = append(memberResps, ownerMember.ToBookMemberInfoResponse(owner))
+
+	for i := 0; i < len(members); i++ {
+		member := members[i]
+		user, err := a.users.GetUserById(c, member.Uid)
+
+		if err != nil {
+			log.WarnfWithRequestId(c, "[books.MemberListHandler] failed to get member \"uid:%d\" of book \"id:%d\", because %s", member.Uid, bookUid, err.Error())
+			continue
+		}
+
+		memberResps = append(memberResps, member.ToBookMemberInfoResponse(user))
+	}
+
+	return memberResps, nil
+}
+
+// MemberInviteHandler invites a user to the shared book which current user is accessing
+func (a *BooksApi) MemberInviteHandler(c *core.Context) (interface{}, *errs.Error) {
+	var memberInviteReq models.BookMemberInviteRequest
+	err := c.ShouldBindJSON(&memberInviteReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[books.MemberInviteHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	bookUid := c.GetCurrentBookUid()
+	err = a.members.CheckBookMemberRole(c, bookUid, uid, models.BOOK_MEMBER_ROLE_OWNER)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[books.MemberInviteHandler] user \"uid:%d\" cannot invite members to book \"id:%d\", because %s", uid, bookUid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	user, err := a.users.GetUserByUsernameOrEmail(c, memberInviteReq.LoginName)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[books.MemberInviteHandler] failed to get user \"%s\", because %s", memberInviteReq.LoginName, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	member := &models.BookMember{
+		BookUid:    bookUid,
+		Uid:        user.Uid,
+		Role:       memberInviteReq.Role,
+		InviterUid: uid,
+	}
+
+	err = a.members.InviteMember(c, member)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[books.MemberInviteHandler] failed to invite user \"uid:%d\" to book \"id:%d\", because %s", user.Uid, bookUid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.InfofWithRequestId(c, "[books.MemberInviteHandler] user \"uid:%d\" has invited user \"uid:%d\" to book \"id:%d\" successfully", uid, user.Uid, bookUid)
+
+	return member.ToBookMemberInfoResponse(user), nil
+}
+
+// MemberModifyHandler updates the role of a member of the shared book which current user is accessing
+func (a *BooksApi) MemberModifyHandler(c *core.Context) (interface{}, *errs.Error) {
+	var memberModifyReq models.BookMemberModifyRequest
+	err := c.ShouldBindJSON(&memberModifyReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[books.MemberModifyHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	bookUid := c.GetCurrentBookUid()
+
+	err = a.members.ModifyMemberRole(c, bookUid, uid, memberModifyReq.Uid, memberModifyReq.Role)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[books.MemberModifyHandler] failed to update role of member \"uid:%d\" in book \"id:%d\", because %s", memberModifyReq.Uid, bookUid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.InfofWithRequestId(c, "[books.MemberModifyHandler] user \"uid:%d\" has updated role of member \"uid:%d\" in book \"id:%d\" successfully", uid, memberModifyReq.Uid, bookUid)
+	return true, nil
+}
+
+// MemberRemoveHandler removes a member or invitation from the shared book which current user is accessing
+func (a *BooksApi) MemberRemoveHandler(c *core.Context) (interface{}, *errs.Error) {
+	var memberRemoveReq models.BookMemberRemoveRequest
+	err := c.ShouldBindJSON(&memberRemoveReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[books.MemberRemoveHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	bookUid := c.GetCurrentBookUid()
+
+	err = a.members.RemoveMember(c, bookUid, uid, memberRemoveReq.Uid)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[books.MemberRemoveHandler] failed to remove member \"uid:%d\" from book \"id:%d\", because %s", memberRemoveReq.Uid, bookUid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.InfofWithRequestId(c, "[books.MemberRemoveHandler] user \"uid:%d\" has removed member \"uid:%d\" from book \"id:%d\"", uid, memberRemoveReq.Uid, bookUid)
+	return true, nil
+}
+
+// BookAcceptHandler accepts the invitation of a shared book for current user
+func (a *BooksApi) BookAcceptHandler(c *core.Context) (interface{}, *errs.Error) {
+	var invitationReq models.BookInvitationRequest
+	err := c.ShouldBindJSON(&invitationReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[books.BookAcceptHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	err = a.members.AcceptInvitation(c, invitationReq.BookId, uid)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[books.BookAcceptHandler] failed to accept invitation of book \"id:%d\" for user \"uid:%d\", because %s", invitationReq.BookId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.InfofWithRequestId(c, "[books.BookAcceptHandler] user \"uid:%d\" has joined book \"id:%d\" successfully", uid, invitationReq.BookId)
+	return true, nil
+}
+
+// BookLeaveHandler declines the invitation or leaves a shared book for current user
+func (a *BooksApi) BookLeaveHandler(c *core.Context) (interface{}, *errs.Error) {
+	var invitationReq models.BookInvitationRequest
+	err := c.ShouldBindJSON(&invitationReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[books.BookLeaveHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	err = a.members.RemoveMember(c, invitationReq.BookId, uid, uid)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[books.BookLeaveHandler] failed to leave book \"id:%d\" for user \"uid:%d\", because %s", invitationReq.BookId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.InfofWithRequestId(c, "[books.BookLeaveHandler] user \"uid:%d\" has left book \"id:%d\"", uid, invitationReq.BookId)
+	return true, nil
+}
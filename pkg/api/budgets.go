@@ -0,0 +1,365 @@
+package api
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/f97/gofire/pkg/core"
+	"github.com/f97/gofire/pkg/errs"
+	"github.com/f97/gofire/pkg/log"
+	"github.com/f97/gofire/pkg/models"
+	"github.com/f97/gofire/pkg/services"
+)
+
+// BudgetsApi represents budget api
+type BudgetsApi struct {
+	budgets       *services.BudgetService
+	transactions  *services.TransactionService
+	categories    *services.TransactionCategoryService
+	accounts      *services.AccountService
+	exchangeRates *services.ExchangeRateService
+}
+
+// Initialize a budget api singleton instance
+var (
+	Budgets = &BudgetsApi{
+		budgets:       services.Budgets,
+		transactions:  services.Transactions,
+		categories:    services.TransactionCategories,
+		accounts:      services.Accounts,
+		exchangeRates: services.ExchangeRates,
+	}
+)
+
+// budgetTotalAmountsKey represents the key of cached total amounts of one period and tag
+type budgetTotalAmountsKey struct {
+	startTime int64
+	endTime   int64
+	tagId     int64
+}
+
+// BudgetListHandler returns budget list of current user
+func (a *BudgetsApi) BudgetListHandler(c *core.Context) (interface{}, *errs.Error) {
+	uid := c.GetCurrentBookUid()
+	budgets, err := a.budgets.GetAllBudgetsByUid(c, uid)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[budgets.BudgetListHandler] failed to get budgets for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	budgetResps := make(models.BudgetInfoResponseSlice, len(budgets))
+
+	for i := 0; i < len(budgets); i++ {
+		budgetResps[i] = budgets[i].ToBudgetInfoResponse()
+	}
+
+	sort.Sort(budgetResps)
+
+	return budgetResps, nil
+}
+
+// BudgetGetHandler returns one specific budget of current user
+func (a *BudgetsApi) BudgetGetHandler(c *core.Context) (interface{}, *errs.Error) {
+	var budgetGetReq models.BudgetGetRequest
+	err := c.ShouldBindQuery(&budgetGetReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[budgets.BudgetGetHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentBookUid()
+	budget, err := a.budgets.GetBudgetByBudgetId(c, uid, budgetGetReq.Id)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[budgets.BudgetGetHandler] failed to get budget \"id:%d\" for user \"uid:%d\", because %s", budgetGetReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	budgetResp := budget.ToBudgetInfoResponse()
+
+	return budgetResp, nil
+}
+
+// BudgetCreateHandler saves a new budget by request parameters for current user
+func (a *BudgetsApi) BudgetCreateHandler(c *core.Context) (interface{}, *errs.Error) {
+	var budgetCreateReq models.BudgetCreateRequest
+	err := c.ShouldBindJSON(&budgetCreateReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[budgets.BudgetCreateHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentBookUid()
+
+	maxOrderId, err := a.budgets.GetMaxDisplayOrder(c, uid)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[budgets.BudgetCreateHandler] failed to get max display order for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	budget := &models.Budget{
+		Uid:          uid,
+		CategoryId:   budgetCreateReq.CategoryId,
+		AccountId:    budgetCreateReq.AccountId,
+		TagId:        budgetCreateReq.TagId,
+		PeriodType:   budgetCreateReq.PeriodType,
+		Currency:     budgetCreateReq.Currency,
+		Amount:       budgetCreateReq.Amount,
+		Comment:      budgetCreateReq.Comment,
+		DisplayOrder: maxOrderId + 1,
+	}
+
+	err = a.budgets.CreateBudget(c, c.GetCurrentUid(), budget)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[budgets.BudgetCreateHandler] failed to create budget \"id:%d\" for user \"uid:%d\", because %s", budget.BudgetId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.InfofWithRequestId(c, "[budgets.BudgetCreateHandler] user \"uid:%d\" has created a new budget \"id:%d\" successfully", uid, budget.BudgetId)
+
+	budgetResp := budget.ToBudgetInfoResponse()
+
+	return budgetResp, nil
+}
+
+// BudgetModifyHandler saves an existed budget by request parameters for current user
+func (a *BudgetsApi) BudgetModifyHandler(c *core.Context) (interface{}, *errs.Error) {
+	var budgetModifyReq models.BudgetModifyRequest
+	err := c.ShouldBindJSON(&budgetModifyReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[budgets.BudgetModifyHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentBookUid()
+	budget, err := a.budgets.GetBudgetByBudgetId(c, uid, budgetModifyReq.Id)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[budgets.BudgetModifyHandler] failed to get budget \"id:%d\" for user \"uid:%d\", because %s", budgetModifyReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	newBudget := &models.Budget{
+		BudgetId:     budget.BudgetId,
+		Uid:          uid,
+		CategoryId:   budgetModifyReq.CategoryId,
+		AccountId:    budgetModifyReq.AccountId,
+		TagId:        budgetModifyReq.TagId,
+		PeriodType:   budgetModifyReq.PeriodType,
+		Currency:     budgetModifyReq.Currency,
+		Amount:       budgetModifyReq.Amount,
+		Comment:      budgetModifyReq.Comment,
+		DisplayOrder: budget.DisplayOrder,
+	}
+
+	if newBudget.CategoryId == budget.CategoryId &&
+		newBudget.AccountId == budget.AccountId &&
+		newBudget.TagId == budget.TagId &&
+		newBudget.PeriodType == budget.PeriodType &&
+		newBudget.Currency == budget.Currency &&
+		newBudget.Amount == budget.Amount &&
+		newBudget.Comment == budget.Comment {
+		return nil, errs.ErrNothingWillBeUpdated
+	}
+
+	err = a.budgets.ModifyBudget(c, c.GetCurrentUid(), newBudget)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[budgets.BudgetModifyHandler] failed to update budget \"id:%d\" for user \"uid:%d\", because %s", budgetModifyReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.InfofWithRequestId(c, "[budgets.BudgetModifyHandler] user \"uid:%d\" has updated budget \"id:%d\" successfully", uid, budgetModifyReq.Id)
+
+	budgetResp := newBudget.ToBudgetInfoResponse()
+
+	return budgetResp, nil
+}
+
+// BudgetDeleteHandler deletes an existed budget by request parameters for current user
+func (a *BudgetsApi) BudgetDeleteHandler(c *core.Context) (interface{}, *errs.Error) {
+	var budgetDeleteReq models.BudgetDeleteRequest
+	err := c.ShouldBindJSON(&budgetDeleteReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[budgets.BudgetDeleteHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentBookUid()
+	err = a.budgets.DeleteBudget(c, uid, c.GetCurrentUid(), budgetDeleteReq.Id)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[budgets.BudgetDeleteHandler] failed to delete budget \"id:%d\" for user \"uid:%d\", because %s", budgetDeleteReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.InfofWithRequestId(c, "[budgets.BudgetDeleteHandler] user \"uid:%d\" has deleted budget \"id:%d\"", uid, budgetDeleteReq.Id)
+	return true, nil
+}
+
+// BudgetProgressHandler returns spent amount of each budget in the period which contains specified month for current user
+func (a *BudgetsApi) BudgetProgressHandler(c *core.Context) (interface{}, *errs.Error) {
+	var budgetProgressReq models.BudgetProgressRequest
+	err := c.ShouldBindQuery(&budgetProgressReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[budgets.BudgetProgressHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	utcOffset, err := c.GetClientTimezoneOffset()
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[budgets.BudgetProgressHandler] cannot get client timezone offset, because %s", err.Error())
+		return nil, errs.ErrClientTimezoneOffsetInvalid
+	}
+
+	timezone := time.FixedZone("Client Timezone", int(utcOffset)*60)
+	uid := c.GetCurrentBookUid()
+	budgets, err := a.budgets.GetAllBudgetsByUid(c, uid)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[budgets.BudgetProgressHandler] failed to get budgets for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	categories, err := a.categories.GetAllCategoriesByUid(c, uid, models.CATEGORY_TYPE_EXPENSE, -1)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[budgets.BudgetProgressHandler] failed to get categories for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	accounts, err := a.accounts.GetAllAccountsByUid(c, uid)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[budgets.BudgetProgressHandler] failed to get accounts for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	accountMap := a.accounts.GetAccountMapByList(accounts)
+	exchangeRates := a.getBudgetExchangeRates(c, budgets, accounts)
+	allTotalAmounts := make(map[budgetTotalAmountsKey][]*models.Transaction)
+	progressResps := make(models.BudgetProgressResponseSlice, len(budgets))
+
+	for i := 0; i < len(budgets); i++ {
+		budget := budgets[i]
+		startTime, endTime := budget.GetPeriodTimeRange(budgetProgressReq.Year, budgetProgressReq.Month, timezone)
+		key := budgetTotalAmountsKey{startTime: startTime, endTime: endTime, tagId: budget.TagId}
+		totalAmounts, exists := allTotalAmounts[key]
+
+		if !exists {
+			if budget.TagId > 0 {
+				totalAmounts, err = a.transactions.GetAccountsAndCategoriesTotalIncomeAndExpenseByTag(c, uid, budget.TagId, startTime, endTime)
+			} else {
+				totalAmounts, err = a.transactions.GetAccountsAndCategoriesTotalIncomeAndExpense(c, uid, startTime, endTime)
+			}
+
+			if err != nil {
+				log.ErrorfWithRequestId(c, "[budgets.BudgetProgressHandler] failed to get accounts and categories total income and expense for user \"uid:%d\", because %s", uid, err.Error())
+				return nil, errs.Or(err, errs.ErrOperationFailed)
+			}
+
+			allTotalAmounts[key] = totalAmounts
+		}
+
+		progressResps[i] = a.getBudgetProgressResponse(budget, startTime, endTime, totalAmounts, categories, accountMap, exchangeRates)
+	}
+
+	sort.Sort(progressResps)
+
+	return progressResps, nil
+}
+
+// getBudgetExchangeRates returns the latest exchange rates if any account is not in the currency of some budget, or nil if exchange rates are not needed or cannot be loaded
+func (a *BudgetsApi) getBudgetExchangeRates(c *core.Context, budgets []*models.Budget, accounts []*models.Account) *models.ExchangeRateHistoryResponse {
+	for i := 0; i < len(budgets); i++ {
+		for j := 0; j < len(accounts); j++ {
+			if accounts[j].Currency == budgets[i].Currency {
+				continue
+			}
+
+			exchangeRates, err := a.exchangeRates.GetExchangeRatesByUnixTime(c, time.Now().Unix())
+
+			if err != nil {
+				log.WarnfWithRequestId(c, "[budgets.getBudgetExchangeRates] failed to get latest exchange rates, because %s", err.Error())
+				return nil
+			}
+
+			return exchangeRates
+		}
+	}
+
+	return nil
+}
+
+func (a *BudgetsApi) getBudgetProgressResponse(budget *models.Budget, startTime int64, endTime int64, totalAmounts []*models.Transaction, categories []*models.TransactionCategory, accountMap map[int64]*models.Account, exchangeRates *models.ExchangeRateHistoryResponse) *models.BudgetProgressResponse {
+	categoryIds := make(map[int64]bool)
+	categoryIds[budget.CategoryId] = true
+
+	for i := 0; i < len(categories); i++ {
+		if categories[i].ParentCategoryId == budget.CategoryId {
+			categoryIds[categories[i].CategoryId] = true
+		}
+	}
+
+	progressResp := &models.BudgetProgressResponse{
+		Budget:    budget.ToBudgetInfoResponse(),
+		StartTime: startTime,
+		EndTime:   endTime,
+	}
+
+	otherCurrencySpentAmounts := make(map[string]int64)
+
+	for i := 0; i < len(totalAmounts); i++ {
+		totalAmountItem := totalAmounts[i]
+
+		if !categoryIds[totalAmountItem.CategoryId] {
+			continue
+		}
+
+		account, exists := accountMap[totalAmountItem.AccountId]
+
+		if !exists {
+			continue
+		}
+
+		if budget.AccountId > 0 && account.AccountId != budget.AccountId && account.ParentAccountId != budget.AccountId {
+			continue
+		}
+
+		if account.Currency == budget.Currency {
+			progressResp.SpentAmount += totalAmountItem.Amount
+		} else {
+			otherCurrencySpentAmounts[account.Currency] += totalAmountItem.Amount
+		}
+	}
+
+	for currency, amount := range otherCurrencySpentAmounts {
+		if exchangeRates != nil {
+			if rate, exists := exchangeRates.GetExchangeRate(currency, budget.Currency); exists {
+				progressResp.SpentAmount += int64(math.Round(float64(amount) * rate))
+				progressResp.ExchangeRateDate = exchangeRates.Date
+				continue
+			}
+		}
+
+		if progressResp.UnconvertibleSpentAmounts == nil {
+			progressResp.UnconvertibleSpentAmounts = make(map[string]int64)
+		}
+
+		progressResp.UnconvertibleSpentAmounts[currency] = amount
+	}
+
+	progressResp.RemainingAmount = budget.Amount - progressResp.SpentAmount
+
+	return progressResp
+}
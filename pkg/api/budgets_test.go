@@ -0,0 +1,111 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/f97/gofire/pkg/models"
+)
+
+func TestGetBudgetProgressResponse_AggregateSecondaryCategories(t *testing.T) {
+	budget := &models.Budget{CategoryId: 10, Currency: "USD", Amount: 1000}
+	categories := []*models.TransactionCategory{
+		{CategoryId: 10},
+		{CategoryId: 11, ParentCategoryId: 10},
+		{CategoryId: 12, ParentCategoryId: 10},
+		{CategoryId: 20},
+		{CategoryId: 21, ParentCategoryId: 20},
+	}
+	accountMap := map[int64]*models.Account{
+		1: {AccountId: 1, Currency: "USD"},
+	}
+	totalAmounts := []*models.Transaction{
+		{CategoryId: 11, AccountId: 1, Amount: 100},
+		{CategoryId: 12, AccountId: 1, Amount: 200},
+		{CategoryId: 21, AccountId: 1, Amount: 400},
+	}
+
+	progressResp := Budgets.getBudgetProgressResponse(budget, 1, 2, totalAmounts, categories, accountMap, nil)
+	assert.Equal(t, int64(300), progressResp.SpentAmount)
+	assert.Equal(t, int64(700), progressResp.RemainingAmount)
+	assert.Equal(t, int64(1), progressResp.StartTime)
+	assert.Equal(t, int64(2), progressResp.EndTime)
+	assert.Nil(t, progressResp.UnconvertibleSpentAmounts)
+}
+
+func TestGetBudgetProgressResponse_FilterByAccount(t *testing.T) {
+	budget := &models.Budget{CategoryId: 10, AccountId: 1, Currency: "USD", Amount: 1000}
+	categories := []*models.TransactionCategory{
+		{CategoryId: 10},
+		{CategoryId: 11, ParentCategoryId: 10},
+	}
+	accountMap := map[int64]*models.Account{
+		1: {AccountId: 1, Currency: "USD"},
+		2: {AccountId: 2, ParentAccountId: 1, Currency: "USD"},
+		3: {AccountId: 3, Currency: "USD"},
+	}
+	totalAmounts := []*models.Transaction{
+		{CategoryId: 11, AccountId: 1, Amount: 100},
+		{CategoryId: 11, AccountId: 2, Amount: 200},
+		{CategoryId: 11, AccountId: 3, Amount: 400},
+		{CategoryId: 11, AccountId: 4, Amount: 800},
+	}
+
+	progressResp := Budgets.getBudgetProgressResponse(budget, 1, 2, totalAmounts, categories, accountMap, nil)
+	assert.Equal(t, int64(300), progressResp.SpentAmount)
+	assert.Equal(t, int64(700), progressResp.RemainingAmount)
+}
+
+func TestGetBudgetProgressResponse_ConvertOtherCurrencies(t *testing.T) {
+	budget := &models.Budget{CategoryId: 10, Currency: "USD", Amount: 10000}
+	categories := []*models.TransactionCategory{
+		{CategoryId: 10},
+		{CategoryId: 11, ParentCategoryId: 10},
+	}
+	accountMap := map[int64]*models.Account{
+		1: {AccountId: 1, Currency: "USD"},
+		2: {AccountId: 2, Currency: "EUR"},
+		3: {AccountId: 3, Currency: "CNY"},
+	}
+	totalAmounts := []*models.Transaction{
+		{CategoryId: 11, AccountId: 1, Amount: 100},
+		{CategoryId: 11, AccountId: 2, Amount: 1000},
+		{CategoryId: 11, AccountId: 3, Amount: 700},
+	}
+	exchangeRates := &models.ExchangeRateHistoryResponse{
+		Date:         "2024-01-02",
+		BaseCurrency: "EUR",
+		ExchangeRates: models.LatestExchangeRateSlice{
+			{Currency: "USD", Rate: "1.1"},
+		},
+	}
+
+	progressResp := Budgets.getBudgetProgressResponse(budget, 1, 2, totalAmounts, categories, accountMap, exchangeRates)
+	assert.Equal(t, int64(1200), progressResp.SpentAmount)
+	assert.Equal(t, int64(8800), progressResp.RemainingAmount)
+	assert.Equal(t, "2024-01-02", progressResp.ExchangeRateDate)
+	assert.Equal(t, map[string]int64{"CNY": 700}, progressResp.UnconvertibleSpentAmounts)
+}
+
+func TestGetBudgetProgressResponse_ExchangeRatesNotAvailable(t *testing.T) {
+	budget := &models.Budget{CategoryId: 10, Currency: "USD", Amount: 10000}
+	categories := []*models.TransactionCategory{
+		{CategoryId: 10},
+		{CategoryId: 11, ParentCategoryId: 10},
+	}
+	accountMap := map[int64]*models.Account{
+		1: {AccountId: 1, Currency: "USD"},
+		2: {AccountId: 2, Currency: "EUR"},
+	}
+	totalAmounts := []*models.Transaction{
+		{CategoryId: 11, AccountId: 1, Amount: 100},
+		{CategoryId: 11, AccountId: 2, Amount: 1000},
+	}
+
+	progressResp := Budgets.getBudgetProgressResponse(budget, 1, 2, totalAmounts, categories, accountMap, nil)
+	assert.Equal(t, int64(100), progressResp.SpentAmount)
+	assert.Equal(t, int64(9900), progressResp.RemainingAmount)
+	assert.Equal(t, "", progressResp.ExchangeRateDate)
+	assert.Equal(t, map[string]int64{"EUR": 1000}, progressResp.UnconvertibleSpentAmounts)
+}
@@ -0,0 +1,185 @@
+package api
+
+import (
+	"time"
+
+	"github.com/f97/gofire/pkg/core"
+	"github.com/f97/gofire/pkg/errs"
+	"github.com/f97/gofire/pkg/log"
+	"github.com/f97/gofire/pkg/models"
+	"github.com/f97/gofire/pkg/services"
+	"github.com/f97/gofire/pkg/utils"
+)
+
+// CreditCardsApi represents credit card api
+type CreditCardsApi struct {
+	accounts     *services.AccountService
+	transactions *services.TransactionService
+	users        *services.UserService
+	creditCards  *services.CreditCardService
+}
+
+// Initialize a credit card api singleton instance
+var (
+	CreditCards = &CreditCardsApi{
+		accounts:     services.Accounts,
+		transactions: services.Transactions,
+		users:        services.Users,
+		creditCards:  services.CreditCards,
+	}
+)
+
+// CreditCardCycleHandler returns the current statement cycle summary of credit card account of current user
+func (a *CreditCardsApi) CreditCardCycleHandler(c *core.Context) (interface{}, *errs.Error) {
+	var cycleReq models.CreditCardCycleRequest
+	err := c.ShouldBindQuery(&cycleReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[credit_cards.CreditCardCycleHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	utcOffset, err := c.GetClientTimezoneOffset()
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[credit_cards.CreditCardCycleHandler] cannot get client timezone offset, because %s", err.Error())
+		return nil, errs.ErrClientTimezoneOffsetInvalid
+	}
+
+	uid := c.GetCurrentBookUid()
+	accountMap, err := a.accounts.GetAccountsByAccountIds(c, uid, []int64{cycleReq.Id})
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[credit_cards.CreditCardCycleHandler] failed to get account \"id:%d\" for user \"uid:%d\", because %s", cycleReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	account, exists := accountMap[cycleReq.Id]
+
+	if !exists {
+		return nil, errs.ErrAccountNotFound
+	}
+
+	cycle, err := a.creditCards.GetCreditCardCycle(c, account, time.Now().Unix(), time.FixedZone("Client Timezone", int(utcOffset)*60))
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[credit_cards.CreditCardCycleHandler] failed to get statement cycle of account \"id:%d\" for user \"uid:%d\", because %s", cycleReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return cycle.ToCreditCardCycleInfoResponse(), nil
+}
+
+// CreditCardPaymentHandler creates a transfer transaction from the specified account to credit card account for current user,
+// the remaining statement balance is paid if payment amount is not set
+func (a *CreditCardsApi) CreditCardPaymentHandler(c *core.Context) (interface{}, *errs.Error) {
+	var paymentReq models.CreditCardPaymentRequest
+	err := c.ShouldBindJSON(&paymentReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[credit_cards.CreditCardPaymentHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	if paymentReq.SourceAccountId == paymentReq.Id {
+		log.WarnfWithRequestId(c, "[credit_cards.CreditCardPaymentHandler] payment source account must not be credit card account")
+		return nil, errs.ErrTransactionSourceAndDestinationIdCannotBeEqual
+	}
+
+	uid := c.GetCurrentBookUid()
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		if !errs.IsCustomError(err) {
+			log.ErrorfWithRequestId(c, "[credit_cards.CreditCardPaymentHandler] failed to get user, because %s", err.Error())
+		}
+
+		return nil, errs.ErrUserNotFound
+	}
+
+	accountMap, err := a.accounts.GetAccountsByAccountIds(c, uid, []int64{paymentReq.Id, paymentReq.SourceAccountId})
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[credit_cards.CreditCardPaymentHandler] failed to get accounts for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	creditCardAccount, exists := accountMap[paymentReq.Id]
+
+	if !exists {
+		return nil, errs.ErrDestinationAccountNotFound
+	}
+
+	sourceAccount, exists := accountMap[paymentReq.SourceAccountId]
+
+	if !exists {
+		return nil, errs.ErrSourceAccountNotFound
+	}
+
+	if creditCardAccount.Category != models.ACCOUNT_CATEGORY_CREDIT_CARD {
+		return nil, errs.ErrNotCreditCardAccount
+	}
+
+	sourceAmount := paymentReq.SourceAmount
+	destinationAmount := paymentReq.DestinationAmount
+
+	if sourceAmount == 0 {
+		if sourceAccount.Currency != creditCardAccount.Currency {
+			log.WarnfWithRequestId(c, "[credit_cards.CreditCardPaymentHandler] payment amount must be set when currencies of accounts are different")
+			return nil, errs.ErrCreditCardPaymentAmountInvalid
+		}
+
+		cycle, err := a.creditCards.GetCreditCardCycle(c, creditCardAccount, paymentReq.Time, time.FixedZone("Client Timezone", int(paymentReq.UtcOffset)*60))
+
+		if err != nil {
+			log.ErrorfWithRequestId(c, "[credit_cards.CreditCardPaymentHandler] failed to get statement cycle of account \"id:%d\" for user \"uid:%d\", because %s", creditCardAccount.AccountId, uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+
+		sourceAmount = cycle.RemainingStatementBalance
+	}
+
+	if destinationAmount == 0 {
+		if sourceAccount.Currency != creditCardAccount.Currency {
+			log.WarnfWithRequestId(c, "[credit_cards.CreditCardPaymentHandler] destination amount must be set when currencies of accounts are different")
+			return nil, errs.ErrCreditCardPaymentAmountInvalid
+		}
+
+		destinationAmount = sourceAmount
+	}
+
+	if sourceAmount <= 0 || destinationAmount <= 0 {
+		return nil, errs.ErrCreditCardPaymentAmountInvalid
+	}
+
+	transaction := &models.Transaction{
+		Uid:                  uid,
+		Type:                 models.TRANSACTION_DB_TYPE_TRANSFER_OUT,
+		CategoryId:           paymentReq.CategoryId,
+		TransactionTime:      utils.GetMinTransactionTimeFromUnixTime(paymentReq.Time),
+		TimezoneUtcOffset:    paymentReq.UtcOffset,
+		AccountId:            sourceAccount.AccountId,
+		Amount:               sourceAmount,
+		RelatedAccountId:     creditCardAccount.AccountId,
+		RelatedAccountAmount: destinationAmount,
+		Comment:              paymentReq.Comment,
+		CreatedIp:            c.ClientIP(),
+	}
+
+	transactionEditable := user.CanEditTransactionByTransactionTime(transaction.TransactionTime, paymentReq.UtcOffset)
+
+	if !transactionEditable {
+		return nil, errs.ErrCannotCreateTransactionWithThisTransactionTime
+	}
+
+	err = a.transactions.CreateTransaction(c, c.GetCurrentUid(), transaction, nil, nil)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[credit_cards.CreditCardPaymentHandler] failed to create payment transaction of account \"id:%d\" for user \"uid:%d\", because %s", creditCardAccount.AccountId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.InfofWithRequestId(c, "[credit_cards.CreditCardPaymentHandler] user \"uid:%d\" has created a new payment transaction \"id:%d\" of credit card account \"id:%d\" successfully", uid, transaction.TransactionId, creditCardAccount.AccountId)
+
+	return transaction.ToTransactionInfoResponse(nil, transactionEditable), nil
+}
@@ -1,7 +1,10 @@
 package api
 
 import (
+	"archive/zip"
+	"bytes"
 	"fmt"
+	"io"
 	"strings"
 	"time"
 
@@ -16,37 +19,78 @@ import (
 )
 
 const pageCountForDataExport = 1000
+const maxImportFileSize = 10 * 1024 * 1024
 
 // DataManagementsApi represents data management api
 type DataManagementsApi struct {
-	exporter     *converters.GoFireCSVFileExporter
-	tokens       *services.TokenService
-	users        *services.UserService
-	accounts     *services.AccountService
-	transactions *services.TransactionService
-	categories   *services.TransactionCategoryService
-	tags         *services.TransactionTagService
+	importer        *converters.GoFireCSVFileImporter
+	ofxImporter     *converters.OFXFileImporter
+	tokens          *services.TokenService
+	users           *services.UserService
+	accounts        *services.AccountService
+	transactions    *services.TransactionService
+	categories      *services.TransactionCategoryService
+	tags            *services.TransactionTagService
+	schedules       *services.TransactionScheduleService
+	loans           *services.LoanService
+	investments     *services.InvestmentService
+	savingsGoals    *services.SavingsGoalService
+	budgets         *services.BudgetService
+	rules           *services.TransactionRuleService
+	bookLocks       *services.BookLockService
+	reconciliations *services.AccountReconciliationService
+	revisions       *services.DataRevisionService
+	imports         *services.TransactionImportService
+	attachments     *services.TransactionAttachmentService
+	members         *services.BookMemberService
 }
 
 // Initialize a data management api singleton instance
 var (
 	DataManagements = &DataManagementsApi{
-		exporter:     &converters.GoFireCSVFileExporter{},
-		tokens:       services.Tokens,
-		users:        services.Users,
-		accounts:     services.Accounts,
-		transactions: services.Transactions,
-		categories:   services.TransactionCategories,
-		tags:         services.TransactionTags,
+		importer:        &converters.GoFireCSVFileImporter{},
+		ofxImporter:     &converters.OFXFileImporter{},
+		tokens:          services.Tokens,
+		users:           services.Users,
+		accounts:        services.Accounts,
+		transactions:    services.Transactions,
+		categories:      services.TransactionCategories,
+		tags:            services.TransactionTags,
+		schedules:       services.TransactionSchedules,
+		loans:           services.Loans,
+		investments:     services.Investments,
+		savingsGoals:    services.SavingsGoals,
+		budgets:         services.Budgets,
+		rules:           services.TransactionRules,
+		bookLocks:       services.BookLocks,
+		reconciliations: services.AccountReconciliations,
+		revisions:       services.DataRevisions,
+		imports:         services.TransactionImports,
+		attachments:     services.TransactionAttachments,
+		members:         services.BookMembers,
 	}
 )
 
-// ExportDataHandler returns exported data in csv format
+// ExportDataHandler returns exported data in csv, ledger-cli, hledger or beancount format, the exported data and transaction attachments are packed into a zip archive if attachments are included
 func (a *DataManagementsApi) ExportDataHandler(c *core.Context) ([]byte, string, *errs.Error) {
 	if !settings.Container.Current.EnableDataExport {
 		return nil, "", errs.ErrDataExportNotAllowed
 	}
 
+	var dataExportReq models.DataExportRequest
+	err := c.ShouldBindQuery(&dataExportReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[data_managements.ExportDataHandler] parse request failed, because %s", err.Error())
+		return nil, "", errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	exporter, fileExtension, err := converters.GetDataExporter(dataExportReq.Format)
+
+	if err != nil {
+		return nil, "", errs.Or(err, errs.ErrDataExportFormatInvalid)
+	}
+
 	timezone := time.Local
 	utcOffset, err := c.GetClientTimezoneOffset()
 
@@ -56,7 +100,14 @@ func (a *DataManagementsApi) ExportDataHandler(c *core.Context) ([]byte, string,
 		timezone = time.FixedZone("Client Timezone", int(utcOffset)*60)
 	}
 
-	uid := c.GetCurrentUid()
+	uid := c.GetCurrentBookUid()
+	err = a.members.CheckBookMemberRole(c, uid, c.GetCurrentUid(), models.BOOK_MEMBER_ROLE_EDITOR)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[data_managements.ExportDataHandler] user \"uid:%d\" cannot export data of book \"id:%d\", because %s", c.GetCurrentUid(), uid, err.Error())
+		return nil, "", errs.Or(err, errs.ErrOperationFailed)
+	}
+
 	user, err := a.users.GetUserById(c, uid)
 
 	if err != nil {
@@ -106,21 +157,143 @@ func (a *DataManagementsApi) ExportDataHandler(c *core.Context) ([]byte, string,
 		return nil, "", errs.ErrOperationFailed
 	}
 
-	result, err := a.exporter.ToExportedContent(uid, timezone, allTransactions, accountMap, categoryMap, tagMap, tagIndexs)
+	result, err := exporter.ToExportedContent(uid, timezone, allTransactions, accountMap, categoryMap, tagMap, tagIndexs)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[data_managements.ExportDataHandler] failed to get exported data for \"uid:%d\", because %s", uid, err.Error())
+		return nil, "", errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	fileName := a.getFileName(user, timezone, fileExtension)
+
+	if !dataExportReq.IncludeAttachments {
+		return result, fileName, nil
+	}
+
+	attachments, err := a.attachments.GetAllAttachmentsByUid(c, uid)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[data_managements.ExportDataHandler] failed to get attachments for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, "", errs.ErrOperationFailed
+	}
+
+	archiveResult, err := a.getExportedArchive(c, fileName, result, attachments)
 
 	if err != nil {
-		log.ErrorfWithRequestId(c, "[data_managements.ExportDataHandler] failed to get csv format exported data for \"uid:%d\", because %s", uid, err.Error())
+		log.ErrorfWithRequestId(c, "[data_managements.ExportDataHandler] failed to get exported archive for \"uid:%d\", because %s", uid, err.Error())
 		return nil, "", errs.Or(err, errs.ErrOperationFailed)
 	}
 
-	fileName := a.getFileName(user, timezone)
+	return archiveResult, a.getFileName(user, timezone, "zip"), nil
+}
+
+// ImportDataHandler imports transactions from the uploaded csv file
+func (a *DataManagementsApi) ImportDataHandler(c *core.Context) (interface{}, *errs.Error) {
+	if !settings.Container.Current.EnableDataImport {
+		return nil, errs.ErrDataImportNotAllowed
+	}
+
+	var dataImportReq models.DataImportRequest
+	err := c.ShouldBind(&dataImportReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[data_managements.ImportDataHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	data, errResult := a.readImportFile(c)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	uid := c.GetCurrentBookUid()
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		if !errs.IsCustomError(err) {
+			log.WarnfWithRequestId(c, "[data_managements.ImportDataHandler] failed to get user for user \"uid:%d\", because %s", uid, err.Error())
+		}
+
+		return nil, errs.ErrUserNotFound
+	}
+
+	importTransactions, err := a.importer.ParseImportedData(uid, data)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[data_managements.ImportDataHandler] failed to parse import file for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrImportFileFormatInvalid)
+	}
+
+	result, err := a.imports.ImportTransactions(c, user, c.GetCurrentUid(), importTransactions, dataImportReq.CreateMissing, dataImportReq.DryRun, c.ClientIP())
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[data_managements.ImportDataHandler] failed to import transactions for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	if !dataImportReq.DryRun {
+		log.InfofWithRequestId(c, "[data_managements.ImportDataHandler] user \"uid:%d\" has imported %d transactions", uid, result.ImportedCount)
+	}
+
+	return result, nil
+}
+
+// ImportStatementHandler imports transactions from the uploaded ofx / qfx bank statement to specified account
+func (a *DataManagementsApi) ImportStatementHandler(c *core.Context) (interface{}, *errs.Error) {
+	if !settings.Container.Current.EnableDataImport {
+		return nil, errs.ErrDataImportNotAllowed
+	}
+
+	var statementImportReq models.StatementImportRequest
+	err := c.ShouldBind(&statementImportReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[data_managements.ImportStatementHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	data, errResult := a.readImportFile(c)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	uid := c.GetCurrentBookUid()
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		if !errs.IsCustomError(err) {
+			log.WarnfWithRequestId(c, "[data_managements.ImportStatementHandler] failed to get user for user \"uid:%d\", because %s", uid, err.Error())
+		}
+
+		return nil, errs.ErrUserNotFound
+	}
+
+	importTransactions, err := a.ofxImporter.ParseImportedData(uid, data)
 
-	return result, fileName, nil
+	if err != nil {
+		log.WarnfWithRequestId(c, "[data_managements.ImportStatementHandler] failed to parse statement file for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrImportFileFormatInvalid)
+	}
+
+	result, err := a.imports.ImportStatementTransactions(c, user, c.GetCurrentUid(), statementImportReq.AccountId, statementImportReq.IncomeCategoryId, statementImportReq.ExpenseCategoryId, importTransactions, statementImportReq.DryRun, c.ClientIP())
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[data_managements.ImportStatementHandler] failed to import statement transactions for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	if !statementImportReq.DryRun {
+		log.InfofWithRequestId(c, "[data_managements.ImportStatementHandler] user \"uid:%d\" has imported %d transactions to account \"id:%d\", %d transactions skipped", uid, result.ImportedCount, statementImportReq.AccountId, result.SkippedCount)
+	}
+
+	return result, nil
 }
 
 // DataStatisticsHandler returns user data statistics
 func (a *DataManagementsApi) DataStatisticsHandler(c *core.Context) (interface{}, *errs.Error) {
-	uid := c.GetCurrentUid()
+	uid := c.GetCurrentBookUid()
 	totalAccountCount, err := a.accounts.GetTotalAccountCountByUid(c, uid)
 
 	if err != nil {
@@ -169,7 +342,14 @@ func (a *DataManagementsApi) ClearDataHandler(c *core.Context) (interface{}, *er
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
+	uid := c.GetCurrentBookUid()
+	err = a.members.CheckBookMemberRole(c, uid, c.GetCurrentUid(), models.BOOK_MEMBER_ROLE_OWNER)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[data_managements.ClearDataHandler] user \"uid:%d\" cannot clear data of book \"id:%d\", because %s", c.GetCurrentUid(), uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
 	user, err := a.users.GetUserById(c, uid)
 
 	if err != nil {
@@ -184,6 +364,48 @@ func (a *DataManagementsApi) ClearDataHandler(c *core.Context) (interface{}, *er
 		return nil, errs.ErrUserPasswordWrong
 	}
 
+	err = a.schedules.DeleteAllSchedules(c, uid)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[data_managements.ClearDataHandler] failed to delete all transaction schedules, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	err = a.loans.DeleteAllLoans(c, uid)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[data_managements.ClearDataHandler] failed to delete all loans, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	err = a.investments.DeleteAllHoldings(c, uid)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[data_managements.ClearDataHandler] failed to delete all investment holdings, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	err = a.savingsGoals.DeleteAllGoals(c, uid)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[data_managements.ClearDataHandler] failed to delete all savings goals, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	err = a.budgets.DeleteAllBudgets(c, uid)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[data_managements.ClearDataHandler] failed to delete all budgets, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	err = a.rules.DeleteAllRules(c, uid)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[data_managements.ClearDataHandler] failed to delete all transaction rules, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
 	err = a.transactions.DeleteAllTransactions(c, uid)
 
 	if err != nil {
@@ -205,15 +427,116 @@ func (a *DataManagementsApi) ClearDataHandler(c *core.Context) (interface{}, *er
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
+	err = a.bookLocks.DeleteAllBookLocks(c, uid)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[data_managements.ClearDataHandler] failed to delete all book locks, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	err = a.reconciliations.DeleteAllReconciliations(c, uid)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[data_managements.ClearDataHandler] failed to delete all account reconciliations, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	err = a.revisions.DeleteAllRevisions(c, uid)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[data_managements.ClearDataHandler] failed to delete all data revisions, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
 	log.InfofWithRequestId(c, "[data_managements.ClearDataHandler] user \"uid:%d\" has cleared all data", uid)
 	return true, nil
 }
 
-func (a *DataManagementsApi) getFileName(user *models.User, timezone *time.Location) string {
+func (a *DataManagementsApi) readImportFile(c *core.Context) ([]byte, *errs.Error) {
+	fileHeader, err := c.FormFile("file")
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[data_managements.readImportFile] failed to get import file, because %s", err.Error())
+		return nil, errs.ErrImportFileIsEmpty
+	}
+
+	if fileHeader.Size > maxImportFileSize {
+		return nil, errs.ErrImportFileTooLarge
+	}
+
+	file, err := fileHeader.Open()
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[data_managements.readImportFile] failed to open import file, because %s", err.Error())
+		return nil, errs.ErrOperationFailed
+	}
+
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[data_managements.readImportFile] failed to read import file, because %s", err.Error())
+		return nil, errs.ErrOperationFailed
+	}
+
+	return data, nil
+}
+
+func (a *DataManagementsApi) getExportedArchive(c *core.Context, fileName string, content []byte, attachments []*models.TransactionAttachment) ([]byte, error) {
+	buffer := &bytes.Buffer{}
+	zipWriter := zip.NewWriter(buffer)
+
+	writer, err := zipWriter.Create(fileName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = writer.Write(content)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < len(attachments); i++ {
+		attachment := attachments[i]
+		data, err := a.attachments.GetAttachmentContent(c, attachment)
+
+		if err == errs.ErrTransactionAttachmentNotFound {
+			log.WarnfWithRequestId(c, "[data_managements.getExportedArchive] content of attachment \"id:%d\" does not exist for user \"uid:%d\"", attachment.AttachmentId, attachment.Uid)
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		writer, err := zipWriter.Create(fmt.Sprintf("attachments/%d/%d_%s", attachment.TransactionId, attachment.AttachmentId, getAttachmentDownloadFileName(attachment.FileName)))
+
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = writer.Write(data)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = zipWriter.Close()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+func (a *DataManagementsApi) getFileName(user *models.User, timezone *time.Location, fileExtension string) string {
 	currentTime := utils.FormatUnixTimeToLongDateTimeWithoutSecond(time.Now().Unix(), timezone)
 	currentTime = strings.Replace(currentTime, "-", "_", -1)
 	currentTime = strings.Replace(currentTime, " ", "_", -1)
 	currentTime = strings.Replace(currentTime, ":", "_", -1)
 
-	return fmt.Sprintf("%s_%s.csv", user.Username, currentTime)
+	return fmt.Sprintf("%s_%s.%s", user.Username, currentTime, fileExtension)
 }
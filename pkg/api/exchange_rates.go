@@ -1,100 +1,66 @@
 package api
 
 import (
-	"io"
-	"net/http"
-	"sort"
-	"time"
-
 	"github.com/f97/gofire/pkg/core"
 	"github.com/f97/gofire/pkg/errs"
 	"github.com/f97/gofire/pkg/exchangerates"
 	"github.com/f97/gofire/pkg/log"
 	"github.com/f97/gofire/pkg/models"
-	"github.com/f97/gofire/pkg/settings"
+	"github.com/f97/gofire/pkg/services"
 )
 
 // ExchangeRatesApi represents exchange rate api
-type ExchangeRatesApi struct{}
+type ExchangeRatesApi struct {
+	exchangeRates *services.ExchangeRateService
+}
 
 // Initialize a exchange rate api singleton instance
 var (
-	ExchangeRates = &ExchangeRatesApi{}
+	ExchangeRates = &ExchangeRatesApi{
+		exchangeRates: services.ExchangeRates,
+	}
 )
 
 // LatestExchangeRateHandler returns latest exchange rate data
 func (a *ExchangeRatesApi) LatestExchangeRateHandler(c *core.Context) (interface{}, *errs.Error) {
-	dataSource := exchangerates.Container.Current
-
-	if dataSource == nil {
-		return nil, errs.ErrInvalidExchangeRatesDataSource
-	}
-
 	uid := c.GetCurrentUid()
+	exchangeRateResp, refreshed, err := exchangerates.Container.GetCachedLatestExchangeRates(c)
 
-	client := &http.Client{
-		Timeout: time.Duration(settings.Container.Current.ExchangeRatesRequestTimeout) * time.Millisecond,
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[exchange_rates.LatestExchangeRateHandler] failed to get latest exchange rate data for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrFailedToRequestRemoteApi)
 	}
 
-	urls := dataSource.GetRequestUrls()
-	exchangeRateResps := make([]*models.LatestExchangeRateResponse, 0, len(urls))
-
-	for i := 0; i < len(urls); i++ {
-		resp, err := client.Get(urls[i])
+	if refreshed {
+		err = a.exchangeRates.SaveExchangeRates(c, exchangerates.Container.CurrentName, exchangeRateResp)
 
 		if err != nil {
-			log.ErrorfWithRequestId(c, "[exchange_rates.LatestExchangeRateHandler] failed to request latest exchange rate data for user \"uid:%d\", because %s", uid, err.Error())
-			return nil, errs.ErrFailedToRequestRemoteApi
+			log.WarnfWithRequestId(c, "[exchange_rates.LatestExchangeRateHandler] failed to save latest exchange rate data, because %s", err.Error())
 		}
-
-		if resp.StatusCode != 200 {
-			log.ErrorfWithRequestId(c, "[exchange_rates.LatestExchangeRateHandler] failed to get latest exchange rate data response for user \"uid:%d\", because response code is not 200", uid)
-			return nil, errs.ErrFailedToRequestRemoteApi
-		}
-
-		defer resp.Body.Close()
-		body, err := io.ReadAll(resp.Body)
-		exchangeRateResp, err := dataSource.Parse(c, body)
-
-		if err != nil {
-			log.ErrorfWithRequestId(c, "[exchange_rates.LatestExchangeRateHandler] failed to parse response for user \"uid:%d\", because %s", uid, err.Error())
-			return nil, errs.Or(err, errs.ErrFailedToRequestRemoteApi)
-		}
-
-		exchangeRateResps = append(exchangeRateResps, exchangeRateResp)
 	}
 
-	lastExchangeRateResponse := exchangeRateResps[len(exchangeRateResps)-1]
-	allExchangeRatesMap := make(map[string]string)
+	return exchangeRateResp, nil
+}
 
-	for i := 0; i < len(exchangeRateResps); i++ {
-		exchangeRateResp := exchangeRateResps[i]
+// ExchangeRateHistoryHandler returns the saved exchange rate data of specified date
+func (a *ExchangeRatesApi) ExchangeRateHistoryHandler(c *core.Context) (interface{}, *errs.Error) {
+	var historyReq models.ExchangeRateHistoryRequest
+	err := c.ShouldBindQuery(&historyReq)
 
-		for j := 0; j < len(exchangeRateResp.ExchangeRates); j++ {
-			exchangeRate := exchangeRateResp.ExchangeRates[j]
-			allExchangeRatesMap[exchangeRate.Currency] = exchangeRate.Rate
-		}
+	if err != nil {
+		log.WarnfWithRequestId(c, "[exchange_rates.ExchangeRateHistoryHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	allExchangeRatesMap[lastExchangeRateResponse.BaseCurrency] = "1"
-	allExchangeRates := make(models.LatestExchangeRateSlice, 0, len(allExchangeRatesMap))
-
-	for currency, rate := range allExchangeRatesMap {
-		allExchangeRates = append(allExchangeRates, &models.LatestExchangeRate{
-			Currency: currency,
-			Rate:     rate,
-		})
-	}
+	exchangeRateResp, err := a.exchangeRates.GetExchangeRatesByDate(c, exchangerates.Container.CurrentName, historyReq.Date)
 
-	sort.Sort(allExchangeRates)
+	if err != nil {
+		if !errs.IsCustomError(err) {
+			log.ErrorfWithRequestId(c, "[exchange_rates.ExchangeRateHistoryHandler] failed to get exchange rate data of \"%s\", because %s", historyReq.Date, err.Error())
+		}
 
-	finalExchangeRateResponse := &models.LatestExchangeRateResponse{
-		DataSource:    lastExchangeRateResponse.DataSource,
-		ReferenceUrl:  lastExchangeRateResponse.ReferenceUrl,
-		UpdateTime:    lastExchangeRateResponse.UpdateTime,
-		BaseCurrency:  lastExchangeRateResponse.BaseCurrency,
-		ExchangeRates: allExchangeRates,
+		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
-	return finalExchangeRateResponse, nil
+	return exchangeRateResp, nil
 }
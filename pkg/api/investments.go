@@ -0,0 +1,429 @@
+package api
+
+import (
+	"time"
+
+	"github.com/f97/gofire/pkg/converters"
+	"github.com/f97/gofire/pkg/core"
+	"github.com/f97/gofire/pkg/errs"
+	"github.com/f97/gofire/pkg/log"
+	"github.com/f97/gofire/pkg/models"
+	"github.com/f97/gofire/pkg/services"
+)
+
+// InvestmentsApi represents investment holding api
+type InvestmentsApi struct {
+	accounts      *services.AccountService
+	investments   *services.InvestmentService
+	priceImporter *converters.InvestmentPriceCSVFileImporter
+}
+
+// Initialize an investment holding api singleton instance
+var (
+	Investments = &InvestmentsApi{
+		accounts:      services.Accounts,
+		investments:   services.Investments,
+		priceImporter: &converters.InvestmentPriceCSVFileImporter{},
+	}
+)
+
+// HoldingListHandler returns investment holding list of current user
+func (a *InvestmentsApi) HoldingListHandler(c *core.Context) (interface{}, *errs.Error) {
+	var holdingListReq models.InvestmentHoldingListRequest
+	err := c.ShouldBindQuery(&holdingListReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[investments.HoldingListHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentBookUid()
+	holdings, err := a.getHoldings(c, uid, holdingListReq.AccountId)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[investments.HoldingListHandler] failed to get holdings for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	holdingResps := make([]*models.InvestmentHoldingInfoResponse, len(holdings))
+
+	for i := 0; i < len(holdings); i++ {
+		holdingResps[i] = holdings[i].ToInvestmentHoldingInfoResponse()
+	}
+
+	return holdingResps, nil
+}
+
+// HoldingGetHandler returns one specific investment holding of current user
+func (a *InvestmentsApi) HoldingGetHandler(c *core.Context) (interface{}, *errs.Error) {
+	var holdingGetReq models.InvestmentHoldingGetRequest
+	err := c.ShouldBindQuery(&holdingGetReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[investments.HoldingGetHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentBookUid()
+	holding, err := a.investments.GetHoldingById(c, uid, holdingGetReq.Id)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[investments.HoldingGetHandler] failed to get holding \"id:%d\" for user \"uid:%d\", because %s", holdingGetReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return holding.ToInvestmentHoldingInfoResponse(), nil
+}
+
+// HoldingCreateHandler saves a new investment holding by request parameters for current user
+func (a *InvestmentsApi) HoldingCreateHandler(c *core.Context) (interface{}, *errs.Error) {
+	var holdingCreateReq models.InvestmentHoldingCreateRequest
+	err := c.ShouldBindJSON(&holdingCreateReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[investments.HoldingCreateHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentBookUid()
+	holding := &models.InvestmentHolding{
+		Uid:        uid,
+		AccountId:  holdingCreateReq.AccountId,
+		Symbol:     holdingCreateReq.Symbol,
+		Name:       holdingCreateReq.Name,
+		CostMethod: holdingCreateReq.CostMethod,
+		Comment:    holdingCreateReq.Comment,
+	}
+
+	err = a.investments.CreateHolding(c, c.GetCurrentUid(), holding)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[investments.HoldingCreateHandler] failed to create holding \"%s\" of account \"id:%d\" for user \"uid:%d\", because %s", holdingCreateReq.Symbol, holdingCreateReq.AccountId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.InfofWithRequestId(c, "[investments.HoldingCreateHandler] user \"uid:%d\" has created a new holding \"id:%d\" successfully", uid, holding.HoldingId)
+
+	return holding.ToInvestmentHoldingInfoResponse(), nil
+}
+
+// HoldingModifyHandler saves an existed investment holding by request parameters for current user
+func (a *InvestmentsApi) HoldingModifyHandler(c *core.Context) (interface{}, *errs.Error) {
+	var holdingModifyReq models.InvestmentHoldingModifyRequest
+	err := c.ShouldBindJSON(&holdingModifyReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[investments.HoldingModifyHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentBookUid()
+	holding, err := a.investments.GetHoldingById(c, uid, holdingModifyReq.Id)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[investments.HoldingModifyHandler] failed to get holding \"id:%d\" for user \"uid:%d\", because %s", holdingModifyReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	holding.Symbol = holdingModifyReq.Symbol
+	holding.Name = holdingModifyReq.Name
+	holding.CostMethod = holdingModifyReq.CostMethod
+	holding.Comment = holdingModifyReq.Comment
+
+	err = a.investments.ModifyHolding(c, c.GetCurrentUid(), holding)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[investments.HoldingModifyHandler] failed to update holding \"id:%d\" for user \"uid:%d\", because %s", holdingModifyReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.InfofWithRequestId(c, "[investments.HoldingModifyHandler] user \"uid:%d\" has updated holding \"id:%d\" successfully", uid, holdingModifyReq.Id)
+
+	return holding.ToInvestmentHoldingInfoResponse(), nil
+}
+
+// HoldingDeleteHandler deletes an existed investment holding by request parameters for current user
+func (a *InvestmentsApi) HoldingDeleteHandler(c *core.Context) (interface{}, *errs.Error) {
+	var holdingDeleteReq models.InvestmentHoldingDeleteRequest
+	err := c.ShouldBindJSON(&holdingDeleteReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[investments.HoldingDeleteHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentBookUid()
+	err = a.investments.DeleteHolding(c, uid, c.GetCurrentUid(), holdingDeleteReq.Id)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[investments.HoldingDeleteHandler] failed to delete holding \"id:%d\" for user \"uid:%d\", because %s", holdingDeleteReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.InfofWithRequestId(c, "[investments.HoldingDeleteHandler] user \"uid:%d\" has deleted holding \"id:%d\"", uid, holdingDeleteReq.Id)
+	return true, nil
+}
+
+// TradeListHandler returns all trades of one specific investment holding of current user
+func (a *InvestmentsApi) TradeListHandler(c *core.Context) (interface{}, *errs.Error) {
+	var tradeListReq models.InvestmentTradeListRequest
+	err := c.ShouldBindQuery(&tradeListReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[investments.TradeListHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentBookUid()
+	trades, err := a.investments.GetAllTradesByHoldingId(c, uid, tradeListReq.HoldingId)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[investments.TradeListHandler] failed to get trades of holding \"id:%d\" for user \"uid:%d\", because %s", tradeListReq.HoldingId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	tradeResps := make([]*models.InvestmentTradeInfoResponse, len(trades))
+
+	for i := 0; i < len(trades); i++ {
+		tradeResps[i] = trades[i].ToInvestmentTradeInfoResponse()
+	}
+
+	return tradeResps, nil
+}
+
+// TradeCreateHandler saves a new buy or sell trade by request parameters for current user
+func (a *InvestmentsApi) TradeCreateHandler(c *core.Context) (interface{}, *errs.Error) {
+	var tradeCreateReq models.InvestmentTradeCreateRequest
+	err := c.ShouldBindJSON(&tradeCreateReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[investments.TradeCreateHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentBookUid()
+	trade := &models.InvestmentTrade{
+		Uid:           uid,
+		HoldingId:     tradeCreateReq.HoldingId,
+		Type:          tradeCreateReq.Type,
+		TradeUnixTime: tradeCreateReq.Time,
+		Units:         tradeCreateReq.Units,
+		Amount:        tradeCreateReq.Amount,
+		Fee:           tradeCreateReq.Fee,
+		Comment:       tradeCreateReq.Comment,
+		CreatedIp:     c.ClientIP(),
+	}
+
+	err = a.investments.CreateTrade(c, c.GetCurrentUid(), trade)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[investments.TradeCreateHandler] failed to create trade of holding \"id:%d\" for user \"uid:%d\", because %s", tradeCreateReq.HoldingId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.InfofWithRequestId(c, "[investments.TradeCreateHandler] user \"uid:%d\" has created a new trade \"id:%d\" of holding \"id:%d\" successfully", uid, trade.TradeId, trade.HoldingId)
+
+	return trade.ToInvestmentTradeInfoResponse(), nil
+}
+
+// TradeDeleteHandler deletes an existed trade by request parameters for current user
+func (a *InvestmentsApi) TradeDeleteHandler(c *core.Context) (interface{}, *errs.Error) {
+	var tradeDeleteReq models.InvestmentTradeDeleteRequest
+	err := c.ShouldBindJSON(&tradeDeleteReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[investments.TradeDeleteHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentBookUid()
+	err = a.investments.DeleteTrade(c, uid, c.GetCurrentUid(), tradeDeleteReq.Id)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[investments.TradeDeleteHandler] failed to delete trade \"id:%d\" for user \"uid:%d\", because %s", tradeDeleteReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.InfofWithRequestId(c, "[investments.TradeDeleteHandler] user \"uid:%d\" has deleted trade \"id:%d\"", uid, tradeDeleteReq.Id)
+	return true, nil
+}
+
+// PriceListHandler returns all prices of one specific investment holding of current user
+func (a *InvestmentsApi) PriceListHandler(c *core.Context) (interface{}, *errs.Error) {
+	var priceListReq models.InvestmentPriceListRequest
+	err := c.ShouldBindQuery(&priceListReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[investments.PriceListHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentBookUid()
+	prices, err := a.investments.GetAllPricesByHoldingId(c, uid, priceListReq.HoldingId)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[investments.PriceListHandler] failed to get prices of holding \"id:%d\" for user \"uid:%d\", because %s", priceListReq.HoldingId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	priceResps := make([]*models.InvestmentPriceInfoResponse, len(prices))
+
+	for i := 0; i < len(prices); i++ {
+		priceResps[i] = prices[i].ToInvestmentPriceInfoResponse()
+	}
+
+	return priceResps, nil
+}
+
+// PriceCreateHandler saves a new price of investment holding by request parameters for current user
+func (a *InvestmentsApi) PriceCreateHandler(c *core.Context) (interface{}, *errs.Error) {
+	var priceCreateReq models.InvestmentPriceCreateRequest
+	err := c.ShouldBindJSON(&priceCreateReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[investments.PriceCreateHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentBookUid()
+	price := &models.InvestmentPrice{
+		Uid:           uid,
+		HoldingId:     priceCreateReq.HoldingId,
+		PriceUnixTime: priceCreateReq.Time,
+		Price:         priceCreateReq.Price,
+	}
+
+	err = a.investments.CreatePrice(c, c.GetCurrentUid(), price)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[investments.PriceCreateHandler] failed to create price of holding \"id:%d\" for user \"uid:%d\", because %s", priceCreateReq.HoldingId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.InfofWithRequestId(c, "[investments.PriceCreateHandler] user \"uid:%d\" has created a new price \"id:%d\" of holding \"id:%d\" successfully", uid, price.PriceId, price.HoldingId)
+
+	return price.ToInvestmentPriceInfoResponse(), nil
+}
+
+// PriceDeleteHandler deletes an existed price by request parameters for current user
+func (a *InvestmentsApi) PriceDeleteHandler(c *core.Context) (interface{}, *errs.Error) {
+	var priceDeleteReq models.InvestmentPriceDeleteRequest
+	err := c.ShouldBindJSON(&priceDeleteReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[investments.PriceDeleteHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentBookUid()
+	err = a.investments.DeletePrice(c, uid, c.GetCurrentUid(), priceDeleteReq.Id)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[investments.PriceDeleteHandler] failed to delete price \"id:%d\" for user \"uid:%d\", because %s", priceDeleteReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.InfofWithRequestId(c, "[investments.PriceDeleteHandler] user \"uid:%d\" has deleted price \"id:%d\"", uid, priceDeleteReq.Id)
+	return true, nil
+}
+
+// PriceImportHandler imports prices from the uploaded csv file to the holdings of specified investment account for current user
+func (a *InvestmentsApi) PriceImportHandler(c *core.Context) (interface{}, *errs.Error) {
+	var priceImportReq models.InvestmentPriceImportRequest
+	err := c.ShouldBind(&priceImportReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[investments.PriceImportHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	data, errResult := DataManagements.readImportFile(c)
+
+	if errResult != nil {
+		return nil, errResult
+	}
+
+	uid := c.GetCurrentBookUid()
+	importPrices, err := a.priceImporter.ParseImportedPrices(data, priceImportReq.UtcOffset)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[investments.PriceImportHandler] failed to parse price file for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrImportFileFormatInvalid)
+	}
+
+	importedCount, skippedCount, err := a.investments.ImportPrices(c, uid, c.GetCurrentUid(), priceImportReq.AccountId, importPrices)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[investments.PriceImportHandler] failed to import prices to account \"id:%d\" for user \"uid:%d\", because %s", priceImportReq.AccountId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.InfofWithRequestId(c, "[investments.PriceImportHandler] user \"uid:%d\" has imported %d prices to account \"id:%d\", %d prices skipped", uid, importedCount, priceImportReq.AccountId, skippedCount)
+
+	return &models.InvestmentPriceImportResponse{
+		ImportedCount: importedCount,
+		SkippedCount:  skippedCount,
+	}, nil
+}
+
+// PerformanceHandler returns the units, cost basis, market value and gains of investment holdings of current user
+func (a *InvestmentsApi) PerformanceHandler(c *core.Context) (interface{}, *errs.Error) {
+	var performanceReq models.InvestmentPerformanceRequest
+	err := c.ShouldBindQuery(&performanceReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[investments.PerformanceHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentBookUid()
+	holdings, err := a.getHoldings(c, uid, performanceReq.AccountId)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[investments.PerformanceHandler] failed to get holdings for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	accountIds := make([]int64, len(holdings))
+
+	for i := 0; i < len(holdings); i++ {
+		accountIds[i] = holdings[i].AccountId
+	}
+
+	accountMap, err := a.accounts.GetAccountsByAccountIds(c, uid, accountIds)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[investments.PerformanceHandler] failed to get accounts for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	allPerformances, err := a.investments.GetHoldingPerformances(c, uid, holdings, []int64{time.Now().Unix()})
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[investments.PerformanceHandler] failed to calculate performance of holdings for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	performanceResps := make([]*models.InvestmentHoldingPerformanceResponse, 0, len(holdings))
+
+	for i := 0; i < len(holdings); i++ {
+		holding := holdings[i]
+		account, exists := accountMap[holding.AccountId]
+
+		if !exists {
+			continue
+		}
+
+		performanceResps = append(performanceResps, allPerformances[0][holding.HoldingId].ToInvestmentHoldingPerformanceResponse(holding, account.Currency))
+	}
+
+	return performanceResps, nil
+}
+
+func (a *InvestmentsApi) getHoldings(c *core.Context, uid int64, accountId int64) ([]*models.InvestmentHolding, error) {
+	if accountId > 0 {
+		return a.investments.GetHoldingsByAccountId(c, uid, accountId)
+	}
+
+	return a.investments.GetAllHoldingsByUid(c, uid)
+}
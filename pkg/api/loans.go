@@ -0,0 +1,191 @@
+package api
+
+import (
+	"github.com/f97/gofire/pkg/core"
+	"github.com/f97/gofire/pkg/errs"
+	"github.com/f97/gofire/pkg/log"
+	"github.com/f97/gofire/pkg/models"
+	"github.com/f97/gofire/pkg/services"
+)
+
+// LoansApi represents loan api
+type LoansApi struct {
+	loans *services.LoanService
+}
+
+// Initialize a loan api singleton instance
+var (
+	Loans = &LoansApi{
+		loans: services.Loans,
+	}
+)
+
+// LoanListHandler returns loan list of current user
+func (a *LoansApi) LoanListHandler(c *core.Context) (interface{}, *errs.Error) {
+	uid := c.GetCurrentBookUid()
+	loans, err := a.loans.GetAllLoansByUid(c, uid)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[loans.LoanListHandler] failed to get loans for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	loanResps := make([]*models.LoanInfoResponse, len(loans))
+
+	for i := 0; i < len(loans); i++ {
+		loanResps[i] = loans[i].ToLoanInfoResponse()
+	}
+
+	return loanResps, nil
+}
+
+// LoanGetHandler returns one specific loan of current user
+func (a *LoansApi) LoanGetHandler(c *core.Context) (interface{}, *errs.Error) {
+	var loanGetReq models.LoanGetRequest
+	err := c.ShouldBindQuery(&loanGetReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[loans.LoanGetHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentBookUid()
+	loan, err := a.loans.GetLoanById(c, uid, loanGetReq.Id)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[loans.LoanGetHandler] failed to get loan \"id:%d\" for user \"uid:%d\", because %s", loanGetReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return loan.ToLoanInfoResponse(), nil
+}
+
+// LoanScheduleHandler returns the amortization schedule of one specific loan of current user
+func (a *LoansApi) LoanScheduleHandler(c *core.Context) (interface{}, *errs.Error) {
+	var loanGetReq models.LoanGetRequest
+	err := c.ShouldBindQuery(&loanGetReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[loans.LoanScheduleHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentBookUid()
+	loan, err := a.loans.GetLoanById(c, uid, loanGetReq.Id)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[loans.LoanScheduleHandler] failed to get loan \"id:%d\" for user \"uid:%d\", because %s", loanGetReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	items := loan.GetAmortizationSchedule()
+	itemResps := make([]*models.LoanAmortizationItemResponse, len(items))
+
+	for i := 0; i < len(items); i++ {
+		itemResps[i] = items[i].ToLoanAmortizationItemResponse()
+	}
+
+	return itemResps, nil
+}
+
+// LoanCreateHandler saves a new loan by request parameters for current user
+func (a *LoansApi) LoanCreateHandler(c *core.Context) (interface{}, *errs.Error) {
+	var loanCreateReq models.LoanCreateRequest
+	err := c.ShouldBindJSON(&loanCreateReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[loans.LoanCreateHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentBookUid()
+	loan := &models.Loan{
+		Uid:                    uid,
+		AccountId:              loanCreateReq.AccountId,
+		Principal:              loanCreateReq.Principal,
+		InterestRate:           loanCreateReq.InterestRate,
+		TermMonths:             loanCreateReq.TermMonths,
+		StartTime:              loanCreateReq.StartTime,
+		TimezoneUtcOffset:      loanCreateReq.UtcOffset,
+		AutoCreateTransactions: loanCreateReq.AutoCreateTransactions,
+		PaymentAccountId:       loanCreateReq.PaymentAccountId,
+		PrincipalCategoryId:    loanCreateReq.PrincipalCategoryId,
+		InterestCategoryId:     loanCreateReq.InterestCategoryId,
+		Comment:                loanCreateReq.Comment,
+		CreatedIp:              c.ClientIP(),
+	}
+
+	err = a.loans.CreateLoan(c, c.GetCurrentUid(), loan)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[loans.LoanCreateHandler] failed to create loan of account \"id:%d\" for user \"uid:%d\", because %s", loanCreateReq.AccountId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.InfofWithRequestId(c, "[loans.LoanCreateHandler] user \"uid:%d\" has created a new loan \"id:%d\" successfully", uid, loan.LoanId)
+
+	return loan.ToLoanInfoResponse(), nil
+}
+
+// LoanModifyHandler saves an existed loan by request parameters for current user
+func (a *LoansApi) LoanModifyHandler(c *core.Context) (interface{}, *errs.Error) {
+	var loanModifyReq models.LoanModifyRequest
+	err := c.ShouldBindJSON(&loanModifyReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[loans.LoanModifyHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentBookUid()
+	loan, err := a.loans.GetLoanById(c, uid, loanModifyReq.Id)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[loans.LoanModifyHandler] failed to get loan \"id:%d\" for user \"uid:%d\", because %s", loanModifyReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	loan.Principal = loanModifyReq.Principal
+	loan.InterestRate = loanModifyReq.InterestRate
+	loan.TermMonths = loanModifyReq.TermMonths
+	loan.StartTime = loanModifyReq.StartTime
+	loan.TimezoneUtcOffset = loanModifyReq.UtcOffset
+	loan.AutoCreateTransactions = loanModifyReq.AutoCreateTransactions
+	loan.PaymentAccountId = loanModifyReq.PaymentAccountId
+	loan.PrincipalCategoryId = loanModifyReq.PrincipalCategoryId
+	loan.InterestCategoryId = loanModifyReq.InterestCategoryId
+	loan.Comment = loanModifyReq.Comment
+
+	err = a.loans.ModifyLoan(c, c.GetCurrentUid(), loan)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[loans.LoanModifyHandler] failed to update loan \"id:%d\" for user \"uid:%d\", because %s", loanModifyReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.InfofWithRequestId(c, "[loans.LoanModifyHandler] user \"uid:%d\" has updated loan \"id:%d\" successfully", uid, loanModifyReq.Id)
+
+	return loan.ToLoanInfoResponse(), nil
+}
+
+// LoanDeleteHandler deletes an existed loan by request parameters for current user
+func (a *LoansApi) LoanDeleteHandler(c *core.Context) (interface{}, *errs.Error) {
+	var loanDeleteReq models.LoanDeleteRequest
+	err := c.ShouldBindJSON(&loanDeleteReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[loans.LoanDeleteHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentBookUid()
+	err = a.loans.DeleteLoan(c, uid, c.GetCurrentUid(), loanDeleteReq.Id)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[loans.LoanDeleteHandler] failed to delete loan \"id:%d\" for user \"uid:%d\", because %s", loanDeleteReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.InfofWithRequestId(c, "[loans.LoanDeleteHandler] user \"uid:%d\" has deleted loan \"id:%d\"", uid, loanDeleteReq.Id)
+	return true, nil
+}
@@ -0,0 +1,372 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/f97/gofire/pkg/core"
+	"github.com/f97/gofire/pkg/errs"
+	"github.com/f97/gofire/pkg/log"
+	"github.com/f97/gofire/pkg/models"
+	"github.com/f97/gofire/pkg/oidc"
+	"github.com/f97/gofire/pkg/services"
+	"github.com/f97/gofire/pkg/settings"
+	"github.com/f97/gofire/pkg/utils"
+)
+
+const oidcStateCookieName = "ebk_oidc_state"
+const oidcStateCookiePath = "/api/oidc"
+const oidcStateExpiredTime = 600 // 10 minutes
+const oidcUsernameMaxLength = 32
+
+// OIDCAuthorizationsApi represents openid connect single sign-on api
+type OIDCAuthorizationsApi struct {
+	users         *services.UserService
+	tokens        *services.TokenService
+	externalAuths *services.UserExternalAuthService
+}
+
+// Initialize a openid connect single sign-on api singleton instance
+var (
+	OIDCAuthorizations = &OIDCAuthorizationsApi{
+		users:         services.Users,
+		tokens:        services.Tokens,
+		externalAuths: services.UserExternalAuths,
+	}
+)
+
+// AuthorizeHandler returns the authorization url of identity provider and saves the state, nonce and pkce code verifier to cookie
+func (a *OIDCAuthorizationsApi) AuthorizeHandler(c *core.Context) (interface{}, *errs.Error) {
+	provider := oidc.Container.Current
+
+	if provider == nil {
+		return nil, errs.ErrOIDCNotEnabled
+	}
+
+	state, err := utils.GetRandomNumberOrLetter(32)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[oidc_authorizations.AuthorizeHandler] failed to generate state, because %s", err.Error())
+		return nil, errs.ErrOperationFailed
+	}
+
+	nonce, err := utils.GetRandomNumberOrLetter(32)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[oidc_authorizations.AuthorizeHandler] failed to generate nonce, because %s", err.Error())
+		return nil, errs.ErrOperationFailed
+	}
+
+	codeVerifier, err := utils.GetRandomNumberOrLetter(64)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[oidc_authorizations.AuthorizeHandler] failed to generate code verifier, because %s", err.Error())
+		return nil, errs.ErrOperationFailed
+	}
+
+	authorizeUrl, err := provider.GetAuthorizationUrl(c, state, nonce, codeVerifier)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[oidc_authorizations.AuthorizeHandler] failed to get authorization url, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrOIDCProviderRequestFailed)
+	}
+
+	expiredUnixTime := time.Now().Unix() + oidcStateExpiredTime
+	cookieValue, err := utils.EncryptSecret(fmt.Sprintf("%s|%s|%s|%d", state, nonce, codeVerifier, expiredUnixTime), settings.Container.Current.SecretKey)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[oidc_authorizations.AuthorizeHandler] failed to encrypt state, because %s", err.Error())
+		return nil, errs.ErrOperationFailed
+	}
+
+	c.SetCookie(oidcStateCookieName, cookieValue, oidcStateExpiredTime, oidcStateCookiePath, "", false, true)
+
+	authorizeResp := &models.OIDCAuthorizeResponse{
+		ProviderName: settings.Container.Current.OIDCProviderName,
+		AuthorizeUrl: authorizeUrl,
+	}
+
+	return authorizeResp, nil
+}
+
+// CallbackHandler verifies the authorization code returned by identity provider and authorizes the linked (or auto registered) user
+func (a *OIDCAuthorizationsApi) CallbackHandler(c *core.Context) (interface{}, *errs.Error) {
+	provider := oidc.Container.Current
+
+	if provider == nil {
+		return nil, errs.ErrOIDCNotEnabled
+	}
+
+	var callbackReq models.OIDCCallbackRequest
+	err := c.ShouldBindJSON(&callbackReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[oidc_authorizations.CallbackHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	nonce, codeVerifier, err := a.getSavedState(c, callbackReq.State)
+	c.SetCookie(oidcStateCookieName, "", -1, oidcStateCookiePath, "", false, true)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[oidc_authorizations.CallbackHandler] state is invalid, because %s", err.Error())
+		return nil, errs.ErrOIDCStateInvalid
+	}
+
+	claims, err := provider.Authenticate(c, callbackReq.Code, codeVerifier, nonce)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[oidc_authorizations.CallbackHandler] failed to authenticate by identity provider, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrOIDCProviderRequestFailed)
+	}
+
+	user, err := a.getOrCreateUser(c, claims)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[oidc_authorizations.CallbackHandler] failed to get user of identity provider account \"%s\", because %s", claims.Subject, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	if user.Disabled {
+		log.WarnfWithRequestId(c, "[oidc_authorizations.CallbackHandler] login failed for user \"uid:%d\", because user is disabled", user.Uid)
+		return nil, errs.ErrUserIsDisabled
+	}
+
+	err = a.users.UpdateUserLastLoginTime(c, user.Uid)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[oidc_authorizations.CallbackHandler] failed to update last login time for user \"uid:%d\", because %s", user.Uid, err.Error())
+	}
+
+	twoFactorMethods, err := Authorizations.getTwoFactorMethods(c, user.Uid)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[oidc_authorizations.CallbackHandler] failed to check two factor setting for user \"uid:%d\", because %s", user.Uid, err.Error())
+		return nil, errs.Or(err, errs.ErrSystemError)
+	}
+
+	twoFactorEnable := len(twoFactorMethods) > 0
+
+	var token string
+	var tokenClaims *core.UserTokenClaims
+
+	if twoFactorEnable {
+		token, tokenClaims, err = a.tokens.CreateRequire2FAToken(c, user)
+	} else {
+		token, tokenClaims, err = a.tokens.CreateToken(c, user)
+	}
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[oidc_authorizations.CallbackHandler] failed to create token for user \"uid:%d\", because %s", user.Uid, err.Error())
+		return nil, errs.ErrTokenGenerating
+	}
+
+	if !twoFactorEnable {
+		c.SetTextualToken(token)
+	}
+
+	c.SetTokenClaims(tokenClaims)
+
+	log.InfofWithRequestId(c, "[oidc_authorizations.CallbackHandler] user \"uid:%d\" has logined by single sign-on, token type is %d, token will be expired at %d", user.Uid, tokenClaims.Type, tokenClaims.ExpiresAt)
+
+	authResp := &models.AuthResponse{
+		Token:            token,
+		Need2FA:          twoFactorEnable,
+		TwoFactorMethods: twoFactorMethods,
+		NeedVerifyEmail:  false,
+		User:             user.ToUserBasicInfo(),
+	}
+
+	return authResp, nil
+}
+
+func (a *OIDCAuthorizationsApi) getSavedState(c *core.Context, state string) (nonce string, codeVerifier string, err error) {
+	cookieValue, err := c.Cookie(oidcStateCookieName)
+
+	if err != nil {
+		return "", "", err
+	}
+
+	savedState, err := utils.DecryptSecret(cookieValue, settings.Container.Current.SecretKey)
+
+	if err != nil {
+		return "", "", err
+	}
+
+	items := strings.Split(savedState, "|")
+
+	if len(items) != 4 {
+		return "", "", errs.ErrOIDCStateInvalid
+	}
+
+	expiredUnixTime, err := utils.StringToInt64(items[3])
+
+	if err != nil {
+		return "", "", err
+	}
+
+	if items[0] != state || expiredUnixTime < time.Now().Unix() {
+		return "", "", errs.ErrOIDCStateInvalid
+	}
+
+	return items[1], items[2], nil
+}
+
+func (a *OIDCAuthorizationsApi) getOrCreateUser(c *core.Context, claims *oidc.OIDCIdTokenClaims) (*models.User, error) {
+	externalAuth, err := a.externalAuths.GetExternalAuthByIssuerAndSubject(c, claims.Issuer, claims.Subject)
+
+	if err == nil {
+		return a.users.GetUserById(c, externalAuth.Uid)
+	} else if err != errs.ErrOIDCUserNotLinked {
+		return nil, err
+	}
+
+	// only the verified email can be used to link existed user or register new user, otherwise anyone could take over the user with same email
+	if claims.Email == "" || !claims.EmailVerified {
+		return nil, errs.ErrOIDCEmailNotVerified
+	}
+
+	user, err := a.users.GetUserByEmail(c, claims.Email)
+
+	if err == errs.ErrUserNotFound {
+		if !settings.Container.Current.OIDCAutoRegister {
+			return nil, errs.ErrOIDCUserNotLinked
+		}
+
+		user, err = a.createUser(c, claims)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	externalAuth = &models.UserExternalAuth{
+		Uid:     user.Uid,
+		Issuer:  claims.Issuer,
+		Subject: claims.Subject,
+	}
+
+	err = a.externalAuths.CreateExternalAuth(c, externalAuth)
+
+	if err != nil {
+		return nil, err
+	}
+
+	log.InfofWithRequestId(c, "[oidc_authorizations.getOrCreateUser] user \"uid:%d\" has been linked to identity provider account \"%s\"", user.Uid, claims.Subject)
+
+	return user, nil
+}
+
+func (a *OIDCAuthorizationsApi) createUser(c *core.Context, claims *oidc.OIDCIdTokenClaims) (*models.User, error) {
+	username, err := a.getAvailableUsername(c, claims)
+
+	if err != nil {
+		return nil, err
+	}
+
+	password, err := utils.GetRandomString(32)
+
+	if err != nil {
+		return nil, err
+	}
+
+	nickname := strings.TrimSpace(claims.Name)
+
+	if nickname == "" {
+		nickname = username
+	} else if len(nickname) > 64 {
+		nickname = utils.SubString(nickname, 0, 64)
+	}
+
+	user := &models.User{
+		Username:             username,
+		Email:                claims.Email,
+		Nickname:             nickname,
+		Password:             password,
+		Language:             settings.Container.Current.OIDCDefaultLanguage,
+		DefaultCurrency:      settings.Container.Current.OIDCDefaultCurrency,
+		TransactionEditScope: models.TRANSACTION_EDIT_SCOPE_ALL,
+		EmailVerified:        true,
+	}
+
+	err = a.users.CreateUser(c, user)
+
+	if err != nil {
+		return nil, err
+	}
+
+	log.InfofWithRequestId(c, "[oidc_authorizations.createUser] user \"%s\" has registered by single sign-on successfully, uid is %d", user.Username, user.Uid)
+
+	return user, nil
+}
+
+func (a *OIDCAuthorizationsApi) getAvailableUsername(c *core.Context, claims *oidc.OIDCIdTokenClaims) (string, error) {
+	candidates := []string{
+		a.getUsernameCandidate(claims.PreferredUsername),
+		a.getUsernameCandidate(strings.Split(claims.Email, "@")[0]),
+	}
+
+	baseUsername := "user"
+
+	for i := 0; i < len(candidates); i++ {
+		candidate := candidates[i]
+
+		if candidate == "" {
+			continue
+		}
+
+		exists, err := a.users.ExistsUsername(c, candidate)
+
+		if err != nil {
+			return "", err
+		} else if !exists {
+			return candidate, nil
+		}
+
+		if baseUsername == "user" {
+			baseUsername = candidate
+		}
+	}
+
+	if len(baseUsername) > oidcUsernameMaxLength-5 {
+		baseUsername = baseUsername[:oidcUsernameMaxLength-5]
+	}
+
+	for i := 0; i < 5; i++ {
+		suffix, err := utils.GetRandomInteger(10000)
+
+		if err != nil {
+			return "", err
+		}
+
+		candidate := fmt.Sprintf("%s-%04d", baseUsername, suffix)
+		exists, err := a.users.ExistsUsername(c, candidate)
+
+		if err != nil {
+			return "", err
+		} else if !exists {
+			return candidate, nil
+		}
+	}
+
+	return "", errs.ErrUsernameAlreadyExists
+}
+
+func (a *OIDCAuthorizationsApi) getUsernameCandidate(value string) string {
+	var builder strings.Builder
+
+	for _, ch := range strings.ToLower(value) {
+		if (ch >= 'a' && ch <= 'z') || (ch >= '0' && ch <= '9') || ch == '_' || ch == '-' {
+			builder.WriteRune(ch)
+		}
+	}
+
+	candidate := builder.String()
+
+	if len(candidate) > oidcUsernameMaxLength {
+		candidate = candidate[:oidcUsernameMaxLength]
+	}
+
+	return candidate
+}
@@ -0,0 +1,255 @@
+package api
+
+import (
+	"time"
+
+	"github.com/f97/gofire/pkg/core"
+	"github.com/f97/gofire/pkg/errs"
+	"github.com/f97/gofire/pkg/log"
+	"github.com/f97/gofire/pkg/models"
+	"github.com/f97/gofire/pkg/services"
+	"github.com/f97/gofire/pkg/utils"
+)
+
+// savingsGoalContributionMonths is the count of recent months whose transfers are used to calculate average monthly contribution
+const savingsGoalContributionMonths = 6
+
+// SavingsGoalsApi represents savings goal api
+type SavingsGoalsApi struct {
+	goals    *services.SavingsGoalService
+	accounts *services.AccountService
+}
+
+// Initialize a savings goal api singleton instance
+var (
+	SavingsGoals = &SavingsGoalsApi{
+		goals:    services.SavingsGoals,
+		accounts: services.Accounts,
+	}
+)
+
+// GoalListHandler returns savings goal list of current user
+func (a *SavingsGoalsApi) GoalListHandler(c *core.Context) (interface{}, *errs.Error) {
+	uid := c.GetCurrentBookUid()
+	goals, err := a.goals.GetAllGoalsByUid(c, uid)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[savings_goals.GoalListHandler] failed to get savings goals for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	goalResps := make([]*models.SavingsGoalInfoResponse, len(goals))
+
+	for i := 0; i < len(goals); i++ {
+		goalResps[i] = goals[i].ToSavingsGoalInfoResponse()
+	}
+
+	return goalResps, nil
+}
+
+// GoalGetHandler returns one specific savings goal of current user
+func (a *SavingsGoalsApi) GoalGetHandler(c *core.Context) (interface{}, *errs.Error) {
+	var goalGetReq models.SavingsGoalGetRequest
+	err := c.ShouldBindQuery(&goalGetReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[savings_goals.GoalGetHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentBookUid()
+	goal, err := a.goals.GetGoalById(c, uid, goalGetReq.Id)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[savings_goals.GoalGetHandler] failed to get savings goal \"id:%d\" for user \"uid:%d\", because %s", goalGetReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return goal.ToSavingsGoalInfoResponse(), nil
+}
+
+// GoalCreateHandler saves a new savings goal by request parameters for current user
+func (a *SavingsGoalsApi) GoalCreateHandler(c *core.Context) (interface{}, *errs.Error) {
+	var goalCreateReq models.SavingsGoalCreateRequest
+	err := c.ShouldBindJSON(&goalCreateReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[savings_goals.GoalCreateHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	accountIds, err := utils.StringArrayToInt64Array(goalCreateReq.AccountIds)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[savings_goals.GoalCreateHandler] parse account ids failed, because %s", err.Error())
+		return nil, errs.ErrSavingsGoalAccountInvalid
+	}
+
+	uid := c.GetCurrentBookUid()
+	goal := &models.SavingsGoal{
+		Uid:          uid,
+		Name:         goalCreateReq.Name,
+		TargetAmount: goalCreateReq.TargetAmount,
+		TargetDate:   goalCreateReq.TargetDate,
+		Comment:      goalCreateReq.Comment,
+	}
+
+	goal.SetAccountIds(utils.ToUniqueInt64Slice(accountIds))
+
+	err = a.goals.CreateGoal(c, c.GetCurrentUid(), goal)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[savings_goals.GoalCreateHandler] failed to create savings goal for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.InfofWithRequestId(c, "[savings_goals.GoalCreateHandler] user \"uid:%d\" has created a new savings goal \"id:%d\" successfully", uid, goal.GoalId)
+
+	return goal.ToSavingsGoalInfoResponse(), nil
+}
+
+// GoalModifyHandler saves an existed savings goal by request parameters for current user
+func (a *SavingsGoalsApi) GoalModifyHandler(c *core.Context) (interface{}, *errs.Error) {
+	var goalModifyReq models.SavingsGoalModifyRequest
+	err := c.ShouldBindJSON(&goalModifyReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[savings_goals.GoalModifyHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	accountIds, err := utils.StringArrayToInt64Array(goalModifyReq.AccountIds)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[savings_goals.GoalModifyHandler] parse account ids failed, because %s", err.Error())
+		return nil, errs.ErrSavingsGoalAccountInvalid
+	}
+
+	uid := c.GetCurrentBookUid()
+	goal, err := a.goals.GetGoalById(c, uid, goalModifyReq.Id)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[savings_goals.GoalModifyHandler] failed to get savings goal \"id:%d\" for user \"uid:%d\", because %s", goalModifyReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	goal.Name = goalModifyReq.Name
+	goal.TargetAmount = goalModifyReq.TargetAmount
+	goal.TargetDate = goalModifyReq.TargetDate
+	goal.Comment = goalModifyReq.Comment
+	goal.SetAccountIds(utils.ToUniqueInt64Slice(accountIds))
+
+	err = a.goals.ModifyGoal(c, c.GetCurrentUid(), goal)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[savings_goals.GoalModifyHandler] failed to update savings goal \"id:%d\" for user \"uid:%d\", because %s", goalModifyReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.InfofWithRequestId(c, "[savings_goals.GoalModifyHandler] user \"uid:%d\" has updated savings goal \"id:%d\" successfully", uid, goalModifyReq.Id)
+
+	return goal.ToSavingsGoalInfoResponse(), nil
+}
+
+// GoalDeleteHandler deletes an existed savings goal by request parameters for current user
+func (a *SavingsGoalsApi) GoalDeleteHandler(c *core.Context) (interface{}, *errs.Error) {
+	var goalDeleteReq models.SavingsGoalDeleteRequest
+	err := c.ShouldBindJSON(&goalDeleteReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[savings_goals.GoalDeleteHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentBookUid()
+	err = a.goals.DeleteGoal(c, uid, c.GetCurrentUid(), goalDeleteReq.Id)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[savings_goals.GoalDeleteHandler] failed to delete savings goal \"id:%d\" for user \"uid:%d\", because %s", goalDeleteReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.InfofWithRequestId(c, "[savings_goals.GoalDeleteHandler] user \"uid:%d\" has deleted savings goal \"id:%d\"", uid, goalDeleteReq.Id)
+	return true, nil
+}
+
+// GoalProgressHandler returns the current amount, required monthly contribution and projected completion of savings goals of current user
+func (a *SavingsGoalsApi) GoalProgressHandler(c *core.Context) (interface{}, *errs.Error) {
+	var goalProgressReq models.SavingsGoalProgressRequest
+	err := c.ShouldBindQuery(&goalProgressReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[savings_goals.GoalProgressHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	utcOffset, err := c.GetClientTimezoneOffset()
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[savings_goals.GoalProgressHandler] cannot get client timezone offset, because %s", err.Error())
+		return nil, errs.ErrClientTimezoneOffsetInvalid
+	}
+
+	uid := c.GetCurrentBookUid()
+	var goals []*models.SavingsGoal
+
+	if goalProgressReq.Id > 0 {
+		goal, err := a.goals.GetGoalById(c, uid, goalProgressReq.Id)
+
+		if err != nil {
+			log.ErrorfWithRequestId(c, "[savings_goals.GoalProgressHandler] failed to get savings goal \"id:%d\" for user \"uid:%d\", because %s", goalProgressReq.Id, uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+
+		goals = []*models.SavingsGoal{goal}
+	} else {
+		goals, err = a.goals.GetAllGoalsByUid(c, uid)
+
+		if err != nil {
+			log.ErrorfWithRequestId(c, "[savings_goals.GoalProgressHandler] failed to get savings goals for user \"uid:%d\", because %s", uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+	}
+
+	accounts, err := a.accounts.GetAllAccountsByUid(c, uid)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[savings_goals.GoalProgressHandler] failed to get accounts for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	accountMap := a.accounts.GetAccountMapByList(accounts)
+	timezone := time.FixedZone("Client Timezone", int(utcOffset)*60)
+	now := time.Now()
+	contributionStartUnixTime := now.In(timezone).AddDate(0, -savingsGoalContributionMonths, 0).Unix()
+	progressResps := make([]*models.SavingsGoalProgressResponse, len(goals))
+
+	for i := 0; i < len(goals); i++ {
+		goal := goals[i]
+		accountIds := make([]int64, 0)
+		currentAmount := int64(0)
+
+		for _, accountId := range goal.GetAccountIds() {
+			account, exists := accountMap[accountId]
+
+			if !exists {
+				continue
+			}
+
+			accountIds = append(accountIds, accountId)
+			currentAmount += account.Balance
+		}
+
+		contributionAmount, err := a.goals.GetContributionAmount(c, uid, accountIds, contributionStartUnixTime, now.Unix())
+
+		if err != nil {
+			log.ErrorfWithRequestId(c, "[savings_goals.GoalProgressHandler] failed to get contributions of savings goal \"id:%d\" for user \"uid:%d\", because %s", goal.GoalId, uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+
+		progress := goal.GetProgress(currentAmount, contributionAmount, savingsGoalContributionMonths, now.Unix(), timezone)
+		progressResps[i] = goal.ToSavingsGoalProgressResponse(progress)
+	}
+
+	return progressResps, nil
+}
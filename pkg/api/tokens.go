@@ -2,6 +2,7 @@ package api
 
 import (
 	"sort"
+	"time"
 
 	"github.com/f97/gofire/pkg/core"
 	"github.com/f97/gofire/pkg/errs"
@@ -60,6 +61,64 @@ func (a *TokensApi) TokenListHandler(c *core.Context) (interface{}, *errs.Error)
 	return tokenResps, nil
 }
 
+// PersonalAccessTokenListHandler returns available personal access token list of current user
+func (a *TokensApi) PersonalAccessTokenListHandler(c *core.Context) (interface{}, *errs.Error) {
+	uid := c.GetCurrentUid()
+	tokens, err := a.tokens.GetAllUnexpiredPersonalAccessTokensByUid(c, uid)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[tokens.PersonalAccessTokenListHandler] failed to get all personal access tokens for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	tokenResps := make(models.TokenInfoResponseSlice, len(tokens))
+
+	for i := 0; i < len(tokens); i++ {
+		tokenResps[i] = a.getPersonalAccessTokenInfoResponse(tokens[i])
+	}
+
+	sort.Sort(tokenResps)
+
+	return tokenResps, nil
+}
+
+// PersonalAccessTokenCreateHandler generates a new personal access token by request parameters for current user
+func (a *TokensApi) PersonalAccessTokenCreateHandler(c *core.Context) (interface{}, *errs.Error) {
+	var tokenCreateReq models.PersonalAccessTokenCreateRequest
+	err := c.ShouldBindJSON(&tokenCreateReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[tokens.PersonalAccessTokenCreateHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[tokens.PersonalAccessTokenCreateHandler] failed to get user \"uid:%d\" info, because %s", uid, err.Error())
+		return nil, errs.ErrUserNotFound
+	}
+
+	scopes := utils.ToUniqueStringSlice(tokenCreateReq.Scopes)
+	expiryDate := time.Duration(tokenCreateReq.ExpiresInDays) * 24 * time.Hour
+	token, tokenRecord, err := a.tokens.CreatePersonalAccessToken(c, user, tokenCreateReq.Name, scopes, expiryDate)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[tokens.PersonalAccessTokenCreateHandler] failed to create personal access token for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrTokenGenerating)
+	}
+
+	log.InfofWithRequestId(c, "[tokens.PersonalAccessTokenCreateHandler] user \"uid:%d\" has created a new personal access token \"id:%s\" successfully", uid, a.tokens.GenerateTokenId(tokenRecord))
+
+	createResp := &models.PersonalAccessTokenCreateResponse{
+		Token:     token,
+		TokenInfo: a.getPersonalAccessTokenInfoResponse(tokenRecord),
+	}
+
+	return createResp, nil
+}
+
 // TokenRevokeCurrentHandler revokes current token of current user
 func (a *TokensApi) TokenRevokeCurrentHandler(c *core.Context) (interface{}, *errs.Error) {
 	_, claims, err := a.tokens.ParseTokenByHeader(c)
@@ -204,3 +263,15 @@ func (a *TokensApi) TokenRefreshHandler(c *core.Context) (interface{}, *errs.Err
 
 	return refreshResp, nil
 }
+
+func (a *TokensApi) getPersonalAccessTokenInfoResponse(token *models.TokenRecord) *models.TokenInfoResponse {
+	return &models.TokenInfoResponse{
+		TokenId:   a.tokens.GenerateTokenId(token),
+		TokenType: token.TokenType,
+		UserAgent: token.UserAgent,
+		Name:      token.Name,
+		Scopes:    token.GetScopes(),
+		CreatedAt: token.CreatedUnixTime,
+		ExpiredAt: token.ExpiredUnixTime,
+	}
+}
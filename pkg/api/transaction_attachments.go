@@ -0,0 +1,219 @@
+package api
+
+import (
+	"io"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/f97/gofire/pkg/core"
+	"github.com/f97/gofire/pkg/errs"
+	"github.com/f97/gofire/pkg/log"
+	"github.com/f97/gofire/pkg/models"
+	"github.com/f97/gofire/pkg/services"
+	"github.com/f97/gofire/pkg/settings"
+	"github.com/f97/gofire/pkg/utils"
+)
+
+const maxAttachmentFileNameLength = 255
+
+var supportedAttachmentContentTypes = map[string]bool{
+	"image/jpeg":      true,
+	"image/png":       true,
+	"image/gif":       true,
+	"image/webp":      true,
+	"application/pdf": true,
+}
+
+// TransactionAttachmentsApi represents transaction attachment api
+type TransactionAttachmentsApi struct {
+	attachments  *services.TransactionAttachmentService
+	transactions *services.TransactionService
+}
+
+// Initialize a transaction attachment api singleton instance
+var (
+	TransactionAttachments = &TransactionAttachmentsApi{
+		attachments:  services.TransactionAttachments,
+		transactions: services.Transactions,
+	}
+)
+
+// AttachmentListHandler returns attachment list of the specified transaction of current user
+func (a *TransactionAttachmentsApi) AttachmentListHandler(c *core.Context) (interface{}, *errs.Error) {
+	var attachmentListReq models.TransactionAttachmentListRequest
+	err := c.ShouldBindQuery(&attachmentListReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[transaction_attachments.AttachmentListHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentBookUid()
+	transaction, err := a.transactions.GetTransactionByTransactionId(c, uid, attachmentListReq.TransactionId)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[transaction_attachments.AttachmentListHandler] failed to get transaction \"id:%d\" for user \"uid:%d\", because %s", attachmentListReq.TransactionId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	transactionId := transaction.TransactionId
+
+	if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+		transactionId = transaction.RelatedId
+	}
+
+	attachments, err := a.attachments.GetAttachmentsByTransactionId(c, uid, transactionId)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[transaction_attachments.AttachmentListHandler] failed to get attachments of transaction \"id:%d\" for user \"uid:%d\", because %s", transactionId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	attachmentResps := make(models.TransactionAttachmentInfoResponseSlice, len(attachments))
+
+	for i := 0; i < len(attachments); i++ {
+		attachmentResps[i] = attachments[i].ToTransactionAttachmentInfoResponse()
+	}
+
+	sort.Sort(attachmentResps)
+
+	return attachmentResps, nil
+}
+
+// AttachmentGetHandler returns the file content of one specific attachment of current user
+func (a *TransactionAttachmentsApi) AttachmentGetHandler(c *core.Context) ([]byte, string, *errs.Error) {
+	var attachmentGetReq models.TransactionAttachmentGetRequest
+	err := c.ShouldBindQuery(&attachmentGetReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[transaction_attachments.AttachmentGetHandler] parse request failed, because %s", err.Error())
+		return nil, "", errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentBookUid()
+	attachment, err := a.attachments.GetAttachmentByAttachmentId(c, uid, attachmentGetReq.Id)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[transaction_attachments.AttachmentGetHandler] failed to get attachment \"id:%d\" for user \"uid:%d\", because %s", attachmentGetReq.Id, uid, err.Error())
+		return nil, "", errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	data, err := a.attachments.GetAttachmentContent(c, attachment)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[transaction_attachments.AttachmentGetHandler] failed to read attachment \"id:%d\" for user \"uid:%d\", because %s", attachmentGetReq.Id, uid, err.Error())
+		return nil, "", errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return data, getAttachmentDownloadFileName(attachment.FileName), nil
+}
+
+// AttachmentUploadHandler saves the uploaded file as a new attachment of the specified transaction for current user
+func (a *TransactionAttachmentsApi) AttachmentUploadHandler(c *core.Context) (interface{}, *errs.Error) {
+	transactionId, err := utils.StringToInt64(c.PostForm("transactionId"))
+
+	if err != nil || transactionId <= 0 {
+		log.WarnfWithRequestId(c, "[transaction_attachments.AttachmentUploadHandler] transaction id is invalid")
+		return nil, errs.ErrTransactionIdInvalid
+	}
+
+	fileHeader, err := c.FormFile("file")
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[transaction_attachments.AttachmentUploadHandler] failed to get attachment file, because %s", err.Error())
+		return nil, errs.ErrTransactionAttachmentFileIsEmpty
+	}
+
+	if fileHeader.Size < 1 {
+		return nil, errs.ErrTransactionAttachmentFileIsEmpty
+	}
+
+	if fileHeader.Size > int64(settings.Container.Current.MaxAttachmentFileSize) {
+		return nil, errs.ErrTransactionAttachmentFileTooLarge
+	}
+
+	file, err := fileHeader.Open()
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[transaction_attachments.AttachmentUploadHandler] failed to open attachment file, because %s", err.Error())
+		return nil, errs.ErrOperationFailed
+	}
+
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[transaction_attachments.AttachmentUploadHandler] failed to read attachment file, because %s", err.Error())
+		return nil, errs.ErrOperationFailed
+	}
+
+	contentType := http.DetectContentType(data)
+
+	if index := strings.Index(contentType, ";"); index >= 0 {
+		contentType = contentType[0:index]
+	}
+
+	if !supportedAttachmentContentTypes[contentType] {
+		log.WarnfWithRequestId(c, "[transaction_attachments.AttachmentUploadHandler] attachment file type \"%s\" is not supported", contentType)
+		return nil, errs.ErrTransactionAttachmentFileTypeInvalid
+	}
+
+	uid := c.GetCurrentBookUid()
+	attachment := &models.TransactionAttachment{
+		Uid:           uid,
+		TransactionId: transactionId,
+		FileName:      utils.SubString(filepath.Base(fileHeader.Filename), 0, maxAttachmentFileNameLength),
+		ContentType:   contentType,
+	}
+
+	err = a.attachments.CreateAttachment(c, c.GetCurrentUid(), attachment, data)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[transaction_attachments.AttachmentUploadHandler] failed to create attachment for transaction \"id:%d\" of user \"uid:%d\", because %s", transactionId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.InfofWithRequestId(c, "[transaction_attachments.AttachmentUploadHandler] user \"uid:%d\" has uploaded a new attachment \"id:%d\" for transaction \"id:%d\" successfully", uid, attachment.AttachmentId, attachment.TransactionId)
+
+	return attachment.ToTransactionAttachmentInfoResponse(), nil
+}
+
+// AttachmentDeleteHandler deletes an existed attachment by request parameters for current user
+func (a *TransactionAttachmentsApi) AttachmentDeleteHandler(c *core.Context) (interface{}, *errs.Error) {
+	var attachmentDeleteReq models.TransactionAttachmentDeleteRequest
+	err := c.ShouldBindJSON(&attachmentDeleteReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[transaction_attachments.AttachmentDeleteHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentBookUid()
+	err = a.attachments.DeleteAttachment(c, uid, c.GetCurrentUid(), attachmentDeleteReq.Id)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[transaction_attachments.AttachmentDeleteHandler] failed to delete attachment \"id:%d\" for user \"uid:%d\", because %s", attachmentDeleteReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.InfofWithRequestId(c, "[transaction_attachments.AttachmentDeleteHandler] user \"uid:%d\" has deleted attachment \"id:%d\"", uid, attachmentDeleteReq.Id)
+	return true, nil
+}
+
+// getAttachmentDownloadFileName returns the file name which only contains safe characters for content disposition header
+func getAttachmentDownloadFileName(fileName string) string {
+	var builder strings.Builder
+
+	for _, ch := range fileName {
+		if (ch >= 'A' && ch <= 'Z') || (ch >= 'a' && ch <= 'z') || (ch >= '0' && ch <= '9') || ch == '.' || ch == '-' || ch == '_' {
+			builder.WriteRune(ch)
+		} else {
+			builder.WriteRune('_')
+		}
+	}
+
+	return builder.String()
+}
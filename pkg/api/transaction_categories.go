@@ -32,7 +32,7 @@ func (a *TransactionCategoriesApi) CategoryListHandler(c *core.Context) (interfa
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
+	uid := c.GetCurrentBookUid()
 	categories, err := a.categories.GetAllCategoriesByUid(c, uid, categoryListReq.Type, categoryListReq.ParentId)
 
 	if err != nil {
@@ -53,7 +53,7 @@ func (a *TransactionCategoriesApi) CategoryGetHandler(c *core.Context) (interfac
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
+	uid := c.GetCurrentBookUid()
 	category, err := a.categories.GetCategoryByCategoryId(c, uid, categoryGetReq.Id)
 
 	if err != nil {
@@ -81,7 +81,7 @@ func (a *TransactionCategoriesApi) CategoryCreateHandler(c *core.Context) (inter
 		return nil, errs.ErrTransactionCategoryTypeInvalid
 	}
 
-	uid := c.GetCurrentUid()
+	uid := c.GetCurrentBookUid()
 
 	if categoryCreateReq.ParentId > 0 {
 		parentCategory, err := a.categories.GetCategoryByCategoryId(c, uid, categoryCreateReq.ParentId)
@@ -117,7 +117,7 @@ func (a *TransactionCategoriesApi) CategoryCreateHandler(c *core.Context) (inter
 
 	category := a.createNewCategoryModel(uid, &categoryCreateReq, maxOrderId+1)
 
-	err = a.categories.CreateCategory(c, category)
+	err = a.categories.CreateCategory(c, c.GetCurrentUid(), category)
 
 	if err != nil {
 		log.ErrorfWithRequestId(c, "[transaction_categories.CategoryCreateHandler] failed to create category \"id:%d\" for user \"uid:%d\", because %s", category.CategoryId, uid, err.Error())
@@ -141,7 +141,7 @@ func (a *TransactionCategoriesApi) CategoryCreateBatchHandler(c *core.Context) (
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
+	uid := c.GetCurrentBookUid()
 
 	categoryTypeMaxOrderMap := make(map[models.TransactionCategoryType]int32)
 	categoriesMap := make(map[*models.TransactionCategory][]*models.TransactionCategory)
@@ -181,7 +181,7 @@ func (a *TransactionCategoriesApi) CategoryCreateBatchHandler(c *core.Context) (
 		totalCount++
 	}
 
-	categories, err := a.categories.CreateCategories(c, uid, categoriesMap)
+	categories, err := a.categories.CreateCategories(c, uid, c.GetCurrentUid(), categoriesMap)
 
 	if err != nil {
 		log.ErrorfWithRequestId(c, "[transaction_categories.CategoryCreateBatchHandler] failed to create categories for user \"uid:%d\", because %s", uid, err.Error())
@@ -203,7 +203,7 @@ func (a *TransactionCategoriesApi) CategoryModifyHandler(c *core.Context) (inter
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
+	uid := c.GetCurrentBookUid()
 	category, err := a.categories.GetCategoryByCategoryId(c, uid, categoryModifyReq.Id)
 
 	if err != nil {
@@ -229,7 +229,7 @@ func (a *TransactionCategoriesApi) CategoryModifyHandler(c *core.Context) (inter
 		return nil, errs.ErrNothingWillBeUpdated
 	}
 
-	err = a.categories.ModifyCategory(c, newCategory)
+	err = a.categories.ModifyCategory(c, c.GetCurrentUid(), newCategory)
 
 	if err != nil {
 		log.ErrorfWithRequestId(c, "[transaction_categories.CategoryModifyHandler] failed to update category \"id:%d\" for user \"uid:%d\", because %s", categoryModifyReq.Id, uid, err.Error())
@@ -256,8 +256,8 @@ func (a *TransactionCategoriesApi) CategoryHideHandler(c *core.Context) (interfa
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
-	err = a.categories.HideCategory(c, uid, []int64{categoryHideReq.Id}, categoryHideReq.Hidden)
+	uid := c.GetCurrentBookUid()
+	err = a.categories.HideCategory(c, uid, c.GetCurrentUid(), []int64{categoryHideReq.Id}, categoryHideReq.Hidden)
 
 	if err != nil {
 		log.ErrorfWithRequestId(c, "[transaction_categories.CategoryHideHandler] failed to hide category \"id:%d\" for user \"uid:%d\", because %s", categoryHideReq.Id, uid, err.Error())
@@ -278,7 +278,7 @@ func (a *TransactionCategoriesApi) CategoryMoveHandler(c *core.Context) (interfa
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
+	uid := c.GetCurrentBookUid()
 	categories := make([]*models.TransactionCategory, len(categoryMoveReq.NewDisplayOrders))
 
 	for i := 0; i < len(categoryMoveReq.NewDisplayOrders); i++ {
@@ -292,7 +292,7 @@ func (a *TransactionCategoriesApi) CategoryMoveHandler(c *core.Context) (interfa
 		categories[i] = category
 	}
 
-	err = a.categories.ModifyCategoryDisplayOrders(c, uid, categories)
+	err = a.categories.ModifyCategoryDisplayOrders(c, uid, c.GetCurrentUid(), categories)
 
 	if err != nil {
 		log.ErrorfWithRequestId(c, "[transaction_categories.CategoryMoveHandler] failed to move categories for user \"uid:%d\", because %s", uid, err.Error())
@@ -313,8 +313,8 @@ func (a *TransactionCategoriesApi) CategoryDeleteHandler(c *core.Context) (inter
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
-	err = a.categories.DeleteCategory(c, uid, categoryDeleteReq.Id)
+	uid := c.GetCurrentBookUid()
+	err = a.categories.DeleteCategory(c, uid, c.GetCurrentUid(), categoryDeleteReq.Id)
 
 	if err != nil {
 		log.ErrorfWithRequestId(c, "[transaction_categories.CategoryDeleteHandler] failed to delete category \"id:%d\" for user \"uid:%d\", because %s", categoryDeleteReq.Id, uid, err.Error())
@@ -0,0 +1,318 @@
+package api
+
+import (
+	"sort"
+	"time"
+
+	"github.com/f97/gofire/pkg/core"
+	"github.com/f97/gofire/pkg/errs"
+	"github.com/f97/gofire/pkg/log"
+	"github.com/f97/gofire/pkg/models"
+	"github.com/f97/gofire/pkg/services"
+	"github.com/f97/gofire/pkg/utils"
+)
+
+// TransactionRulesApi represents transaction rule api
+type TransactionRulesApi struct {
+	rules *services.TransactionRuleService
+	users *services.UserService
+}
+
+// Initialize a transaction rule api singleton instance
+var (
+	TransactionRules = &TransactionRulesApi{
+		rules: services.TransactionRules,
+		users: services.Users,
+	}
+)
+
+// RuleListHandler returns transaction rule list of current user
+func (a *TransactionRulesApi) RuleListHandler(c *core.Context) (interface{}, *errs.Error) {
+	uid := c.GetCurrentBookUid()
+	rules, err := a.rules.GetAllRulesByUid(c, uid)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[transaction_rules.RuleListHandler] failed to get rules for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	ruleResps := make(models.TransactionRuleInfoResponseSlice, len(rules))
+
+	for i := 0; i < len(rules); i++ {
+		ruleResps[i] = rules[i].ToTransactionRuleInfoResponse()
+	}
+
+	sort.Sort(ruleResps)
+
+	return ruleResps, nil
+}
+
+// RuleGetHandler returns one specific transaction rule of current user
+func (a *TransactionRulesApi) RuleGetHandler(c *core.Context) (interface{}, *errs.Error) {
+	var ruleGetReq models.TransactionRuleGetRequest
+	err := c.ShouldBindQuery(&ruleGetReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[transaction_rules.RuleGetHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentBookUid()
+	rule, err := a.rules.GetRuleByRuleId(c, uid, ruleGetReq.Id)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[transaction_rules.RuleGetHandler] failed to get rule \"id:%d\" for user \"uid:%d\", because %s", ruleGetReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	ruleResp := rule.ToTransactionRuleInfoResponse()
+
+	return ruleResp, nil
+}
+
+// RuleCreateHandler saves a new transaction rule by request parameters for current user
+func (a *TransactionRulesApi) RuleCreateHandler(c *core.Context) (interface{}, *errs.Error) {
+	var ruleCreateReq models.TransactionRuleCreateRequest
+	err := c.ShouldBindJSON(&ruleCreateReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[transaction_rules.RuleCreateHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	transactionType, err := a.getTransactionRuleDbType(ruleCreateReq.TransactionType)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[transaction_rules.RuleCreateHandler] transaction type \"%d\" is invalid", ruleCreateReq.TransactionType)
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	tagIds, err := utils.StringArrayToInt64Array(ruleCreateReq.TargetTagIds)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[transaction_rules.RuleCreateHandler] parse tag ids failed, because %s", err.Error())
+		return nil, errs.ErrTransactionTagIdInvalid
+	}
+
+	uid := c.GetCurrentBookUid()
+
+	maxOrderId, err := a.rules.GetMaxDisplayOrder(c, uid)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[transaction_rules.RuleCreateHandler] failed to get max display order for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	rule := &models.TransactionRule{
+		Uid:              uid,
+		Name:             ruleCreateReq.Name,
+		DisplayOrder:     maxOrderId + 1,
+		Disabled:         ruleCreateReq.Disabled,
+		TransactionType:  transactionType,
+		CommentKeyword:   ruleCreateReq.CommentKeyword,
+		MinAmount:        ruleCreateReq.MinAmount,
+		MaxAmount:        ruleCreateReq.MaxAmount,
+		AccountId:        ruleCreateReq.AccountId,
+		TargetCategoryId: ruleCreateReq.TargetCategoryId,
+		TargetComment:    ruleCreateReq.TargetComment,
+	}
+
+	rule.SetTargetTagIds(utils.ToUniqueInt64Slice(tagIds))
+
+	err = a.rules.CreateRule(c, c.GetCurrentUid(), rule)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[transaction_rules.RuleCreateHandler] failed to create rule \"id:%d\" for user \"uid:%d\", because %s", rule.RuleId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.InfofWithRequestId(c, "[transaction_rules.RuleCreateHandler] user \"uid:%d\" has created a new rule \"id:%d\" successfully", uid, rule.RuleId)
+
+	ruleResp := rule.ToTransactionRuleInfoResponse()
+
+	return ruleResp, nil
+}
+
+// RuleModifyHandler saves an existed transaction rule by request parameters for current user
+func (a *TransactionRulesApi) RuleModifyHandler(c *core.Context) (interface{}, *errs.Error) {
+	var ruleModifyReq models.TransactionRuleModifyRequest
+	err := c.ShouldBindJSON(&ruleModifyReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[transaction_rules.RuleModifyHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	transactionType, err := a.getTransactionRuleDbType(ruleModifyReq.TransactionType)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[transaction_rules.RuleModifyHandler] transaction type \"%d\" is invalid", ruleModifyReq.TransactionType)
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	tagIds, err := utils.StringArrayToInt64Array(ruleModifyReq.TargetTagIds)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[transaction_rules.RuleModifyHandler] parse tag ids failed, because %s", err.Error())
+		return nil, errs.ErrTransactionTagIdInvalid
+	}
+
+	uid := c.GetCurrentBookUid()
+	rule, err := a.rules.GetRuleByRuleId(c, uid, ruleModifyReq.Id)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[transaction_rules.RuleModifyHandler] failed to get rule \"id:%d\" for user \"uid:%d\", because %s", ruleModifyReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	newRule := &models.TransactionRule{
+		RuleId:           rule.RuleId,
+		Uid:              uid,
+		Name:             ruleModifyReq.Name,
+		DisplayOrder:     rule.DisplayOrder,
+		Disabled:         ruleModifyReq.Disabled,
+		TransactionType:  transactionType,
+		CommentKeyword:   ruleModifyReq.CommentKeyword,
+		MinAmount:        ruleModifyReq.MinAmount,
+		MaxAmount:        ruleModifyReq.MaxAmount,
+		AccountId:        ruleModifyReq.AccountId,
+		TargetCategoryId: ruleModifyReq.TargetCategoryId,
+		TargetComment:    ruleModifyReq.TargetComment,
+	}
+
+	newRule.SetTargetTagIds(utils.ToUniqueInt64Slice(tagIds))
+
+	if newRule.Name == rule.Name &&
+		newRule.Disabled == rule.Disabled &&
+		newRule.TransactionType == rule.TransactionType &&
+		newRule.CommentKeyword == rule.CommentKeyword &&
+		newRule.MinAmount == rule.MinAmount &&
+		newRule.MaxAmount == rule.MaxAmount &&
+		newRule.AccountId == rule.AccountId &&
+		newRule.TargetCategoryId == rule.TargetCategoryId &&
+		newRule.TargetTagIds == rule.TargetTagIds &&
+		newRule.TargetComment == rule.TargetComment {
+		return nil, errs.ErrNothingWillBeUpdated
+	}
+
+	err = a.rules.ModifyRule(c, c.GetCurrentUid(), newRule)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[transaction_rules.RuleModifyHandler] failed to update rule \"id:%d\" for user \"uid:%d\", because %s", ruleModifyReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.InfofWithRequestId(c, "[transaction_rules.RuleModifyHandler] user \"uid:%d\" has updated rule \"id:%d\" successfully", uid, ruleModifyReq.Id)
+
+	ruleResp := newRule.ToTransactionRuleInfoResponse()
+
+	return ruleResp, nil
+}
+
+// RuleMoveHandler moves display order of existed transaction rules by request parameters for current user
+func (a *TransactionRulesApi) RuleMoveHandler(c *core.Context) (interface{}, *errs.Error) {
+	var ruleMoveReq models.TransactionRuleMoveRequest
+	err := c.ShouldBindJSON(&ruleMoveReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[transaction_rules.RuleMoveHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentBookUid()
+	rules := make([]*models.TransactionRule, len(ruleMoveReq.NewDisplayOrders))
+
+	for i := 0; i < len(ruleMoveReq.NewDisplayOrders); i++ {
+		newDisplayOrder := ruleMoveReq.NewDisplayOrders[i]
+		rule := &models.TransactionRule{
+			Uid:          uid,
+			RuleId:       newDisplayOrder.Id,
+			DisplayOrder: newDisplayOrder.DisplayOrder,
+		}
+
+		rules[i] = rule
+	}
+
+	err = a.rules.ModifyRuleDisplayOrders(c, uid, c.GetCurrentUid(), rules)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[transaction_rules.RuleMoveHandler] failed to move rules for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.InfofWithRequestId(c, "[transaction_rules.RuleMoveHandler] user \"uid:%d\" has moved rules", uid)
+	return true, nil
+}
+
+// RuleDeleteHandler deletes an existed transaction rule by request parameters for current user
+func (a *TransactionRulesApi) RuleDeleteHandler(c *core.Context) (interface{}, *errs.Error) {
+	var ruleDeleteReq models.TransactionRuleDeleteRequest
+	err := c.ShouldBindJSON(&ruleDeleteReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[transaction_rules.RuleDeleteHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentBookUid()
+	err = a.rules.DeleteRule(c, uid, c.GetCurrentUid(), ruleDeleteReq.Id)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[transaction_rules.RuleDeleteHandler] failed to delete rule \"id:%d\" for user \"uid:%d\", because %s", ruleDeleteReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.InfofWithRequestId(c, "[transaction_rules.RuleDeleteHandler] user \"uid:%d\" has deleted rule \"id:%d\"", uid, ruleDeleteReq.Id)
+	return true, nil
+}
+
+// RuleApplyHandler re-applies all enabled transaction rules to the transactions of last months for current user
+func (a *TransactionRulesApi) RuleApplyHandler(c *core.Context) (interface{}, *errs.Error) {
+	var ruleApplyReq models.TransactionRuleApplyRequest
+	err := c.ShouldBindJSON(&ruleApplyReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[transaction_rules.RuleApplyHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentBookUid()
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		if !errs.IsCustomError(err) {
+			log.ErrorfWithRequestId(c, "[transaction_rules.RuleApplyHandler] failed to get user, because %s", err.Error())
+		}
+
+		return nil, errs.ErrUserNotFound
+	}
+
+	startUnixTime := time.Now().AddDate(0, -int(ruleApplyReq.Months), 0).Unix()
+	updatedCount, err := a.rules.ApplyRulesToTransactions(c, user, c.GetCurrentUid(), startUnixTime)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[transaction_rules.RuleApplyHandler] failed to apply rules to transactions of last %d months for user \"uid:%d\", because %s", ruleApplyReq.Months, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.InfofWithRequestId(c, "[transaction_rules.RuleApplyHandler] user \"uid:%d\" has applied rules to %d transactions of last %d months", uid, updatedCount, ruleApplyReq.Months)
+
+	return &models.TransactionRuleApplyResponse{
+		UpdatedCount: updatedCount,
+	}, nil
+}
+
+func (a *TransactionRulesApi) getTransactionRuleDbType(transactionType models.TransactionType) (models.TransactionDbType, error) {
+	switch transactionType {
+	case 0:
+		return 0, nil
+	case models.TRANSACTION_TYPE_INCOME:
+		return models.TRANSACTION_DB_TYPE_INCOME, nil
+	case models.TRANSACTION_TYPE_EXPENSE:
+		return models.TRANSACTION_DB_TYPE_EXPENSE, nil
+	case models.TRANSACTION_TYPE_TRANSFER:
+		return models.TRANSACTION_DB_TYPE_TRANSFER_OUT, nil
+	default:
+		return 0, errs.ErrTransactionRuleTransactionTypeInvalid
+	}
+}
@@ -0,0 +1,283 @@
+package api
+
+import (
+	"sort"
+
+	"github.com/f97/gofire/pkg/core"
+	"github.com/f97/gofire/pkg/errs"
+	"github.com/f97/gofire/pkg/log"
+	"github.com/f97/gofire/pkg/models"
+	"github.com/f97/gofire/pkg/services"
+	"github.com/f97/gofire/pkg/utils"
+)
+
+// TransactionSchedulesApi represents transaction schedule api
+type TransactionSchedulesApi struct {
+	schedules *services.TransactionScheduleService
+}
+
+// Initialize a transaction schedule api singleton instance
+var (
+	TransactionSchedules = &TransactionSchedulesApi{
+		schedules: services.TransactionSchedules,
+	}
+)
+
+// ScheduleListHandler returns transaction schedule list of current user
+func (a *TransactionSchedulesApi) ScheduleListHandler(c *core.Context) (interface{}, *errs.Error) {
+	uid := c.GetCurrentBookUid()
+	schedules, err := a.schedules.GetAllSchedulesByUid(c, uid)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[transaction_schedules.ScheduleListHandler] failed to get schedules for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	scheduleResps := make(models.TransactionScheduleInfoResponseSlice, 0, len(schedules))
+
+	for i := 0; i < len(schedules); i++ {
+		scheduleResp, err := a.getScheduleInfoResponse(schedules[i])
+
+		if err != nil {
+			log.WarnfWithRequestId(c, "[transaction_schedules.ScheduleListHandler] failed to parse schedule \"id:%d\" for user \"uid:%d\", because %s", schedules[i].ScheduleId, uid, err.Error())
+			continue
+		}
+
+		scheduleResps = append(scheduleResps, scheduleResp)
+	}
+
+	sort.Sort(scheduleResps)
+
+	return scheduleResps, nil
+}
+
+// ScheduleGetHandler returns one specific transaction schedule of current user
+func (a *TransactionSchedulesApi) ScheduleGetHandler(c *core.Context) (interface{}, *errs.Error) {
+	var scheduleGetReq models.TransactionScheduleGetRequest
+	err := c.ShouldBindQuery(&scheduleGetReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[transaction_schedules.ScheduleGetHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentBookUid()
+	schedule, err := a.schedules.GetScheduleByScheduleId(c, uid, scheduleGetReq.Id)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[transaction_schedules.ScheduleGetHandler] failed to get schedule \"id:%d\" for user \"uid:%d\", because %s", scheduleGetReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	scheduleResp, err := a.getScheduleInfoResponse(schedule)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[transaction_schedules.ScheduleGetHandler] failed to parse schedule \"id:%d\" for user \"uid:%d\", because %s", scheduleGetReq.Id, uid, err.Error())
+		return nil, errs.ErrOperationFailed
+	}
+
+	return scheduleResp, nil
+}
+
+// ScheduleCreateHandler saves a new transaction schedule by request parameters for current user
+func (a *TransactionSchedulesApi) ScheduleCreateHandler(c *core.Context) (interface{}, *errs.Error) {
+	var scheduleCreateReq models.TransactionScheduleCreateRequest
+	err := c.ShouldBindJSON(&scheduleCreateReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[transaction_schedules.ScheduleCreateHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	tagIds, err := utils.StringArrayToInt64Array(scheduleCreateReq.TagIds)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[transaction_schedules.ScheduleCreateHandler] parse tag ids failed, because %s", err.Error())
+		return nil, errs.ErrTransactionTagIdInvalid
+	}
+
+	if scheduleCreateReq.Type != models.TRANSACTION_TYPE_INCOME && scheduleCreateReq.Type != models.TRANSACTION_TYPE_EXPENSE && scheduleCreateReq.Type != models.TRANSACTION_TYPE_TRANSFER {
+		log.WarnfWithRequestId(c, "[transaction_schedules.ScheduleCreateHandler] transaction schedule type is invalid")
+		return nil, errs.ErrTransactionScheduleTypeInvalid
+	}
+
+	if scheduleCreateReq.Type != models.TRANSACTION_TYPE_TRANSFER && scheduleCreateReq.DestinationAccountId != 0 {
+		log.WarnfWithRequestId(c, "[transaction_schedules.ScheduleCreateHandler] non-transfer transaction destination account cannot be set")
+		return nil, errs.ErrTransactionDestinationAccountCannotBeSet
+	} else if scheduleCreateReq.Type == models.TRANSACTION_TYPE_TRANSFER && scheduleCreateReq.SourceAccountId == scheduleCreateReq.DestinationAccountId {
+		log.WarnfWithRequestId(c, "[transaction_schedules.ScheduleCreateHandler] transfer transaction source account must not be destination account")
+		return nil, errs.ErrTransactionSourceAndDestinationIdCannotBeEqual
+	}
+
+	if scheduleCreateReq.Type != models.TRANSACTION_TYPE_TRANSFER && scheduleCreateReq.DestinationAmount != 0 {
+		log.WarnfWithRequestId(c, "[transaction_schedules.ScheduleCreateHandler] non-transfer transaction destination amount cannot be set")
+		return nil, errs.ErrTransactionDestinationAmountCannotBeSet
+	}
+
+	uid := c.GetCurrentBookUid()
+	schedule := a.createNewScheduleModel(uid, &scheduleCreateReq, c.ClientIP())
+	schedule.SetTagIds(tagIds)
+
+	err = a.schedules.CreateSchedule(c, c.GetCurrentUid(), schedule)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[transaction_schedules.ScheduleCreateHandler] failed to create schedule \"id:%d\" for user \"uid:%d\", because %s", schedule.ScheduleId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.InfofWithRequestId(c, "[transaction_schedules.ScheduleCreateHandler] user \"uid:%d\" has created a new schedule \"id:%d\" successfully", uid, schedule.ScheduleId)
+
+	scheduleResp, err := a.getScheduleInfoResponse(schedule)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[transaction_schedules.ScheduleCreateHandler] failed to parse schedule \"id:%d\" for user \"uid:%d\", because %s", schedule.ScheduleId, uid, err.Error())
+		return nil, errs.ErrOperationFailed
+	}
+
+	return scheduleResp, nil
+}
+
+// ScheduleModifyHandler saves an existed transaction schedule by request parameters for current user
+func (a *TransactionSchedulesApi) ScheduleModifyHandler(c *core.Context) (interface{}, *errs.Error) {
+	var scheduleModifyReq models.TransactionScheduleModifyRequest
+	err := c.ShouldBindJSON(&scheduleModifyReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[transaction_schedules.ScheduleModifyHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	tagIds, err := utils.StringArrayToInt64Array(scheduleModifyReq.TagIds)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[transaction_schedules.ScheduleModifyHandler] parse tag ids failed, because %s", err.Error())
+		return nil, errs.ErrTransactionTagIdInvalid
+	}
+
+	uid := c.GetCurrentBookUid()
+	schedule, err := a.schedules.GetScheduleByScheduleId(c, uid, scheduleModifyReq.Id)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[transaction_schedules.ScheduleModifyHandler] failed to get schedule \"id:%d\" for user \"uid:%d\", because %s", scheduleModifyReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	if schedule.Type != models.TRANSACTION_DB_TYPE_TRANSFER_OUT && scheduleModifyReq.DestinationAccountId != 0 {
+		log.WarnfWithRequestId(c, "[transaction_schedules.ScheduleModifyHandler] non-transfer transaction destination account cannot be set")
+		return nil, errs.ErrTransactionDestinationAccountCannotBeSet
+	} else if schedule.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT && scheduleModifyReq.SourceAccountId == scheduleModifyReq.DestinationAccountId {
+		log.WarnfWithRequestId(c, "[transaction_schedules.ScheduleModifyHandler] transfer transaction source account must not be destination account")
+		return nil, errs.ErrTransactionSourceAndDestinationIdCannotBeEqual
+	}
+
+	if schedule.Type != models.TRANSACTION_DB_TYPE_TRANSFER_OUT && scheduleModifyReq.DestinationAmount != 0 {
+		log.WarnfWithRequestId(c, "[transaction_schedules.ScheduleModifyHandler] non-transfer transaction destination amount cannot be set")
+		return nil, errs.ErrTransactionDestinationAmountCannotBeSet
+	}
+
+	schedule.Disabled = scheduleModifyReq.Disabled
+	schedule.CategoryId = scheduleModifyReq.CategoryId
+	schedule.AccountId = scheduleModifyReq.SourceAccountId
+	schedule.Amount = scheduleModifyReq.SourceAmount
+	schedule.RelatedAccountId = scheduleModifyReq.DestinationAccountId
+	schedule.RelatedAccountAmount = scheduleModifyReq.DestinationAmount
+	schedule.HideAmount = scheduleModifyReq.HideAmount
+	schedule.Comment = scheduleModifyReq.Comment
+	schedule.Frequency = scheduleModifyReq.Frequency
+	schedule.DayOfMonth = scheduleModifyReq.DayOfMonth
+	schedule.StartTime = scheduleModifyReq.StartTime
+	schedule.EndTime = scheduleModifyReq.EndTime
+	schedule.TimezoneUtcOffset = scheduleModifyReq.UtcOffset
+	schedule.SetTagIds(tagIds)
+
+	err = a.schedules.ModifySchedule(c, c.GetCurrentUid(), schedule)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[transaction_schedules.ScheduleModifyHandler] failed to update schedule \"id:%d\" for user \"uid:%d\", because %s", scheduleModifyReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.InfofWithRequestId(c, "[transaction_schedules.ScheduleModifyHandler] user \"uid:%d\" has updated schedule \"id:%d\" successfully", uid, scheduleModifyReq.Id)
+
+	scheduleResp, err := a.getScheduleInfoResponse(schedule)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[transaction_schedules.ScheduleModifyHandler] failed to parse schedule \"id:%d\" for user \"uid:%d\", because %s", scheduleModifyReq.Id, uid, err.Error())
+		return nil, errs.ErrOperationFailed
+	}
+
+	return scheduleResp, nil
+}
+
+// ScheduleDeleteHandler deletes an existed transaction schedule by request parameters for current user
+func (a *TransactionSchedulesApi) ScheduleDeleteHandler(c *core.Context) (interface{}, *errs.Error) {
+	var scheduleDeleteReq models.TransactionScheduleDeleteRequest
+	err := c.ShouldBindJSON(&scheduleDeleteReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[transaction_schedules.ScheduleDeleteHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentBookUid()
+	err = a.schedules.DeleteSchedule(c, uid, c.GetCurrentUid(), scheduleDeleteReq.Id)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[transaction_schedules.ScheduleDeleteHandler] failed to delete schedule \"id:%d\" for user \"uid:%d\", because %s", scheduleDeleteReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.InfofWithRequestId(c, "[transaction_schedules.ScheduleDeleteHandler] user \"uid:%d\" has deleted schedule \"id:%d\"", uid, scheduleDeleteReq.Id)
+	return true, nil
+}
+
+func (a *TransactionSchedulesApi) getScheduleInfoResponse(schedule *models.TransactionSchedule) (*models.TransactionScheduleInfoResponse, error) {
+	tagIds, err := schedule.GetTagIds()
+
+	if err != nil {
+		return nil, err
+	}
+
+	scheduleResp := schedule.ToTransactionScheduleInfoResponse(tagIds)
+
+	if scheduleResp == nil {
+		return nil, errs.ErrTransactionScheduleTypeInvalid
+	}
+
+	return scheduleResp, nil
+}
+
+func (a *TransactionSchedulesApi) createNewScheduleModel(uid int64, scheduleCreateReq *models.TransactionScheduleCreateRequest, clientIp string) *models.TransactionSchedule {
+	var transactionDbType models.TransactionDbType
+
+	if scheduleCreateReq.Type == models.TRANSACTION_TYPE_EXPENSE {
+		transactionDbType = models.TRANSACTION_DB_TYPE_EXPENSE
+	} else if scheduleCreateReq.Type == models.TRANSACTION_TYPE_INCOME {
+		transactionDbType = models.TRANSACTION_DB_TYPE_INCOME
+	} else if scheduleCreateReq.Type == models.TRANSACTION_TYPE_TRANSFER {
+		transactionDbType = models.TRANSACTION_DB_TYPE_TRANSFER_OUT
+	}
+
+	schedule := &models.TransactionSchedule{
+		Uid:               uid,
+		Type:              transactionDbType,
+		CategoryId:        scheduleCreateReq.CategoryId,
+		AccountId:         scheduleCreateReq.SourceAccountId,
+		Amount:            scheduleCreateReq.SourceAmount,
+		HideAmount:        scheduleCreateReq.HideAmount,
+		Comment:           scheduleCreateReq.Comment,
+		Frequency:         scheduleCreateReq.Frequency,
+		DayOfMonth:        scheduleCreateReq.DayOfMonth,
+		StartTime:         scheduleCreateReq.StartTime,
+		EndTime:           scheduleCreateReq.EndTime,
+		TimezoneUtcOffset: scheduleCreateReq.UtcOffset,
+		CreatedIp:         clientIp,
+	}
+
+	if scheduleCreateReq.Type == models.TRANSACTION_TYPE_TRANSFER {
+		schedule.RelatedAccountId = scheduleCreateReq.DestinationAccountId
+		schedule.RelatedAccountAmount = scheduleCreateReq.DestinationAmount
+	}
+
+	return schedule
+}
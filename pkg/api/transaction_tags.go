@@ -24,7 +24,7 @@ var (
 
 // TagListHandler returns transaction tag list of current user
 func (a *TransactionTagsApi) TagListHandler(c *core.Context) (interface{}, *errs.Error) {
-	uid := c.GetCurrentUid()
+	uid := c.GetCurrentBookUid()
 	tags, err := a.tags.GetAllTagsByUid(c, uid)
 
 	if err != nil {
@@ -53,7 +53,7 @@ func (a *TransactionTagsApi) TagGetHandler(c *core.Context) (interface{}, *errs.
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
+	uid := c.GetCurrentBookUid()
 	tag, err := a.tags.GetTagByTagId(c, uid, tagGetReq.Id)
 
 	if err != nil {
@@ -76,7 +76,7 @@ func (a *TransactionTagsApi) TagCreateHandler(c *core.Context) (interface{}, *er
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
+	uid := c.GetCurrentBookUid()
 
 	maxOrderId, err := a.tags.GetMaxDisplayOrder(c, uid)
 
@@ -87,7 +87,7 @@ func (a *TransactionTagsApi) TagCreateHandler(c *core.Context) (interface{}, *er
 
 	tag := a.createNewTagModel(uid, &tagCreateReq, maxOrderId+1)
 
-	err = a.tags.CreateTag(c, tag)
+	err = a.tags.CreateTag(c, c.GetCurrentUid(), tag)
 
 	if err != nil {
 		log.ErrorfWithRequestId(c, "[transaction_tags.TagCreateHandler] failed to create tag \"id:%d\" for user \"uid:%d\", because %s", tag.TagId, uid, err.Error())
@@ -111,7 +111,7 @@ func (a *TransactionTagsApi) TagModifyHandler(c *core.Context) (interface{}, *er
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
+	uid := c.GetCurrentBookUid()
 	tag, err := a.tags.GetTagByTagId(c, uid, tagModifyReq.Id)
 
 	if err != nil {
@@ -129,7 +129,7 @@ func (a *TransactionTagsApi) TagModifyHandler(c *core.Context) (interface{}, *er
 		return nil, errs.ErrNothingWillBeUpdated
 	}
 
-	err = a.tags.ModifyTag(c, newTag)
+	err = a.tags.ModifyTag(c, c.GetCurrentUid(), newTag)
 
 	if err != nil {
 		log.ErrorfWithRequestId(c, "[transaction_tags.TagModifyHandler] failed to update tag \"id:%d\" for user \"uid:%d\", because %s", tagModifyReq.Id, uid, err.Error())
@@ -154,8 +154,8 @@ func (a *TransactionTagsApi) TagHideHandler(c *core.Context) (interface{}, *errs
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
-	err = a.tags.HideTag(c, uid, []int64{tagHideReq.Id}, tagHideReq.Hidden)
+	uid := c.GetCurrentBookUid()
+	err = a.tags.HideTag(c, uid, c.GetCurrentUid(), []int64{tagHideReq.Id}, tagHideReq.Hidden)
 
 	if err != nil {
 		log.ErrorfWithRequestId(c, "[transaction_tags.CategoryHideHandler] failed to hide tag \"id:%d\" for user \"uid:%d\", because %s", tagHideReq.Id, uid, err.Error())
@@ -176,7 +176,7 @@ func (a *TransactionTagsApi) TagMoveHandler(c *core.Context) (interface{}, *errs
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
+	uid := c.GetCurrentBookUid()
 	tags := make([]*models.TransactionTag, len(tagMoveReq.NewDisplayOrders))
 
 	for i := 0; i < len(tagMoveReq.NewDisplayOrders); i++ {
@@ -190,7 +190,7 @@ func (a *TransactionTagsApi) TagMoveHandler(c *core.Context) (interface{}, *errs
 		tags[i] = tag
 	}
 
-	err = a.tags.ModifyTagDisplayOrders(c, uid, tags)
+	err = a.tags.ModifyTagDisplayOrders(c, uid, c.GetCurrentUid(), tags)
 
 	if err != nil {
 		log.ErrorfWithRequestId(c, "[transaction_tags.CategoryMoveHandler] failed to move tags for user \"uid:%d\", because %s", uid, err.Error())
@@ -211,8 +211,8 @@ func (a *TransactionTagsApi) TagDeleteHandler(c *core.Context) (interface{}, *er
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
-	err = a.tags.DeleteTag(c, uid, tagDeleteReq.Id)
+	uid := c.GetCurrentBookUid()
+	err = a.tags.DeleteTag(c, uid, c.GetCurrentUid(), tagDeleteReq.Id)
 
 	if err != nil {
 		log.ErrorfWithRequestId(c, "[transaction_tags.TagDeleteHandler] failed to delete tag \"id:%d\" for user \"uid:%d\", because %s", tagDeleteReq.Id, uid, err.Error())
@@ -1,8 +1,10 @@
 package api
 
 import (
+	"math"
 	"sort"
 	"strings"
+	"time"
 
 	orderedmap "github.com/wk8/go-ordered-map/v2"
 
@@ -11,6 +13,7 @@ import (
 	"github.com/f97/gofire/pkg/log"
 	"github.com/f97/gofire/pkg/models"
 	"github.com/f97/gofire/pkg/services"
+	"github.com/f97/gofire/pkg/settings"
 	"github.com/f97/gofire/pkg/utils"
 )
 
@@ -23,6 +26,8 @@ type TransactionsApi struct {
 	transactionTags       *services.TransactionTagService
 	accounts              *services.AccountService
 	users                 *services.UserService
+	exchangeRates         *services.ExchangeRateService
+	revisions             *services.DataRevisionService
 }
 
 // Initialize a transaction api singleton instance
@@ -33,6 +38,8 @@ var (
 		transactionTags:       services.TransactionTags,
 		accounts:              services.Accounts,
 		users:                 services.Users,
+		exchangeRates:         services.ExchangeRates,
+		revisions:             services.DataRevisions,
 	}
 )
 
@@ -46,7 +53,7 @@ func (a *TransactionsApi) TransactionCountHandler(c *core.Context) (interface{},
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
+	uid := c.GetCurrentBookUid()
 
 	allAccountIds, err := a.getAccountOrSubAccountIds(c, transactionCountReq.AccountId, uid)
 
@@ -93,7 +100,7 @@ func (a *TransactionsApi) TransactionListHandler(c *core.Context) (interface{},
 		return nil, errs.ErrClientTimezoneOffsetInvalid
 	}
 
-	uid := c.GetCurrentUid()
+	uid := c.GetCurrentBookUid()
 	user, err := a.users.GetUserById(c, uid)
 
 	if err != nil {
@@ -184,7 +191,7 @@ func (a *TransactionsApi) TransactionMonthListHandler(c *core.Context) (interfac
 		return nil, errs.ErrClientTimezoneOffsetInvalid
 	}
 
-	uid := c.GetCurrentUid()
+	uid := c.GetCurrentBookUid()
 	user, err := a.users.GetUserById(c, uid)
 
 	if err != nil {
@@ -241,7 +248,7 @@ func (a *TransactionsApi) TransactionStatisticsHandler(c *core.Context) (interfa
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
+	uid := c.GetCurrentBookUid()
 	totalAmounts, err := a.transactions.GetAccountsAndCategoriesTotalIncomeAndExpense(c, uid, statisticReq.StartTime, statisticReq.EndTime)
 
 	if err != nil {
@@ -295,7 +302,16 @@ func (a *TransactionsApi) TransactionAmountsHandler(c *core.Context) (interface{
 		return nil, errs.ErrQueryItemsTooMuch
 	}
 
-	uid := c.GetCurrentUid()
+	uid := c.GetCurrentBookUid()
+	convertToCurrency, err := a.getConvertToCurrency(c, uid, transactionAmountsReq.ConvertTo)
+
+	if err != nil {
+		if !errs.IsCustomError(err) {
+			log.ErrorfWithRequestId(c, "[transactions.TransactionAmountsHandler] failed to get user, because %s", err.Error())
+		}
+
+		return nil, errs.ErrUserNotFound
+	}
 
 	accounts, err := a.accounts.GetAllAccountsByUid(c, uid)
 	accountMap := a.accounts.GetAccountMapByList(accounts)
@@ -306,6 +322,7 @@ func (a *TransactionsApi) TransactionAmountsHandler(c *core.Context) (interface{
 	}
 
 	amountsResp := orderedmap.New[string, *models.TransactionAmountsResponseItem]()
+	allExchangeRates := make(map[string]*models.ExchangeRateHistoryResponse)
 
 	for i := 0; i < len(requestItems); i++ {
 		requestItem := requestItems[i]
@@ -372,9 +389,10 @@ func (a *TransactionsApi) TransactionAmountsHandler(c *core.Context) (interface{
 		sort.Sort(allTotalAmounts)
 
 		amountsResp.Set(requestItem.Name, &models.TransactionAmountsResponseItem{
-			StartTime: requestItem.StartTime,
-			EndTime:   requestItem.EndTime,
-			Amounts:   allTotalAmounts,
+			StartTime:   requestItem.StartTime,
+			EndTime:     requestItem.EndTime,
+			Amounts:     allTotalAmounts,
+			TotalAmount: a.getConvertedTotalAmount(c, allTotalAmounts, convertToCurrency, requestItem.EndTime, allExchangeRates),
 		})
 	}
 
@@ -405,7 +423,16 @@ func (a *TransactionsApi) TransactionMonthAmountsHandler(c *core.Context) (inter
 		return nil, errs.ErrParameterInvalid
 	}
 
-	uid := c.GetCurrentUid()
+	uid := c.GetCurrentBookUid()
+	convertToCurrency, err := a.getConvertToCurrency(c, uid, transactionAmountsReq.ConvertTo)
+
+	if err != nil {
+		if !errs.IsCustomError(err) {
+			log.ErrorfWithRequestId(c, "[transactions.TransactionMonthAmountsHandler] failed to get user, because %s", err.Error())
+		}
+
+		return nil, errs.ErrUserNotFound
+	}
 
 	accounts, err := a.accounts.GetAllAccountsByUid(c, uid)
 	accountMap := a.accounts.GetAccountMapByList(accounts)
@@ -458,6 +485,7 @@ func (a *TransactionsApi) TransactionMonthAmountsHandler(c *core.Context) (inter
 	}
 
 	amountsResp := make(models.TransactionMonthAmountsResponseItemSlice, 0)
+	allExchangeRates := make(map[string]*models.ExchangeRateHistoryResponse)
 
 	for yearMonth, monthTotalAmounts := range amountsMap {
 		yearMonthItems := strings.Split(yearMonth, "-")
@@ -483,10 +511,13 @@ func (a *TransactionsApi) TransactionMonthAmountsHandler(c *core.Context) (inter
 
 		sort.Sort(amounts)
 
+		monthEndUnixTime := time.Date(int(year), time.Month(month)+1, 1, 0, 0, 0, 0, time.UTC).Unix() - int64(utcOffset)*60 - 1
+
 		amountsResp = append(amountsResp, &models.TransactionMonthAmountsResponseItem{
-			Year:    year,
-			Month:   month,
-			Amounts: amounts,
+			Year:        year,
+			Month:       month,
+			Amounts:     amounts,
+			TotalAmount: a.getConvertedTotalAmount(c, amounts, convertToCurrency, monthEndUnixTime, allExchangeRates),
 		})
 	}
 
@@ -512,7 +543,7 @@ func (a *TransactionsApi) TransactionGetHandler(c *core.Context) (interface{}, *
 		return nil, errs.ErrClientTimezoneOffsetInvalid
 	}
 
-	uid := c.GetCurrentUid()
+	uid := c.GetCurrentBookUid()
 	user, err := a.users.GetUserById(c, uid)
 
 	if err != nil {
@@ -563,6 +594,13 @@ func (a *TransactionsApi) TransactionGetHandler(c *core.Context) (interface{}, *
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
+	allTransactionSplits, err := a.transactions.GetAllSplitsOfTransactions(c, uid, []int64{transaction.TransactionId})
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[transactions.TransactionGetHandler] failed to get transactions splits for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
 	var category *models.TransactionCategory
 	var tagMap map[int64]*models.TransactionTag
 
@@ -587,6 +625,7 @@ func (a *TransactionsApi) TransactionGetHandler(c *core.Context) (interface{}, *
 	transactionEditable := transaction.IsEditable(user, utcOffset, accountMap[transaction.AccountId], accountMap[transaction.RelatedAccountId])
 	transactionTagIds := allTransactionTagIds[transaction.TransactionId]
 	transactionResp := transaction.ToTransactionInfoResponse(transactionTagIds, transactionEditable)
+	transactionResp.Splits = a.getTransactionSplitInfoResponses(allTransactionSplits[transaction.TransactionId])
 
 	if !transactionGetReq.TrimAccount {
 		if sourceAccount := accountMap[transaction.AccountId]; sourceAccount != nil {
@@ -628,6 +667,13 @@ func (a *TransactionsApi) TransactionCreateHandler(c *core.Context) (interface{}
 		return nil, errs.ErrTransactionTagIdInvalid
 	}
 
+	splits, err := a.createNewTransactionSplitModels(transactionCreateReq.Splits)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[transactions.TransactionCreateHandler] parse split tag ids failed, because %s", err.Error())
+		return nil, errs.ErrTransactionTagIdInvalid
+	}
+
 	if transactionCreateReq.Type < models.TRANSACTION_TYPE_MODIFY_BALANCE || transactionCreateReq.Type > models.TRANSACTION_TYPE_TRANSFER {
 		log.WarnfWithRequestId(c, "[transactions.TransactionCreateHandler] transaction type is invalid")
 		return nil, errs.ErrTransactionTypeInvalid
@@ -651,7 +697,7 @@ func (a *TransactionsApi) TransactionCreateHandler(c *core.Context) (interface{}
 		return nil, errs.ErrTransactionDestinationAmountCannotBeSet
 	}
 
-	uid := c.GetCurrentUid()
+	uid := c.GetCurrentBookUid()
 	user, err := a.users.GetUserById(c, uid)
 
 	if err != nil {
@@ -669,7 +715,7 @@ func (a *TransactionsApi) TransactionCreateHandler(c *core.Context) (interface{}
 		return nil, errs.ErrCannotCreateTransactionWithThisTransactionTime
 	}
 
-	err = a.transactions.CreateTransaction(c, transaction, tagIds)
+	err = a.transactions.CreateTransaction(c, c.GetCurrentUid(), transaction, tagIds, splits)
 
 	if err != nil {
 		log.ErrorfWithRequestId(c, "[transactions.TransactionCreateHandler] failed to create transaction \"id:%d\" for user \"uid:%d\", because %s", transaction.TransactionId, uid, err.Error())
@@ -679,6 +725,7 @@ func (a *TransactionsApi) TransactionCreateHandler(c *core.Context) (interface{}
 	log.InfofWithRequestId(c, "[transactions.TransactionCreateHandler] user \"uid:%d\" has created a new transaction \"id:%d\" successfully", uid, transaction.TransactionId)
 
 	transactionResp := transaction.ToTransactionInfoResponse(tagIds, transactionEditable)
+	transactionResp.Splits = a.getTransactionSplitInfoResponses(splits)
 
 	return transactionResp, nil
 }
@@ -700,7 +747,14 @@ func (a *TransactionsApi) TransactionModifyHandler(c *core.Context) (interface{}
 		return nil, errs.ErrTransactionTagIdInvalid
 	}
 
-	uid := c.GetCurrentUid()
+	splits, err := a.createNewTransactionSplitModels(transactionModifyReq.Splits)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[transactions.TransactionModifyHandler] parse split tag ids failed, because %s", err.Error())
+		return nil, errs.ErrTransactionTagIdInvalid
+	}
+
+	uid := c.GetCurrentBookUid()
 	user, err := a.users.GetUserById(c, uid)
 
 	if err != nil {
@@ -736,6 +790,15 @@ func (a *TransactionsApi) TransactionModifyHandler(c *core.Context) (interface{}
 		transactionTagIds = make([]int64, 0, 0)
 	}
 
+	allTransactionSplits, err := a.transactions.GetAllSplitsOfTransactions(c, uid, []int64{transaction.TransactionId})
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[transactions.TransactionModifyHandler] failed to get transactions splits for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	transactionSplits := allTransactionSplits[transaction.TransactionId]
+
 	newTransaction := &models.Transaction{
 		TransactionId:     transaction.TransactionId,
 		Uid:               uid,
@@ -769,7 +832,8 @@ func (a *TransactionsApi) TransactionModifyHandler(c *core.Context) (interface{}
 		newTransaction.Comment == transaction.Comment &&
 		newTransaction.GeoLongitude == transaction.GeoLongitude &&
 		newTransaction.GeoLatitude == transaction.GeoLatitude &&
-		utils.Int64SliceEquals(tagIds, transactionTagIds) {
+		utils.Int64SliceEquals(tagIds, transactionTagIds) &&
+		(splits == nil || a.isTransactionSplitsEqual(splits, transactionSplits)) {
 		return nil, errs.ErrNothingWillBeUpdated
 	}
 
@@ -788,7 +852,7 @@ func (a *TransactionsApi) TransactionModifyHandler(c *core.Context) (interface{}
 		return nil, errs.ErrCannotModifyTransactionWithThisTransactionTime
 	}
 
-	err = a.transactions.ModifyTransaction(c, newTransaction, addTransactionTagIds, removeTransactionTagIds)
+	err = a.transactions.ModifyTransaction(c, c.GetCurrentUid(), newTransaction, addTransactionTagIds, removeTransactionTagIds, splits)
 
 	if err != nil {
 		log.ErrorfWithRequestId(c, "[transactions.TransactionModifyHandler] failed to update transaction \"id:%d\" for user \"uid:%d\", because %s", transactionModifyReq.Id, uid, err.Error())
@@ -800,6 +864,12 @@ func (a *TransactionsApi) TransactionModifyHandler(c *core.Context) (interface{}
 	newTransaction.Type = transaction.Type
 	newTransactionResp := newTransaction.ToTransactionInfoResponse(tagIds, transactionEditable)
 
+	if splits != nil {
+		newTransactionResp.Splits = a.getTransactionSplitInfoResponses(splits)
+	} else {
+		newTransactionResp.Splits = a.getTransactionSplitInfoResponses(transactionSplits)
+	}
+
 	return newTransactionResp, nil
 }
 
@@ -820,7 +890,7 @@ func (a *TransactionsApi) TransactionDeleteHandler(c *core.Context) (interface{}
 		return nil, errs.ErrClientTimezoneOffsetInvalid
 	}
 
-	uid := c.GetCurrentUid()
+	uid := c.GetCurrentBookUid()
 	user, err := a.users.GetUserById(c, uid)
 
 	if err != nil {
@@ -849,7 +919,7 @@ func (a *TransactionsApi) TransactionDeleteHandler(c *core.Context) (interface{}
 		return nil, errs.ErrCannotDeleteTransactionWithThisTransactionTime
 	}
 
-	err = a.transactions.DeleteTransaction(c, uid, transactionDeleteReq.Id)
+	err = a.transactions.DeleteTransaction(c, uid, c.GetCurrentUid(), transactionDeleteReq.Id)
 
 	if err != nil {
 		log.ErrorfWithRequestId(c, "[transactions.TransactionDeleteHandler] failed to delete transaction \"id:%d\" for user \"uid:%d\", because %s", transactionDeleteReq.Id, uid, err.Error())
@@ -860,6 +930,177 @@ func (a *TransactionsApi) TransactionDeleteHandler(c *core.Context) (interface{}
 	return true, nil
 }
 
+// TransactionHistoryHandler returns the revision history of one specific transaction of current user, the transaction may have been deleted
+func (a *TransactionsApi) TransactionHistoryHandler(c *core.Context) (interface{}, *errs.Error) {
+	var transactionHistoryReq models.TransactionHistoryRequest
+	err := c.ShouldBindQuery(&transactionHistoryReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[transactions.TransactionHistoryHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentBookUid()
+	revisions, err := a.revisions.GetTransactionRevisions(c, uid, transactionHistoryReq.Id)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[transactions.TransactionHistoryHandler] failed to get revisions of transaction \"id:%d\" for user \"uid:%d\", because %s", transactionHistoryReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	revisionResps := make(models.DataRevisionInfoResponseSlice, len(revisions))
+
+	for i := 0; i < len(revisions); i++ {
+		revisionResps[i] = revisions[i].ToDataRevisionInfoResponse()
+	}
+
+	sort.Sort(revisionResps)
+
+	return revisionResps, nil
+}
+
+// TransactionTrashListHandler returns deleted transaction list in trash bin of current user
+func (a *TransactionsApi) TransactionTrashListHandler(c *core.Context) (interface{}, *errs.Error) {
+	var transactionTrashListReq models.TransactionTrashListRequest
+	err := c.ShouldBindQuery(&transactionTrashListReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[transactions.TransactionTrashListHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentBookUid()
+	minDeletedUnixTime := a.getTrashMinDeletedUnixTime()
+
+	totalCount, err := a.transactions.GetTrashTransactionCount(c, uid, minDeletedUnixTime)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[transactions.TransactionTrashListHandler] failed to get deleted transaction count for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	transactions, err := a.transactions.GetTrashTransactionsByPage(c, uid, minDeletedUnixTime, transactionTrashListReq.Page, transactionTrashListReq.Count)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[transactions.TransactionTrashListHandler] failed to get deleted transactions for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	retentionDays := settings.Container.Current.TrashRetentionDays
+	transactionResps := make([]*models.TransactionTrashInfoResponse, len(transactions))
+
+	for i := 0; i < len(transactions); i++ {
+		transaction := transactions[i]
+		transactionResps[i] = &models.TransactionTrashInfoResponse{
+			TransactionInfoResponse: transaction.ToTransactionInfoResponse(nil, false),
+			DeletedTime:             transaction.DeletedUnixTime,
+		}
+
+		if retentionDays > 0 {
+			transactionResps[i].PurgeTime = transaction.DeletedUnixTime + int64(retentionDays)*24*60*60
+		}
+	}
+
+	return &models.TransactionTrashInfoPageWrapperResponse{
+		Items:      transactionResps,
+		TotalCount: totalCount,
+	}, nil
+}
+
+// TransactionTrashRestoreHandler restores a deleted transaction in trash bin by request parameters for current user
+func (a *TransactionsApi) TransactionTrashRestoreHandler(c *core.Context) (interface{}, *errs.Error) {
+	var transactionTrashRestoreReq models.TransactionTrashRestoreRequest
+	err := c.ShouldBindJSON(&transactionTrashRestoreReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[transactions.TransactionTrashRestoreHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	utcOffset, err := c.GetClientTimezoneOffset()
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[transactions.TransactionTrashRestoreHandler] cannot get client timezone offset, because %s", err.Error())
+		return nil, errs.ErrClientTimezoneOffsetInvalid
+	}
+
+	uid := c.GetCurrentBookUid()
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		if !errs.IsCustomError(err) {
+			log.ErrorfWithRequestId(c, "[transactions.TransactionTrashRestoreHandler] failed to get user, because %s", err.Error())
+		}
+
+		return nil, errs.ErrUserNotFound
+	}
+
+	minDeletedUnixTime := a.getTrashMinDeletedUnixTime()
+	transaction, err := a.transactions.GetTrashTransactionByTransactionId(c, uid, transactionTrashRestoreReq.Id, minDeletedUnixTime)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[transactions.TransactionTrashRestoreHandler] failed to get deleted transaction \"id:%d\" for user \"uid:%d\", because %s", transactionTrashRestoreReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	transactionEditable := user.CanEditTransactionByTransactionTime(transaction.TransactionTime, utcOffset)
+
+	if !transactionEditable {
+		return nil, errs.ErrCannotRestoreTransactionWithThisTransactionTime
+	}
+
+	err = a.transactions.RestoreTransaction(c, uid, c.GetCurrentUid(), transactionTrashRestoreReq.Id, minDeletedUnixTime)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[transactions.TransactionTrashRestoreHandler] failed to restore transaction \"id:%d\" for user \"uid:%d\", because %s", transactionTrashRestoreReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.InfofWithRequestId(c, "[transactions.TransactionTrashRestoreHandler] user \"uid:%d\" has restored transaction \"id:%d\"", uid, transactionTrashRestoreReq.Id)
+	return true, nil
+}
+
+// TransactionTrashPurgeHandler permanently deletes one or all deleted transactions in trash bin by request parameters for current user
+func (a *TransactionsApi) TransactionTrashPurgeHandler(c *core.Context) (interface{}, *errs.Error) {
+	var transactionTrashPurgeReq models.TransactionTrashPurgeRequest
+	err := c.ShouldBindJSON(&transactionTrashPurgeReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[transactions.TransactionTrashPurgeHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	if !transactionTrashPurgeReq.All && transactionTrashPurgeReq.Id <= 0 {
+		return nil, errs.ErrTransactionIdInvalid
+	}
+
+	uid := c.GetCurrentBookUid()
+
+	if !transactionTrashPurgeReq.All {
+		_, err = a.transactions.GetTrashTransactionByTransactionId(c, uid, transactionTrashPurgeReq.Id, 0)
+
+		if err != nil {
+			log.ErrorfWithRequestId(c, "[transactions.TransactionTrashPurgeHandler] failed to get deleted transaction \"id:%d\" for user \"uid:%d\", because %s", transactionTrashPurgeReq.Id, uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+	} else {
+		transactionTrashPurgeReq.Id = 0
+	}
+
+	purgedCount, err := a.transactions.PurgeTrashTransactions(c, uid, c.GetCurrentUid(), transactionTrashPurgeReq.Id, 0)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[transactions.TransactionTrashPurgeHandler] failed to purge deleted transactions for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.InfofWithRequestId(c, "[transactions.TransactionTrashPurgeHandler] user \"uid:%d\" has purged %d deleted transactions", uid, purgedCount)
+
+	return &models.TransactionTrashPurgeResponse{
+		PurgedCount: purgedCount,
+	}, nil
+}
+
 func (a *TransactionsApi) filterTransactions(c *core.Context, uid int64, transactions []*models.Transaction, accountMap map[int64]*models.Account) []*models.Transaction {
 	finalTransactions := make([]*models.Transaction, 0, len(transactions))
 
@@ -993,6 +1234,13 @@ func (a *TransactionsApi) getTransactionListResult(c *core.Context, user *models
 		return nil, err
 	}
 
+	allTransactionSplits, err := a.transactions.GetAllSplitsOfTransactions(c, uid, transactionIds)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[transactions.getTransactionListResult] failed to get transactions splits for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, err
+	}
+
 	var categoryMap map[int64]*models.TransactionCategory
 	var tagMap map[int64]*models.TransactionTag
 
@@ -1026,6 +1274,7 @@ func (a *TransactionsApi) getTransactionListResult(c *core.Context, user *models
 		transactionEditable := transaction.IsEditable(user, utcOffset, allAccounts[transaction.AccountId], allAccounts[transaction.RelatedAccountId])
 		transactionTagIds := allTransactionTagIds[transaction.TransactionId]
 		result[i] = transaction.ToTransactionInfoResponse(transactionTagIds, transactionEditable)
+		result[i].Splits = a.getTransactionSplitInfoResponses(allTransactionSplits[transaction.TransactionId])
 
 		if !trimAccount {
 			if sourceAccount := allAccounts[transaction.AccountId]; sourceAccount != nil {
@@ -1091,3 +1340,154 @@ func (a *TransactionsApi) createNewTransactionModel(uid int64, transactionCreate
 
 	return transaction
 }
+
+// createNewTransactionSplitModels returns nil if split lines are not submitted, or an empty slice if split lines are submitted as an empty array
+func (a *TransactionsApi) createNewTransactionSplitModels(splitReqs []*models.TransactionSplitRequest) ([]*models.TransactionSplit, error) {
+	if splitReqs == nil {
+		return nil, nil
+	}
+
+	splits := make([]*models.TransactionSplit, len(splitReqs))
+
+	for i := 0; i < len(splitReqs); i++ {
+		tagIds, err := utils.StringArrayToInt64Array(splitReqs[i].TagIds)
+
+		if err != nil {
+			return nil, err
+		}
+
+		splits[i] = &models.TransactionSplit{
+			CategoryId: splitReqs[i].CategoryId,
+			Amount:     splitReqs[i].Amount,
+			Comment:    splitReqs[i].Comment,
+		}
+
+		splits[i].SetTagIds(utils.ToUniqueInt64Slice(tagIds))
+	}
+
+	return splits, nil
+}
+
+func (a *TransactionsApi) isTransactionSplitsEqual(splits []*models.TransactionSplit, oldSplits []*models.TransactionSplit) bool {
+	if len(splits) != len(oldSplits) {
+		return false
+	}
+
+	for i := 0; i < len(splits); i++ {
+		if splits[i].CategoryId != oldSplits[i].CategoryId ||
+			splits[i].Amount != oldSplits[i].Amount ||
+			splits[i].TagIds != oldSplits[i].TagIds ||
+			splits[i].Comment != oldSplits[i].Comment {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (a *TransactionsApi) getTransactionSplitInfoResponses(splits []*models.TransactionSplit) []*models.TransactionSplitInfoResponse {
+	if len(splits) < 1 {
+		return nil
+	}
+
+	splitResps := make([]*models.TransactionSplitInfoResponse, len(splits))
+
+	for i := 0; i < len(splits); i++ {
+		splitResps[i] = splits[i].ToTransactionSplitInfoResponse()
+	}
+
+	return splitResps
+}
+
+func (a *TransactionsApi) getTrashMinDeletedUnixTime() int64 {
+	retentionDays := settings.Container.Current.TrashRetentionDays
+
+	if retentionDays < 1 {
+		return 0
+	}
+
+	return time.Now().Unix() - int64(retentionDays)*24*60*60
+}
+
+func (a *TransactionsApi) getConvertToCurrency(c *core.Context, uid int64, convertTo string) (string, error) {
+	if convertTo != "" {
+		return convertTo, nil
+	}
+
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		return "", err
+	}
+
+	return user.DefaultCurrency, nil
+}
+
+func (a *TransactionsApi) getConvertedTotalAmount(c *core.Context, amounts models.TransactionAmountsResponseItemAmountInfoSlice, currency string, unixTime int64, allExchangeRates map[string]*models.ExchangeRateHistoryResponse) *models.TransactionAmountsResponseItemTotalAmountInfo {
+	totalAmount := &models.TransactionAmountsResponseItemTotalAmountInfo{
+		Currency:      currency,
+		ExchangeRates: make([]*models.TransactionAmountsResponseItemExchangeRate, 0),
+	}
+
+	if unixTime > time.Now().Unix() {
+		unixTime = time.Now().Unix()
+	}
+
+	var exchangeRates *models.ExchangeRateHistoryResponse
+
+	for i := 0; i < len(amounts); i++ {
+		amount := amounts[i]
+
+		if amount.Currency == currency {
+			totalAmount.IncomeAmount += amount.IncomeAmount
+			totalAmount.ExpenseAmount += amount.ExpenseAmount
+			continue
+		}
+
+		if exchangeRates == nil {
+			exchangeRates = a.getExchangeRatesByUnixTime(c, unixTime, allExchangeRates)
+		}
+
+		if exchangeRates == nil {
+			totalAmount.UnconvertibleCurrencies = append(totalAmount.UnconvertibleCurrencies, amount.Currency)
+			continue
+		}
+
+		rate, exists := exchangeRates.GetExchangeRate(amount.Currency, currency)
+
+		if !exists {
+			totalAmount.UnconvertibleCurrencies = append(totalAmount.UnconvertibleCurrencies, amount.Currency)
+			continue
+		}
+
+		totalAmount.IncomeAmount += int64(math.Round(float64(amount.IncomeAmount) * rate))
+		totalAmount.ExpenseAmount += int64(math.Round(float64(amount.ExpenseAmount) * rate))
+		totalAmount.ExchangeRateDate = exchangeRates.Date
+		totalAmount.ExchangeRates = append(totalAmount.ExchangeRates, &models.TransactionAmountsResponseItemExchangeRate{
+			Currency: amount.Currency,
+			Rate:     utils.Float64ToString(rate),
+		})
+	}
+
+	return totalAmount
+}
+
+func (a *TransactionsApi) getExchangeRatesByUnixTime(c *core.Context, unixTime int64, allExchangeRates map[string]*models.ExchangeRateHistoryResponse) *models.ExchangeRateHistoryResponse {
+	date := time.Unix(unixTime, 0).UTC().Format("2006-01-02")
+	exchangeRates, exists := allExchangeRates[date]
+
+	if exists {
+		return exchangeRates
+	}
+
+	exchangeRates, err := a.exchangeRates.GetExchangeRatesByUnixTime(c, unixTime)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[transactions.getExchangeRatesByUnixTime] failed to get exchange rates of \"%s\", because %s", date, err.Error())
+		exchangeRates = nil
+	}
+
+	allExchangeRates[date] = exchangeRates
+
+	return exchangeRates
+}
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/f97/gofire/pkg/core"
+	"github.com/f97/gofire/pkg/models"
+)
+
+func TestGetConvertedTotalAmount(t *testing.T) {
+	context := &core.Context{Context: &gin.Context{}}
+	unixTime := time.Date(2024, time.January, 31, 12, 0, 0, 0, time.UTC).Unix()
+	allExchangeRates := map[string]*models.ExchangeRateHistoryResponse{
+		"2024-01-31": {
+			Date:         "2024-01-31",
+			BaseCurrency: "EUR",
+			ExchangeRates: models.LatestExchangeRateSlice{
+				{Currency: "USD", Rate: "1.25"},
+			},
+		},
+	}
+	amounts := models.TransactionAmountsResponseItemAmountInfoSlice{
+		{Currency: "USD", IncomeAmount: 1000, ExpenseAmount: 500},
+		{Currency: "EUR", IncomeAmount: 800, ExpenseAmount: 400},
+		{Currency: "CNY", IncomeAmount: 300, ExpenseAmount: 200},
+	}
+
+	totalAmount := Transactions.getConvertedTotalAmount(context, amounts, "USD", unixTime, allExchangeRates)
+	assert.Equal(t, "USD", totalAmount.Currency)
+	assert.Equal(t, int64(2000), totalAmount.IncomeAmount)
+	assert.Equal(t, int64(1000), totalAmount.ExpenseAmount)
+	assert.Equal(t, "2024-01-31", totalAmount.ExchangeRateDate)
+	assert.Equal(t, 1, len(totalAmount.ExchangeRates))
+	assert.Equal(t, "EUR", totalAmount.ExchangeRates[0].Currency)
+	assert.Equal(t, "1.25", totalAmount.ExchangeRates[0].Rate)
+	assert.Equal(t, []string{"CNY"}, totalAmount.UnconvertibleCurrencies)
+}
+
+func TestGetConvertedTotalAmount_SameCurrency(t *testing.T) {
+	context := &core.Context{Context: &gin.Context{}}
+	amounts := models.TransactionAmountsResponseItemAmountInfoSlice{
+		{Currency: "USD", IncomeAmount: 1000, ExpenseAmount: 500},
+	}
+
+	totalAmount := Transactions.getConvertedTotalAmount(context, amounts, "USD", time.Now().Unix(), make(map[string]*models.ExchangeRateHistoryResponse))
+	assert.Equal(t, int64(1000), totalAmount.IncomeAmount)
+	assert.Equal(t, int64(500), totalAmount.ExpenseAmount)
+	assert.Equal(t, "", totalAmount.ExchangeRateDate)
+	assert.Equal(t, 0, len(totalAmount.ExchangeRates))
+	assert.Nil(t, totalAmount.UnconvertibleCurrencies)
+}
+
+func TestCreateNewTransactionSplitModels_NilAndEmptySplits(t *testing.T) {
+	splits, err := Transactions.createNewTransactionSplitModels(nil)
+	assert.Equal(t, nil, err)
+	assert.Nil(t, splits)
+
+	splits, err = Transactions.createNewTransactionSplitModels([]*models.TransactionSplitRequest{})
+	assert.Equal(t, nil, err)
+	assert.NotNil(t, splits)
+	assert.Equal(t, 0, len(splits))
+
+	splits, err = Transactions.createNewTransactionSplitModels([]*models.TransactionSplitRequest{
+		{CategoryId: 1, Amount: 100, TagIds: []string{"2", "3", "2"}},
+	})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, len(splits))
+	assert.Equal(t, "2,3", splits[0].TagIds)
+
+	_, err = Transactions.createNewTransactionSplitModels([]*models.TransactionSplitRequest{
+		{CategoryId: 1, Amount: 100, TagIds: []string{"a"}},
+	})
+	assert.NotEqual(t, nil, err)
+}
@@ -0,0 +1,571 @@
+package api
+
+import (
+	"encoding/base64"
+	"strings"
+	"time"
+
+	"github.com/f97/gofire/pkg/core"
+	"github.com/f97/gofire/pkg/errs"
+	"github.com/f97/gofire/pkg/log"
+	"github.com/f97/gofire/pkg/models"
+	"github.com/f97/gofire/pkg/services"
+	"github.com/f97/gofire/pkg/settings"
+	"github.com/f97/gofire/pkg/utils"
+	"github.com/f97/gofire/pkg/webauthn"
+)
+
+const webAuthnStateCookieName = "ebk_webauthn_state"
+const webAuthnStateCookiePath = "/api"
+const webAuthnStateExpiredTime = 300 // 5 minutes
+
+const webAuthnPurposeRegister = "register"
+const webAuthnPurposeLogin = "login"
+const webAuthnPurposeTwoFactor = "2fa"
+
+const webAuthnCredentialType = "public-key"
+
+// WebAuthnAuthorizationsApi represents webauthn credential and authorization api
+type WebAuthnAuthorizationsApi struct {
+	users       *services.UserService
+	tokens      *services.TokenService
+	credentials *services.UserWebAuthnCredentialService
+}
+
+// Initialize a webauthn credential and authorization api singleton instance
+var (
+	WebAuthnAuthorizations = &WebAuthnAuthorizationsApi{
+		users:       services.Users,
+		tokens:      services.Tokens,
+		credentials: services.UserWebAuthnCredentials,
+	}
+)
+
+// CredentialListHandler returns all webauthn credentials of current user
+func (a *WebAuthnAuthorizationsApi) CredentialListHandler(c *core.Context) (interface{}, *errs.Error) {
+	uid := c.GetCurrentUid()
+	credentials, err := a.credentials.GetAllCredentialsByUid(c, uid)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[webauthn_authorizations.CredentialListHandler] failed to get webauthn credentials for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	credentialResps := make([]*models.WebAuthnCredentialInfoResponse, len(credentials))
+
+	for i := 0; i < len(credentials); i++ {
+		credentialResps[i] = credentials[i].ToWebAuthnCredentialInfoResponse()
+	}
+
+	return credentialResps, nil
+}
+
+// CredentialRegisterOptionsHandler returns the options for current user to create a new webauthn credential by authenticator
+func (a *WebAuthnAuthorizationsApi) CredentialRegisterOptionsHandler(c *core.Context) (interface{}, *errs.Error) {
+	relyingParty := webauthn.Container.Current
+
+	if relyingParty == nil {
+		return nil, errs.ErrWebAuthnNotEnabled
+	}
+
+	uid := c.GetCurrentUid()
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[webauthn_authorizations.CredentialRegisterOptionsHandler] failed to get user \"uid:%d\" info, because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrUserNotFound)
+	}
+
+	credentials, err := a.credentials.GetAllCredentialsByUid(c, uid)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[webauthn_authorizations.CredentialRegisterOptionsHandler] failed to get webauthn credentials for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	challenge, err := a.createChallenge(c, webAuthnPurposeRegister, uid)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[webauthn_authorizations.CredentialRegisterOptionsHandler] failed to create challenge for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.ErrOperationFailed
+	}
+
+	pubKeyCredParams := make([]*models.WebAuthnCredentialParameter, len(webauthn.SupportedAlgorithms))
+
+	for i := 0; i < len(webauthn.SupportedAlgorithms); i++ {
+		pubKeyCredParams[i] = &models.WebAuthnCredentialParameter{
+			Type:      webAuthnCredentialType,
+			Algorithm: webauthn.SupportedAlgorithms[i],
+		}
+	}
+
+	excludeCredentials := make([]*models.WebAuthnCredentialDescriptor, len(credentials))
+
+	for i := 0; i < len(credentials); i++ {
+		excludeCredentials[i] = credentials[i].ToWebAuthnCredentialDescriptor()
+	}
+
+	optionsResp := &models.WebAuthnCreationOptionsResponse{
+		Challenge: challenge,
+		RelyingParty: &models.WebAuthnRelyingPartyEntity{
+			Id:   relyingParty.Id,
+			Name: relyingParty.Name,
+		},
+		User: &models.WebAuthnUserEntity{
+			Id:          a.getUserHandle(user.Uid),
+			Name:        user.Username,
+			DisplayName: user.Nickname,
+		},
+		PubKeyCredParams:   pubKeyCredParams,
+		Timeout:            webAuthnStateExpiredTime * 1000,
+		Attestation:        "none",
+		ExcludeCredentials: excludeCredentials,
+		AuthenticatorSelection: &models.WebAuthnAuthenticatorSelection{
+			ResidentKey:      "preferred",
+			UserVerification: "preferred",
+		},
+	}
+
+	return optionsResp, nil
+}
+
+// CredentialRegisterHandler verifies the credential created by authenticator and saves it for current user
+func (a *WebAuthnAuthorizationsApi) CredentialRegisterHandler(c *core.Context) (interface{}, *errs.Error) {
+	relyingParty := webauthn.Container.Current
+
+	if relyingParty == nil {
+		return nil, errs.ErrWebAuthnNotEnabled
+	}
+
+	var registerReq models.WebAuthnRegisterRequest
+	err := c.ShouldBindJSON(&registerReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[webauthn_authorizations.CredentialRegisterHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	challenge, err := a.getSavedChallenge(c, webAuthnPurposeRegister, uid)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[webauthn_authorizations.CredentialRegisterHandler] challenge is invalid for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.ErrWebAuthnChallengeInvalid
+	}
+
+	rawId, err := webauthn.DecodeBase64Url(registerReq.Id)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[webauthn_authorizations.CredentialRegisterHandler] failed to decode credential id, because %s", err.Error())
+		return nil, errs.ErrWebAuthnCredentialIdInvalid
+	}
+
+	clientDataJSON, err := webauthn.DecodeBase64Url(registerReq.ClientDataJSON)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[webauthn_authorizations.CredentialRegisterHandler] failed to decode client data, because %s", err.Error())
+		return nil, errs.ErrWebAuthnCredentialInvalid
+	}
+
+	attestationObject, err := webauthn.DecodeBase64Url(registerReq.AttestationObject)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[webauthn_authorizations.CredentialRegisterHandler] failed to decode attestation object, because %s", err.Error())
+		return nil, errs.ErrWebAuthnCredentialInvalid
+	}
+
+	createdCredential, err := relyingParty.VerifyRegistration(c, challenge, rawId, clientDataJSON, attestationObject)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[webauthn_authorizations.CredentialRegisterHandler] failed to verify webauthn credential for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrWebAuthnCredentialInvalid)
+	}
+
+	credential := &models.UserWebAuthnCredential{
+		Uid:       uid,
+		Name:      strings.TrimSpace(registerReq.Name),
+		RawId:     webauthn.EncodeBase64Url(createdCredential.RawId),
+		PublicKey: base64.StdEncoding.EncodeToString(createdCredential.PublicKey),
+		Algorithm: createdCredential.Algorithm,
+		SignCount: int64(createdCredential.SignCount),
+	}
+
+	err = a.credentials.CreateCredential(c, credential)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[webauthn_authorizations.CredentialRegisterHandler] failed to save webauthn credential for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.InfofWithRequestId(c, "[webauthn_authorizations.CredentialRegisterHandler] user \"uid:%d\" has registered webauthn credential \"id:%d\" successfully", uid, credential.CredentialId)
+
+	return credential.ToWebAuthnCredentialInfoResponse(), nil
+}
+
+// CredentialRevokeHandler deletes the specified webauthn credential of current user
+func (a *WebAuthnAuthorizationsApi) CredentialRevokeHandler(c *core.Context) (interface{}, *errs.Error) {
+	var revokeReq models.WebAuthnCredentialRevokeRequest
+	err := c.ShouldBindJSON(&revokeReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[webauthn_authorizations.CredentialRevokeHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	err = a.credentials.DeleteCredential(c, uid, revokeReq.Id)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[webauthn_authorizations.CredentialRevokeHandler] failed to revoke webauthn credential \"id:%d\" for user \"uid:%d\", because %s", revokeReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.InfofWithRequestId(c, "[webauthn_authorizations.CredentialRevokeHandler] user \"uid:%d\" has revoked webauthn credential \"id:%d\"", uid, revokeReq.Id)
+	return true, nil
+}
+
+// AuthorizeOptionsHandler returns the options for authenticator to sign a passwordless login assertion by any discoverable credential
+func (a *WebAuthnAuthorizationsApi) AuthorizeOptionsHandler(c *core.Context) (interface{}, *errs.Error) {
+	relyingParty := webauthn.Container.Current
+
+	if relyingParty == nil {
+		return nil, errs.ErrWebAuthnNotEnabled
+	}
+
+	challenge, err := a.createChallenge(c, webAuthnPurposeLogin, 0)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[webauthn_authorizations.AuthorizeOptionsHandler] failed to create challenge, because %s", err.Error())
+		return nil, errs.ErrOperationFailed
+	}
+
+	optionsResp := &models.WebAuthnRequestOptionsResponse{
+		Challenge:        challenge,
+		RelyingPartyId:   relyingParty.Id,
+		Timeout:          webAuthnStateExpiredTime * 1000,
+		AllowCredentials: make([]*models.WebAuthnCredentialDescriptor, 0),
+		UserVerification: "required",
+	}
+
+	return optionsResp, nil
+}
+
+// AuthorizeHandler verifies the passwordless login assertion signed by authenticator and authorizes the owner of the credential,
+// two factor authorization is not required because the authenticator has verified the user
+func (a *WebAuthnAuthorizationsApi) AuthorizeHandler(c *core.Context) (interface{}, *errs.Error) {
+	relyingParty := webauthn.Container.Current
+
+	if relyingParty == nil {
+		return nil, errs.ErrWebAuthnNotEnabled
+	}
+
+	var loginReq models.WebAuthnLoginRequest
+	err := c.ShouldBindJSON(&loginReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[webauthn_authorizations.AuthorizeHandler] parse request failed, because %s", err.Error())
+		return nil, errs.ErrWebAuthnAssertionInvalid
+	}
+
+	challenge, err := a.getSavedChallenge(c, webAuthnPurposeLogin, 0)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[webauthn_authorizations.AuthorizeHandler] challenge is invalid, because %s", err.Error())
+		return nil, errs.ErrWebAuthnChallengeInvalid
+	}
+
+	credential, err := a.verifyAssertion(c, relyingParty, &loginReq, challenge, 0, true)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[webauthn_authorizations.AuthorizeHandler] failed to verify webauthn assertion, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrWebAuthnAssertionInvalid)
+	}
+
+	user, err := a.users.GetUserById(c, credential.Uid)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[webauthn_authorizations.AuthorizeHandler] failed to get user \"uid:%d\" info, because %s", credential.Uid, err.Error())
+		return nil, errs.ErrWebAuthnAssertionInvalid
+	}
+
+	if user.Disabled {
+		log.WarnfWithRequestId(c, "[webauthn_authorizations.AuthorizeHandler] login failed for user \"uid:%d\", because user is disabled", user.Uid)
+		return nil, errs.ErrUserIsDisabled
+	}
+
+	if settings.Container.Current.EnableUserForceVerifyEmail && !user.EmailVerified {
+		log.WarnfWithRequestId(c, "[webauthn_authorizations.AuthorizeHandler] login failed for user \"uid:%d\", because user has not verified email", user.Uid)
+		return nil, errs.NewErrorWithContext(errs.ErrEmailIsNotVerified, map[string]string{
+			"email": user.Email,
+		})
+	}
+
+	err = a.users.UpdateUserLastLoginTime(c, user.Uid)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[webauthn_authorizations.AuthorizeHandler] failed to update last login time for user \"uid:%d\", because %s", user.Uid, err.Error())
+	}
+
+	token, claims, err := a.tokens.CreateToken(c, user)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[webauthn_authorizations.AuthorizeHandler] failed to create token for user \"uid:%d\", because %s", user.Uid, err.Error())
+		return nil, errs.ErrTokenGenerating
+	}
+
+	c.SetTextualToken(token)
+	c.SetTokenClaims(claims)
+
+	log.InfofWithRequestId(c, "[webauthn_authorizations.AuthorizeHandler] user \"uid:%d\" has logined by webauthn credential \"id:%d\", token will be expired at %d", user.Uid, credential.CredentialId, claims.ExpiresAt)
+
+	authResp := &models.AuthResponse{
+		Token:           token,
+		Need2FA:         false,
+		NeedVerifyEmail: false,
+		User:            user.ToUserBasicInfo(),
+	}
+
+	return authResp, nil
+}
+
+// TwoFactorAuthorizeOptionsHandler returns the options for authenticator to sign a two factor assertion by the credentials of current user
+func (a *WebAuthnAuthorizationsApi) TwoFactorAuthorizeOptionsHandler(c *core.Context) (interface{}, *errs.Error) {
+	relyingParty := webauthn.Container.Current
+
+	if relyingParty == nil {
+		return nil, errs.ErrWebAuthnNotEnabled
+	}
+
+	uid := c.GetCurrentUid()
+	credentials, err := a.credentials.GetAllCredentialsByUid(c, uid)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[webauthn_authorizations.TwoFactorAuthorizeOptionsHandler] failed to get webauthn credentials for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	if len(credentials) < 1 {
+		return nil, errs.ErrWebAuthnCredentialNotFound
+	}
+
+	challenge, err := a.createChallenge(c, webAuthnPurposeTwoFactor, uid)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[webauthn_authorizations.TwoFactorAuthorizeOptionsHandler] failed to create challenge for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.ErrOperationFailed
+	}
+
+	allowCredentials := make([]*models.WebAuthnCredentialDescriptor, len(credentials))
+
+	for i := 0; i < len(credentials); i++ {
+		allowCredentials[i] = credentials[i].ToWebAuthnCredentialDescriptor()
+	}
+
+	optionsResp := &models.WebAuthnRequestOptionsResponse{
+		Challenge:        challenge,
+		RelyingPartyId:   relyingParty.Id,
+		Timeout:          webAuthnStateExpiredTime * 1000,
+		AllowCredentials: allowCredentials,
+		UserVerification: "discouraged",
+	}
+
+	return optionsResp, nil
+}
+
+// TwoFactorAuthorizeHandler verifies the two factor assertion signed by authenticator and authorizes current 2fa login
+func (a *WebAuthnAuthorizationsApi) TwoFactorAuthorizeHandler(c *core.Context) (interface{}, *errs.Error) {
+	relyingParty := webauthn.Container.Current
+
+	if relyingParty == nil {
+		return nil, errs.ErrWebAuthnNotEnabled
+	}
+
+	var loginReq models.WebAuthnLoginRequest
+	err := c.ShouldBindJSON(&loginReq)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[webauthn_authorizations.TwoFactorAuthorizeHandler] parse request failed, because %s", err.Error())
+		return nil, errs.ErrWebAuthnAssertionInvalid
+	}
+
+	uid := c.GetCurrentUid()
+	challenge, err := a.getSavedChallenge(c, webAuthnPurposeTwoFactor, uid)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[webauthn_authorizations.TwoFactorAuthorizeHandler] challenge is invalid for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.ErrWebAuthnChallengeInvalid
+	}
+
+	credential, err := a.verifyAssertion(c, relyingParty, &loginReq, challenge, uid, false)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[webauthn_authorizations.TwoFactorAuthorizeHandler] failed to verify webauthn assertion for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrWebAuthnAssertionInvalid)
+	}
+
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[webauthn_authorizations.TwoFactorAuthorizeHandler] failed to get user \"uid:%d\" info, because %s", uid, err.Error())
+		return nil, errs.ErrUserNotFound
+	}
+
+	if user.Disabled {
+		log.WarnfWithRequestId(c, "[webauthn_authorizations.TwoFactorAuthorizeHandler] user \"uid:%d\" is disabled", user.Uid)
+		return nil, errs.ErrUserIsDisabled
+	}
+
+	if settings.Container.Current.EnableUserForceVerifyEmail && !user.EmailVerified {
+		log.WarnfWithRequestId(c, "[webauthn_authorizations.TwoFactorAuthorizeHandler] user \"uid:%d\" has not verified email", user.Uid)
+		return nil, errs.ErrEmailIsNotVerified
+	}
+
+	oldTokenClaims := c.GetTokenClaims()
+	err = a.tokens.DeleteTokenByClaims(c, oldTokenClaims)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[webauthn_authorizations.TwoFactorAuthorizeHandler] failed to revoke temporary token \"utid:%s\" for user \"uid:%d\", because %s", oldTokenClaims.UserTokenId, user.Uid, err.Error())
+	}
+
+	token, claims, err := a.tokens.CreateToken(c, user)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[webauthn_authorizations.TwoFactorAuthorizeHandler] failed to create token for user \"uid:%d\", because %s", user.Uid, err.Error())
+		return nil, errs.ErrTokenGenerating
+	}
+
+	c.SetTextualToken(token)
+	c.SetTokenClaims(claims)
+
+	log.InfofWithRequestId(c, "[webauthn_authorizations.TwoFactorAuthorizeHandler] user \"uid:%d\" has authorized two factor via webauthn credential \"id:%d\", token will be expired at %d", user.Uid, credential.CredentialId, claims.ExpiresAt)
+
+	authResp := &models.AuthResponse{
+		Token:           token,
+		Need2FA:         false,
+		NeedVerifyEmail: false,
+		User:            user.ToUserBasicInfo(),
+	}
+
+	return authResp, nil
+}
+
+func (a *WebAuthnAuthorizationsApi) verifyAssertion(c *core.Context, relyingParty *webauthn.WebAuthnRelyingParty, loginReq *models.WebAuthnLoginRequest, challenge string, uid int64, requireUserVerification bool) (*models.UserWebAuthnCredential, error) {
+	rawId, err := webauthn.DecodeBase64Url(loginReq.Id)
+
+	if err != nil {
+		return nil, errs.ErrWebAuthnCredentialIdInvalid
+	}
+
+	clientDataJSON, err := webauthn.DecodeBase64Url(loginReq.ClientDataJSON)
+
+	if err != nil {
+		return nil, errs.ErrWebAuthnAssertionInvalid
+	}
+
+	authenticatorData, err := webauthn.DecodeBase64Url(loginReq.AuthenticatorData)
+
+	if err != nil {
+		return nil, errs.ErrWebAuthnAssertionInvalid
+	}
+
+	signature, err := webauthn.DecodeBase64Url(loginReq.Signature)
+
+	if err != nil {
+		return nil, errs.ErrWebAuthnAssertionInvalid
+	}
+
+	credential, err := a.credentials.GetCredentialByRawId(c, webauthn.EncodeBase64Url(rawId))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if uid > 0 && credential.Uid != uid {
+		return nil, errs.ErrWebAuthnCredentialNotFound
+	}
+
+	if loginReq.UserHandle != "" {
+		userHandle, err := webauthn.DecodeBase64Url(loginReq.UserHandle)
+
+		if err != nil || webauthn.EncodeBase64Url(userHandle) != a.getUserHandle(credential.Uid) {
+			return nil, errs.ErrWebAuthnAssertionInvalid
+		}
+	}
+
+	publicKey, err := base64.StdEncoding.DecodeString(credential.PublicKey)
+
+	if err != nil {
+		return nil, err
+	}
+
+	storedCredential := &webauthn.WebAuthnCredential{
+		RawId:     rawId,
+		PublicKey: publicKey,
+		Algorithm: credential.Algorithm,
+		SignCount: uint32(credential.SignCount),
+	}
+
+	signCount, err := relyingParty.VerifyAssertion(c, challenge, storedCredential, clientDataJSON, authenticatorData, signature, requireUserVerification)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = a.credentials.UpdateCredentialLastUsed(c, credential, signCount)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[webauthn_authorizations.verifyAssertion] failed to update last used time of webauthn credential \"id:%d\", because %s", credential.CredentialId, err.Error())
+	}
+
+	return credential, nil
+}
+
+func (a *WebAuthnAuthorizationsApi) createChallenge(c *core.Context, purpose string, uid int64) (string, error) {
+	challenge, err := webauthn.GenerateChallenge()
+
+	if err != nil {
+		return "", err
+	}
+
+	savedChallenge := &models.UserWebAuthnChallenge{
+		Purpose:         purpose,
+		Uid:             uid,
+		Challenge:       challenge,
+		ExpiredUnixTime: time.Now().Unix() + webAuthnStateExpiredTime,
+	}
+
+	err = a.credentials.CreateChallenge(c, savedChallenge)
+
+	if err != nil {
+		return "", err
+	}
+
+	c.SetCookie(webAuthnStateCookieName, savedChallenge.ChallengeId, webAuthnStateExpiredTime, webAuthnStateCookiePath, "", false, true)
+
+	return challenge, nil
+}
+
+func (a *WebAuthnAuthorizationsApi) getSavedChallenge(c *core.Context, purpose string, uid int64) (string, error) {
+	challengeId, err := c.Cookie(webAuthnStateCookieName)
+	c.SetCookie(webAuthnStateCookieName, "", -1, webAuthnStateCookiePath, "", false, true)
+
+	if err != nil {
+		return "", err
+	}
+
+	savedChallenge, err := a.credentials.GetAndDeleteChallenge(c, challengeId)
+
+	if err != nil {
+		return "", err
+	}
+
+	if savedChallenge.Purpose != purpose || savedChallenge.Uid != uid || savedChallenge.ExpiredUnixTime < time.Now().Unix() {
+		return "", errs.ErrWebAuthnChallengeInvalid
+	}
+
+	return savedChallenge.Challenge, nil
+}
+
+func (a *WebAuthnAuthorizationsApi) getUserHandle(uid int64) string {
+	return webauthn.EncodeBase64Url([]byte(utils.Int64ToString(uid)))
+}
@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/f97/gofire/pkg/errs"
+	"github.com/f97/gofire/pkg/exchangerates"
+	"github.com/f97/gofire/pkg/log"
+	"github.com/f97/gofire/pkg/services"
+)
+
+// ExchangeRatesCli represents exchange rates cli
+type ExchangeRatesCli struct {
+	exchangeRates *services.ExchangeRateService
+}
+
+// Initialize an exchange rates cli singleton instance
+var (
+	ExchangeRates = &ExchangeRatesCli{
+		exchangeRates: services.ExchangeRates,
+	}
+)
+
+// BackfillExchangeRates requests the historical exchange rates between start date and end date from current data source and saves them
+func (l *ExchangeRatesCli) BackfillExchangeRates(c *cli.Context, startDate time.Time, endDate time.Time) (int, error) {
+	if startDate.After(endDate) {
+		log.BootErrorf("[exchange_rates.BackfillExchangeRates] start date is later than end date")
+		return 0, errs.ErrExchangeRateDateInvalid
+	}
+
+	exchangeRateResps, err := exchangerates.Container.GetHistoricalExchangeRates(nil, startDate, endDate)
+
+	if err != nil {
+		log.BootErrorf("[exchange_rates.BackfillExchangeRates] failed to get historical exchange rates, because %s", err.Error())
+		return 0, err
+	}
+
+	for i := 0; i < len(exchangeRateResps); i++ {
+		err = l.exchangeRates.SaveExchangeRates(nil, exchangerates.Container.CurrentName, exchangeRateResps[i])
+
+		if err != nil {
+			log.BootErrorf("[exchange_rates.BackfillExchangeRates] failed to save historical exchange rates, because %s", err.Error())
+			return i, err
+		}
+	}
+
+	return len(exchangeRateResps), nil
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/f97/n/pkg/models"
 	"github.com/f97/n/pkg/services"
 	"github.com/f97/n/pkg/settings"
+	"github.com/f97/n/pkg/utils"
 	"github.com/f97/n/pkg/validators"
 )
 
@@ -19,7 +20,7 @@ const pageCountForDataExport = 1000
 
 // UserDataCli represents user data cli
 type UserDataCli struct {
-	goFireCsvExporter *converters.GoFireCSVFileExporter
+	goFireCsvImporter *converters.GoFireCSVFileImporter
 	accounts                 *services.AccountService
 	transactions             *services.TransactionService
 	categories               *services.TransactionCategoryService
@@ -28,12 +29,14 @@ type UserDataCli struct {
 	twoFactorAuthorizations  *services.TwoFactorAuthorizationService
 	tokens                   *services.TokenService
 	forgetPasswords          *services.ForgetPasswordService
+	transactionImports       *services.TransactionImportService
+	reconciliations          *services.AccountReconciliationService
 }
 
 // Initialize an user data cli singleton instance
 var (
 	UserData = &UserDataCli{
-		goFireCsvExporter: &converters.GoFireCSVFileExporter{},
+		goFireCsvImporter: &converters.GoFireCSVFileImporter{},
 		accounts:                 services.Accounts,
 		transactions:             services.Transactions,
 		categories:               services.TransactionCategories,
@@ -42,6 +45,8 @@ var (
 		twoFactorAuthorizations:  services.TwoFactorAuthorizations,
 		tokens:                   services.Tokens,
 		forgetPasswords:          services.ForgetPasswords,
+		transactionImports:       services.TransactionImports,
+		reconciliations:          services.AccountReconciliations,
 	}
 )
 
@@ -529,16 +534,29 @@ func (l *UserDataCli) CheckTransactionAndAccount(c *cli.Context, username string
 		}
 	}
 
+	err = l.checkUnclearedTransactions(c, uid, allTransactions)
+
+	if err != nil {
+		return false, err
+	}
+
 	return true, nil
 }
 
-// ExportTransaction returns csv file content according user all transactions
-func (l *UserDataCli) ExportTransaction(c *cli.Context, username string) ([]byte, error) {
+// ExportTransaction returns exported file content in specified format according user all transactions
+func (l *UserDataCli) ExportTransaction(c *cli.Context, username string, format string) ([]byte, error) {
 	if username == "" {
 		log.BootErrorf("[user_data.ExportTransaction] user name is empty")
 		return nil, errs.ErrUsernameIsEmpty
 	}
 
+	exporter, _, err := converters.GetDataExporter(format)
+
+	if err != nil {
+		log.BootErrorf("[user_data.ExportTransaction] export format \"%s\" is invalid", format)
+		return nil, err
+	}
+
 	uid, err := l.getUserIdByUsername(c, username)
 
 	if err != nil {
@@ -560,16 +578,84 @@ func (l *UserDataCli) ExportTransaction(c *cli.Context, username string) ([]byte
 		return nil, err
 	}
 
-	result, err := l.goFireCsvExporter.ToExportedContent(uid, time.Local, allTransactions, accountMap, categoryMap, tagMap, tagIndexs)
+	result, err := exporter.ToExportedContent(uid, time.Local, allTransactions, accountMap, categoryMap, tagMap, tagIndexs)
 
 	if err != nil {
-		log.BootErrorf("[user_data.ExportTransaction] failed to get csv format exported data for \"%s\", because %s", username, err.Error())
+		log.BootErrorf("[user_data.ExportTransaction] failed to get %s format exported data for \"%s\", because %s", format, username, err.Error())
 		return nil, err
 	}
 
 	return result, nil
 }
 
+// ImportTransaction imports transactions from csv data to specified user
+func (l *UserDataCli) ImportTransaction(c *cli.Context, username string, data []byte, createMissing bool, dryRun bool) (*models.DataImportResponse, error) {
+	if username == "" {
+		log.BootErrorf("[user_data.ImportTransaction] user name is empty")
+		return nil, errs.ErrUsernameIsEmpty
+	}
+
+	user, err := l.GetUserByUsername(c, username)
+
+	if err != nil {
+		log.BootErrorf("[user_data.ImportTransaction] failed to get user by user name \"%s\", because %s", username, err.Error())
+		return nil, err
+	}
+
+	importTransactions, err := l.goFireCsvImporter.ParseImportedData(user.Uid, data)
+
+	if err != nil {
+		log.BootErrorf("[user_data.ImportTransaction] failed to parse csv format imported data for \"%s\", because %s", username, err.Error())
+		return nil, err
+	}
+
+	result, err := l.transactionImports.ImportTransactions(nil, user, user.Uid, importTransactions, createMissing, dryRun, "127.0.0.1")
+
+	if err != nil {
+		log.BootErrorf("[user_data.ImportTransaction] failed to import transactions for \"%s\", because %s", username, err.Error())
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// PurgeTrashTransactions permanently deletes the deleted transactions of specified user which exceed the trash retention days, or all deleted transactions if purgeAll is true
+func (l *UserDataCli) PurgeTrashTransactions(c *cli.Context, username string, purgeAll bool) (int, error) {
+	if username == "" {
+		log.BootErrorf("[user_data.PurgeTrashTransactions] user name is empty")
+		return 0, errs.ErrUsernameIsEmpty
+	}
+
+	uid, err := l.getUserIdByUsername(c, username)
+
+	if err != nil {
+		log.BootErrorf("[user_data.PurgeTrashTransactions] error occurs when getting user id by user name")
+		return 0, err
+	}
+
+	maxDeletedUnixTime := int64(0)
+
+	if !purgeAll {
+		retentionDays := settings.Container.Current.TrashRetentionDays
+
+		if retentionDays < 1 {
+			log.BootWarnf("[user_data.PurgeTrashTransactions] deleted transactions are kept forever because trash retention days is not set")
+			return 0, nil
+		}
+
+		maxDeletedUnixTime = time.Now().Unix() - int64(retentionDays)*24*60*60
+	}
+
+	purgedCount, err := l.transactions.PurgeTrashTransactions(nil, uid, uid, 0, maxDeletedUnixTime)
+
+	if err != nil {
+		log.BootErrorf("[user_data.PurgeTrashTransactions] failed to purge deleted transactions of user \"%s\", because %s", username, err.Error())
+		return 0, err
+	}
+
+	return purgedCount, nil
+}
+
 func (l *UserDataCli) getUserIdByUsername(c *cli.Context, username string) (int64, error) {
 	user, err := l.GetUserByUsername(c, username)
 
@@ -735,3 +821,37 @@ func (l *UserDataCli) checkTransactionRelatedTransaction(c *cli.Context, transac
 
 	return nil
 }
+
+func (l *UserDataCli) checkUnclearedTransactions(c *cli.Context, uid int64, allTransactions []*models.Transaction) error {
+	lastReconciliations, err := l.reconciliations.GetLastCompletedReconciliationsByUid(nil, uid)
+
+	if err != nil {
+		log.BootErrorf("[user_data.checkUnclearedTransactions] failed to get last completed reconciliations for user \"uid:%d\", because %s", uid, err.Error())
+		return err
+	}
+
+	unclearedCount := 0
+
+	for i := 0; i < len(allTransactions); i++ {
+		transaction := allTransactions[i]
+
+		if transaction.Cleared || transaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
+			continue
+		}
+
+		lastReconciliation, exists := lastReconciliations[transaction.AccountId]
+
+		if !exists || transaction.TransactionTime > utils.GetMaxTransactionTimeFromUnixTime(lastReconciliation.StatementUnixTime) {
+			continue
+		}
+
+		log.BootWarnf("[user_data.checkUnclearedTransactions] transaction \"id:%d\" of account \"id:%d\" is not cleared, but it is earlier than the last reconciliation \"id:%d\"", transaction.TransactionId, transaction.AccountId, lastReconciliation.ReconciliationId)
+		unclearedCount++
+	}
+
+	if unclearedCount > 0 {
+		log.BootWarnf("[user_data.checkUnclearedTransactions] there are %d uncleared transactions earlier than the last reconciliation", unclearedCount)
+	}
+
+	return nil
+}
@@ -0,0 +1,23 @@
+package converters
+
+import (
+	"time"
+
+	"github.com/f97/gofire/pkg/models"
+)
+
+// BeancountFileExporter defines the structure of beancount file exporter
+type BeancountFileExporter struct {
+	DataConverter
+}
+
+// ToExportedContent returns the exported beancount data
+func (e *BeancountFileExporter) ToExportedContent(uid int64, timezone *time.Location, transactions []*models.Transaction, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, allTagIndexs map[int64][]int64) ([]byte, error) {
+	exporter := &plainTextAccountingFileExporter{
+		dialect:     plainTextAccountingDialectBeancount,
+		accountMap:  accountMap,
+		categoryMap: categoryMap,
+	}
+
+	return exporter.toExportedContent(transactions, tagMap, allTagIndexs)
+}
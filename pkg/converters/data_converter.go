@@ -3,11 +3,39 @@ package converters
 import (
 	"time"
 
+	"github.com/f97/gofire/pkg/errs"
 	"github.com/f97/gofire/pkg/models"
 )
 
-// DataConverter defines the structure of data exporter
+// Data export formats
+const (
+	DATA_EXPORT_FORMAT_CSV       = "csv"
+	DATA_EXPORT_FORMAT_LEDGER    = "ledger"
+	DATA_EXPORT_FORMAT_HLEDGER   = "hledger"
+	DATA_EXPORT_FORMAT_BEANCOUNT = "beancount"
+)
+
+// DataConverter defines the structure of data exporter and importer
 type DataConverter interface {
 	// ToExportedContent returns the exported data
 	ToExportedContent(uid int64, timezone *time.Location, transactions []*models.Transaction, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, allTagIndexs map[int64][]int64) ([]byte, error)
+
+	// ParseImportedData returns the transactions parsed from the imported data
+	ParseImportedData(uid int64, data []byte) ([]*models.ImportTransaction, error)
+}
+
+// GetDataExporter returns the data exporter and the extension of exported file according to the export format, csv format is used if format is empty
+func GetDataExporter(format string) (DataConverter, string, error) {
+	switch format {
+	case "", DATA_EXPORT_FORMAT_CSV:
+		return &GoFireCSVFileExporter{}, "csv", nil
+	case DATA_EXPORT_FORMAT_LEDGER:
+		return &LedgerFileExporter{}, "ledger", nil
+	case DATA_EXPORT_FORMAT_HLEDGER:
+		return &HledgerFileExporter{}, "journal", nil
+	case DATA_EXPORT_FORMAT_BEANCOUNT:
+		return &BeancountFileExporter{}, "beancount", nil
+	default:
+		return nil, "", errs.ErrDataExportFormatInvalid
+	}
 }
@@ -0,0 +1,178 @@
+package converters
+
+import (
+	"bytes"
+	"encoding/csv"
+	"io"
+	"strings"
+
+	"github.com/f97/gofire/pkg/errs"
+	"github.com/f97/gofire/pkg/models"
+	"github.com/f97/gofire/pkg/utils"
+)
+
+// GoFireCSVFileImporter defines the structure of csv file importer
+type GoFireCSVFileImporter struct {
+	DataConverter
+}
+
+const csvColumnCount = 13
+const utf8BomPrefix = "\xef\xbb\xbf"
+
+// ParseImportedData returns the transactions parsed from the csv data which has the same layout as the exported csv file
+func (e *GoFireCSVFileImporter) ParseImportedData(uid int64, data []byte) ([]*models.ImportTransaction, error) {
+	data = bytes.TrimPrefix(data, []byte(utf8BomPrefix))
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	headerRead := false
+	transactions := make([]*models.ImportTransaction, 0)
+
+	for {
+		record, err := reader.Read()
+
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			if parseErr, ok := err.(*csv.ParseError); ok {
+				return nil, e.newFormatInvalidError(parseErr.StartLine)
+			}
+
+			return nil, e.newFormatInvalidError(0)
+		}
+
+		lineNumber, _ := reader.FieldPos(0)
+
+		if !headerRead {
+			if strings.Join(record, ",")+"\n" != csvHeaderLine {
+				return nil, e.newFormatInvalidError(lineNumber)
+			}
+
+			headerRead = true
+			continue
+		}
+
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+
+		if len(record) != csvColumnCount {
+			return nil, e.newFormatInvalidError(lineNumber)
+		}
+
+		transaction, err := e.parseTransaction(lineNumber, record)
+
+		if err != nil {
+			return nil, err
+		}
+
+		transactions = append(transactions, transaction)
+	}
+
+	if len(transactions) < 1 {
+		return nil, errs.ErrImportFileIsEmpty
+	}
+
+	return transactions, nil
+}
+
+func (e *GoFireCSVFileImporter) parseTransaction(lineNumber int, record []string) (*models.ImportTransaction, error) {
+	timezone, err := utils.ParseFromTimezoneOffset(record[1])
+
+	if err != nil {
+		return nil, e.newFormatInvalidError(lineNumber)
+	}
+
+	transactionTime, err := utils.ParseFromLongDateTimeWithoutSecond(record[0], timezone)
+
+	if err != nil {
+		return nil, e.newFormatInvalidError(lineNumber)
+	}
+
+	transactionType, err := e.getTransactionDbType(record[2])
+
+	if err != nil {
+		return nil, errs.NewErrorWithContext(errs.ErrImportedTransactionTypeInvalid, e.getErrorContext(lineNumber))
+	}
+
+	amount, err := utils.StringToAmount(record[7])
+
+	if err != nil {
+		return nil, e.newFormatInvalidError(lineNumber)
+	}
+
+	transaction := &models.ImportTransaction{
+		LineNumber:          lineNumber,
+		Type:                transactionType,
+		TransactionUnixTime: transactionTime.Unix(),
+		TimezoneUtcOffset:   utils.GetTimezoneOffsetMinutes(timezone),
+		CategoryName:        record[3],
+		SubCategoryName:     record[4],
+		AccountName:         record[5],
+		AccountCurrency:     record[6],
+		Amount:              amount,
+		TagNames:            e.getTagNames(record[11]),
+		Comment:             record[12],
+	}
+
+	if transactionType == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
+		relatedAccountAmount, err := utils.StringToAmount(record[10])
+
+		if err != nil {
+			return nil, e.newFormatInvalidError(lineNumber)
+		}
+
+		transaction.RelatedAccountName = record[8]
+		transaction.RelatedAccountCurrency = record[9]
+		transaction.RelatedAccountAmount = relatedAccountAmount
+	}
+
+	if transaction.AccountName == "" ||
+		(transactionType == models.TRANSACTION_DB_TYPE_TRANSFER_OUT && transaction.RelatedAccountName == "") ||
+		(transactionType != models.TRANSACTION_DB_TYPE_MODIFY_BALANCE && transaction.SubCategoryName == "") {
+		return nil, e.newFormatInvalidError(lineNumber)
+	}
+
+	return transaction, nil
+}
+
+func (e *GoFireCSVFileImporter) getTransactionDbType(transactionTypeName string) (models.TransactionDbType, error) {
+	if transactionTypeName == "Balance Modification" {
+		return models.TRANSACTION_DB_TYPE_MODIFY_BALANCE, nil
+	} else if transactionTypeName == "Income" {
+		return models.TRANSACTION_DB_TYPE_INCOME, nil
+	} else if transactionTypeName == "Expense" {
+		return models.TRANSACTION_DB_TYPE_EXPENSE, nil
+	} else if transactionTypeName == "Transfer" {
+		return models.TRANSACTION_DB_TYPE_TRANSFER_OUT, nil
+	} else {
+		return 0, errs.ErrImportedTransactionTypeInvalid
+	}
+}
+
+func (e *GoFireCSVFileImporter) getTagNames(tags string) []string {
+	items := strings.Split(tags, ";")
+	tagNames := make([]string, 0, len(items))
+
+	for i := 0; i < len(items); i++ {
+		if items[i] != "" {
+			tagNames = append(tagNames, items[i])
+		}
+	}
+
+	return tagNames
+}
+
+func (e *GoFireCSVFileImporter) newFormatInvalidError(lineNumber int) *errs.Error {
+	return errs.NewErrorWithContext(errs.ErrImportFileFormatInvalid, e.getErrorContext(lineNumber))
+}
+
+func (e *GoFireCSVFileImporter) getErrorContext(lineNumber int) map[string]string {
+	return map[string]string{
+		"lineNumber": utils.IntToString(lineNumber),
+	}
+}
@@ -0,0 +1,88 @@
+package converters
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/f97/gofire/pkg/errs"
+	"github.com/f97/gofire/pkg/models"
+)
+
+const goFireCsvImportedContent = "Time,Timezone,Type,Category,Sub Category,Account,Account Currency,Amount,Account2,Account2 Currency,Account2 Amount,Tags,Comment\n" +
+	"2023-08-01 12:34,+07:00,Expense,Food,Lunch,Cash,VND,-12.50,,,,Work;Daily,Noodles\n" +
+	"2023-08-02 08:00,+00:00,Transfer,Transfer,Bank Transfer,Bank,USD,100.00,Wallet,EUR,92.10,,\n" +
+	"2023-08-03 09:15,-05:30,Balance Modification,,,Bank,USD,1234.00,,,,,\n"
+
+func TestGoFireCSVFileImporter_ParseExpenseTransaction(t *testing.T) {
+	importer := &GoFireCSVFileImporter{}
+
+	transactions, err := importer.ParseImportedData(1, []byte(goFireCsvImportedContent))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 3, len(transactions))
+
+	transaction := transactions[0]
+	assert.Equal(t, 2, transaction.LineNumber)
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, transaction.Type)
+	assert.Equal(t, int64(1690868040), transaction.TransactionUnixTime)
+	assert.Equal(t, int16(420), transaction.TimezoneUtcOffset)
+	assert.Equal(t, "Food", transaction.CategoryName)
+	assert.Equal(t, "Lunch", transaction.SubCategoryName)
+	assert.Equal(t, "Cash", transaction.AccountName)
+	assert.Equal(t, "VND", transaction.AccountCurrency)
+	assert.Equal(t, int64(-1250), transaction.Amount)
+	assert.Equal(t, []string{"Work", "Daily"}, transaction.TagNames)
+	assert.Equal(t, "Noodles", transaction.Comment)
+}
+
+func TestGoFireCSVFileImporter_ParseTransferTransaction(t *testing.T) {
+	importer := &GoFireCSVFileImporter{}
+
+	transactions, err := importer.ParseImportedData(1, []byte(goFireCsvImportedContent))
+	assert.Equal(t, nil, err)
+
+	transaction := transactions[1]
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_TRANSFER_OUT, transaction.Type)
+	assert.Equal(t, int64(1690963200), transaction.TransactionUnixTime)
+	assert.Equal(t, "Bank", transaction.AccountName)
+	assert.Equal(t, int64(10000), transaction.Amount)
+	assert.Equal(t, "Wallet", transaction.RelatedAccountName)
+	assert.Equal(t, "EUR", transaction.RelatedAccountCurrency)
+	assert.Equal(t, int64(9210), transaction.RelatedAccountAmount)
+	assert.Equal(t, 0, len(transaction.TagNames))
+}
+
+func TestGoFireCSVFileImporter_ParseBalanceModificationTransaction(t *testing.T) {
+	importer := &GoFireCSVFileImporter{}
+
+	transactions, err := importer.ParseImportedData(1, []byte(goFireCsvImportedContent))
+	assert.Equal(t, nil, err)
+
+	transaction := transactions[2]
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_MODIFY_BALANCE, transaction.Type)
+	assert.Equal(t, int16(-330), transaction.TimezoneUtcOffset)
+	assert.Equal(t, "", transaction.SubCategoryName)
+	assert.Equal(t, int64(123400), transaction.Amount)
+}
+
+func TestGoFireCSVFileImporter_ParseInvalidHeader(t *testing.T) {
+	importer := &GoFireCSVFileImporter{}
+
+	_, err := importer.ParseImportedData(1, []byte("Time,Type\n2023-08-01 12:34,Expense\n"))
+	assert.Equal(t, errs.ErrImportFileFormatInvalid.Code(), err.(*errs.Error).Code())
+}
+
+func TestGoFireCSVFileImporter_ParseInvalidTransactionType(t *testing.T) {
+	importer := &GoFireCSVFileImporter{}
+
+	_, err := importer.ParseImportedData(1, []byte(csvHeaderLine+"2023-08-01 12:34,+07:00,Unknown,Food,Lunch,Cash,VND,-12.50,,,,,\n"))
+	assert.Equal(t, errs.ErrImportedTransactionTypeInvalid.Code(), err.(*errs.Error).Code())
+	assert.Equal(t, map[string]string{"lineNumber": "2"}, err.(*errs.Error).Context)
+}
+
+func TestGoFireCSVFileImporter_ParseEmptyFile(t *testing.T) {
+	importer := &GoFireCSVFileImporter{}
+
+	_, err := importer.ParseImportedData(1, []byte(csvHeaderLine))
+	assert.Equal(t, errs.ErrImportFileIsEmpty, err)
+}
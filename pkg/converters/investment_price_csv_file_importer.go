@@ -0,0 +1,114 @@
+package converters
+
+import (
+	"bytes"
+	"encoding/csv"
+	"io"
+	"strings"
+
+	"github.com/f97/gofire/pkg/errs"
+	"github.com/f97/gofire/pkg/models"
+	"github.com/f97/gofire/pkg/utils"
+)
+
+// InvestmentPriceCSVFileImporter defines the structure of investment price csv file importer
+type InvestmentPriceCSVFileImporter struct {
+}
+
+const investmentPriceCsvHeaderLine = "symbol,date,price"
+const investmentPriceCsvColumnCount = 3
+
+// ParseImportedPrices returns the investment prices parsed from the csv data whose columns are symbol, date (yyyy-MM-dd) and price (e.g. 123.456789),
+// the price time is the beginning of the date in the specified timezone
+func (e *InvestmentPriceCSVFileImporter) ParseImportedPrices(data []byte, utcOffset int16) ([]*models.ImportInvestmentPrice, error) {
+	data = bytes.TrimPrefix(data, []byte(utf8BomPrefix))
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	headerRead := false
+	prices := make([]*models.ImportInvestmentPrice, 0)
+
+	for {
+		record, err := reader.Read()
+
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			if parseErr, ok := err.(*csv.ParseError); ok {
+				return nil, e.newFormatInvalidError(parseErr.StartLine)
+			}
+
+			return nil, e.newFormatInvalidError(0)
+		}
+
+		lineNumber, _ := reader.FieldPos(0)
+
+		if !headerRead {
+			if strings.ToLower(strings.Join(record, ",")) != investmentPriceCsvHeaderLine {
+				return nil, e.newFormatInvalidError(lineNumber)
+			}
+
+			headerRead = true
+			continue
+		}
+
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+
+		if len(record) != investmentPriceCsvColumnCount {
+			return nil, e.newFormatInvalidError(lineNumber)
+		}
+
+		price, err := e.parsePrice(lineNumber, record, utcOffset)
+
+		if err != nil {
+			return nil, err
+		}
+
+		prices = append(prices, price)
+	}
+
+	if len(prices) < 1 {
+		return nil, errs.ErrImportFileIsEmpty
+	}
+
+	return prices, nil
+}
+
+func (e *InvestmentPriceCSVFileImporter) parsePrice(lineNumber int, record []string, utcOffset int16) (*models.ImportInvestmentPrice, error) {
+	symbol := strings.TrimSpace(record[0])
+
+	if symbol == "" {
+		return nil, e.newFormatInvalidError(lineNumber)
+	}
+
+	priceTime, err := utils.ParseFromShortDateTime(strings.TrimSpace(record[1])+" 0:0:0", utcOffset)
+
+	if err != nil {
+		return nil, e.newFormatInvalidError(lineNumber)
+	}
+
+	price, err := utils.StringToDecimal(record[2], 6)
+
+	if err != nil || price < 0 {
+		return nil, e.newFormatInvalidError(lineNumber)
+	}
+
+	return &models.ImportInvestmentPrice{
+		LineNumber:    lineNumber,
+		Symbol:        symbol,
+		PriceUnixTime: priceTime.Unix(),
+		Price:         price,
+	}, nil
+}
+
+func (e *InvestmentPriceCSVFileImporter) newFormatInvalidError(lineNumber int) *errs.Error {
+	return errs.NewErrorWithContext(errs.ErrImportFileFormatInvalid, map[string]string{
+		"lineNumber": utils.IntToString(lineNumber),
+	})
+}
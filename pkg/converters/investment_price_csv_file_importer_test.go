@@ -0,0 +1,46 @@
+package converters
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/f97/gofire/pkg/errs"
+)
+
+func TestInvestmentPriceCSVFileImporter_ParseImportedPrices(t *testing.T) {
+	importer := &InvestmentPriceCSVFileImporter{}
+	content := "Symbol,Date,Price\n" +
+		"VTI,2023-08-01,220.15\n" +
+		"\n" +
+		"BTC, 2023-08-02, 29150.123456\n"
+
+	prices, err := importer.ParseImportedPrices([]byte(content), 480)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2, len(prices))
+
+	assert.Equal(t, "VTI", prices[0].Symbol)
+	assert.Equal(t, int64(1690819200), prices[0].PriceUnixTime)
+	assert.Equal(t, int64(220150000), prices[0].Price)
+	assert.Equal(t, 2, prices[0].LineNumber)
+
+	assert.Equal(t, "BTC", prices[1].Symbol)
+	assert.Equal(t, int64(1690905600), prices[1].PriceUnixTime)
+	assert.Equal(t, int64(29150123456), prices[1].Price)
+}
+
+func TestInvestmentPriceCSVFileImporter_ParseImportedPrices_InvalidContent(t *testing.T) {
+	importer := &InvestmentPriceCSVFileImporter{}
+
+	_, err := importer.ParseImportedPrices([]byte("Symbol,Time,Price\nVTI,2023-08-01,220.15\n"), 0)
+	assert.NotEqual(t, nil, err)
+
+	_, err = importer.ParseImportedPrices([]byte("Symbol,Date,Price\nVTI,2023/08/01,220.15\n"), 0)
+	assert.NotEqual(t, nil, err)
+
+	_, err = importer.ParseImportedPrices([]byte("Symbol,Date,Price\nVTI,2023-08-01,-1\n"), 0)
+	assert.NotEqual(t, nil, err)
+
+	_, err = importer.ParseImportedPrices([]byte("Symbol,Date,Price\n"), 0)
+	assert.Equal(t, errs.ErrImportFileIsEmpty, err)
+}
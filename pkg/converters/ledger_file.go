@@ -0,0 +1,39 @@
+package converters
+
+import (
+	"time"
+
+	"github.com/f97/gofire/pkg/models"
+)
+
+// LedgerFileExporter defines the structure of ledger-cli journal file exporter
+type LedgerFileExporter struct {
+	DataConverter
+}
+
+// HledgerFileExporter defines the structure of hledger journal file exporter
+type HledgerFileExporter struct {
+	DataConverter
+}
+
+// ToExportedContent returns the exported ledger-cli journal data
+func (e *LedgerFileExporter) ToExportedContent(uid int64, timezone *time.Location, transactions []*models.Transaction, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, allTagIndexs map[int64][]int64) ([]byte, error) {
+	exporter := &plainTextAccountingFileExporter{
+		dialect:     plainTextAccountingDialectLedger,
+		accountMap:  accountMap,
+		categoryMap: categoryMap,
+	}
+
+	return exporter.toExportedContent(transactions, tagMap, allTagIndexs)
+}
+
+// ToExportedContent returns the exported hledger journal data
+func (e *HledgerFileExporter) ToExportedContent(uid int64, timezone *time.Location, transactions []*models.Transaction, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, allTagIndexs map[int64][]int64) ([]byte, error) {
+	exporter := &plainTextAccountingFileExporter{
+		dialect:     plainTextAccountingDialectHledger,
+		accountMap:  accountMap,
+		categoryMap: categoryMap,
+	}
+
+	return exporter.toExportedContent(transactions, tagMap, allTagIndexs)
+}
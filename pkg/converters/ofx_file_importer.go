@@ -0,0 +1,218 @@
+package converters
+
+import (
+	"bytes"
+	"strings"
+	"time"
+
+	"github.com/f97/gofire/pkg/errs"
+	"github.com/f97/gofire/pkg/models"
+	"github.com/f97/gofire/pkg/utils"
+)
+
+// OFXFileImporter defines the structure of ofx / qfx bank statement importer, which supports both ofx 1.x (sgml) and ofx 2.x (xml)
+type OFXFileImporter struct {
+	DataConverter
+}
+
+const ofxRootTag = "OFX"
+const ofxStatementTransactionTag = "STMTTRN"
+const ofxDefaultCurrencyTag = "CURDEF"
+const ofxTransactionIdTag = "FITID"
+const ofxPostedDateTag = "DTPOSTED"
+const ofxAmountTag = "TRNAMT"
+const ofxNameTag = "NAME"
+const ofxMemoTag = "MEMO"
+const ofxMaxCommentLength = 255
+
+var ofxEntityReplacer = strings.NewReplacer("&lt;", "<", "&gt;", ">", "&quot;", "\"", "&apos;", "'", "&nbsp;", " ", "&amp;", "&")
+
+// ParseImportedData returns the transactions parsed from the ofx statement, the account and category of transactions are not set
+func (e *OFXFileImporter) ParseImportedData(uid int64, data []byte) ([]*models.ImportTransaction, error) {
+	rootIndex := bytes.Index(data, []byte("<"+ofxRootTag+">"))
+
+	if rootIndex < 0 {
+		return nil, errs.ErrImportFileFormatInvalid
+	}
+
+	content := string(data[rootIndex:])
+	currency := ""
+	transactions := make([]*models.ImportTransaction, 0)
+
+	var currentTransaction map[string]string
+
+	for len(content) > 0 {
+		tagStart := strings.Index(content, "<")
+
+		if tagStart < 0 {
+			break
+		}
+
+		tagEnd := strings.Index(content[tagStart:], ">")
+
+		if tagEnd < 0 {
+			return nil, errs.ErrImportFileFormatInvalid
+		}
+
+		tag := strings.ToUpper(strings.TrimSpace(content[tagStart+1 : tagStart+tagEnd]))
+		content = content[tagStart+tagEnd+1:]
+
+		valueEnd := strings.Index(content, "<")
+
+		if valueEnd < 0 {
+			valueEnd = len(content)
+		}
+
+		value := strings.TrimSpace(ofxEntityReplacer.Replace(content[0:valueEnd]))
+
+		if tag == ofxStatementTransactionTag {
+			currentTransaction = make(map[string]string)
+		} else if tag == "/"+ofxStatementTransactionTag {
+			if currentTransaction == nil {
+				return nil, errs.ErrImportFileFormatInvalid
+			}
+
+			transaction, err := e.parseTransaction(len(transactions)+1, currentTransaction)
+
+			if err != nil {
+				return nil, err
+			}
+
+			transactions = append(transactions, transaction)
+			currentTransaction = nil
+		} else if tag == ofxDefaultCurrencyTag && currency == "" {
+			currency = strings.ToUpper(value)
+		} else if currentTransaction != nil && !strings.HasPrefix(tag, "/") && value != "" {
+			currentTransaction[tag] = value
+		}
+	}
+
+	if currentTransaction != nil {
+		return nil, errs.ErrImportFileFormatInvalid
+	}
+
+	if len(transactions) < 1 {
+		return nil, errs.ErrImportFileIsEmpty
+	}
+
+	for i := 0; i < len(transactions); i++ {
+		transactions[i].AccountCurrency = currency
+	}
+
+	return transactions, nil
+}
+
+func (e *OFXFileImporter) parseTransaction(index int, fields map[string]string) (*models.ImportTransaction, error) {
+	externalId := fields[ofxTransactionIdTag]
+
+	if externalId == "" {
+		return nil, errs.NewErrorWithContext(errs.ErrImportFileFormatInvalid, e.getErrorContext(index))
+	}
+
+	transactionTime, err := e.parseDateTime(fields[ofxPostedDateTag])
+
+	if err != nil {
+		return nil, errs.NewErrorWithContext(errs.ErrImportFileFormatInvalid, e.getErrorContext(index))
+	}
+
+	amount, err := utils.StringToAmount(strings.Replace(fields[ofxAmountTag], ",", ".", 1))
+
+	if err != nil {
+		return nil, errs.NewErrorWithContext(errs.ErrImportFileFormatInvalid, e.getErrorContext(index))
+	}
+
+	transaction := &models.ImportTransaction{
+		LineNumber:          index,
+		ExternalId:          externalId,
+		TransactionUnixTime: transactionTime.Unix(),
+		TimezoneUtcOffset:   utils.GetTimezoneOffsetMinutes(transactionTime.Location()),
+		Comment:             e.getComment(fields[ofxNameTag], fields[ofxMemoTag]),
+	}
+
+	if amount >= 0 {
+		transaction.Type = models.TRANSACTION_DB_TYPE_INCOME
+		transaction.Amount = amount
+	} else {
+		transaction.Type = models.TRANSACTION_DB_TYPE_EXPENSE
+		transaction.Amount = -amount
+	}
+
+	return transaction, nil
+}
+
+// parseDateTime parses the ofx datetime (e.g. 20230801123456.000[-5:EST]), the time is in GMT if timezone is not specified
+func (e *OFXFileImporter) parseDateTime(value string) (time.Time, error) {
+	timezone := time.UTC
+
+	if index := strings.Index(value, "["); index >= 0 {
+		timezoneEnd := strings.Index(value, "]")
+
+		if timezoneEnd < index {
+			return time.Time{}, errs.ErrFormatInvalid
+		}
+
+		offset := value[index+1 : timezoneEnd]
+		value = value[0:index]
+
+		if nameIndex := strings.Index(offset, ":"); nameIndex >= 0 {
+			offset = offset[0:nameIndex]
+		}
+
+		offsetHours, offsetMinutes := offset, "0"
+
+		if dotIndex := strings.Index(offset, "."); dotIndex >= 0 {
+			offsetHours = offset[0:dotIndex]
+			offsetMinutes = offset[dotIndex+1:]
+		}
+
+		hours, err := utils.StringToInt(offsetHours)
+
+		if err != nil {
+			return time.Time{}, err
+		}
+
+		minutes, err := utils.StringToInt(offsetMinutes)
+
+		if err != nil {
+			return time.Time{}, err
+		}
+
+		if strings.HasPrefix(offsetHours, "-") {
+			minutes = -minutes
+		}
+
+		timezone = time.FixedZone("", hours*3600+minutes*60)
+	}
+
+	if index := strings.Index(value, "."); index >= 0 {
+		value = value[0:index]
+	}
+
+	if len(value) == len("20060102") {
+		return time.ParseInLocation("20060102", value, timezone)
+	} else if len(value) == len("200601021504") {
+		return time.ParseInLocation("200601021504", value, timezone)
+	}
+
+	return time.ParseInLocation("20060102150405", value, timezone)
+}
+
+func (e *OFXFileImporter) getComment(name string, memo string) string {
+	comment := name
+
+	if memo != "" && memo != name {
+		if comment != "" {
+			comment = comment + " " + memo
+		} else {
+			comment = memo
+		}
+	}
+
+	return utils.SubString(comment, 0, ofxMaxCommentLength)
+}
+
+func (e *OFXFileImporter) getErrorContext(index int) map[string]string {
+	return map[string]string{
+		"transactionIndex": utils.IntToString(index),
+	}
+}
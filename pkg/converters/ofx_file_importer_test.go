@@ -0,0 +1,115 @@
+package converters
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/f97/gofire/pkg/errs"
+	"github.com/f97/gofire/pkg/models"
+)
+
+const ofxSgmlContent = "OFXHEADER:100\n" +
+	"DATA:OFXSGML\n" +
+	"VERSION:102\n" +
+	"\n" +
+	"<OFX>\n" +
+	"<BANKMSGSRSV1><STMTTRNRS><STMTRS>\n" +
+	"<CURDEF>USD\n" +
+	"<BANKTRANLIST>\n" +
+	"<STMTTRN>\n" +
+	"<TRNTYPE>DEBIT\n" +
+	"<DTPOSTED>20230801120000.000[-5:EST]\n" +
+	"<TRNAMT>-12.50\n" +
+	"<FITID>20230801001\n" +
+	"<NAME>Coffee &amp; Bagel\n" +
+	"<MEMO>Card 1234\n" +
+	"</STMTTRN>\n" +
+	"<STMTTRN>\n" +
+	"<TRNTYPE>CREDIT\n" +
+	"<DTPOSTED>20230802\n" +
+	"<TRNAMT>1000.00\n" +
+	"<FITID>20230802001\n" +
+	"<NAME>Salary\n" +
+	"</STMTTRN>\n" +
+	"</BANKTRANLIST>\n" +
+	"</STMTRS></STMTTRNRS></BANKMSGSRSV1>\n" +
+	"</OFX>\n"
+
+const ofxXmlContent = "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n" +
+	"<?OFX OFXHEADER=\"200\" VERSION=\"211\" SECURITY=\"NONE\"?>\n" +
+	"<OFX>\n" +
+	"<CREDITCARDMSGSRSV1><CCSTMTTRNRS><CCSTMTRS>\n" +
+	"<CURDEF>EUR</CURDEF>\n" +
+	"<BANKTRANLIST>\n" +
+	"<STMTTRN>\n" +
+	"<TRNTYPE>DEBIT</TRNTYPE>\n" +
+	"<DTPOSTED>20230803093000[+5.30:IST]</DTPOSTED>\n" +
+	"<TRNAMT>-5.2</TRNAMT>\n" +
+	"<FITID>A-1</FITID>\n" +
+	"<NAME>Bus</NAME>\n" +
+	"<MEMO>Bus</MEMO>\n" +
+	"</STMTTRN>\n" +
+	"</BANKTRANLIST>\n" +
+	"</CCSTMTRS></CCSTMTTRNRS></CREDITCARDMSGSRSV1>\n" +
+	"</OFX>\n"
+
+func TestOFXFileImporter_ParseSgmlStatement(t *testing.T) {
+	importer := &OFXFileImporter{}
+
+	transactions, err := importer.ParseImportedData(1, []byte(ofxSgmlContent))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2, len(transactions))
+
+	transaction := transactions[0]
+	assert.Equal(t, "20230801001", transaction.ExternalId)
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, transaction.Type)
+	assert.Equal(t, int64(1690909200), transaction.TransactionUnixTime)
+	assert.Equal(t, int16(-300), transaction.TimezoneUtcOffset)
+	assert.Equal(t, int64(1250), transaction.Amount)
+	assert.Equal(t, "USD", transaction.AccountCurrency)
+	assert.Equal(t, "Coffee & Bagel Card 1234", transaction.Comment)
+
+	transaction = transactions[1]
+	assert.Equal(t, "20230802001", transaction.ExternalId)
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_INCOME, transaction.Type)
+	assert.Equal(t, int64(1690934400), transaction.TransactionUnixTime)
+	assert.Equal(t, int16(0), transaction.TimezoneUtcOffset)
+	assert.Equal(t, int64(100000), transaction.Amount)
+	assert.Equal(t, "Salary", transaction.Comment)
+}
+
+func TestOFXFileImporter_ParseXmlStatement(t *testing.T) {
+	importer := &OFXFileImporter{}
+
+	transactions, err := importer.ParseImportedData(1, []byte(ofxXmlContent))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, len(transactions))
+
+	transaction := transactions[0]
+	assert.Equal(t, "A-1", transaction.ExternalId)
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, transaction.Type)
+	assert.Equal(t, int64(1691035200), transaction.TransactionUnixTime)
+	assert.Equal(t, int16(330), transaction.TimezoneUtcOffset)
+	assert.Equal(t, int64(520), transaction.Amount)
+	assert.Equal(t, "EUR", transaction.AccountCurrency)
+	assert.Equal(t, "Bus", transaction.Comment)
+}
+
+func TestOFXFileImporter_ParseTransactionWithoutFitId(t *testing.T) {
+	importer := &OFXFileImporter{}
+
+	_, err := importer.ParseImportedData(1, []byte("<OFX><STMTTRN><DTPOSTED>20230801<TRNAMT>1.00</STMTTRN></OFX>"))
+	assert.NotEqual(t, nil, err)
+	assert.Equal(t, errs.ErrImportFileFormatInvalid.Message, err.(*errs.Error).Message)
+}
+
+func TestOFXFileImporter_ParseInvalidFile(t *testing.T) {
+	importer := &OFXFileImporter{}
+
+	_, err := importer.ParseImportedData(1, []byte("Time,Timezone,Type\n"))
+	assert.Equal(t, errs.ErrImportFileFormatInvalid, err)
+
+	_, err = importer.ParseImportedData(1, []byte("<OFX></OFX>"))
+	assert.Equal(t, errs.ErrImportFileIsEmpty, err)
+}
@@ -0,0 +1,370 @@
+package converters
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/f97/gofire/pkg/models"
+	"github.com/f97/gofire/pkg/utils"
+)
+
+// plainTextAccountingDialect represents the dialect of plain text accounting journal
+type plainTextAccountingDialect byte
+
+// Plain text accounting dialects
+const (
+	plainTextAccountingDialectLedger    plainTextAccountingDialect = 1
+	plainTextAccountingDialectHledger   plainTextAccountingDialect = 2
+	plainTextAccountingDialectBeancount plainTextAccountingDialect = 3
+)
+
+const plainTextAccountingAssetsRootAccount = "Assets"
+const plainTextAccountingLiabilitiesRootAccount = "Liabilities"
+const plainTextAccountingIncomeRootAccount = "Income"
+const plainTextAccountingExpensesRootAccount = "Expenses"
+const plainTextAccountingEquityRootAccount = "Equity"
+const plainTextAccountingOpeningBalancesAccount = "Opening Balances"
+const plainTextAccountingPostingFormat = "    %s  %s %s\n"
+
+// plainTextAccountingFileExporter defines the structure of plain text accounting journal exporter
+type plainTextAccountingFileExporter struct {
+	dialect         plainTextAccountingDialect
+	accountMap      map[int64]*models.Account
+	categoryMap     map[int64]*models.TransactionCategory
+	accountCurrency map[string]string
+}
+
+// toExportedContent returns the exported journal data, the transactions are written in chronological order
+func (e *plainTextAccountingFileExporter) toExportedContent(transactions []*models.Transaction, tagMap map[int64]*models.TransactionTag, allTagIndexs map[int64][]int64) ([]byte, error) {
+	var journal strings.Builder
+	journal.Grow(len(transactions) * 150)
+
+	e.accountCurrency = make(map[string]string)
+	firstTransactionDate := ""
+
+	for i := len(transactions) - 1; i >= 0; i-- {
+		transaction := transactions[i]
+
+		if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+			continue
+		}
+
+		transactionTimeZone := time.FixedZone("Transaction Timezone", int(transaction.TimezoneUtcOffset)*60)
+		transactionTime := time.Unix(utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime), 0).In(transactionTimeZone)
+		transactionDate := e.getDate(transactionTime)
+
+		if firstTransactionDate == "" {
+			firstTransactionDate = transactionDate
+		}
+
+		tagNames := e.getTagNames(transaction.TransactionId, allTagIndexs, tagMap)
+
+		journal.WriteString(e.getTransactionHeader(transactionDate, e.getDescription(transaction), tagNames))
+		journal.WriteString(e.getTransactionPostings(transaction))
+		journal.WriteString("\n")
+	}
+
+	var ret strings.Builder
+	ret.Grow(journal.Len() + len(e.accountCurrency)*50)
+
+	if e.dialect == plainTextAccountingDialectBeancount && firstTransactionDate != "" {
+		accountNames := make([]string, 0, len(e.accountCurrency))
+
+		for accountName := range e.accountCurrency {
+			accountNames = append(accountNames, accountName)
+		}
+
+		sort.Strings(accountNames)
+
+		for i := 0; i < len(accountNames); i++ {
+			accountName := accountNames[i]
+			currency := e.accountCurrency[accountName]
+
+			if currency != "" {
+				ret.WriteString(fmt.Sprintf("%s open %s %s\n", firstTransactionDate, accountName, currency))
+			} else {
+				ret.WriteString(fmt.Sprintf("%s open %s\n", firstTransactionDate, accountName))
+			}
+		}
+
+		ret.WriteString("\n")
+	}
+
+	ret.WriteString(journal.String())
+
+	return []byte(ret.String()), nil
+}
+
+func (e *plainTextAccountingFileExporter) getTransactionHeader(transactionDate string, description string, tagNames []string) string {
+	var ret strings.Builder
+
+	if e.dialect == plainTextAccountingDialectBeancount {
+		ret.WriteString(fmt.Sprintf("%s * \"%s\"", transactionDate, description))
+
+		for i := 0; i < len(tagNames); i++ {
+			ret.WriteString(" #")
+			ret.WriteString(tagNames[i])
+		}
+
+		ret.WriteString("\n")
+
+		return ret.String()
+	}
+
+	ret.WriteString(fmt.Sprintf("%s %s\n", transactionDate, description))
+
+	if len(tagNames) < 1 {
+		return ret.String()
+	}
+
+	if e.dialect == plainTextAccountingDialectLedger {
+		ret.WriteString(fmt.Sprintf("    ; :%s:\n", strings.Join(tagNames, ":")))
+	} else {
+		ret.WriteString(fmt.Sprintf("    ; %s:\n", strings.Join(tagNames, ":, ")))
+	}
+
+	return ret.String()
+}
+
+func (e *plainTextAccountingFileExporter) getTransactionPostings(transaction *models.Transaction) string {
+	var ret strings.Builder
+	accountName := e.getAccountName(transaction.AccountId)
+	currency := e.getAccountCurrency(transaction.AccountId)
+
+	if transaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
+		equityAccountName := e.getEquityAccountName()
+		e.useAccount(accountName, currency)
+		e.useAccount(equityAccountName, "")
+
+		ret.WriteString(fmt.Sprintf(plainTextAccountingPostingFormat, accountName, e.getDisplayAmount(transaction.RelatedAccountAmount), currency))
+		ret.WriteString(fmt.Sprintf(plainTextAccountingPostingFormat, equityAccountName, e.getDisplayAmount(-transaction.RelatedAccountAmount), currency))
+	} else if transaction.Type == models.TRANSACTION_DB_TYPE_INCOME {
+		categoryAccountName := e.getCategoryAccountName(transaction.CategoryId, plainTextAccountingIncomeRootAccount)
+		e.useAccount(accountName, currency)
+		e.useAccount(categoryAccountName, "")
+
+		ret.WriteString(fmt.Sprintf(plainTextAccountingPostingFormat, accountName, e.getDisplayAmount(transaction.Amount), currency))
+		ret.WriteString(fmt.Sprintf(plainTextAccountingPostingFormat, categoryAccountName, e.getDisplayAmount(-transaction.Amount), currency))
+	} else if transaction.Type == models.TRANSACTION_DB_TYPE_EXPENSE {
+		categoryAccountName := e.getCategoryAccountName(transaction.CategoryId, plainTextAccountingExpensesRootAccount)
+		e.useAccount(accountName, currency)
+		e.useAccount(categoryAccountName, "")
+
+		ret.WriteString(fmt.Sprintf(plainTextAccountingPostingFormat, categoryAccountName, e.getDisplayAmount(transaction.Amount), currency))
+		ret.WriteString(fmt.Sprintf(plainTextAccountingPostingFormat, accountName, e.getDisplayAmount(-transaction.Amount), currency))
+	} else if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
+		relatedAccountName := e.getAccountName(transaction.RelatedAccountId)
+		relatedCurrency := e.getAccountCurrency(transaction.RelatedAccountId)
+		e.useAccount(accountName, currency)
+		e.useAccount(relatedAccountName, relatedCurrency)
+
+		ret.WriteString(fmt.Sprintf(plainTextAccountingPostingFormat, relatedAccountName, e.getDisplayAmount(transaction.RelatedAccountAmount), relatedCurrency))
+
+		if currency != relatedCurrency {
+			ret.WriteString(fmt.Sprintf("    %s  %s %s @@ %s %s\n", accountName, e.getDisplayAmount(-transaction.Amount), currency, e.getDisplayAmount(transaction.RelatedAccountAmount), relatedCurrency))
+		} else {
+			ret.WriteString(fmt.Sprintf(plainTextAccountingPostingFormat, accountName, e.getDisplayAmount(-transaction.Amount), currency))
+		}
+	}
+
+	return ret.String()
+}
+
+func (e *plainTextAccountingFileExporter) useAccount(accountName string, currency string) {
+	if _, exists := e.accountCurrency[accountName]; !exists {
+		e.accountCurrency[accountName] = currency
+	}
+}
+
+func (e *plainTextAccountingFileExporter) getDate(transactionTime time.Time) string {
+	if e.dialect == plainTextAccountingDialectLedger {
+		return transactionTime.Format("2006/01/02")
+	}
+
+	return transactionTime.Format("2006-01-02")
+}
+
+func (e *plainTextAccountingFileExporter) getDescription(transaction *models.Transaction) string {
+	description := transaction.Comment
+
+	if description == "" {
+		if transaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
+			description = "Balance Modification"
+		} else if category, exists := e.categoryMap[transaction.CategoryId]; exists {
+			description = category.Name
+		}
+	}
+
+	description = strings.Join(strings.Fields(description), " ")
+
+	if e.dialect == plainTextAccountingDialectBeancount {
+		description = strings.Replace(description, "\\", "\\\\", -1)
+		description = strings.Replace(description, "\"", "\\\"", -1)
+	}
+
+	return description
+}
+
+// getAccountName returns the full name of account which contains its parent account name (e.g. Assets:Bank:Checking)
+func (e *plainTextAccountingFileExporter) getAccountName(accountId int64) string {
+	account, exists := e.accountMap[accountId]
+
+	if !exists {
+		return e.getFullAccountName(plainTextAccountingAssetsRootAccount, "Unknown")
+	}
+
+	names := []string{account.Name}
+
+	if account.ParentAccountId != models.LevelOneAccountParentId {
+		if parentAccount, exists := e.accountMap[account.ParentAccountId]; exists {
+			account = parentAccount
+			names = append([]string{parentAccount.Name}, names...)
+		}
+	}
+
+	rootAccount := plainTextAccountingAssetsRootAccount
+
+	if account.IsLiability() {
+		rootAccount = plainTextAccountingLiabilitiesRootAccount
+	}
+
+	return e.getFullAccountName(rootAccount, names...)
+}
+
+func (e *plainTextAccountingFileExporter) getAccountCurrency(accountId int64) string {
+	account, exists := e.accountMap[accountId]
+
+	if exists {
+		return account.Currency
+	} else {
+		return ""
+	}
+}
+
+// getCategoryAccountName returns the full name of category as income or expense account (e.g. Expenses:Food:Lunch)
+func (e *plainTextAccountingFileExporter) getCategoryAccountName(categoryId int64, rootAccount string) string {
+	category, exists := e.categoryMap[categoryId]
+
+	if !exists {
+		return e.getFullAccountName(rootAccount, "Unknown")
+	}
+
+	names := []string{category.Name}
+
+	if category.ParentCategoryId != models.LevelOneTransactionParentId {
+		if parentCategory, exists := e.categoryMap[category.ParentCategoryId]; exists {
+			names = append([]string{parentCategory.Name}, names...)
+		}
+	}
+
+	return e.getFullAccountName(rootAccount, names...)
+}
+
+func (e *plainTextAccountingFileExporter) getEquityAccountName() string {
+	return e.getFullAccountName(plainTextAccountingEquityRootAccount, plainTextAccountingOpeningBalancesAccount)
+}
+
+func (e *plainTextAccountingFileExporter) getFullAccountName(rootAccount string, names ...string) string {
+	var ret strings.Builder
+	ret.WriteString(rootAccount)
+
+	for i := 0; i < len(names); i++ {
+		ret.WriteString(":")
+		ret.WriteString(e.getAccountNameComponent(names[i]))
+	}
+
+	return ret.String()
+}
+
+// getAccountNameComponent returns the valid component of account name, beancount only allows letters, numbers and dash in account name and it must start with capital letter or number, ledger and hledger do not allow colon and two spaces in account name
+func (e *plainTextAccountingFileExporter) getAccountNameComponent(name string) string {
+	if e.dialect != plainTextAccountingDialectBeancount {
+		name = strings.Join(strings.Fields(name), " ")
+		name = strings.Replace(name, ":", "-", -1)
+
+		if name == "" {
+			return "Unknown"
+		}
+
+		return name
+	}
+
+	runes := []rune(strings.Join(strings.Fields(name), "-"))
+
+	for i := 0; i < len(runes); i++ {
+		if !unicode.IsLetter(runes[i]) && !unicode.IsDigit(runes[i]) {
+			runes[i] = '-'
+		}
+	}
+
+	if len(runes) < 1 {
+		return "Unknown"
+	}
+
+	if unicode.IsLetter(runes[0]) {
+		runes[0] = unicode.ToUpper(runes[0])
+	}
+
+	if !unicode.IsUpper(runes[0]) && !unicode.IsDigit(runes[0]) {
+		return "X" + string(runes)
+	}
+
+	return string(runes)
+}
+
+func (e *plainTextAccountingFileExporter) getTagNames(transactionId int64, allTagIndexs map[int64][]int64, tagMap map[int64]*models.TransactionTag) []string {
+	tagIndexs, exists := allTagIndexs[transactionId]
+
+	if !exists {
+		return nil
+	}
+
+	tagNames := make([]string, 0, len(tagIndexs))
+
+	for i := 0; i < len(tagIndexs); i++ {
+		tag, exists := tagMap[tagIndexs[i]]
+
+		if !exists {
+			continue
+		}
+
+		tagName := e.getTagName(tag.Name)
+
+		if tagName != "" {
+			tagNames = append(tagNames, tagName)
+		}
+	}
+
+	return tagNames
+}
+
+// getTagName returns the valid tag name, beancount only allows letters, numbers, dash, underscore, slash and dot in tag, ledger and hledger do not allow spaces, colon and comma in tag
+func (e *plainTextAccountingFileExporter) getTagName(name string) string {
+	runes := []rune(strings.TrimSpace(name))
+
+	for i := 0; i < len(runes); i++ {
+		if e.dialect == plainTextAccountingDialectBeancount {
+			if (runes[i] > unicode.MaxASCII || (!unicode.IsLetter(runes[i]) && !unicode.IsDigit(runes[i]))) && runes[i] != '-' && runes[i] != '_' && runes[i] != '/' && runes[i] != '.' {
+				runes[i] = '-'
+			}
+		} else if unicode.IsSpace(runes[i]) || runes[i] == ':' || runes[i] == ',' {
+			runes[i] = '-'
+		}
+	}
+
+	return string(runes)
+}
+
+func (e *plainTextAccountingFileExporter) getDisplayAmount(amount int64) string {
+	sign := ""
+
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	return fmt.Sprintf("%s%d.%02d", sign, amount/100, amount%100)
+}
@@ -0,0 +1,115 @@
+package converters
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/f97/gofire/pkg/models"
+)
+
+func getPlainTextAccountingTestData() ([]*models.Transaction, map[int64]*models.Account, map[int64]*models.TransactionCategory, map[int64]*models.TransactionTag, map[int64][]int64) {
+	accountMap := map[int64]*models.Account{
+		1: {AccountId: 1, Name: "Bank", Category: models.ACCOUNT_CATEGORY_DEBIT_CARD, Type: models.ACCOUNT_TYPE_MULTI_SUB_ACCOUNTS, Currency: "USD"},
+		2: {AccountId: 2, Name: "Checking: Main", ParentAccountId: 1, Category: models.ACCOUNT_CATEGORY_DEBIT_CARD, Currency: "USD"},
+		3: {AccountId: 3, Name: "my card", Category: models.ACCOUNT_CATEGORY_CREDIT_CARD, Currency: "EUR"},
+	}
+
+	categoryMap := map[int64]*models.TransactionCategory{
+		10: {CategoryId: 10, Name: "Food", Type: models.CATEGORY_TYPE_EXPENSE},
+		11: {CategoryId: 11, Name: "Lunch", ParentCategoryId: 10, Type: models.CATEGORY_TYPE_EXPENSE},
+	}
+
+	tagMap := map[int64]*models.TransactionTag{
+		20: {TagId: 20, Name: "Work Trip"},
+	}
+
+	transactions := []*models.Transaction{
+		{TransactionId: 102, Type: models.TRANSACTION_DB_TYPE_TRANSFER_OUT, TransactionTime: 1690963200000, AccountId: 2, Amount: 10000, RelatedAccountId: 3, RelatedAccountAmount: 9210},
+		{TransactionId: 101, Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 11, TransactionTime: 1690868040000, TimezoneUtcOffset: 420, AccountId: 2, Amount: 1250, Comment: "Noodles \"Pho\""},
+	}
+
+	allTagIndexs := map[int64][]int64{
+		101: {20},
+	}
+
+	return transactions, accountMap, categoryMap, tagMap, allTagIndexs
+}
+
+func TestLedgerFileExporter_ToExportedContent(t *testing.T) {
+	transactions, accountMap, categoryMap, tagMap, allTagIndexs := getPlainTextAccountingTestData()
+	exporter := &LedgerFileExporter{}
+
+	content, err := exporter.ToExportedContent(1, time.UTC, transactions, accountMap, categoryMap, tagMap, allTagIndexs)
+	assert.Equal(t, nil, err)
+
+	expected := "2023/08/01 Noodles \"Pho\"\n" +
+		"    ; :Work-Trip:\n" +
+		"    Expenses:Food:Lunch  12.50 USD\n" +
+		"    Assets:Bank:Checking- Main  -12.50 USD\n" +
+		"\n" +
+		"2023/08/02 \n" +
+		"    Liabilities:my card  92.10 EUR\n" +
+		"    Assets:Bank:Checking- Main  -100.00 USD @@ 92.10 EUR\n" +
+		"\n"
+
+	assert.Equal(t, expected, string(content))
+}
+
+func TestHledgerFileExporter_ToExportedContent(t *testing.T) {
+	transactions, accountMap, categoryMap, tagMap, allTagIndexs := getPlainTextAccountingTestData()
+	exporter := &HledgerFileExporter{}
+
+	content, err := exporter.ToExportedContent(1, time.UTC, transactions, accountMap, categoryMap, tagMap, allTagIndexs)
+	assert.Equal(t, nil, err)
+
+	expected := "2023-08-01 Noodles \"Pho\"\n" +
+		"    ; Work-Trip:\n" +
+		"    Expenses:Food:Lunch  12.50 USD\n" +
+		"    Assets:Bank:Checking- Main  -12.50 USD\n" +
+		"\n" +
+		"2023-08-02 \n" +
+		"    Liabilities:my card  92.10 EUR\n" +
+		"    Assets:Bank:Checking- Main  -100.00 USD @@ 92.10 EUR\n" +
+		"\n"
+
+	assert.Equal(t, expected, string(content))
+}
+
+func TestBeancountFileExporter_ToExportedContent(t *testing.T) {
+	transactions, accountMap, categoryMap, tagMap, allTagIndexs := getPlainTextAccountingTestData()
+	exporter := &BeancountFileExporter{}
+
+	content, err := exporter.ToExportedContent(1, time.UTC, transactions, accountMap, categoryMap, tagMap, allTagIndexs)
+	assert.Equal(t, nil, err)
+
+	expected := "2023-08-01 open Assets:Bank:Checking--Main USD\n" +
+		"2023-08-01 open Expenses:Food:Lunch\n" +
+		"2023-08-01 open Liabilities:My-card EUR\n" +
+		"\n" +
+		"2023-08-01 * \"Noodles \\\"Pho\\\"\" #Work-Trip\n" +
+		"    Expenses:Food:Lunch  12.50 USD\n" +
+		"    Assets:Bank:Checking--Main  -12.50 USD\n" +
+		"\n" +
+		"2023-08-02 * \"\"\n" +
+		"    Liabilities:My-card  92.10 EUR\n" +
+		"    Assets:Bank:Checking--Main  -100.00 USD @@ 92.10 EUR\n" +
+		"\n"
+
+	assert.Equal(t, expected, string(content))
+}
+
+func TestGetDataExporter(t *testing.T) {
+	_, fileExtension, err := GetDataExporter("")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "csv", fileExtension)
+
+	exporter, fileExtension, err := GetDataExporter(DATA_EXPORT_FORMAT_BEANCOUNT)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "beancount", fileExtension)
+	assert.IsType(t, &BeancountFileExporter{}, exporter)
+
+	_, _, err = GetDataExporter("qif")
+	assert.NotEqual(t, nil, err)
+}
@@ -11,6 +11,7 @@ import (
 const requestIdFieldKey = "REQUEST_ID"
 const textualTokenFieldKey = "TOKEN_STRING"
 const tokenClaimsFieldKey = "TOKEN_CLAIMS"
+const bookUidFieldKey = "BOOK_UID"
 const responseErrorFieldKey = "RESPONSE_ERROR"
 
 // AcceptLanguageHeaderName represents the header name of accept language
@@ -19,6 +20,9 @@ const AcceptLanguageHeaderName = "Accept-Language"
 // ClientTimezoneOffsetHeaderName represents the header name of client timezone offset
 const ClientTimezoneOffsetHeaderName = "X-Timezone-Offset"
 
+// BookIdHeaderName represents the header name of the shared book (the uid of book owner) which current request accesses
+const BookIdHeaderName = "X-Book-Id"
+
 // Context represents the request and response context
 type Context struct {
 	*gin.Context
@@ -32,6 +36,10 @@ func (c *Context) SetRequestId(requestId string) {
 
 // GetRequestId returns the current request id
 func (c *Context) GetRequestId() string {
+	if c == nil || c.Context == nil {
+		return ""
+	}
+
 	requestId, exists := c.Get(requestIdFieldKey)
 
 	if !exists {
@@ -84,6 +92,22 @@ func (c *Context) GetCurrentUid() int64 {
 	return claims.Uid
 }
 
+// SetCurrentBookUid sets the uid of the shared book owner which current user is accessing
+func (c *Context) SetCurrentBookUid(uid int64) {
+	c.Set(bookUidFieldKey, uid)
+}
+
+// GetCurrentBookUid returns the uid of the shared book owner which current user is accessing, or the current user uid if no shared book is accessed
+func (c *Context) GetCurrentBookUid() int64 {
+	uid, exists := c.Get(bookUidFieldKey)
+
+	if !exists {
+		return c.GetCurrentUid()
+	}
+
+	return uid.(int64)
+}
+
 // GetClientLocale returns the client locale name
 func (c *Context) GetClientLocale() string {
 	value := c.GetHeader(AcceptLanguageHeaderName)
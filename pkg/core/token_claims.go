@@ -11,10 +11,11 @@ type TokenType byte
 
 // Token types
 const (
-	USER_TOKEN_TYPE_NORMAL         TokenType = 1
-	USER_TOKEN_TYPE_REQUIRE_2FA    TokenType = 2
-	USER_TOKEN_TYPE_EMAIL_VERIFY   TokenType = 3
-	USER_TOKEN_TYPE_PASSWORD_RESET TokenType = 4
+	USER_TOKEN_TYPE_NORMAL          TokenType = 1
+	USER_TOKEN_TYPE_REQUIRE_2FA     TokenType = 2
+	USER_TOKEN_TYPE_EMAIL_VERIFY    TokenType = 3
+	USER_TOKEN_TYPE_PASSWORD_RESET  TokenType = 4
+	USER_TOKEN_TYPE_PERSONAL_ACCESS TokenType = 5
 )
 
 // UserTokenClaims represents user token
@@ -23,10 +24,22 @@ type UserTokenClaims struct {
 	Uid         int64     `json:"jti,string"`
 	Username    string    `json:"username,omitempty"`
 	Type        TokenType `json:"type"`
+	Scopes      []string  `json:"scopes,omitempty"`
 	IssuedAt    int64     `json:"iat"`
 	ExpiresAt   int64     `json:"exp"`
 }
 
+// HasScope returns whether this token is granted the specified scope
+func (c *UserTokenClaims) HasScope(scope string) bool {
+	for i := 0; i < len(c.Scopes); i++ {
+		if c.Scopes[i] == scope {
+			return true
+		}
+	}
+
+	return false
+}
+
 // GetExpirationTime returns the expiration time of this token
 func (c *UserTokenClaims) GetExpirationTime() (*jwt.NumericDate, error) {
 	return &jwt.NumericDate{
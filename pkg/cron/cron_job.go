@@ -0,0 +1,45 @@
+package cron
+
+import (
+	"time"
+
+	"github.com/f97/gofire/pkg/log"
+)
+
+// CronJob represents a job which runs periodically in background
+type CronJob struct {
+	Name     string
+	Interval time.Duration
+	Run      func() error
+}
+
+// start runs the job immediately and then runs it once every interval
+func (j *CronJob) start() {
+	go func() {
+		ticker := time.NewTicker(j.Interval)
+		defer ticker.Stop()
+
+		for {
+			j.runOnce()
+			<-ticker.C
+		}
+	}()
+}
+
+func (j *CronJob) runOnce() {
+	defer func() {
+		if err := recover(); err != nil {
+			log.Errorf("[cron_job.runOnce] job \"%s\" crashed, because %s", j.Name, err)
+		}
+	}()
+
+	startTime := time.Now()
+	err := j.Run()
+
+	if err != nil {
+		log.Errorf("[cron_job.runOnce] job \"%s\" failed, because %s", j.Name, err.Error())
+		return
+	}
+
+	log.Debugf("[cron_job.runOnce] job \"%s\" finished, cost %dms", j.Name, time.Since(startTime).Milliseconds())
+}
@@ -0,0 +1,77 @@
+package cron
+
+import (
+	"time"
+
+	"github.com/f97/gofire/pkg/exchangerates"
+	"github.com/f97/gofire/pkg/log"
+	"github.com/f97/gofire/pkg/services"
+	"github.com/f97/gofire/pkg/settings"
+)
+
+// CronJobContainer contains all background jobs
+type CronJobContainer struct {
+	jobs []*CronJob
+}
+
+// Initialize a cron job container singleton instance
+var (
+	Container = &CronJobContainer{}
+)
+
+// InitializeCronJobs registers all background jobs according to the config and starts them
+func InitializeCronJobs(config *settings.Config) error {
+	Container.registerJob(&CronJob{
+		Name:     "CreateScheduledTransactions",
+		Interval: time.Minute,
+		Run: func() error {
+			createdCount, err := services.TransactionSchedules.CreateDueScheduledTransactions(nil, time.Now().Unix())
+
+			if createdCount > 0 {
+				log.Infof("[cron_job_container.CreateScheduledTransactions] %d scheduled transactions have been created", createdCount)
+			}
+
+			return err
+		},
+	})
+
+	Container.registerJob(&CronJob{
+		Name:     "RemoveExpiredWebAuthnChallenges",
+		Interval: time.Hour,
+		Run: func() error {
+			deletedCount, err := services.UserWebAuthnCredentials.DeleteExpiredChallenges(nil, time.Now().Unix())
+
+			if deletedCount > 0 {
+				log.Infof("[cron_job_container.RemoveExpiredWebAuthnChallenges] %d expired webauthn challenges have been removed", deletedCount)
+			}
+
+			return err
+		},
+	})
+
+	if exchangerates.Container.IsCacheEnabled() {
+		Container.registerJob(&CronJob{
+			Name:     "RefreshLatestExchangeRates",
+			Interval: exchangerates.Container.GetCacheTTL(),
+			Run: func() error {
+				exchangeRateResp, err := exchangerates.Container.RefreshLatestExchangeRates(nil)
+
+				if err != nil {
+					return err
+				}
+
+				return services.ExchangeRates.SaveExchangeRates(nil, exchangerates.Container.CurrentName, exchangeRateResp)
+			},
+		})
+	}
+
+	for i := 0; i < len(Container.jobs); i++ {
+		Container.jobs[i].start()
+	}
+
+	return nil
+}
+
+func (c *CronJobContainer) registerJob(job *CronJob) {
+	c.jobs = append(c.jobs, job)
+}
@@ -17,6 +17,11 @@ func (s *DataStore) Choose(key int64) *Database {
 	return s.databases[0]
 }
 
+// All returns all database instances in this data storage
+func (s *DataStore) All() []*Database {
+	return s.databases
+}
+
 // Query returns a new database session in a specific database by sharding key
 func (s *DataStore) Query(c *core.Context, key int64) *xorm.Session {
 	return s.Choose(key).NewSession(c)
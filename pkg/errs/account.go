@@ -18,4 +18,9 @@ var (
 	ErrSourceAccountNotFound                  = NewNormalError(NormalSubcategoryAccount, 11, http.StatusBadRequest, "source account not found")
 	ErrDestinationAccountNotFound             = NewNormalError(NormalSubcategoryAccount, 12, http.StatusBadRequest, "destination account not found")
 	ErrAccountInUseCannotBeDeleted            = NewNormalError(NormalSubcategoryAccount, 13, http.StatusBadRequest, "account is in use and cannot be deleted")
+	ErrAccountNetWorthTooManyDataPoints       = NewNormalError(NormalSubcategoryAccount, 14, http.StatusBadRequest, "there are too many data points in net worth series")
+	ErrAccountCannotSetCreditCardSettings     = NewNormalError(NormalSubcategoryAccount, 15, http.StatusBadRequest, "only credit card account can set credit card settings")
+	ErrNotCreditCardAccount                   = NewNormalError(NormalSubcategoryAccount, 16, http.StatusBadRequest, "account is not a credit card account")
+	ErrCreditCardStatementClosingDayNotSet    = NewNormalError(NormalSubcategoryAccount, 17, http.StatusBadRequest, "statement closing day of credit card is not set")
+	ErrCreditCardPaymentAmountInvalid         = NewNormalError(NormalSubcategoryAccount, 18, http.StatusBadRequest, "credit card payment amount is invalid")
 )
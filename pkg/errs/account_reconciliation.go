@@ -0,0 +1,15 @@
+package errs
+
+import "net/http"
+
+// Error codes related to account reconciliations
+var (
+	ErrReconciliationIdInvalid               = NewNormalError(NormalSubcategoryReconciliation, 0, http.StatusBadRequest, "reconciliation id is invalid")
+	ErrReconciliationNotFound                = NewNormalError(NormalSubcategoryReconciliation, 1, http.StatusBadRequest, "reconciliation not found")
+	ErrReconciliationInProgressAlreadyExists = NewNormalError(NormalSubcategoryReconciliation, 2, http.StatusBadRequest, "account already has a reconciliation in progress")
+	ErrReconciliationAlreadyCompleted        = NewNormalError(NormalSubcategoryReconciliation, 3, http.StatusBadRequest, "reconciliation has been completed")
+	ErrReconciliationBalanceNotEqual         = NewNormalError(NormalSubcategoryReconciliation, 4, http.StatusBadRequest, "cleared balance does not equal to statement ending balance")
+	ErrReconciliationStatementTimeInvalid    = NewNormalError(NormalSubcategoryReconciliation, 5, http.StatusBadRequest, "statement time must be later than last reconciliation")
+	ErrCannotReconcileAccountWithSubAccounts = NewNormalError(NormalSubcategoryReconciliation, 6, http.StatusBadRequest, "cannot reconcile account with sub accounts")
+	ErrTransactionNotInReconciliationPeriod  = NewNormalError(NormalSubcategoryReconciliation, 7, http.StatusBadRequest, "transaction is not in reconciliation period")
+)
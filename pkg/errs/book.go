@@ -0,0 +1,16 @@
+package errs
+
+import "net/http"
+
+// Error codes related to shared books
+var (
+	ErrBookIdInvalid           = NewNormalError(NormalSubcategoryBook, 0, http.StatusBadRequest, "book id is invalid")
+	ErrBookNotFound            = NewNormalError(NormalSubcategoryBook, 1, http.StatusForbidden, "book not found or you are not a member")
+	ErrBookPermissionDenied    = NewNormalError(NormalSubcategoryBook, 2, http.StatusForbidden, "you do not have permission to perform this operation in this book")
+	ErrBookMemberRoleInvalid   = NewNormalError(NormalSubcategoryBook, 3, http.StatusBadRequest, "book member role is invalid")
+	ErrBookMemberNotFound      = NewNormalError(NormalSubcategoryBook, 4, http.StatusBadRequest, "book member not found")
+	ErrBookMemberAlreadyExists = NewNormalError(NormalSubcategoryBook, 5, http.StatusBadRequest, "user is already a member of this book")
+	ErrCannotInviteBookOwner   = NewNormalError(NormalSubcategoryBook, 6, http.StatusBadRequest, "cannot invite book owner")
+	ErrBookInvitationNotFound  = NewNormalError(NormalSubcategoryBook, 7, http.StatusBadRequest, "book invitation not found")
+	ErrCannotModifyBookOwner   = NewNormalError(NormalSubcategoryBook, 8, http.StatusBadRequest, "cannot modify or remove book owner")
+)
@@ -0,0 +1,12 @@
+package errs
+
+import "net/http"
+
+// Error codes related to book locks
+var (
+	ErrBookLockNotFound                         = NewNormalError(NormalSubcategoryBookLock, 0, http.StatusBadRequest, "book lock not found")
+	ErrBookLockClosedTimeInvalid                = NewNormalError(NormalSubcategoryBookLock, 1, http.StatusBadRequest, "book lock closed time is invalid")
+	ErrCannotCloseBooksOfAccountWithSubAccounts = NewNormalError(NormalSubcategoryBookLock, 2, http.StatusBadRequest, "cannot close books of account with sub accounts")
+	ErrCannotCloseBooksBeforeCurrentClosedTime  = NewNormalError(NormalSubcategoryBookLock, 3, http.StatusBadRequest, "cannot close books before current closed time")
+	ErrCannotReopenBooksAfterCurrentClosedTime  = NewNormalError(NormalSubcategoryBookLock, 4, http.StatusBadRequest, "cannot reopen books after current closed time")
+)
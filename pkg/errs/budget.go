@@ -0,0 +1,11 @@
+package errs
+
+import "net/http"
+
+// Error codes related to budgets
+var (
+	ErrBudgetIdInvalid           = NewNormalError(NormalSubcategoryBudget, 0, http.StatusBadRequest, "budget id is invalid")
+	ErrBudgetNotFound            = NewNormalError(NormalSubcategoryBudget, 1, http.StatusBadRequest, "budget not found")
+	ErrBudgetCategoryTypeInvalid = NewNormalError(NormalSubcategoryBudget, 2, http.StatusBadRequest, "budget category must be expense category")
+	ErrBudgetAlreadyExists       = NewNormalError(NormalSubcategoryBudget, 3, http.StatusBadRequest, "budget with same scope already exists")
+)
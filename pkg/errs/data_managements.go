@@ -6,5 +6,15 @@ import (
 
 // Error codes related to data management
 var (
-	ErrDataExportNotAllowed = NewNormalError(NormalSubcategoryDataManagement, 1, http.StatusBadRequest, "data export not allowed")
+	ErrDataExportNotAllowed              = NewNormalError(NormalSubcategoryDataManagement, 1, http.StatusBadRequest, "data export not allowed")
+	ErrDataImportNotAllowed              = NewNormalError(NormalSubcategoryDataManagement, 2, http.StatusBadRequest, "data import not allowed")
+	ErrImportFileIsEmpty                 = NewNormalError(NormalSubcategoryDataManagement, 3, http.StatusBadRequest, "import file is empty")
+	ErrImportFileFormatInvalid           = NewNormalError(NormalSubcategoryDataManagement, 4, http.StatusBadRequest, "import file format is invalid")
+	ErrImportedAccountNotFound           = NewNormalError(NormalSubcategoryDataManagement, 5, http.StatusBadRequest, "account in import file not found")
+	ErrImportedCategoryNotFound          = NewNormalError(NormalSubcategoryDataManagement, 6, http.StatusBadRequest, "transaction category in import file not found")
+	ErrImportedTagNotFound               = NewNormalError(NormalSubcategoryDataManagement, 7, http.StatusBadRequest, "transaction tag in import file not found")
+	ErrImportedTransactionTypeInvalid    = NewNormalError(NormalSubcategoryDataManagement, 8, http.StatusBadRequest, "transaction type in import file is invalid")
+	ErrImportFileTooLarge                = NewNormalError(NormalSubcategoryDataManagement, 9, http.StatusBadRequest, "import file is too large")
+	ErrImportFileCurrencyNotMatchAccount = NewNormalError(NormalSubcategoryDataManagement, 10, http.StatusBadRequest, "currency of import file does not match account")
+	ErrDataExportFormatInvalid           = NewNormalError(NormalSubcategoryDataManagement, 11, http.StatusBadRequest, "data export format is invalid")
 )
@@ -15,6 +15,7 @@ const (
 	SystemSubcategorySetting  = 1
 	SystemSubcategoryDatabase = 2
 	SystemSubcategoryMail     = 3
+	SystemSubcategoryStorage  = 4
 )
 
 // Sub categories of normal error
@@ -28,6 +29,19 @@ const (
 	NormalSubcategoryCategory       = 6
 	NormalSubcategoryTag            = 7
 	NormalSubcategoryDataManagement = 8
+	NormalSubcategorySchedule       = 9
+	NormalSubcategoryBudget         = 10
+	NormalSubcategoryExchangeRate   = 11
+	NormalSubcategoryAttachment     = 12
+	NormalSubcategoryRule           = 13
+	NormalSubcategoryBookLock       = 14
+	NormalSubcategoryReconciliation = 15
+	NormalSubcategoryLoan           = 16
+	NormalSubcategoryInvestment     = 17
+	NormalSubcategorySavingsGoal    = 18
+	NormalSubcategoryBook           = 19
+	NormalSubcategoryOIDC           = 20
+	NormalSubcategoryWebAuthn       = 21
 )
 
 // Error represents the specific error returned to user
@@ -0,0 +1,10 @@
+package errs
+
+import "net/http"
+
+// Error codes related to exchange rates
+var (
+	ErrHistoricalExchangeRatesNotSupported = NewNormalError(NormalSubcategoryExchangeRate, 0, http.StatusBadRequest, "historical exchange rates not supported by current data source")
+	ErrHistoricalExchangeRatesNotFound     = NewNormalError(NormalSubcategoryExchangeRate, 1, http.StatusNotFound, "historical exchange rates not found")
+	ErrExchangeRateDateInvalid             = NewNormalError(NormalSubcategoryExchangeRate, 2, http.StatusBadRequest, "exchange rate date is invalid")
+)
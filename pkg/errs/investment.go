@@ -0,0 +1,18 @@
+package errs
+
+import "net/http"
+
+// Error codes related to investments
+var (
+	ErrInvestmentHoldingIdInvalid           = NewNormalError(NormalSubcategoryInvestment, 0, http.StatusBadRequest, "investment holding id is invalid")
+	ErrInvestmentHoldingNotFound            = NewNormalError(NormalSubcategoryInvestment, 1, http.StatusBadRequest, "investment holding not found")
+	ErrInvestmentHoldingSymbolAlreadyExists = NewNormalError(NormalSubcategoryInvestment, 2, http.StatusBadRequest, "investment holding symbol already exists")
+	ErrInvestmentAccountInvalid             = NewNormalError(NormalSubcategoryInvestment, 3, http.StatusBadRequest, "holdings can only be added to investment account without sub accounts")
+	ErrInvestmentCostMethodInvalid          = NewNormalError(NormalSubcategoryInvestment, 4, http.StatusBadRequest, "investment cost method is invalid")
+	ErrInvestmentTradeIdInvalid             = NewNormalError(NormalSubcategoryInvestment, 5, http.StatusBadRequest, "investment trade id is invalid")
+	ErrInvestmentTradeNotFound              = NewNormalError(NormalSubcategoryInvestment, 6, http.StatusBadRequest, "investment trade not found")
+	ErrInvestmentTradeTypeInvalid           = NewNormalError(NormalSubcategoryInvestment, 7, http.StatusBadRequest, "investment trade type is invalid")
+	ErrInvestmentSellUnitsExceedHolding     = NewNormalError(NormalSubcategoryInvestment, 8, http.StatusBadRequest, "sold units exceed held units")
+	ErrInvestmentPriceIdInvalid             = NewNormalError(NormalSubcategoryInvestment, 9, http.StatusBadRequest, "investment price id is invalid")
+	ErrInvestmentPriceNotFound              = NewNormalError(NormalSubcategoryInvestment, 10, http.StatusBadRequest, "investment price not found")
+)
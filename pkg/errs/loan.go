@@ -0,0 +1,15 @@
+package errs
+
+import "net/http"
+
+// Error codes related to loans
+var (
+	ErrLoanIdInvalid                      = NewNormalError(NormalSubcategoryLoan, 0, http.StatusBadRequest, "loan id is invalid")
+	ErrLoanNotFound                       = NewNormalError(NormalSubcategoryLoan, 1, http.StatusBadRequest, "loan not found")
+	ErrLoanAccountInvalid                 = NewNormalError(NormalSubcategoryLoan, 2, http.StatusBadRequest, "loan can only be set on debt account without sub accounts")
+	ErrLoanAlreadyExists                  = NewNormalError(NormalSubcategoryLoan, 3, http.StatusBadRequest, "account already has a loan")
+	ErrLoanPaymentSettingsInvalid         = NewNormalError(NormalSubcategoryLoan, 4, http.StatusBadRequest, "payment account and categories must be set when creating loan transactions automatically")
+	ErrLoanPaymentAccountInvalid          = NewNormalError(NormalSubcategoryLoan, 5, http.StatusBadRequest, "loan payment account is invalid")
+	ErrLoanPaymentAccountCurrencyNotEqual = NewNormalError(NormalSubcategoryLoan, 6, http.StatusBadRequest, "currency of loan payment account must be equal to loan account")
+	ErrLoanPaymentNotFound                = NewNormalError(NormalSubcategoryLoan, 7, http.StatusBadRequest, "loan payment not found")
+)
@@ -0,0 +1,13 @@
+package errs
+
+import "net/http"
+
+// Error codes related to openid connect single sign-on
+var (
+	ErrOIDCNotEnabled            = NewNormalError(NormalSubcategoryOIDC, 0, http.StatusBadRequest, "single sign-on is not enabled")
+	ErrOIDCProviderRequestFailed = NewNormalError(NormalSubcategoryOIDC, 1, http.StatusBadGateway, "failed to request identity provider")
+	ErrOIDCStateInvalid          = NewNormalError(NormalSubcategoryOIDC, 2, http.StatusBadRequest, "single sign-on state is invalid or expired")
+	ErrOIDCIdTokenInvalid        = NewNormalError(NormalSubcategoryOIDC, 3, http.StatusUnauthorized, "identity token is invalid")
+	ErrOIDCEmailNotVerified      = NewNormalError(NormalSubcategoryOIDC, 4, http.StatusBadRequest, "email of identity provider account is not verified")
+	ErrOIDCUserNotLinked         = NewNormalError(NormalSubcategoryOIDC, 5, http.StatusBadRequest, "no user is linked to this identity provider account")
+)
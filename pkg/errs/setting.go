@@ -11,4 +11,7 @@ var (
 	ErrInvalidExchangeRatesDataSource        = NewSystemError(SystemSubcategorySetting, 4, http.StatusInternalServerError, "invalid exchange rates data source")
 	ErrInvalidMapProvider                    = NewSystemError(SystemSubcategorySetting, 5, http.StatusInternalServerError, "invalid map provider")
 	ErrInvalidAmapSecurityVerificationMethod = NewSystemError(SystemSubcategorySetting, 6, http.StatusInternalServerError, "invalid amap security verification method")
+	ErrInvalidStorageType                    = NewSystemError(SystemSubcategorySetting, 7, http.StatusInternalServerError, "invalid storage type")
+	ErrInvalidOIDCConfig                     = NewSystemError(SystemSubcategorySetting, 8, http.StatusInternalServerError, "invalid openid connect config")
+	ErrInvalidWebAuthnConfig                 = NewSystemError(SystemSubcategorySetting, 9, http.StatusInternalServerError, "invalid webauthn config")
 )
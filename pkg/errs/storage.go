@@ -0,0 +1,11 @@
+package errs
+
+import "net/http"
+
+// Error codes related to object storage
+var (
+	ErrStorageObjectPathInvalid = NewSystemError(SystemSubcategoryStorage, 0, http.StatusInternalServerError, "object storage path is invalid")
+	ErrStorageObjectNotFound    = NewSystemError(SystemSubcategoryStorage, 1, http.StatusInternalServerError, "object is not found in object storage")
+	ErrStorageRequestFailed     = NewSystemError(SystemSubcategoryStorage, 2, http.StatusInternalServerError, "failed to request object storage")
+	ErrStorageNotInitialized    = NewSystemError(SystemSubcategoryStorage, 3, http.StatusInternalServerError, "object storage is not initialized")
+)
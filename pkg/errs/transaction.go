@@ -21,4 +21,11 @@ var (
 	ErrCannotCreateTransactionWithThisTransactionTime      = NewNormalError(NormalSubcategoryTransaction, 14, http.StatusBadRequest, "cannot add transaction with this transaction time")
 	ErrCannotModifyTransactionWithThisTransactionTime      = NewNormalError(NormalSubcategoryTransaction, 15, http.StatusBadRequest, "cannot modify transaction with this transaction time")
 	ErrCannotDeleteTransactionWithThisTransactionTime      = NewNormalError(NormalSubcategoryTransaction, 16, http.StatusBadRequest, "cannot delete transaction with this transaction time")
+	ErrTransactionSplitsNotAllowed                         = NewNormalError(NormalSubcategoryTransaction, 17, http.StatusBadRequest, "only income or expense transaction can be split")
+	ErrTransactionSplitsTooFew                             = NewNormalError(NormalSubcategoryTransaction, 18, http.StatusBadRequest, "transaction must have at least two split lines")
+	ErrTransactionSplitsTooMuch                            = NewNormalError(NormalSubcategoryTransaction, 19, http.StatusBadRequest, "transaction has too many split lines")
+	ErrTransactionSplitAmountInvalid                       = NewNormalError(NormalSubcategoryTransaction, 20, http.StatusBadRequest, "transaction split line amount is invalid")
+	ErrTransactionSplitAmountsNotEqualToTotal              = NewNormalError(NormalSubcategoryTransaction, 21, http.StatusBadRequest, "sum of transaction split line amounts does not equal to transaction amount")
+	ErrCannotRestoreTransactionWithThisTransactionTime     = NewNormalError(NormalSubcategoryTransaction, 22, http.StatusBadRequest, "cannot restore transaction with this transaction time")
+	ErrTransactionInClosedPeriod                           = NewNormalError(NormalSubcategoryTransaction, 23, http.StatusBadRequest, "cannot add, modify or delete transaction in closed period")
 )
@@ -0,0 +1,13 @@
+package errs
+
+import "net/http"
+
+// Error codes related to transaction attachments
+var (
+	ErrTransactionAttachmentIdInvalid       = NewNormalError(NormalSubcategoryAttachment, 0, http.StatusBadRequest, "transaction attachment id is invalid")
+	ErrTransactionAttachmentNotFound        = NewNormalError(NormalSubcategoryAttachment, 1, http.StatusBadRequest, "transaction attachment not found")
+	ErrTransactionAttachmentFileIsEmpty     = NewNormalError(NormalSubcategoryAttachment, 2, http.StatusBadRequest, "uploaded attachment file is empty")
+	ErrTransactionAttachmentFileTooLarge    = NewNormalError(NormalSubcategoryAttachment, 3, http.StatusBadRequest, "uploaded attachment file size exceeds the limit")
+	ErrTransactionAttachmentFileTypeInvalid = NewNormalError(NormalSubcategoryAttachment, 4, http.StatusBadRequest, "uploaded attachment file type is not supported")
+	ErrTransactionAttachmentTooMuch         = NewNormalError(NormalSubcategoryAttachment, 5, http.StatusBadRequest, "transaction has too many attachments")
+)
@@ -0,0 +1,14 @@
+package errs
+
+import "net/http"
+
+// Error codes related to transaction rules
+var (
+	ErrTransactionRuleIdInvalid                       = NewNormalError(NormalSubcategoryRule, 0, http.StatusBadRequest, "transaction rule id is invalid")
+	ErrTransactionRuleNotFound                        = NewNormalError(NormalSubcategoryRule, 1, http.StatusBadRequest, "transaction rule not found")
+	ErrTransactionRuleConditionIsEmpty                = NewNormalError(NormalSubcategoryRule, 2, http.StatusBadRequest, "transaction rule must have at least one condition")
+	ErrTransactionRuleActionIsEmpty                   = NewNormalError(NormalSubcategoryRule, 3, http.StatusBadRequest, "transaction rule must have at least one action")
+	ErrTransactionRuleTransactionTypeInvalid          = NewNormalError(NormalSubcategoryRule, 4, http.StatusBadRequest, "transaction rule transaction type is invalid")
+	ErrTransactionRuleAmountRangeInvalid              = NewNormalError(NormalSubcategoryRule, 5, http.StatusBadRequest, "transaction rule max amount must not be less than min amount")
+	ErrTransactionRuleCategoryRequiresTransactionType = NewNormalError(NormalSubcategoryRule, 6, http.StatusBadRequest, "transaction rule must specify transaction type when setting category")
+)
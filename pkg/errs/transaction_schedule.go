@@ -0,0 +1,16 @@
+package errs
+
+import "net/http"
+
+// Error codes related to transaction schedules
+var (
+	ErrTransactionScheduleIdInvalid           = NewNormalError(NormalSubcategorySchedule, 0, http.StatusBadRequest, "transaction schedule id is invalid")
+	ErrTransactionScheduleNotFound            = NewNormalError(NormalSubcategorySchedule, 1, http.StatusBadRequest, "transaction schedule not found")
+	ErrTransactionScheduleTypeInvalid         = NewNormalError(NormalSubcategorySchedule, 2, http.StatusBadRequest, "transaction schedule type is invalid")
+	ErrTransactionScheduleFrequencyInvalid    = NewNormalError(NormalSubcategorySchedule, 3, http.StatusBadRequest, "transaction schedule frequency is invalid")
+	ErrTransactionScheduleDayOfMonthInvalid   = NewNormalError(NormalSubcategorySchedule, 4, http.StatusBadRequest, "transaction schedule day of month is invalid")
+	ErrTransactionScheduleEndTimeInvalid      = NewNormalError(NormalSubcategorySchedule, 5, http.StatusBadRequest, "transaction schedule end time must be later than start time")
+	ErrTransactionScheduleHasNoMoreOccurrence = NewNormalError(NormalSubcategorySchedule, 6, http.StatusBadRequest, "transaction schedule has no more occurrence")
+	ErrTransactionScheduleCreatedByLoan       = NewNormalError(NormalSubcategorySchedule, 7, http.StatusBadRequest, "transaction schedule created by loan can only be changed by loan")
+	ErrTransactionScheduleRunConcurrently     = NewNormalError(NormalSubcategorySchedule, 8, http.StatusBadRequest, "transaction schedule has run during modification, please try again")
+)
@@ -0,0 +1,15 @@
+package errs
+
+import "net/http"
+
+// Error codes related to webauthn
+var (
+	ErrWebAuthnNotEnabled                      = NewNormalError(NormalSubcategoryWebAuthn, 0, http.StatusBadRequest, "webauthn is not enabled")
+	ErrWebAuthnChallengeInvalid                = NewNormalError(NormalSubcategoryWebAuthn, 1, http.StatusBadRequest, "webauthn challenge is invalid or expired")
+	ErrWebAuthnCredentialInvalid               = NewNormalError(NormalSubcategoryWebAuthn, 2, http.StatusBadRequest, "webauthn credential is invalid")
+	ErrWebAuthnAssertionInvalid                = NewNormalError(NormalSubcategoryWebAuthn, 3, http.StatusUnauthorized, "webauthn assertion is invalid")
+	ErrWebAuthnCredentialIdInvalid             = NewNormalError(NormalSubcategoryWebAuthn, 4, http.StatusBadRequest, "webauthn credential id is invalid")
+	ErrWebAuthnCredentialNotFound              = NewNormalError(NormalSubcategoryWebAuthn, 5, http.StatusBadRequest, "webauthn credential not found")
+	ErrWebAuthnCredentialAlreadyExists         = NewNormalError(NormalSubcategoryWebAuthn, 6, http.StatusBadRequest, "webauthn credential already exists")
+	ErrWebAuthnCredentialAlgorithmNotSupported = NewNormalError(NormalSubcategoryWebAuthn, 7, http.StatusBadRequest, "webauthn credential algorithm is not supported")
+)
@@ -2,6 +2,7 @@ package exchangerates
 
 import (
 	"encoding/json"
+	"fmt"
 	"math"
 	"strings"
 	"time"
@@ -15,6 +16,7 @@ import (
 )
 
 const bankOfCanadaExchangeRateUrl = "https://www.bankofcanada.ca/valet/observations/group/FX_RATES_DAILY/json?recent=1"
+const bankOfCanadaHistoricalExchangeRateUrlFormat = "https://www.bankofcanada.ca/valet/observations/group/FX_RATES_DAILY/json?start_date=%s&end_date=%s"
 const bankOfCanadaExchangeRateReferenceUrl = "https://www.bankofcanada.ca/rates/exchange/daily-exchange-rates/"
 const bankOfCanadaDataSource = "Bank of Canada"
 const bankOfCanadaBaseCurrency = "CAD"
@@ -129,6 +131,43 @@ func (e *BankOfCanadaExchangeRateData) ToLatestExchangeRateResponse(c *core.Cont
 	return latestExchangeRateResp
 }
 
+// ToLatestExchangeRateResponses returns view-objects of every day according to original data from bank of Canada
+func (e *BankOfCanadaExchangeRateData) ToLatestExchangeRateResponses(c *core.Context) []*models.LatestExchangeRateResponse {
+	allObservations := make(map[string][]BankOfCanadaObservationData)
+	allUpdateDates := make([]string, 0)
+
+	for i := 0; i < len(e.Observations); i++ {
+		observation := e.Observations[i]
+		updateDate, ok := observation["d"].(string)
+
+		if !ok {
+			continue
+		}
+
+		if _, exists := allObservations[updateDate]; !exists {
+			allUpdateDates = append(allUpdateDates, updateDate)
+		}
+
+		allObservations[updateDate] = append(allObservations[updateDate], observation)
+	}
+
+	latestExchangeRateResponses := make([]*models.LatestExchangeRateResponse, 0, len(allUpdateDates))
+
+	for i := 0; i < len(allUpdateDates); i++ {
+		exchangeRateData := &BankOfCanadaExchangeRateData{
+			Observations: allObservations[allUpdateDates[i]],
+		}
+
+		latestExchangeRateResponse := exchangeRateData.ToLatestExchangeRateResponse(c)
+
+		if latestExchangeRateResponse != nil {
+			latestExchangeRateResponses = append(latestExchangeRateResponses, latestExchangeRateResponse)
+		}
+	}
+
+	return latestExchangeRateResponses
+}
+
 // GetRequestUrls returns the bank of Canada data source urls
 func (e *BankOfCanadaDataSource) GetRequestUrls() []string {
 	return []string{bankOfCanadaExchangeRateUrl}
@@ -153,3 +192,21 @@ func (e *BankOfCanadaDataSource) Parse(c *core.Context, content []byte) (*models
 
 	return latestExchangeRateResponse, nil
 }
+
+// GetHistoricalRequestUrls returns the bank of Canada data source urls which contain the exchange rates between start date and end date
+func (e *BankOfCanadaDataSource) GetHistoricalRequestUrls(startDate time.Time, endDate time.Time) []string {
+	return []string{fmt.Sprintf(bankOfCanadaHistoricalExchangeRateUrlFormat, startDate.Format("2006-01-02"), endDate.Format("2006-01-02"))}
+}
+
+// ParseHistorical returns the common response entities of every day according to the bank of Canada data source raw response
+func (e *BankOfCanadaDataSource) ParseHistorical(c *core.Context, content []byte) ([]*models.LatestExchangeRateResponse, error) {
+	bankOfCanadaData := &BankOfCanadaExchangeRateData{}
+	err := json.Unmarshal(content, bankOfCanadaData)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[bank_of_canada_datasource.ParseHistorical] failed to parse json data, because %s", err.Error())
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	return bankOfCanadaData.ToLatestExchangeRateResponses(c), nil
+}
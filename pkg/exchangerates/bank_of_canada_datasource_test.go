@@ -63,6 +63,27 @@ func TestBankOfCanadaDataSource_StandardDataExtractExchangeRates(t *testing.T) {
 	})
 }
 
+func TestBankOfCanadaDataSource_HistoricalDataExtractExchangeRates(t *testing.T) {
+	dataSource := &BankOfCanadaDataSource{}
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	actualLatestExchangeRateResponses, err := dataSource.ParseHistorical(context, []byte(bankOfCanadaMinimumRequiredContent))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2, len(actualLatestExchangeRateResponses))
+	assert.Equal(t, 1, len(actualLatestExchangeRateResponses[0].ExchangeRates))
+	assert.Contains(t, actualLatestExchangeRateResponses[0].ExchangeRates, &models.LatestExchangeRate{
+		Currency: "VND",
+		Rate:     "17857.14285714286",
+	})
+	assert.Equal(t, 2, len(actualLatestExchangeRateResponses[1].ExchangeRates))
+	assert.Contains(t, actualLatestExchangeRateResponses[1].ExchangeRates, &models.LatestExchangeRate{
+		Currency: "USD",
+		Rate:     "0.7958615200955034",
+	})
+}
+
 func TestBankOfCanadaDataSource_BlankContent(t *testing.T) {
 	dataSource := &BankOfCanadaDataSource{}
 	context := &core.Context{
@@ -1,6 +1,7 @@
 package exchangerates
 
 import (
+	"fmt"
 	"math"
 	"strings"
 	"time"
@@ -14,6 +15,7 @@ import (
 )
 
 const czechNationalBankDailyExchangeRateUrl = "https://www.cnb.cz/en/financial-markets/foreign-exchange-market/central-bank-exchange-rate-fixing/central-bank-exchange-rate-fixing/daily.txt"
+const czechNationalBankHistoricalDailyExchangeRateUrlFormat = "https://www.cnb.cz/en/financial-markets/foreign-exchange-market/central-bank-exchange-rate-fixing/central-bank-exchange-rate-fixing/daily.txt?date=%s"
 const czechNationalBankMonthlyOtherExchangeRateUrl = "https://www.cnb.cz/en/financial-markets/foreign-exchange-market/fx-rates-of-other-currencies/fx-rates-of-other-currencies/fx_rates.txt"
 const czechNationalBankExchangeRateReferenceUrl = "https://www.cnb.cz/en/financial-markets/foreign-exchange-market/central-bank-exchange-rate-fixing/central-bank-exchange-rate-fixing/"
 const czechNationalBankDataSource = "Česká národní banka"
@@ -115,6 +117,32 @@ func (e *CzechNationalBankDataSource) Parse(c *core.Context, content []byte) (*m
 	return latestExchangeRateResp, nil
 }
 
+// GetHistoricalRequestUrls returns the czech nation bank data source urls of every day between start date and end date, only the exchange rates of commonly traded currencies are supported
+func (e *CzechNationalBankDataSource) GetHistoricalRequestUrls(startDate time.Time, endDate time.Time) []string {
+	urls := make([]string, 0)
+
+	for date := startDate; !date.After(endDate); date = date.AddDate(0, 0, 1) {
+		if date.Weekday() == time.Saturday || date.Weekday() == time.Sunday {
+			continue
+		}
+
+		urls = append(urls, fmt.Sprintf(czechNationalBankHistoricalDailyExchangeRateUrlFormat, date.Format("02.01.2006")))
+	}
+
+	return urls
+}
+
+// ParseHistorical returns the common response entities according to the czech nation bank data source raw response
+func (e *CzechNationalBankDataSource) ParseHistorical(c *core.Context, content []byte) ([]*models.LatestExchangeRateResponse, error) {
+	latestExchangeRateResponse, err := e.Parse(c, content)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return []*models.LatestExchangeRateResponse{latestExchangeRateResponse}, nil
+}
+
 func (e *CzechNationalBankDataSource) parseExchangeRate(c *core.Context, line string, currencyCodeColumnIndex int, amountColumnIndex int, rateColumnIndex int) *models.LatestExchangeRate {
 	if len(line) < 1 {
 		return nil
@@ -13,6 +13,9 @@ import (
 )
 
 const euroCentralBankExchangeRateUrl = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-daily.xml"
+const euroCentralBankRecentHistoricalExchangeRateUrl = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-hist-90d.xml"
+const euroCentralBankAllHistoricalExchangeRateUrl = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-hist.xml"
+const euroCentralBankRecentHistoricalDays = 90
 const euroCentralBankExchangeRateReferenceUrl = "https://www.ecb.europa.eu/stats/policy_and_exchange_rates/euro_reference_exchange_rates/html/index.en.html"
 const euroCentralBankDataSource = "European Central Bank"
 const euroCentralBankBaseCurrency = "EUR"
@@ -50,17 +53,35 @@ func (e *EuroCentralBankExchangeRateData) ToLatestExchangeRateResponse(c *core.C
 		return nil
 	}
 
-	latestEuroCentralBankExchangeRate := e.AllExchangeRates[0]
+	return e.AllExchangeRates[0].ToLatestExchangeRateResponse(c)
+}
+
+// ToLatestExchangeRateResponses returns view-objects of every day according to original data from euro central bank
+func (e *EuroCentralBankExchangeRateData) ToLatestExchangeRateResponses(c *core.Context) []*models.LatestExchangeRateResponse {
+	latestExchangeRateResponses := make([]*models.LatestExchangeRateResponse, 0, len(e.AllExchangeRates))
+
+	for i := 0; i < len(e.AllExchangeRates); i++ {
+		latestExchangeRateResponse := e.AllExchangeRates[i].ToLatestExchangeRateResponse(c)
+
+		if latestExchangeRateResponse != nil {
+			latestExchangeRateResponses = append(latestExchangeRateResponses, latestExchangeRateResponse)
+		}
+	}
 
-	if len(latestEuroCentralBankExchangeRate.ExchangeRates) < 1 {
+	return latestExchangeRateResponses
+}
+
+// ToLatestExchangeRateResponse returns a view-object according to the exchange rates of one day from euro central bank
+func (e *EuroCentralBankExchangeRates) ToLatestExchangeRateResponse(c *core.Context) *models.LatestExchangeRateResponse {
+	if len(e.ExchangeRates) < 1 {
 		log.ErrorfWithRequestId(c, "[euro_central_bank_datasource.ToLatestExchangeRateResponse] exchange rates is empty")
 		return nil
 	}
 
-	exchangeRates := make(models.LatestExchangeRateSlice, 0, len(latestEuroCentralBankExchangeRate.ExchangeRates))
+	exchangeRates := make(models.LatestExchangeRateSlice, 0, len(e.ExchangeRates))
 
-	for i := 0; i < len(latestEuroCentralBankExchangeRate.ExchangeRates); i++ {
-		exchangeRate := latestEuroCentralBankExchangeRate.ExchangeRates[i]
+	for i := 0; i < len(e.ExchangeRates); i++ {
+		exchangeRate := e.ExchangeRates[i]
 
 		if _, exists := validators.AllCurrencyNames[exchangeRate.Currency]; !exists {
 			continue
@@ -80,7 +101,7 @@ func (e *EuroCentralBankExchangeRateData) ToLatestExchangeRateResponse(c *core.C
 		return nil
 	}
 
-	updateDateTime := latestEuroCentralBankExchangeRate.Date + " 16" // The reference rates are usually updated around 16:00 CET on every working day
+	updateDateTime := e.Date + " 16" // The reference rates are usually updated around 16:00 CET on every working day
 	updateTime, err := time.ParseInLocation(euroCentralBankDataUpdateDateFormat, updateDateTime, timezone)
 
 	if err != nil {
@@ -131,3 +152,25 @@ func (e *EuroCentralBankDataSource) Parse(c *core.Context, content []byte) (*mod
 
 	return latestExchangeRateResponse, nil
 }
+
+// GetHistoricalRequestUrls returns the euro central bank data source urls which contain the exchange rates between start date and end date
+func (e *EuroCentralBankDataSource) GetHistoricalRequestUrls(startDate time.Time, endDate time.Time) []string {
+	if time.Since(startDate) < euroCentralBankRecentHistoricalDays*24*time.Hour {
+		return []string{euroCentralBankRecentHistoricalExchangeRateUrl}
+	}
+
+	return []string{euroCentralBankAllHistoricalExchangeRateUrl}
+}
+
+// ParseHistorical returns the common response entities of every day according to the euro central bank data source raw response
+func (e *EuroCentralBankDataSource) ParseHistorical(c *core.Context, content []byte) ([]*models.LatestExchangeRateResponse, error) {
+	euroCentralBankData := &EuroCentralBankExchangeRateData{}
+	err := xml.Unmarshal(content, euroCentralBankData)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[euro_central_bank_datasource.ParseHistorical] failed to parse xml data, because %s", err.Error())
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	return euroCentralBankData.ToLatestExchangeRateResponses(c), nil
+}
@@ -49,6 +49,38 @@ func TestEuroCentralBankDataSource_StandardDataExtractExchangeRates(t *testing.T
 	})
 }
 
+func TestEuroCentralBankDataSource_HistoricalDataExtractExchangeRates(t *testing.T) {
+	dataSource := &EuroCentralBankDataSource{}
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	content := "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n" +
+		"<gesmes:Envelope xmlns:gesmes=\"http://www.gesmes.org/xml/2002-08-01\" xmlns=\"http://www.ecb.int/vocabulary/2002-08-01/eurofxref\">\n" +
+		"  <Cube>\n" +
+		"    <Cube time=\"2021-04-01\">\n" +
+		"      <Cube currency=\"USD\" rate=\"1.1746\" />\n" +
+		"    </Cube>\n" +
+		"    <Cube time=\"2021-03-31\">\n" +
+		"      <Cube currency=\"USD\" rate=\"1.1725\" />\n" +
+		"    </Cube>\n" +
+		"  </Cube>\n" +
+		"</gesmes:Envelope>"
+
+	actualLatestExchangeRateResponses, err := dataSource.ParseHistorical(context, []byte(content))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2, len(actualLatestExchangeRateResponses))
+	assert.Contains(t, actualLatestExchangeRateResponses[0].ExchangeRates, &models.LatestExchangeRate{
+		Currency: "USD",
+		Rate:     "1.1746",
+	})
+	assert.Contains(t, actualLatestExchangeRateResponses[1].ExchangeRates, &models.LatestExchangeRate{
+		Currency: "USD",
+		Rate:     "1.1725",
+	})
+	assert.True(t, actualLatestExchangeRateResponses[0].UpdateTime > actualLatestExchangeRateResponses[1].UpdateTime)
+}
+
 func TestEuroCentralBankDataSource_BlankContent(t *testing.T) {
 	dataSource := &EuroCentralBankDataSource{}
 	context := &core.Context{
@@ -1,6 +1,8 @@
 package exchangerates
 
 import (
+	"time"
+
 	"github.com/f97/gofire/pkg/core"
 	"github.com/f97/gofire/pkg/models"
 )
@@ -13,3 +15,12 @@ type ExchangeRatesDataSource interface {
 	// Parse returns the common response entity according to the data source raw response
 	Parse(c *core.Context, content []byte) (*models.LatestExchangeRateResponse, error)
 }
+
+// HistoricalExchangeRatesDataSource defines the structure of exchange rates data source which supports querying historical exchange rates
+type HistoricalExchangeRatesDataSource interface {
+	// GetHistoricalRequestUrls returns the data source urls which contain the exchange rates between start date and end date
+	GetHistoricalRequestUrls(startDate time.Time, endDate time.Time) []string
+
+	// ParseHistorical returns the common response entities of every day according to the data source raw response
+	ParseHistorical(c *core.Context, content []byte) ([]*models.LatestExchangeRateResponse, error)
+}
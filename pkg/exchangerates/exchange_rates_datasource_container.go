@@ -1,41 +1,330 @@
 package exchangerates
 
 import (
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/f97/gofire/pkg/core"
 	"github.com/f97/gofire/pkg/errs"
+	"github.com/f97/gofire/pkg/log"
+	"github.com/f97/gofire/pkg/models"
 	"github.com/f97/gofire/pkg/settings"
 )
 
 // ExchangeRatesDataSourceContainer contains the current exchange rates data source
 type ExchangeRatesDataSourceContainer struct {
-	Current ExchangeRatesDataSource
+	DataSources []ExchangeRatesDataSource
+	CurrentName string
+
+	cacheTTL              time.Duration
+	cacheMutex            sync.Mutex
+	cachedResponse        *models.LatestExchangeRateResponse
+	cachedTime            time.Time
+	lastRefreshFailedTime time.Time
 }
 
+const exchangeRatesRefreshRetryInterval = time.Minute
+
 // Initialize a exchange rates data source container singleton instance
 var (
 	Container = &ExchangeRatesDataSourceContainer{}
 )
 
-// InitializeExchangeRatesDataSource initializes the current exchange rates data source according to the config
+// InitializeExchangeRatesDataSource initializes the exchange rates data sources according to the config, the data sources would be used in order
 func InitializeExchangeRatesDataSource(config *settings.Config) error {
-	if config.ExchangeRatesDataSource == settings.EuroCentralBankDataSource {
-		Container.Current = &EuroCentralBankDataSource{}
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.BankOfCanadaDataSource {
-		Container.Current = &BankOfCanadaDataSource{}
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.ReserveBankOfAustraliaDataSource {
-		Container.Current = &ReserveBankOfAustraliaDataSource{}
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.CzechNationalBankDataSource {
-		Container.Current = &CzechNationalBankDataSource{}
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.NationalBankOfPolandDataSource {
-		Container.Current = &NationalBankOfPolandDataSource{}
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.MonetaryAuthorityOfSingaporeDataSource {
-		Container.Current = &MonetaryAuthorityOfSingaporeDataSource{}
-		return nil
-	}
-
-	return errs.ErrInvalidExchangeRatesDataSource
+	dataSourceNames := config.ExchangeRatesDataSources
+
+	if len(dataSourceNames) < 1 {
+		dataSourceNames = []string{config.ExchangeRatesDataSource}
+	}
+
+	dataSources := make([]ExchangeRatesDataSource, 0, len(dataSourceNames))
+
+	for i := 0; i < len(dataSourceNames); i++ {
+		dataSource, err := newExchangeRatesDataSource(dataSourceNames[i])
+
+		if err != nil {
+			return err
+		}
+
+		dataSources = append(dataSources, dataSource)
+	}
+
+	Container.DataSources = dataSources
+	Container.CurrentName = config.ExchangeRatesDataSource
+	Container.cacheTTL = time.Duration(config.ExchangeRatesCacheTTL) * time.Second
+
+	return nil
+}
+
+func newExchangeRatesDataSource(dataSourceName string) (ExchangeRatesDataSource, error) {
+	if dataSourceName == settings.EuroCentralBankDataSource {
+		return &EuroCentralBankDataSource{}, nil
+	} else if dataSourceName == settings.BankOfCanadaDataSource {
+		return &BankOfCanadaDataSource{}, nil
+	} else if dataSourceName == settings.ReserveBankOfAustraliaDataSource {
+		return &ReserveBankOfAustraliaDataSource{}, nil
+	} else if dataSourceName == settings.CzechNationalBankDataSource {
+		return &CzechNationalBankDataSource{}, nil
+	} else if dataSourceName == settings.NationalBankOfPolandDataSource {
+		return &NationalBankOfPolandDataSource{}, nil
+	} else if dataSourceName == settings.MonetaryAuthorityOfSingaporeDataSource {
+		return &MonetaryAuthorityOfSingaporeDataSource{}, nil
+	}
+
+	return nil, errs.ErrInvalidExchangeRatesDataSource
+}
+
+// GetLatestExchangeRates returns the latest exchange rates of all currencies from the first available exchange rates data source,
+// the next data source would be requested only if the previous one fails
+func (e *ExchangeRatesDataSourceContainer) GetLatestExchangeRates(c *core.Context) (*models.LatestExchangeRateResponse, error) {
+	if len(e.DataSources) < 1 {
+		return nil, errs.ErrInvalidExchangeRatesDataSource
+	}
+
+	var lastErr error
+
+	for i := 0; i < len(e.DataSources); i++ {
+		exchangeRateResp, err := e.getLatestExchangeRatesFromDataSource(c, e.DataSources[i])
+
+		if err != nil {
+			log.WarnfWithRequestId(c, "[exchange_rates_datasource_container.GetLatestExchangeRates] failed to get latest exchange rates from data source #%d, because %s", i+1, err.Error())
+			lastErr = err
+			continue
+		}
+
+		return exchangeRateResp, nil
+	}
+
+	return nil, lastErr
+}
+
+// GetHistoricalExchangeRates returns the exchange rates of every day between start date and end date from the first available exchange rates data source which supports historical exchange rates
+func (e *ExchangeRatesDataSourceContainer) GetHistoricalExchangeRates(c *core.Context, startDate time.Time, endDate time.Time) ([]*models.LatestExchangeRateResponse, error) {
+	if len(e.DataSources) < 1 {
+		return nil, errs.ErrInvalidExchangeRatesDataSource
+	}
+
+	var lastErr error = errs.ErrHistoricalExchangeRatesNotSupported
+
+	for i := 0; i < len(e.DataSources); i++ {
+		dataSource, ok := e.DataSources[i].(HistoricalExchangeRatesDataSource)
+
+		if !ok {
+			continue
+		}
+
+		exchangeRateResps, err := e.getHistoricalExchangeRatesFromDataSource(c, dataSource, startDate, endDate)
+
+		if err != nil {
+			log.WarnfWithRequestId(c, "[exchange_rates_datasource_container.GetHistoricalExchangeRates] failed to get historical exchange rates from data source #%d, because %s", i+1, err.Error())
+			lastErr = err
+			continue
+		}
+
+		return exchangeRateResps, nil
+	}
+
+	return nil, lastErr
+}
+
+// GetCachedLatestExchangeRates returns the cached latest exchange rates if it does not expire, otherwise requests the latest exchange rates from the current data source,
+// the last cached exchange rates would be returned with stale flag if failed to request, the second returned value indicates whether the exchange rates are newly requested
+func (e *ExchangeRatesDataSourceContainer) GetCachedLatestExchangeRates(c *core.Context) (*models.LatestExchangeRateResponse, bool, error) {
+	e.cacheMutex.Lock()
+	defer e.cacheMutex.Unlock()
+
+	now := time.Now()
+
+	if e.cachedResponse != nil && now.Sub(e.cachedTime) < e.cacheTTL {
+		return e.cachedResponse, false, nil
+	}
+
+	if e.cachedResponse != nil && now.Sub(e.lastRefreshFailedTime) < exchangeRatesRefreshRetryInterval {
+		return e.getStaleResponse(), false, nil
+	}
+
+	exchangeRateResp, err := e.refreshLatestExchangeRates(c)
+
+	if err != nil {
+		if e.cachedResponse != nil {
+			log.WarnfWithRequestId(c, "[exchange_rates_datasource_container.GetCachedLatestExchangeRates] failed to refresh latest exchange rates, returns stale data which is cached at %d", e.cachedTime.Unix())
+			return e.getStaleResponse(), false, nil
+		}
+
+		return nil, false, err
+	}
+
+	return exchangeRateResp, true, nil
+}
+
+// RefreshLatestExchangeRates requests the latest exchange rates from the current data source and updates the cache
+func (e *ExchangeRatesDataSourceContainer) RefreshLatestExchangeRates(c *core.Context) (*models.LatestExchangeRateResponse, error) {
+	e.cacheMutex.Lock()
+	defer e.cacheMutex.Unlock()
+
+	return e.refreshLatestExchangeRates(c)
+}
+
+// IsCacheEnabled returns whether the latest exchange rates would be cached
+func (e *ExchangeRatesDataSourceContainer) IsCacheEnabled() bool {
+	return e.cacheTTL > 0
+}
+
+// GetCacheTTL returns the time-to-live of the cached latest exchange rates
+func (e *ExchangeRatesDataSourceContainer) GetCacheTTL() time.Duration {
+	return e.cacheTTL
+}
+
+func (e *ExchangeRatesDataSourceContainer) refreshLatestExchangeRates(c *core.Context) (*models.LatestExchangeRateResponse, error) {
+	exchangeRateResp, err := e.GetLatestExchangeRates(c)
+
+	if err != nil {
+		e.lastRefreshFailedTime = time.Now()
+		return nil, err
+	}
+
+	e.cachedResponse = exchangeRateResp
+	e.cachedTime = time.Now()
+	e.lastRefreshFailedTime = time.Time{}
+
+	return exchangeRateResp, nil
+}
+
+func (e *ExchangeRatesDataSourceContainer) getStaleResponse() *models.LatestExchangeRateResponse {
+	staleResponse := *e.cachedResponse
+	staleResponse.Stale = true
+
+	return &staleResponse
+}
+
+func (e *ExchangeRatesDataSourceContainer) getLatestExchangeRatesFromDataSource(c *core.Context, dataSource ExchangeRatesDataSource) (*models.LatestExchangeRateResponse, error) {
+	urls := dataSource.GetRequestUrls()
+	exchangeRateResps := make([]*models.LatestExchangeRateResponse, 0, len(urls))
+
+	for i := 0; i < len(urls); i++ {
+		body, err := e.requestRemoteData(c, urls[i])
+
+		if err != nil {
+			return nil, err
+		}
+
+		exchangeRateResp, err := dataSource.Parse(c, body)
+
+		if err != nil {
+			log.ErrorfWithRequestId(c, "[exchange_rates_datasource_container.getLatestExchangeRatesFromDataSource] failed to parse response, because %s", err.Error())
+			return nil, errs.Or(err, errs.ErrFailedToRequestRemoteApi)
+		}
+
+		if exchangeRateResp == nil {
+			return nil, errs.ErrFailedToRequestRemoteApi
+		}
+
+		exchangeRateResps = append(exchangeRateResps, exchangeRateResp)
+	}
+
+	if len(exchangeRateResps) < 1 {
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	return e.mergeExchangeRateResponses(exchangeRateResps), nil
+}
+
+func (e *ExchangeRatesDataSourceContainer) getHistoricalExchangeRatesFromDataSource(c *core.Context, dataSource HistoricalExchangeRatesDataSource, startDate time.Time, endDate time.Time) ([]*models.LatestExchangeRateResponse, error) {
+	urls := dataSource.GetHistoricalRequestUrls(startDate, endDate)
+	exchangeRateResps := make([]*models.LatestExchangeRateResponse, 0, len(urls))
+
+	for i := 0; i < len(urls); i++ {
+		body, err := e.requestRemoteData(c, urls[i])
+
+		if err != nil {
+			return nil, err
+		}
+
+		historicalExchangeRateResps, err := dataSource.ParseHistorical(c, body)
+
+		if err != nil {
+			log.ErrorfWithRequestId(c, "[exchange_rates_datasource_container.getHistoricalExchangeRatesFromDataSource] failed to parse response, because %s", err.Error())
+			return nil, errs.Or(err, errs.ErrFailedToRequestRemoteApi)
+		}
+
+		for j := 0; j < len(historicalExchangeRateResps); j++ {
+			exchangeRateResp := historicalExchangeRateResps[j]
+			updateDate := time.Unix(exchangeRateResp.UpdateTime, 0).UTC().Format("2006-01-02")
+
+			if updateDate < startDate.Format("2006-01-02") || updateDate > endDate.Format("2006-01-02") {
+				continue
+			}
+
+			exchangeRateResps = append(exchangeRateResps, e.mergeExchangeRateResponses([]*models.LatestExchangeRateResponse{exchangeRateResp}))
+		}
+	}
+
+	return exchangeRateResps, nil
+}
+
+func (e *ExchangeRatesDataSourceContainer) requestRemoteData(c *core.Context, url string) ([]byte, error) {
+	client := &http.Client{
+		Timeout: time.Duration(settings.Container.Current.ExchangeRatesRequestTimeout) * time.Millisecond,
+	}
+
+	resp, err := client.Get(url)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[exchange_rates_datasource_container.requestRemoteData] failed to request exchange rate data, because %s", err.Error())
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		log.ErrorfWithRequestId(c, "[exchange_rates_datasource_container.requestRemoteData] failed to get exchange rate data response, because response code is %d", resp.StatusCode)
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	body, err := io.ReadAll(resp.Body)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[exchange_rates_datasource_container.requestRemoteData] failed to read exchange rate data response, because %s", err.Error())
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	return body, nil
+}
+
+func (e *ExchangeRatesDataSourceContainer) mergeExchangeRateResponses(exchangeRateResps []*models.LatestExchangeRateResponse) *models.LatestExchangeRateResponse {
+	lastExchangeRateResponse := exchangeRateResps[len(exchangeRateResps)-1]
+	allExchangeRatesMap := make(map[string]string)
+
+	for i := 0; i < len(exchangeRateResps); i++ {
+		exchangeRateResp := exchangeRateResps[i]
+
+		for j := 0; j < len(exchangeRateResp.ExchangeRates); j++ {
+			exchangeRate := exchangeRateResp.ExchangeRates[j]
+			allExchangeRatesMap[exchangeRate.Currency] = exchangeRate.Rate
+		}
+	}
+
+	allExchangeRatesMap[lastExchangeRateResponse.BaseCurrency] = "1"
+	allExchangeRates := make(models.LatestExchangeRateSlice, 0, len(allExchangeRatesMap))
+
+	for currency, rate := range allExchangeRatesMap {
+		allExchangeRates = append(allExchangeRates, &models.LatestExchangeRate{
+			Currency: currency,
+			Rate:     rate,
+		})
+	}
+
+	sort.Sort(allExchangeRates)
+
+	return &models.LatestExchangeRateResponse{
+		DataSource:    lastExchangeRateResponse.DataSource,
+		ReferenceUrl:  lastExchangeRateResponse.ReferenceUrl,
+		UpdateTime:    lastExchangeRateResponse.UpdateTime,
+		BaseCurrency:  lastExchangeRateResponse.BaseCurrency,
+		ExchangeRates: allExchangeRates,
+	}
 }
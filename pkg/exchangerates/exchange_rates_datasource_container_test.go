@@ -0,0 +1,109 @@
+package exchangerates
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/f97/gofire/pkg/core"
+	"github.com/f97/gofire/pkg/settings"
+)
+
+type testExchangeRatesDataSource struct {
+	EuroCentralBankDataSource
+	url string
+}
+
+func (e *testExchangeRatesDataSource) GetRequestUrls() []string {
+	return []string{e.url}
+}
+
+func TestExchangeRatesDataSourceContainer_GetCachedLatestExchangeRates(t *testing.T) {
+	requestCount := 0
+	available := true
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestCount++
+
+		if !available {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		_, _ = w.Write([]byte(euroCentralBankMinimumRequiredContent))
+	}))
+	defer server.Close()
+
+	settings.SetCurrentConfig(&settings.Config{ExchangeRatesRequestTimeout: 1000})
+
+	container := &ExchangeRatesDataSourceContainer{
+		DataSources: []ExchangeRatesDataSource{&testExchangeRatesDataSource{url: server.URL}},
+		cacheTTL:    time.Hour,
+	}
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	actualLatestExchangeRateResponse, refreshed, err := container.GetCachedLatestExchangeRates(context)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, true, refreshed)
+	assert.Equal(t, false, actualLatestExchangeRateResponse.Stale)
+	assert.Equal(t, 1, requestCount)
+
+	actualLatestExchangeRateResponse, refreshed, err = container.GetCachedLatestExchangeRates(context)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, false, refreshed)
+	assert.Equal(t, false, actualLatestExchangeRateResponse.Stale)
+	assert.Equal(t, 1, requestCount)
+
+	available = false
+	container.cachedTime = time.Now().Add(-2 * time.Hour)
+
+	actualLatestExchangeRateResponse, refreshed, err = container.GetCachedLatestExchangeRates(context)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, false, refreshed)
+	assert.Equal(t, true, actualLatestExchangeRateResponse.Stale)
+	assert.Equal(t, "EUR", actualLatestExchangeRateResponse.BaseCurrency)
+	assert.Equal(t, 2, requestCount)
+	assert.Equal(t, false, container.cachedResponse.Stale)
+}
+
+func TestExchangeRatesDataSourceContainer_GetLatestExchangeRatesFallback(t *testing.T) {
+	unavailableServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer unavailableServer.Close()
+
+	availableServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(euroCentralBankMinimumRequiredContent))
+	}))
+	defer availableServer.Close()
+
+	settings.SetCurrentConfig(&settings.Config{ExchangeRatesRequestTimeout: 1000})
+
+	container := &ExchangeRatesDataSourceContainer{
+		DataSources: []ExchangeRatesDataSource{
+			&testExchangeRatesDataSource{url: unavailableServer.URL},
+			&testExchangeRatesDataSource{url: availableServer.URL},
+		},
+	}
+	context := &core.Context{
+		Context: &gin.Context{},
+	}
+
+	actualLatestExchangeRateResponse, err := container.GetLatestExchangeRates(context)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "EUR", actualLatestExchangeRateResponse.BaseCurrency)
+	assert.Equal(t, 3, len(actualLatestExchangeRateResponse.ExchangeRates))
+
+	container.DataSources = []ExchangeRatesDataSource{
+		&testExchangeRatesDataSource{url: unavailableServer.URL},
+	}
+
+	_, err = container.GetLatestExchangeRates(context)
+	assert.NotEqual(t, nil, err)
+}
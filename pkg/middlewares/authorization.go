@@ -1,11 +1,15 @@
 package middlewares
 
 import (
+	"net/http"
+	"strings"
+
 	"github.com/golang-jwt/jwt/v5"
 
 	"github.com/f97/gofire/pkg/core"
 	"github.com/f97/gofire/pkg/errs"
 	"github.com/f97/gofire/pkg/log"
+	"github.com/f97/gofire/pkg/models"
 	"github.com/f97/gofire/pkg/services"
 	"github.com/f97/gofire/pkg/utils"
 )
@@ -22,6 +26,31 @@ const (
 
 const tokenQueryStringParam = "token"
 
+const apiV1PathPrefix = "/api/v1/"
+
+// tokenScopeRoute represents the scopes required by personal access token to request apis under the path prefix
+type tokenScopeRoute struct {
+	pathPrefix string
+	readScope  string
+	writeScope string
+}
+
+// tokenScopeRoutes represents all apis which personal access token can request, other apis (e.g. tokens, users) are not allowed
+var tokenScopeRoutes = []tokenScopeRoute{
+	{"accounts/", models.TOKEN_SCOPE_ACCOUNTS_READ, models.TOKEN_SCOPE_ACCOUNTS_WRITE},
+	{"investments/", models.TOKEN_SCOPE_ACCOUNTS_READ, models.TOKEN_SCOPE_ACCOUNTS_WRITE},
+	{"loans/", models.TOKEN_SCOPE_ACCOUNTS_READ, models.TOKEN_SCOPE_ACCOUNTS_WRITE},
+	{"savings_goals/", models.TOKEN_SCOPE_ACCOUNTS_READ, models.TOKEN_SCOPE_ACCOUNTS_WRITE},
+	{"transactions/", models.TOKEN_SCOPE_TRANSACTIONS_READ, models.TOKEN_SCOPE_TRANSACTIONS_WRITE},
+	{"transaction/", models.TOKEN_SCOPE_TRANSACTIONS_READ, models.TOKEN_SCOPE_TRANSACTIONS_WRITE},
+	{"book_locks/", models.TOKEN_SCOPE_TRANSACTIONS_READ, models.TOKEN_SCOPE_TRANSACTIONS_WRITE},
+	{"budgets/", models.TOKEN_SCOPE_BUDGETS_READ, models.TOKEN_SCOPE_BUDGETS_WRITE},
+	{"exchange_rates/", models.TOKEN_SCOPE_EXCHANGE_RATES, ""},
+	{"data/statistics", models.TOKEN_SCOPE_DATA_EXPORT, ""},
+	{"data/export", models.TOKEN_SCOPE_DATA_EXPORT, ""},
+	{"data/import", "", models.TOKEN_SCOPE_DATA_IMPORT},
+}
+
 // JWTAuthorization verifies whether current request is valid by jwt token in header
 func JWTAuthorization(c *core.Context) {
 	jwtAuthorization(c, TOKEN_SOURCE_TYPE_HEADER)
@@ -108,7 +137,15 @@ func jwtAuthorization(c *core.Context, source TokenSourceType) {
 		return
 	}
 
-	if claims.Type != core.USER_TOKEN_TYPE_NORMAL {
+	if claims.Type == core.USER_TOKEN_TYPE_PERSONAL_ACCESS && source == TOKEN_SOURCE_TYPE_HEADER {
+		requiredScope := getRequiredTokenScope(c)
+
+		if requiredScope == "" || !claims.HasScope(requiredScope) {
+			log.WarnfWithRequestId(c, "[authorization.jwtAuthorization] user \"uid:%d\" personal access token does not have scope \"%s\" to request \"%s\"", claims.Uid, requiredScope, c.Request.URL.Path)
+			utils.PrintJsonErrorResult(c, errs.ErrCurrentTokenScopeNotAllowed)
+			return
+		}
+	} else if claims.Type != core.USER_TOKEN_TYPE_NORMAL {
 		log.WarnfWithRequestId(c, "[authorization.jwtAuthorization] user \"uid:%d\" token type is invalid", claims.Uid)
 		utils.PrintJsonErrorResult(c, errs.ErrCurrentInvalidTokenType)
 		return
@@ -139,6 +176,32 @@ func getTokenClaims(c *core.Context, source TokenSourceType) (*core.UserTokenCla
 	return claims, nil
 }
 
+func getRequiredTokenScope(c *core.Context) string {
+	path := c.Request.URL.Path
+
+	if !strings.HasPrefix(path, apiV1PathPrefix) {
+		return ""
+	}
+
+	path = path[len(apiV1PathPrefix):]
+
+	for i := 0; i < len(tokenScopeRoutes); i++ {
+		route := tokenScopeRoutes[i]
+
+		if !strings.HasPrefix(path, route.pathPrefix) {
+			continue
+		}
+
+		if c.Request.Method == http.MethodGet {
+			return route.readScope
+		}
+
+		return route.writeScope
+	}
+
+	return ""
+}
+
 func parseToken(c *core.Context, source TokenSourceType) (*jwt.Token, *core.UserTokenClaims, error) {
 	if source == TOKEN_SOURCE_TYPE_ARGUMENT {
 		return services.Tokens.ParseTokenByArgument(c, tokenQueryStringParam)
@@ -0,0 +1,396 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/f97/gofire/pkg/core"
+	"github.com/f97/gofire/pkg/models"
+)
+
+func newTestTokenScopeContext(method string, path string) *core.Context {
+	ginContext, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ginContext.Request = httptest.NewRequest(method, path, nil)
+
+	return &core.Context{Context: ginContext}
+}
+
+func TestGetRequiredTokenScope_ReadAccounts(t *testing.T) {
+	c := newTestTokenScopeContext(http.MethodGet, "/api/v1/accounts/list.json")
+
+	expectedValue := models.TOKEN_SCOPE_ACCOUNTS_READ
+	actualValue := getRequiredTokenScope(c)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestGetRequiredTokenScope_WriteAccounts(t *testing.T) {
+	c := newTestTokenScopeContext(http.MethodPost, "/api/v1/accounts/add.json")
+
+	expectedValue := models.TOKEN_SCOPE_ACCOUNTS_WRITE
+	actualValue := getRequiredTokenScope(c)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestGetRequiredTokenScope_WriteInvestments(t *testing.T) {
+	c := newTestTokenScopeContext(http.MethodPost, "/api/v1/investments/trades/add.json")
+
+	expectedValue := models.TOKEN_SCOPE_ACCOUNTS_WRITE
+	actualValue := getRequiredTokenScope(c)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestGetRequiredTokenScope_ReadLoans(t *testing.T) {
+	c := newTestTokenScopeContext(http.MethodGet, "/api/v1/loans/list.json")
+
+	expectedValue := models.TOKEN_SCOPE_ACCOUNTS_READ
+	actualValue := getRequiredTokenScope(c)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestGetRequiredTokenScope_WriteSavingsGoals(t *testing.T) {
+	c := newTestTokenScopeContext(http.MethodPost, "/api/v1/savings_goals/add.json")
+
+	expectedValue := models.TOKEN_SCOPE_ACCOUNTS_WRITE
+	actualValue := getRequiredTokenScope(c)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestGetRequiredTokenScope_ReadTransactions(t *testing.T) {
+	c := newTestTokenScopeContext(http.MethodGet, "/api/v1/transactions/list.json")
+
+	expectedValue := models.TOKEN_SCOPE_TRANSACTIONS_READ
+	actualValue := getRequiredTokenScope(c)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestGetRequiredTokenScope_WriteTransactions(t *testing.T) {
+	c := newTestTokenScopeContext(http.MethodPost, "/api/v1/transactions/add.json")
+
+	expectedValue := models.TOKEN_SCOPE_TRANSACTIONS_WRITE
+	actualValue := getRequiredTokenScope(c)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestGetRequiredTokenScope_WriteTransactionCategories(t *testing.T) {
+	c := newTestTokenScopeContext(http.MethodPost, "/api/v1/transaction/categories/add.json")
+
+	expectedValue := models.TOKEN_SCOPE_TRANSACTIONS_WRITE
+	actualValue := getRequiredTokenScope(c)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestGetRequiredTokenScope_ReadTransactionTags(t *testing.T) {
+	c := newTestTokenScopeContext(http.MethodGet, "/api/v1/transaction/tags/list.json")
+
+	expectedValue := models.TOKEN_SCOPE_TRANSACTIONS_READ
+	actualValue := getRequiredTokenScope(c)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestGetRequiredTokenScope_WriteBookLocks(t *testing.T) {
+	c := newTestTokenScopeContext(http.MethodPost, "/api/v1/book_locks/close.json")
+
+	expectedValue := models.TOKEN_SCOPE_TRANSACTIONS_WRITE
+	actualValue := getRequiredTokenScope(c)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestGetRequiredTokenScope_ReadBudgets(t *testing.T) {
+	c := newTestTokenScopeContext(http.MethodGet, "/api/v1/budgets/list.json")
+
+	expectedValue := models.TOKEN_SCOPE_BUDGETS_READ
+	actualValue := getRequiredTokenScope(c)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestGetRequiredTokenScope_WriteBudgets(t *testing.T) {
+	c := newTestTokenScopeContext(http.MethodPost, "/api/v1/budgets/add.json")
+
+	expectedValue := models.TOKEN_SCOPE_BUDGETS_WRITE
+	actualValue := getRequiredTokenScope(c)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestGetRequiredTokenScope_ReadExchangeRates(t *testing.T) {
+	c := newTestTokenScopeContext(http.MethodGet, "/api/v1/exchange_rates/latest.json")
+
+	expectedValue := models.TOKEN_SCOPE_EXCHANGE_RATES
+	actualValue := getRequiredTokenScope(c)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestGetRequiredTokenScope_WriteExchangeRates(t *testing.T) {
+	c := newTestTokenScopeContext(http.MethodPost, "/api/v1/exchange_rates/latest.json")
+
+	expectedValue := ""
+	actualValue := getRequiredTokenScope(c)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestGetRequiredTokenScope_ReadDataStatistics(t *testing.T) {
+	c := newTestTokenScopeContext(http.MethodGet, "/api/v1/data/statistics.json")
+
+	expectedValue := models.TOKEN_SCOPE_DATA_EXPORT
+	actualValue := getRequiredTokenScope(c)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestGetRequiredTokenScope_ExportDataInCsv(t *testing.T) {
+	c := newTestTokenScopeContext(http.MethodGet, "/api/v1/data/export.csv")
+
+	expectedValue := models.TOKEN_SCOPE_DATA_EXPORT
+	actualValue := getRequiredTokenScope(c)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestGetRequiredTokenScope_ExportData(t *testing.T) {
+	c := newTestTokenScopeContext(http.MethodGet, "/api/v1/data/export")
+
+	expectedValue := models.TOKEN_SCOPE_DATA_EXPORT
+	actualValue := getRequiredTokenScope(c)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestGetRequiredTokenScope_ImportData(t *testing.T) {
+	c := newTestTokenScopeContext(http.MethodPost, "/api/v1/data/import.json")
+
+	expectedValue := models.TOKEN_SCOPE_DATA_IMPORT
+	actualValue := getRequiredTokenScope(c)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestGetRequiredTokenScope_ImportStatement(t *testing.T) {
+	c := newTestTokenScopeContext(http.MethodPost, "/api/v1/data/import_statement.json")
+
+	expectedValue := models.TOKEN_SCOPE_DATA_IMPORT
+	actualValue := getRequiredTokenScope(c)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestGetRequiredTokenScope_ImportDataByGet(t *testing.T) {
+	c := newTestTokenScopeContext(http.MethodGet, "/api/v1/data/import.json")
+
+	expectedValue := ""
+	actualValue := getRequiredTokenScope(c)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestGetRequiredTokenScope_ClearData(t *testing.T) {
+	c := newTestTokenScopeContext(http.MethodPost, "/api/v1/data/clear.json")
+
+	expectedValue := ""
+	actualValue := getRequiredTokenScope(c)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestGetRequiredTokenScope_ListTokens(t *testing.T) {
+	c := newTestTokenScopeContext(http.MethodGet, "/api/v1/tokens/list.json")
+
+	expectedValue := ""
+	actualValue := getRequiredTokenScope(c)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestGetRequiredTokenScope_CreatePersonalAccessToken(t *testing.T) {
+	c := newTestTokenScopeContext(http.MethodPost, "/api/v1/tokens/personal/add.json")
+
+	expectedValue := ""
+	actualValue := getRequiredTokenScope(c)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestGetRequiredTokenScope_RevokeTokens(t *testing.T) {
+	c := newTestTokenScopeContext(http.MethodPost, "/api/v1/tokens/revoke_all.json")
+
+	expectedValue := ""
+	actualValue := getRequiredTokenScope(c)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestGetRequiredTokenScope_ReadUserProfile(t *testing.T) {
+	c := newTestTokenScopeContext(http.MethodGet, "/api/v1/users/profile/get.json")
+
+	expectedValue := ""
+	actualValue := getRequiredTokenScope(c)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestGetRequiredTokenScope_UpdateUserProfile(t *testing.T) {
+	c := newTestTokenScopeContext(http.MethodPost, "/api/v1/users/profile/update.json")
+
+	expectedValue := ""
+	actualValue := getRequiredTokenScope(c)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestGetRequiredTokenScope_ListBooks(t *testing.T) {
+	c := newTestTokenScopeContext(http.MethodGet, "/api/v1/books/list.json")
+
+	expectedValue := ""
+	actualValue := getRequiredTokenScope(c)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestGetRequiredTokenScope_InviteBookMember(t *testing.T) {
+	c := newTestTokenScopeContext(http.MethodPost, "/api/v1/books/members/invite.json")
+
+	expectedValue := ""
+	actualValue := getRequiredTokenScope(c)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestGetRequiredTokenScope_PathWithoutTrailingSlashOfPrefix(t *testing.T) {
+	c := newTestTokenScopeContext(http.MethodGet, "/api/v1/accounts")
+
+	expectedValue := ""
+	actualValue := getRequiredTokenScope(c)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestGetRequiredTokenScope_PathWithSimilarPrefix(t *testing.T) {
+	c := newTestTokenScopeContext(http.MethodGet, "/api/v1/accounts_backup/list.json")
+
+	expectedValue := ""
+	actualValue := getRequiredTokenScope(c)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestGetRequiredTokenScope_UnknownApi(t *testing.T) {
+	c := newTestTokenScopeContext(http.MethodGet, "/api/v1/unknown/list.json")
+
+	expectedValue := ""
+	actualValue := getRequiredTokenScope(c)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestGetRequiredTokenScope_ApiRoot(t *testing.T) {
+	c := newTestTokenScopeContext(http.MethodGet, "/api/v1/")
+
+	expectedValue := ""
+	actualValue := getRequiredTokenScope(c)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestGetRequiredTokenScope_OtherApiVersion(t *testing.T) {
+	c := newTestTokenScopeContext(http.MethodGet, "/api/v2/accounts/list.json")
+
+	expectedValue := ""
+	actualValue := getRequiredTokenScope(c)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestGetRequiredTokenScope_NotApi(t *testing.T) {
+	c := newTestTokenScopeContext(http.MethodGet, "/desktop/accounts/")
+
+	expectedValue := ""
+	actualValue := getRequiredTokenScope(c)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestGetRequiredTokenScope_ReadOnlyTokenReadAccounts(t *testing.T) {
+	claims := &core.UserTokenClaims{
+		Type:   core.USER_TOKEN_TYPE_PERSONAL_ACCESS,
+		Scopes: []string{models.TOKEN_SCOPE_ACCOUNTS_READ, models.TOKEN_SCOPE_TRANSACTIONS_READ},
+	}
+	c := newTestTokenScopeContext(http.MethodGet, "/api/v1/accounts/list.json")
+	requiredScope := getRequiredTokenScope(c)
+
+	expectedValue := true
+	actualValue := requiredScope != "" && claims.HasScope(requiredScope)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestGetRequiredTokenScope_ReadOnlyTokenAddAccount(t *testing.T) {
+	claims := &core.UserTokenClaims{
+		Type:   core.USER_TOKEN_TYPE_PERSONAL_ACCESS,
+		Scopes: []string{models.TOKEN_SCOPE_ACCOUNTS_READ, models.TOKEN_SCOPE_TRANSACTIONS_READ},
+	}
+	c := newTestTokenScopeContext(http.MethodPost, "/api/v1/accounts/add.json")
+	requiredScope := getRequiredTokenScope(c)
+
+	expectedValue := false
+	actualValue := requiredScope != "" && claims.HasScope(requiredScope)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestGetRequiredTokenScope_ReadOnlyTokenDeleteAccount(t *testing.T) {
+	claims := &core.UserTokenClaims{
+		Type:   core.USER_TOKEN_TYPE_PERSONAL_ACCESS,
+		Scopes: []string{models.TOKEN_SCOPE_ACCOUNTS_READ, models.TOKEN_SCOPE_TRANSACTIONS_READ},
+	}
+	c := newTestTokenScopeContext(http.MethodPost, "/api/v1/accounts/delete.json")
+	requiredScope := getRequiredTokenScope(c)
+
+	expectedValue := false
+	actualValue := requiredScope != "" && claims.HasScope(requiredScope)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestGetRequiredTokenScope_ReadOnlyTokenReadTransactions(t *testing.T) {
+	claims := &core.UserTokenClaims{
+		Type:   core.USER_TOKEN_TYPE_PERSONAL_ACCESS,
+		Scopes: []string{models.TOKEN_SCOPE_ACCOUNTS_READ, models.TOKEN_SCOPE_TRANSACTIONS_READ},
+	}
+	c := newTestTokenScopeContext(http.MethodGet, "/api/v1/transactions/list.json")
+	requiredScope := getRequiredTokenScope(c)
+
+	expectedValue := true
+	actualValue := requiredScope != "" && claims.HasScope(requiredScope)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestGetRequiredTokenScope_ReadOnlyTokenAddTransaction(t *testing.T) {
+	claims := &core.UserTokenClaims{
+		Type:   core.USER_TOKEN_TYPE_PERSONAL_ACCESS,
+		Scopes: []string{models.TOKEN_SCOPE_ACCOUNTS_READ, models.TOKEN_SCOPE_TRANSACTIONS_READ},
+	}
+	c := newTestTokenScopeContext(http.MethodPost, "/api/v1/transactions/add.json")
+	requiredScope := getRequiredTokenScope(c)
+
+	expectedValue := false
+	actualValue := requiredScope != "" && claims.HasScope(requiredScope)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestGetRequiredTokenScope_ReadOnlyTokenReadBudgets(t *testing.T) {
+	claims := &core.UserTokenClaims{
+		Type:   core.USER_TOKEN_TYPE_PERSONAL_ACCESS,
+		Scopes: []string{models.TOKEN_SCOPE_ACCOUNTS_READ, models.TOKEN_SCOPE_TRANSACTIONS_READ},
+	}
+	c := newTestTokenScopeContext(http.MethodGet, "/api/v1/budgets/list.json")
+	requiredScope := getRequiredTokenScope(c)
+
+	expectedValue := false
+	actualValue := requiredScope != "" && claims.HasScope(requiredScope)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestGetRequiredTokenScope_ReadOnlyTokenExportData(t *testing.T) {
+	claims := &core.UserTokenClaims{
+		Type:   core.USER_TOKEN_TYPE_PERSONAL_ACCESS,
+		Scopes: []string{models.TOKEN_SCOPE_ACCOUNTS_READ, models.TOKEN_SCOPE_TRANSACTIONS_READ},
+	}
+	c := newTestTokenScopeContext(http.MethodGet, "/api/v1/data/export.csv")
+	requiredScope := getRequiredTokenScope(c)
+
+	expectedValue := false
+	actualValue := requiredScope != "" && claims.HasScope(requiredScope)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestGetRequiredTokenScope_ReadOnlyTokenListTokens(t *testing.T) {
+	claims := &core.UserTokenClaims{
+		Type:   core.USER_TOKEN_TYPE_PERSONAL_ACCESS,
+		Scopes: []string{models.TOKEN_SCOPE_ACCOUNTS_READ, models.TOKEN_SCOPE_TRANSACTIONS_READ},
+	}
+	c := newTestTokenScopeContext(http.MethodGet, "/api/v1/tokens/list.json")
+	requiredScope := getRequiredTokenScope(c)
+
+	expectedValue := false
+	actualValue := requiredScope != "" && claims.HasScope(requiredScope)
+	assert.Equal(t, expectedValue, actualValue)
+}
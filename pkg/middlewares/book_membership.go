@@ -0,0 +1,47 @@
+package middlewares
+
+import (
+	"github.com/f97/gofire/pkg/core"
+	"github.com/f97/gofire/pkg/errs"
+	"github.com/f97/gofire/pkg/log"
+	"github.com/f97/gofire/pkg/models"
+	"github.com/f97/gofire/pkg/services"
+	"github.com/f97/gofire/pkg/utils"
+)
+
+// BookMembership verifies whether current user is a member of the shared book in header,
+// and sets the book owner uid as the uid of data which current request accesses,
+// the permission of each operation is checked by the services according to the role of current user
+func BookMembership(c *core.Context) {
+	bookIdValue := c.GetHeader(core.BookIdHeaderName)
+
+	if bookIdValue == "" {
+		c.Next()
+		return
+	}
+
+	uid := c.GetCurrentUid()
+	bookUid, err := utils.StringToInt64(bookIdValue)
+
+	if err != nil || bookUid <= 0 {
+		log.WarnfWithRequestId(c, "[book_membership.BookMembership] user \"uid:%d\" requests invalid book id \"%s\"", uid, bookIdValue)
+		utils.PrintJsonErrorResult(c, errs.ErrBookIdInvalid)
+		return
+	}
+
+	if bookUid == uid {
+		c.Next()
+		return
+	}
+
+	err = services.BookMembers.CheckBookMemberRole(c, bookUid, uid, models.BOOK_MEMBER_ROLE_VIEWER)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[book_membership.BookMembership] user \"uid:%d\" cannot access book \"id:%d\", because %s", uid, bookUid, err.Error())
+		utils.PrintJsonErrorResult(c, errs.Or(err, errs.ErrOperationFailed))
+		return
+	}
+
+	c.SetCurrentBookUid(bookUid)
+	c.Next()
+}
@@ -0,0 +1,265 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/f97/gofire/pkg/core"
+	"github.com/f97/gofire/pkg/datastore"
+	"github.com/f97/gofire/pkg/models"
+	"github.com/f97/gofire/pkg/settings"
+)
+
+func initializeTestBookMembers(t *testing.T, members ...*models.BookMember) {
+	config := &settings.Config{
+		DatabaseConfig: &settings.DatabaseConfig{
+			DatabaseType:          settings.Sqlite3DbType,
+			DatabasePath:          filepath.Join(t.TempDir(), "gofire.db"),
+			MaxIdleConnection:     1,
+			MaxOpenConnection:     1,
+			ConnectionMaxLifeTime: 3600,
+		},
+	}
+
+	err := datastore.InitializeDataStore(config)
+	assert.Equal(t, nil, err)
+
+	err = datastore.Container.UserStore.SyncStructs(new(models.BookMember))
+	assert.Equal(t, nil, err)
+
+	for i := 0; i < len(members); i++ {
+		members[i].MemberId = int64(i + 1)
+		_, err = datastore.Container.UserStore.Choose(0).NewSession(&core.Context{Context: &gin.Context{}}).Insert(members[i])
+		assert.Equal(t, nil, err)
+	}
+}
+
+func newTestBookMembershipContext(method string, uid int64, bookId string) (*core.Context, *httptest.ResponseRecorder) {
+	recorder := httptest.NewRecorder()
+	ginContext, _ := gin.CreateTestContext(recorder)
+	ginContext.Request = httptest.NewRequest(method, "/api/v1/accounts/list.json", nil)
+
+	if bookId != "" {
+		ginContext.Request.Header.Set(core.BookIdHeaderName, bookId)
+	}
+
+	c := &core.Context{Context: ginContext}
+	c.SetTokenClaims(&core.UserTokenClaims{Uid: uid})
+
+	return c, recorder
+}
+
+func TestBookMembership_NoBookId(t *testing.T) {
+	initializeTestBookMembers(t)
+
+	c, recorder := newTestBookMembershipContext(http.MethodPost, 1002, "")
+	BookMembership(c)
+
+	expectedValue := false
+	actualValue := c.IsAborted()
+	assert.Equal(t, expectedValue, actualValue)
+
+	expectedStatus := http.StatusOK
+	actualStatus := recorder.Code
+	assert.Equal(t, expectedStatus, actualStatus)
+
+	expectedBookUid := int64(1002)
+	actualBookUid := c.GetCurrentBookUid()
+	assert.Equal(t, expectedBookUid, actualBookUid)
+}
+
+func TestBookMembership_InvalidBookId(t *testing.T) {
+	initializeTestBookMembers(t)
+
+	c, recorder := newTestBookMembershipContext(http.MethodGet, 1002, "abc")
+	BookMembership(c)
+
+	expectedValue := true
+	actualValue := c.IsAborted()
+	assert.Equal(t, expectedValue, actualValue)
+
+	expectedStatus := http.StatusBadRequest
+	actualStatus := recorder.Code
+	assert.Equal(t, expectedStatus, actualStatus)
+
+	expectedBookUid := int64(1002)
+	actualBookUid := c.GetCurrentBookUid()
+	assert.Equal(t, expectedBookUid, actualBookUid)
+}
+
+func TestBookMembership_ZeroBookId(t *testing.T) {
+	initializeTestBookMembers(t)
+
+	c, recorder := newTestBookMembershipContext(http.MethodGet, 1002, "0")
+	BookMembership(c)
+
+	expectedValue := true
+	actualValue := c.IsAborted()
+	assert.Equal(t, expectedValue, actualValue)
+
+	expectedStatus := http.StatusBadRequest
+	actualStatus := recorder.Code
+	assert.Equal(t, expectedStatus, actualStatus)
+
+	expectedBookUid := int64(1002)
+	actualBookUid := c.GetCurrentBookUid()
+	assert.Equal(t, expectedBookUid, actualBookUid)
+}
+
+func TestBookMembership_PersonalBook(t *testing.T) {
+	initializeTestBookMembers(t)
+
+	c, recorder := newTestBookMembershipContext(http.MethodPost, 1001, "1001")
+	BookMembership(c)
+
+	expectedValue := false
+	actualValue := c.IsAborted()
+	assert.Equal(t, expectedValue, actualValue)
+
+	expectedStatus := http.StatusOK
+	actualStatus := recorder.Code
+	assert.Equal(t, expectedStatus, actualStatus)
+
+	expectedBookUid := int64(1001)
+	actualBookUid := c.GetCurrentBookUid()
+	assert.Equal(t, expectedBookUid, actualBookUid)
+}
+
+func TestBookMembership_EditorReads(t *testing.T) {
+	initializeTestBookMembers(t, &models.BookMember{BookUid: 1001, Uid: 1002, Role: models.BOOK_MEMBER_ROLE_EDITOR, Accepted: true, InviterUid: 1001})
+
+	c, recorder := newTestBookMembershipContext(http.MethodGet, 1002, "1001")
+	BookMembership(c)
+
+	expectedValue := false
+	actualValue := c.IsAborted()
+	assert.Equal(t, expectedValue, actualValue)
+
+	expectedStatus := http.StatusOK
+	actualStatus := recorder.Code
+	assert.Equal(t, expectedStatus, actualStatus)
+
+	expectedBookUid := int64(1001)
+	actualBookUid := c.GetCurrentBookUid()
+	assert.Equal(t, expectedBookUid, actualBookUid)
+}
+
+func TestBookMembership_EditorModifies(t *testing.T) {
+	initializeTestBookMembers(t, &models.BookMember{BookUid: 1001, Uid: 1002, Role: models.BOOK_MEMBER_ROLE_EDITOR, Accepted: true, InviterUid: 1001})
+
+	c, recorder := newTestBookMembershipContext(http.MethodPost, 1002, "1001")
+	BookMembership(c)
+
+	expectedValue := false
+	actualValue := c.IsAborted()
+	assert.Equal(t, expectedValue, actualValue)
+
+	expectedStatus := http.StatusOK
+	actualStatus := recorder.Code
+	assert.Equal(t, expectedStatus, actualStatus)
+
+	expectedBookUid := int64(1001)
+	actualBookUid := c.GetCurrentBookUid()
+	assert.Equal(t, expectedBookUid, actualBookUid)
+}
+
+func TestBookMembership_ViewerReads(t *testing.T) {
+	initializeTestBookMembers(t, &models.BookMember{BookUid: 1001, Uid: 1003, Role: models.BOOK_MEMBER_ROLE_VIEWER, Accepted: true, InviterUid: 1001})
+
+	c, recorder := newTestBookMembershipContext(http.MethodGet, 1003, "1001")
+	BookMembership(c)
+
+	expectedValue := false
+	actualValue := c.IsAborted()
+	assert.Equal(t, expectedValue, actualValue)
+
+	expectedStatus := http.StatusOK
+	actualStatus := recorder.Code
+	assert.Equal(t, expectedStatus, actualStatus)
+
+	expectedBookUid := int64(1001)
+	actualBookUid := c.GetCurrentBookUid()
+	assert.Equal(t, expectedBookUid, actualBookUid)
+}
+
+// The role of viewer is checked by services when modifying data
+func TestBookMembership_ViewerModifies(t *testing.T) {
+	initializeTestBookMembers(t, &models.BookMember{BookUid: 1001, Uid: 1003, Role: models.BOOK_MEMBER_ROLE_VIEWER, Accepted: true, InviterUid: 1001})
+
+	c, recorder := newTestBookMembershipContext(http.MethodPost, 1003, "1001")
+	BookMembership(c)
+
+	expectedValue := false
+	actualValue := c.IsAborted()
+	assert.Equal(t, expectedValue, actualValue)
+
+	expectedStatus := http.StatusOK
+	actualStatus := recorder.Code
+	assert.Equal(t, expectedStatus, actualStatus)
+
+	expectedBookUid := int64(1001)
+	actualBookUid := c.GetCurrentBookUid()
+	assert.Equal(t, expectedBookUid, actualBookUid)
+}
+
+func TestBookMembership_InvitationNotAccepted(t *testing.T) {
+	initializeTestBookMembers(t, &models.BookMember{BookUid: 1001, Uid: 1004, Role: models.BOOK_MEMBER_ROLE_EDITOR, Accepted: false, InviterUid: 1001})
+
+	c, recorder := newTestBookMembershipContext(http.MethodGet, 1004, "1001")
+	BookMembership(c)
+
+	expectedValue := true
+	actualValue := c.IsAborted()
+	assert.Equal(t, expectedValue, actualValue)
+
+	expectedStatus := http.StatusForbidden
+	actualStatus := recorder.Code
+	assert.Equal(t, expectedStatus, actualStatus)
+
+	expectedBookUid := int64(1004)
+	actualBookUid := c.GetCurrentBookUid()
+	assert.Equal(t, expectedBookUid, actualBookUid)
+}
+
+func TestBookMembership_NotAMember(t *testing.T) {
+	initializeTestBookMembers(t, &models.BookMember{BookUid: 1001, Uid: 1002, Role: models.BOOK_MEMBER_ROLE_EDITOR, Accepted: true, InviterUid: 1001})
+
+	c, recorder := newTestBookMembershipContext(http.MethodGet, 1005, "1001")
+	BookMembership(c)
+
+	expectedValue := true
+	actualValue := c.IsAborted()
+	assert.Equal(t, expectedValue, actualValue)
+
+	expectedStatus := http.StatusForbidden
+	actualStatus := recorder.Code
+	assert.Equal(t, expectedStatus, actualStatus)
+
+	expectedBookUid := int64(1005)
+	actualBookUid := c.GetCurrentBookUid()
+	assert.Equal(t, expectedBookUid, actualBookUid)
+}
+
+func TestBookMembership_MemberOfOtherBook(t *testing.T) {
+	initializeTestBookMembers(t, &models.BookMember{BookUid: 1001, Uid: 1002, Role: models.BOOK_MEMBER_ROLE_EDITOR, Accepted: true, InviterUid: 1001})
+
+	c, recorder := newTestBookMembershipContext(http.MethodGet, 1001, "1002")
+	BookMembership(c)
+
+	expectedValue := true
+	actualValue := c.IsAborted()
+	assert.Equal(t, expectedValue, actualValue)
+
+	expectedStatus := http.StatusForbidden
+	actualStatus := recorder.Code
+	assert.Equal(t, expectedStatus, actualStatus)
+
+	expectedBookUid := int64(1001)
+	actualBookUid := c.GetCurrentBookUid()
+	assert.Equal(t, expectedBookUid, actualBookUid)
+}
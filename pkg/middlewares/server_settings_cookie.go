@@ -20,6 +20,9 @@ func ServerSettingsCookie(config *settings.Config) core.MiddlewareHandlerFunc {
 			buildBooleanSetting("f", config.EnableUserForgetPassword),
 			buildBooleanSetting("v", config.EnableUserVerifyEmail),
 			buildBooleanSetting("e", config.EnableDataExport),
+			buildBooleanSetting("i", config.EnableDataImport),
+			buildBooleanSetting("w", config.EnableWebAuthn),
+			buildBooleanSetting("o", config.EnableOIDC),
 			buildStringSetting("m", strings.Replace(config.MapProvider, "_", "-", -1)),
 		}
 
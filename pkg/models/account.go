@@ -18,6 +18,16 @@ const (
 	ACCOUNT_CATEGORY_SAVING  		 AccountCategory = 8
 )
 
+// AccountNetWorthInterval represents the interval between two points of net worth series
+type AccountNetWorthInterval byte
+
+// Account net worth intervals
+const (
+	ACCOUNT_NET_WORTH_INTERVAL_DAILY   AccountNetWorthInterval = 1
+	ACCOUNT_NET_WORTH_INTERVAL_WEEKLY  AccountNetWorthInterval = 2
+	ACCOUNT_NET_WORTH_INTERVAL_MONTHLY AccountNetWorthInterval = 3
+)
+
 var assetAccountCategory = map[AccountCategory]bool{
 	ACCOUNT_CATEGORY_CASH:        true,
 	ACCOUNT_CATEGORY_DEBIT_CARD:  true,
@@ -51,48 +61,57 @@ const (
 
 // Account represents account data stored in database
 type Account struct {
-	AccountId       int64           `xorm:"PK"`
-	Uid             int64           `xorm:"INDEX(IDX_account_uid_deleted_parent_account_id_order) NOT NULL"`
-	Deleted         bool            `xorm:"INDEX(IDX_account_uid_deleted_parent_account_id_order) NOT NULL"`
-	Category        AccountCategory `xorm:"NOT NULL"`
-	Type            AccountType     `xorm:"NOT NULL"`
-	ParentAccountId int64           `xorm:"INDEX(IDX_account_uid_deleted_parent_account_id_order) NOT NULL"`
-	Name            string          `xorm:"VARCHAR(32) NOT NULL"`
-	DisplayOrder    int32           `xorm:"INDEX(IDX_account_uid_deleted_parent_account_id_order) NOT NULL"`
-	Icon            int64           `xorm:"NOT NULL"`
-	Color           string          `xorm:"VARCHAR(6) NOT NULL"`
-	Currency        string          `xorm:"VARCHAR(3) NOT NULL"`
-	Balance         int64           `xorm:"NOT NULL"`
-	Comment         string          `xorm:"VARCHAR(255) NOT NULL"`
-	Hidden          bool            `xorm:"NOT NULL"`
-	CreatedUnixTime int64
-	UpdatedUnixTime int64
-	DeletedUnixTime int64
+	AccountId           int64           `xorm:"PK"`
+	Uid                 int64           `xorm:"INDEX(IDX_account_uid_deleted_parent_account_id_order) NOT NULL"`
+	Deleted             bool            `xorm:"INDEX(IDX_account_uid_deleted_parent_account_id_order) NOT NULL"`
+	Category            AccountCategory `xorm:"NOT NULL"`
+	Type                AccountType     `xorm:"NOT NULL"`
+	ParentAccountId     int64           `xorm:"INDEX(IDX_account_uid_deleted_parent_account_id_order) NOT NULL"`
+	Name                string          `xorm:"VARCHAR(32) NOT NULL"`
+	DisplayOrder        int32           `xorm:"INDEX(IDX_account_uid_deleted_parent_account_id_order) NOT NULL"`
+	Icon                int64           `xorm:"NOT NULL"`
+	Color               string          `xorm:"VARCHAR(6) NOT NULL"`
+	Currency            string          `xorm:"VARCHAR(3) NOT NULL"`
+	Balance             int64           `xorm:"NOT NULL"`
+	Comment             string          `xorm:"VARCHAR(255) NOT NULL"`
+	Hidden              bool            `xorm:"NOT NULL"`
+	StatementClosingDay int32           `xorm:"NOT NULL"`
+	PaymentDueDay       int32           `xorm:"NOT NULL"`
+	CreditLimit         int64           `xorm:"NOT NULL"`
+	CreatedUnixTime     int64
+	UpdatedUnixTime     int64
+	DeletedUnixTime     int64
 }
 
 // AccountCreateRequest represents all parameters of account creation request
 type AccountCreateRequest struct {
-	Name        string                  `json:"name" binding:"required,notBlank,max=32"`
-	Category    AccountCategory         `json:"category" binding:"required"`
-	Type        AccountType             `json:"type" binding:"required"`
-	Icon        int64                   `json:"icon,string" binding:"required,min=1"`
-	Color       string                  `json:"color" binding:"required,len=6,validHexRGBColor"`
-	Currency    string                  `json:"currency" binding:"required,len=3,validCurrency"`
-	Balance     int64                   `json:"balance"`
-	Comment     string                  `json:"comment" binding:"max=255"`
-	SubAccounts []*AccountCreateRequest `json:"subAccounts" binding:"omitempty"`
+	Name                string                  `json:"name" binding:"required,notBlank,max=32"`
+	Category            AccountCategory         `json:"category" binding:"required"`
+	Type                AccountType             `json:"type" binding:"required"`
+	Icon                int64                   `json:"icon,string" binding:"required,min=1"`
+	Color               string                  `json:"color" binding:"required,len=6,validHexRGBColor"`
+	Currency            string                  `json:"currency" binding:"required,len=3,validCurrency"`
+	Balance             int64                   `json:"balance"`
+	Comment             string                  `json:"comment" binding:"max=255"`
+	StatementClosingDay int32                   `json:"statementClosingDay" binding:"min=0,max=28"`
+	PaymentDueDay       int32                   `json:"paymentDueDay" binding:"min=0,max=28"`
+	CreditLimit         int64                   `json:"creditLimit" binding:"min=0,max=99999999999"`
+	SubAccounts         []*AccountCreateRequest `json:"subAccounts" binding:"omitempty"`
 }
 
 // AccountModifyRequest represents all parameters of account modification request
 type AccountModifyRequest struct {
-	Id          int64                   `json:"id,string" binding:"required,min=1"`
-	Name        string                  `json:"name" binding:"required,notBlank,max=32"`
-	Category    AccountCategory         `json:"category" binding:"required"`
-	Icon        int64                   `json:"icon,string" binding:"min=1"`
-	Color       string                  `json:"color" binding:"required,len=6,validHexRGBColor"`
-	Comment     string                  `json:"comment" binding:"max=255"`
-	Hidden      bool                    `json:"hidden"`
-	SubAccounts []*AccountModifyRequest `json:"subAccounts" binding:"omitempty"`
+	Id                  int64                   `json:"id,string" binding:"required,min=1"`
+	Name                string                  `json:"name" binding:"required,notBlank,max=32"`
+	Category            AccountCategory         `json:"category" binding:"required"`
+	Icon                int64                   `json:"icon,string" binding:"min=1"`
+	Color               string                  `json:"color" binding:"required,len=6,validHexRGBColor"`
+	Comment             string                  `json:"comment" binding:"max=255"`
+	Hidden              bool                    `json:"hidden"`
+	StatementClosingDay int32                   `json:"statementClosingDay" binding:"min=0,max=28"`
+	PaymentDueDay       int32                   `json:"paymentDueDay" binding:"min=0,max=28"`
+	CreditLimit         int64                   `json:"creditLimit" binding:"min=0,max=99999999999"`
+	SubAccounts         []*AccountModifyRequest `json:"subAccounts" binding:"omitempty"`
 }
 
 // AccountListRequest represents all parameters of account listing request
@@ -127,42 +146,82 @@ type AccountDeleteRequest struct {
 	Id int64 `json:"id,string" binding:"required,min=1"`
 }
 
+// AccountNetWorthRequest represents all parameters of account net worth series request
+type AccountNetWorthRequest struct {
+	Interval  AccountNetWorthInterval `form:"interval" binding:"omitempty,min=1,max=3"`
+	StartTime int64                   `form:"start_time" binding:"min=0"`
+	EndTime   int64                   `form:"end_time" binding:"min=0"`
+	ConvertTo string                  `form:"convert_to" binding:"omitempty,len=3,validCurrency"`
+}
+
+// AccountNetWorthResponse represents a view-object of account net worth series
+type AccountNetWorthResponse struct {
+	Currency                string                         `json:"currency"`
+	ExchangeRateDate        string                         `json:"exchangeRateDate"`
+	UnconvertibleCurrencies []string                       `json:"unconvertibleCurrencies,omitempty"`
+	Items                   []*AccountNetWorthResponseItem `json:"items"`
+}
+
+// AccountNetWorthResponseItem represents total assets, liabilities and net worth at the end of a period
+type AccountNetWorthResponseItem struct {
+	Time             int64 `json:"time"`
+	TotalAssets      int64 `json:"totalAssets"`
+	TotalLiabilities int64 `json:"totalLiabilities"`
+	NetWorth         int64 `json:"netWorth"`
+}
+
 // AccountInfoResponse represents a view-object of account
 type AccountInfoResponse struct {
-	Id           int64                    `json:"id,string"`
-	Name         string                   `json:"name"`
-	ParentId     int64                    `json:"parentId,string"`
-	Category     AccountCategory          `json:"category"`
-	Type         AccountType              `json:"type"`
-	Icon         int64                    `json:"icon,string"`
-	Color        string                   `json:"color"`
-	Currency     string                   `json:"currency"`
-	Balance      int64                    `json:"balance"`
-	Comment      string                   `json:"comment"`
-	DisplayOrder int32                    `json:"displayOrder"`
-	IsAsset      bool                     `json:"isAsset,omitempty"`
-	IsLiability  bool                     `json:"isLiability,omitempty"`
-	Hidden       bool                     `json:"hidden"`
-	SubAccounts  AccountInfoResponseSlice `json:"subAccounts,omitempty"`
+	Id                  int64                    `json:"id,string"`
+	Name                string                   `json:"name"`
+	ParentId            int64                    `json:"parentId,string"`
+	Category            AccountCategory          `json:"category"`
+	Type                AccountType              `json:"type"`
+	Icon                int64                    `json:"icon,string"`
+	Color               string                   `json:"color"`
+	Currency            string                   `json:"currency"`
+	Balance             int64                    `json:"balance"`
+	Comment             string                   `json:"comment"`
+	DisplayOrder        int32                    `json:"displayOrder"`
+	IsAsset             bool                     `json:"isAsset,omitempty"`
+	IsLiability         bool                     `json:"isLiability,omitempty"`
+	Hidden              bool                     `json:"hidden"`
+	StatementClosingDay int32                    `json:"statementClosingDay,omitempty"`
+	PaymentDueDay       int32                    `json:"paymentDueDay,omitempty"`
+	CreditLimit         int64                    `json:"creditLimit,omitempty"`
+	SubAccounts         AccountInfoResponseSlice `json:"subAccounts,omitempty"`
+}
+
+// IsAsset returns whether the account is an asset account
+func (a *Account) IsAsset() bool {
+	return assetAccountCategory[a.Category]
+}
+
+// IsLiability returns whether the account is a liability account
+func (a *Account) IsLiability() bool {
+	return liabilityAccountCategory[a.Category]
 }
 
 // ToAccountInfoResponse returns a view-object according to database model
 func (a *Account) ToAccountInfoResponse() *AccountInfoResponse {
 	return &AccountInfoResponse{
-		Id:           a.AccountId,
-		Name:         a.Name,
-		ParentId:     a.ParentAccountId,
-		Category:     a.Category,
-		Type:         a.Type,
-		Icon:         a.Icon,
-		Color:        a.Color,
-		Currency:     a.Currency,
-		Balance:      a.Balance,
-		Comment:      a.Comment,
-		DisplayOrder: a.DisplayOrder,
-		IsAsset:      assetAccountCategory[a.Category],
-		IsLiability:  liabilityAccountCategory[a.Category],
-		Hidden:       a.Hidden,
+		Id:                  a.AccountId,
+		Name:                a.Name,
+		ParentId:            a.ParentAccountId,
+		Category:            a.Category,
+		Type:                a.Type,
+		Icon:                a.Icon,
+		Color:               a.Color,
+		Currency:            a.Currency,
+		Balance:             a.Balance,
+		Comment:             a.Comment,
+		DisplayOrder:        a.DisplayOrder,
+		IsAsset:             assetAccountCategory[a.Category],
+		IsLiability:         liabilityAccountCategory[a.Category],
+		Hidden:              a.Hidden,
+		StatementClosingDay: a.StatementClosingDay,
+		PaymentDueDay:       a.PaymentDueDay,
+		CreditLimit:         a.CreditLimit,
 	}
 }
 
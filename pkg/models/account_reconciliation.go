@@ -0,0 +1,91 @@
+package models
+
+// AccountReconciliationStatus represents the status of account reconciliation
+type AccountReconciliationStatus byte
+
+// Account reconciliation statuses
+const (
+	ACCOUNT_RECONCILIATION_STATUS_IN_PROGRESS AccountReconciliationStatus = 1
+	ACCOUNT_RECONCILIATION_STATUS_COMPLETED   AccountReconciliationStatus = 2
+)
+
+// AccountReconciliation represents a reconciliation session of account against bank statement stored in database
+type AccountReconciliation struct {
+	ReconciliationId  int64                       `xorm:"PK"`
+	Uid               int64                       `xorm:"INDEX(IDX_account_reconciliation_uid_deleted_account_id_time) NOT NULL"`
+	Deleted           bool                        `xorm:"INDEX(IDX_account_reconciliation_uid_deleted_account_id_time) NOT NULL"`
+	AccountId         int64                       `xorm:"INDEX(IDX_account_reconciliation_uid_deleted_account_id_time) NOT NULL"`
+	StatementUnixTime int64                       `xorm:"INDEX(IDX_account_reconciliation_uid_deleted_account_id_time) NOT NULL"`
+	StatementBalance  int64                       `xorm:"NOT NULL"`
+	BookBalance       int64                       `xorm:"NOT NULL"`
+	ClearedBalance    int64                       `xorm:"NOT NULL"`
+	Status            AccountReconciliationStatus `xorm:"TINYINT NOT NULL"`
+	CreatedUnixTime   int64
+	UpdatedUnixTime   int64
+	CompletedUnixTime int64
+	DeletedUnixTime   int64
+}
+
+// AccountReconciliationListRequest represents all parameters of account reconciliation listing request
+type AccountReconciliationListRequest struct {
+	AccountId int64 `form:"accountId,string" binding:"required,min=1"`
+}
+
+// AccountReconciliationGetRequest represents all parameters of account reconciliation getting request
+type AccountReconciliationGetRequest struct {
+	Id int64 `form:"id,string" binding:"required,min=1"`
+}
+
+// AccountReconciliationStartRequest represents all parameters of account reconciliation starting request
+type AccountReconciliationStartRequest struct {
+	AccountId        int64 `json:"accountId,string" binding:"required,min=1"`
+	StatementTime    int64 `json:"statementTime" binding:"required,min=1"`
+	StatementBalance int64 `json:"statementBalance" binding:"min=-99999999999,max=99999999999"`
+}
+
+// AccountReconciliationClearRequest represents all parameters of marking transactions cleared or uncleared request
+type AccountReconciliationClearRequest struct {
+	Id             int64    `json:"id,string" binding:"required,min=1"`
+	TransactionIds []string `json:"transactionIds" binding:"required,min=1,max=500"`
+	Cleared        bool     `json:"cleared"`
+}
+
+// AccountReconciliationCompleteRequest represents all parameters of account reconciliation completing request
+type AccountReconciliationCompleteRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// AccountReconciliationDeleteRequest represents all parameters of account reconciliation deleting request
+type AccountReconciliationDeleteRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// AccountReconciliationInfoResponse represents a view-object of account reconciliation
+type AccountReconciliationInfoResponse struct {
+	Id                int64                       `json:"id,string"`
+	AccountId         int64                       `json:"accountId,string"`
+	StatementTime     int64                       `json:"statementTime"`
+	StatementBalance  int64                       `json:"statementBalance"`
+	BookBalance       int64                       `json:"bookBalance"`
+	ClearedBalance    int64                       `json:"clearedBalance"`
+	Difference        int64                       `json:"difference"`
+	ClearedDifference int64                       `json:"clearedDifference"`
+	Status            AccountReconciliationStatus `json:"status"`
+	CompletedTime     int64                       `json:"completedTime,omitempty"`
+}
+
+// ToAccountReconciliationInfoResponse returns a view-object according to database model and the specified book balance and cleared balance
+func (r *AccountReconciliation) ToAccountReconciliationInfoResponse(bookBalance int64, clearedBalance int64) *AccountReconciliationInfoResponse {
+	return &AccountReconciliationInfoResponse{
+		Id:                r.ReconciliationId,
+		AccountId:         r.AccountId,
+		StatementTime:     r.StatementUnixTime,
+		StatementBalance:  r.StatementBalance,
+		BookBalance:       bookBalance,
+		ClearedBalance:    clearedBalance,
+		Difference:        r.StatementBalance - bookBalance,
+		ClearedDifference: r.StatementBalance - clearedBalance,
+		Status:            r.Status,
+		CompletedTime:     r.CompletedUnixTime,
+	}
+}
@@ -0,0 +1,55 @@
+package models
+
+// BookLock represents the closed period of user books (or a specified account) stored in database
+type BookLock struct {
+	LockId          int64 `xorm:"PK"`
+	Uid             int64 `xorm:"INDEX(IDX_book_lock_uid_deleted_account_id) NOT NULL"`
+	Deleted         bool  `xorm:"INDEX(IDX_book_lock_uid_deleted_account_id) NOT NULL"`
+	AccountId       int64 `xorm:"INDEX(IDX_book_lock_uid_deleted_account_id) NOT NULL"`
+	ClosedUnixTime  int64 `xorm:"NOT NULL"`
+	CreatedUnixTime int64
+	UpdatedUnixTime int64
+	DeletedUnixTime int64
+}
+
+// BookLockCloseRequest represents all parameters of books closing request
+type BookLockCloseRequest struct {
+	AccountId  int64 `json:"accountId,string" binding:"min=0"`
+	ClosedTime int64 `json:"closedTime" binding:"required,min=1"`
+}
+
+// BookLockReopenRequest represents all parameters of books reopening request
+type BookLockReopenRequest struct {
+	AccountId  int64 `json:"accountId,string" binding:"min=0"`
+	ClosedTime int64 `json:"closedTime" binding:"min=0"`
+}
+
+// BookLockHistoryRequest represents all parameters of book lock revision history getting request
+type BookLockHistoryRequest struct {
+	AccountId int64 `form:"accountId,string" binding:"min=0"`
+}
+
+// BookLockInfoResponse represents a view-object of book lock
+type BookLockInfoResponse struct {
+	Id         int64 `json:"id,string"`
+	AccountId  int64 `json:"accountId,string,omitempty"`
+	ClosedTime int64 `json:"closedTime"`
+}
+
+// IsTransactionLocked returns whether the specified transaction is in the closed period of book lock
+func (l *BookLock) IsTransactionLocked(transactionUnixTime int64, accountId int64, relatedAccountId int64) bool {
+	if transactionUnixTime > l.ClosedUnixTime {
+		return false
+	}
+
+	return l.AccountId == 0 || l.AccountId == accountId || (relatedAccountId > 0 && l.AccountId == relatedAccountId)
+}
+
+// ToBookLockInfoResponse returns a view-object according to database model
+func (l *BookLock) ToBookLockInfoResponse() *BookLockInfoResponse {
+	return &BookLockInfoResponse{
+		Id:         l.LockId,
+		AccountId:  l.AccountId,
+		ClosedTime: l.ClosedUnixTime,
+	}
+}
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBookLockIsTransactionLocked_AllAccountsBeforeClosedTime(t *testing.T) {
+	bookLock := &BookLock{ClosedUnixTime: 1000}
+
+	expectedValue := true
+	actualValue := bookLock.IsTransactionLocked(999, 2, 0)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestBookLockIsTransactionLocked_AllAccountsAtClosedTime(t *testing.T) {
+	bookLock := &BookLock{ClosedUnixTime: 1000}
+
+	expectedValue := true
+	actualValue := bookLock.IsTransactionLocked(1000, 2, 0)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestBookLockIsTransactionLocked_AllAccountsAfterClosedTime(t *testing.T) {
+	bookLock := &BookLock{ClosedUnixTime: 1000}
+
+	expectedValue := false
+	actualValue := bookLock.IsTransactionLocked(1001, 2, 0)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestBookLockIsTransactionLocked_SameAccountBeforeClosedTime(t *testing.T) {
+	bookLock := &BookLock{AccountId: 1, ClosedUnixTime: 1000}
+
+	expectedValue := true
+	actualValue := bookLock.IsTransactionLocked(999, 1, 0)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestBookLockIsTransactionLocked_SameAccountAfterClosedTime(t *testing.T) {
+	bookLock := &BookLock{AccountId: 1, ClosedUnixTime: 1000}
+
+	expectedValue := false
+	actualValue := bookLock.IsTransactionLocked(1001, 1, 0)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestBookLockIsTransactionLocked_OtherAccountBeforeClosedTime(t *testing.T) {
+	bookLock := &BookLock{AccountId: 1, ClosedUnixTime: 1000}
+
+	expectedValue := false
+	actualValue := bookLock.IsTransactionLocked(999, 2, 0)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestBookLockIsTransactionLocked_RelatedAccountBeforeClosedTime(t *testing.T) {
+	bookLock := &BookLock{AccountId: 1, ClosedUnixTime: 1000}
+
+	expectedValue := true
+	actualValue := bookLock.IsTransactionLocked(999, 2, 1)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestBookLockIsTransactionLocked_RelatedAccountAfterClosedTime(t *testing.T) {
+	bookLock := &BookLock{AccountId: 1, ClosedUnixTime: 1000}
+
+	expectedValue := false
+	actualValue := bookLock.IsTransactionLocked(1001, 2, 1)
+	assert.Equal(t, expectedValue, actualValue)
+}
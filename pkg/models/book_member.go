@@ -0,0 +1,92 @@
+package models
+
+// BookMemberRole represents the role of member in shared book
+type BookMemberRole byte
+
+// Book member roles
+const (
+	BOOK_MEMBER_ROLE_OWNER  BookMemberRole = 1
+	BOOK_MEMBER_ROLE_EDITOR BookMemberRole = 2
+	BOOK_MEMBER_ROLE_VIEWER BookMemberRole = 3
+)
+
+// BookMember represents a member of the shared book (all data of the book owner) stored in database
+type BookMember struct {
+	MemberId        int64          `xorm:"PK"`
+	BookUid         int64          `xorm:"UNIQUE(UQE_book_member_book_uid_uid) NOT NULL"`
+	Uid             int64          `xorm:"UNIQUE(UQE_book_member_book_uid_uid) INDEX(IDX_book_member_uid) NOT NULL"`
+	Role            BookMemberRole `xorm:"TINYINT NOT NULL"`
+	Accepted        bool           `xorm:"NOT NULL"`
+	InviterUid      int64          `xorm:"NOT NULL"`
+	CreatedUnixTime int64
+	UpdatedUnixTime int64
+}
+
+// BookMemberInviteRequest represents all parameters of book member inviting request
+type BookMemberInviteRequest struct {
+	LoginName string         `json:"loginName" binding:"required,notBlank,max=100,validUsername|validEmail"`
+	Role      BookMemberRole `json:"role" binding:"required,min=2,max=3"`
+}
+
+// BookMemberModifyRequest represents all parameters of book member role modification request
+type BookMemberModifyRequest struct {
+	Uid  int64          `json:"uid,string" binding:"required,min=1"`
+	Role BookMemberRole `json:"role" binding:"required,min=2,max=3"`
+}
+
+// BookMemberRemoveRequest represents all parameters of book member removing request
+type BookMemberRemoveRequest struct {
+	Uid int64 `json:"uid,string" binding:"required,min=1"`
+}
+
+// BookInvitationRequest represents all parameters of accepting or declining book invitation (or leaving book) request
+type BookInvitationRequest struct {
+	BookId int64 `json:"bookId,string" binding:"required,min=1"`
+}
+
+// BookInfoResponse represents a view-object of shared book which current user can access
+type BookInfoResponse struct {
+	Id            int64          `json:"id,string"`
+	OwnerUsername string         `json:"ownerUsername"`
+	OwnerNickname string         `json:"ownerNickname"`
+	Role          BookMemberRole `json:"role"`
+	Accepted      bool           `json:"accepted"`
+	Personal      bool           `json:"personal"`
+}
+
+// BookMemberInfoResponse represents a view-object of shared book member
+type BookMemberInfoResponse struct {
+	Uid      int64          `json:"uid,string"`
+	Username string         `json:"username"`
+	Nickname string         `json:"nickname"`
+	Role     BookMemberRole `json:"role"`
+	Accepted bool           `json:"accepted"`
+}
+
+// HasPermissionOf returns whether the member of this role has all permissions of the specified role
+func (r BookMemberRole) HasPermissionOf(role BookMemberRole) bool {
+	return r >= BOOK_MEMBER_ROLE_OWNER && r <= role
+}
+
+// ToBookMemberInfoResponse returns a view-object according to database model and member user
+func (m *BookMember) ToBookMemberInfoResponse(user *User) *BookMemberInfoResponse {
+	return &BookMemberInfoResponse{
+		Uid:      m.Uid,
+		Username: user.Username,
+		Nickname: user.Nickname,
+		Role:     m.Role,
+		Accepted: m.Accepted,
+	}
+}
+
+// ToBookInfoResponse returns a view-object according to database model and book owner
+func (m *BookMember) ToBookInfoResponse(owner *User) *BookInfoResponse {
+	return &BookInfoResponse{
+		Id:            m.BookUid,
+		OwnerUsername: owner.Username,
+		OwnerNickname: owner.Nickname,
+		Role:          m.Role,
+		Accepted:      m.Accepted,
+		Personal:      m.BookUid == m.Uid,
+	}
+}
@@ -0,0 +1,161 @@
+package models
+
+import "time"
+
+// BudgetPeriodType represents the period type of budget
+type BudgetPeriodType byte
+
+// Budget period types
+const (
+	BUDGET_PERIOD_TYPE_MONTHLY BudgetPeriodType = 1
+	BUDGET_PERIOD_TYPE_YEARLY  BudgetPeriodType = 2
+)
+
+// Budget represents a spending limit of a transaction category stored in database
+type Budget struct {
+	BudgetId        int64            `xorm:"PK"`
+	Uid             int64            `xorm:"INDEX(IDX_budget_uid_deleted_category_id) NOT NULL"`
+	Deleted         bool             `xorm:"INDEX(IDX_budget_uid_deleted_category_id) NOT NULL"`
+	CategoryId      int64            `xorm:"INDEX(IDX_budget_uid_deleted_category_id) NOT NULL"`
+	AccountId       int64            `xorm:"NOT NULL"`
+	TagId           int64            `xorm:"NOT NULL"`
+	PeriodType      BudgetPeriodType `xorm:"TINYINT NOT NULL"`
+	Currency        string           `xorm:"VARCHAR(3) NOT NULL"`
+	Amount          int64            `xorm:"NOT NULL"`
+	Comment         string           `xorm:"VARCHAR(255) NOT NULL"`
+	DisplayOrder    int32            `xorm:"NOT NULL"`
+	CreatedUnixTime int64
+	UpdatedUnixTime int64
+	DeletedUnixTime int64
+}
+
+// BudgetGetRequest represents all parameters of budget getting request
+type BudgetGetRequest struct {
+	Id int64 `form:"id,string" binding:"required,min=1"`
+}
+
+// BudgetCreateRequest represents all parameters of budget creation request
+type BudgetCreateRequest struct {
+	CategoryId int64            `json:"categoryId,string" binding:"required,min=1"`
+	AccountId  int64            `json:"accountId,string" binding:"min=0"`
+	TagId      int64            `json:"tagId,string" binding:"min=0"`
+	PeriodType BudgetPeriodType `json:"periodType" binding:"required,min=1,max=2"`
+	Currency   string           `json:"currency" binding:"required,len=3,validCurrency"`
+	Amount     int64            `json:"amount" binding:"min=1,max=99999999999"`
+	Comment    string           `json:"comment" binding:"max=255"`
+}
+
+// BudgetModifyRequest represents all parameters of budget modification request
+type BudgetModifyRequest struct {
+	Id         int64            `json:"id,string" binding:"required,min=1"`
+	CategoryId int64            `json:"categoryId,string" binding:"required,min=1"`
+	AccountId  int64            `json:"accountId,string" binding:"min=0"`
+	TagId      int64            `json:"tagId,string" binding:"min=0"`
+	PeriodType BudgetPeriodType `json:"periodType" binding:"required,min=1,max=2"`
+	Currency   string           `json:"currency" binding:"required,len=3,validCurrency"`
+	Amount     int64            `json:"amount" binding:"min=1,max=99999999999"`
+	Comment    string           `json:"comment" binding:"max=255"`
+}
+
+// BudgetDeleteRequest represents all parameters of budget deleting request
+type BudgetDeleteRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// BudgetProgressRequest represents all parameters of budget progress request
+type BudgetProgressRequest struct {
+	Year  int32 `form:"year" binding:"required,min=1"`
+	Month int32 `form:"month" binding:"required,min=1,max=12"`
+}
+
+// BudgetInfoResponse represents a view-object of budget
+type BudgetInfoResponse struct {
+	Id           int64            `json:"id,string"`
+	CategoryId   int64            `json:"categoryId,string"`
+	AccountId    int64            `json:"accountId,string,omitempty"`
+	TagId        int64            `json:"tagId,string,omitempty"`
+	PeriodType   BudgetPeriodType `json:"periodType"`
+	Currency     string           `json:"currency"`
+	Amount       int64            `json:"amount"`
+	Comment      string           `json:"comment"`
+	DisplayOrder int32            `json:"displayOrder"`
+}
+
+// BudgetProgressResponse represents a view-object of budget progress in a period,
+// amounts spent in other currencies are converted by the latest exchange rates and included in spent amount,
+// amounts in currencies which cannot be converted are excluded from spent amount and returned in unconvertible spent amounts
+type BudgetProgressResponse struct {
+	Budget                    *BudgetInfoResponse `json:"budget"`
+	StartTime                 int64               `json:"startTime"`
+	EndTime                   int64               `json:"endTime"`
+	SpentAmount               int64               `json:"spentAmount"`
+	RemainingAmount           int64               `json:"remainingAmount"`
+	ExchangeRateDate          string              `json:"exchangeRateDate,omitempty"`
+	UnconvertibleSpentAmounts map[string]int64    `json:"unconvertibleSpentAmounts,omitempty"`
+}
+
+// GetPeriodTimeRange returns the start and end unix time of the budget period which contains the given year and month
+func (b *Budget) GetPeriodTimeRange(year int32, month int32, timezone *time.Location) (int64, int64) {
+	var startTime, endTime time.Time
+
+	if b.PeriodType == BUDGET_PERIOD_TYPE_YEARLY {
+		startTime = time.Date(int(year), time.January, 1, 0, 0, 0, 0, timezone)
+		endTime = startTime.AddDate(1, 0, 0)
+	} else {
+		startTime = time.Date(int(year), time.Month(month), 1, 0, 0, 0, 0, timezone)
+		endTime = startTime.AddDate(0, 1, 0)
+	}
+
+	return startTime.Unix(), endTime.Unix() - 1
+}
+
+// ToBudgetInfoResponse returns a view-object according to database model
+func (b *Budget) ToBudgetInfoResponse() *BudgetInfoResponse {
+	return &BudgetInfoResponse{
+		Id:           b.BudgetId,
+		CategoryId:   b.CategoryId,
+		AccountId:    b.AccountId,
+		TagId:        b.TagId,
+		PeriodType:   b.PeriodType,
+		Currency:     b.Currency,
+		Amount:       b.Amount,
+		Comment:      b.Comment,
+		DisplayOrder: b.DisplayOrder,
+	}
+}
+
+// BudgetInfoResponseSlice represents the slice data structure of BudgetInfoResponse
+type BudgetInfoResponseSlice []*BudgetInfoResponse
+
+// Len returns the count of items
+func (s BudgetInfoResponseSlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps two items
+func (s BudgetInfoResponseSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less reports whether the first item is less than the second one
+func (s BudgetInfoResponseSlice) Less(i, j int) bool {
+	return s[i].DisplayOrder < s[j].DisplayOrder
+}
+
+// BudgetProgressResponseSlice represents the slice data structure of BudgetProgressResponse
+type BudgetProgressResponseSlice []*BudgetProgressResponse
+
+// Len returns the count of items
+func (s BudgetProgressResponseSlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps two items
+func (s BudgetProgressResponseSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less reports whether the first item is less than the second one
+func (s BudgetProgressResponseSlice) Less(i, j int) bool {
+	return s[i].Budget.DisplayOrder < s[j].Budget.DisplayOrder
+}
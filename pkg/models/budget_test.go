@@ -0,0 +1,41 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBudgetGetPeriodTimeRange_MonthlyPeriod(t *testing.T) {
+	budget := &Budget{PeriodType: BUDGET_PERIOD_TYPE_MONTHLY}
+
+	startTime, endTime := budget.GetPeriodTimeRange(2024, 2, time.UTC)
+	assert.Equal(t, time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC).Unix(), startTime)
+	assert.Equal(t, time.Date(2024, time.February, 29, 23, 59, 59, 0, time.UTC).Unix(), endTime)
+}
+
+func TestBudgetGetPeriodTimeRange_MonthlyPeriodAtEndOfYear(t *testing.T) {
+	budget := &Budget{PeriodType: BUDGET_PERIOD_TYPE_MONTHLY}
+
+	startTime, endTime := budget.GetPeriodTimeRange(2023, 12, time.UTC)
+	assert.Equal(t, time.Date(2023, time.December, 1, 0, 0, 0, 0, time.UTC).Unix(), startTime)
+	assert.Equal(t, time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC).Unix()-1, endTime)
+}
+
+func TestBudgetGetPeriodTimeRange_YearlyPeriod(t *testing.T) {
+	budget := &Budget{PeriodType: BUDGET_PERIOD_TYPE_YEARLY}
+
+	startTime, endTime := budget.GetPeriodTimeRange(2023, 7, time.UTC)
+	assert.Equal(t, time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC).Unix(), startTime)
+	assert.Equal(t, time.Date(2023, time.December, 31, 23, 59, 59, 0, time.UTC).Unix(), endTime)
+}
+
+func TestBudgetGetPeriodTimeRange_ClientTimezone(t *testing.T) {
+	budget := &Budget{PeriodType: BUDGET_PERIOD_TYPE_MONTHLY}
+	timezone := time.FixedZone("Client Timezone", 8*60*60)
+
+	startTime, endTime := budget.GetPeriodTimeRange(2024, 3, timezone)
+	assert.Equal(t, time.Date(2024, time.February, 29, 16, 0, 0, 0, time.UTC).Unix(), startTime)
+	assert.Equal(t, time.Date(2024, time.March, 31, 15, 59, 59, 0, time.UTC).Unix(), endTime)
+}
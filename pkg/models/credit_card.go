@@ -0,0 +1,71 @@
+package models
+
+// CreditCardCycle represents the statement cycle summary of credit card account
+type CreditCardCycle struct {
+	AccountId                 int64
+	CycleStartUnixTime        int64
+	CycleEndUnixTime          int64
+	CycleSpend                int64
+	LastStatementUnixTime     int64
+	LastStatementBalance      int64
+	PaymentsSinceStatement    int64
+	RemainingStatementBalance int64
+	MinimumDue                int64
+	PaymentDueUnixTime        int64
+	DaysUntilDue              int32
+	CreditLimit               int64
+	AvailableCredit           int64
+}
+
+// CreditCardCycleRequest represents all parameters of credit card statement cycle getting request
+type CreditCardCycleRequest struct {
+	Id int64 `form:"id,string" binding:"required,min=1"`
+}
+
+// CreditCardPaymentRequest represents all parameters of credit card payment creation request
+type CreditCardPaymentRequest struct {
+	Id                int64  `json:"id,string" binding:"required,min=1"`
+	SourceAccountId   int64  `json:"sourceAccountId,string" binding:"required,min=1"`
+	CategoryId        int64  `json:"categoryId,string" binding:"required,min=1"`
+	Time              int64  `json:"time" binding:"required,min=1"`
+	UtcOffset         int16  `json:"utcOffset" binding:"min=-720,max=840"`
+	SourceAmount      int64  `json:"sourceAmount" binding:"min=0,max=99999999999"`
+	DestinationAmount int64  `json:"destinationAmount" binding:"min=0,max=99999999999"`
+	Comment           string `json:"comment" binding:"max=255"`
+}
+
+// CreditCardCycleInfoResponse represents a view-object of credit card statement cycle
+type CreditCardCycleInfoResponse struct {
+	AccountId                 int64 `json:"accountId,string"`
+	CycleStartTime            int64 `json:"cycleStartTime"`
+	CycleEndTime              int64 `json:"cycleEndTime"`
+	CycleSpend                int64 `json:"cycleSpend"`
+	LastStatementTime         int64 `json:"lastStatementTime"`
+	LastStatementBalance      int64 `json:"lastStatementBalance"`
+	PaymentsSinceStatement    int64 `json:"paymentsSinceStatement"`
+	RemainingStatementBalance int64 `json:"remainingStatementBalance"`
+	MinimumDue                int64 `json:"minimumDue"`
+	PaymentDueTime            int64 `json:"paymentDueTime,omitempty"`
+	DaysUntilDue              int32 `json:"daysUntilDue"`
+	CreditLimit               int64 `json:"creditLimit,omitempty"`
+	AvailableCredit           int64 `json:"availableCredit,omitempty"`
+}
+
+// ToCreditCardCycleInfoResponse returns a view-object according to credit card statement cycle summary
+func (c *CreditCardCycle) ToCreditCardCycleInfoResponse() *CreditCardCycleInfoResponse {
+	return &CreditCardCycleInfoResponse{
+		AccountId:                 c.AccountId,
+		CycleStartTime:            c.CycleStartUnixTime,
+		CycleEndTime:              c.CycleEndUnixTime,
+		CycleSpend:                c.CycleSpend,
+		LastStatementTime:         c.LastStatementUnixTime,
+		LastStatementBalance:      c.LastStatementBalance,
+		PaymentsSinceStatement:    c.PaymentsSinceStatement,
+		RemainingStatementBalance: c.RemainingStatementBalance,
+		MinimumDue:                c.MinimumDue,
+		PaymentDueTime:            c.PaymentDueUnixTime,
+		DaysUntilDue:              c.DaysUntilDue,
+		CreditLimit:               c.CreditLimit,
+		AvailableCredit:           c.AvailableCredit,
+	}
+}
@@ -5,6 +5,12 @@ type ClearDataRequest struct {
 	Password string `json:"password" binding:"omitempty,min=6,max=128"`
 }
 
+// DataExportRequest represents all parameters of data export request
+type DataExportRequest struct {
+	Format             string `form:"format"`
+	IncludeAttachments bool   `form:"include_attachments"`
+}
+
 // DataStatisticsResponse represents a view-object of user data statistic
 type DataStatisticsResponse struct {
 	TotalAccountCount             int64 `json:"totalAccountCount,string"`
@@ -0,0 +1,129 @@
+package models
+
+import "encoding/json"
+
+// DataRevisionObjectType represents the type of object which data revision belongs to
+type DataRevisionObjectType byte
+
+// Data revision object types
+const (
+	DATA_REVISION_OBJECT_TYPE_TRANSACTION DataRevisionObjectType = 1
+	DATA_REVISION_OBJECT_TYPE_ACCOUNT     DataRevisionObjectType = 2
+	DATA_REVISION_OBJECT_TYPE_CATEGORY    DataRevisionObjectType = 3
+	DATA_REVISION_OBJECT_TYPE_TAG         DataRevisionObjectType = 4
+	DATA_REVISION_OBJECT_TYPE_BOOK_LOCK   DataRevisionObjectType = 5
+)
+
+// DataRevisionAction represents the action which generates data revision
+type DataRevisionAction byte
+
+// Data revision actions
+const (
+	DATA_REVISION_ACTION_CREATE  DataRevisionAction = 1
+	DATA_REVISION_ACTION_MODIFY  DataRevisionAction = 2
+	DATA_REVISION_ACTION_DELETE  DataRevisionAction = 3
+	DATA_REVISION_ACTION_RESTORE DataRevisionAction = 4
+)
+
+// DataRevision represents a revision record of user data stored in database
+type DataRevision struct {
+	RevisionId      int64                  `xorm:"PK"`
+	Uid             int64                  `xorm:"INDEX(IDX_data_revision_uid_object_type_object_id) NOT NULL"`
+	ObjectType      DataRevisionObjectType `xorm:"INDEX(IDX_data_revision_uid_object_type_object_id) TINYINT NOT NULL"`
+	ObjectId        int64                  `xorm:"INDEX(IDX_data_revision_uid_object_type_object_id) NOT NULL"`
+	Action          DataRevisionAction     `xorm:"TINYINT NOT NULL"`
+	Changes         string                 `xorm:"TEXT NOT NULL"`
+	TokenId         string                 `xorm:"VARCHAR(64) NOT NULL"`
+	ClientIp        string                 `xorm:"VARCHAR(39) NOT NULL"`
+	CreatedUnixTime int64
+}
+
+// DataRevisionFieldChange represents the old value and new value of a changed field
+type DataRevisionFieldChange struct {
+	Field    string `json:"field"`
+	OldValue string `json:"oldValue"`
+	NewValue string `json:"newValue"`
+}
+
+// TransactionHistoryRequest represents all parameters of transaction revision history getting request
+type TransactionHistoryRequest struct {
+	Id int64 `form:"id,string" binding:"required,min=1"`
+}
+
+// DataRevisionInfoResponse represents a view-object of data revision
+type DataRevisionInfoResponse struct {
+	Id       int64                      `json:"id,string"`
+	Action   DataRevisionAction         `json:"action"`
+	Changes  []*DataRevisionFieldChange `json:"changes"`
+	TokenId  string                     `json:"tokenId,omitempty"`
+	ClientIp string                     `json:"clientIp,omitempty"`
+	Time     int64                      `json:"time"`
+}
+
+// GetChanges returns the changed fields of data revision
+func (r *DataRevision) GetChanges() []*DataRevisionFieldChange {
+	changes := make([]*DataRevisionFieldChange, 0)
+
+	if r.Changes == "" {
+		return changes
+	}
+
+	err := json.Unmarshal([]byte(r.Changes), &changes)
+
+	if err != nil {
+		return make([]*DataRevisionFieldChange, 0)
+	}
+
+	return changes
+}
+
+// SetChanges sets the changed fields of data revision
+func (r *DataRevision) SetChanges(changes []*DataRevisionFieldChange) {
+	if len(changes) < 1 {
+		r.Changes = ""
+		return
+	}
+
+	data, err := json.Marshal(changes)
+
+	if err != nil {
+		r.Changes = ""
+		return
+	}
+
+	r.Changes = string(data)
+}
+
+// ToDataRevisionInfoResponse returns a view-object according to database model
+func (r *DataRevision) ToDataRevisionInfoResponse() *DataRevisionInfoResponse {
+	return &DataRevisionInfoResponse{
+		Id:       r.RevisionId,
+		Action:   r.Action,
+		Changes:  r.GetChanges(),
+		TokenId:  r.TokenId,
+		ClientIp: r.ClientIp,
+		Time:     r.CreatedUnixTime,
+	}
+}
+
+// DataRevisionInfoResponseSlice represents the slice data structure of DataRevisionInfoResponse
+type DataRevisionInfoResponseSlice []*DataRevisionInfoResponse
+
+// Len returns the count of items
+func (s DataRevisionInfoResponseSlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps two items
+func (s DataRevisionInfoResponseSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less reports whether the first item is less than the second one
+func (s DataRevisionInfoResponseSlice) Less(i, j int) bool {
+	if s[i].Time != s[j].Time {
+		return s[i].Time < s[j].Time
+	}
+
+	return s[i].Id < s[j].Id
+}
@@ -1,6 +1,27 @@
 package models
 
-import "strings"
+import (
+	"strings"
+
+	"github.com/f97/gofire/pkg/utils"
+)
+
+// ExchangeRate represents a historical exchange rate of one currency stored in database
+type ExchangeRate struct {
+	DataSource      string `xorm:"VARCHAR(64) PK"`
+	RateDate        string `xorm:"VARCHAR(10) PK"`
+	Currency        string `xorm:"VARCHAR(3) PK"`
+	BaseCurrency    string `xorm:"VARCHAR(3) NOT NULL"`
+	Rate            string `xorm:"VARCHAR(32) NOT NULL"`
+	UpdateUnixTime  int64
+	CreatedUnixTime int64
+	UpdatedUnixTime int64
+}
+
+// ExchangeRateHistoryRequest represents all parameters of historical exchange rate getting request
+type ExchangeRateHistoryRequest struct {
+	Date string `form:"date" binding:"required"`
+}
 
 // LatestExchangeRateResponse returns a view-object which contains latest exchange rate
 type LatestExchangeRateResponse struct {
@@ -9,6 +30,60 @@ type LatestExchangeRateResponse struct {
 	UpdateTime    int64                   `json:"updateTime"`
 	BaseCurrency  string                  `json:"baseCurrency"`
 	ExchangeRates LatestExchangeRateSlice `json:"exchangeRates"`
+	Stale         bool                    `json:"stale"`
+}
+
+// ExchangeRateHistoryResponse returns a view-object which contains historical exchange rate of specified date
+type ExchangeRateHistoryResponse struct {
+	DataSource    string                  `json:"dataSource"`
+	Date          string                  `json:"date"`
+	UpdateTime    int64                   `json:"updateTime"`
+	BaseCurrency  string                  `json:"baseCurrency"`
+	ExchangeRates LatestExchangeRateSlice `json:"exchangeRates"`
+}
+
+// GetExchangeRate returns the amount of target currency which equals to one unit of source currency
+func (r *ExchangeRateHistoryResponse) GetExchangeRate(sourceCurrency string, targetCurrency string) (float64, bool) {
+	if sourceCurrency == targetCurrency {
+		return 1, true
+	}
+
+	sourceRate := float64(0)
+	targetRate := float64(0)
+
+	for i := 0; i < len(r.ExchangeRates); i++ {
+		exchangeRate := r.ExchangeRates[i]
+
+		if exchangeRate.Currency != sourceCurrency && exchangeRate.Currency != targetCurrency {
+			continue
+		}
+
+		rate, err := utils.StringToFloat64(exchangeRate.Rate)
+
+		if err != nil || rate <= 0 {
+			continue
+		}
+
+		if exchangeRate.Currency == sourceCurrency {
+			sourceRate = rate
+		} else {
+			targetRate = rate
+		}
+	}
+
+	if sourceCurrency == r.BaseCurrency {
+		sourceRate = 1
+	}
+
+	if targetCurrency == r.BaseCurrency {
+		targetRate = 1
+	}
+
+	if sourceRate <= 0 || targetRate <= 0 {
+		return 0, false
+	}
+
+	return targetRate / sourceRate, true
 }
 
 // LatestExchangeRate represents a data pair of currency and exchange rate
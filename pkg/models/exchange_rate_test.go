@@ -0,0 +1,67 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func getTestExchangeRateHistoryResponse() *ExchangeRateHistoryResponse {
+	return &ExchangeRateHistoryResponse{
+		BaseCurrency: "EUR",
+		ExchangeRates: LatestExchangeRateSlice{
+			{Currency: "USD", Rate: "1.25"},
+			{Currency: "CNY", Rate: "7.5"},
+			{Currency: "JPY", Rate: "invalid"},
+			{Currency: "GBP", Rate: "0"},
+		},
+	}
+}
+
+func TestExchangeRateHistoryResponseGetExchangeRate_SameCurrency(t *testing.T) {
+	exchangeRates := getTestExchangeRateHistoryResponse()
+
+	rate, exists := exchangeRates.GetExchangeRate("KRW", "KRW")
+	assert.Equal(t, true, exists)
+	assert.Equal(t, float64(1), rate)
+}
+
+func TestExchangeRateHistoryResponseGetExchangeRate_BaseCurrency(t *testing.T) {
+	exchangeRates := getTestExchangeRateHistoryResponse()
+
+	rate, exists := exchangeRates.GetExchangeRate("EUR", "USD")
+	assert.Equal(t, true, exists)
+	assert.Equal(t, 1.25, rate)
+
+	rate, exists = exchangeRates.GetExchangeRate("USD", "EUR")
+	assert.Equal(t, true, exists)
+	assert.Equal(t, 0.8, rate)
+}
+
+func TestExchangeRateHistoryResponseGetExchangeRate_CrossCurrency(t *testing.T) {
+	exchangeRates := getTestExchangeRateHistoryResponse()
+
+	rate, exists := exchangeRates.GetExchangeRate("USD", "CNY")
+	assert.Equal(t, true, exists)
+	assert.Equal(t, float64(6), rate)
+}
+
+func TestExchangeRateHistoryResponseGetExchangeRate_CurrencyNotExists(t *testing.T) {
+	exchangeRates := getTestExchangeRateHistoryResponse()
+
+	_, exists := exchangeRates.GetExchangeRate("KRW", "USD")
+	assert.Equal(t, false, exists)
+
+	_, exists = exchangeRates.GetExchangeRate("USD", "KRW")
+	assert.Equal(t, false, exists)
+}
+
+func TestExchangeRateHistoryResponseGetExchangeRate_InvalidRate(t *testing.T) {
+	exchangeRates := getTestExchangeRateHistoryResponse()
+
+	_, exists := exchangeRates.GetExchangeRate("JPY", "USD")
+	assert.Equal(t, false, exists)
+
+	_, exists = exchangeRates.GetExchangeRate("USD", "GBP")
+	assert.Equal(t, false, exists)
+}
@@ -0,0 +1,419 @@
+package models
+
+import (
+	"math"
+
+	"github.com/f97/gofire/pkg/errs"
+)
+
+// InvestmentUnitsMultiplier represents the multiplier of investment units, e.g. 150000000 means 1.5 units
+const InvestmentUnitsMultiplier = 100000000
+
+// InvestmentPriceMultiplier represents the multiplier of investment price in cents, e.g. 1234567 means 123.4567 cents per unit
+const InvestmentPriceMultiplier = 10000
+
+// InvestmentCostMethod represents the method of calculating cost basis of sold units
+type InvestmentCostMethod byte
+
+// Investment cost methods
+const (
+	INVESTMENT_COST_METHOD_FIFO         InvestmentCostMethod = 1
+	INVESTMENT_COST_METHOD_AVERAGE_COST InvestmentCostMethod = 2
+)
+
+// InvestmentTradeType represents investment trade type
+type InvestmentTradeType byte
+
+// Investment trade types
+const (
+	INVESTMENT_TRADE_TYPE_BUY  InvestmentTradeType = 1
+	INVESTMENT_TRADE_TYPE_SELL InvestmentTradeType = 2
+)
+
+// InvestmentHolding represents a security held in investment account stored in database
+type InvestmentHolding struct {
+	HoldingId       int64                `xorm:"PK"`
+	Uid             int64                `xorm:"INDEX(IDX_investment_holding_uid_deleted_account_id) NOT NULL"`
+	Deleted         bool                 `xorm:"INDEX(IDX_investment_holding_uid_deleted_account_id) NOT NULL"`
+	AccountId       int64                `xorm:"INDEX(IDX_investment_holding_uid_deleted_account_id) NOT NULL"`
+	Symbol          string               `xorm:"VARCHAR(32) NOT NULL"`
+	Name            string               `xorm:"VARCHAR(64) NOT NULL"`
+	CostMethod      InvestmentCostMethod `xorm:"NOT NULL"`
+	Comment         string               `xorm:"VARCHAR(255) NOT NULL"`
+	CreatedUnixTime int64
+	UpdatedUnixTime int64
+	DeletedUnixTime int64
+}
+
+// InvestmentTrade represents a buy or sell trade of investment holding stored in database
+type InvestmentTrade struct {
+	TradeId         int64               `xorm:"PK"`
+	Uid             int64               `xorm:"INDEX(IDX_investment_trade_uid_deleted_holding_id_time) NOT NULL"`
+	Deleted         bool                `xorm:"INDEX(IDX_investment_trade_uid_deleted_holding_id_time) NOT NULL"`
+	HoldingId       int64               `xorm:"INDEX(IDX_investment_trade_uid_deleted_holding_id_time) NOT NULL"`
+	Type            InvestmentTradeType `xorm:"NOT NULL"`
+	TradeUnixTime   int64               `xorm:"INDEX(IDX_investment_trade_uid_deleted_holding_id_time) NOT NULL"`
+	Units           int64               `xorm:"NOT NULL"`
+	Amount          int64               `xorm:"NOT NULL"`
+	Fee             int64               `xorm:"NOT NULL"`
+	Comment         string              `xorm:"VARCHAR(255) NOT NULL"`
+	CreatedIp       string              `xorm:"VARCHAR(39)"`
+	CreatedUnixTime int64
+	UpdatedUnixTime int64
+	DeletedUnixTime int64
+}
+
+// InvestmentPrice represents a historical price of investment holding stored in database
+type InvestmentPrice struct {
+	PriceId         int64 `xorm:"PK"`
+	Uid             int64 `xorm:"INDEX(IDX_investment_price_uid_deleted_holding_id_time) NOT NULL"`
+	Deleted         bool  `xorm:"INDEX(IDX_investment_price_uid_deleted_holding_id_time) NOT NULL"`
+	HoldingId       int64 `xorm:"INDEX(IDX_investment_price_uid_deleted_holding_id_time) NOT NULL"`
+	PriceUnixTime   int64 `xorm:"INDEX(IDX_investment_price_uid_deleted_holding_id_time) NOT NULL"`
+	Price           int64 `xorm:"NOT NULL"`
+	CreatedUnixTime int64
+	UpdatedUnixTime int64
+	DeletedUnixTime int64
+}
+
+// InvestmentHoldingPerformance represents the units, cost basis, market value and gains of investment holding at specified time
+type InvestmentHoldingPerformance struct {
+	HoldingId      int64
+	Units          int64
+	CostBasis      int64
+	MarketValue    int64
+	UnrealizedGain int64
+	RealizedGain   int64
+	Price          int64
+	PriceUnixTime  int64
+}
+
+// ImportInvestmentPrice represents an investment price parsed from imported file, whose holding is still a symbol
+type ImportInvestmentPrice struct {
+	LineNumber    int
+	Symbol        string
+	PriceUnixTime int64
+	Price         int64
+}
+
+// InvestmentHoldingListRequest represents all parameters of investment holding listing request
+type InvestmentHoldingListRequest struct {
+	AccountId int64 `form:"accountId,string" binding:"min=0"`
+}
+
+// InvestmentHoldingGetRequest represents all parameters of investment holding getting request
+type InvestmentHoldingGetRequest struct {
+	Id int64 `form:"id,string" binding:"required,min=1"`
+}
+
+// InvestmentHoldingCreateRequest represents all parameters of investment holding creation request
+type InvestmentHoldingCreateRequest struct {
+	AccountId  int64                `json:"accountId,string" binding:"required,min=1"`
+	Symbol     string               `json:"symbol" binding:"required,notBlank,max=32"`
+	Name       string               `json:"name" binding:"max=64"`
+	CostMethod InvestmentCostMethod `json:"costMethod" binding:"required"`
+	Comment    string               `json:"comment" binding:"max=255"`
+}
+
+// InvestmentHoldingModifyRequest represents all parameters of investment holding modification request
+type InvestmentHoldingModifyRequest struct {
+	Id         int64                `json:"id,string" binding:"required,min=1"`
+	Symbol     string               `json:"symbol" binding:"required,notBlank,max=32"`
+	Name       string               `json:"name" binding:"max=64"`
+	CostMethod InvestmentCostMethod `json:"costMethod" binding:"required"`
+	Comment    string               `json:"comment" binding:"max=255"`
+}
+
+// InvestmentHoldingDeleteRequest represents all parameters of investment holding deleting request
+type InvestmentHoldingDeleteRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// InvestmentTradeListRequest represents all parameters of investment trade listing request
+type InvestmentTradeListRequest struct {
+	HoldingId int64 `form:"holdingId,string" binding:"required,min=1"`
+}
+
+// InvestmentTradeCreateRequest represents all parameters of investment trade creation request
+type InvestmentTradeCreateRequest struct {
+	HoldingId int64               `json:"holdingId,string" binding:"required,min=1"`
+	Type      InvestmentTradeType `json:"type" binding:"required"`
+	Time      int64               `json:"time" binding:"required,min=1"`
+	Units     int64               `json:"units" binding:"required,min=1"`
+	Amount    int64               `json:"amount" binding:"min=0,max=99999999999"`
+	Fee       int64               `json:"fee" binding:"min=0,max=99999999999"`
+	Comment   string              `json:"comment" binding:"max=255"`
+}
+
+// InvestmentTradeDeleteRequest represents all parameters of investment trade deleting request
+type InvestmentTradeDeleteRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// InvestmentPriceListRequest represents all parameters of investment price listing request
+type InvestmentPriceListRequest struct {
+	HoldingId int64 `form:"holdingId,string" binding:"required,min=1"`
+}
+
+// InvestmentPriceCreateRequest represents all parameters of investment price creation request
+type InvestmentPriceCreateRequest struct {
+	HoldingId int64 `json:"holdingId,string" binding:"required,min=1"`
+	Time      int64 `json:"time" binding:"required,min=1"`
+	Price     int64 `json:"price" binding:"min=0"`
+}
+
+// InvestmentPriceDeleteRequest represents all parameters of investment price deleting request
+type InvestmentPriceDeleteRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// InvestmentPriceImportRequest represents all parameters of investment price file import request
+type InvestmentPriceImportRequest struct {
+	AccountId int64 `form:"accountId,string" binding:"required,min=1"`
+	UtcOffset int16 `form:"utcOffset" binding:"min=-720,max=840"`
+}
+
+// InvestmentPerformanceRequest represents all parameters of investment performance getting request
+type InvestmentPerformanceRequest struct {
+	AccountId int64 `form:"accountId,string" binding:"min=0"`
+}
+
+// InvestmentHoldingInfoResponse represents a view-object of investment holding
+type InvestmentHoldingInfoResponse struct {
+	Id         int64                `json:"id,string"`
+	AccountId  int64                `json:"accountId,string"`
+	Symbol     string               `json:"symbol"`
+	Name       string               `json:"name"`
+	CostMethod InvestmentCostMethod `json:"costMethod"`
+	Comment    string               `json:"comment"`
+}
+
+// InvestmentTradeInfoResponse represents a view-object of investment trade
+type InvestmentTradeInfoResponse struct {
+	Id        int64               `json:"id,string"`
+	HoldingId int64               `json:"holdingId,string"`
+	Type      InvestmentTradeType `json:"type"`
+	Time      int64               `json:"time"`
+	Units     int64               `json:"units"`
+	Amount    int64               `json:"amount"`
+	Fee       int64               `json:"fee"`
+	Comment   string              `json:"comment"`
+}
+
+// InvestmentPriceInfoResponse represents a view-object of investment price
+type InvestmentPriceInfoResponse struct {
+	Id        int64 `json:"id,string"`
+	HoldingId int64 `json:"holdingId,string"`
+	Time      int64 `json:"time"`
+	Price     int64 `json:"price"`
+}
+
+// InvestmentPriceImportResponse represents the result of investment price file import
+type InvestmentPriceImportResponse struct {
+	ImportedCount int `json:"importedCount"`
+	SkippedCount  int `json:"skippedCount"`
+}
+
+// InvestmentHoldingPerformanceResponse represents a view-object of investment holding performance
+type InvestmentHoldingPerformanceResponse struct {
+	HoldingId      int64  `json:"holdingId,string"`
+	AccountId      int64  `json:"accountId,string"`
+	Symbol         string `json:"symbol"`
+	Name           string `json:"name"`
+	Currency       string `json:"currency"`
+	Units          int64  `json:"units"`
+	CostBasis      int64  `json:"costBasis"`
+	MarketValue    int64  `json:"marketValue"`
+	UnrealizedGain int64  `json:"unrealizedGain"`
+	RealizedGain   int64  `json:"realizedGain"`
+	Price          int64  `json:"price"`
+	PriceTime      int64  `json:"priceTime,omitempty"`
+}
+
+// investmentLot represents the units bought in one trade which are not sold yet
+type investmentLot struct {
+	units int64
+	cost  int64
+}
+
+// IsValid returns whether the cost method is supported
+func (m InvestmentCostMethod) IsValid() bool {
+	return m == INVESTMENT_COST_METHOD_FIFO || m == INVESTMENT_COST_METHOD_AVERAGE_COST
+}
+
+// IsValid returns whether the trade type is supported
+func (t InvestmentTradeType) IsValid() bool {
+	return t == INVESTMENT_TRADE_TYPE_BUY || t == INVESTMENT_TRADE_TYPE_SELL
+}
+
+// GetPerformance returns the performance of investment holding at the specified unix time,
+// the trades must be sorted by trade time in ascending order, and the market value is calculated by the latest price not later than the specified time,
+// or the price of the latest trade if there is no such price
+func (h *InvestmentHolding) GetPerformance(trades []*InvestmentTrade, prices []*InvestmentPrice, unixTime int64) (*InvestmentHoldingPerformance, error) {
+	performance := &InvestmentHoldingPerformance{
+		HoldingId: h.HoldingId,
+	}
+
+	lots := make([]*investmentLot, 0)
+
+	for i := 0; i < len(trades); i++ {
+		trade := trades[i]
+
+		if trade.TradeUnixTime > unixTime {
+			break
+		}
+
+		if trade.Type == INVESTMENT_TRADE_TYPE_BUY {
+			lots = append(lots, &investmentLot{
+				units: trade.Units,
+				cost:  trade.Amount + trade.Fee,
+			})
+		} else if trade.Type == INVESTMENT_TRADE_TYPE_SELL {
+			var soldCost int64
+			var err error
+
+			if h.CostMethod == INVESTMENT_COST_METHOD_AVERAGE_COST {
+				lots, soldCost, err = sellInvestmentLotsByAverageCost(lots, trade.Units)
+			} else {
+				lots, soldCost, err = sellInvestmentLotsByFIFO(lots, trade.Units)
+			}
+
+			if err != nil {
+				return nil, err
+			}
+
+			performance.RealizedGain += trade.Amount - trade.Fee - soldCost
+		}
+
+		if trade.Units > 0 {
+			performance.Price = int64(math.Round(float64(trade.Amount) * InvestmentPriceMultiplier * InvestmentUnitsMultiplier / float64(trade.Units)))
+			performance.PriceUnixTime = trade.TradeUnixTime
+		}
+	}
+
+	for i := 0; i < len(prices); i++ {
+		price := prices[i]
+
+		if price.PriceUnixTime <= unixTime && price.PriceUnixTime >= performance.PriceUnixTime {
+			performance.Price = price.Price
+			performance.PriceUnixTime = price.PriceUnixTime
+		}
+	}
+
+	for i := 0; i < len(lots); i++ {
+		performance.Units += lots[i].units
+		performance.CostBasis += lots[i].cost
+	}
+
+	performance.MarketValue = int64(math.Round(float64(performance.Units) / InvestmentUnitsMultiplier * float64(performance.Price) / InvestmentPriceMultiplier))
+	performance.UnrealizedGain = performance.MarketValue - performance.CostBasis
+
+	return performance, nil
+}
+
+// ToInvestmentHoldingInfoResponse returns a view-object according to database model
+func (h *InvestmentHolding) ToInvestmentHoldingInfoResponse() *InvestmentHoldingInfoResponse {
+	return &InvestmentHoldingInfoResponse{
+		Id:         h.HoldingId,
+		AccountId:  h.AccountId,
+		Symbol:     h.Symbol,
+		Name:       h.Name,
+		CostMethod: h.CostMethod,
+		Comment:    h.Comment,
+	}
+}
+
+// ToInvestmentTradeInfoResponse returns a view-object according to database model
+func (t *InvestmentTrade) ToInvestmentTradeInfoResponse() *InvestmentTradeInfoResponse {
+	return &InvestmentTradeInfoResponse{
+		Id:        t.TradeId,
+		HoldingId: t.HoldingId,
+		Type:      t.Type,
+		Time:      t.TradeUnixTime,
+		Units:     t.Units,
+		Amount:    t.Amount,
+		Fee:       t.Fee,
+		Comment:   t.Comment,
+	}
+}
+
+// ToInvestmentPriceInfoResponse returns a view-object according to database model
+func (p *InvestmentPrice) ToInvestmentPriceInfoResponse() *InvestmentPriceInfoResponse {
+	return &InvestmentPriceInfoResponse{
+		Id:        p.PriceId,
+		HoldingId: p.HoldingId,
+		Time:      p.PriceUnixTime,
+		Price:     p.Price,
+	}
+}
+
+// ToInvestmentHoldingPerformanceResponse returns a view-object according to investment holding performance
+func (p *InvestmentHoldingPerformance) ToInvestmentHoldingPerformanceResponse(holding *InvestmentHolding, currency string) *InvestmentHoldingPerformanceResponse {
+	return &InvestmentHoldingPerformanceResponse{
+		HoldingId:      p.HoldingId,
+		AccountId:      holding.AccountId,
+		Symbol:         holding.Symbol,
+		Name:           holding.Name,
+		Currency:       currency,
+		Units:          p.Units,
+		CostBasis:      p.CostBasis,
+		MarketValue:    p.MarketValue,
+		UnrealizedGain: p.UnrealizedGain,
+		RealizedGain:   p.RealizedGain,
+		Price:          p.Price,
+		PriceTime:      p.PriceUnixTime,
+	}
+}
+
+// sellInvestmentLotsByFIFO removes the sold units from the earliest lots and returns the remaining lots and the cost of sold units
+func sellInvestmentLotsByFIFO(lots []*investmentLot, units int64) ([]*investmentLot, int64, error) {
+	soldCost := int64(0)
+
+	for units > 0 {
+		if len(lots) < 1 {
+			return nil, 0, errs.ErrInvestmentSellUnitsExceedHolding
+		}
+
+		lot := lots[0]
+
+		if lot.units <= units {
+			soldCost += lot.cost
+			units -= lot.units
+			lots = lots[1:]
+			continue
+		}
+
+		cost := int64(math.Round(float64(lot.cost) * float64(units) / float64(lot.units)))
+		lots[0] = &investmentLot{
+			units: lot.units - units,
+			cost:  lot.cost - cost,
+		}
+		soldCost += cost
+		units = 0
+	}
+
+	return lots, soldCost, nil
+}
+
+// sellInvestmentLotsByAverageCost merges all lots into one lot and returns the remaining lot and the average cost of sold units
+func sellInvestmentLotsByAverageCost(lots []*investmentLot, units int64) ([]*investmentLot, int64, error) {
+	totalUnits := int64(0)
+	totalCost := int64(0)
+
+	for i := 0; i < len(lots); i++ {
+		totalUnits += lots[i].units
+		totalCost += lots[i].cost
+	}
+
+	if units > totalUnits {
+		return nil, 0, errs.ErrInvestmentSellUnitsExceedHolding
+	}
+
+	if units == totalUnits {
+		return make([]*investmentLot, 0), totalCost, nil
+	}
+
+	soldCost := int64(math.Round(float64(totalCost) * float64(units) / float64(totalUnits)))
+
+	return []*investmentLot{{units: totalUnits - units, cost: totalCost - soldCost}}, soldCost, nil
+}
@@ -0,0 +1,253 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/f97/gofire/pkg/errs"
+)
+
+func getTestInvestmentLots() []*investmentLot {
+	return []*investmentLot{
+		{units: 10 * InvestmentUnitsMultiplier, cost: 1000},
+		{units: 10 * InvestmentUnitsMultiplier, cost: 2000},
+	}
+}
+
+func TestSellInvestmentLotsByFIFO_SellNothing(t *testing.T) {
+	remainingLots, soldCost, err := sellInvestmentLotsByFIFO(getTestInvestmentLots(), 0)
+	assert.Equal(t, nil, err)
+
+	expectedValue := int64(0)
+	actualValue := soldCost
+	assert.Equal(t, expectedValue, actualValue)
+
+	expectedLots := []*investmentLot{{units: 10 * InvestmentUnitsMultiplier, cost: 1000}, {units: 10 * InvestmentUnitsMultiplier, cost: 2000}}
+	actualLots := remainingLots
+	assert.Equal(t, expectedLots, actualLots)
+}
+
+func TestSellInvestmentLotsByFIFO_SellPartOfFirstLot(t *testing.T) {
+	remainingLots, soldCost, err := sellInvestmentLotsByFIFO(getTestInvestmentLots(), 5*InvestmentUnitsMultiplier)
+	assert.Equal(t, nil, err)
+
+	expectedValue := int64(500)
+	actualValue := soldCost
+	assert.Equal(t, expectedValue, actualValue)
+
+	expectedLots := []*investmentLot{{units: 5 * InvestmentUnitsMultiplier, cost: 500}, {units: 10 * InvestmentUnitsMultiplier, cost: 2000}}
+	actualLots := remainingLots
+	assert.Equal(t, expectedLots, actualLots)
+}
+
+func TestSellInvestmentLotsByFIFO_SellWholeFirstLot(t *testing.T) {
+	remainingLots, soldCost, err := sellInvestmentLotsByFIFO(getTestInvestmentLots(), 10*InvestmentUnitsMultiplier)
+	assert.Equal(t, nil, err)
+
+	expectedValue := int64(1000)
+	actualValue := soldCost
+	assert.Equal(t, expectedValue, actualValue)
+
+	expectedLots := []*investmentLot{{units: 10 * InvestmentUnitsMultiplier, cost: 2000}}
+	actualLots := remainingLots
+	assert.Equal(t, expectedLots, actualLots)
+}
+
+func TestSellInvestmentLotsByFIFO_SellAcrossLots(t *testing.T) {
+	remainingLots, soldCost, err := sellInvestmentLotsByFIFO(getTestInvestmentLots(), 15*InvestmentUnitsMultiplier)
+	assert.Equal(t, nil, err)
+
+	expectedValue := int64(2000)
+	actualValue := soldCost
+	assert.Equal(t, expectedValue, actualValue)
+
+	expectedLots := []*investmentLot{{units: 5 * InvestmentUnitsMultiplier, cost: 1000}}
+	actualLots := remainingLots
+	assert.Equal(t, expectedLots, actualLots)
+}
+
+func TestSellInvestmentLotsByFIFO_SellAllLots(t *testing.T) {
+	remainingLots, soldCost, err := sellInvestmentLotsByFIFO(getTestInvestmentLots(), 20*InvestmentUnitsMultiplier)
+	assert.Equal(t, nil, err)
+
+	expectedValue := int64(3000)
+	actualValue := soldCost
+	assert.Equal(t, expectedValue, actualValue)
+
+	expectedLots := []*investmentLot{}
+	actualLots := remainingLots
+	assert.Equal(t, expectedLots, actualLots)
+}
+
+func TestSellInvestmentLotsByFIFO_SellMoreThanHeld(t *testing.T) {
+	_, _, err := sellInvestmentLotsByFIFO(getTestInvestmentLots(), 21*InvestmentUnitsMultiplier)
+	assert.Equal(t, errs.ErrInvestmentSellUnitsExceedHolding, err)
+}
+
+func TestSellInvestmentLotsByFIFO_RoundCostOfPartialLot(t *testing.T) {
+	lots := []*investmentLot{{units: 3 * InvestmentUnitsMultiplier, cost: 1000}}
+
+	remainingLots, soldCost, err := sellInvestmentLotsByFIFO(lots, InvestmentUnitsMultiplier)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(333), soldCost)
+	assert.Equal(t, []*investmentLot{{units: 2 * InvestmentUnitsMultiplier, cost: 667}}, remainingLots)
+}
+
+func TestSellInvestmentLotsByAverageCost_SellPartOfLots(t *testing.T) {
+	remainingLots, soldCost, err := sellInvestmentLotsByAverageCost(getTestInvestmentLots(), 5*InvestmentUnitsMultiplier)
+	assert.Equal(t, nil, err)
+
+	expectedValue := int64(750)
+	actualValue := soldCost
+	assert.Equal(t, expectedValue, actualValue)
+
+	expectedLots := []*investmentLot{{units: 15 * InvestmentUnitsMultiplier, cost: 2250}}
+	actualLots := remainingLots
+	assert.Equal(t, expectedLots, actualLots)
+}
+
+func TestSellInvestmentLotsByAverageCost_SellMoreThanFirstLot(t *testing.T) {
+	remainingLots, soldCost, err := sellInvestmentLotsByAverageCost(getTestInvestmentLots(), 15*InvestmentUnitsMultiplier)
+	assert.Equal(t, nil, err)
+
+	expectedValue := int64(2250)
+	actualValue := soldCost
+	assert.Equal(t, expectedValue, actualValue)
+
+	expectedLots := []*investmentLot{{units: 5 * InvestmentUnitsMultiplier, cost: 750}}
+	actualLots := remainingLots
+	assert.Equal(t, expectedLots, actualLots)
+}
+
+func TestSellInvestmentLotsByAverageCost_SellAllLots(t *testing.T) {
+	remainingLots, soldCost, err := sellInvestmentLotsByAverageCost(getTestInvestmentLots(), 20*InvestmentUnitsMultiplier)
+	assert.Equal(t, nil, err)
+
+	expectedValue := int64(3000)
+	actualValue := soldCost
+	assert.Equal(t, expectedValue, actualValue)
+
+	expectedLots := []*investmentLot{}
+	actualLots := remainingLots
+	assert.Equal(t, expectedLots, actualLots)
+}
+
+func TestSellInvestmentLotsByAverageCost_SellMoreThanHeld(t *testing.T) {
+	_, _, err := sellInvestmentLotsByAverageCost(getTestInvestmentLots(), 21*InvestmentUnitsMultiplier)
+	assert.Equal(t, errs.ErrInvestmentSellUnitsExceedHolding, err)
+}
+
+func TestSellInvestmentLotsByAverageCost_RoundCostOfSoldUnits(t *testing.T) {
+	lots := []*investmentLot{{units: 3 * InvestmentUnitsMultiplier, cost: 1000}}
+
+	remainingLots, soldCost, err := sellInvestmentLotsByAverageCost(lots, InvestmentUnitsMultiplier)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(333), soldCost)
+	assert.Equal(t, []*investmentLot{{units: 2 * InvestmentUnitsMultiplier, cost: 667}}, remainingLots)
+}
+
+func getTestInvestmentTrades() []*InvestmentTrade {
+	return []*InvestmentTrade{
+		{Type: INVESTMENT_TRADE_TYPE_BUY, TradeUnixTime: 100, Units: 10 * InvestmentUnitsMultiplier, Amount: 1000, Fee: 10},
+		{Type: INVESTMENT_TRADE_TYPE_BUY, TradeUnixTime: 200, Units: 10 * InvestmentUnitsMultiplier, Amount: 2000},
+		{Type: INVESTMENT_TRADE_TYPE_SELL, TradeUnixTime: 300, Units: 5 * InvestmentUnitsMultiplier, Amount: 1500, Fee: 5},
+	}
+}
+
+func TestInvestmentHoldingGetPerformance_FIFO(t *testing.T) {
+	holding := &InvestmentHolding{HoldingId: 1, CostMethod: INVESTMENT_COST_METHOD_FIFO}
+
+	actualValue, err := holding.GetPerformance(getTestInvestmentTrades(), nil, 300)
+	assert.Equal(t, nil, err)
+
+	expectedValue := &InvestmentHoldingPerformance{
+		HoldingId:      1,
+		Units:          15 * InvestmentUnitsMultiplier,
+		CostBasis:      2505,
+		Price:          3000000,
+		PriceUnixTime:  300,
+		MarketValue:    4500,
+		RealizedGain:   990,
+		UnrealizedGain: 1995,
+	}
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestInvestmentHoldingGetPerformance_AverageCost(t *testing.T) {
+	holding := &InvestmentHolding{HoldingId: 1, CostMethod: INVESTMENT_COST_METHOD_AVERAGE_COST}
+
+	actualValue, err := holding.GetPerformance(getTestInvestmentTrades(), nil, 300)
+	assert.Equal(t, nil, err)
+
+	expectedValue := &InvestmentHoldingPerformance{
+		HoldingId:      1,
+		Units:          15 * InvestmentUnitsMultiplier,
+		CostBasis:      2257,
+		Price:          3000000,
+		PriceUnixTime:  300,
+		MarketValue:    4500,
+		RealizedGain:   742,
+		UnrealizedGain: 2243,
+	}
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestInvestmentHoldingGetPerformance_UseLatestPrice(t *testing.T) {
+	holding := &InvestmentHolding{CostMethod: INVESTMENT_COST_METHOD_FIFO}
+	prices := []*InvestmentPrice{
+		{PriceUnixTime: 250, Price: 1000000},
+		{PriceUnixTime: 400, Price: 2000000},
+		{PriceUnixTime: 600, Price: 4000000},
+	}
+
+	// The price earlier than the latest trade is ignored
+	performance, err := holding.GetPerformance(getTestInvestmentTrades(), prices, 300)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(3000000), performance.Price)
+	assert.Equal(t, int64(4500), performance.MarketValue)
+
+	performance, err = holding.GetPerformance(getTestInvestmentTrades(), prices, 500)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(2000000), performance.Price)
+	assert.Equal(t, int64(400), performance.PriceUnixTime)
+	assert.Equal(t, int64(3000), performance.MarketValue)
+	assert.Equal(t, int64(495), performance.UnrealizedGain)
+	assert.Equal(t, int64(990), performance.RealizedGain)
+}
+
+func TestInvestmentHoldingGetPerformance_TradesAfterUnixTime(t *testing.T) {
+	holding := &InvestmentHolding{CostMethod: INVESTMENT_COST_METHOD_FIFO}
+
+	performance, err := holding.GetPerformance(getTestInvestmentTrades(), nil, 250)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 20*int64(InvestmentUnitsMultiplier), performance.Units)
+	assert.Equal(t, int64(3010), performance.CostBasis)
+	assert.Equal(t, int64(2000000), performance.Price)
+	assert.Equal(t, int64(4000), performance.MarketValue)
+	assert.Equal(t, int64(990), performance.UnrealizedGain)
+	assert.Equal(t, int64(0), performance.RealizedGain)
+}
+
+func getTestInvestmentTradesSellMoreThanHeld() []*InvestmentTrade {
+	return []*InvestmentTrade{
+		{Type: INVESTMENT_TRADE_TYPE_BUY, TradeUnixTime: 100, Units: 10 * InvestmentUnitsMultiplier, Amount: 1000},
+		{Type: INVESTMENT_TRADE_TYPE_SELL, TradeUnixTime: 200, Units: 11 * InvestmentUnitsMultiplier, Amount: 1100},
+	}
+}
+
+func TestInvestmentHoldingGetPerformance_SellMoreThanHeldByFIFO(t *testing.T) {
+	holding := &InvestmentHolding{CostMethod: INVESTMENT_COST_METHOD_FIFO}
+
+	performance, err := holding.GetPerformance(getTestInvestmentTradesSellMoreThanHeld(), nil, 200)
+	assert.Equal(t, errs.ErrInvestmentSellUnitsExceedHolding, err)
+	assert.Nil(t, performance)
+}
+
+func TestInvestmentHoldingGetPerformance_SellMoreThanHeldByAverageCost(t *testing.T) {
+	holding := &InvestmentHolding{CostMethod: INVESTMENT_COST_METHOD_AVERAGE_COST}
+
+	performance, err := holding.GetPerformance(getTestInvestmentTradesSellMoreThanHeld(), nil, 200)
+	assert.Equal(t, errs.ErrInvestmentSellUnitsExceedHolding, err)
+	assert.Nil(t, performance)
+}
@@ -0,0 +1,290 @@
+package models
+
+import (
+	"math"
+	"time"
+
+	"github.com/f97/gofire/pkg/utils"
+)
+
+// LoanInterestRateMultiplier represents the multiplier of loan annual interest rate, e.g. 4250 means 4.25%
+const LoanInterestRateMultiplier = 100000
+
+// Loan represents loan (or other debt) amortization settings of debt account stored in database
+type Loan struct {
+	LoanId                 int64  `xorm:"PK"`
+	Uid                    int64  `xorm:"INDEX(IDX_loan_uid_deleted_account_id) NOT NULL"`
+	Deleted                bool   `xorm:"INDEX(IDX_loan_uid_deleted_account_id) NOT NULL"`
+	AccountId              int64  `xorm:"INDEX(IDX_loan_uid_deleted_account_id) NOT NULL"`
+	Principal              int64  `xorm:"NOT NULL"`
+	InterestRate           int32  `xorm:"NOT NULL"`
+	TermMonths             int32  `xorm:"NOT NULL"`
+	StartTime              int64  `xorm:"NOT NULL"`
+	TimezoneUtcOffset      int16  `xorm:"NOT NULL"`
+	AutoCreateTransactions bool   `xorm:"NOT NULL"`
+	PaymentAccountId       int64  `xorm:"NOT NULL"`
+	PrincipalCategoryId    int64  `xorm:"NOT NULL"`
+	InterestCategoryId     int64  `xorm:"NOT NULL"`
+	Comment                string `xorm:"VARCHAR(255) NOT NULL"`
+	CreatedIp              string `xorm:"VARCHAR(39)"`
+	CreatedUnixTime        int64
+	UpdatedUnixTime        int64
+	DeletedUnixTime        int64
+}
+
+// LoanAmortizationItem represents one payment of loan amortization schedule
+type LoanAmortizationItem struct {
+	Period             int32
+	PaymentUnixTime    int64
+	Payment            int64
+	Principal          int64
+	Interest           int64
+	RemainingPrincipal int64
+}
+
+// LoanGetRequest represents all parameters of loan getting request
+type LoanGetRequest struct {
+	Id int64 `form:"id,string" binding:"required,min=1"`
+}
+
+// LoanCreateRequest represents all parameters of loan creation request
+type LoanCreateRequest struct {
+	AccountId              int64  `json:"accountId,string" binding:"required,min=1"`
+	Principal              int64  `json:"principal" binding:"required,min=1,max=99999999999"`
+	InterestRate           int32  `json:"interestRate" binding:"min=0,max=100000"`
+	TermMonths             int32  `json:"termMonths" binding:"required,min=1,max=600"`
+	StartTime              int64  `json:"startTime" binding:"required,min=1"`
+	UtcOffset              int16  `json:"utcOffset" binding:"min=-720,max=840"`
+	AutoCreateTransactions bool   `json:"autoCreateTransactions"`
+	PaymentAccountId       int64  `json:"paymentAccountId,string" binding:"min=0"`
+	PrincipalCategoryId    int64  `json:"principalCategoryId,string" binding:"min=0"`
+	InterestCategoryId     int64  `json:"interestCategoryId,string" binding:"min=0"`
+	Comment                string `json:"comment" binding:"max=255"`
+}
+
+// LoanModifyRequest represents all parameters of loan modification request
+type LoanModifyRequest struct {
+	Id                     int64  `json:"id,string" binding:"required,min=1"`
+	Principal              int64  `json:"principal" binding:"required,min=1,max=99999999999"`
+	InterestRate           int32  `json:"interestRate" binding:"min=0,max=100000"`
+	TermMonths             int32  `json:"termMonths" binding:"required,min=1,max=600"`
+	StartTime              int64  `json:"startTime" binding:"required,min=1"`
+	UtcOffset              int16  `json:"utcOffset" binding:"min=-720,max=840"`
+	AutoCreateTransactions bool   `json:"autoCreateTransactions"`
+	PaymentAccountId       int64  `json:"paymentAccountId,string" binding:"min=0"`
+	PrincipalCategoryId    int64  `json:"principalCategoryId,string" binding:"min=0"`
+	InterestCategoryId     int64  `json:"interestCategoryId,string" binding:"min=0"`
+	Comment                string `json:"comment" binding:"max=255"`
+}
+
+// LoanDeleteRequest represents all parameters of loan deleting request
+type LoanDeleteRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// LoanInfoResponse represents a view-object of loan
+type LoanInfoResponse struct {
+	Id                     int64  `json:"id,string"`
+	AccountId              int64  `json:"accountId,string"`
+	Principal              int64  `json:"principal"`
+	InterestRate           int32  `json:"interestRate"`
+	TermMonths             int32  `json:"termMonths"`
+	StartTime              int64  `json:"startTime"`
+	UtcOffset              int16  `json:"utcOffset"`
+	MonthlyPayment         int64  `json:"monthlyPayment"`
+	TotalInterest          int64  `json:"totalInterest"`
+	AutoCreateTransactions bool   `json:"autoCreateTransactions"`
+	PaymentAccountId       int64  `json:"paymentAccountId,string,omitempty"`
+	PrincipalCategoryId    int64  `json:"principalCategoryId,string,omitempty"`
+	InterestCategoryId     int64  `json:"interestCategoryId,string,omitempty"`
+	Comment                string `json:"comment"`
+}
+
+// LoanAmortizationItemResponse represents a view-object of loan amortization schedule item
+type LoanAmortizationItemResponse struct {
+	Period             int32 `json:"period"`
+	PaymentTime        int64 `json:"paymentTime"`
+	Payment            int64 `json:"payment"`
+	Principal          int64 `json:"principal"`
+	Interest           int64 `json:"interest"`
+	RemainingPrincipal int64 `json:"remainingPrincipal"`
+}
+
+// GetMonthlyPayment returns the fixed monthly payment amount of loan
+func (l *Loan) GetMonthlyPayment() int64 {
+	if l.TermMonths < 1 {
+		return 0
+	}
+
+	if l.InterestRate == 0 {
+		return (l.Principal + int64(l.TermMonths) - 1) / int64(l.TermMonths)
+	}
+
+	monthlyRate := l.getMonthlyInterestRate()
+	payment := float64(l.Principal) * monthlyRate / (1 - math.Pow(1+monthlyRate, -float64(l.TermMonths)))
+
+	return int64(math.Round(payment))
+}
+
+// GetAmortizationSchedule returns all payments of loan which split each payment into principal and interest,
+// the principal of the last payment is adjusted to pay off the remaining principal
+func (l *Loan) GetAmortizationSchedule() []*LoanAmortizationItem {
+	if l.TermMonths < 1 {
+		return nil
+	}
+
+	payment := l.GetMonthlyPayment()
+	monthlyRate := l.getMonthlyInterestRate()
+	remainingPrincipal := l.Principal
+	items := make([]*LoanAmortizationItem, l.TermMonths)
+
+	for i := int32(0); i < l.TermMonths; i++ {
+		interest := int64(math.Round(float64(remainingPrincipal) * monthlyRate))
+		principal := payment - interest
+
+		if i == l.TermMonths-1 || principal > remainingPrincipal {
+			principal = remainingPrincipal
+		}
+
+		if principal < 0 {
+			principal = 0
+		}
+
+		remainingPrincipal -= principal
+
+		items[i] = &LoanAmortizationItem{
+			Period:             i + 1,
+			PaymentUnixTime:    l.GetPaymentUnixTime(i + 1),
+			Payment:            principal + interest,
+			Principal:          principal,
+			Interest:           interest,
+			RemainingPrincipal: remainingPrincipal,
+		}
+	}
+
+	return items
+}
+
+// GetPaymentUnixTime returns the unix time of the specified payment period (starts from 1),
+// the day of payment is the last day of month if the month does not have the same day as start time
+func (l *Loan) GetPaymentUnixTime(period int32) int64 {
+	timezone := time.FixedZone("Loan Timezone", int(l.TimezoneUtcOffset)*60)
+	startTime := time.Unix(l.StartTime, 0).In(timezone)
+	firstDayOfMonth := time.Date(startTime.Year(), startTime.Month()+time.Month(period-1), 1, 0, 0, 0, 0, timezone)
+	day := startTime.Day()
+
+	if daysInMonth := utils.GetDaysInMonth(firstDayOfMonth.Year(), firstDayOfMonth.Month()); day > daysInMonth {
+		day = daysInMonth
+	}
+
+	return time.Date(firstDayOfMonth.Year(), firstDayOfMonth.Month(), day, startTime.Hour(), startTime.Minute(), startTime.Second(), 0, timezone).Unix()
+}
+
+// GetPaymentPeriod returns the payment period whose payment time is the given unix time, or 0 if there is no payment at that time
+func (l *Loan) GetPaymentPeriod(paymentUnixTime int64) int32 {
+	for period := int32(1); period <= l.TermMonths; period++ {
+		if l.GetPaymentUnixTime(period) == paymentUnixTime {
+			return period
+		}
+	}
+
+	return 0
+}
+
+// ToTransactionSchedule returns a new monthly transaction schedule model which creates the payment transactions of loan,
+// the amount of schedule is the monthly payment and the principal and interest are calculated when each payment is created
+func (l *Loan) ToTransactionSchedule() *TransactionSchedule {
+	payment := l.GetMonthlyPayment()
+
+	return &TransactionSchedule{
+		Uid:                  l.Uid,
+		LoanId:               l.LoanId,
+		Type:                 TRANSACTION_DB_TYPE_TRANSFER_OUT,
+		CategoryId:           l.PrincipalCategoryId,
+		AccountId:            l.PaymentAccountId,
+		Amount:               payment,
+		RelatedAccountId:     l.AccountId,
+		RelatedAccountAmount: payment,
+		Comment:              l.Comment,
+		Frequency:            TRANSACTION_SCHEDULE_FREQUENCY_MONTHLY,
+		StartTime:            l.StartTime,
+		EndTime:              l.GetPaymentUnixTime(l.TermMonths),
+		TimezoneUtcOffset:    l.TimezoneUtcOffset,
+		CreatedIp:            l.CreatedIp,
+	}
+}
+
+// ToPrincipalTransaction returns a new transfer transaction model which pays the principal of the specified amortization item
+func (l *Loan) ToPrincipalTransaction(item *LoanAmortizationItem) *Transaction {
+	return &Transaction{
+		Uid:                  l.Uid,
+		Type:                 TRANSACTION_DB_TYPE_TRANSFER_OUT,
+		CategoryId:           l.PrincipalCategoryId,
+		TransactionTime:      utils.GetMinTransactionTimeFromUnixTime(item.PaymentUnixTime),
+		TimezoneUtcOffset:    l.TimezoneUtcOffset,
+		AccountId:            l.PaymentAccountId,
+		Amount:               item.Principal,
+		RelatedAccountId:     l.AccountId,
+		RelatedAccountAmount: item.Principal,
+		Comment:              l.Comment,
+		CreatedIp:            l.CreatedIp,
+	}
+}
+
+// ToInterestTransaction returns a new expense transaction model which pays the interest of the specified amortization item
+func (l *Loan) ToInterestTransaction(item *LoanAmortizationItem) *Transaction {
+	return &Transaction{
+		Uid:               l.Uid,
+		Type:              TRANSACTION_DB_TYPE_EXPENSE,
+		CategoryId:        l.InterestCategoryId,
+		TransactionTime:   utils.GetMinTransactionTimeFromUnixTime(item.PaymentUnixTime),
+		TimezoneUtcOffset: l.TimezoneUtcOffset,
+		AccountId:         l.PaymentAccountId,
+		Amount:            item.Interest,
+		Comment:           l.Comment,
+		CreatedIp:         l.CreatedIp,
+	}
+}
+
+// ToLoanInfoResponse returns a view-object according to database model
+func (l *Loan) ToLoanInfoResponse() *LoanInfoResponse {
+	totalInterest := int64(0)
+	items := l.GetAmortizationSchedule()
+
+	for i := 0; i < len(items); i++ {
+		totalInterest += items[i].Interest
+	}
+
+	return &LoanInfoResponse{
+		Id:                     l.LoanId,
+		AccountId:              l.AccountId,
+		Principal:              l.Principal,
+		InterestRate:           l.InterestRate,
+		TermMonths:             l.TermMonths,
+		StartTime:              l.StartTime,
+		UtcOffset:              l.TimezoneUtcOffset,
+		MonthlyPayment:         l.GetMonthlyPayment(),
+		TotalInterest:          totalInterest,
+		AutoCreateTransactions: l.AutoCreateTransactions,
+		PaymentAccountId:       l.PaymentAccountId,
+		PrincipalCategoryId:    l.PrincipalCategoryId,
+		InterestCategoryId:     l.InterestCategoryId,
+		Comment:                l.Comment,
+	}
+}
+
+// ToLoanAmortizationItemResponse returns a view-object according to amortization schedule item
+func (i *LoanAmortizationItem) ToLoanAmortizationItemResponse() *LoanAmortizationItemResponse {
+	return &LoanAmortizationItemResponse{
+		Period:             i.Period,
+		PaymentTime:        i.PaymentUnixTime,
+		Payment:            i.Payment,
+		Principal:          i.Principal,
+		Interest:           i.Interest,
+		RemainingPrincipal: i.RemainingPrincipal,
+	}
+}
+
+func (l *Loan) getMonthlyInterestRate() float64 {
+	return float64(l.InterestRate) / LoanInterestRateMultiplier / 12
+}
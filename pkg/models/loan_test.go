@@ -0,0 +1,224 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoanGetMonthlyPayment_ZeroInterestDivisible(t *testing.T) {
+	loan := &Loan{Principal: 1200, TermMonths: 12}
+
+	expectedValue := int64(100)
+	actualValue := loan.GetMonthlyPayment()
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestLoanGetMonthlyPayment_ZeroInterestRoundedUp(t *testing.T) {
+	loan := &Loan{Principal: 1000, TermMonths: 3}
+
+	expectedValue := int64(334)
+	actualValue := loan.GetMonthlyPayment()
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestLoanGetMonthlyPayment_WithInterest(t *testing.T) {
+	loan := &Loan{Principal: 1000000, InterestRate: 6000, TermMonths: 12}
+
+	expectedValue := int64(86066)
+	actualValue := loan.GetMonthlyPayment()
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestLoanGetMonthlyPayment_OneMonthWithInterest(t *testing.T) {
+	loan := &Loan{Principal: 1000000, InterestRate: 12000, TermMonths: 1}
+
+	expectedValue := int64(1010000)
+	actualValue := loan.GetMonthlyPayment()
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestLoanGetMonthlyPayment_InvalidTerm(t *testing.T) {
+	loan := &Loan{Principal: 1000, TermMonths: 0}
+
+	expectedValue := int64(0)
+	actualValue := loan.GetMonthlyPayment()
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestLoanGetAmortizationSchedule_ZeroInterest(t *testing.T) {
+	loan := &Loan{Principal: 1000, TermMonths: 3, StartTime: time.Date(2024, time.January, 15, 0, 0, 0, 0, time.UTC).Unix()}
+
+	expectedValue := []*LoanAmortizationItem{
+		{Period: 1, PaymentUnixTime: time.Date(2024, time.January, 15, 0, 0, 0, 0, time.UTC).Unix(), Payment: 334, Principal: 334, RemainingPrincipal: 666},
+		{Period: 2, PaymentUnixTime: time.Date(2024, time.February, 15, 0, 0, 0, 0, time.UTC).Unix(), Payment: 334, Principal: 334, RemainingPrincipal: 332},
+		{Period: 3, PaymentUnixTime: time.Date(2024, time.March, 15, 0, 0, 0, 0, time.UTC).Unix(), Payment: 332, Principal: 332, RemainingPrincipal: 0},
+	}
+	actualValue := loan.GetAmortizationSchedule()
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestLoanGetAmortizationSchedule_FinalPaymentRounding(t *testing.T) {
+	loan := &Loan{Principal: 1000000, InterestRate: 6000, TermMonths: 12, StartTime: time.Date(2024, time.January, 15, 0, 0, 0, 0, time.UTC).Unix()}
+	items := loan.GetAmortizationSchedule()
+
+	assert.Equal(t, 12, len(items))
+
+	assert.Equal(t, int64(86066), items[0].Payment)
+	assert.Equal(t, int64(81066), items[0].Principal)
+	assert.Equal(t, int64(5000), items[0].Interest)
+	assert.Equal(t, int64(918934), items[0].RemainingPrincipal)
+
+	assert.Equal(t, int64(86066), items[10].Payment)
+	assert.Equal(t, int64(85642), items[10].RemainingPrincipal)
+
+	// The principal of the last payment pays off all remaining principal
+	assert.Equal(t, int64(86070), items[11].Payment)
+	assert.Equal(t, int64(85642), items[11].Principal)
+	assert.Equal(t, int64(428), items[11].Interest)
+	assert.Equal(t, int64(0), items[11].RemainingPrincipal)
+
+	totalPrincipal := int64(0)
+	totalInterest := int64(0)
+
+	for i := 0; i < len(items); i++ {
+		totalPrincipal += items[i].Principal
+		totalInterest += items[i].Interest
+	}
+
+	assert.Equal(t, loan.Principal, totalPrincipal)
+	assert.Equal(t, int64(32796), totalInterest)
+	assert.Equal(t, totalInterest, loan.ToLoanInfoResponse().TotalInterest)
+}
+
+func TestLoanGetPaymentUnixTime_FirstPeriod(t *testing.T) {
+	loan := &Loan{TermMonths: 14, StartTime: time.Date(2024, time.January, 31, 10, 0, 0, 0, time.UTC).Unix()}
+
+	expectedValue := time.Date(2024, time.January, 31, 10, 0, 0, 0, time.UTC).Unix()
+	actualValue := loan.GetPaymentUnixTime(1)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestLoanGetPaymentUnixTime_FebruaryInLeapYear(t *testing.T) {
+	loan := &Loan{TermMonths: 14, StartTime: time.Date(2024, time.January, 31, 10, 0, 0, 0, time.UTC).Unix()}
+
+	expectedValue := time.Date(2024, time.February, 29, 10, 0, 0, 0, time.UTC).Unix()
+	actualValue := loan.GetPaymentUnixTime(2)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestLoanGetPaymentUnixTime_MonthWith31Days(t *testing.T) {
+	loan := &Loan{TermMonths: 14, StartTime: time.Date(2024, time.January, 31, 10, 0, 0, 0, time.UTC).Unix()}
+
+	expectedValue := time.Date(2024, time.March, 31, 10, 0, 0, 0, time.UTC).Unix()
+	actualValue := loan.GetPaymentUnixTime(3)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestLoanGetPaymentUnixTime_MonthWith30Days(t *testing.T) {
+	loan := &Loan{TermMonths: 14, StartTime: time.Date(2024, time.January, 31, 10, 0, 0, 0, time.UTC).Unix()}
+
+	expectedValue := time.Date(2024, time.April, 30, 10, 0, 0, 0, time.UTC).Unix()
+	actualValue := loan.GetPaymentUnixTime(4)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestLoanGetPaymentUnixTime_LastPeriodOfFirstYear(t *testing.T) {
+	loan := &Loan{TermMonths: 14, StartTime: time.Date(2024, time.January, 31, 10, 0, 0, 0, time.UTC).Unix()}
+
+	expectedValue := time.Date(2024, time.December, 31, 10, 0, 0, 0, time.UTC).Unix()
+	actualValue := loan.GetPaymentUnixTime(12)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestLoanGetPaymentUnixTime_FebruaryInCommonYear(t *testing.T) {
+	loan := &Loan{TermMonths: 14, StartTime: time.Date(2024, time.January, 31, 10, 0, 0, 0, time.UTC).Unix()}
+
+	expectedValue := time.Date(2025, time.February, 28, 10, 0, 0, 0, time.UTC).Unix()
+	actualValue := loan.GetPaymentUnixTime(14)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestLoanGetPaymentUnixTime_LoanTimezone(t *testing.T) {
+	timezone := time.FixedZone("Loan Timezone", 8*60*60)
+	loan := &Loan{TermMonths: 2, TimezoneUtcOffset: 8 * 60, StartTime: time.Date(2024, time.January, 31, 1, 0, 0, 0, timezone).Unix()}
+
+	// The day of payment is the 31st in loan timezone although it is the 30th in UTC
+	expectedValue := time.Date(2024, time.February, 29, 1, 0, 0, 0, timezone).Unix()
+	actualValue := loan.GetPaymentUnixTime(2)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestLoanGetPaymentPeriod_StartTime(t *testing.T) {
+	loan := &Loan{TermMonths: 3, StartTime: time.Date(2024, time.January, 31, 0, 0, 0, 0, time.UTC).Unix()}
+
+	expectedValue := int32(1)
+	actualValue := loan.GetPaymentPeriod(loan.StartTime)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestLoanGetPaymentPeriod_SecondPaymentTime(t *testing.T) {
+	loan := &Loan{TermMonths: 3, StartTime: time.Date(2024, time.January, 31, 0, 0, 0, 0, time.UTC).Unix()}
+
+	expectedValue := int32(2)
+	actualValue := loan.GetPaymentPeriod(time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC).Unix())
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestLoanGetPaymentPeriod_LastPaymentTime(t *testing.T) {
+	loan := &Loan{TermMonths: 3, StartTime: time.Date(2024, time.January, 31, 0, 0, 0, 0, time.UTC).Unix()}
+
+	expectedValue := int32(3)
+	actualValue := loan.GetPaymentPeriod(time.Date(2024, time.March, 31, 0, 0, 0, 0, time.UTC).Unix())
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestLoanGetPaymentPeriod_AfterLastPeriod(t *testing.T) {
+	loan := &Loan{TermMonths: 3, StartTime: time.Date(2024, time.January, 31, 0, 0, 0, 0, time.UTC).Unix()}
+
+	expectedValue := int32(0)
+	actualValue := loan.GetPaymentPeriod(time.Date(2024, time.April, 30, 0, 0, 0, 0, time.UTC).Unix())
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestLoanGetPaymentPeriod_NotPaymentTime(t *testing.T) {
+	loan := &Loan{TermMonths: 3, StartTime: time.Date(2024, time.January, 31, 0, 0, 0, 0, time.UTC).Unix()}
+
+	expectedValue := int32(0)
+	actualValue := loan.GetPaymentPeriod(loan.StartTime + 1)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestLoanToTransactionSchedule(t *testing.T) {
+	loan := &Loan{
+		LoanId:              1,
+		Uid:                 2,
+		AccountId:           3,
+		Principal:           1000,
+		TermMonths:          3,
+		StartTime:           time.Date(2024, time.January, 31, 0, 0, 0, 0, time.UTC).Unix(),
+		PaymentAccountId:    4,
+		PrincipalCategoryId: 5,
+		InterestCategoryId:  6,
+	}
+
+	schedule := loan.ToTransactionSchedule()
+	assert.Equal(t, int64(1), schedule.LoanId)
+	assert.Equal(t, TRANSACTION_DB_TYPE_TRANSFER_OUT, schedule.Type)
+	assert.Equal(t, int64(4), schedule.AccountId)
+	assert.Equal(t, int64(3), schedule.RelatedAccountId)
+	assert.Equal(t, int64(5), schedule.CategoryId)
+	assert.Equal(t, int64(334), schedule.Amount)
+	assert.Equal(t, TRANSACTION_SCHEDULE_FREQUENCY_MONTHLY, schedule.Frequency)
+
+	// The occurrences of schedule are the same as the payments of loan
+	runTime := schedule.GetNextRunTime(schedule.StartTime - 1)
+
+	for period := int32(1); period <= loan.TermMonths; period++ {
+		assert.Equal(t, loan.GetPaymentUnixTime(period), runTime)
+		runTime = schedule.GetNextRunTime(runTime)
+	}
+
+	assert.Equal(t, int64(0), runTime)
+}
@@ -0,0 +1,184 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/f97/gofire/pkg/utils"
+)
+
+// SavingsGoal represents a savings target of one or more accounts stored in database
+type SavingsGoal struct {
+	GoalId          int64  `xorm:"PK"`
+	Uid             int64  `xorm:"INDEX(IDX_savings_goal_uid_deleted_target_date) NOT NULL"`
+	Deleted         bool   `xorm:"INDEX(IDX_savings_goal_uid_deleted_target_date) NOT NULL"`
+	Name            string `xorm:"VARCHAR(64) NOT NULL"`
+	Currency        string `xorm:"VARCHAR(3) NOT NULL"`
+	TargetAmount    int64  `xorm:"NOT NULL"`
+	TargetDate      int64  `xorm:"INDEX(IDX_savings_goal_uid_deleted_target_date) NOT NULL"`
+	AccountIds      string `xorm:"VARCHAR(255) NOT NULL"`
+	Comment         string `xorm:"VARCHAR(255) NOT NULL"`
+	CreatedUnixTime int64
+	UpdatedUnixTime int64
+	DeletedUnixTime int64
+}
+
+// SavingsGoalProgress represents the progress of savings goal at specified time
+type SavingsGoalProgress struct {
+	CurrentAmount               int64
+	RemainingAmount             int64
+	MonthsRemaining             int32
+	RequiredMonthlyContribution int64
+	AverageMonthlyContribution  int64
+	ProjectedCompletionUnixTime int64
+}
+
+// SavingsGoalGetRequest represents all parameters of savings goal getting request
+type SavingsGoalGetRequest struct {
+	Id int64 `form:"id,string" binding:"required,min=1"`
+}
+
+// SavingsGoalCreateRequest represents all parameters of savings goal creation request
+type SavingsGoalCreateRequest struct {
+	Name         string   `json:"name" binding:"required,notBlank,max=64"`
+	TargetAmount int64    `json:"targetAmount" binding:"required,min=1,max=99999999999"`
+	TargetDate   int64    `json:"targetDate" binding:"required,min=1"`
+	AccountIds   []string `json:"accountIds" binding:"required,min=1,max=10"`
+	Comment      string   `json:"comment" binding:"max=255"`
+}
+
+// SavingsGoalModifyRequest represents all parameters of savings goal modification request
+type SavingsGoalModifyRequest struct {
+	Id           int64    `json:"id,string" binding:"required,min=1"`
+	Name         string   `json:"name" binding:"required,notBlank,max=64"`
+	TargetAmount int64    `json:"targetAmount" binding:"required,min=1,max=99999999999"`
+	TargetDate   int64    `json:"targetDate" binding:"required,min=1"`
+	AccountIds   []string `json:"accountIds" binding:"required,min=1,max=10"`
+	Comment      string   `json:"comment" binding:"max=255"`
+}
+
+// SavingsGoalDeleteRequest represents all parameters of savings goal deleting request
+type SavingsGoalDeleteRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// SavingsGoalProgressRequest represents all parameters of savings goal progress request, all goals are returned if id is not set
+type SavingsGoalProgressRequest struct {
+	Id int64 `form:"id,string" binding:"min=0"`
+}
+
+// SavingsGoalInfoResponse represents a view-object of savings goal
+type SavingsGoalInfoResponse struct {
+	Id           int64    `json:"id,string"`
+	Name         string   `json:"name"`
+	Currency     string   `json:"currency"`
+	TargetAmount int64    `json:"targetAmount"`
+	TargetDate   int64    `json:"targetDate"`
+	AccountIds   []string `json:"accountIds"`
+	Comment      string   `json:"comment"`
+}
+
+// SavingsGoalProgressResponse represents a view-object of savings goal progress
+type SavingsGoalProgressResponse struct {
+	Goal                        *SavingsGoalInfoResponse `json:"goal"`
+	CurrentAmount               int64                    `json:"currentAmount"`
+	RemainingAmount             int64                    `json:"remainingAmount"`
+	Completed                   bool                     `json:"completed"`
+	MonthsRemaining             int32                    `json:"monthsRemaining"`
+	RequiredMonthlyContribution int64                    `json:"requiredMonthlyContribution"`
+	AverageMonthlyContribution  int64                    `json:"averageMonthlyContribution"`
+	ProjectedCompletionTime     int64                    `json:"projectedCompletionTime,omitempty"`
+}
+
+// GetAccountIds returns the ids of accounts linked to savings goal
+func (g *SavingsGoal) GetAccountIds() []int64 {
+	if g.AccountIds == "" {
+		return []int64{}
+	}
+
+	accountIds, err := utils.StringArrayToInt64Array(strings.Split(g.AccountIds, ","))
+
+	if err != nil {
+		return []int64{}
+	}
+
+	return accountIds
+}
+
+// SetAccountIds sets the ids of accounts linked to savings goal
+func (g *SavingsGoal) SetAccountIds(accountIds []int64) {
+	g.AccountIds = strings.Join(utils.Int64ArrayToStringArray(accountIds), ",")
+}
+
+// GetProgress returns the progress of savings goal at the specified unix time,
+// the average monthly contribution is the net amount transferred into linked accounts in the contribution months divided by the count of months,
+// and the projected completion time is not set if the goal cannot be completed by the average monthly contribution
+func (g *SavingsGoal) GetProgress(currentAmount int64, contributionAmount int64, contributionMonths int32, unixTime int64, timezone *time.Location) *SavingsGoalProgress {
+	progress := &SavingsGoalProgress{
+		CurrentAmount: currentAmount,
+	}
+
+	if contributionMonths > 0 {
+		progress.AverageMonthlyContribution = contributionAmount / int64(contributionMonths)
+	}
+
+	if currentAmount >= g.TargetAmount {
+		return progress
+	}
+
+	progress.RemainingAmount = g.TargetAmount - currentAmount
+	now := time.Unix(unixTime, 0).In(timezone)
+
+	if g.TargetDate > unixTime {
+		targetDate := time.Unix(g.TargetDate, 0).In(timezone)
+		progress.MonthsRemaining = int32((targetDate.Year()-now.Year())*12 + int(targetDate.Month()) - int(now.Month()))
+
+		if targetDate.Day() < now.Day() {
+			progress.MonthsRemaining--
+		}
+
+		if progress.MonthsRemaining < 1 {
+			progress.MonthsRemaining = 1
+		}
+	}
+
+	if progress.MonthsRemaining > 0 {
+		progress.RequiredMonthlyContribution = (progress.RemainingAmount + int64(progress.MonthsRemaining) - 1) / int64(progress.MonthsRemaining)
+	} else {
+		progress.RequiredMonthlyContribution = progress.RemainingAmount
+	}
+
+	if progress.AverageMonthlyContribution > 0 {
+		months := (progress.RemainingAmount + progress.AverageMonthlyContribution - 1) / progress.AverageMonthlyContribution
+		progress.ProjectedCompletionUnixTime = now.AddDate(0, int(months), 0).Unix()
+	}
+
+	return progress
+}
+
+// ToSavingsGoalInfoResponse returns a view-object according to database model
+func (g *SavingsGoal) ToSavingsGoalInfoResponse() *SavingsGoalInfoResponse {
+	return &SavingsGoalInfoResponse{
+		Id:           g.GoalId,
+		Name:         g.Name,
+		Currency:     g.Currency,
+		TargetAmount: g.TargetAmount,
+		TargetDate:   g.TargetDate,
+		AccountIds:   utils.Int64ArrayToStringArray(g.GetAccountIds()),
+		Comment:      g.Comment,
+	}
+}
+
+// ToSavingsGoalProgressResponse returns a view-object according to database model and progress
+func (g *SavingsGoal) ToSavingsGoalProgressResponse(progress *SavingsGoalProgress) *SavingsGoalProgressResponse {
+	return &SavingsGoalProgressResponse{
+		Goal:                        g.ToSavingsGoalInfoResponse(),
+		CurrentAmount:               progress.CurrentAmount,
+		RemainingAmount:             progress.RemainingAmount,
+		Completed:                   progress.RemainingAmount == 0,
+		MonthsRemaining:             progress.MonthsRemaining,
+		RequiredMonthlyContribution: progress.RequiredMonthlyContribution,
+		AverageMonthlyContribution:  progress.AverageMonthlyContribution,
+		ProjectedCompletionTime:     progress.ProjectedCompletionUnixTime,
+	}
+}
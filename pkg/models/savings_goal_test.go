@@ -0,0 +1,180 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSavingsGoalGetProgress_TargetDateInFuture(t *testing.T) {
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	goal := &SavingsGoal{TargetAmount: 12000, TargetDate: time.Date(2024, 7, 15, 0, 0, 0, 0, time.UTC).Unix()}
+
+	expectedValue := &SavingsGoalProgress{
+		CurrentAmount:               6000,
+		RemainingAmount:             6000,
+		MonthsRemaining:             6,
+		RequiredMonthlyContribution: 1000,
+		AverageMonthlyContribution:  1000,
+		ProjectedCompletionUnixTime: now.AddDate(0, 6, 0).Unix(),
+	}
+	actualValue := goal.GetProgress(6000, 3000, 3, now.Unix(), time.UTC)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestSavingsGoalGetProgress_TargetDayEarlierThanCurrentDay(t *testing.T) {
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	goal := &SavingsGoal{TargetAmount: 12000, TargetDate: time.Date(2024, 7, 10, 0, 0, 0, 0, time.UTC).Unix()}
+
+	expectedValue := &SavingsGoalProgress{
+		CurrentAmount:               6000,
+		RemainingAmount:             6000,
+		MonthsRemaining:             5,
+		RequiredMonthlyContribution: 1200,
+		AverageMonthlyContribution:  1000,
+		ProjectedCompletionUnixTime: now.AddDate(0, 6, 0).Unix(),
+	}
+	actualValue := goal.GetProgress(6000, 3000, 3, now.Unix(), time.UTC)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestSavingsGoalGetProgress_RoundUpRequiredAndProjectedMonths(t *testing.T) {
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	goal := &SavingsGoal{TargetAmount: 12000, TargetDate: time.Date(2024, 4, 15, 0, 0, 0, 0, time.UTC).Unix()}
+
+	expectedValue := &SavingsGoalProgress{
+		CurrentAmount:               11000,
+		RemainingAmount:             1000,
+		MonthsRemaining:             3,
+		RequiredMonthlyContribution: 334,
+		AverageMonthlyContribution:  750,
+		ProjectedCompletionUnixTime: now.AddDate(0, 2, 0).Unix(),
+	}
+	actualValue := goal.GetProgress(11000, 1500, 2, now.Unix(), time.UTC)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestSavingsGoalGetProgress_PastTargetDate(t *testing.T) {
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	goal := &SavingsGoal{TargetAmount: 12000, TargetDate: time.Date(2023, 12, 1, 0, 0, 0, 0, time.UTC).Unix()}
+
+	expectedValue := &SavingsGoalProgress{
+		CurrentAmount:               6000,
+		RemainingAmount:             6000,
+		MonthsRemaining:             0,
+		RequiredMonthlyContribution: 6000,
+		AverageMonthlyContribution:  1000,
+		ProjectedCompletionUnixTime: now.AddDate(0, 6, 0).Unix(),
+	}
+	actualValue := goal.GetProgress(6000, 3000, 3, now.Unix(), time.UTC)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestSavingsGoalGetProgress_TargetDateEqualsToCurrentTime(t *testing.T) {
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	goal := &SavingsGoal{TargetAmount: 12000, TargetDate: now.Unix()}
+
+	expectedValue := &SavingsGoalProgress{
+		CurrentAmount:               6000,
+		RemainingAmount:             6000,
+		MonthsRemaining:             0,
+		RequiredMonthlyContribution: 6000,
+	}
+	actualValue := goal.GetProgress(6000, 0, 0, now.Unix(), time.UTC)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestSavingsGoalGetProgress_TargetDateLaterInCurrentMonth(t *testing.T) {
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	goal := &SavingsGoal{TargetAmount: 12000, TargetDate: time.Date(2024, 1, 20, 0, 0, 0, 0, time.UTC).Unix()}
+
+	expectedValue := &SavingsGoalProgress{
+		CurrentAmount:               6000,
+		RemainingAmount:             6000,
+		MonthsRemaining:             1,
+		RequiredMonthlyContribution: 6000,
+	}
+	actualValue := goal.GetProgress(6000, 0, 0, now.Unix(), time.UTC)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestSavingsGoalGetProgress_TargetDateInNextMonthButEarlierDay(t *testing.T) {
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	goal := &SavingsGoal{TargetAmount: 12000, TargetDate: time.Date(2024, 2, 10, 0, 0, 0, 0, time.UTC).Unix()}
+
+	expectedValue := &SavingsGoalProgress{
+		CurrentAmount:               6000,
+		RemainingAmount:             6000,
+		MonthsRemaining:             1,
+		RequiredMonthlyContribution: 6000,
+	}
+	actualValue := goal.GetProgress(6000, 0, 0, now.Unix(), time.UTC)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestSavingsGoalGetProgress_NoTargetDate(t *testing.T) {
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	goal := &SavingsGoal{TargetAmount: 12000, TargetDate: 0}
+
+	expectedValue := &SavingsGoalProgress{
+		CurrentAmount:               6000,
+		RemainingAmount:             6000,
+		MonthsRemaining:             0,
+		RequiredMonthlyContribution: 6000,
+	}
+	actualValue := goal.GetProgress(6000, 0, 0, now.Unix(), time.UTC)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestSavingsGoalGetProgress_GoalAlreadyReached(t *testing.T) {
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	goal := &SavingsGoal{TargetAmount: 12000, TargetDate: time.Date(2024, 7, 15, 0, 0, 0, 0, time.UTC).Unix()}
+
+	expectedValue := &SavingsGoalProgress{
+		CurrentAmount:              12000,
+		AverageMonthlyContribution: 1000,
+	}
+	actualValue := goal.GetProgress(12000, 3000, 3, now.Unix(), time.UTC)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestSavingsGoalGetProgress_GoalExceededWithPastTargetDate(t *testing.T) {
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	goal := &SavingsGoal{TargetAmount: 12000, TargetDate: time.Date(2023, 12, 1, 0, 0, 0, 0, time.UTC).Unix()}
+
+	expectedValue := &SavingsGoalProgress{
+		CurrentAmount: 15000,
+	}
+	actualValue := goal.GetProgress(15000, 0, 0, now.Unix(), time.UTC)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestSavingsGoalGetProgress_NegativeContribution(t *testing.T) {
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	goal := &SavingsGoal{TargetAmount: 12000, TargetDate: time.Date(2024, 7, 15, 0, 0, 0, 0, time.UTC).Unix()}
+
+	expectedValue := &SavingsGoalProgress{
+		CurrentAmount:               6000,
+		RemainingAmount:             6000,
+		MonthsRemaining:             6,
+		RequiredMonthlyContribution: 1000,
+		AverageMonthlyContribution:  -1000,
+	}
+	actualValue := goal.GetProgress(6000, -3000, 3, now.Unix(), time.UTC)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestSavingsGoalGetProgress_Timezone(t *testing.T) {
+	timezone := time.FixedZone("Test Timezone", 9*60*60)
+	unixTime := time.Date(2024, 1, 31, 20, 0, 0, 0, time.UTC).Unix() // 2024-02-01 05:00 in UTC+9
+	goal := &SavingsGoal{TargetAmount: 12000, TargetDate: time.Date(2024, 3, 31, 23, 0, 0, 0, timezone).Unix()}
+
+	progress := goal.GetProgress(0, 0, 0, unixTime, timezone)
+	assert.Equal(t, int32(1), progress.MonthsRemaining)
+	assert.Equal(t, int64(12000), progress.RequiredMonthlyContribution)
+
+	progress = goal.GetProgress(0, 0, 0, unixTime, time.UTC)
+	assert.Equal(t, int32(2), progress.MonthsRemaining)
+	assert.Equal(t, int64(6000), progress.RequiredMonthlyContribution)
+}
@@ -1,10 +1,40 @@
 package models
 
-import "github.com/f97/gofire/pkg/core"
+import (
+	"strings"
+
+	"github.com/f97/gofire/pkg/core"
+)
 
 // TokenMaxUserAgentLength represents the maximum size of user agent stored in database
 const TokenMaxUserAgentLength = 255
 
+// Personal access token scopes, the read scope allows GET requests and the write scope allows other requests of the same resources
+const (
+	TOKEN_SCOPE_ACCOUNTS_READ      = "accounts:read"
+	TOKEN_SCOPE_ACCOUNTS_WRITE     = "accounts:write"
+	TOKEN_SCOPE_TRANSACTIONS_READ  = "transactions:read"
+	TOKEN_SCOPE_TRANSACTIONS_WRITE = "transactions:write"
+	TOKEN_SCOPE_BUDGETS_READ       = "budgets:read"
+	TOKEN_SCOPE_BUDGETS_WRITE      = "budgets:write"
+	TOKEN_SCOPE_EXCHANGE_RATES     = "exchange_rates:read"
+	TOKEN_SCOPE_DATA_EXPORT        = "data:export"
+	TOKEN_SCOPE_DATA_IMPORT        = "data:import"
+)
+
+// ALL_TOKEN_SCOPES represents all scopes which personal access token can be granted
+var ALL_TOKEN_SCOPES = map[string]bool{
+	TOKEN_SCOPE_ACCOUNTS_READ:      true,
+	TOKEN_SCOPE_ACCOUNTS_WRITE:     true,
+	TOKEN_SCOPE_TRANSACTIONS_READ:  true,
+	TOKEN_SCOPE_TRANSACTIONS_WRITE: true,
+	TOKEN_SCOPE_BUDGETS_READ:       true,
+	TOKEN_SCOPE_BUDGETS_WRITE:      true,
+	TOKEN_SCOPE_EXCHANGE_RATES:     true,
+	TOKEN_SCOPE_DATA_EXPORT:        true,
+	TOKEN_SCOPE_DATA_IMPORT:        true,
+}
+
 // TokenRecord represents token data stored in database
 type TokenRecord struct {
 	Uid             int64          `xorm:"PK INDEX(IDX_token_record_uid_type_expired_time)"`
@@ -12,6 +42,8 @@ type TokenRecord struct {
 	TokenType       core.TokenType `xorm:"INDEX(IDX_token_record_uid_type_expired_time) TINYINT NOT NULL"`
 	Secret          string         `xorm:"VARCHAR(10) NOT NULL"`
 	UserAgent       string         `xorm:"VARCHAR(255)"`
+	Name            string         `xorm:"VARCHAR(64)"`
+	Scopes          string         `xorm:"VARCHAR(255)"`
 	CreatedUnixTime int64          `xorm:"PK"`
 	ExpiredUnixTime int64          `xorm:"INDEX(IDX_token_record_uid_type_expired_time)"`
 }
@@ -21,6 +53,19 @@ type TokenRevokeRequest struct {
 	TokenId string `json:"tokenId" binding:"required,notBlank"`
 }
 
+// PersonalAccessTokenCreateRequest represents all parameters of personal access token creation request
+type PersonalAccessTokenCreateRequest struct {
+	Name          string   `json:"name" binding:"required,notBlank,max=64"`
+	ExpiresInDays int32    `json:"expiresInDays" binding:"required,min=1,max=3650"`
+	Scopes        []string `json:"scopes" binding:"required,min=1,max=9,dive,required"`
+}
+
+// PersonalAccessTokenCreateResponse represents the response of personal access token creation, the token is only returned once
+type PersonalAccessTokenCreateResponse struct {
+	Token     string             `json:"token"`
+	TokenInfo *TokenInfoResponse `json:"tokenInfo"`
+}
+
 // TokenRefreshResponse represents all parameters of token refreshing request
 type TokenRefreshResponse struct {
 	NewToken   string         `json:"newToken"`
@@ -33,11 +78,27 @@ type TokenInfoResponse struct {
 	TokenId   string         `json:"tokenId"`
 	TokenType core.TokenType `json:"tokenType"`
 	UserAgent string         `json:"userAgent"`
+	Name      string         `json:"name,omitempty"`
+	Scopes    []string       `json:"scopes,omitempty"`
 	CreatedAt int64          `json:"createdAt"`
 	ExpiredAt int64          `json:"expiredAt"`
 	IsCurrent bool           `json:"isCurrent"`
 }
 
+// GetScopes returns the scopes granted to the token
+func (t *TokenRecord) GetScopes() []string {
+	if t.Scopes == "" {
+		return nil
+	}
+
+	return strings.Split(t.Scopes, ",")
+}
+
+// SetScopes sets the scopes granted to the token
+func (t *TokenRecord) SetScopes(scopes []string) {
+	t.Scopes = strings.Join(scopes, ",")
+}
+
 // TokenInfoResponseSlice represents the slice data structure of TokenInfoResponse
 type TokenInfoResponseSlice []*TokenInfoResponse
 
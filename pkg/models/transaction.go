@@ -47,9 +47,11 @@ type Transaction struct {
 	RelatedAccountId     int64             `xorm:"NOT NULL"`
 	RelatedAccountAmount int64             `xorm:"NOT NULL"`
 	HideAmount           bool              `xorm:"NOT NULL"`
+	Cleared              bool              `xorm:"NOT NULL"`
 	Comment              string            `xorm:"VARCHAR(255) NOT NULL"`
 	GeoLongitude         float64           `xorm:"INDEX(IDX_transaction_uid_deleted_time_longitude_latitude)"`
 	GeoLatitude          float64           `xorm:"INDEX(IDX_transaction_uid_deleted_time_longitude_latitude)"`
+	CreatedUid           int64             `xorm:"NOT NULL"`
 	CreatedIp            string            `xorm:"VARCHAR(39)"`
 	CreatedUnixTime      int64
 	UpdatedUnixTime      int64
@@ -76,6 +78,7 @@ type TransactionCreateRequest struct {
 	TagIds               []string                       `json:"tagIds"`
 	Comment              string                         `json:"comment" binding:"max=255"`
 	GeoLocation          *TransactionGeoLocationRequest `json:"geoLocation" binding:"omitempty"`
+	Splits               []*TransactionSplitRequest     `json:"splits" binding:"omitempty,max=50,dive"`
 }
 
 // TransactionModifyRequest represents all parameters of transaction modification request
@@ -92,6 +95,7 @@ type TransactionModifyRequest struct {
 	TagIds               []string                       `json:"tagIds"`
 	Comment              string                         `json:"comment" binding:"max=255"`
 	GeoLocation          *TransactionGeoLocationRequest `json:"geoLocation" binding:"omitempty"`
+	Splits               []*TransactionSplitRequest     `json:"splits" binding:"omitempty,max=50,dive"`
 }
 
 // TransactionCountRequest represents transaction count request
@@ -141,7 +145,8 @@ type TransactionStatisticRequest struct {
 
 // TransactionAmountsRequest represents all parameters of transaction amounts request
 type TransactionAmountsRequest struct {
-	Query string `form:"query"`
+	Query     string `form:"query"`
+	ConvertTo string `form:"convert_to" binding:"omitempty,len=3,validCurrency"`
 }
 
 // TransactionAmountsRequestItem represents an item of transaction amounts request
@@ -155,6 +160,7 @@ type TransactionAmountsRequestItem struct {
 type TransactionMonthAmountsRequest struct {
 	StartYearMonth string `form:"start_year_month"`
 	EndYearMonth   string `form:"end_year_month"`
+	ConvertTo      string `form:"convert_to" binding:"omitempty,len=3,validCurrency"`
 }
 
 // TransactionGetRequest represents all parameters of transaction getting request
@@ -170,6 +176,23 @@ type TransactionDeleteRequest struct {
 	Id int64 `json:"id,string" binding:"required,min=1"`
 }
 
+// TransactionTrashListRequest represents all parameters of deleted transaction listing request
+type TransactionTrashListRequest struct {
+	Page  int32 `form:"page" binding:"required,min=1"`
+	Count int32 `form:"count" binding:"required,min=1,max=50"`
+}
+
+// TransactionTrashRestoreRequest represents all parameters of deleted transaction restoring request
+type TransactionTrashRestoreRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// TransactionTrashPurgeRequest represents all parameters of deleted transaction purging request
+type TransactionTrashPurgeRequest struct {
+	Id  int64 `json:"id,string" binding:"min=0"`
+	All bool  `json:"all"`
+}
+
 // TransactionAccountsAmount represents transaction accounts amount map
 type TransactionAccountsAmount map[int64]*TransactionAccountAmount
 
@@ -206,6 +229,9 @@ type TransactionInfoResponse struct {
 	Tags                 []*TransactionTagInfoResponse    `json:"tags,omitempty"`
 	Comment              string                           `json:"comment"`
 	GeoLocation          *TransactionGeoLocationResponse  `json:"geoLocation,omitempty"`
+	Splits               []*TransactionSplitInfoResponse  `json:"splits,omitempty"`
+	Cleared              bool                             `json:"cleared"`
+	CreatedUid           int64                            `json:"createdUid,string"`
 	Editable             bool                             `json:"editable"`
 }
 
@@ -227,6 +253,24 @@ type TransactionInfoPageWrapperResponse2 struct {
 	TotalCount int64                        `json:"totalCount"`
 }
 
+// TransactionTrashInfoResponse represents a view-object of deleted transaction in trash bin
+type TransactionTrashInfoResponse struct {
+	*TransactionInfoResponse
+	DeletedTime int64 `json:"deletedTime"`
+	PurgeTime   int64 `json:"purgeTime,omitempty"`
+}
+
+// TransactionTrashInfoPageWrapperResponse represents a response of deleted transaction which contains items and count
+type TransactionTrashInfoPageWrapperResponse struct {
+	Items      []*TransactionTrashInfoResponse `json:"items"`
+	TotalCount int64                           `json:"totalCount"`
+}
+
+// TransactionTrashPurgeResponse represents the result of purging deleted transactions
+type TransactionTrashPurgeResponse struct {
+	PurgedCount int `json:"purgedCount"`
+}
+
 // TransactionStatisticResponse represents an item of transaction amounts
 type TransactionStatisticResponse struct {
 	StartTime int64                               `json:"startTime"`
@@ -243,16 +287,18 @@ type TransactionStatisticResponseItem struct {
 
 // TransactionAmountsResponseItem represents an item of transaction amounts
 type TransactionAmountsResponseItem struct {
-	StartTime int64                                       `json:"startTime"`
-	EndTime   int64                                       `json:"endTime"`
-	Amounts   []*TransactionAmountsResponseItemAmountInfo `json:"amounts"`
+	StartTime   int64                                          `json:"startTime"`
+	EndTime     int64                                          `json:"endTime"`
+	Amounts     []*TransactionAmountsResponseItemAmountInfo    `json:"amounts"`
+	TotalAmount *TransactionAmountsResponseItemTotalAmountInfo `json:"totalAmount,omitempty"`
 }
 
 // TransactionMonthAmountsResponseItem represents an item of transaction month amounts
 type TransactionMonthAmountsResponseItem struct {
-	Year    int32                                       `json:"year"`
-	Month   int32                                       `json:"month"`
-	Amounts []*TransactionAmountsResponseItemAmountInfo `json:"amounts"`
+	Year        int32                                          `json:"year"`
+	Month       int32                                          `json:"month"`
+	Amounts     []*TransactionAmountsResponseItemAmountInfo    `json:"amounts"`
+	TotalAmount *TransactionAmountsResponseItemTotalAmountInfo `json:"totalAmount,omitempty"`
 }
 
 // TransactionAmountsResponseItemAmountInfo represents amount info for an response item
@@ -262,6 +308,22 @@ type TransactionAmountsResponseItemAmountInfo struct {
 	ExpenseAmount int64  `json:"expenseAmount"`
 }
 
+// TransactionAmountsResponseItemTotalAmountInfo represents total amount info which is converted into one currency for an response item
+type TransactionAmountsResponseItemTotalAmountInfo struct {
+	Currency                string                                        `json:"currency"`
+	IncomeAmount            int64                                         `json:"incomeAmount"`
+	ExpenseAmount           int64                                         `json:"expenseAmount"`
+	ExchangeRateDate        string                                        `json:"exchangeRateDate"`
+	ExchangeRates           []*TransactionAmountsResponseItemExchangeRate `json:"exchangeRates"`
+	UnconvertibleCurrencies []string                                      `json:"unconvertibleCurrencies,omitempty"`
+}
+
+// TransactionAmountsResponseItemExchangeRate represents the exchange rate which is used to convert the amount of one currency into total amount
+type TransactionAmountsResponseItemExchangeRate struct {
+	Currency string `json:"currency"`
+	Rate     string `json:"rate"`
+}
+
 // IsEditable returns whether this transaction can be edited
 func (t *Transaction) IsEditable(currentUser *User, utcOffset int16, account *Account, relatedAccount *Account) bool {
 	if currentUser == nil || !currentUser.CanEditTransactionByTransactionTime(t.TransactionTime, utcOffset) {
@@ -325,6 +387,12 @@ func (t *Transaction) ToTransactionInfoResponse(tagIds []int64, editable bool) *
 		geoLocation = nil
 	}
 
+	createdUid := t.CreatedUid
+
+	if createdUid == 0 {
+		createdUid = t.Uid
+	}
+
 	return &TransactionInfoResponse{
 		Id:                   t.TransactionId,
 		TimeSequenceId:       t.TransactionTime,
@@ -340,6 +408,8 @@ func (t *Transaction) ToTransactionInfoResponse(tagIds []int64, editable bool) *
 		TagIds:               utils.Int64ArrayToStringArray(tagIds),
 		Comment:              t.Comment,
 		GeoLocation:          geoLocation,
+		Cleared:              t.Cleared,
+		CreatedUid:           createdUid,
 		Editable:             editable,
 	}
 }
@@ -0,0 +1,70 @@
+package models
+
+// TransactionAttachment represents transaction attachment (e.g. receipt image or document) data stored in database, the file content is stored in object storage
+type TransactionAttachment struct {
+	AttachmentId    int64  `xorm:"PK"`
+	Uid             int64  `xorm:"INDEX(IDX_transaction_attachment_uid_deleted_transaction_id) NOT NULL"`
+	Deleted         bool   `xorm:"INDEX(IDX_transaction_attachment_uid_deleted_transaction_id) NOT NULL"`
+	TransactionId   int64  `xorm:"INDEX(IDX_transaction_attachment_uid_deleted_transaction_id) NOT NULL"`
+	FileName        string `xorm:"VARCHAR(255) NOT NULL"`
+	ContentType     string `xorm:"VARCHAR(64) NOT NULL"`
+	FileSize        int64  `xorm:"NOT NULL"`
+	CreatedUnixTime int64
+	UpdatedUnixTime int64
+	DeletedUnixTime int64
+}
+
+// TransactionAttachmentListRequest represents all parameters of transaction attachment listing request
+type TransactionAttachmentListRequest struct {
+	TransactionId int64 `form:"transaction_id,string" binding:"required,min=1"`
+}
+
+// TransactionAttachmentGetRequest represents all parameters of transaction attachment getting request
+type TransactionAttachmentGetRequest struct {
+	Id int64 `form:"id,string" binding:"required,min=1"`
+}
+
+// TransactionAttachmentDeleteRequest represents all parameters of transaction attachment deleting request
+type TransactionAttachmentDeleteRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// TransactionAttachmentInfoResponse represents a view-object of transaction attachment
+type TransactionAttachmentInfoResponse struct {
+	Id            int64  `json:"id,string"`
+	TransactionId int64  `json:"transactionId,string"`
+	FileName      string `json:"fileName"`
+	ContentType   string `json:"contentType"`
+	FileSize      int64  `json:"fileSize"`
+	CreatedTime   int64  `json:"createdTime"`
+}
+
+// ToTransactionAttachmentInfoResponse returns a view-object according to database model
+func (a *TransactionAttachment) ToTransactionAttachmentInfoResponse() *TransactionAttachmentInfoResponse {
+	return &TransactionAttachmentInfoResponse{
+		Id:            a.AttachmentId,
+		TransactionId: a.TransactionId,
+		FileName:      a.FileName,
+		ContentType:   a.ContentType,
+		FileSize:      a.FileSize,
+		CreatedTime:   a.CreatedUnixTime,
+	}
+}
+
+// TransactionAttachmentInfoResponseSlice represents the slice data structure of TransactionAttachmentInfoResponse
+type TransactionAttachmentInfoResponseSlice []*TransactionAttachmentInfoResponse
+
+// Len returns the count of items
+func (s TransactionAttachmentInfoResponseSlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps two items
+func (s TransactionAttachmentInfoResponseSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less reports whether the first item is less than the second one
+func (s TransactionAttachmentInfoResponseSlice) Less(i, j int) bool {
+	return s[i].CreatedTime < s[j].CreatedTime
+}
@@ -0,0 +1,14 @@
+package models
+
+// TransactionExternalIndex represents the relation of transaction and the id of it in external system (e.g. FITID in ofx statement) stored in database
+type TransactionExternalIndex struct {
+	ExternalIndexId int64  `xorm:"PK"`
+	Uid             int64  `xorm:"INDEX(IDX_transaction_external_index_uid_deleted_account_id_external_id) INDEX(IDX_transaction_external_index_uid_deleted_transaction_id)"`
+	Deleted         bool   `xorm:"INDEX(IDX_transaction_external_index_uid_deleted_account_id_external_id) INDEX(IDX_transaction_external_index_uid_deleted_transaction_id) NOT NULL"`
+	AccountId       int64  `xorm:"INDEX(IDX_transaction_external_index_uid_deleted_account_id_external_id) NOT NULL"`
+	ExternalId      string `xorm:"VARCHAR(255) INDEX(IDX_transaction_external_index_uid_deleted_account_id_external_id) NOT NULL"`
+	TransactionId   int64  `xorm:"INDEX(IDX_transaction_external_index_uid_deleted_transaction_id) NOT NULL"`
+	CreatedUnixTime int64
+	UpdatedUnixTime int64
+	DeletedUnixTime int64
+}
@@ -0,0 +1,56 @@
+package models
+
+// ImportTransaction represents a transaction parsed from imported file, whose account, category and tags are still names
+type ImportTransaction struct {
+	LineNumber             int
+	ExternalId             string
+	Type                   TransactionDbType
+	TransactionUnixTime    int64
+	TimezoneUtcOffset      int16
+	CategoryName           string
+	SubCategoryName        string
+	AccountName            string
+	AccountCurrency        string
+	Amount                 int64
+	RelatedAccountName     string
+	RelatedAccountCurrency string
+	RelatedAccountAmount   int64
+	TagNames               []string
+	Comment                string
+}
+
+// DataImportRequest represents all parameters of data import request
+type DataImportRequest struct {
+	CreateMissing bool `form:"createMissing"`
+	DryRun        bool `form:"dryRun"`
+}
+
+// StatementImportRequest represents all parameters of bank statement import request
+type StatementImportRequest struct {
+	AccountId         int64 `form:"accountId,string" binding:"required,min=1"`
+	IncomeCategoryId  int64 `form:"incomeCategoryId,string" binding:"required,min=1"`
+	ExpenseCategoryId int64 `form:"expenseCategoryId,string" binding:"required,min=1"`
+	DryRun            bool  `form:"dryRun"`
+}
+
+// DataImportResponse represents a view-object of data import result
+type DataImportResponse struct {
+	DryRun               bool                          `json:"dryRun"`
+	TotalCount           int                           `json:"totalCount"`
+	ImportedCount        int                           `json:"importedCount"`
+	SkippedCount         int                           `json:"skippedCount"`
+	NewAccountNames      []string                      `json:"newAccountNames"`
+	NewCategoryNames     []string                      `json:"newCategoryNames"`
+	NewTagNames          []string                      `json:"newTagNames"`
+	MissingAccountNames  []string                      `json:"missingAccountNames,omitempty"`
+	MissingCategoryNames []string                      `json:"missingCategoryNames,omitempty"`
+	MissingTagNames      []string                      `json:"missingTagNames,omitempty"`
+	Conflicts            []*DataImportConflictResponse `json:"conflicts"`
+}
+
+// DataImportConflictResponse represents an imported transaction whose time conflicts with existed transactions
+type DataImportConflictResponse struct {
+	LineNumber            int      `json:"lineNumber"`
+	Time                  int64    `json:"time"`
+	ExistedTransactionIds []string `json:"existedTransactionIds"`
+}
@@ -0,0 +1,214 @@
+package models
+
+import (
+	"strings"
+
+	"github.com/f97/gofire/pkg/utils"
+)
+
+// TransactionRule represents an auto-categorization rule of transaction stored in database
+type TransactionRule struct {
+	RuleId           int64             `xorm:"PK"`
+	Uid              int64             `xorm:"INDEX(IDX_transaction_rule_uid_deleted_order) NOT NULL"`
+	Deleted          bool              `xorm:"INDEX(IDX_transaction_rule_uid_deleted_order) NOT NULL"`
+	Name             string            `xorm:"VARCHAR(64) NOT NULL"`
+	DisplayOrder     int32             `xorm:"INDEX(IDX_transaction_rule_uid_deleted_order) NOT NULL"`
+	Disabled         bool              `xorm:"NOT NULL"`
+	TransactionType  TransactionDbType `xorm:"NOT NULL"`
+	CommentKeyword   string            `xorm:"VARCHAR(64) NOT NULL"`
+	MinAmount        int64             `xorm:"NOT NULL"`
+	MaxAmount        int64             `xorm:"NOT NULL"`
+	AccountId        int64             `xorm:"NOT NULL"`
+	TargetCategoryId int64             `xorm:"NOT NULL"`
+	TargetTagIds     string            `xorm:"VARCHAR(255) NOT NULL"`
+	TargetComment    string            `xorm:"VARCHAR(255) NOT NULL"`
+	CreatedUnixTime  int64
+	UpdatedUnixTime  int64
+	DeletedUnixTime  int64
+}
+
+// TransactionRuleGetRequest represents all parameters of transaction rule getting request
+type TransactionRuleGetRequest struct {
+	Id int64 `form:"id,string" binding:"required,min=1"`
+}
+
+// TransactionRuleCreateRequest represents all parameters of transaction rule creation request
+type TransactionRuleCreateRequest struct {
+	Name             string          `json:"name" binding:"required,notBlank,max=64"`
+	Disabled         bool            `json:"disabled"`
+	TransactionType  TransactionType `json:"transactionType" binding:"min=0,max=4"`
+	CommentKeyword   string          `json:"commentKeyword" binding:"max=64"`
+	MinAmount        int64           `json:"minAmount" binding:"min=0,max=99999999999"`
+	MaxAmount        int64           `json:"maxAmount" binding:"min=0,max=99999999999"`
+	AccountId        int64           `json:"accountId,string" binding:"min=0"`
+	TargetCategoryId int64           `json:"targetCategoryId,string" binding:"min=0"`
+	TargetTagIds     []string        `json:"targetTagIds" binding:"max=10"`
+	TargetComment    string          `json:"targetComment" binding:"max=255"`
+}
+
+// TransactionRuleModifyRequest represents all parameters of transaction rule modification request
+type TransactionRuleModifyRequest struct {
+	Id               int64           `json:"id,string" binding:"required,min=1"`
+	Name             string          `json:"name" binding:"required,notBlank,max=64"`
+	Disabled         bool            `json:"disabled"`
+	TransactionType  TransactionType `json:"transactionType" binding:"min=0,max=4"`
+	CommentKeyword   string          `json:"commentKeyword" binding:"max=64"`
+	MinAmount        int64           `json:"minAmount" binding:"min=0,max=99999999999"`
+	MaxAmount        int64           `json:"maxAmount" binding:"min=0,max=99999999999"`
+	AccountId        int64           `json:"accountId,string" binding:"min=0"`
+	TargetCategoryId int64           `json:"targetCategoryId,string" binding:"min=0"`
+	TargetTagIds     []string        `json:"targetTagIds" binding:"max=10"`
+	TargetComment    string          `json:"targetComment" binding:"max=255"`
+}
+
+// TransactionRuleMoveRequest represents all parameters of transaction rule moving request
+type TransactionRuleMoveRequest struct {
+	NewDisplayOrders []*TransactionRuleNewDisplayOrderRequest `json:"newDisplayOrders"`
+}
+
+// TransactionRuleNewDisplayOrderRequest represents a data pair of id and display order
+type TransactionRuleNewDisplayOrderRequest struct {
+	Id           int64 `json:"id,string" binding:"required,min=1"`
+	DisplayOrder int32 `json:"displayOrder"`
+}
+
+// TransactionRuleDeleteRequest represents all parameters of transaction rule deleting request
+type TransactionRuleDeleteRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// TransactionRuleApplyRequest represents all parameters of re-applying transaction rules request
+type TransactionRuleApplyRequest struct {
+	Months int32 `json:"months" binding:"required,min=1,max=120"`
+}
+
+// TransactionRuleApplyResponse represents the result of re-applying transaction rules
+type TransactionRuleApplyResponse struct {
+	UpdatedCount int `json:"updatedCount"`
+}
+
+// TransactionRuleInfoResponse represents a view-object of transaction rule
+type TransactionRuleInfoResponse struct {
+	Id               int64           `json:"id,string"`
+	Name             string          `json:"name"`
+	DisplayOrder     int32           `json:"displayOrder"`
+	Disabled         bool            `json:"disabled"`
+	TransactionType  TransactionType `json:"transactionType"`
+	CommentKeyword   string          `json:"commentKeyword"`
+	MinAmount        int64           `json:"minAmount"`
+	MaxAmount        int64           `json:"maxAmount"`
+	AccountId        int64           `json:"accountId,string,omitempty"`
+	TargetCategoryId int64           `json:"targetCategoryId,string,omitempty"`
+	TargetTagIds     []string        `json:"targetTagIds"`
+	TargetComment    string          `json:"targetComment"`
+}
+
+// HasCondition returns whether the transaction rule has at least one condition
+func (r *TransactionRule) HasCondition() bool {
+	return r.TransactionType != 0 || r.CommentKeyword != "" || r.MinAmount > 0 || r.MaxAmount > 0 || r.AccountId > 0
+}
+
+// HasAction returns whether the transaction rule has at least one action
+func (r *TransactionRule) HasAction() bool {
+	return r.TargetCategoryId > 0 || r.TargetTagIds != "" || r.TargetComment != ""
+}
+
+// IsMatched returns whether the specified transaction matches all conditions of the transaction rule
+func (r *TransactionRule) IsMatched(transaction *Transaction) bool {
+	if r.Disabled {
+		return false
+	}
+
+	if transaction.Type != TRANSACTION_DB_TYPE_INCOME && transaction.Type != TRANSACTION_DB_TYPE_EXPENSE && transaction.Type != TRANSACTION_DB_TYPE_TRANSFER_OUT {
+		return false
+	}
+
+	if r.TransactionType != 0 && r.TransactionType != transaction.Type {
+		return false
+	}
+
+	if r.AccountId > 0 && r.AccountId != transaction.AccountId {
+		return false
+	}
+
+	if r.MinAmount > 0 && transaction.Amount < r.MinAmount {
+		return false
+	}
+
+	if r.MaxAmount > 0 && transaction.Amount > r.MaxAmount {
+		return false
+	}
+
+	if r.CommentKeyword != "" && !strings.Contains(strings.ToLower(transaction.Comment), strings.ToLower(r.CommentKeyword)) {
+		return false
+	}
+
+	return true
+}
+
+// GetTargetTagIds returns the tag ids which would be added by transaction rule
+func (r *TransactionRule) GetTargetTagIds() []int64 {
+	if r.TargetTagIds == "" {
+		return []int64{}
+	}
+
+	tagIds, err := utils.StringArrayToInt64Array(strings.Split(r.TargetTagIds, ","))
+
+	if err != nil {
+		return []int64{}
+	}
+
+	return tagIds
+}
+
+// SetTargetTagIds sets the tag ids which would be added by transaction rule
+func (r *TransactionRule) SetTargetTagIds(tagIds []int64) {
+	r.TargetTagIds = strings.Join(utils.Int64ArrayToStringArray(tagIds), ",")
+}
+
+// ToTransactionRuleInfoResponse returns a view-object according to database model
+func (r *TransactionRule) ToTransactionRuleInfoResponse() *TransactionRuleInfoResponse {
+	var transactionType TransactionType
+
+	switch r.TransactionType {
+	case TRANSACTION_DB_TYPE_INCOME:
+		transactionType = TRANSACTION_TYPE_INCOME
+	case TRANSACTION_DB_TYPE_EXPENSE:
+		transactionType = TRANSACTION_TYPE_EXPENSE
+	case TRANSACTION_DB_TYPE_TRANSFER_OUT:
+		transactionType = TRANSACTION_TYPE_TRANSFER
+	}
+
+	return &TransactionRuleInfoResponse{
+		Id:               r.RuleId,
+		Name:             r.Name,
+		DisplayOrder:     r.DisplayOrder,
+		Disabled:         r.Disabled,
+		TransactionType:  transactionType,
+		CommentKeyword:   r.CommentKeyword,
+		MinAmount:        r.MinAmount,
+		MaxAmount:        r.MaxAmount,
+		AccountId:        r.AccountId,
+		TargetCategoryId: r.TargetCategoryId,
+		TargetTagIds:     utils.Int64ArrayToStringArray(r.GetTargetTagIds()),
+		TargetComment:    r.TargetComment,
+	}
+}
+
+// TransactionRuleInfoResponseSlice represents the slice data structure of TransactionRuleInfoResponse
+type TransactionRuleInfoResponseSlice []*TransactionRuleInfoResponse
+
+// Len returns the count of items
+func (s TransactionRuleInfoResponseSlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps two items
+func (s TransactionRuleInfoResponseSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less reports whether the first item is less than the second one
+func (s TransactionRuleInfoResponseSlice) Less(i, j int) bool {
+	return s[i].DisplayOrder < s[j].DisplayOrder
+}
@@ -0,0 +1,209 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func getTestTransactionRuleTransaction() *Transaction {
+	return &Transaction{
+		Type:      TRANSACTION_DB_TYPE_EXPENSE,
+		AccountId: 1,
+		Amount:    1000,
+		Comment:   "Morning Coffee at Station",
+	}
+}
+
+func TestTransactionRuleIsMatched_DisabledRule(t *testing.T) {
+	rule := &TransactionRule{Disabled: true, TransactionType: TRANSACTION_DB_TYPE_EXPENSE}
+
+	expectedValue := false
+	actualValue := rule.IsMatched(getTestTransactionRuleTransaction())
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestTransactionRuleIsMatched_MatchedTransactionType(t *testing.T) {
+	rule := &TransactionRule{TransactionType: TRANSACTION_DB_TYPE_EXPENSE}
+
+	expectedValue := true
+	actualValue := rule.IsMatched(getTestTransactionRuleTransaction())
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestTransactionRuleIsMatched_MismatchedTransactionType(t *testing.T) {
+	rule := &TransactionRule{TransactionType: TRANSACTION_DB_TYPE_INCOME}
+
+	expectedValue := false
+	actualValue := rule.IsMatched(getTestTransactionRuleTransaction())
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestTransactionRuleIsMatched_MatchedAccount(t *testing.T) {
+	rule := &TransactionRule{AccountId: 1}
+
+	expectedValue := true
+	actualValue := rule.IsMatched(getTestTransactionRuleTransaction())
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestTransactionRuleIsMatched_MismatchedAccount(t *testing.T) {
+	rule := &TransactionRule{AccountId: 2}
+
+	expectedValue := false
+	actualValue := rule.IsMatched(getTestTransactionRuleTransaction())
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestTransactionRuleIsMatched_AmountEqualsToMinAmount(t *testing.T) {
+	rule := &TransactionRule{MinAmount: 1000}
+
+	expectedValue := true
+	actualValue := rule.IsMatched(getTestTransactionRuleTransaction())
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestTransactionRuleIsMatched_AmountLessThanMinAmount(t *testing.T) {
+	rule := &TransactionRule{MinAmount: 1001}
+
+	expectedValue := false
+	actualValue := rule.IsMatched(getTestTransactionRuleTransaction())
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestTransactionRuleIsMatched_AmountEqualsToMaxAmount(t *testing.T) {
+	rule := &TransactionRule{MaxAmount: 1000}
+
+	expectedValue := true
+	actualValue := rule.IsMatched(getTestTransactionRuleTransaction())
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestTransactionRuleIsMatched_AmountGreaterThanMaxAmount(t *testing.T) {
+	rule := &TransactionRule{MaxAmount: 999}
+
+	expectedValue := false
+	actualValue := rule.IsMatched(getTestTransactionRuleTransaction())
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestTransactionRuleIsMatched_AmountInRange(t *testing.T) {
+	rule := &TransactionRule{MinAmount: 500, MaxAmount: 1500}
+
+	expectedValue := true
+	actualValue := rule.IsMatched(getTestTransactionRuleTransaction())
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestTransactionRuleIsMatched_KeywordInDifferentCase(t *testing.T) {
+	rule := &TransactionRule{CommentKeyword: "coffee"}
+
+	expectedValue := true
+	actualValue := rule.IsMatched(getTestTransactionRuleTransaction())
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestTransactionRuleIsMatched_KeywordNotFound(t *testing.T) {
+	rule := &TransactionRule{CommentKeyword: "tea"}
+
+	expectedValue := false
+	actualValue := rule.IsMatched(getTestTransactionRuleTransaction())
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestTransactionRuleIsMatched_AllConditionsMatched(t *testing.T) {
+	rule := &TransactionRule{TransactionType: TRANSACTION_DB_TYPE_EXPENSE, AccountId: 1, MinAmount: 1, MaxAmount: 1000, CommentKeyword: "STATION"}
+
+	expectedValue := true
+	actualValue := rule.IsMatched(getTestTransactionRuleTransaction())
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestTransactionRuleIsMatched_OneOfConditionsMismatched(t *testing.T) {
+	rule := &TransactionRule{TransactionType: TRANSACTION_DB_TYPE_EXPENSE, AccountId: 1, CommentKeyword: "tea"}
+
+	expectedValue := false
+	actualValue := rule.IsMatched(getTestTransactionRuleTransaction())
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestTransactionRuleIsMatched_NoCondition(t *testing.T) {
+	rule := &TransactionRule{}
+
+	expectedValue := true
+	actualValue := rule.IsMatched(getTestTransactionRuleTransaction())
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestTransactionRuleIsMatched_ModifyBalanceTransaction(t *testing.T) {
+	rule := &TransactionRule{CommentKeyword: "salary"}
+
+	expectedValue := false
+	actualValue := rule.IsMatched(&Transaction{Type: TRANSACTION_DB_TYPE_MODIFY_BALANCE, Comment: "salary"})
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestTransactionRuleIsMatched_IncomeTransaction(t *testing.T) {
+	rule := &TransactionRule{CommentKeyword: "salary"}
+
+	expectedValue := true
+	actualValue := rule.IsMatched(&Transaction{Type: TRANSACTION_DB_TYPE_INCOME, Comment: "salary"})
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestTransactionRuleIsMatched_ExpenseTransaction(t *testing.T) {
+	rule := &TransactionRule{CommentKeyword: "salary"}
+
+	expectedValue := true
+	actualValue := rule.IsMatched(&Transaction{Type: TRANSACTION_DB_TYPE_EXPENSE, Comment: "salary"})
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestTransactionRuleIsMatched_TransferOutTransaction(t *testing.T) {
+	rule := &TransactionRule{CommentKeyword: "salary"}
+
+	expectedValue := true
+	actualValue := rule.IsMatched(&Transaction{Type: TRANSACTION_DB_TYPE_TRANSFER_OUT, Comment: "salary"})
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestTransactionRuleIsMatched_TransferInTransaction(t *testing.T) {
+	rule := &TransactionRule{CommentKeyword: "salary"}
+
+	expectedValue := false
+	actualValue := rule.IsMatched(&Transaction{Type: TRANSACTION_DB_TYPE_TRANSFER_IN, Comment: "salary"})
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestTransactionRuleGetTargetTagIds_EmptyTagIds(t *testing.T) {
+	rule := &TransactionRule{}
+
+	expectedValue := []int64{}
+	actualValue := rule.GetTargetTagIds()
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestTransactionRuleSetTargetTagIds(t *testing.T) {
+	rule := &TransactionRule{}
+	rule.SetTargetTagIds([]int64{3, 1, 2})
+
+	expectedValue := "3,1,2"
+	actualValue := rule.TargetTagIds
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestTransactionRuleGetTargetTagIds_ValidTagIds(t *testing.T) {
+	rule := &TransactionRule{TargetTagIds: "3,1,2"}
+
+	expectedValue := []int64{3, 1, 2}
+	actualValue := rule.GetTargetTagIds()
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestTransactionRuleGetTargetTagIds_InvalidTagIds(t *testing.T) {
+	rule := &TransactionRule{TargetTagIds: "1,abc"}
+
+	expectedValue := []int64{}
+	actualValue := rule.GetTargetTagIds()
+	assert.Equal(t, expectedValue, actualValue)
+}
@@ -0,0 +1,304 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/f97/gofire/pkg/utils"
+)
+
+// TransactionScheduleFrequency represents how often a transaction schedule occurs
+type TransactionScheduleFrequency byte
+
+// Transaction schedule frequencies
+const (
+	TRANSACTION_SCHEDULE_FREQUENCY_DAILY             TransactionScheduleFrequency = 1
+	TRANSACTION_SCHEDULE_FREQUENCY_WEEKLY            TransactionScheduleFrequency = 2
+	TRANSACTION_SCHEDULE_FREQUENCY_MONTHLY           TransactionScheduleFrequency = 3
+	TRANSACTION_SCHEDULE_FREQUENCY_YEARLY            TransactionScheduleFrequency = 4
+	TRANSACTION_SCHEDULE_FREQUENCY_LAST_BUSINESS_DAY TransactionScheduleFrequency = 5
+)
+
+// maxTransactionScheduleLookupSteps is the max count of periods to look up when searching next occurrence
+const maxTransactionScheduleLookupSteps = 24
+
+// TransactionSchedule represents recurring transaction template stored in database
+type TransactionSchedule struct {
+	ScheduleId           int64                        `xorm:"PK"`
+	Uid                  int64                        `xorm:"INDEX(IDX_transaction_schedule_uid_deleted) NOT NULL"`
+	Deleted              bool                         `xorm:"INDEX(IDX_transaction_schedule_uid_deleted) INDEX(IDX_transaction_schedule_deleted_disabled_next_run_time) NOT NULL"`
+	Disabled             bool                         `xorm:"INDEX(IDX_transaction_schedule_deleted_disabled_next_run_time) NOT NULL"`
+	Type                 TransactionDbType            `xorm:"NOT NULL"`
+	CategoryId           int64                        `xorm:"NOT NULL"`
+	AccountId            int64                        `xorm:"NOT NULL"`
+	Amount               int64                        `xorm:"NOT NULL"`
+	RelatedAccountId     int64                        `xorm:"NOT NULL"`
+	RelatedAccountAmount int64                        `xorm:"NOT NULL"`
+	HideAmount           bool                         `xorm:"NOT NULL"`
+	TagIds               string                       `xorm:"VARCHAR(255) NOT NULL"`
+	Comment              string                       `xorm:"VARCHAR(255) NOT NULL"`
+	Frequency            TransactionScheduleFrequency `xorm:"TINYINT NOT NULL"`
+	DayOfMonth           int32                        `xorm:"NOT NULL"`
+	StartTime            int64                        `xorm:"NOT NULL"`
+	EndTime              int64                        `xorm:"NOT NULL"`
+	TimezoneUtcOffset    int16                        `xorm:"NOT NULL"`
+	NextRunTime          int64                        `xorm:"INDEX(IDX_transaction_schedule_deleted_disabled_next_run_time) NOT NULL"`
+	LastRunTime          int64                        `xorm:"NOT NULL"`
+	LoanId               int64                        `xorm:"NOT NULL"`
+	CreatedIp            string                       `xorm:"VARCHAR(39)"`
+	CreatedUnixTime      int64
+	UpdatedUnixTime      int64
+	DeletedUnixTime      int64
+}
+
+// TransactionScheduleGetRequest represents all parameters of transaction schedule getting request
+type TransactionScheduleGetRequest struct {
+	Id int64 `form:"id,string" binding:"required,min=1"`
+}
+
+// TransactionScheduleCreateRequest represents all parameters of transaction schedule creation request
+type TransactionScheduleCreateRequest struct {
+	Type                 TransactionType              `json:"type" binding:"required"`
+	CategoryId           int64                        `json:"categoryId,string"`
+	SourceAccountId      int64                        `json:"sourceAccountId,string" binding:"required,min=1"`
+	DestinationAccountId int64                        `json:"destinationAccountId,string" binding:"min=0"`
+	SourceAmount         int64                        `json:"sourceAmount" binding:"min=-99999999999,max=99999999999"`
+	DestinationAmount    int64                        `json:"destinationAmount" binding:"min=-99999999999,max=99999999999"`
+	HideAmount           bool                         `json:"hideAmount"`
+	TagIds               []string                     `json:"tagIds"`
+	Comment              string                       `json:"comment" binding:"max=255"`
+	Frequency            TransactionScheduleFrequency `json:"frequency" binding:"required,min=1,max=5"`
+	DayOfMonth           int32                        `json:"dayOfMonth" binding:"min=0,max=31"`
+	StartTime            int64                        `json:"startTime" binding:"required,min=1"`
+	EndTime              int64                        `json:"endTime" binding:"min=0"`
+	UtcOffset            int16                        `json:"utcOffset" binding:"min=-720,max=840"`
+}
+
+// TransactionScheduleModifyRequest represents all parameters of transaction schedule modification request
+type TransactionScheduleModifyRequest struct {
+	Id                   int64                        `json:"id,string" binding:"required,min=1"`
+	CategoryId           int64                        `json:"categoryId,string"`
+	SourceAccountId      int64                        `json:"sourceAccountId,string" binding:"required,min=1"`
+	DestinationAccountId int64                        `json:"destinationAccountId,string" binding:"min=0"`
+	SourceAmount         int64                        `json:"sourceAmount" binding:"min=-99999999999,max=99999999999"`
+	DestinationAmount    int64                        `json:"destinationAmount" binding:"min=-99999999999,max=99999999999"`
+	HideAmount           bool                         `json:"hideAmount"`
+	TagIds               []string                     `json:"tagIds"`
+	Comment              string                       `json:"comment" binding:"max=255"`
+	Frequency            TransactionScheduleFrequency `json:"frequency" binding:"required,min=1,max=5"`
+	DayOfMonth           int32                        `json:"dayOfMonth" binding:"min=0,max=31"`
+	StartTime            int64                        `json:"startTime" binding:"required,min=1"`
+	EndTime              int64                        `json:"endTime" binding:"min=0"`
+	UtcOffset            int16                        `json:"utcOffset" binding:"min=-720,max=840"`
+	Disabled             bool                         `json:"disabled"`
+}
+
+// TransactionScheduleDeleteRequest represents all parameters of transaction schedule deleting request
+type TransactionScheduleDeleteRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// TransactionScheduleInfoResponse represents a view-object of transaction schedule
+type TransactionScheduleInfoResponse struct {
+	Id                   int64                        `json:"id,string"`
+	Type                 TransactionType              `json:"type"`
+	CategoryId           int64                        `json:"categoryId,string"`
+	SourceAccountId      int64                        `json:"sourceAccountId,string"`
+	DestinationAccountId int64                        `json:"destinationAccountId,string,omitempty"`
+	SourceAmount         int64                        `json:"sourceAmount"`
+	DestinationAmount    int64                        `json:"destinationAmount,omitempty"`
+	HideAmount           bool                         `json:"hideAmount"`
+	TagIds               []string                     `json:"tagIds"`
+	Comment              string                       `json:"comment"`
+	Frequency            TransactionScheduleFrequency `json:"frequency"`
+	DayOfMonth           int32                        `json:"dayOfMonth"`
+	StartTime            int64                        `json:"startTime"`
+	EndTime              int64                        `json:"endTime"`
+	UtcOffset            int16                        `json:"utcOffset"`
+	NextRunTime          int64                        `json:"nextRunTime"`
+	LastRunTime          int64                        `json:"lastRunTime"`
+	LoanId               int64                        `json:"loanId,string,omitempty"`
+	Disabled             bool                         `json:"disabled"`
+}
+
+// GetTagIds returns the tag ids of transactions created by this schedule
+func (s *TransactionSchedule) GetTagIds() ([]int64, error) {
+	if s.TagIds == "" {
+		return []int64{}, nil
+	}
+
+	return utils.StringArrayToInt64Array(strings.Split(s.TagIds, ","))
+}
+
+// SetTagIds sets the tag ids of transactions created by this schedule
+func (s *TransactionSchedule) SetTagIds(tagIds []int64) {
+	s.TagIds = strings.Join(utils.Int64ArrayToStringArray(utils.ToUniqueInt64Slice(tagIds)), ",")
+}
+
+// GetNextRunTime returns the first occurrence unix time which is later than the given unix time, or 0 if the schedule will not occur any more
+func (s *TransactionSchedule) GetNextRunTime(afterUnixTime int64) int64 {
+	timezone := time.FixedZone("Schedule Timezone", int(s.TimezoneUtcOffset)*60)
+	startTime := time.Unix(s.StartTime, 0).In(timezone)
+	baseTime := startTime
+
+	if afterUnixTime >= s.StartTime {
+		baseTime = time.Unix(afterUnixTime, 0).In(timezone)
+	}
+
+	var nextTime time.Time
+	found := false
+
+	if s.Frequency == TRANSACTION_SCHEDULE_FREQUENCY_DAILY || s.Frequency == TRANSACTION_SCHEDULE_FREQUENCY_WEEKLY {
+		periodDays := 1
+
+		if s.Frequency == TRANSACTION_SCHEDULE_FREQUENCY_WEEKLY {
+			periodDays = 7
+		}
+
+		nextTime = startTime
+
+		if afterUnixTime >= s.StartTime {
+			elapsedPeriods := (afterUnixTime-s.StartTime)/int64(periodDays*86400) + 1
+			nextTime = startTime.AddDate(0, 0, int(elapsedPeriods)*periodDays)
+		}
+
+		found = true
+	} else if s.Frequency == TRANSACTION_SCHEDULE_FREQUENCY_MONTHLY || s.Frequency == TRANSACTION_SCHEDULE_FREQUENCY_LAST_BUSINESS_DAY {
+		for i := 0; i < maxTransactionScheduleLookupSteps && !found; i++ {
+			year, month := baseTime.Year(), baseTime.Month()+time.Month(i)
+			year, month = year+int(month-1)/12, (month-1)%12+1
+
+			var day int
+
+			if s.Frequency == TRANSACTION_SCHEDULE_FREQUENCY_MONTHLY {
+				day = s.getDayOfMonth(year, month, startTime.Day())
+			} else {
+				day = utils.GetLastBusinessDayOfMonth(year, month)
+			}
+
+			candidate := time.Date(year, month, day, startTime.Hour(), startTime.Minute(), startTime.Second(), 0, timezone)
+
+			if candidate.Unix() >= s.StartTime && candidate.Unix() > afterUnixTime {
+				nextTime = candidate
+				found = true
+			}
+		}
+	} else if s.Frequency == TRANSACTION_SCHEDULE_FREQUENCY_YEARLY {
+		for i := 0; i < maxTransactionScheduleLookupSteps && !found; i++ {
+			year := baseTime.Year() + i
+			day := startTime.Day()
+
+			if daysInMonth := utils.GetDaysInMonth(year, startTime.Month()); day > daysInMonth {
+				day = daysInMonth
+			}
+
+			candidate := time.Date(year, startTime.Month(), day, startTime.Hour(), startTime.Minute(), startTime.Second(), 0, timezone)
+
+			if candidate.Unix() >= s.StartTime && candidate.Unix() > afterUnixTime {
+				nextTime = candidate
+				found = true
+			}
+		}
+	}
+
+	if !found {
+		return 0
+	}
+
+	if s.EndTime > 0 && nextTime.Unix() > s.EndTime {
+		return 0
+	}
+
+	return nextTime.Unix()
+}
+
+// ToTransaction returns a new transaction model which occurs at the given unix time according to this schedule
+func (s *TransactionSchedule) ToTransaction(unixTime int64) *Transaction {
+	return &Transaction{
+		Uid:                  s.Uid,
+		Type:                 s.Type,
+		CategoryId:           s.CategoryId,
+		TransactionTime:      utils.GetMinTransactionTimeFromUnixTime(unixTime),
+		TimezoneUtcOffset:    s.TimezoneUtcOffset,
+		AccountId:            s.AccountId,
+		Amount:               s.Amount,
+		RelatedAccountId:     s.RelatedAccountId,
+		RelatedAccountAmount: s.RelatedAccountAmount,
+		HideAmount:           s.HideAmount,
+		Comment:              s.Comment,
+		CreatedIp:            s.CreatedIp,
+	}
+}
+
+// ToTransactionScheduleInfoResponse returns a view-object according to database model
+func (s *TransactionSchedule) ToTransactionScheduleInfoResponse(tagIds []int64) *TransactionScheduleInfoResponse {
+	var transactionType TransactionType
+
+	if s.Type == TRANSACTION_DB_TYPE_INCOME {
+		transactionType = TRANSACTION_TYPE_INCOME
+	} else if s.Type == TRANSACTION_DB_TYPE_EXPENSE {
+		transactionType = TRANSACTION_TYPE_EXPENSE
+	} else if s.Type == TRANSACTION_DB_TYPE_TRANSFER_OUT {
+		transactionType = TRANSACTION_TYPE_TRANSFER
+	} else {
+		return nil
+	}
+
+	return &TransactionScheduleInfoResponse{
+		Id:                   s.ScheduleId,
+		Type:                 transactionType,
+		CategoryId:           s.CategoryId,
+		SourceAccountId:      s.AccountId,
+		DestinationAccountId: s.RelatedAccountId,
+		SourceAmount:         s.Amount,
+		DestinationAmount:    s.RelatedAccountAmount,
+		HideAmount:           s.HideAmount,
+		TagIds:               utils.Int64ArrayToStringArray(tagIds),
+		Comment:              s.Comment,
+		Frequency:            s.Frequency,
+		DayOfMonth:           s.DayOfMonth,
+		StartTime:            s.StartTime,
+		EndTime:              s.EndTime,
+		UtcOffset:            s.TimezoneUtcOffset,
+		NextRunTime:          s.NextRunTime,
+		LastRunTime:          s.LastRunTime,
+		LoanId:               s.LoanId,
+		Disabled:             s.Disabled,
+	}
+}
+
+func (s *TransactionSchedule) getDayOfMonth(year int, month time.Month, defaultDay int) int {
+	day := int(s.DayOfMonth)
+
+	if day < 1 {
+		day = defaultDay
+	}
+
+	if daysInMonth := utils.GetDaysInMonth(year, month); day > daysInMonth {
+		day = daysInMonth
+	}
+
+	return day
+}
+
+// TransactionScheduleInfoResponseSlice represents the slice data structure of TransactionScheduleInfoResponse
+type TransactionScheduleInfoResponseSlice []*TransactionScheduleInfoResponse
+
+// Len returns the count of items
+func (s TransactionScheduleInfoResponseSlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps two items
+func (s TransactionScheduleInfoResponseSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less reports whether the first item is less than the second one
+func (s TransactionScheduleInfoResponseSlice) Less(i, j int) bool {
+	if s[i].NextRunTime != s[j].NextRunTime {
+		return s[i].NextRunTime < s[j].NextRunTime
+	}
+
+	return s[i].Id < s[j].Id
+}
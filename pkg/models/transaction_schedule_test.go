@@ -0,0 +1,142 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransactionScheduleGetNextRunTime_MonthlyOnDay31InFebruary(t *testing.T) {
+	schedule := &TransactionSchedule{
+		Frequency:  TRANSACTION_SCHEDULE_FREQUENCY_MONTHLY,
+		DayOfMonth: 31,
+		StartTime:  time.Date(2024, 1, 31, 10, 0, 0, 0, time.UTC).Unix(),
+	}
+
+	expectedValue := time.Date(2024, 2, 29, 10, 0, 0, 0, time.UTC).Unix()
+	actualValue := schedule.GetNextRunTime(schedule.StartTime)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestTransactionScheduleGetNextRunTime_MonthlyOnDay31InApril(t *testing.T) {
+	schedule := &TransactionSchedule{
+		Frequency:  TRANSACTION_SCHEDULE_FREQUENCY_MONTHLY,
+		DayOfMonth: 31,
+		StartTime:  time.Date(2024, 1, 31, 10, 0, 0, 0, time.UTC).Unix(),
+	}
+
+	expectedValue := time.Date(2024, 4, 30, 10, 0, 0, 0, time.UTC).Unix()
+	actualValue := schedule.GetNextRunTime(time.Date(2024, 3, 31, 10, 0, 0, 0, time.UTC).Unix())
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestTransactionScheduleGetNextRunTime_MonthlyOnDay31AfterShortMonth(t *testing.T) {
+	schedule := &TransactionSchedule{
+		Frequency:  TRANSACTION_SCHEDULE_FREQUENCY_MONTHLY,
+		DayOfMonth: 31,
+		StartTime:  time.Date(2024, 1, 31, 10, 0, 0, 0, time.UTC).Unix(),
+	}
+
+	expectedValue := time.Date(2024, 5, 31, 10, 0, 0, 0, time.UTC).Unix()
+	actualValue := schedule.GetNextRunTime(time.Date(2024, 4, 30, 10, 0, 0, 0, time.UTC).Unix())
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestTransactionScheduleGetNextRunTime_LastBusinessDayWhenMonthEndsOnSaturday(t *testing.T) {
+	schedule := &TransactionSchedule{
+		Frequency: TRANSACTION_SCHEDULE_FREQUENCY_LAST_BUSINESS_DAY,
+		StartTime: time.Date(2024, 8, 1, 10, 0, 0, 0, time.UTC).Unix(),
+	}
+
+	expectedValue := time.Date(2024, 8, 30, 10, 0, 0, 0, time.UTC).Unix()
+	actualValue := schedule.GetNextRunTime(schedule.StartTime - 1)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestTransactionScheduleGetNextRunTime_LastBusinessDayWhenMonthEndsOnSunday(t *testing.T) {
+	schedule := &TransactionSchedule{
+		Frequency: TRANSACTION_SCHEDULE_FREQUENCY_LAST_BUSINESS_DAY,
+		StartTime: time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC).Unix(),
+	}
+
+	expectedValue := time.Date(2024, 3, 29, 10, 0, 0, 0, time.UTC).Unix()
+	actualValue := schedule.GetNextRunTime(schedule.StartTime - 1)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestTransactionScheduleGetNextRunTime_YearlyOnFebruary29InCommonYear(t *testing.T) {
+	schedule := &TransactionSchedule{
+		Frequency: TRANSACTION_SCHEDULE_FREQUENCY_YEARLY,
+		StartTime: time.Date(2024, 2, 29, 10, 0, 0, 0, time.UTC).Unix(),
+	}
+
+	expectedValue := time.Date(2025, 2, 28, 10, 0, 0, 0, time.UTC).Unix()
+	actualValue := schedule.GetNextRunTime(schedule.StartTime)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestTransactionScheduleGetNextRunTime_YearlyOnFebruary29InLeapYear(t *testing.T) {
+	schedule := &TransactionSchedule{
+		Frequency: TRANSACTION_SCHEDULE_FREQUENCY_YEARLY,
+		StartTime: time.Date(2024, 2, 29, 10, 0, 0, 0, time.UTC).Unix(),
+	}
+
+	expectedValue := time.Date(2028, 2, 29, 10, 0, 0, 0, time.UTC).Unix()
+	actualValue := schedule.GetNextRunTime(time.Date(2027, 2, 28, 10, 0, 0, 0, time.UTC).Unix())
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestTransactionScheduleGetNextRunTime_OccurrenceAtEndTime(t *testing.T) {
+	startTime := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC).Unix()
+	schedule := &TransactionSchedule{
+		Frequency: TRANSACTION_SCHEDULE_FREQUENCY_DAILY,
+		StartTime: startTime,
+		EndTime:   startTime + 86400,
+	}
+
+	expectedValue := startTime + 86400
+	actualValue := schedule.GetNextRunTime(startTime)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestTransactionScheduleGetNextRunTime_OccurrenceLaterThanEndTime(t *testing.T) {
+	startTime := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC).Unix()
+	schedule := &TransactionSchedule{
+		Frequency: TRANSACTION_SCHEDULE_FREQUENCY_DAILY,
+		StartTime: startTime,
+		EndTime:   startTime + 86400,
+	}
+
+	expectedValue := int64(0)
+	actualValue := schedule.GetNextRunTime(startTime + 86400)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestTransactionScheduleGetNextRunTime_MonthlyWithPositiveUtcOffset(t *testing.T) {
+	timezone := time.FixedZone("UTC+8", 8*60*60)
+	schedule := &TransactionSchedule{
+		Frequency:         TRANSACTION_SCHEDULE_FREQUENCY_MONTHLY,
+		DayOfMonth:        31,
+		StartTime:         time.Date(2024, 1, 31, 2, 0, 0, 0, timezone).Unix(),
+		TimezoneUtcOffset: 480,
+	}
+
+	expectedValue := time.Date(2024, 2, 29, 2, 0, 0, 0, timezone).Unix()
+	actualValue := schedule.GetNextRunTime(schedule.StartTime)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestTransactionScheduleGetNextRunTime_MonthlyWithNegativeUtcOffset(t *testing.T) {
+	timezone := time.FixedZone("UTC-5", -5*60*60)
+	schedule := &TransactionSchedule{
+		Frequency:         TRANSACTION_SCHEDULE_FREQUENCY_MONTHLY,
+		DayOfMonth:        31,
+		StartTime:         time.Date(2024, 1, 31, 22, 0, 0, 0, timezone).Unix(),
+		TimezoneUtcOffset: -300,
+	}
+
+	expectedValue := time.Date(2024, 2, 29, 22, 0, 0, 0, timezone).Unix()
+	actualValue := schedule.GetNextRunTime(schedule.StartTime)
+	assert.Equal(t, expectedValue, actualValue)
+}
@@ -0,0 +1,74 @@
+package models
+
+import (
+	"strings"
+
+	"github.com/f97/gofire/pkg/utils"
+)
+
+// TransactionSplit represents a split line of income or expense transaction stored in database
+type TransactionSplit struct {
+	SplitId         int64             `xorm:"PK"`
+	Uid             int64             `xorm:"INDEX(IDX_transaction_split_uid_deleted_transaction_id) INDEX(IDX_transaction_split_uid_deleted_time) NOT NULL"`
+	Deleted         bool              `xorm:"INDEX(IDX_transaction_split_uid_deleted_transaction_id) INDEX(IDX_transaction_split_uid_deleted_time) NOT NULL"`
+	TransactionId   int64             `xorm:"INDEX(IDX_transaction_split_uid_deleted_transaction_id) NOT NULL"`
+	Type            TransactionDbType `xorm:"NOT NULL"`
+	AccountId       int64             `xorm:"NOT NULL"`
+	TransactionTime int64             `xorm:"INDEX(IDX_transaction_split_uid_deleted_time) NOT NULL"`
+	CategoryId      int64             `xorm:"NOT NULL"`
+	Amount          int64             `xorm:"NOT NULL"`
+	TagIds          string            `xorm:"VARCHAR(255) NOT NULL"`
+	Comment         string            `xorm:"VARCHAR(255) NOT NULL"`
+	DisplayOrder    int32             `xorm:"NOT NULL"`
+	CreatedUnixTime int64
+	UpdatedUnixTime int64
+	DeletedUnixTime int64
+}
+
+// TransactionSplitRequest represents all parameters of a transaction split line
+type TransactionSplitRequest struct {
+	CategoryId int64    `json:"categoryId,string" binding:"required,min=1"`
+	Amount     int64    `json:"amount" binding:"min=1,max=99999999999"`
+	TagIds     []string `json:"tagIds" binding:"max=10"`
+	Comment    string   `json:"comment" binding:"max=255"`
+}
+
+// TransactionSplitInfoResponse represents a view-object of transaction split line
+type TransactionSplitInfoResponse struct {
+	Id         int64    `json:"id,string"`
+	CategoryId int64    `json:"categoryId,string"`
+	Amount     int64    `json:"amount"`
+	TagIds     []string `json:"tagIds"`
+	Comment    string   `json:"comment"`
+}
+
+// GetTagIds returns the tag ids of transaction split line
+func (s *TransactionSplit) GetTagIds() []int64 {
+	if s.TagIds == "" {
+		return []int64{}
+	}
+
+	tagIds, err := utils.StringArrayToInt64Array(strings.Split(s.TagIds, ","))
+
+	if err != nil {
+		return []int64{}
+	}
+
+	return tagIds
+}
+
+// SetTagIds sets the tag ids of transaction split line
+func (s *TransactionSplit) SetTagIds(tagIds []int64) {
+	s.TagIds = strings.Join(utils.Int64ArrayToStringArray(tagIds), ",")
+}
+
+// ToTransactionSplitInfoResponse returns a view-object according to database model
+func (s *TransactionSplit) ToTransactionSplitInfoResponse() *TransactionSplitInfoResponse {
+	return &TransactionSplitInfoResponse{
+		Id:         s.SplitId,
+		CategoryId: s.CategoryId,
+		Amount:     s.Amount,
+		TagIds:     utils.Int64ArrayToStringArray(s.GetTagIds()),
+		Comment:    s.Comment,
+	}
+}
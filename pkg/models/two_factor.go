@@ -1,5 +1,11 @@
 package models
 
+// Two factor authorization methods
+const (
+	TWO_FACTOR_METHOD_PASSCODE string = "passcode"
+	TWO_FACTOR_METHOD_WEBAUTHN string = "webauthn"
+)
+
 // TwoFactor represents user 2fa data stored in database
 type TwoFactor struct {
 	Uid             int64  `xorm:"PK"`
@@ -0,0 +1,22 @@
+package models
+
+// UserExternalAuth represents the link between user and the account of external identity provider stored in database
+type UserExternalAuth struct {
+	ExternalAuthId  int64  `xorm:"PK"`
+	Uid             int64  `xorm:"INDEX(IDX_user_external_auth_uid) NOT NULL"`
+	Issuer          string `xorm:"UNIQUE(UQE_user_external_auth_issuer_subject) VARCHAR(255) NOT NULL"`
+	Subject         string `xorm:"UNIQUE(UQE_user_external_auth_issuer_subject) VARCHAR(255) NOT NULL"`
+	CreatedUnixTime int64
+}
+
+// OIDCAuthorizeResponse represents a view-object of openid connect authorization
+type OIDCAuthorizeResponse struct {
+	ProviderName string `json:"providerName"`
+	AuthorizeUrl string `json:"authorizeUrl"`
+}
+
+// OIDCCallbackRequest represents all parameters of openid connect callback request
+type OIDCCallbackRequest struct {
+	Code  string `json:"code" binding:"required,notBlank"`
+	State string `json:"state" binding:"required,notBlank"`
+}
@@ -0,0 +1,124 @@
+package models
+
+// UserWebAuthnCredential represents user webauthn public key credential stored in database
+type UserWebAuthnCredential struct {
+	CredentialId     int64  `xorm:"PK"`
+	Uid              int64  `xorm:"INDEX(IDX_user_webauthn_credential_uid) NOT NULL"`
+	Name             string `xorm:"VARCHAR(64) NOT NULL"`
+	RawId            string `xorm:"UNIQUE(UQE_user_webauthn_credential_raw_id) VARCHAR(512) NOT NULL"`
+	PublicKey        string `xorm:"VARCHAR(1024) NOT NULL"`
+	Algorithm        int    `xorm:"NOT NULL"`
+	SignCount        int64  `xorm:"NOT NULL"`
+	CreatedUnixTime  int64
+	LastUsedUnixTime int64
+}
+
+// UserWebAuthnChallenge represents the webauthn challenge which is waiting for verification stored in database
+type UserWebAuthnChallenge struct {
+	ChallengeId     string `xorm:"PK VARCHAR(32)"`
+	Purpose         string `xorm:"VARCHAR(16) NOT NULL"`
+	Uid             int64  `xorm:"NOT NULL"`
+	Challenge       string `xorm:"VARCHAR(128) NOT NULL"`
+	ExpiredUnixTime int64  `xorm:"INDEX(IDX_user_webauthn_challenge_expired_unix_time) NOT NULL"`
+	CreatedUnixTime int64
+}
+
+// WebAuthnRegisterRequest represents all parameters of webauthn credential registering request
+type WebAuthnRegisterRequest struct {
+	Name              string `json:"name" binding:"required,notBlank,max=64"`
+	Id                string `json:"id" binding:"required,notBlank"`
+	ClientDataJSON    string `json:"clientDataJSON" binding:"required,notBlank"`
+	AttestationObject string `json:"attestationObject" binding:"required,notBlank"`
+}
+
+// WebAuthnLoginRequest represents all parameters of webauthn login request
+type WebAuthnLoginRequest struct {
+	Id                string `json:"id" binding:"required,notBlank"`
+	ClientDataJSON    string `json:"clientDataJSON" binding:"required,notBlank"`
+	AuthenticatorData string `json:"authenticatorData" binding:"required,notBlank"`
+	Signature         string `json:"signature" binding:"required,notBlank"`
+	UserHandle        string `json:"userHandle"`
+}
+
+// WebAuthnCredentialRevokeRequest represents all parameters of webauthn credential revoking request
+type WebAuthnCredentialRevokeRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// WebAuthnCredentialDescriptor represents a view-object of webauthn credential descriptor
+type WebAuthnCredentialDescriptor struct {
+	Type string `json:"type"`
+	Id   string `json:"id"`
+}
+
+// WebAuthnRelyingPartyEntity represents a view-object of webauthn relying party
+type WebAuthnRelyingPartyEntity struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// WebAuthnUserEntity represents a view-object of webauthn user
+type WebAuthnUserEntity struct {
+	Id          string `json:"id"`
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+}
+
+// WebAuthnCredentialParameter represents a view-object of webauthn credential type and algorithm
+type WebAuthnCredentialParameter struct {
+	Type      string `json:"type"`
+	Algorithm int    `json:"alg"`
+}
+
+// WebAuthnAuthenticatorSelection represents a view-object of webauthn authenticator requirements
+type WebAuthnAuthenticatorSelection struct {
+	ResidentKey      string `json:"residentKey"`
+	UserVerification string `json:"userVerification"`
+}
+
+// WebAuthnCreationOptionsResponse represents the options of webauthn credential creation which should be passed to navigator.credentials.create
+type WebAuthnCreationOptionsResponse struct {
+	Challenge              string                          `json:"challenge"`
+	RelyingParty           *WebAuthnRelyingPartyEntity     `json:"rp"`
+	User                   *WebAuthnUserEntity             `json:"user"`
+	PubKeyCredParams       []*WebAuthnCredentialParameter  `json:"pubKeyCredParams"`
+	Timeout                int64                           `json:"timeout"`
+	Attestation            string                          `json:"attestation"`
+	ExcludeCredentials     []*WebAuthnCredentialDescriptor `json:"excludeCredentials"`
+	AuthenticatorSelection *WebAuthnAuthenticatorSelection `json:"authenticatorSelection"`
+}
+
+// WebAuthnRequestOptionsResponse represents the options of webauthn assertion which should be passed to navigator.credentials.get
+type WebAuthnRequestOptionsResponse struct {
+	Challenge        string                          `json:"challenge"`
+	RelyingPartyId   string                          `json:"rpId"`
+	Timeout          int64                           `json:"timeout"`
+	AllowCredentials []*WebAuthnCredentialDescriptor `json:"allowCredentials"`
+	UserVerification string                          `json:"userVerification"`
+}
+
+// WebAuthnCredentialInfoResponse represents a view-object of webauthn credential
+type WebAuthnCredentialInfoResponse struct {
+	Id         int64  `json:"id,string"`
+	Name       string `json:"name"`
+	CreatedAt  int64  `json:"createdAt"`
+	LastUsedAt int64  `json:"lastUsedAt,omitempty"`
+}
+
+// ToWebAuthnCredentialInfoResponse returns a view-object according to database model
+func (c *UserWebAuthnCredential) ToWebAuthnCredentialInfoResponse() *WebAuthnCredentialInfoResponse {
+	return &WebAuthnCredentialInfoResponse{
+		Id:         c.CredentialId,
+		Name:       c.Name,
+		CreatedAt:  c.CreatedUnixTime,
+		LastUsedAt: c.LastUsedUnixTime,
+	}
+}
+
+// ToWebAuthnCredentialDescriptor returns a webauthn credential descriptor according to database model
+func (c *UserWebAuthnCredential) ToWebAuthnCredentialDescriptor() *WebAuthnCredentialDescriptor {
+	return &WebAuthnCredentialDescriptor{
+		Type: "public-key",
+		Id:   c.RawId,
+	}
+}
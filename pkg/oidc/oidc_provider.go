@@ -0,0 +1,359 @@
+package oidc
+
+import (
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/f97/gofire/pkg/core"
+	"github.com/f97/gofire/pkg/errs"
+	"github.com/f97/gofire/pkg/log"
+)
+
+const openIdConfigurationPath = "/.well-known/openid-configuration"
+
+// jwksRefreshInterval is the min interval of reloading json web key set when the key id of identity token is not found
+const jwksRefreshInterval = time.Minute
+
+// OIDCProviderMetadata represents the metadata of openid connect provider returned by discovery endpoint
+type OIDCProviderMetadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JwksUri               string `json:"jwks_uri"`
+}
+
+// OIDCIdTokenClaims represents the claims of identity token issued by openid connect provider
+type OIDCIdTokenClaims struct {
+	jwt.RegisteredClaims
+	Nonce             string `json:"nonce"`
+	Email             string `json:"email"`
+	EmailVerified     bool   `json:"email_verified"`
+	Name              string `json:"name"`
+	PreferredUsername string `json:"preferred_username"`
+}
+
+// OIDCProvider represents an openid connect provider which supports authorization code flow with pkce
+type OIDCProvider struct {
+	IssuerUrl      string
+	ClientId       string
+	ClientSecret   string
+	RedirectUrl    string
+	Scopes         []string
+	RequestTimeout time.Duration
+
+	mutex             sync.Mutex
+	metadata          *OIDCProviderMetadata
+	keys              map[string]*rsa.PublicKey
+	keysRefreshedTime time.Time
+}
+
+type oidcTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	IdToken     string `json:"id_token"`
+}
+
+type oidcJsonWebKeySet struct {
+	Keys []*oidcJsonWebKey `json:"keys"`
+}
+
+type oidcJsonWebKey struct {
+	KeyType string `json:"kty"`
+	KeyId   string `json:"kid"`
+	Use     string `json:"use"`
+	N       string `json:"n"`
+	E       string `json:"e"`
+}
+
+// GetCodeChallenge returns the pkce code challenge of the code verifier by S256 method
+func GetCodeChallenge(codeVerifier string) string {
+	hash := sha256.Sum256([]byte(codeVerifier))
+	return base64.RawURLEncoding.EncodeToString(hash[:])
+}
+
+// GetAuthorizationUrl returns the url of provider authorization endpoint which user agent should be redirected to
+func (p *OIDCProvider) GetAuthorizationUrl(c *core.Context, state string, nonce string, codeVerifier string) (string, error) {
+	metadata, err := p.getMetadata(c)
+
+	if err != nil {
+		return "", err
+	}
+
+	params := url.Values{}
+	params.Set("response_type", "code")
+	params.Set("client_id", p.ClientId)
+	params.Set("redirect_uri", p.RedirectUrl)
+	params.Set("scope", strings.Join(p.Scopes, " "))
+	params.Set("state", state)
+	params.Set("nonce", nonce)
+	params.Set("code_challenge", GetCodeChallenge(codeVerifier))
+	params.Set("code_challenge_method", "S256")
+
+	separator := "?"
+
+	if strings.Contains(metadata.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+
+	return metadata.AuthorizationEndpoint + separator + params.Encode(), nil
+}
+
+// Authenticate exchanges the authorization code for tokens and returns the verified claims of identity token
+func (p *OIDCProvider) Authenticate(c *core.Context, code string, codeVerifier string, nonce string) (*OIDCIdTokenClaims, error) {
+	metadata, err := p.getMetadata(c)
+
+	if err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	params.Set("grant_type", "authorization_code")
+	params.Set("code", code)
+	params.Set("redirect_uri", p.RedirectUrl)
+	params.Set("client_id", p.ClientId)
+	params.Set("code_verifier", codeVerifier)
+
+	if p.ClientSecret != "" {
+		params.Set("client_secret", p.ClientSecret)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, metadata.TokenEndpoint, strings.NewReader(params.Encode()))
+
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	body, err := p.requestRemoteData(c, req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	tokenResp := &oidcTokenResponse{}
+	err = json.Unmarshal(body, tokenResp)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[oidc_provider.Authenticate] failed to parse token response, because %s", err.Error())
+		return nil, errs.ErrOIDCProviderRequestFailed
+	}
+
+	if tokenResp.IdToken == "" {
+		log.ErrorfWithRequestId(c, "[oidc_provider.Authenticate] token response does not contain identity token")
+		return nil, errs.ErrOIDCIdTokenInvalid
+	}
+
+	return p.VerifyIdToken(c, tokenResp.IdToken, nonce)
+}
+
+// VerifyIdToken verifies the signature, issuer, audience, expiration time and nonce of identity token and returns its claims
+func (p *OIDCProvider) VerifyIdToken(c *core.Context, rawIdToken string, nonce string) (*OIDCIdTokenClaims, error) {
+	metadata, err := p.getMetadata(c)
+
+	if err != nil {
+		return nil, err
+	}
+
+	claims := &OIDCIdTokenClaims{}
+	_, err = jwt.ParseWithClaims(rawIdToken, claims,
+		func(token *jwt.Token) (interface{}, error) {
+			keyId, _ := token.Header["kid"].(string)
+			return p.getPublicKey(c, metadata, keyId)
+		},
+		jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodRS384.Alg(), jwt.SigningMethodRS512.Alg()}),
+		jwt.WithIssuer(metadata.Issuer),
+		jwt.WithAudience(p.ClientId),
+	)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[oidc_provider.VerifyIdToken] identity token is invalid, because %s", err.Error())
+		return nil, errs.ErrOIDCIdTokenInvalid
+	}
+
+	if claims.Nonce != nonce {
+		log.WarnfWithRequestId(c, "[oidc_provider.VerifyIdToken] nonce of identity token does not match")
+		return nil, errs.ErrOIDCIdTokenInvalid
+	}
+
+	if claims.ExpiresAt == nil {
+		log.WarnfWithRequestId(c, "[oidc_provider.VerifyIdToken] expiration time of identity token is not set")
+		return nil, errs.ErrOIDCIdTokenInvalid
+	}
+
+	if claims.Subject == "" {
+		log.WarnfWithRequestId(c, "[oidc_provider.VerifyIdToken] subject of identity token is empty")
+		return nil, errs.ErrOIDCIdTokenInvalid
+	}
+
+	return claims, nil
+}
+
+func (p *OIDCProvider) getMetadata(c *core.Context) (*OIDCProviderMetadata, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.metadata != nil {
+		return p.metadata, nil
+	}
+
+	req, err := http.NewRequest(http.MethodGet, p.IssuerUrl+openIdConfigurationPath, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := p.requestRemoteData(c, req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := &OIDCProviderMetadata{}
+	err = json.Unmarshal(body, metadata)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[oidc_provider.getMetadata] failed to parse provider metadata, because %s", err.Error())
+		return nil, errs.ErrOIDCProviderRequestFailed
+	}
+
+	if strings.TrimRight(metadata.Issuer, "/") != p.IssuerUrl || metadata.AuthorizationEndpoint == "" || metadata.TokenEndpoint == "" || metadata.JwksUri == "" {
+		log.ErrorfWithRequestId(c, "[oidc_provider.getMetadata] provider metadata of issuer \"%s\" is invalid", p.IssuerUrl)
+		return nil, errs.ErrOIDCProviderRequestFailed
+	}
+
+	p.metadata = metadata
+
+	return metadata, nil
+}
+
+func (p *OIDCProvider) getPublicKey(c *core.Context, metadata *OIDCProviderMetadata, keyId string) (*rsa.PublicKey, error) {
+	p.mutex.Lock()
+
+	if key, exists := p.keys[keyId]; exists {
+		p.mutex.Unlock()
+		return key, nil
+	}
+
+	// the provider may rotate its signing keys, so reload the key set if key id is not found,
+	// but tokens with unknown key id must not make every request reload the key set
+	now := time.Now()
+
+	if now.Sub(p.keysRefreshedTime) < jwksRefreshInterval {
+		p.mutex.Unlock()
+		log.WarnfWithRequestId(c, "[oidc_provider.getPublicKey] key \"%s\" is not found and json web key set has been reloaded recently", keyId)
+		return nil, errs.ErrOIDCIdTokenInvalid
+	}
+
+	p.keysRefreshedTime = now
+	p.mutex.Unlock()
+
+	req, err := http.NewRequest(http.MethodGet, metadata.JwksUri, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := p.requestRemoteData(c, req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	keySet := &oidcJsonWebKeySet{}
+	err = json.Unmarshal(body, keySet)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[oidc_provider.getPublicKey] failed to parse json web key set, because %s", err.Error())
+		return nil, errs.ErrOIDCProviderRequestFailed
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(keySet.Keys))
+
+	for i := 0; i < len(keySet.Keys); i++ {
+		jsonWebKey := keySet.Keys[i]
+
+		if jsonWebKey.KeyType != "RSA" || (jsonWebKey.Use != "" && jsonWebKey.Use != "sig") {
+			continue
+		}
+
+		key, err := jsonWebKey.toRSAPublicKey()
+
+		if err != nil {
+			log.WarnfWithRequestId(c, "[oidc_provider.getPublicKey] failed to parse json web key \"%s\", because %s", jsonWebKey.KeyId, err.Error())
+			continue
+		}
+
+		keys[jsonWebKey.KeyId] = key
+	}
+
+	p.mutex.Lock()
+	p.keys = keys
+	p.mutex.Unlock()
+
+	if key, exists := keys[keyId]; exists {
+		return key, nil
+	}
+
+	return nil, errs.ErrOIDCIdTokenInvalid
+}
+
+func (p *OIDCProvider) requestRemoteData(c *core.Context, req *http.Request) ([]byte, error) {
+	client := &http.Client{
+		Timeout: p.RequestTimeout,
+	}
+
+	resp, err := client.Do(req)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[oidc_provider.requestRemoteData] failed to request \"%s\", because %s", req.URL.String(), err.Error())
+		return nil, errs.ErrOIDCProviderRequestFailed
+	}
+
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[oidc_provider.requestRemoteData] failed to read response of \"%s\", because %s", req.URL.String(), err.Error())
+		return nil, errs.ErrOIDCProviderRequestFailed
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		log.ErrorfWithRequestId(c, "[oidc_provider.requestRemoteData] failed to request \"%s\", because response code is %d, response is %s", req.URL.String(), resp.StatusCode, string(body))
+		return nil, errs.ErrOIDCProviderRequestFailed
+	}
+
+	return body, nil
+}
+
+func (k *oidcJsonWebKey) toRSAPublicKey() (*rsa.PublicKey, error) {
+	modulus, err := base64.RawURLEncoding.DecodeString(k.N)
+
+	if err != nil {
+		return nil, err
+	}
+
+	exponent, err := base64.RawURLEncoding.DecodeString(k.E)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(modulus),
+		E: int(new(big.Int).SetBytes(exponent).Int64()),
+	}, nil
+}
@@ -0,0 +1,36 @@
+package oidc
+
+import (
+	"time"
+
+	"github.com/f97/gofire/pkg/settings"
+)
+
+// OIDCProviderContainer contains the current openid connect provider
+type OIDCProviderContainer struct {
+	Current *OIDCProvider
+}
+
+// Initialize a openid connect provider container singleton instance
+var (
+	Container = &OIDCProviderContainer{}
+)
+
+// InitializeOIDCProvider initializes the openid connect provider according to the config, the provider is not set if single sign-on is disabled
+func InitializeOIDCProvider(config *settings.Config) error {
+	if !config.EnableOIDC {
+		Container.Current = nil
+		return nil
+	}
+
+	Container.Current = &OIDCProvider{
+		IssuerUrl:      config.OIDCIssuerUrl,
+		ClientId:       config.OIDCClientId,
+		ClientSecret:   config.OIDCClientSecret,
+		RedirectUrl:    config.OIDCRedirectUrl,
+		Scopes:         config.OIDCScopes,
+		RequestTimeout: time.Duration(config.OIDCRequestTimeout) * time.Millisecond,
+	}
+
+	return nil
+}
@@ -0,0 +1,269 @@
+package oidc
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/f97/gofire/pkg/core"
+	"github.com/f97/gofire/pkg/errs"
+)
+
+const mockOIDCClientId = "gofire"
+const mockOIDCClientSecret = "secret"
+const mockOIDCRedirectUrl = "http://localhost:8080/desktop/"
+const mockOIDCKeyId = "key1"
+const mockOIDCAuthorizationCode = "authorization_code"
+
+type mockOIDCIssuer struct {
+	server       *httptest.Server
+	privateKey   *rsa.PrivateKey
+	nonce        string
+	codeVerifier string
+	audience     string
+	jwksRequests int
+}
+
+func newMockOIDCIssuer(t *testing.T, nonce string, codeVerifier string) *mockOIDCIssuer {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Equal(t, nil, err)
+
+	issuer := &mockOIDCIssuer{
+		privateKey:   privateKey,
+		nonce:        nonce,
+		codeVerifier: codeVerifier,
+		audience:     mockOIDCClientId,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(openIdConfigurationPath, issuer.handleDiscovery)
+	mux.HandleFunc("/jwks", issuer.handleJwks)
+	mux.HandleFunc("/token", issuer.handleToken)
+	issuer.server = httptest.NewServer(mux)
+
+	return issuer
+}
+
+func (m *mockOIDCIssuer) newProvider() *OIDCProvider {
+	return &OIDCProvider{
+		IssuerUrl:      m.server.URL,
+		ClientId:       mockOIDCClientId,
+		ClientSecret:   mockOIDCClientSecret,
+		RedirectUrl:    mockOIDCRedirectUrl,
+		Scopes:         []string{"openid", "profile", "email"},
+		RequestTimeout: 5 * time.Second,
+	}
+}
+
+func (m *mockOIDCIssuer) handleDiscovery(w http.ResponseWriter, r *http.Request) {
+	json.NewEncoder(w).Encode(map[string]string{
+		"issuer":                 m.server.URL,
+		"authorization_endpoint": m.server.URL + "/authorize",
+		"token_endpoint":         m.server.URL + "/token",
+		"jwks_uri":               m.server.URL + "/jwks",
+	})
+}
+
+func (m *mockOIDCIssuer) handleJwks(w http.ResponseWriter, r *http.Request) {
+	m.jwksRequests++
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"keys": []map[string]string{
+			{
+				"kty": "RSA",
+				"kid": mockOIDCKeyId,
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(m.privateKey.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(m.privateKey.E)).Bytes()),
+			},
+		},
+	})
+}
+
+func (m *mockOIDCIssuer) handleToken(w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+
+	if r.PostForm.Get("code") != mockOIDCAuthorizationCode || r.PostForm.Get("code_verifier") != m.codeVerifier || r.PostForm.Get("client_secret") != mockOIDCClientSecret {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("{\"error\":\"invalid_grant\"}"))
+		return
+	}
+
+	now := time.Now()
+	idToken := jwt.NewWithClaims(jwt.SigningMethodRS256, &OIDCIdTokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    m.server.URL,
+			Subject:   "user1",
+			Audience:  jwt.ClaimStrings{m.audience},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+		},
+		Nonce:             m.nonce,
+		Email:             "user1@example.com",
+		EmailVerified:     true,
+		Name:              "User One",
+		PreferredUsername: "user1",
+	})
+	idToken.Header["kid"] = mockOIDCKeyId
+
+	rawIdToken, _ := idToken.SignedString(m.privateKey)
+
+	json.NewEncoder(w).Encode(map[string]string{
+		"access_token": "access_token",
+		"token_type":   "Bearer",
+		"id_token":     rawIdToken,
+	})
+}
+
+func newTestContext() *core.Context {
+	return &core.Context{
+		Context: &gin.Context{},
+	}
+}
+
+func TestGetCodeChallenge(t *testing.T) {
+	actualValue := GetCodeChallenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk")
+	assert.Equal(t, "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM", actualValue)
+}
+
+func TestOIDCProviderGetAuthorizationUrl(t *testing.T) {
+	issuer := newMockOIDCIssuer(t, "nonce", "verifier")
+	defer issuer.server.Close()
+
+	provider := issuer.newProvider()
+	authorizationUrl, err := provider.GetAuthorizationUrl(newTestContext(), "state", "nonce", "verifier")
+	assert.Equal(t, nil, err)
+
+	parsedUrl, err := url.Parse(authorizationUrl)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "/authorize", parsedUrl.Path)
+
+	params := parsedUrl.Query()
+	assert.Equal(t, "code", params.Get("response_type"))
+	assert.Equal(t, mockOIDCClientId, params.Get("client_id"))
+	assert.Equal(t, mockOIDCRedirectUrl, params.Get("redirect_uri"))
+	assert.Equal(t, "openid profile email", params.Get("scope"))
+	assert.Equal(t, "state", params.Get("state"))
+	assert.Equal(t, "nonce", params.Get("nonce"))
+	assert.Equal(t, GetCodeChallenge("verifier"), params.Get("code_challenge"))
+	assert.Equal(t, "S256", params.Get("code_challenge_method"))
+}
+
+func TestOIDCProviderAuthenticate(t *testing.T) {
+	issuer := newMockOIDCIssuer(t, "nonce", "verifier")
+	defer issuer.server.Close()
+
+	provider := issuer.newProvider()
+	claims, err := provider.Authenticate(newTestContext(), mockOIDCAuthorizationCode, "verifier", "nonce")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, issuer.server.URL, claims.Issuer)
+	assert.Equal(t, "user1", claims.Subject)
+	assert.Equal(t, "user1@example.com", claims.Email)
+	assert.Equal(t, true, claims.EmailVerified)
+	assert.Equal(t, "User One", claims.Name)
+	assert.Equal(t, "user1", claims.PreferredUsername)
+}
+
+func TestOIDCProviderAuthenticate_InvalidCodeVerifier(t *testing.T) {
+	issuer := newMockOIDCIssuer(t, "nonce", "verifier")
+	defer issuer.server.Close()
+
+	provider := issuer.newProvider()
+	_, err := provider.Authenticate(newTestContext(), mockOIDCAuthorizationCode, "other_verifier", "nonce")
+	assert.Equal(t, errs.ErrOIDCProviderRequestFailed, err)
+}
+
+func TestOIDCProviderAuthenticate_InvalidNonce(t *testing.T) {
+	issuer := newMockOIDCIssuer(t, "nonce", "verifier")
+	defer issuer.server.Close()
+
+	provider := issuer.newProvider()
+	_, err := provider.Authenticate(newTestContext(), mockOIDCAuthorizationCode, "verifier", "other_nonce")
+	assert.Equal(t, errs.ErrOIDCIdTokenInvalid, err)
+}
+
+func TestOIDCProviderAuthenticate_InvalidAudience(t *testing.T) {
+	issuer := newMockOIDCIssuer(t, "nonce", "verifier")
+	issuer.audience = "other_client"
+	defer issuer.server.Close()
+
+	provider := issuer.newProvider()
+	_, err := provider.Authenticate(newTestContext(), mockOIDCAuthorizationCode, "verifier", "nonce")
+	assert.Equal(t, errs.ErrOIDCIdTokenInvalid, err)
+}
+
+func TestOIDCProviderVerifyIdToken_InvalidSignature(t *testing.T) {
+	issuer := newMockOIDCIssuer(t, "nonce", "verifier")
+	defer issuer.server.Close()
+
+	otherPrivateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Equal(t, nil, err)
+
+	idToken := jwt.NewWithClaims(jwt.SigningMethodRS256, &OIDCIdTokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    issuer.server.URL,
+			Subject:   "user1",
+			Audience:  jwt.ClaimStrings{mockOIDCClientId},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+		Nonce: "nonce",
+	})
+	idToken.Header["kid"] = mockOIDCKeyId
+
+	rawIdToken, err := idToken.SignedString(otherPrivateKey)
+	assert.Equal(t, nil, err)
+
+	provider := issuer.newProvider()
+	_, err = provider.VerifyIdToken(newTestContext(), rawIdToken, "nonce")
+	assert.Equal(t, errs.ErrOIDCIdTokenInvalid, err)
+}
+
+func TestOIDCProviderVerifyIdToken_UnknownKeyIdReloadsKeySetOncePerInterval(t *testing.T) {
+	issuer := newMockOIDCIssuer(t, "nonce", "verifier")
+	defer issuer.server.Close()
+
+	idToken := jwt.NewWithClaims(jwt.SigningMethodRS256, &OIDCIdTokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    issuer.server.URL,
+			Subject:   "user1",
+			Audience:  jwt.ClaimStrings{mockOIDCClientId},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+		Nonce: "nonce",
+	})
+	idToken.Header["kid"] = "unknown_key"
+
+	rawIdToken, err := idToken.SignedString(issuer.privateKey)
+	assert.Equal(t, nil, err)
+
+	provider := issuer.newProvider()
+
+	for i := 0; i < 3; i++ {
+		_, err = provider.VerifyIdToken(newTestContext(), rawIdToken, "nonce")
+		assert.Equal(t, errs.ErrOIDCIdTokenInvalid, err)
+	}
+
+	expectedValue := 1
+	actualValue := issuer.jwksRequests
+	assert.Equal(t, expectedValue, actualValue)
+
+	provider.keysRefreshedTime = time.Now().Add(-jwksRefreshInterval)
+
+	_, err = provider.VerifyIdToken(newTestContext(), rawIdToken, "nonce")
+	assert.Equal(t, errs.ErrOIDCIdTokenInvalid, err)
+
+	expectedValue = 2
+	actualValue = issuer.jwksRequests
+	assert.Equal(t, expectedValue, actualValue)
+}
@@ -0,0 +1,385 @@
+package services
+
+import (
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/f97/gofire/pkg/core"
+	"github.com/f97/gofire/pkg/datastore"
+	"github.com/f97/gofire/pkg/errs"
+	"github.com/f97/gofire/pkg/models"
+	"github.com/f97/gofire/pkg/utils"
+	"github.com/f97/gofire/pkg/uuid"
+)
+
+// AccountReconciliationService represents account reconciliation service
+type AccountReconciliationService struct {
+	ServiceUsingDB
+	ServiceUsingUuid
+}
+
+// Initialize an account reconciliation service singleton instance
+var (
+	AccountReconciliations = &AccountReconciliationService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+		ServiceUsingUuid: ServiceUsingUuid{
+			container: uuid.Container,
+		},
+	}
+)
+
+// GetAllReconciliationsByAccountId returns all reconciliation models of the specified account
+func (s *AccountReconciliationService) GetAllReconciliationsByAccountId(c *core.Context, uid int64, accountId int64) ([]*models.AccountReconciliation, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if accountId <= 0 {
+		return nil, errs.ErrAccountIdInvalid
+	}
+
+	var reconciliations []*models.AccountReconciliation
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=? AND account_id=?", uid, false, accountId).OrderBy("statement_unix_time desc").Find(&reconciliations)
+
+	return reconciliations, err
+}
+
+// GetLastCompletedReconciliationsByUid returns the last completed reconciliation model of every account of user
+func (s *AccountReconciliationService) GetLastCompletedReconciliationsByUid(c *core.Context, uid int64) (map[int64]*models.AccountReconciliation, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	var reconciliations []*models.AccountReconciliation
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=? AND status=?", uid, false, models.ACCOUNT_RECONCILIATION_STATUS_COMPLETED).Find(&reconciliations)
+
+	if err != nil {
+		return nil, err
+	}
+
+	lastReconciliations := make(map[int64]*models.AccountReconciliation)
+
+	for i := 0; i < len(reconciliations); i++ {
+		reconciliation := reconciliations[i]
+		lastReconciliation, exists := lastReconciliations[reconciliation.AccountId]
+
+		if !exists || reconciliation.StatementUnixTime > lastReconciliation.StatementUnixTime {
+			lastReconciliations[reconciliation.AccountId] = reconciliation
+		}
+	}
+
+	return lastReconciliations, nil
+}
+
+// GetReconciliationById returns a reconciliation model according to reconciliation id
+func (s *AccountReconciliationService) GetReconciliationById(c *core.Context, uid int64, reconciliationId int64) (*models.AccountReconciliation, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if reconciliationId <= 0 {
+		return nil, errs.ErrReconciliationIdInvalid
+	}
+
+	reconciliation := &models.AccountReconciliation{}
+	has, err := s.UserDataDB(uid).NewSession(c).ID(reconciliationId).Where("uid=? AND deleted=?", uid, false).Get(reconciliation)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrReconciliationNotFound
+	}
+
+	return reconciliation, nil
+}
+
+// GetReconciliationBalances returns the book balance and cleared balance of account as of the statement time of reconciliation,
+// the stored balances are returned if the reconciliation has been completed
+func (s *AccountReconciliationService) GetReconciliationBalances(c *core.Context, reconciliation *models.AccountReconciliation) (int64, int64, error) {
+	if reconciliation.Status == models.ACCOUNT_RECONCILIATION_STATUS_COMPLETED {
+		return reconciliation.BookBalance, reconciliation.ClearedBalance, nil
+	}
+
+	return s.getAccountBalances(s.UserDataDB(reconciliation.Uid).NewSession(c), reconciliation.Uid, reconciliation.AccountId, reconciliation.StatementUnixTime)
+}
+
+// GetReconciliationTransactions returns the transactions of account which need to be reconciled in the specified reconciliation,
+// including all uncleared transactions before statement time and all transactions after previous completed reconciliation
+func (s *AccountReconciliationService) GetReconciliationTransactions(c *core.Context, reconciliation *models.AccountReconciliation) ([]*models.Transaction, error) {
+	uid := reconciliation.Uid
+	sess := s.UserDataDB(uid).NewSession(c)
+
+	previousReconciliation := &models.AccountReconciliation{}
+	has, err := sess.Where("uid=? AND deleted=? AND account_id=? AND status=? AND statement_unix_time<?", uid, false, reconciliation.AccountId, models.ACCOUNT_RECONCILIATION_STATUS_COMPLETED, reconciliation.StatementUnixTime).OrderBy("statement_unix_time desc").Limit(1).Get(previousReconciliation)
+
+	if err != nil {
+		return nil, err
+	}
+
+	previousMaxTransactionTime := int64(0)
+
+	if has {
+		previousMaxTransactionTime = utils.GetMaxTransactionTimeFromUnixTime(previousReconciliation.StatementUnixTime)
+	}
+
+	maxTransactionTime := utils.GetMaxTransactionTimeFromUnixTime(reconciliation.StatementUnixTime)
+
+	var transactions []*models.Transaction
+	err = s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=? AND account_id=? AND type<>? AND transaction_time<=? AND (cleared=? OR transaction_time>?)", uid, false, reconciliation.AccountId, models.TRANSACTION_DB_TYPE_MODIFY_BALANCE, maxTransactionTime, false, previousMaxTransactionTime).OrderBy("transaction_time desc").Find(&transactions)
+
+	return transactions, err
+}
+
+// StartReconciliation saves a new in-progress reconciliation model to database
+func (s *AccountReconciliationService) StartReconciliation(c *core.Context, operatorUid int64, reconciliation *models.AccountReconciliation) error {
+	if reconciliation.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	err := s.CheckBookMemberRole(c, reconciliation.Uid, operatorUid, models.BOOK_MEMBER_ROLE_EDITOR)
+
+	if err != nil {
+		return err
+	}
+
+	uid := reconciliation.Uid
+	now := time.Now().Unix()
+
+	reconciliation.ReconciliationId = s.GenerateUuid(uuid.UUID_TYPE_DEFAULT)
+	reconciliation.Deleted = false
+	reconciliation.Status = models.ACCOUNT_RECONCILIATION_STATUS_IN_PROGRESS
+	reconciliation.CreatedUnixTime = now
+	reconciliation.UpdatedUnixTime = now
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		account := &models.Account{}
+		has, err := sess.ID(reconciliation.AccountId).Where("uid=? AND deleted=?", uid, false).Get(account)
+
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrAccountNotFound
+		} else if account.Type == models.ACCOUNT_TYPE_MULTI_SUB_ACCOUNTS {
+			return errs.ErrCannotReconcileAccountWithSubAccounts
+		}
+
+		exists, err := sess.Cols("uid", "deleted", "account_id", "status").Where("uid=? AND deleted=? AND account_id=? AND status=?", uid, false, reconciliation.AccountId, models.ACCOUNT_RECONCILIATION_STATUS_IN_PROGRESS).Exist(&models.AccountReconciliation{})
+
+		if err != nil {
+			return err
+		} else if exists {
+			return errs.ErrReconciliationInProgressAlreadyExists
+		}
+
+		exists, err = sess.Cols("uid", "deleted", "account_id", "status", "statement_unix_time").Where("uid=? AND deleted=? AND account_id=? AND status=? AND statement_unix_time>=?", uid, false, reconciliation.AccountId, models.ACCOUNT_RECONCILIATION_STATUS_COMPLETED, reconciliation.StatementUnixTime).Exist(&models.AccountReconciliation{})
+
+		if err != nil {
+			return err
+		} else if exists {
+			return errs.ErrReconciliationStatementTimeInvalid
+		}
+
+		_, err = sess.Insert(reconciliation)
+
+		return err
+	})
+}
+
+// SetTransactionsCleared marks the specified transactions of reconciliation account as cleared or uncleared
+func (s *AccountReconciliationService) SetTransactionsCleared(c *core.Context, uid int64, operatorUid int64, reconciliationId int64, transactionIds []int64, cleared bool) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	err := s.CheckBookMemberRole(c, uid, operatorUid, models.BOOK_MEMBER_ROLE_EDITOR)
+
+	if err != nil {
+		return err
+	}
+
+	transactionIds = utils.ToUniqueInt64Slice(transactionIds)
+
+	updateModel := &models.Transaction{
+		Cleared:         cleared,
+		UpdatedUnixTime: time.Now().Unix(),
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		reconciliation, err := s.getInProgressReconciliation(sess, uid, reconciliationId)
+
+		if err != nil {
+			return err
+		}
+
+		maxTransactionTime := utils.GetMaxTransactionTimeFromUnixTime(reconciliation.StatementUnixTime)
+		count, err := sess.Where("uid=? AND deleted=? AND account_id=? AND type<>? AND transaction_time<=?", uid, false, reconciliation.AccountId, models.TRANSACTION_DB_TYPE_MODIFY_BALANCE, maxTransactionTime).In("transaction_id", transactionIds).Count(&models.Transaction{})
+
+		if err != nil {
+			return err
+		} else if count < int64(len(transactionIds)) {
+			return errs.ErrTransactionNotInReconciliationPeriod
+		}
+
+		_, err = sess.Cols("cleared", "updated_unix_time").Where("uid=? AND deleted=? AND account_id=?", uid, false, reconciliation.AccountId).In("transaction_id", transactionIds).Update(updateModel)
+
+		return err
+	})
+}
+
+// CompleteReconciliation completes the specified reconciliation when cleared balance equals to statement ending balance
+func (s *AccountReconciliationService) CompleteReconciliation(c *core.Context, uid int64, operatorUid int64, reconciliationId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	err := s.CheckBookMemberRole(c, uid, operatorUid, models.BOOK_MEMBER_ROLE_EDITOR)
+
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().Unix()
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		reconciliation, err := s.getInProgressReconciliation(sess, uid, reconciliationId)
+
+		if err != nil {
+			return err
+		}
+
+		bookBalance, clearedBalance, err := s.getAccountBalances(sess, uid, reconciliation.AccountId, reconciliation.StatementUnixTime)
+
+		if err != nil {
+			return err
+		} else if clearedBalance != reconciliation.StatementBalance {
+			return errs.ErrReconciliationBalanceNotEqual
+		}
+
+		reconciliation.BookBalance = bookBalance
+		reconciliation.ClearedBalance = clearedBalance
+		reconciliation.Status = models.ACCOUNT_RECONCILIATION_STATUS_COMPLETED
+		reconciliation.UpdatedUnixTime = now
+		reconciliation.CompletedUnixTime = now
+
+		updatedRows, err := sess.ID(reconciliation.ReconciliationId).Cols("book_balance", "cleared_balance", "status", "updated_unix_time", "completed_unix_time").Where("uid=? AND deleted=?", uid, false).Update(reconciliation)
+
+		if err != nil {
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrReconciliationNotFound
+		}
+
+		return nil
+	})
+}
+
+// DeleteReconciliation deletes an existed reconciliation from database, the cleared status of transactions is kept
+func (s *AccountReconciliationService) DeleteReconciliation(c *core.Context, uid int64, operatorUid int64, reconciliationId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	err := s.CheckBookMemberRole(c, uid, operatorUid, models.BOOK_MEMBER_ROLE_EDITOR)
+
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.AccountReconciliation{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		deletedRows, err := sess.ID(reconciliationId).Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrReconciliationNotFound
+		}
+
+		return err
+	})
+}
+
+// DeleteAllReconciliations deletes all existed reconciliations from database
+func (s *AccountReconciliationService) DeleteAllReconciliations(c *core.Context, uid int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.AccountReconciliation{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+		return err
+	})
+}
+
+func (s *AccountReconciliationService) getInProgressReconciliation(sess *xorm.Session, uid int64, reconciliationId int64) (*models.AccountReconciliation, error) {
+	if reconciliationId <= 0 {
+		return nil, errs.ErrReconciliationIdInvalid
+	}
+
+	reconciliation := &models.AccountReconciliation{}
+	has, err := sess.ID(reconciliationId).Where("uid=? AND deleted=?", uid, false).Get(reconciliation)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrReconciliationNotFound
+	} else if reconciliation.Status == models.ACCOUNT_RECONCILIATION_STATUS_COMPLETED {
+		return nil, errs.ErrReconciliationAlreadyCompleted
+	}
+
+	return reconciliation, nil
+}
+
+// getAccountBalances returns the book balance and cleared balance of account as of the specified unix time,
+// the balance modification transaction is always regarded as cleared because it is the opening balance of account
+func (s *AccountReconciliationService) getAccountBalances(sess *xorm.Session, uid int64, accountId int64, unixTime int64) (int64, int64, error) {
+	maxTransactionTime := utils.GetMaxTransactionTimeFromUnixTime(unixTime)
+
+	var transactionTotalAmounts []*models.Transaction
+	err := sess.Select("type, cleared, SUM(amount) as amount, SUM(related_account_amount) as related_account_amount").Where("uid=? AND deleted=? AND account_id=? AND transaction_time<=?", uid, false, accountId, maxTransactionTime).GroupBy("type, cleared").Find(&transactionTotalAmounts)
+
+	if err != nil {
+		return 0, 0, err
+	}
+
+	bookBalance := int64(0)
+	clearedBalance := int64(0)
+
+	for i := 0; i < len(transactionTotalAmounts); i++ {
+		transactionTotalAmount := transactionTotalAmounts[i]
+		amount := int64(0)
+
+		switch transactionTotalAmount.Type {
+		case models.TRANSACTION_DB_TYPE_MODIFY_BALANCE:
+			amount = transactionTotalAmount.RelatedAccountAmount
+		case models.TRANSACTION_DB_TYPE_INCOME, models.TRANSACTION_DB_TYPE_TRANSFER_IN:
+			amount = transactionTotalAmount.Amount
+		case models.TRANSACTION_DB_TYPE_EXPENSE, models.TRANSACTION_DB_TYPE_TRANSFER_OUT:
+			amount = -transactionTotalAmount.Amount
+		}
+
+		bookBalance += amount
+
+		if transactionTotalAmount.Cleared || transactionTotalAmount.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
+			clearedBalance += amount
+		}
+	}
+
+	return bookBalance, clearedBalance, nil
+}
@@ -0,0 +1,314 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/f97/gofire/pkg/datastore"
+	"github.com/f97/gofire/pkg/errs"
+	"github.com/f97/gofire/pkg/models"
+	"github.com/f97/gofire/pkg/utils"
+	"github.com/f97/gofire/pkg/uuid"
+)
+
+func TestReconciliation_BookBalanceAsOfStatementTime(t *testing.T) {
+	c := initializeTestDataStore(t)
+	uid := int64(1001)
+	statementTime := int64(1704067200)
+
+	primaryIncomeCategory := &models.TransactionCategory{
+		CategoryId: TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:        uid,
+		Name:       "Income",
+		Type:       models.CATEGORY_TYPE_INCOME,
+	}
+
+	primaryExpenseCategory := &models.TransactionCategory{
+		CategoryId: TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:        uid,
+		Name:       "Expense",
+		Type:       models.CATEGORY_TYPE_EXPENSE,
+	}
+
+	account := &models.Account{
+		AccountId: Accounts.GenerateUuid(uuid.UUID_TYPE_ACCOUNT),
+		Uid:       uid,
+		Name:      "Bank",
+		Category:  models.ACCOUNT_CATEGORY_CASH,
+		Type:      models.ACCOUNT_TYPE_SINGLE_ACCOUNT,
+		Currency:  "USD",
+		Balance:   0,
+	}
+
+	incomeCategory := &models.TransactionCategory{
+		CategoryId:       TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:              uid,
+		Name:             "Income Category",
+		Type:             models.CATEGORY_TYPE_INCOME,
+		ParentCategoryId: primaryIncomeCategory.CategoryId,
+	}
+
+	expenseCategory := &models.TransactionCategory{
+		CategoryId:       TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:              uid,
+		Name:             "Expense Category",
+		Type:             models.CATEGORY_TYPE_EXPENSE,
+		ParentCategoryId: primaryExpenseCategory.CategoryId,
+	}
+
+	_, err := datastore.Container.UserDataStore.Choose(uid).NewSession(c).Insert(primaryIncomeCategory, primaryExpenseCategory, account, incomeCategory, expenseCategory)
+	assert.Equal(t, nil, err)
+
+	income := &models.Transaction{
+		Uid:             uid,
+		Type:            models.TRANSACTION_DB_TYPE_INCOME,
+		CategoryId:      incomeCategory.CategoryId,
+		AccountId:       account.AccountId,
+		Amount:          1000,
+		TransactionTime: utils.GetMinTransactionTimeFromUnixTime(statementTime - 2*86400),
+	}
+
+	expense := &models.Transaction{
+		Uid:             uid,
+		Type:            models.TRANSACTION_DB_TYPE_EXPENSE,
+		CategoryId:      expenseCategory.CategoryId,
+		AccountId:       account.AccountId,
+		Amount:          200,
+		TransactionTime: utils.GetMinTransactionTimeFromUnixTime(statementTime),
+	}
+
+	laterExpense := &models.Transaction{
+		Uid:             uid,
+		Type:            models.TRANSACTION_DB_TYPE_EXPENSE,
+		CategoryId:      expenseCategory.CategoryId,
+		AccountId:       account.AccountId,
+		Amount:          300,
+		TransactionTime: utils.GetMinTransactionTimeFromUnixTime(statementTime + 1),
+	}
+
+	err = Transactions.CreateTransaction(c, uid, income, nil, nil)
+	assert.Equal(t, nil, err)
+
+	err = Transactions.CreateTransaction(c, uid, expense, nil, nil)
+	assert.Equal(t, nil, err)
+
+	err = Transactions.CreateTransaction(c, uid, laterExpense, nil, nil)
+	assert.Equal(t, nil, err)
+
+	reconciliation := &models.AccountReconciliation{
+		Uid:               uid,
+		AccountId:         account.AccountId,
+		StatementUnixTime: statementTime,
+		StatementBalance:  800,
+	}
+
+	err = AccountReconciliations.StartReconciliation(c, uid, reconciliation)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, models.ACCOUNT_RECONCILIATION_STATUS_IN_PROGRESS, reconciliation.Status)
+
+	bookBalance, clearedBalance, err := AccountReconciliations.GetReconciliationBalances(c, reconciliation)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(800), bookBalance)
+	assert.Equal(t, int64(0), clearedBalance)
+
+	transactions, err := AccountReconciliations.GetReconciliationTransactions(c, reconciliation)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2, len(transactions))
+	assert.Equal(t, expense.TransactionId, transactions[0].TransactionId)
+	assert.Equal(t, income.TransactionId, transactions[1].TransactionId)
+
+	// Transaction after statement time cannot be cleared in this reconciliation
+	err = AccountReconciliations.SetTransactionsCleared(c, uid, uid, reconciliation.ReconciliationId, []int64{laterExpense.TransactionId}, true)
+	assert.Equal(t, errs.ErrTransactionNotInReconciliationPeriod, err)
+
+	err = AccountReconciliations.SetTransactionsCleared(c, uid, uid, reconciliation.ReconciliationId, []int64{income.TransactionId}, true)
+	assert.Equal(t, nil, err)
+
+	bookBalance, clearedBalance, err = AccountReconciliations.GetReconciliationBalances(c, reconciliation)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(800), bookBalance)
+	assert.Equal(t, int64(1000), clearedBalance)
+
+	err = AccountReconciliations.CompleteReconciliation(c, uid, uid, reconciliation.ReconciliationId)
+	assert.Equal(t, errs.ErrReconciliationBalanceNotEqual, err)
+
+	err = AccountReconciliations.SetTransactionsCleared(c, uid, uid, reconciliation.ReconciliationId, []int64{expense.TransactionId}, true)
+	assert.Equal(t, nil, err)
+
+	err = AccountReconciliations.CompleteReconciliation(c, uid, uid, reconciliation.ReconciliationId)
+	assert.Equal(t, nil, err)
+
+	// The balances are stored when reconciliation completed, so the later changes do not affect them
+	transaction := &models.Transaction{
+		Uid:             uid,
+		Type:            models.TRANSACTION_DB_TYPE_EXPENSE,
+		CategoryId:      expenseCategory.CategoryId,
+		AccountId:       account.AccountId,
+		Amount:          50,
+		TransactionTime: utils.GetMinTransactionTimeFromUnixTime(statementTime - 86400),
+	}
+
+	err = Transactions.CreateTransaction(c, uid, transaction, nil, nil)
+	assert.Equal(t, nil, err)
+
+	savedReconciliation, err := AccountReconciliations.GetReconciliationById(c, uid, reconciliation.ReconciliationId)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, models.ACCOUNT_RECONCILIATION_STATUS_COMPLETED, savedReconciliation.Status)
+
+	bookBalance, clearedBalance, err = AccountReconciliations.GetReconciliationBalances(c, savedReconciliation)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(800), bookBalance)
+	assert.Equal(t, int64(800), clearedBalance)
+
+	err = AccountReconciliations.SetTransactionsCleared(c, uid, uid, reconciliation.ReconciliationId, []int64{income.TransactionId}, false)
+	assert.Equal(t, errs.ErrReconciliationAlreadyCompleted, err)
+}
+
+func TestReconciliation_ModifyTransactionResetClearedStatus(t *testing.T) {
+	c := initializeTestDataStore(t)
+	uid := int64(1001)
+	statementTime := int64(1704067200)
+
+	primaryExpenseCategory := &models.TransactionCategory{
+		CategoryId: TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:        uid,
+		Name:       "Expense",
+		Type:       models.CATEGORY_TYPE_EXPENSE,
+	}
+
+	account := &models.Account{
+		AccountId: Accounts.GenerateUuid(uuid.UUID_TYPE_ACCOUNT),
+		Uid:       uid,
+		Name:      "Bank",
+		Category:  models.ACCOUNT_CATEGORY_CASH,
+		Type:      models.ACCOUNT_TYPE_SINGLE_ACCOUNT,
+		Currency:  "USD",
+		Balance:   0,
+	}
+
+	category := &models.TransactionCategory{
+		CategoryId:       TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:              uid,
+		Name:             "Category",
+		Type:             models.CATEGORY_TYPE_EXPENSE,
+		ParentCategoryId: primaryExpenseCategory.CategoryId,
+	}
+
+	_, err := datastore.Container.UserDataStore.Choose(uid).NewSession(c).Insert(primaryExpenseCategory, account, category)
+	assert.Equal(t, nil, err)
+	transaction := &models.Transaction{
+		Uid:             uid,
+		Type:            models.TRANSACTION_DB_TYPE_EXPENSE,
+		CategoryId:      category.CategoryId,
+		AccountId:       account.AccountId,
+		Amount:          200,
+		TransactionTime: utils.GetMinTransactionTimeFromUnixTime(statementTime),
+	}
+
+	err = Transactions.CreateTransaction(c, uid, transaction, nil, nil)
+	assert.Equal(t, nil, err)
+
+	reconciliation := &models.AccountReconciliation{
+		Uid:               uid,
+		AccountId:         account.AccountId,
+		StatementUnixTime: statementTime,
+		StatementBalance:  -200,
+	}
+
+	err = AccountReconciliations.StartReconciliation(c, uid, reconciliation)
+	assert.Equal(t, nil, err)
+
+	err = AccountReconciliations.SetTransactionsCleared(c, uid, uid, reconciliation.ReconciliationId, []int64{transaction.TransactionId}, true)
+	assert.Equal(t, nil, err)
+
+	newTransaction := &models.Transaction{
+		TransactionId:   transaction.TransactionId,
+		Uid:             uid,
+		CategoryId:      category.CategoryId,
+		AccountId:       account.AccountId,
+		Amount:          200,
+		Comment:         "modified",
+		TransactionTime: transaction.TransactionTime,
+	}
+
+	// Cleared status is kept when the fields which do not affect balance are modified
+	err = Transactions.ModifyTransaction(c, uid, newTransaction, nil, nil, nil)
+	assert.Equal(t, nil, err)
+
+	savedTransaction, err := Transactions.GetTransactionByTransactionId(c, uid, transaction.TransactionId)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, true, savedTransaction.Cleared)
+
+	newTransaction.Amount = 300
+	err = Transactions.ModifyTransaction(c, uid, newTransaction, nil, nil, nil)
+	assert.Equal(t, nil, err)
+
+	savedTransaction, err = Transactions.GetTransactionByTransactionId(c, uid, transaction.TransactionId)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, false, savedTransaction.Cleared)
+
+	bookBalance, clearedBalance, err := AccountReconciliations.GetReconciliationBalances(c, reconciliation)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(-300), bookBalance)
+	assert.Equal(t, int64(0), clearedBalance)
+}
+
+func TestStartReconciliation_StatementTimeMustBeLaterThanLastReconciliation(t *testing.T) {
+	c := initializeTestDataStore(t)
+	uid := int64(1001)
+	statementTime := int64(1704067200)
+
+	account := &models.Account{
+		AccountId: Accounts.GenerateUuid(uuid.UUID_TYPE_ACCOUNT),
+		Uid:       uid,
+		Name:      "Bank",
+		Category:  models.ACCOUNT_CATEGORY_CASH,
+		Type:      models.ACCOUNT_TYPE_SINGLE_ACCOUNT,
+		Currency:  "USD",
+		Balance:   0,
+	}
+
+	_, err := datastore.Container.UserDataStore.Choose(uid).NewSession(c).Insert(account)
+	assert.Equal(t, nil, err)
+
+	reconciliation := &models.AccountReconciliation{
+		Uid:               uid,
+		AccountId:         account.AccountId,
+		StatementUnixTime: statementTime,
+	}
+
+	err = AccountReconciliations.StartReconciliation(c, uid, reconciliation)
+	assert.Equal(t, nil, err)
+
+	err = AccountReconciliations.StartReconciliation(c, uid, &models.AccountReconciliation{
+		Uid:               uid,
+		AccountId:         account.AccountId,
+		StatementUnixTime: statementTime + 86400,
+	})
+	assert.Equal(t, errs.ErrReconciliationInProgressAlreadyExists, err)
+
+	err = AccountReconciliations.CompleteReconciliation(c, uid, uid, reconciliation.ReconciliationId)
+	assert.Equal(t, nil, err)
+
+	err = AccountReconciliations.StartReconciliation(c, uid, &models.AccountReconciliation{
+		Uid:               uid,
+		AccountId:         account.AccountId,
+		StatementUnixTime: statementTime,
+	})
+	assert.Equal(t, errs.ErrReconciliationStatementTimeInvalid, err)
+
+	err = AccountReconciliations.StartReconciliation(c, uid, &models.AccountReconciliation{
+		Uid:               uid,
+		AccountId:         account.AccountId,
+		StatementUnixTime: statementTime + 86400,
+	})
+	assert.Equal(t, nil, err)
+
+	err = AccountReconciliations.StartReconciliation(c, uid, &models.AccountReconciliation{
+		Uid:               uid,
+		AccountId:         123456,
+		StatementUnixTime: statementTime,
+	})
+	assert.Equal(t, errs.ErrAccountNotFound, err)
+}
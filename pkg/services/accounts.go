@@ -1,6 +1,7 @@
 package services
 
 import (
+	"math"
 	"time"
 
 	"xorm.io/xorm"
@@ -152,11 +153,17 @@ func (s *AccountService) GetMaxSubAccountDisplayOrder(c *core.Context, uid int64
 }
 
 // CreateAccounts saves a new account model to database
-func (s *AccountService) CreateAccounts(c *core.Context, mainAccount *models.Account, childrenAccounts []*models.Account, utcOffset int16) error {
+func (s *AccountService) CreateAccounts(c *core.Context, operatorUid int64, mainAccount *models.Account, childrenAccounts []*models.Account, utcOffset int16) error {
 	if mainAccount.Uid <= 0 {
 		return errs.ErrUserIdInvalid
 	}
 
+	err := s.CheckBookMemberRole(c, mainAccount.Uid, operatorUid, models.BOOK_MEMBER_ROLE_EDITOR)
+
+	if err != nil {
+		return err
+	}
+
 	now := time.Now().Unix()
 
 	allAccounts := make([]*models.Account, len(childrenAccounts)+1)
@@ -213,6 +220,12 @@ func (s *AccountService) CreateAccounts(c *core.Context, mainAccount *models.Acc
 			if err != nil {
 				return err
 			}
+
+			err = DataRevisions.insertRevision(c, sess, account.Uid, models.DATA_REVISION_OBJECT_TYPE_ACCOUNT, account.AccountId, models.DATA_REVISION_ACTION_CREATE, nil, getAccountRevisionFields(account))
+
+			if err != nil {
+				return err
+			}
 		}
 
 		for i := 0; i < len(allInitTransactions); i++ {
@@ -222,6 +235,12 @@ func (s *AccountService) CreateAccounts(c *core.Context, mainAccount *models.Acc
 			if err != nil {
 				return err
 			}
+
+			err = DataRevisions.insertRevision(c, sess, transaction.Uid, models.DATA_REVISION_OBJECT_TYPE_TRANSACTION, transaction.TransactionId, models.DATA_REVISION_ACTION_CREATE, nil, getTransactionRevisionFields(transaction, nil, nil))
+
+			if err != nil {
+				return err
+			}
 		}
 
 		return nil
@@ -229,11 +248,17 @@ func (s *AccountService) CreateAccounts(c *core.Context, mainAccount *models.Acc
 }
 
 // ModifyAccounts saves an existed account model to database
-func (s *AccountService) ModifyAccounts(c *core.Context, uid int64, accounts []*models.Account) error {
+func (s *AccountService) ModifyAccounts(c *core.Context, uid int64, operatorUid int64, accounts []*models.Account) error {
 	if uid <= 0 {
 		return errs.ErrUserIdInvalid
 	}
 
+	err := s.CheckBookMemberRole(c, uid, operatorUid, models.BOOK_MEMBER_ROLE_EDITOR)
+
+	if err != nil {
+		return err
+	}
+
 	now := time.Now().Unix()
 
 	for i := 0; i < len(accounts); i++ {
@@ -243,13 +268,39 @@ func (s *AccountService) ModifyAccounts(c *core.Context, uid int64, accounts []*
 	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
 		for i := 0; i < len(accounts); i++ {
 			account := accounts[i]
-			updatedRows, err := sess.ID(account.AccountId).Cols("name", "category", "icon", "color", "comment", "hidden", "updated_unix_time").Where("uid=? AND deleted=?", uid, false).Update(account)
+			oldAccount := &models.Account{}
+			has, err := sess.ID(account.AccountId).Where("uid=? AND deleted=?", uid, false).Get(oldAccount)
+
+			if err != nil {
+				return err
+			} else if !has {
+				return errs.ErrAccountNotFound
+			}
+
+			updatedRows, err := sess.ID(account.AccountId).Cols("name", "category", "icon", "color", "comment", "hidden", "statement_closing_day", "payment_due_day", "credit_limit", "updated_unix_time").Where("uid=? AND deleted=?", uid, false).Update(account)
 
 			if err != nil {
 				return err
 			} else if updatedRows < 1 {
 				return errs.ErrAccountNotFound
 			}
+
+			newAccount := *oldAccount
+			newAccount.Name = account.Name
+			newAccount.Category = account.Category
+			newAccount.Icon = account.Icon
+			newAccount.Color = account.Color
+			newAccount.Comment = account.Comment
+			newAccount.Hidden = account.Hidden
+			newAccount.StatementClosingDay = account.StatementClosingDay
+			newAccount.PaymentDueDay = account.PaymentDueDay
+			newAccount.CreditLimit = account.CreditLimit
+
+			err = DataRevisions.insertRevision(c, sess, uid, models.DATA_REVISION_OBJECT_TYPE_ACCOUNT, account.AccountId, models.DATA_REVISION_ACTION_MODIFY, getAccountRevisionFields(oldAccount), getAccountRevisionFields(&newAccount))
+
+			if err != nil {
+				return err
+			}
 		}
 
 		return nil
@@ -257,11 +308,17 @@ func (s *AccountService) ModifyAccounts(c *core.Context, uid int64, accounts []*
 }
 
 // HideAccount updates hidden field of given accounts
-func (s *AccountService) HideAccount(c *core.Context, uid int64, ids []int64, hidden bool) error {
+func (s *AccountService) HideAccount(c *core.Context, uid int64, operatorUid int64, ids []int64, hidden bool) error {
 	if uid <= 0 {
 		return errs.ErrUserIdInvalid
 	}
 
+	err := s.CheckBookMemberRole(c, uid, operatorUid, models.BOOK_MEMBER_ROLE_EDITOR)
+
+	if err != nil {
+		return err
+	}
+
 	now := time.Now().Unix()
 
 	updateModel := &models.Account{
@@ -270,6 +327,13 @@ func (s *AccountService) HideAccount(c *core.Context, uid int64, ids []int64, hi
 	}
 
 	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		var oldAccounts []*models.Account
+		err := sess.Where("uid=? AND deleted=?", uid, false).In("account_id", ids).Find(&oldAccounts)
+
+		if err != nil {
+			return err
+		}
+
 		updatedRows, err := sess.Cols("hidden", "updated_unix_time").Where("uid=? AND deleted=?", uid, false).In("account_id", ids).Update(updateModel)
 
 		if err != nil {
@@ -278,16 +342,34 @@ func (s *AccountService) HideAccount(c *core.Context, uid int64, ids []int64, hi
 			return errs.ErrAccountNotFound
 		}
 
+		for i := 0; i < len(oldAccounts); i++ {
+			oldAccount := oldAccounts[i]
+			newAccount := *oldAccount
+			newAccount.Hidden = hidden
+
+			err = DataRevisions.insertRevision(c, sess, uid, models.DATA_REVISION_OBJECT_TYPE_ACCOUNT, oldAccount.AccountId, models.DATA_REVISION_ACTION_MODIFY, getAccountRevisionFields(oldAccount), getAccountRevisionFields(&newAccount))
+
+			if err != nil {
+				return err
+			}
+		}
+
 		return nil
 	})
 }
 
 // ModifyAccountDisplayOrders updates display order of given accounts
-func (s *AccountService) ModifyAccountDisplayOrders(c *core.Context, uid int64, accounts []*models.Account) error {
+func (s *AccountService) ModifyAccountDisplayOrders(c *core.Context, uid int64, operatorUid int64, accounts []*models.Account) error {
 	if uid <= 0 {
 		return errs.ErrUserIdInvalid
 	}
 
+	err := s.CheckBookMemberRole(c, uid, operatorUid, models.BOOK_MEMBER_ROLE_EDITOR)
+
+	if err != nil {
+		return err
+	}
+
 	for i := 0; i < len(accounts); i++ {
 		accounts[i].UpdatedUnixTime = time.Now().Unix()
 	}
@@ -309,11 +391,17 @@ func (s *AccountService) ModifyAccountDisplayOrders(c *core.Context, uid int64,
 }
 
 // DeleteAccount deletes an existed account from database
-func (s *AccountService) DeleteAccount(c *core.Context, uid int64, accountId int64) error {
+func (s *AccountService) DeleteAccount(c *core.Context, uid int64, operatorUid int64, accountId int64) error {
 	if uid <= 0 {
 		return errs.ErrUserIdInvalid
 	}
 
+	err := s.CheckBookMemberRole(c, uid, operatorUid, models.BOOK_MEMBER_ROLE_EDITOR)
+
+	if err != nil {
+		return err
+	}
+
 	now := time.Now().Unix()
 
 	updateModel := &models.Account{
@@ -339,7 +427,7 @@ func (s *AccountService) DeleteAccount(c *core.Context, uid int64, accountId int
 		}
 
 		var relatedTransactionsByAccount []*models.Transaction
-		err = sess.Cols("transaction_id", "uid", "deleted", "account_id", "type").Where("uid=? AND deleted=?", uid, false).In("account_id", accountAndSubAccountIds).Limit(len(accountAndSubAccounts) + 1).Find(&relatedTransactionsByAccount)
+		err = sess.Where("uid=? AND deleted=?", uid, false).In("account_id", accountAndSubAccountIds).Limit(len(accountAndSubAccounts) + 1).Find(&relatedTransactionsByAccount)
 
 		if err != nil {
 			return err
@@ -388,12 +476,146 @@ func (s *AccountService) DeleteAccount(c *core.Context, uid int64, accountId int
 			} else if deletedTransactionRows < int64(len(transactionIds)) {
 				return errs.ErrDatabaseOperationFailed
 			}
+
+			for i := 0; i < len(relatedTransactionsByAccount); i++ {
+				transaction := relatedTransactionsByAccount[i]
+				err = DataRevisions.insertRevision(c, sess, uid, models.DATA_REVISION_OBJECT_TYPE_TRANSACTION, transaction.TransactionId, models.DATA_REVISION_ACTION_DELETE, getTransactionRevisionFields(transaction, nil, nil), nil)
+
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		var loans []*models.Loan
+		err = sess.Cols("loan_id").Where("uid=? AND deleted=?", uid, false).In("account_id", accountAndSubAccountIds).Find(&loans)
+
+		if err != nil {
+			return err
+		}
+
+		if len(loans) > 0 {
+			loanIds := make([]int64, len(loans))
+
+			for i := 0; i < len(loans); i++ {
+				loanIds[i] = loans[i].LoanId
+			}
+
+			updateLoan := &models.Loan{
+				Deleted:         true,
+				DeletedUnixTime: now,
+			}
+
+			_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).In("loan_id", loanIds).Update(updateLoan)
+
+			if err != nil {
+				return err
+			}
+
+			updateLoanSchedule := &models.TransactionSchedule{
+				Deleted:         true,
+				DeletedUnixTime: now,
+			}
+
+			_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).In("loan_id", loanIds).Update(updateLoanSchedule)
+
+			if err != nil {
+				return err
+			}
+		}
+
+		updateHolding := &models.InvestmentHolding{
+			Deleted:         true,
+			DeletedUnixTime: now,
+		}
+
+		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).In("account_id", accountAndSubAccountIds).Update(updateHolding)
+
+		if err != nil {
+			return err
+		}
+
+		for i := 0; i < len(accountAndSubAccounts); i++ {
+			account := accountAndSubAccounts[i]
+			err = DataRevisions.insertRevision(c, sess, uid, models.DATA_REVISION_OBJECT_TYPE_ACCOUNT, account.AccountId, models.DATA_REVISION_ACTION_DELETE, getAccountRevisionFields(account), nil)
+
+			if err != nil {
+				return err
+			}
 		}
 
 		return err
 	})
 }
 
+// GetAccountsBalancesByUnixTimes returns the balances of every account at every specified unix time (which must be sorted in descending order),
+// the balances are reconstructed by replaying transactions backwards from the current balances
+func (s *AccountService) GetAccountsBalancesByUnixTimes(c *core.Context, uid int64, accounts []*models.Account, unixTimes []int64, pageCount int) ([]map[int64]int64, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	balances := make(map[int64]int64, len(accounts))
+
+	for i := 0; i < len(accounts); i++ {
+		balances[accounts[i].AccountId] = accounts[i].Balance
+	}
+
+	allBalances := make([]map[int64]int64, len(unixTimes))
+
+	if len(unixTimes) < 1 {
+		return allBalances, nil
+	}
+
+	minTransactionTime := utils.GetMaxTransactionTimeFromUnixTime(unixTimes[len(unixTimes)-1]) + 1
+	maxTransactionTime := int64(math.MaxInt64)
+	unixTimeIndex := 0
+
+	for maxTransactionTime > 0 {
+		var transactions []*models.Transaction
+		err := s.UserDataDB(uid).NewSession(c).Select("uid, type, account_id, transaction_time, amount, related_account_amount").Where("uid=? AND deleted=? AND transaction_time>=? AND transaction_time<=?", uid, false, minTransactionTime, maxTransactionTime).Limit(pageCount, 0).OrderBy("transaction_time desc").Find(&transactions)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for i := 0; i < len(transactions); i++ {
+			transaction := transactions[i]
+			transactionUnixTime := utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime)
+
+			for unixTimeIndex < len(unixTimes) && transactionUnixTime <= unixTimes[unixTimeIndex] {
+				allBalances[unixTimeIndex] = s.copyBalances(balances)
+				unixTimeIndex++
+			}
+
+			if _, exists := balances[transaction.AccountId]; !exists {
+				continue
+			}
+
+			if transaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
+				balances[transaction.AccountId] -= transaction.RelatedAccountAmount
+			} else if transaction.Type == models.TRANSACTION_DB_TYPE_INCOME || transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+				balances[transaction.AccountId] -= transaction.Amount
+			} else if transaction.Type == models.TRANSACTION_DB_TYPE_EXPENSE || transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
+				balances[transaction.AccountId] += transaction.Amount
+			}
+		}
+
+		if len(transactions) < pageCount {
+			break
+		}
+
+		maxTransactionTime = transactions[len(transactions)-1].TransactionTime - 1
+	}
+
+	for unixTimeIndex < len(unixTimes) {
+		allBalances[unixTimeIndex] = s.copyBalances(balances)
+		unixTimeIndex++
+	}
+
+	return allBalances, nil
+}
+
 // GetAccountMapByList returns an account map by a list
 func (s *AccountService) GetAccountMapByList(accounts []*models.Account) map[int64]*models.Account {
 	accountMap := make(map[int64]*models.Account)
@@ -404,3 +626,13 @@ func (s *AccountService) GetAccountMapByList(accounts []*models.Account) map[int
 	}
 	return accountMap
 }
+
+func (s *AccountService) copyBalances(balances map[int64]int64) map[int64]int64 {
+	copiedBalances := make(map[int64]int64, len(balances))
+
+	for accountId, balance := range balances {
+		copiedBalances[accountId] = balance
+	}
+
+	return copiedBalances
+}
@@ -0,0 +1,148 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/f97/gofire/pkg/datastore"
+	"github.com/f97/gofire/pkg/models"
+	"github.com/f97/gofire/pkg/utils"
+	"github.com/f97/gofire/pkg/uuid"
+)
+
+func TestGetAccountsBalancesByUnixTimes(t *testing.T) {
+	c := initializeTestDataStore(t)
+	uid := int64(1001)
+	baseTime := int64(1704067200)
+
+	primaryIncomeCategory := &models.TransactionCategory{
+		CategoryId: TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:        uid,
+		Name:       "Income",
+		Type:       models.CATEGORY_TYPE_INCOME,
+	}
+
+	primaryExpenseCategory := &models.TransactionCategory{
+		CategoryId: TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:        uid,
+		Name:       "Expense",
+		Type:       models.CATEGORY_TYPE_EXPENSE,
+	}
+
+	primaryTransferCategory := &models.TransactionCategory{
+		CategoryId: TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:        uid,
+		Name:       "Transfer",
+		Type:       models.CATEGORY_TYPE_TRANSFER,
+	}
+
+	account1 := &models.Account{
+		AccountId: Accounts.GenerateUuid(uuid.UUID_TYPE_ACCOUNT),
+		Uid:       uid,
+		Name:      "Cash",
+		Category:  models.ACCOUNT_CATEGORY_CASH,
+		Type:      models.ACCOUNT_TYPE_SINGLE_ACCOUNT,
+		Currency:  "USD",
+		Balance:   1000,
+	}
+
+	account2 := &models.Account{
+		AccountId: Accounts.GenerateUuid(uuid.UUID_TYPE_ACCOUNT),
+		Uid:       uid,
+		Name:      "Bank",
+		Category:  models.ACCOUNT_CATEGORY_CASH,
+		Type:      models.ACCOUNT_TYPE_SINGLE_ACCOUNT,
+		Currency:  "USD",
+		Balance:   0,
+	}
+
+	incomeCategory := &models.TransactionCategory{
+		CategoryId:       TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:              uid,
+		Name:             "Income Category",
+		Type:             models.CATEGORY_TYPE_INCOME,
+		ParentCategoryId: primaryIncomeCategory.CategoryId,
+	}
+
+	expenseCategory := &models.TransactionCategory{
+		CategoryId:       TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:              uid,
+		Name:             "Expense Category",
+		Type:             models.CATEGORY_TYPE_EXPENSE,
+		ParentCategoryId: primaryExpenseCategory.CategoryId,
+	}
+
+	transferCategory := &models.TransactionCategory{
+		CategoryId:       TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:              uid,
+		Name:             "Transfer Category",
+		Type:             models.CATEGORY_TYPE_TRANSFER,
+		ParentCategoryId: primaryTransferCategory.CategoryId,
+	}
+
+	_, err := datastore.Container.UserDataStore.Choose(uid).NewSession(c).Insert(primaryIncomeCategory, primaryExpenseCategory, primaryTransferCategory, account1, account2, incomeCategory, expenseCategory, transferCategory)
+	assert.Equal(t, nil, err)
+
+	transactions := []*models.Transaction{
+		{Uid: uid, Type: models.TRANSACTION_DB_TYPE_INCOME, CategoryId: incomeCategory.CategoryId, AccountId: account1.AccountId, Amount: 500, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(baseTime)},
+		{Uid: uid, Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: expenseCategory.CategoryId, AccountId: account1.AccountId, Amount: 200, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(baseTime + 86400)},
+	}
+
+	for i := 0; i < len(transactions); i++ {
+		err := Transactions.CreateTransaction(c, uid, transactions[i], nil, nil)
+		assert.Equal(t, nil, err)
+	}
+
+	err = Transactions.CreateTransaction(c, uid, &models.Transaction{
+		Uid:                  uid,
+		Type:                 models.TRANSACTION_DB_TYPE_TRANSFER_OUT,
+		CategoryId:           transferCategory.CategoryId,
+		AccountId:            account1.AccountId,
+		Amount:               100,
+		RelatedAccountId:     account2.AccountId,
+		RelatedAccountAmount: 100,
+		TransactionTime:      utils.GetMinTransactionTimeFromUnixTime(baseTime + 2*86400),
+	}, nil, nil)
+	assert.Equal(t, nil, err)
+
+	accounts, err := Accounts.GetAllAccountsByUid(c, uid)
+	assert.Equal(t, nil, err)
+
+	unixTimes := []int64{baseTime + 2*86400 + 10, baseTime + 86400 + 10, baseTime + 10, baseTime - 10}
+
+	// Load one transaction per page to make sure balances are replayed across pages
+	allBalances, err := Accounts.GetAccountsBalancesByUnixTimes(c, uid, accounts, unixTimes, 1)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 4, len(allBalances))
+
+	assert.Equal(t, int64(1200), allBalances[0][account1.AccountId])
+	assert.Equal(t, int64(100), allBalances[0][account2.AccountId])
+	assert.Equal(t, int64(1300), allBalances[1][account1.AccountId])
+	assert.Equal(t, int64(0), allBalances[1][account2.AccountId])
+	assert.Equal(t, int64(1500), allBalances[2][account1.AccountId])
+	assert.Equal(t, int64(1000), allBalances[3][account1.AccountId])
+	assert.Equal(t, int64(0), allBalances[3][account2.AccountId])
+}
+
+func TestGetAccountsBalancesByUnixTimes_EmptyUnixTimes(t *testing.T) {
+	c := initializeTestDataStore(t)
+	uid := int64(1001)
+
+	account := &models.Account{
+		AccountId: Accounts.GenerateUuid(uuid.UUID_TYPE_ACCOUNT),
+		Uid:       uid,
+		Name:      "Cash",
+		Category:  models.ACCOUNT_CATEGORY_CASH,
+		Type:      models.ACCOUNT_TYPE_SINGLE_ACCOUNT,
+		Currency:  "USD",
+		Balance:   1000,
+	}
+
+	_, err := datastore.Container.UserDataStore.Choose(uid).NewSession(c).Insert(account)
+	assert.Equal(t, nil, err)
+
+	allBalances, err := Accounts.GetAccountsBalancesByUnixTimes(c, uid, []*models.Account{account}, []int64{}, 10)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 0, len(allBalances))
+}
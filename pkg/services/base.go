@@ -1,9 +1,11 @@
 package services
 
 import (
+	"github.com/f97/gofire/pkg/core"
 	"github.com/f97/gofire/pkg/datastore"
 	"github.com/f97/gofire/pkg/errs"
 	"github.com/f97/gofire/pkg/mail"
+	"github.com/f97/gofire/pkg/models"
 	"github.com/f97/gofire/pkg/settings"
 	"github.com/f97/gofire/pkg/uuid"
 )
@@ -28,6 +30,53 @@ func (s *ServiceUsingDB) UserDataDB(uid int64) *datastore.Database {
 	return s.container.UserDataStore.Choose(uid)
 }
 
+// AllUserDataDBs returns all the datastores which contain user data
+func (s *ServiceUsingDB) AllUserDataDBs() []*datastore.Database {
+	return s.container.UserDataStore.All()
+}
+
+// GetBookMemberRole returns the role of user in the shared book, the book owner always has owner role
+// and the user who has not accepted the invitation is not a member
+func (s *ServiceUsingDB) GetBookMemberRole(c *core.Context, bookUid int64, uid int64) (models.BookMemberRole, error) {
+	if bookUid <= 0 {
+		return 0, errs.ErrBookIdInvalid
+	}
+
+	if uid <= 0 {
+		return 0, errs.ErrUserIdInvalid
+	}
+
+	if bookUid == uid {
+		return models.BOOK_MEMBER_ROLE_OWNER, nil
+	}
+
+	member := &models.BookMember{}
+	has, err := s.UserDB().NewSession(c).Where("book_uid=? AND uid=? AND accepted=?", bookUid, uid, true).Get(member)
+
+	if err != nil {
+		return 0, err
+	} else if !has {
+		return 0, errs.ErrBookNotFound
+	}
+
+	return member.Role, nil
+}
+
+// CheckBookMemberRole returns an error if the user is not a member of the shared book or the role of user has less permissions than the required role
+func (s *ServiceUsingDB) CheckBookMemberRole(c *core.Context, bookUid int64, uid int64, requiredRole models.BookMemberRole) error {
+	role, err := s.GetBookMemberRole(c, bookUid, uid)
+
+	if err != nil {
+		return err
+	}
+
+	if !role.HasPermissionOf(requiredRole) {
+		return errs.ErrBookPermissionDenied
+	}
+
+	return nil
+}
+
 // ServiceUsingConfig represents a service that need to use config
 type ServiceUsingConfig struct {
 	container *settings.ConfigContainer
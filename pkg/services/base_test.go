@@ -0,0 +1,60 @@
+package services
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/f97/gofire/pkg/core"
+	"github.com/f97/gofire/pkg/datastore"
+	"github.com/f97/gofire/pkg/models"
+	"github.com/f97/gofire/pkg/settings"
+	"github.com/f97/gofire/pkg/uuid"
+)
+
+// initializeTestDataStore creates a new sqlite3 database for one test and syncs all tables services use
+func initializeTestDataStore(t *testing.T) *core.Context {
+	config := &settings.Config{
+		DatabaseConfig: &settings.DatabaseConfig{
+			DatabaseType:          settings.Sqlite3DbType,
+			DatabasePath:          filepath.Join(t.TempDir(), "gofire.db"),
+			MaxIdleConnection:     1,
+			MaxOpenConnection:     1,
+			ConnectionMaxLifeTime: 3600,
+		},
+		UuidGeneratorType: settings.InternalUuidGeneratorType,
+	}
+
+	settings.SetCurrentConfig(config)
+
+	err := datastore.InitializeDataStore(config)
+	assert.Equal(t, nil, err)
+
+	err = uuid.InitializeUuidGenerator(config)
+	assert.Equal(t, nil, err)
+
+	err = datastore.Container.UserStore.SyncStructs(new(models.User), new(models.BookMember), new(models.TokenRecord), new(models.UserWebAuthnChallenge))
+	assert.Equal(t, nil, err)
+
+	err = datastore.Container.UserDataStore.SyncStructs(
+		new(models.Account),
+		new(models.Transaction),
+		new(models.TransactionCategory),
+		new(models.TransactionTag),
+		new(models.TransactionTagIndex),
+		new(models.TransactionExternalIndex),
+		new(models.TransactionSplit),
+		new(models.TransactionAttachment),
+		new(models.TransactionSchedule),
+		new(models.TransactionRule),
+		new(models.DataRevision),
+		new(models.BookLock),
+		new(models.AccountReconciliation),
+		new(models.Loan),
+	)
+	assert.Equal(t, nil, err)
+
+	return &core.Context{Context: &gin.Context{}}
+}
@@ -0,0 +1,222 @@
+package services
+
+import (
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/f97/gofire/pkg/core"
+	"github.com/f97/gofire/pkg/datastore"
+	"github.com/f97/gofire/pkg/errs"
+	"github.com/f97/gofire/pkg/models"
+	"github.com/f97/gofire/pkg/utils"
+	"github.com/f97/gofire/pkg/uuid"
+)
+
+// BookLockService represents book lock service
+type BookLockService struct {
+	ServiceUsingDB
+	ServiceUsingUuid
+}
+
+// Initialize a book lock service singleton instance
+var (
+	BookLocks = &BookLockService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+		ServiceUsingUuid: ServiceUsingUuid{
+			container: uuid.Container,
+		},
+	}
+)
+
+// GetAllBookLocksByUid returns all book lock models of user
+func (s *BookLockService) GetAllBookLocksByUid(c *core.Context, uid int64) ([]*models.BookLock, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	return getBookLocks(s.UserDataDB(uid).NewSession(c), uid)
+}
+
+// CloseBooks closes the books of user (or the specified account) through the specified unix time, the closed time can only be moved later
+func (s *BookLockService) CloseBooks(c *core.Context, uid int64, operatorUid int64, accountId int64, closedUnixTime int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	err := s.CheckBookMemberRole(c, uid, operatorUid, models.BOOK_MEMBER_ROLE_EDITOR)
+
+	if err != nil {
+		return err
+	}
+
+	if closedUnixTime <= 0 {
+		return errs.ErrBookLockClosedTimeInvalid
+	}
+
+	now := time.Now().Unix()
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		if accountId > 0 {
+			account := &models.Account{}
+			has, err := sess.ID(accountId).Where("uid=? AND deleted=?", uid, false).Get(account)
+
+			if err != nil {
+				return err
+			} else if !has {
+				return errs.ErrAccountNotFound
+			} else if account.Type == models.ACCOUNT_TYPE_MULTI_SUB_ACCOUNTS {
+				return errs.ErrCannotCloseBooksOfAccountWithSubAccounts
+			}
+		}
+
+		bookLock := &models.BookLock{}
+		has, err := sess.Where("uid=? AND deleted=? AND account_id=?", uid, false, accountId).Get(bookLock)
+
+		if err != nil {
+			return err
+		}
+
+		if !has {
+			bookLock = &models.BookLock{
+				LockId:          s.GenerateUuid(uuid.UUID_TYPE_DEFAULT),
+				Uid:             uid,
+				Deleted:         false,
+				AccountId:       accountId,
+				ClosedUnixTime:  closedUnixTime,
+				CreatedUnixTime: now,
+				UpdatedUnixTime: now,
+			}
+
+			_, err = sess.Insert(bookLock)
+
+			if err != nil {
+				return err
+			}
+
+			return DataRevisions.insertRevision(c, sess, uid, models.DATA_REVISION_OBJECT_TYPE_BOOK_LOCK, accountId, models.DATA_REVISION_ACTION_CREATE, nil, getBookLockRevisionFields(bookLock))
+		}
+
+		if closedUnixTime <= bookLock.ClosedUnixTime {
+			return errs.ErrCannotCloseBooksBeforeCurrentClosedTime
+		}
+
+		return s.updateClosedTime(c, sess, bookLock, closedUnixTime, now)
+	})
+}
+
+// ReopenBooks reopens the closed books of user (or the specified account) after the specified unix time, the book lock is removed if the specified unix time is zero
+func (s *BookLockService) ReopenBooks(c *core.Context, uid int64, operatorUid int64, accountId int64, closedUnixTime int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	err := s.CheckBookMemberRole(c, uid, operatorUid, models.BOOK_MEMBER_ROLE_EDITOR)
+
+	if err != nil {
+		return err
+	}
+
+	if closedUnixTime < 0 {
+		return errs.ErrBookLockClosedTimeInvalid
+	}
+
+	now := time.Now().Unix()
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		bookLock := &models.BookLock{}
+		has, err := sess.Where("uid=? AND deleted=? AND account_id=?", uid, false, accountId).Get(bookLock)
+
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrBookLockNotFound
+		}
+
+		if closedUnixTime > 0 {
+			if closedUnixTime >= bookLock.ClosedUnixTime {
+				return errs.ErrCannotReopenBooksAfterCurrentClosedTime
+			}
+
+			return s.updateClosedTime(c, sess, bookLock, closedUnixTime, now)
+		}
+
+		updateModel := &models.BookLock{
+			Deleted:         true,
+			DeletedUnixTime: now,
+		}
+
+		deletedRows, err := sess.ID(bookLock.LockId).Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrBookLockNotFound
+		}
+
+		return DataRevisions.insertRevision(c, sess, uid, models.DATA_REVISION_OBJECT_TYPE_BOOK_LOCK, accountId, models.DATA_REVISION_ACTION_DELETE, getBookLockRevisionFields(bookLock), nil)
+	})
+}
+
+// DeleteAllBookLocks deletes all existed book locks from database
+func (s *BookLockService) DeleteAllBookLocks(c *core.Context, uid int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.BookLock{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+		return err
+	})
+}
+
+func (s *BookLockService) updateClosedTime(c *core.Context, sess *xorm.Session, bookLock *models.BookLock, closedUnixTime int64, now int64) error {
+	newBookLock := *bookLock
+	newBookLock.ClosedUnixTime = closedUnixTime
+	newBookLock.UpdatedUnixTime = now
+
+	updatedRows, err := sess.ID(bookLock.LockId).Cols("closed_unix_time", "updated_unix_time").Where("uid=? AND deleted=?", bookLock.Uid, false).Update(&newBookLock)
+
+	if err != nil {
+		return err
+	} else if updatedRows < 1 {
+		return errs.ErrBookLockNotFound
+	}
+
+	return DataRevisions.insertRevision(c, sess, bookLock.Uid, models.DATA_REVISION_OBJECT_TYPE_BOOK_LOCK, bookLock.AccountId, models.DATA_REVISION_ACTION_MODIFY, getBookLockRevisionFields(bookLock), getBookLockRevisionFields(&newBookLock))
+}
+
+// getBookLocks returns all book locks of user in the given session
+func getBookLocks(sess *xorm.Session, uid int64) ([]*models.BookLock, error) {
+	var bookLocks []*models.BookLock
+	err := sess.Where("uid=? AND deleted=?", uid, false).Find(&bookLocks)
+
+	return bookLocks, err
+}
+
+// isTransactionInClosedPeriod returns whether the specified transaction is in the closed period of any book lock
+func isTransactionInClosedPeriod(bookLocks []*models.BookLock, transaction *models.Transaction) bool {
+	transactionUnixTime := utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime)
+	relatedAccountId := int64(0)
+
+	if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+		relatedAccountId = transaction.RelatedAccountId
+	}
+
+	for i := 0; i < len(bookLocks); i++ {
+		if bookLocks[i].IsTransactionLocked(transactionUnixTime, transaction.AccountId, relatedAccountId) {
+			return true
+		}
+	}
+
+	return false
+}
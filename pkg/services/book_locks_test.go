@@ -0,0 +1,444 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/f97/gofire/pkg/datastore"
+	"github.com/f97/gofire/pkg/errs"
+	"github.com/f97/gofire/pkg/models"
+	"github.com/f97/gofire/pkg/utils"
+	"github.com/f97/gofire/pkg/uuid"
+)
+
+func TestCreateTransaction_RejectClosedPeriod(t *testing.T) {
+	c := initializeTestDataStore(t)
+	uid := int64(1001)
+	closedTime := int64(1704067200)
+
+	primaryExpenseCategory := &models.TransactionCategory{
+		CategoryId: TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:        uid,
+		Name:       "Expense",
+		Type:       models.CATEGORY_TYPE_EXPENSE,
+	}
+
+	account := &models.Account{
+		AccountId: Accounts.GenerateUuid(uuid.UUID_TYPE_ACCOUNT),
+		Uid:       uid,
+		Name:      "Cash",
+		Category:  models.ACCOUNT_CATEGORY_CASH,
+		Type:      models.ACCOUNT_TYPE_SINGLE_ACCOUNT,
+		Currency:  "USD",
+		Balance:   1000,
+	}
+
+	category := &models.TransactionCategory{
+		CategoryId:       TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:              uid,
+		Name:             "Category",
+		Type:             models.CATEGORY_TYPE_EXPENSE,
+		ParentCategoryId: primaryExpenseCategory.CategoryId,
+	}
+
+	_, err := datastore.Container.UserDataStore.Choose(uid).NewSession(c).Insert(primaryExpenseCategory, account, category)
+	assert.Equal(t, nil, err)
+
+	err = BookLocks.CloseBooks(c, uid, uid, 0, closedTime)
+	assert.Equal(t, nil, err)
+
+	newExpense := func(unixTime int64) *models.Transaction {
+		return &models.Transaction{
+			Uid:             uid,
+			Type:            models.TRANSACTION_DB_TYPE_EXPENSE,
+			CategoryId:      category.CategoryId,
+			AccountId:       account.AccountId,
+			Amount:          100,
+			TransactionTime: utils.GetMinTransactionTimeFromUnixTime(unixTime),
+		}
+	}
+
+	err = Transactions.CreateTransaction(c, uid, newExpense(closedTime), nil, nil)
+	assert.Equal(t, errs.ErrTransactionInClosedPeriod, err)
+
+	err = Transactions.CreateTransaction(c, uid, newExpense(closedTime-86400), nil, nil)
+	assert.Equal(t, errs.ErrTransactionInClosedPeriod, err)
+	savedAccounts, err := Accounts.GetAccountsByAccountIds(c, uid, []int64{account.AccountId})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(1000), savedAccounts[account.AccountId].Balance)
+
+	err = Transactions.CreateTransaction(c, uid, newExpense(closedTime+1), nil, nil)
+	assert.Equal(t, nil, err)
+	savedAccounts, err = Accounts.GetAccountsByAccountIds(c, uid, []int64{account.AccountId})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(900), savedAccounts[account.AccountId].Balance)
+}
+
+func TestCreateTransaction_RejectClosedPeriodOfRelatedAccount(t *testing.T) {
+	c := initializeTestDataStore(t)
+	uid := int64(1001)
+	closedTime := int64(1704067200)
+
+	primaryExpenseCategory := &models.TransactionCategory{
+		CategoryId: TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:        uid,
+		Name:       "Expense",
+		Type:       models.CATEGORY_TYPE_EXPENSE,
+	}
+
+	primaryTransferCategory := &models.TransactionCategory{
+		CategoryId: TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:        uid,
+		Name:       "Transfer",
+		Type:       models.CATEGORY_TYPE_TRANSFER,
+	}
+
+	account1 := &models.Account{
+		AccountId: Accounts.GenerateUuid(uuid.UUID_TYPE_ACCOUNT),
+		Uid:       uid,
+		Name:      "Cash",
+		Category:  models.ACCOUNT_CATEGORY_CASH,
+		Type:      models.ACCOUNT_TYPE_SINGLE_ACCOUNT,
+		Currency:  "USD",
+		Balance:   1000,
+	}
+
+	account2 := &models.Account{
+		AccountId: Accounts.GenerateUuid(uuid.UUID_TYPE_ACCOUNT),
+		Uid:       uid,
+		Name:      "Bank",
+		Category:  models.ACCOUNT_CATEGORY_CASH,
+		Type:      models.ACCOUNT_TYPE_SINGLE_ACCOUNT,
+		Currency:  "USD",
+		Balance:   0,
+	}
+
+	account3 := &models.Account{
+		AccountId: Accounts.GenerateUuid(uuid.UUID_TYPE_ACCOUNT),
+		Uid:       uid,
+		Name:      "Card",
+		Category:  models.ACCOUNT_CATEGORY_CASH,
+		Type:      models.ACCOUNT_TYPE_SINGLE_ACCOUNT,
+		Currency:  "USD",
+		Balance:   0,
+	}
+
+	expenseCategory := &models.TransactionCategory{
+		CategoryId:       TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:              uid,
+		Name:             "Expense Category",
+		Type:             models.CATEGORY_TYPE_EXPENSE,
+		ParentCategoryId: primaryExpenseCategory.CategoryId,
+	}
+
+	transferCategory := &models.TransactionCategory{
+		CategoryId:       TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:              uid,
+		Name:             "Transfer Category",
+		Type:             models.CATEGORY_TYPE_TRANSFER,
+		ParentCategoryId: primaryTransferCategory.CategoryId,
+	}
+
+	_, err := datastore.Container.UserDataStore.Choose(uid).NewSession(c).Insert(primaryExpenseCategory, primaryTransferCategory, account1, account2, account3, expenseCategory, transferCategory)
+	assert.Equal(t, nil, err)
+
+	err = BookLocks.CloseBooks(c, uid, uid, account2.AccountId, closedTime)
+	assert.Equal(t, nil, err)
+
+	newTransfer := func(destinationAccountId int64) *models.Transaction {
+		return &models.Transaction{
+			Uid:                  uid,
+			Type:                 models.TRANSACTION_DB_TYPE_TRANSFER_OUT,
+			CategoryId:           transferCategory.CategoryId,
+			AccountId:            account1.AccountId,
+			Amount:               100,
+			RelatedAccountId:     destinationAccountId,
+			RelatedAccountAmount: 100,
+			TransactionTime:      utils.GetMinTransactionTimeFromUnixTime(closedTime - 86400),
+		}
+	}
+
+	err = Transactions.CreateTransaction(c, uid, newTransfer(account2.AccountId), nil, nil)
+	assert.Equal(t, errs.ErrTransactionInClosedPeriod, err)
+
+	err = Transactions.CreateTransaction(c, uid, newTransfer(account3.AccountId), nil, nil)
+	assert.Equal(t, nil, err)
+
+	// The books of other accounts are still open
+	transaction := &models.Transaction{
+		Uid:             uid,
+		Type:            models.TRANSACTION_DB_TYPE_EXPENSE,
+		CategoryId:      expenseCategory.CategoryId,
+		AccountId:       account1.AccountId,
+		Amount:          100,
+		TransactionTime: utils.GetMinTransactionTimeFromUnixTime(closedTime - 86400),
+	}
+
+	err = Transactions.CreateTransaction(c, uid, transaction, nil, nil)
+	assert.Equal(t, nil, err)
+	savedAccounts, err := Accounts.GetAccountsByAccountIds(c, uid, []int64{account1.AccountId})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(800), savedAccounts[account1.AccountId].Balance)
+}
+
+func TestModifyTransaction_RejectClosedPeriod(t *testing.T) {
+	c := initializeTestDataStore(t)
+	uid := int64(1001)
+	closedTime := int64(1704067200)
+
+	primaryExpenseCategory := &models.TransactionCategory{
+		CategoryId: TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:        uid,
+		Name:       "Expense",
+		Type:       models.CATEGORY_TYPE_EXPENSE,
+	}
+
+	account := &models.Account{
+		AccountId: Accounts.GenerateUuid(uuid.UUID_TYPE_ACCOUNT),
+		Uid:       uid,
+		Name:      "Cash",
+		Category:  models.ACCOUNT_CATEGORY_CASH,
+		Type:      models.ACCOUNT_TYPE_SINGLE_ACCOUNT,
+		Currency:  "USD",
+		Balance:   1000,
+	}
+
+	category := &models.TransactionCategory{
+		CategoryId:       TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:              uid,
+		Name:             "Category",
+		Type:             models.CATEGORY_TYPE_EXPENSE,
+		ParentCategoryId: primaryExpenseCategory.CategoryId,
+	}
+
+	_, err := datastore.Container.UserDataStore.Choose(uid).NewSession(c).Insert(primaryExpenseCategory, account, category)
+	assert.Equal(t, nil, err)
+	closedTransaction := &models.Transaction{
+		Uid:             uid,
+		Type:            models.TRANSACTION_DB_TYPE_EXPENSE,
+		CategoryId:      category.CategoryId,
+		AccountId:       account.AccountId,
+		Amount:          100,
+		TransactionTime: utils.GetMinTransactionTimeFromUnixTime(closedTime - 86400),
+	}
+
+	openTransaction := &models.Transaction{
+		Uid:             uid,
+		Type:            models.TRANSACTION_DB_TYPE_EXPENSE,
+		CategoryId:      category.CategoryId,
+		AccountId:       account.AccountId,
+		Amount:          100,
+		TransactionTime: utils.GetMinTransactionTimeFromUnixTime(closedTime + 86400),
+	}
+
+	err = Transactions.CreateTransaction(c, uid, closedTransaction, nil, nil)
+	assert.Equal(t, nil, err)
+
+	err = Transactions.CreateTransaction(c, uid, openTransaction, nil, nil)
+	assert.Equal(t, nil, err)
+
+	err = BookLocks.CloseBooks(c, uid, uid, 0, closedTime)
+	assert.Equal(t, nil, err)
+
+	newExpense := func(transactionId int64, amount int64, unixTime int64) *models.Transaction {
+		return &models.Transaction{
+			TransactionId:   transactionId,
+			Uid:             uid,
+			CategoryId:      category.CategoryId,
+			AccountId:       account.AccountId,
+			Amount:          amount,
+			TransactionTime: utils.GetMinTransactionTimeFromUnixTime(unixTime),
+		}
+	}
+
+	// Transaction in closed period cannot be modified
+	err = Transactions.ModifyTransaction(c, uid, newExpense(closedTransaction.TransactionId, 200, closedTime-86400), nil, nil, nil)
+	assert.Equal(t, errs.ErrTransactionInClosedPeriod, err)
+
+	// Transaction in closed period cannot be moved to open period
+	err = Transactions.ModifyTransaction(c, uid, newExpense(closedTransaction.TransactionId, 100, closedTime+86400), nil, nil, nil)
+	assert.Equal(t, errs.ErrTransactionInClosedPeriod, err)
+
+	// Transaction in open period cannot be moved to closed period
+	err = Transactions.ModifyTransaction(c, uid, newExpense(openTransaction.TransactionId, 100, closedTime), nil, nil, nil)
+	assert.Equal(t, errs.ErrTransactionInClosedPeriod, err)
+	savedAccounts, err := Accounts.GetAccountsByAccountIds(c, uid, []int64{account.AccountId})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(800), savedAccounts[account.AccountId].Balance)
+
+	err = Transactions.ModifyTransaction(c, uid, newExpense(openTransaction.TransactionId, 200, closedTime+86400), nil, nil, nil)
+	assert.Equal(t, nil, err)
+	savedAccounts, err = Accounts.GetAccountsByAccountIds(c, uid, []int64{account.AccountId})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(700), savedAccounts[account.AccountId].Balance)
+
+	// Transaction can be modified after the books are reopened
+	err = BookLocks.ReopenBooks(c, uid, uid, 0, 0)
+	assert.Equal(t, nil, err)
+
+	err = Transactions.ModifyTransaction(c, uid, newExpense(closedTransaction.TransactionId, 200, closedTime-86400), nil, nil, nil)
+	assert.Equal(t, nil, err)
+	savedAccounts, err = Accounts.GetAccountsByAccountIds(c, uid, []int64{account.AccountId})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(600), savedAccounts[account.AccountId].Balance)
+}
+
+func TestDeleteTransaction_RejectClosedPeriod(t *testing.T) {
+	c := initializeTestDataStore(t)
+	uid := int64(1001)
+	closedTime := int64(1704067200)
+
+	primaryExpenseCategory := &models.TransactionCategory{
+		CategoryId: TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:        uid,
+		Name:       "Expense",
+		Type:       models.CATEGORY_TYPE_EXPENSE,
+	}
+
+	account := &models.Account{
+		AccountId: Accounts.GenerateUuid(uuid.UUID_TYPE_ACCOUNT),
+		Uid:       uid,
+		Name:      "Cash",
+		Category:  models.ACCOUNT_CATEGORY_CASH,
+		Type:      models.ACCOUNT_TYPE_SINGLE_ACCOUNT,
+		Currency:  "USD",
+		Balance:   1000,
+	}
+
+	category := &models.TransactionCategory{
+		CategoryId:       TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:              uid,
+		Name:             "Category",
+		Type:             models.CATEGORY_TYPE_EXPENSE,
+		ParentCategoryId: primaryExpenseCategory.CategoryId,
+	}
+
+	_, err := datastore.Container.UserDataStore.Choose(uid).NewSession(c).Insert(primaryExpenseCategory, account, category)
+	assert.Equal(t, nil, err)
+	transaction := &models.Transaction{
+		Uid:             uid,
+		Type:            models.TRANSACTION_DB_TYPE_EXPENSE,
+		CategoryId:      category.CategoryId,
+		AccountId:       account.AccountId,
+		Amount:          100,
+		TransactionTime: utils.GetMinTransactionTimeFromUnixTime(closedTime - 86400),
+	}
+
+	err = Transactions.CreateTransaction(c, uid, transaction, nil, nil)
+	assert.Equal(t, nil, err)
+
+	err = BookLocks.CloseBooks(c, uid, uid, account.AccountId, closedTime)
+	assert.Equal(t, nil, err)
+
+	err = Transactions.DeleteTransaction(c, uid, uid, transaction.TransactionId)
+	assert.Equal(t, errs.ErrTransactionInClosedPeriod, err)
+	savedAccounts, err := Accounts.GetAccountsByAccountIds(c, uid, []int64{account.AccountId})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(900), savedAccounts[account.AccountId].Balance)
+
+	// Reopen books after the transaction time
+	err = BookLocks.ReopenBooks(c, uid, uid, account.AccountId, closedTime-2*86400)
+	assert.Equal(t, nil, err)
+
+	err = Transactions.DeleteTransaction(c, uid, uid, transaction.TransactionId)
+	assert.Equal(t, nil, err)
+	savedAccounts, err = Accounts.GetAccountsByAccountIds(c, uid, []int64{account.AccountId})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(1000), savedAccounts[account.AccountId].Balance)
+}
+
+func TestRestoreTransaction_RejectClosedPeriod(t *testing.T) {
+	c := initializeTestDataStore(t)
+	uid := int64(1001)
+	closedTime := int64(1704067200)
+
+	primaryExpenseCategory := &models.TransactionCategory{
+		CategoryId: TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:        uid,
+		Name:       "Expense",
+		Type:       models.CATEGORY_TYPE_EXPENSE,
+	}
+
+	account := &models.Account{
+		AccountId: Accounts.GenerateUuid(uuid.UUID_TYPE_ACCOUNT),
+		Uid:       uid,
+		Name:      "Cash",
+		Category:  models.ACCOUNT_CATEGORY_CASH,
+		Type:      models.ACCOUNT_TYPE_SINGLE_ACCOUNT,
+		Currency:  "USD",
+		Balance:   1000,
+	}
+
+	category := &models.TransactionCategory{
+		CategoryId:       TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:              uid,
+		Name:             "Category",
+		Type:             models.CATEGORY_TYPE_EXPENSE,
+		ParentCategoryId: primaryExpenseCategory.CategoryId,
+	}
+
+	_, err := datastore.Container.UserDataStore.Choose(uid).NewSession(c).Insert(primaryExpenseCategory, account, category)
+	assert.Equal(t, nil, err)
+	transaction := &models.Transaction{
+		Uid:             uid,
+		Type:            models.TRANSACTION_DB_TYPE_EXPENSE,
+		CategoryId:      category.CategoryId,
+		AccountId:       account.AccountId,
+		Amount:          100,
+		TransactionTime: utils.GetMinTransactionTimeFromUnixTime(closedTime - 86400),
+	}
+
+	err = Transactions.CreateTransaction(c, uid, transaction, nil, nil)
+	assert.Equal(t, nil, err)
+
+	err = Transactions.DeleteTransaction(c, uid, uid, transaction.TransactionId)
+	assert.Equal(t, nil, err)
+
+	err = BookLocks.CloseBooks(c, uid, uid, 0, closedTime)
+	assert.Equal(t, nil, err)
+
+	err = Transactions.RestoreTransaction(c, uid, uid, transaction.TransactionId, 0)
+	assert.Equal(t, errs.ErrTransactionInClosedPeriod, err)
+	savedAccounts, err := Accounts.GetAccountsByAccountIds(c, uid, []int64{account.AccountId})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(1000), savedAccounts[account.AccountId].Balance)
+}
+
+func TestCloseBooks_ClosedTimeCanOnlyBeMovedLater(t *testing.T) {
+	c := initializeTestDataStore(t)
+	uid := int64(1001)
+	closedTime := int64(1704067200)
+
+	err := BookLocks.CloseBooks(c, uid, uid, 0, 0)
+	assert.Equal(t, errs.ErrBookLockClosedTimeInvalid, err)
+
+	err = BookLocks.CloseBooks(c, uid, uid, 123456, closedTime)
+	assert.Equal(t, errs.ErrAccountNotFound, err)
+
+	err = BookLocks.CloseBooks(c, uid, uid, 0, closedTime)
+	assert.Equal(t, nil, err)
+
+	err = BookLocks.CloseBooks(c, uid, uid, 0, closedTime)
+	assert.Equal(t, errs.ErrCannotCloseBooksBeforeCurrentClosedTime, err)
+
+	err = BookLocks.CloseBooks(c, uid, uid, 0, closedTime+86400)
+	assert.Equal(t, nil, err)
+
+	err = BookLocks.ReopenBooks(c, uid, uid, 0, closedTime+86400)
+	assert.Equal(t, errs.ErrCannotReopenBooksAfterCurrentClosedTime, err)
+
+	err = BookLocks.ReopenBooks(c, uid, uid, 0, closedTime)
+	assert.Equal(t, nil, err)
+
+	bookLocks, err := BookLocks.GetAllBookLocksByUid(c, uid)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, len(bookLocks))
+	assert.Equal(t, closedTime, bookLocks[0].ClosedUnixTime)
+
+	err = BookLocks.ReopenBooks(c, uid, uid, 0, 0)
+	assert.Equal(t, nil, err)
+
+	err = BookLocks.ReopenBooks(c, uid, uid, 0, 0)
+	assert.Equal(t, errs.ErrBookLockNotFound, err)
+}
@@ -0,0 +1,192 @@
+package services
+
+import (
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/f97/gofire/pkg/core"
+	"github.com/f97/gofire/pkg/datastore"
+	"github.com/f97/gofire/pkg/errs"
+	"github.com/f97/gofire/pkg/models"
+	"github.com/f97/gofire/pkg/uuid"
+)
+
+// BookMemberService represents shared book member service
+type BookMemberService struct {
+	ServiceUsingDB
+	ServiceUsingUuid
+}
+
+// Initialize a shared book member service singleton instance
+var (
+	BookMembers = &BookMemberService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+		ServiceUsingUuid: ServiceUsingUuid{
+			container: uuid.Container,
+		},
+	}
+)
+
+// GetAllMembersByBookUid returns all member models of the shared book, including the members who have not accepted invitation
+func (s *BookMemberService) GetAllMembersByBookUid(c *core.Context, bookUid int64) ([]*models.BookMember, error) {
+	if bookUid <= 0 {
+		return nil, errs.ErrBookIdInvalid
+	}
+
+	var members []*models.BookMember
+	err := s.UserDB().NewSession(c).Where("book_uid=?", bookUid).OrderBy("created_unix_time asc").Find(&members)
+
+	return members, err
+}
+
+// GetAllMembershipsByUid returns all member models of the shared books which user has joined or been invited to
+func (s *BookMemberService) GetAllMembershipsByUid(c *core.Context, uid int64) ([]*models.BookMember, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	var members []*models.BookMember
+	err := s.UserDB().NewSession(c).Where("uid=?", uid).OrderBy("created_unix_time asc").Find(&members)
+
+	return members, err
+}
+
+// InviteMember saves a new member model which has not accepted the invitation to database
+func (s *BookMemberService) InviteMember(c *core.Context, member *models.BookMember) error {
+	if member.BookUid <= 0 {
+		return errs.ErrBookIdInvalid
+	}
+
+	if member.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if member.BookUid == member.Uid {
+		return errs.ErrCannotInviteBookOwner
+	}
+
+	if member.Role < models.BOOK_MEMBER_ROLE_EDITOR || member.Role > models.BOOK_MEMBER_ROLE_VIEWER {
+		return errs.ErrBookMemberRoleInvalid
+	}
+
+	err := s.CheckBookMemberRole(c, member.BookUid, member.InviterUid, models.BOOK_MEMBER_ROLE_OWNER)
+
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().Unix()
+
+	member.MemberId = s.GenerateUuid(uuid.UUID_TYPE_DEFAULT)
+	member.Accepted = false
+	member.CreatedUnixTime = now
+	member.UpdatedUnixTime = now
+
+	return s.UserDB().DoTransaction(c, func(sess *xorm.Session) error {
+		exists, err := sess.Cols("book_uid", "uid").Where("book_uid=? AND uid=?", member.BookUid, member.Uid).Exist(&models.BookMember{})
+
+		if err != nil {
+			return err
+		} else if exists {
+			return errs.ErrBookMemberAlreadyExists
+		}
+
+		_, err = sess.Insert(member)
+		return err
+	})
+}
+
+// ModifyMemberRole updates the role of an existed member of the shared book, only the book owner can modify the role of members
+func (s *BookMemberService) ModifyMemberRole(c *core.Context, bookUid int64, operatorUid int64, uid int64, role models.BookMemberRole) error {
+	if bookUid <= 0 {
+		return errs.ErrBookIdInvalid
+	}
+
+	if bookUid == uid {
+		return errs.ErrCannotModifyBookOwner
+	}
+
+	if role < models.BOOK_MEMBER_ROLE_EDITOR || role > models.BOOK_MEMBER_ROLE_VIEWER {
+		return errs.ErrBookMemberRoleInvalid
+	}
+
+	err := s.CheckBookMemberRole(c, bookUid, operatorUid, models.BOOK_MEMBER_ROLE_OWNER)
+
+	if err != nil {
+		return err
+	}
+
+	updateModel := &models.BookMember{
+		Role:            role,
+		UpdatedUnixTime: time.Now().Unix(),
+	}
+
+	updatedRows, err := s.UserDB().NewSession(c).Cols("role", "updated_unix_time").Where("book_uid=? AND uid=?", bookUid, uid).Update(updateModel)
+
+	if err != nil {
+		return err
+	} else if updatedRows < 1 {
+		return errs.ErrBookMemberNotFound
+	}
+
+	return nil
+}
+
+// RemoveMember deletes an existed member (or invitation) of the shared book from database,
+// the book owner can remove any member and other members can only remove themselves
+func (s *BookMemberService) RemoveMember(c *core.Context, bookUid int64, operatorUid int64, uid int64) error {
+	if bookUid <= 0 {
+		return errs.ErrBookIdInvalid
+	}
+
+	if bookUid == uid {
+		return errs.ErrCannotModifyBookOwner
+	}
+
+	if operatorUid != uid {
+		err := s.CheckBookMemberRole(c, bookUid, operatorUid, models.BOOK_MEMBER_ROLE_OWNER)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	deletedRows, err := s.UserDB().NewSession(c).Where("book_uid=? AND uid=?", bookUid, uid).Delete(&models.BookMember{})
+
+	if err != nil {
+		return err
+	} else if deletedRows < 1 {
+		return errs.ErrBookMemberNotFound
+	}
+
+	return nil
+}
+
+// AcceptInvitation marks the invitation of the shared book as accepted by user
+func (s *BookMemberService) AcceptInvitation(c *core.Context, bookUid int64, uid int64) error {
+	if bookUid <= 0 {
+		return errs.ErrBookIdInvalid
+	}
+
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	updateModel := &models.BookMember{
+		Accepted:        true,
+		UpdatedUnixTime: time.Now().Unix(),
+	}
+
+	updatedRows, err := s.UserDB().NewSession(c).Cols("accepted", "updated_unix_time").Where("book_uid=? AND uid=? AND accepted=?", bookUid, uid, false).Update(updateModel)
+
+	if err != nil {
+		return err
+	} else if updatedRows < 1 {
+		return errs.ErrBookInvitationNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,436 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/f97/gofire/pkg/core"
+	"github.com/f97/gofire/pkg/datastore"
+	"github.com/f97/gofire/pkg/errs"
+	"github.com/f97/gofire/pkg/models"
+	"github.com/f97/gofire/pkg/utils"
+	"github.com/f97/gofire/pkg/uuid"
+)
+
+// createTestBookMember saves a new member of the shared book with the given role
+func createTestBookMember(t *testing.T, c *core.Context, bookUid int64, uid int64, role models.BookMemberRole, accepted bool) *models.BookMember {
+	member := &models.BookMember{
+		MemberId:        BookMembers.GenerateUuid(uuid.UUID_TYPE_DEFAULT),
+		BookUid:         bookUid,
+		Uid:             uid,
+		Role:            role,
+		Accepted:        accepted,
+		InviterUid:      bookUid,
+		CreatedUnixTime: 1,
+		UpdatedUnixTime: 1,
+	}
+
+	_, err := datastore.Container.UserStore.Choose(0).NewSession(c).Insert(member)
+	assert.Equal(t, nil, err)
+
+	return member
+}
+
+func TestCheckBookMemberRole_OwnerRequiresOwner(t *testing.T) {
+	c := initializeTestDataStore(t)
+	bookUid := int64(1001)
+
+	actualValue := BookMembers.CheckBookMemberRole(c, bookUid, bookUid, models.BOOK_MEMBER_ROLE_OWNER)
+	assert.Equal(t, nil, actualValue)
+}
+
+func TestCheckBookMemberRole_OwnerRequiresViewer(t *testing.T) {
+	c := initializeTestDataStore(t)
+	bookUid := int64(1001)
+
+	actualValue := BookMembers.CheckBookMemberRole(c, bookUid, bookUid, models.BOOK_MEMBER_ROLE_VIEWER)
+	assert.Equal(t, nil, actualValue)
+}
+
+func TestCheckBookMemberRole_EditorRequiresOwner(t *testing.T) {
+	c := initializeTestDataStore(t)
+	bookUid := int64(1001)
+	editorUid := int64(1002)
+
+	createTestBookMember(t, c, bookUid, editorUid, models.BOOK_MEMBER_ROLE_EDITOR, true)
+
+	expectedValue := errs.ErrBookPermissionDenied
+	actualValue := BookMembers.CheckBookMemberRole(c, bookUid, editorUid, models.BOOK_MEMBER_ROLE_OWNER)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestCheckBookMemberRole_EditorRequiresEditor(t *testing.T) {
+	c := initializeTestDataStore(t)
+	bookUid := int64(1001)
+	editorUid := int64(1002)
+
+	createTestBookMember(t, c, bookUid, editorUid, models.BOOK_MEMBER_ROLE_EDITOR, true)
+
+	actualValue := BookMembers.CheckBookMemberRole(c, bookUid, editorUid, models.BOOK_MEMBER_ROLE_EDITOR)
+	assert.Equal(t, nil, actualValue)
+}
+
+func TestCheckBookMemberRole_EditorRequiresViewer(t *testing.T) {
+	c := initializeTestDataStore(t)
+	bookUid := int64(1001)
+	editorUid := int64(1002)
+
+	createTestBookMember(t, c, bookUid, editorUid, models.BOOK_MEMBER_ROLE_EDITOR, true)
+
+	actualValue := BookMembers.CheckBookMemberRole(c, bookUid, editorUid, models.BOOK_MEMBER_ROLE_VIEWER)
+	assert.Equal(t, nil, actualValue)
+}
+
+func TestCheckBookMemberRole_ViewerRequiresEditor(t *testing.T) {
+	c := initializeTestDataStore(t)
+	bookUid := int64(1001)
+	viewerUid := int64(1003)
+
+	createTestBookMember(t, c, bookUid, viewerUid, models.BOOK_MEMBER_ROLE_VIEWER, true)
+
+	expectedValue := errs.ErrBookPermissionDenied
+	actualValue := BookMembers.CheckBookMemberRole(c, bookUid, viewerUid, models.BOOK_MEMBER_ROLE_EDITOR)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestCheckBookMemberRole_ViewerRequiresViewer(t *testing.T) {
+	c := initializeTestDataStore(t)
+	bookUid := int64(1001)
+	viewerUid := int64(1003)
+
+	createTestBookMember(t, c, bookUid, viewerUid, models.BOOK_MEMBER_ROLE_VIEWER, true)
+
+	actualValue := BookMembers.CheckBookMemberRole(c, bookUid, viewerUid, models.BOOK_MEMBER_ROLE_VIEWER)
+	assert.Equal(t, nil, actualValue)
+}
+
+func TestCheckBookMemberRole_InvitationNotAccepted(t *testing.T) {
+	c := initializeTestDataStore(t)
+	bookUid := int64(1001)
+	pendingUid := int64(1004)
+
+	createTestBookMember(t, c, bookUid, pendingUid, models.BOOK_MEMBER_ROLE_EDITOR, false)
+
+	expectedValue := errs.ErrBookNotFound
+	actualValue := BookMembers.CheckBookMemberRole(c, bookUid, pendingUid, models.BOOK_MEMBER_ROLE_VIEWER)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestCheckBookMemberRole_NotAMember(t *testing.T) {
+	c := initializeTestDataStore(t)
+	bookUid := int64(1001)
+	editorUid := int64(1002)
+	otherUid := int64(1005)
+
+	createTestBookMember(t, c, bookUid, editorUid, models.BOOK_MEMBER_ROLE_EDITOR, true)
+
+	expectedValue := errs.ErrBookNotFound
+	actualValue := BookMembers.CheckBookMemberRole(c, bookUid, otherUid, models.BOOK_MEMBER_ROLE_VIEWER)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestCheckBookMemberRole_MemberOfOtherBook(t *testing.T) {
+	c := initializeTestDataStore(t)
+	bookUid := int64(1001)
+	editorUid := int64(1002)
+
+	createTestBookMember(t, c, bookUid, editorUid, models.BOOK_MEMBER_ROLE_EDITOR, true)
+
+	expectedValue := errs.ErrBookNotFound
+	actualValue := BookMembers.CheckBookMemberRole(c, editorUid, bookUid, models.BOOK_MEMBER_ROLE_VIEWER)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestCheckBookMemberRole_InvalidBookId(t *testing.T) {
+	c := initializeTestDataStore(t)
+	editorUid := int64(1002)
+
+	expectedValue := errs.ErrBookIdInvalid
+	actualValue := BookMembers.CheckBookMemberRole(c, 0, editorUid, models.BOOK_MEMBER_ROLE_VIEWER)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestCheckBookMemberRole_InvalidUserId(t *testing.T) {
+	c := initializeTestDataStore(t)
+	bookUid := int64(1001)
+
+	expectedValue := errs.ErrUserIdInvalid
+	actualValue := BookMembers.CheckBookMemberRole(c, bookUid, 0, models.BOOK_MEMBER_ROLE_VIEWER)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestInviteMember(t *testing.T) {
+	c := initializeTestDataStore(t)
+	bookUid := int64(1001)
+	editorUid := int64(1002)
+	invitedUid := int64(1003)
+
+	createTestBookMember(t, c, bookUid, editorUid, models.BOOK_MEMBER_ROLE_EDITOR, true)
+
+	err := BookMembers.InviteMember(c, &models.BookMember{BookUid: bookUid, Uid: invitedUid, Role: models.BOOK_MEMBER_ROLE_OWNER, InviterUid: bookUid})
+	assert.Equal(t, errs.ErrBookMemberRoleInvalid, err)
+
+	err = BookMembers.InviteMember(c, &models.BookMember{BookUid: bookUid, Uid: bookUid, Role: models.BOOK_MEMBER_ROLE_EDITOR, InviterUid: bookUid})
+	assert.Equal(t, errs.ErrCannotInviteBookOwner, err)
+
+	// Only the book owner can invite members
+	err = BookMembers.InviteMember(c, &models.BookMember{BookUid: bookUid, Uid: invitedUid, Role: models.BOOK_MEMBER_ROLE_VIEWER, InviterUid: editorUid})
+	assert.Equal(t, errs.ErrBookPermissionDenied, err)
+
+	err = BookMembers.InviteMember(c, &models.BookMember{BookUid: bookUid, Uid: invitedUid, Role: models.BOOK_MEMBER_ROLE_VIEWER, InviterUid: bookUid})
+	assert.Equal(t, nil, err)
+
+	err = BookMembers.InviteMember(c, &models.BookMember{BookUid: bookUid, Uid: invitedUid, Role: models.BOOK_MEMBER_ROLE_EDITOR, InviterUid: bookUid})
+	assert.Equal(t, errs.ErrBookMemberAlreadyExists, err)
+
+	// Invited user is not a member until the invitation is accepted
+	err = BookMembers.CheckBookMemberRole(c, bookUid, invitedUid, models.BOOK_MEMBER_ROLE_VIEWER)
+	assert.Equal(t, errs.ErrBookNotFound, err)
+
+	err = BookMembers.AcceptInvitation(c, bookUid, invitedUid)
+	assert.Equal(t, nil, err)
+
+	err = BookMembers.CheckBookMemberRole(c, bookUid, invitedUid, models.BOOK_MEMBER_ROLE_VIEWER)
+	assert.Equal(t, nil, err)
+
+	err = BookMembers.AcceptInvitation(c, bookUid, invitedUid)
+	assert.Equal(t, errs.ErrBookInvitationNotFound, err)
+}
+
+func TestModifyMemberRole(t *testing.T) {
+	c := initializeTestDataStore(t)
+	bookUid := int64(1001)
+	editorUid := int64(1002)
+	viewerUid := int64(1003)
+
+	createTestBookMember(t, c, bookUid, editorUid, models.BOOK_MEMBER_ROLE_EDITOR, true)
+	createTestBookMember(t, c, bookUid, viewerUid, models.BOOK_MEMBER_ROLE_VIEWER, true)
+
+	err := BookMembers.ModifyMemberRole(c, bookUid, bookUid, bookUid, models.BOOK_MEMBER_ROLE_VIEWER)
+	assert.Equal(t, errs.ErrCannotModifyBookOwner, err)
+
+	err = BookMembers.ModifyMemberRole(c, bookUid, bookUid, viewerUid, models.BOOK_MEMBER_ROLE_OWNER)
+	assert.Equal(t, errs.ErrBookMemberRoleInvalid, err)
+
+	err = BookMembers.ModifyMemberRole(c, bookUid, editorUid, viewerUid, models.BOOK_MEMBER_ROLE_EDITOR)
+	assert.Equal(t, errs.ErrBookPermissionDenied, err)
+
+	err = BookMembers.ModifyMemberRole(c, bookUid, editorUid, editorUid, models.BOOK_MEMBER_ROLE_EDITOR)
+	assert.Equal(t, errs.ErrBookPermissionDenied, err)
+
+	err = BookMembers.ModifyMemberRole(c, bookUid, bookUid, int64(1004), models.BOOK_MEMBER_ROLE_EDITOR)
+	assert.Equal(t, errs.ErrBookMemberNotFound, err)
+
+	err = BookMembers.ModifyMemberRole(c, bookUid, bookUid, viewerUid, models.BOOK_MEMBER_ROLE_EDITOR)
+	assert.Equal(t, nil, err)
+
+	err = BookMembers.CheckBookMemberRole(c, bookUid, viewerUid, models.BOOK_MEMBER_ROLE_EDITOR)
+	assert.Equal(t, nil, err)
+}
+
+func TestRemoveMember(t *testing.T) {
+	c := initializeTestDataStore(t)
+	bookUid := int64(1001)
+	editorUid := int64(1002)
+	viewerUid := int64(1003)
+
+	createTestBookMember(t, c, bookUid, editorUid, models.BOOK_MEMBER_ROLE_EDITOR, true)
+	createTestBookMember(t, c, bookUid, viewerUid, models.BOOK_MEMBER_ROLE_VIEWER, true)
+
+	err := BookMembers.RemoveMember(c, bookUid, bookUid, bookUid)
+	assert.Equal(t, errs.ErrCannotModifyBookOwner, err)
+
+	err = BookMembers.RemoveMember(c, bookUid, editorUid, bookUid)
+	assert.Equal(t, errs.ErrCannotModifyBookOwner, err)
+
+	err = BookMembers.RemoveMember(c, bookUid, editorUid, viewerUid)
+	assert.Equal(t, errs.ErrBookPermissionDenied, err)
+
+	// Member can leave the book by itself
+	err = BookMembers.RemoveMember(c, bookUid, viewerUid, viewerUid)
+	assert.Equal(t, nil, err)
+
+	err = BookMembers.CheckBookMemberRole(c, bookUid, viewerUid, models.BOOK_MEMBER_ROLE_VIEWER)
+	assert.Equal(t, errs.ErrBookNotFound, err)
+
+	err = BookMembers.RemoveMember(c, bookUid, bookUid, editorUid)
+	assert.Equal(t, nil, err)
+
+	err = BookMembers.RemoveMember(c, bookUid, bookUid, editorUid)
+	assert.Equal(t, errs.ErrBookMemberNotFound, err)
+}
+
+func TestCreateTransaction_CheckBookMemberRole(t *testing.T) {
+	c := initializeTestDataStore(t)
+	bookUid := int64(1001)
+	editorUid := int64(1002)
+	viewerUid := int64(1003)
+	otherUid := int64(1004)
+	unixTime := int64(1700000000)
+
+	createTestBookMember(t, c, bookUid, editorUid, models.BOOK_MEMBER_ROLE_EDITOR, true)
+	createTestBookMember(t, c, bookUid, viewerUid, models.BOOK_MEMBER_ROLE_VIEWER, true)
+
+	primaryExpenseCategory := &models.TransactionCategory{
+		CategoryId: TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:        bookUid,
+		Name:       "Expense",
+		Type:       models.CATEGORY_TYPE_EXPENSE,
+	}
+
+	account := &models.Account{
+		AccountId: Accounts.GenerateUuid(uuid.UUID_TYPE_ACCOUNT),
+		Uid:       bookUid,
+		Name:      "Cash",
+		Category:  models.ACCOUNT_CATEGORY_CASH,
+		Type:      models.ACCOUNT_TYPE_SINGLE_ACCOUNT,
+		Currency:  "USD",
+		Balance:   10000,
+	}
+
+	category := &models.TransactionCategory{
+		CategoryId:       TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:              bookUid,
+		Name:             "Category",
+		Type:             models.CATEGORY_TYPE_EXPENSE,
+		ParentCategoryId: primaryExpenseCategory.CategoryId,
+	}
+
+	_, err := datastore.Container.UserDataStore.Choose(bookUid).NewSession(c).Insert(primaryExpenseCategory, account, category)
+	assert.Equal(t, nil, err)
+
+	newTransaction := func() *models.Transaction {
+		return &models.Transaction{
+			Uid:             bookUid,
+			Type:            models.TRANSACTION_DB_TYPE_EXPENSE,
+			CategoryId:      category.CategoryId,
+			AccountId:       account.AccountId,
+			Amount:          100,
+			TransactionTime: utils.GetMinTransactionTimeFromUnixTime(unixTime),
+		}
+	}
+
+	err = Transactions.CreateTransaction(c, viewerUid, newTransaction(), nil, nil)
+	assert.Equal(t, errs.ErrBookPermissionDenied, err)
+
+	err = Transactions.CreateTransaction(c, otherUid, newTransaction(), nil, nil)
+	assert.Equal(t, errs.ErrBookNotFound, err)
+
+	transaction := newTransaction()
+	err = Transactions.CreateTransaction(c, editorUid, transaction, nil, nil)
+	assert.Equal(t, nil, err)
+
+	savedTransaction, err := Transactions.GetTransactionByTransactionId(c, bookUid, transaction.TransactionId)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, editorUid, savedTransaction.CreatedUid)
+
+	err = Transactions.DeleteTransaction(c, bookUid, viewerUid, transaction.TransactionId)
+	assert.Equal(t, errs.ErrBookPermissionDenied, err)
+
+	err = Transactions.DeleteTransaction(c, bookUid, editorUid, transaction.TransactionId)
+	assert.Equal(t, nil, err)
+
+	savedAccounts, err := Accounts.GetAccountsByAccountIds(c, bookUid, []int64{account.AccountId})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(10000), savedAccounts[account.AccountId].Balance)
+}
+
+func TestModifyData_ViewerPermissionDenied(t *testing.T) {
+	c := initializeTestDataStore(t)
+	bookUid := int64(1001)
+	viewerUid := int64(1002)
+
+	createTestBookMember(t, c, bookUid, viewerUid, models.BOOK_MEMBER_ROLE_VIEWER, true)
+
+	account := &models.Account{
+		AccountId: Accounts.GenerateUuid(uuid.UUID_TYPE_ACCOUNT),
+		Uid:       bookUid,
+		Name:      "Cash",
+		Category:  models.ACCOUNT_CATEGORY_CASH,
+		Type:      models.ACCOUNT_TYPE_SINGLE_ACCOUNT,
+		Currency:  "USD",
+		Balance:   10000,
+	}
+
+	tag := &models.TransactionTag{
+		TagId: TransactionTags.GenerateUuid(uuid.UUID_TYPE_TAG),
+		Uid:   bookUid,
+		Name:  "Tag",
+	}
+
+	_, err := datastore.Container.UserDataStore.Choose(bookUid).NewSession(c).Insert(account, tag)
+	assert.Equal(t, nil, err)
+
+	err = Accounts.DeleteAccount(c, bookUid, viewerUid, account.AccountId)
+	assert.Equal(t, errs.ErrBookPermissionDenied, err)
+
+	err = TransactionTags.DeleteTag(c, bookUid, viewerUid, tag.TagId)
+	assert.Equal(t, errs.ErrBookPermissionDenied, err)
+
+	err = TransactionRules.CreateRule(c, viewerUid, &models.TransactionRule{Uid: bookUid, Name: "Rule"})
+	assert.Equal(t, errs.ErrBookPermissionDenied, err)
+
+	err = BookLocks.CloseBooks(c, bookUid, viewerUid, 0, 1700000000)
+	assert.Equal(t, errs.ErrBookPermissionDenied, err)
+
+	_, err = TransactionImports.ImportTransactions(c, &models.User{Uid: bookUid}, viewerUid, []*models.ImportTransaction{{}}, true, false, "127.0.0.1")
+	assert.Equal(t, errs.ErrBookPermissionDenied, err)
+}
+
+func TestCreateTransaction_CreatedUidDefaultsToBookOwner(t *testing.T) {
+	c := initializeTestDataStore(t)
+	uid := int64(1001)
+	startTime := int64(1700000000)
+
+	primaryExpenseCategory := &models.TransactionCategory{
+		CategoryId: TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:        uid,
+		Name:       "Expense",
+		Type:       models.CATEGORY_TYPE_EXPENSE,
+	}
+
+	account := &models.Account{
+		AccountId: Accounts.GenerateUuid(uuid.UUID_TYPE_ACCOUNT),
+		Uid:       uid,
+		Name:      "Cash",
+		Category:  models.ACCOUNT_CATEGORY_CASH,
+		Type:      models.ACCOUNT_TYPE_SINGLE_ACCOUNT,
+		Currency:  "USD",
+		Balance:   10000,
+	}
+
+	category := &models.TransactionCategory{
+		CategoryId:       TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:              uid,
+		Name:             "Category",
+		Type:             models.CATEGORY_TYPE_EXPENSE,
+		ParentCategoryId: primaryExpenseCategory.CategoryId,
+	}
+
+	_, err := datastore.Container.UserDataStore.Choose(uid).NewSession(c).Insert(primaryExpenseCategory, account, category)
+	assert.Equal(t, nil, err)
+
+	schedule := &models.TransactionSchedule{
+		Uid:        uid,
+		Type:       models.TRANSACTION_DB_TYPE_EXPENSE,
+		CategoryId: category.CategoryId,
+		AccountId:  account.AccountId,
+		Amount:     100,
+		Frequency:  models.TRANSACTION_SCHEDULE_FREQUENCY_DAILY,
+		StartTime:  startTime,
+	}
+
+	err = TransactionSchedules.CreateSchedule(c, uid, schedule)
+	assert.Equal(t, nil, err)
+
+	createdCount, err := TransactionSchedules.CreateDueScheduledTransactions(c, startTime)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, createdCount)
+
+	var transactions []*models.Transaction
+	err = datastore.Container.UserDataStore.Choose(uid).NewSession(c).Where("uid=?", uid).Find(&transactions)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, len(transactions))
+	assert.Equal(t, uid, transactions[0].CreatedUid)
+}
@@ -0,0 +1,244 @@
+package services
+
+import (
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/f97/gofire/pkg/core"
+	"github.com/f97/gofire/pkg/datastore"
+	"github.com/f97/gofire/pkg/errs"
+	"github.com/f97/gofire/pkg/models"
+	"github.com/f97/gofire/pkg/uuid"
+)
+
+// BudgetService represents budget service
+type BudgetService struct {
+	ServiceUsingDB
+	ServiceUsingUuid
+}
+
+// Initialize a budget service singleton instance
+var (
+	Budgets = &BudgetService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+		ServiceUsingUuid: ServiceUsingUuid{
+			container: uuid.Container,
+		},
+	}
+)
+
+// GetAllBudgetsByUid returns all budget models of user
+func (s *BudgetService) GetAllBudgetsByUid(c *core.Context, uid int64) ([]*models.Budget, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	var budgets []*models.Budget
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false).Find(&budgets)
+
+	return budgets, err
+}
+
+// GetBudgetByBudgetId returns a budget model according to budget id
+func (s *BudgetService) GetBudgetByBudgetId(c *core.Context, uid int64, budgetId int64) (*models.Budget, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if budgetId <= 0 {
+		return nil, errs.ErrBudgetIdInvalid
+	}
+
+	budget := &models.Budget{}
+	has, err := s.UserDataDB(uid).NewSession(c).ID(budgetId).Where("uid=? AND deleted=?", uid, false).Get(budget)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrBudgetNotFound
+	}
+
+	return budget, nil
+}
+
+// GetMaxDisplayOrder returns the max display order
+func (s *BudgetService) GetMaxDisplayOrder(c *core.Context, uid int64) (int32, error) {
+	if uid <= 0 {
+		return 0, errs.ErrUserIdInvalid
+	}
+
+	budget := &models.Budget{}
+	has, err := s.UserDataDB(uid).NewSession(c).Cols("uid", "deleted", "display_order").Where("uid=? AND deleted=?", uid, false).OrderBy("display_order desc").Limit(1).Get(budget)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if has {
+		return budget.DisplayOrder, nil
+	} else {
+		return 0, nil
+	}
+}
+
+// CreateBudget saves a new budget model to database
+func (s *BudgetService) CreateBudget(c *core.Context, operatorUid int64, budget *models.Budget) error {
+	if budget.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	err := s.CheckBookMemberRole(c, budget.Uid, operatorUid, models.BOOK_MEMBER_ROLE_EDITOR)
+
+	if err != nil {
+		return err
+	}
+
+	budget.BudgetId = s.GenerateUuid(uuid.UUID_TYPE_BUDGET)
+
+	budget.Deleted = false
+	budget.CreatedUnixTime = time.Now().Unix()
+	budget.UpdatedUnixTime = time.Now().Unix()
+
+	return s.UserDataDB(budget.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		err := s.isBudgetValid(sess, budget)
+
+		if err != nil {
+			return err
+		}
+
+		_, err = sess.Insert(budget)
+		return err
+	})
+}
+
+// ModifyBudget saves an existed budget model to database
+func (s *BudgetService) ModifyBudget(c *core.Context, operatorUid int64, budget *models.Budget) error {
+	if budget.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	err := s.CheckBookMemberRole(c, budget.Uid, operatorUid, models.BOOK_MEMBER_ROLE_EDITOR)
+
+	if err != nil {
+		return err
+	}
+
+	budget.UpdatedUnixTime = time.Now().Unix()
+
+	return s.UserDataDB(budget.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		err := s.isBudgetValid(sess, budget)
+
+		if err != nil {
+			return err
+		}
+
+		updatedRows, err := sess.ID(budget.BudgetId).Cols("category_id", "account_id", "tag_id", "period_type", "currency", "amount", "comment", "updated_unix_time").Where("uid=? AND deleted=?", budget.Uid, false).Update(budget)
+
+		if err != nil {
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrBudgetNotFound
+		}
+
+		return err
+	})
+}
+
+// DeleteBudget deletes an existed budget from database
+func (s *BudgetService) DeleteBudget(c *core.Context, uid int64, operatorUid int64, budgetId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	err := s.CheckBookMemberRole(c, uid, operatorUid, models.BOOK_MEMBER_ROLE_EDITOR)
+
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.Budget{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		deletedRows, err := sess.ID(budgetId).Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrBudgetNotFound
+		}
+
+		return err
+	})
+}
+
+// DeleteAllBudgets deletes all existed budgets from database
+func (s *BudgetService) DeleteAllBudgets(c *core.Context, uid int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.Budget{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+		return err
+	})
+}
+
+func (s *BudgetService) isBudgetValid(sess *xorm.Session, budget *models.Budget) error {
+	category := &models.TransactionCategory{}
+	has, err := sess.ID(budget.CategoryId).Where("uid=? AND deleted=?", budget.Uid, false).Get(category)
+
+	if err != nil {
+		return err
+	} else if !has {
+		return errs.ErrTransactionCategoryNotFound
+	}
+
+	if category.Type != models.CATEGORY_TYPE_EXPENSE {
+		return errs.ErrBudgetCategoryTypeInvalid
+	}
+
+	if budget.AccountId > 0 {
+		exists, err := sess.ID(budget.AccountId).Where("uid=? AND deleted=?", budget.Uid, false).Exist(&models.Account{})
+
+		if err != nil {
+			return err
+		} else if !exists {
+			return errs.ErrAccountNotFound
+		}
+	}
+
+	if budget.TagId > 0 {
+		exists, err := sess.ID(budget.TagId).Where("uid=? AND deleted=?", budget.Uid, false).Exist(&models.TransactionTag{})
+
+		if err != nil {
+			return err
+		} else if !exists {
+			return errs.ErrTransactionTagNotFound
+		}
+	}
+
+	exists, err := sess.Where("uid=? AND deleted=? AND category_id=? AND account_id=? AND tag_id=? AND period_type=? AND budget_id<>?", budget.Uid, false, budget.CategoryId, budget.AccountId, budget.TagId, budget.PeriodType, budget.BudgetId).Exist(&models.Budget{})
+
+	if err != nil {
+		return err
+	} else if exists {
+		return errs.ErrBudgetAlreadyExists
+	}
+
+	return nil
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"time"
+
+	"github.com/f97/gofire/pkg/core"
+	"github.com/f97/gofire/pkg/datastore"
+	"github.com/f97/gofire/pkg/errs"
+	"github.com/f97/gofire/pkg/models"
+	"github.com/f97/gofire/pkg/utils"
+)
+
+// creditCardMinimumPaymentRate represents the percentage of remaining statement balance which must be paid before due date
+const creditCardMinimumPaymentRate = 5
+
+// CreditCardService represents credit card service
+type CreditCardService struct {
+	ServiceUsingDB
+}
+
+// Initialize a credit card service singleton instance
+var (
+	CreditCards = &CreditCardService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+	}
+)
+
+// GetCreditCardCycle returns the statement cycle summary of credit card account at the specified unix time,
+// the statement balance and the amounts are positive when money is owed to card issuer
+func (s *CreditCardService) GetCreditCardCycle(c *core.Context, account *models.Account, unixTime int64, timezone *time.Location) (*models.CreditCardCycle, error) {
+	if account.Uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if account.Category != models.ACCOUNT_CATEGORY_CREDIT_CARD || account.Type != models.ACCOUNT_TYPE_SINGLE_ACCOUNT {
+		return nil, errs.ErrNotCreditCardAccount
+	}
+
+	if account.StatementClosingDay < 1 {
+		return nil, errs.ErrCreditCardStatementClosingDayNotSet
+	}
+
+	lastStatementUnixTime := utils.GetLastMonthDayEndUnixTime(unixTime, int(account.StatementClosingDay), timezone)
+	cycleEndUnixTime := utils.GetNextMonthDayEndUnixTime(unixTime, int(account.StatementClosingDay), timezone)
+
+	var transactions []*models.Transaction
+	err := s.UserDataDB(account.Uid).NewSession(c).Select("type, transaction_time, amount, related_account_amount").Where("uid=? AND deleted=? AND account_id=? AND transaction_time>?", account.Uid, false, account.AccountId, utils.GetMaxTransactionTimeFromUnixTime(lastStatementUnixTime)).Find(&transactions)
+
+	if err != nil {
+		return nil, err
+	}
+
+	maxCycleTransactionTime := utils.GetMaxTransactionTimeFromUnixTime(cycleEndUnixTime)
+	balanceChangesSinceStatement := int64(0)
+	cycleSpend := int64(0)
+	paymentsSinceStatement := int64(0)
+
+	for i := 0; i < len(transactions); i++ {
+		transaction := transactions[i]
+
+		if transaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
+			balanceChangesSinceStatement += transaction.RelatedAccountAmount
+		} else if transaction.Type == models.TRANSACTION_DB_TYPE_INCOME || transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+			balanceChangesSinceStatement += transaction.Amount
+
+			if transaction.TransactionTime <= maxCycleTransactionTime {
+				paymentsSinceStatement += transaction.Amount
+			}
+		} else if transaction.Type == models.TRANSACTION_DB_TYPE_EXPENSE || transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
+			balanceChangesSinceStatement -= transaction.Amount
+
+			if transaction.TransactionTime <= maxCycleTransactionTime {
+				cycleSpend += transaction.Amount
+			}
+		}
+	}
+
+	lastStatementBalance := -(account.Balance - balanceChangesSinceStatement)
+	remainingStatementBalance := lastStatementBalance - paymentsSinceStatement
+
+	if remainingStatementBalance < 0 {
+		remainingStatementBalance = 0
+	}
+
+	cycle := &models.CreditCardCycle{
+		AccountId:                 account.AccountId,
+		CycleStartUnixTime:        lastStatementUnixTime + 1,
+		CycleEndUnixTime:          cycleEndUnixTime,
+		CycleSpend:                cycleSpend,
+		LastStatementUnixTime:     lastStatementUnixTime,
+		LastStatementBalance:      lastStatementBalance,
+		PaymentsSinceStatement:    paymentsSinceStatement,
+		RemainingStatementBalance: remainingStatementBalance,
+		MinimumDue:                (remainingStatementBalance*creditCardMinimumPaymentRate + 99) / 100,
+		CreditLimit:               account.CreditLimit,
+	}
+
+	if account.PaymentDueDay > 0 {
+		cycle.PaymentDueUnixTime = utils.GetNextMonthDayEndUnixTime(lastStatementUnixTime, int(account.PaymentDueDay), timezone)
+		cycle.DaysUntilDue = int32(utils.GetDaysBetweenUnixTimes(unixTime, cycle.PaymentDueUnixTime, timezone))
+	}
+
+	if account.CreditLimit > 0 {
+		cycle.AvailableCredit = account.CreditLimit + account.Balance
+	}
+
+	return cycle, nil
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/f97/gofire/pkg/core"
+	"github.com/f97/gofire/pkg/datastore"
+	"github.com/f97/gofire/pkg/models"
+	"github.com/f97/gofire/pkg/utils"
+	"github.com/f97/gofire/pkg/uuid"
+)
+
+// createTestCreditCardCycleData saves a credit card account whose statement closes on day 15 and payment is due on day 5,
+// with one expense before the statement on 2024-03-15 and one expense and one payment after it
+func createTestCreditCardCycleData(t *testing.T, c *core.Context, uid int64, balance int64) *models.Account {
+	account := &models.Account{
+		AccountId:           Accounts.GenerateUuid(uuid.UUID_TYPE_ACCOUNT),
+		Uid:                 uid,
+		Name:                "Credit Card",
+		Category:            models.ACCOUNT_CATEGORY_CREDIT_CARD,
+		Type:                models.ACCOUNT_TYPE_SINGLE_ACCOUNT,
+		Currency:            "USD",
+		Balance:             balance,
+		StatementClosingDay: 15,
+		PaymentDueDay:       5,
+	}
+
+	transactions := []*models.Transaction{
+		{Type: models.TRANSACTION_DB_TYPE_EXPENSE, Amount: 5000, TransactionTime: time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC).Unix()},
+		{Type: models.TRANSACTION_DB_TYPE_EXPENSE, Amount: 2000, TransactionTime: time.Date(2024, 3, 17, 12, 0, 0, 0, time.UTC).Unix()},
+		{Type: models.TRANSACTION_DB_TYPE_TRANSFER_IN, Amount: 3000, TransactionTime: time.Date(2024, 3, 18, 12, 0, 0, 0, time.UTC).Unix()},
+	}
+
+	for i := 0; i < len(transactions); i++ {
+		transactions[i].TransactionId = Transactions.GenerateUuid(uuid.UUID_TYPE_TRANSACTION)
+		transactions[i].Uid = uid
+		transactions[i].AccountId = account.AccountId
+		transactions[i].TransactionTime = utils.GetMinTransactionTimeFromUnixTime(transactions[i].TransactionTime)
+	}
+
+	_, err := datastore.Container.UserDataStore.Choose(uid).NewSession(c).Insert(account, transactions)
+	assert.Equal(t, nil, err)
+
+	return account
+}
+
+func TestGetCreditCardCycle_LastStatementBalance(t *testing.T) {
+	c := initializeTestDataStore(t)
+	uid := int64(1001)
+	account := createTestCreditCardCycleData(t, c, uid, -14000)
+
+	cycle, err := CreditCards.GetCreditCardCycle(c, account, time.Date(2024, 3, 20, 12, 0, 0, 0, time.UTC).Unix(), time.UTC)
+	assert.Equal(t, nil, err)
+
+	expectedValue := int64(15000)
+	actualValue := cycle.LastStatementBalance
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestGetCreditCardCycle_PaymentsSinceStatement(t *testing.T) {
+	c := initializeTestDataStore(t)
+	uid := int64(1001)
+	account := createTestCreditCardCycleData(t, c, uid, -14000)
+
+	cycle, err := CreditCards.GetCreditCardCycle(c, account, time.Date(2024, 3, 20, 12, 0, 0, 0, time.UTC).Unix(), time.UTC)
+	assert.Equal(t, nil, err)
+
+	expectedValue := int64(3000)
+	actualValue := cycle.PaymentsSinceStatement
+	assert.Equal(t, expectedValue, actualValue)
+
+	expectedValue = int64(12000)
+	actualValue = cycle.RemainingStatementBalance
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestGetCreditCardCycle_MinimumDueRoundedUp(t *testing.T) {
+	c := initializeTestDataStore(t)
+	uid := int64(1001)
+	account := createTestCreditCardCycleData(t, c, uid, -14345)
+
+	cycle, err := CreditCards.GetCreditCardCycle(c, account, time.Date(2024, 3, 20, 12, 0, 0, 0, time.UTC).Unix(), time.UTC)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(12345), cycle.RemainingStatementBalance)
+
+	// 5% of 12345 is 617.25
+	expectedValue := int64(618)
+	actualValue := cycle.MinimumDue
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestGetCreditCardCycle_DaysUntilDueAcrossMonthBoundary(t *testing.T) {
+	c := initializeTestDataStore(t)
+	uid := int64(1001)
+	account := createTestCreditCardCycleData(t, c, uid, -14000)
+
+	cycle, err := CreditCards.GetCreditCardCycle(c, account, time.Date(2024, 3, 20, 12, 0, 0, 0, time.UTC).Unix(), time.UTC)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, time.Date(2024, 4, 5, 23, 59, 59, 0, time.UTC).Unix(), cycle.PaymentDueUnixTime)
+
+	expectedValue := int32(16)
+	actualValue := cycle.DaysUntilDue
+	assert.Equal(t, expectedValue, actualValue)
+}
@@ -0,0 +1,298 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/f97/gofire/pkg/core"
+	"github.com/f97/gofire/pkg/datastore"
+	"github.com/f97/gofire/pkg/errs"
+	"github.com/f97/gofire/pkg/models"
+	"github.com/f97/gofire/pkg/utils"
+	"github.com/f97/gofire/pkg/uuid"
+)
+
+// DataRevisionService represents data revision service
+type DataRevisionService struct {
+	ServiceUsingDB
+	ServiceUsingUuid
+}
+
+// Initialize a data revision service singleton instance
+var (
+	DataRevisions = &DataRevisionService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+		ServiceUsingUuid: ServiceUsingUuid{
+			container: uuid.Container,
+		},
+	}
+)
+
+// dataRevisionField represents the name and value of a field which is tracked in data revision
+type dataRevisionField struct {
+	name  string
+	value string
+}
+
+// GetTransactionRevisions returns all revision models of the specified transaction, the transaction may have been deleted
+func (s *DataRevisionService) GetTransactionRevisions(c *core.Context, uid int64, transactionId int64) ([]*models.DataRevision, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if transactionId <= 0 {
+		return nil, errs.ErrTransactionIdInvalid
+	}
+
+	transaction := &models.Transaction{}
+	has, err := s.UserDataDB(uid).NewSession(c).ID(transactionId).Where("uid=?", uid).Get(transaction)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrTransactionNotFound
+	}
+
+	if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+		transactionId = transaction.RelatedId
+	}
+
+	return s.GetRevisionsByObjectId(c, uid, models.DATA_REVISION_OBJECT_TYPE_TRANSACTION, transactionId)
+}
+
+// GetRevisionsByObjectId returns all revision models of the specified object
+func (s *DataRevisionService) GetRevisionsByObjectId(c *core.Context, uid int64, objectType models.DataRevisionObjectType, objectId int64) ([]*models.DataRevision, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	var revisions []*models.DataRevision
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND object_type=? AND object_id=?", uid, objectType, objectId).OrderBy("created_unix_time asc, revision_id asc").Find(&revisions)
+
+	return revisions, err
+}
+
+// DeleteAllRevisions deletes all revisions of user
+func (s *DataRevisionService) DeleteAllRevisions(c *core.Context, uid int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Where("uid=?", uid).Delete(&models.DataRevision{})
+		return err
+	})
+}
+
+// insertRevision saves a new revision of the specified object in the given session, nothing is saved when a modification changes no field
+func (s *DataRevisionService) insertRevision(c *core.Context, sess *xorm.Session, uid int64, objectType models.DataRevisionObjectType, objectId int64, action models.DataRevisionAction, oldFields []dataRevisionField, newFields []dataRevisionField) error {
+	revision := s.newRevision(c, uid, objectType, objectId, action, oldFields, newFields)
+
+	if revision == nil {
+		return nil
+	}
+
+	_, err := sess.Insert(revision)
+
+	return err
+}
+
+// newRevision returns a new revision model of the specified object, or nil when a modification changes no field
+func (s *DataRevisionService) newRevision(c *core.Context, uid int64, objectType models.DataRevisionObjectType, objectId int64, action models.DataRevisionAction, oldFields []dataRevisionField, newFields []dataRevisionField) *models.DataRevision {
+	changes := s.getChangedFields(oldFields, newFields)
+
+	if action == models.DATA_REVISION_ACTION_MODIFY && len(changes) < 1 {
+		return nil
+	}
+
+	tokenId, clientIp := s.getOperatorInfo(c)
+
+	revision := &models.DataRevision{
+		RevisionId:      s.GenerateUuid(uuid.UUID_TYPE_REVISION),
+		Uid:             uid,
+		ObjectType:      objectType,
+		ObjectId:        objectId,
+		Action:          action,
+		TokenId:         tokenId,
+		ClientIp:        clientIp,
+		CreatedUnixTime: time.Now().Unix(),
+	}
+
+	revision.SetChanges(changes)
+
+	return revision
+}
+
+func (s *DataRevisionService) getChangedFields(oldFields []dataRevisionField, newFields []dataRevisionField) []*models.DataRevisionFieldChange {
+	fieldCount := len(oldFields)
+
+	if len(newFields) > fieldCount {
+		fieldCount = len(newFields)
+	}
+
+	changes := make([]*models.DataRevisionFieldChange, 0, fieldCount)
+
+	for i := 0; i < fieldCount; i++ {
+		change := &models.DataRevisionFieldChange{}
+
+		if i < len(oldFields) {
+			change.Field = oldFields[i].name
+			change.OldValue = oldFields[i].value
+		}
+
+		if i < len(newFields) {
+			change.Field = newFields[i].name
+			change.NewValue = newFields[i].value
+		}
+
+		if change.OldValue != change.NewValue {
+			changes = append(changes, change)
+		}
+	}
+
+	return changes
+}
+
+func (s *DataRevisionService) getOperatorInfo(c *core.Context) (string, string) {
+	if c == nil || c.Context == nil {
+		return "", ""
+	}
+
+	tokenId := ""
+	clientIp := ""
+	claims := c.GetTokenClaims()
+
+	if claims != nil {
+		tokenId = fmt.Sprintf("%d:%d:%s", claims.Uid, claims.IssuedAt, claims.UserTokenId)
+	}
+
+	if c.Request != nil {
+		clientIp = c.ClientIP()
+	}
+
+	return tokenId, clientIp
+}
+
+func getTransactionRevisionFields(transaction *models.Transaction, tagIds []int64, splits []*models.TransactionSplit) []dataRevisionField {
+	if transaction == nil {
+		return nil
+	}
+
+	transactionType := models.TRANSACTION_TYPE_MODIFY_BALANCE
+
+	switch transaction.Type {
+	case models.TRANSACTION_DB_TYPE_INCOME:
+		transactionType = models.TRANSACTION_TYPE_INCOME
+	case models.TRANSACTION_DB_TYPE_EXPENSE:
+		transactionType = models.TRANSACTION_TYPE_EXPENSE
+	case models.TRANSACTION_DB_TYPE_TRANSFER_OUT, models.TRANSACTION_DB_TYPE_TRANSFER_IN:
+		transactionType = models.TRANSACTION_TYPE_TRANSFER
+	}
+
+	destinationAccountId := ""
+	destinationAmount := ""
+
+	if transactionType == models.TRANSACTION_TYPE_TRANSFER {
+		destinationAccountId = utils.Int64ToString(transaction.RelatedAccountId)
+		destinationAmount = utils.Int64ToString(transaction.RelatedAccountAmount)
+	}
+
+	geoLocation := ""
+
+	if transaction.GeoLongitude != 0 || transaction.GeoLatitude != 0 {
+		geoLocation = fmt.Sprintf("%f,%f", transaction.GeoLongitude, transaction.GeoLatitude)
+	}
+
+	splitValues := make([]string, len(splits))
+
+	for i := 0; i < len(splits); i++ {
+		splitValues[i] = fmt.Sprintf("%d:%d", splits[i].CategoryId, splits[i].Amount)
+	}
+
+	return []dataRevisionField{
+		{name: "type", value: utils.IntToString(int(transactionType))},
+		{name: "categoryId", value: utils.Int64ToString(transaction.CategoryId)},
+		{name: "time", value: utils.Int64ToString(utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime))},
+		{name: "utcOffset", value: utils.IntToString(int(transaction.TimezoneUtcOffset))},
+		{name: "sourceAccountId", value: utils.Int64ToString(transaction.AccountId)},
+		{name: "sourceAmount", value: utils.Int64ToString(transaction.Amount)},
+		{name: "destinationAccountId", value: destinationAccountId},
+		{name: "destinationAmount", value: destinationAmount},
+		{name: "hideAmount", value: fmt.Sprintf("%t", transaction.HideAmount)},
+		{name: "comment", value: transaction.Comment},
+		{name: "geoLocation", value: geoLocation},
+		{name: "tagIds", value: strings.Join(utils.Int64ArrayToStringArray(tagIds), ",")},
+		{name: "splits", value: strings.Join(splitValues, ",")},
+	}
+}
+
+func getAccountRevisionFields(account *models.Account) []dataRevisionField {
+	if account == nil {
+		return nil
+	}
+
+	return []dataRevisionField{
+		{name: "name", value: account.Name},
+		{name: "category", value: utils.IntToString(int(account.Category))},
+		{name: "type", value: utils.IntToString(int(account.Type))},
+		{name: "parentAccountId", value: utils.Int64ToString(account.ParentAccountId)},
+		{name: "icon", value: utils.Int64ToString(account.Icon)},
+		{name: "color", value: account.Color},
+		{name: "currency", value: account.Currency},
+		{name: "comment", value: account.Comment},
+		{name: "hidden", value: fmt.Sprintf("%t", account.Hidden)},
+		{name: "statementClosingDay", value: utils.IntToString(int(account.StatementClosingDay))},
+		{name: "paymentDueDay", value: utils.IntToString(int(account.PaymentDueDay))},
+		{name: "creditLimit", value: utils.Int64ToString(account.CreditLimit)},
+	}
+}
+
+func getCategoryRevisionFields(category *models.TransactionCategory) []dataRevisionField {
+	if category == nil {
+		return nil
+	}
+
+	return []dataRevisionField{
+		{name: "name", value: category.Name},
+		{name: "type", value: utils.IntToString(int(category.Type))},
+		{name: "parentId", value: utils.Int64ToString(category.ParentCategoryId)},
+		{name: "icon", value: utils.Int64ToString(category.Icon)},
+		{name: "color", value: category.Color},
+		{name: "comment", value: category.Comment},
+		{name: "hidden", value: fmt.Sprintf("%t", category.Hidden)},
+	}
+}
+
+func getTagRevisionFields(tag *models.TransactionTag) []dataRevisionField {
+	if tag == nil {
+		return nil
+	}
+
+	return []dataRevisionField{
+		{name: "name", value: tag.Name},
+		{name: "hidden", value: fmt.Sprintf("%t", tag.Hidden)},
+	}
+}
+
+func getBookLockRevisionFields(bookLock *models.BookLock) []dataRevisionField {
+	if bookLock == nil {
+		return nil
+	}
+
+	return []dataRevisionField{
+		{name: "closedTime", value: utils.Int64ToString(bookLock.ClosedUnixTime)},
+	}
+}
+
+func getTransactionRevisionObjectId(transaction *models.Transaction) int64 {
+	if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+		return transaction.RelatedId
+	}
+
+	return transaction.TransactionId
+}
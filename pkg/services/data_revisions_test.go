@@ -0,0 +1,249 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/f97/gofire/pkg/datastore"
+	"github.com/f97/gofire/pkg/models"
+	"github.com/f97/gofire/pkg/utils"
+	"github.com/f97/gofire/pkg/uuid"
+)
+
+// getTestRevisionChangeMap returns the changed fields of revision keyed by field name
+func getTestRevisionChangeMap(revision *models.DataRevision) map[string]*models.DataRevisionFieldChange {
+	changes := revision.GetChanges()
+	changeMap := make(map[string]*models.DataRevisionFieldChange, len(changes))
+
+	for i := 0; i < len(changes); i++ {
+		changeMap[changes[i].Field] = changes[i]
+	}
+
+	return changeMap
+}
+
+func TestGetChangedFields(t *testing.T) {
+	oldFields := []dataRevisionField{
+		{name: "name", value: "Old"},
+		{name: "hidden", value: "false"},
+	}
+	newFields := []dataRevisionField{
+		{name: "name", value: "New"},
+		{name: "hidden", value: "false"},
+	}
+
+	changes := DataRevisions.getChangedFields(oldFields, newFields)
+	assert.Equal(t, 1, len(changes))
+	assert.Equal(t, "name", changes[0].Field)
+	assert.Equal(t, "Old", changes[0].OldValue)
+	assert.Equal(t, "New", changes[0].NewValue)
+
+	changes = DataRevisions.getChangedFields(nil, newFields)
+	assert.Equal(t, 2, len(changes))
+	assert.Equal(t, "", changes[0].OldValue)
+	assert.Equal(t, "New", changes[0].NewValue)
+
+	changes = DataRevisions.getChangedFields(oldFields, nil)
+	assert.Equal(t, 2, len(changes))
+	assert.Equal(t, "Old", changes[0].OldValue)
+	assert.Equal(t, "", changes[0].NewValue)
+
+	changes = DataRevisions.getChangedFields(oldFields, oldFields)
+	assert.Equal(t, 0, len(changes))
+}
+
+func TestNewRevision_SkipModificationWithoutChanges(t *testing.T) {
+	initializeTestDataStore(t)
+	fields := []dataRevisionField{
+		{name: "name", value: "Name"},
+	}
+
+	revision := DataRevisions.newRevision(nil, 1001, models.DATA_REVISION_OBJECT_TYPE_TAG, 1, models.DATA_REVISION_ACTION_MODIFY, fields, fields)
+	assert.Nil(t, revision)
+
+	revision = DataRevisions.newRevision(nil, 1001, models.DATA_REVISION_OBJECT_TYPE_TAG, 1, models.DATA_REVISION_ACTION_DELETE, fields, nil)
+	assert.NotNil(t, revision)
+	assert.Equal(t, models.DATA_REVISION_ACTION_DELETE, revision.Action)
+	assert.Equal(t, "", revision.TokenId)
+	assert.Equal(t, "", revision.ClientIp)
+}
+
+func TestTransactionRevisions_RecordTransactionLifecycle(t *testing.T) {
+	c := initializeTestDataStore(t)
+	uid := int64(1001)
+
+	primaryExpenseCategory := &models.TransactionCategory{
+		CategoryId: TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:        uid,
+		Name:       "Expense",
+		Type:       models.CATEGORY_TYPE_EXPENSE,
+	}
+
+	account := &models.Account{
+		AccountId: Accounts.GenerateUuid(uuid.UUID_TYPE_ACCOUNT),
+		Uid:       uid,
+		Name:      "Cash",
+		Category:  models.ACCOUNT_CATEGORY_CASH,
+		Type:      models.ACCOUNT_TYPE_SINGLE_ACCOUNT,
+		Currency:  "USD",
+		Balance:   1000,
+	}
+
+	category := &models.TransactionCategory{
+		CategoryId:       TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:              uid,
+		Name:             "Category",
+		Type:             models.CATEGORY_TYPE_EXPENSE,
+		ParentCategoryId: primaryExpenseCategory.CategoryId,
+	}
+
+	tag := &models.TransactionTag{
+		TagId: TransactionTags.GenerateUuid(uuid.UUID_TYPE_TAG),
+		Uid:   uid,
+		Name:  "Tag",
+	}
+
+	_, err := datastore.Container.UserDataStore.Choose(uid).NewSession(c).Insert(primaryExpenseCategory, account, category, tag)
+	assert.Equal(t, nil, err)
+	transaction := &models.Transaction{
+		Uid:             uid,
+		Type:            models.TRANSACTION_DB_TYPE_EXPENSE,
+		CategoryId:      category.CategoryId,
+		AccountId:       account.AccountId,
+		Amount:          100,
+		TransactionTime: utils.GetMinTransactionTimeFromUnixTime(1704067200),
+	}
+
+	err = Transactions.CreateTransaction(c, uid, transaction, nil, nil)
+	assert.Equal(t, nil, err)
+
+	revisions, err := DataRevisions.GetTransactionRevisions(c, uid, transaction.TransactionId)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, len(revisions))
+	assert.Equal(t, models.DATA_REVISION_ACTION_CREATE, revisions[0].Action)
+
+	changeMap := getTestRevisionChangeMap(revisions[0])
+	assert.Equal(t, "", changeMap["sourceAmount"].OldValue)
+	assert.Equal(t, "100", changeMap["sourceAmount"].NewValue)
+	assert.Equal(t, utils.Int64ToString(account.AccountId), changeMap["sourceAccountId"].NewValue)
+
+	newTransaction := &models.Transaction{
+		TransactionId:   transaction.TransactionId,
+		Uid:             uid,
+		CategoryId:      category.CategoryId,
+		AccountId:       account.AccountId,
+		Amount:          150,
+		Comment:         "modified",
+		TransactionTime: transaction.TransactionTime,
+	}
+
+	err = Transactions.ModifyTransaction(c, uid, newTransaction, []int64{tag.TagId}, nil, nil)
+	assert.Equal(t, nil, err)
+
+	revisions, err = DataRevisions.GetTransactionRevisions(c, uid, transaction.TransactionId)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2, len(revisions))
+	assert.Equal(t, models.DATA_REVISION_ACTION_MODIFY, revisions[1].Action)
+
+	changeMap = getTestRevisionChangeMap(revisions[1])
+	assert.Equal(t, 3, len(changeMap))
+	assert.Equal(t, "100", changeMap["sourceAmount"].OldValue)
+	assert.Equal(t, "150", changeMap["sourceAmount"].NewValue)
+	assert.Equal(t, "", changeMap["comment"].OldValue)
+	assert.Equal(t, "modified", changeMap["comment"].NewValue)
+	assert.Equal(t, "", changeMap["tagIds"].OldValue)
+	assert.Equal(t, utils.Int64ToString(tag.TagId), changeMap["tagIds"].NewValue)
+
+	err = Transactions.DeleteTransaction(c, uid, uid, transaction.TransactionId)
+	assert.Equal(t, nil, err)
+
+	err = Transactions.RestoreTransaction(c, uid, uid, transaction.TransactionId, 0)
+	assert.Equal(t, nil, err)
+
+	revisions, err = DataRevisions.GetTransactionRevisions(c, uid, transaction.TransactionId)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 4, len(revisions))
+	assert.Equal(t, models.DATA_REVISION_ACTION_DELETE, revisions[2].Action)
+	assert.Equal(t, models.DATA_REVISION_ACTION_RESTORE, revisions[3].Action)
+
+	changeMap = getTestRevisionChangeMap(revisions[2])
+	assert.Equal(t, "150", changeMap["sourceAmount"].OldValue)
+	assert.Equal(t, "", changeMap["sourceAmount"].NewValue)
+
+	changeMap = getTestRevisionChangeMap(revisions[3])
+	assert.Equal(t, "", changeMap["sourceAmount"].OldValue)
+	assert.Equal(t, "150", changeMap["sourceAmount"].NewValue)
+	assert.Equal(t, utils.Int64ToString(tag.TagId), changeMap["tagIds"].NewValue)
+}
+
+func TestTransactionRevisions_TransferInSharesRevisions(t *testing.T) {
+	c := initializeTestDataStore(t)
+	uid := int64(1001)
+
+	primaryTransferCategory := &models.TransactionCategory{
+		CategoryId: TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:        uid,
+		Name:       "Transfer",
+		Type:       models.CATEGORY_TYPE_TRANSFER,
+	}
+
+	account1 := &models.Account{
+		AccountId: Accounts.GenerateUuid(uuid.UUID_TYPE_ACCOUNT),
+		Uid:       uid,
+		Name:      "Cash",
+		Category:  models.ACCOUNT_CATEGORY_CASH,
+		Type:      models.ACCOUNT_TYPE_SINGLE_ACCOUNT,
+		Currency:  "USD",
+		Balance:   1000,
+	}
+
+	account2 := &models.Account{
+		AccountId: Accounts.GenerateUuid(uuid.UUID_TYPE_ACCOUNT),
+		Uid:       uid,
+		Name:      "Bank",
+		Category:  models.ACCOUNT_CATEGORY_CASH,
+		Type:      models.ACCOUNT_TYPE_SINGLE_ACCOUNT,
+		Currency:  "USD",
+		Balance:   0,
+	}
+
+	category := &models.TransactionCategory{
+		CategoryId:       TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:              uid,
+		Name:             "Category",
+		Type:             models.CATEGORY_TYPE_TRANSFER,
+		ParentCategoryId: primaryTransferCategory.CategoryId,
+	}
+
+	_, err := datastore.Container.UserDataStore.Choose(uid).NewSession(c).Insert(primaryTransferCategory, account1, account2, category)
+	assert.Equal(t, nil, err)
+
+	transaction := &models.Transaction{
+		Uid:                  uid,
+		Type:                 models.TRANSACTION_DB_TYPE_TRANSFER_OUT,
+		CategoryId:           category.CategoryId,
+		AccountId:            account1.AccountId,
+		Amount:               300,
+		RelatedAccountId:     account2.AccountId,
+		RelatedAccountAmount: 300,
+		TransactionTime:      utils.GetMinTransactionTimeFromUnixTime(1704067200),
+	}
+
+	err = Transactions.CreateTransaction(c, uid, transaction, nil, nil)
+	assert.Equal(t, nil, err)
+
+	revisions, err := DataRevisions.GetTransactionRevisions(c, uid, transaction.TransactionId)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, len(revisions))
+
+	relatedRevisions, err := DataRevisions.GetTransactionRevisions(c, uid, transaction.RelatedId)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, len(relatedRevisions))
+	assert.Equal(t, revisions[0].RevisionId, relatedRevisions[0].RevisionId)
+
+	changeMap := getTestRevisionChangeMap(revisions[0])
+	assert.Equal(t, utils.IntToString(int(models.TRANSACTION_TYPE_TRANSFER)), changeMap["type"].NewValue)
+	assert.Equal(t, utils.Int64ToString(account2.AccountId), changeMap["destinationAccountId"].NewValue)
+	assert.Equal(t, "300", changeMap["destinationAmount"].NewValue)
+}
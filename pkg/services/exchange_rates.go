@@ -0,0 +1,152 @@
+package services
+
+import (
+	"sort"
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/f97/gofire/pkg/core"
+	"github.com/f97/gofire/pkg/datastore"
+	"github.com/f97/gofire/pkg/errs"
+	"github.com/f97/gofire/pkg/exchangerates"
+	"github.com/f97/gofire/pkg/log"
+	"github.com/f97/gofire/pkg/models"
+)
+
+const exchangeRateDateFormat = "2006-01-02"
+
+// ExchangeRateService represents historical exchange rate service
+type ExchangeRateService struct {
+	ServiceUsingDB
+}
+
+// Initialize a exchange rate service singleton instance
+var (
+	ExchangeRates = &ExchangeRateService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+	}
+)
+
+// GetExchangeRatesByDate returns the exchange rates of the specified data source at the latest date which is not later than the specified date
+func (s *ExchangeRateService) GetExchangeRatesByDate(c *core.Context, dataSource string, date string) (*models.ExchangeRateHistoryResponse, error) {
+	if dataSource == "" {
+		return nil, errs.ErrInvalidExchangeRatesDataSource
+	}
+
+	if _, err := time.Parse(exchangeRateDateFormat, date); err != nil {
+		return nil, errs.ErrExchangeRateDateInvalid
+	}
+
+	latestExchangeRate := &models.ExchangeRate{}
+	has, err := s.UserDB().NewSession(c).Where("data_source=? AND rate_date<=?", dataSource, date).Desc("rate_date").Get(latestExchangeRate)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrHistoricalExchangeRatesNotFound
+	}
+
+	var exchangeRates []*models.ExchangeRate
+	err = s.UserDB().NewSession(c).Where("data_source=? AND rate_date=?", dataSource, latestExchangeRate.RateDate).Find(&exchangeRates)
+
+	if err != nil {
+		return nil, err
+	}
+
+	allExchangeRates := make(models.LatestExchangeRateSlice, 0, len(exchangeRates))
+
+	for i := 0; i < len(exchangeRates); i++ {
+		allExchangeRates = append(allExchangeRates, &models.LatestExchangeRate{
+			Currency: exchangeRates[i].Currency,
+			Rate:     exchangeRates[i].Rate,
+		})
+	}
+
+	sort.Sort(allExchangeRates)
+
+	return &models.ExchangeRateHistoryResponse{
+		DataSource:    dataSource,
+		Date:          latestExchangeRate.RateDate,
+		UpdateTime:    latestExchangeRate.UpdateUnixTime,
+		BaseCurrency:  latestExchangeRate.BaseCurrency,
+		ExchangeRates: allExchangeRates,
+	}, nil
+}
+
+// GetExchangeRatesByUnixTime returns the saved exchange rates of current data source at the date of specified unix time, or the latest exchange rates if there are no saved exchange rates before that date
+func (s *ExchangeRateService) GetExchangeRatesByUnixTime(c *core.Context, unixTime int64) (*models.ExchangeRateHistoryResponse, error) {
+	dataSource := exchangerates.Container.CurrentName
+	date := time.Unix(unixTime, 0).UTC().Format(exchangeRateDateFormat)
+	exchangeRateResp, err := s.GetExchangeRatesByDate(c, dataSource, date)
+
+	if err == nil {
+		return exchangeRateResp, nil
+	} else if err != errs.ErrHistoricalExchangeRatesNotFound {
+		return nil, err
+	}
+
+	latestExchangeRateResp, refreshed, err := exchangerates.Container.GetCachedLatestExchangeRates(c)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if refreshed {
+		err = s.SaveExchangeRates(c, dataSource, latestExchangeRateResp)
+
+		if err != nil {
+			log.WarnfWithRequestId(c, "[exchange_rates.GetExchangeRatesByUnixTime] failed to save latest exchange rate data, because %s", err.Error())
+		}
+	}
+
+	return &models.ExchangeRateHistoryResponse{
+		DataSource:    dataSource,
+		Date:          time.Unix(latestExchangeRateResp.UpdateTime, 0).UTC().Format(exchangeRateDateFormat),
+		UpdateTime:    latestExchangeRateResp.UpdateTime,
+		BaseCurrency:  latestExchangeRateResp.BaseCurrency,
+		ExchangeRates: latestExchangeRateResp.ExchangeRates,
+	}, nil
+}
+
+// SaveExchangeRates saves the exchange rates of the specified data source, the saved exchange rates of the same date would be replaced
+func (s *ExchangeRateService) SaveExchangeRates(c *core.Context, dataSource string, exchangeRateResp *models.LatestExchangeRateResponse) error {
+	if dataSource == "" {
+		return errs.ErrInvalidExchangeRatesDataSource
+	}
+
+	if exchangeRateResp == nil || len(exchangeRateResp.ExchangeRates) < 1 {
+		return errs.ErrNothingWillBeUpdated
+	}
+
+	now := time.Now().Unix()
+	rateDate := time.Unix(exchangeRateResp.UpdateTime, 0).UTC().Format(exchangeRateDateFormat)
+	exchangeRates := make([]*models.ExchangeRate, 0, len(exchangeRateResp.ExchangeRates))
+
+	for i := 0; i < len(exchangeRateResp.ExchangeRates); i++ {
+		exchangeRates = append(exchangeRates, &models.ExchangeRate{
+			DataSource:      dataSource,
+			RateDate:        rateDate,
+			Currency:        exchangeRateResp.ExchangeRates[i].Currency,
+			BaseCurrency:    exchangeRateResp.BaseCurrency,
+			Rate:            exchangeRateResp.ExchangeRates[i].Rate,
+			UpdateUnixTime:  exchangeRateResp.UpdateTime,
+			CreatedUnixTime: now,
+			UpdatedUnixTime: now,
+		})
+	}
+
+	return s.UserDB().DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Where("data_source=? AND rate_date=?", dataSource, rateDate).Delete(&models.ExchangeRate{})
+
+		if err != nil {
+			return err
+		}
+
+		_, err = sess.Insert(exchangeRates)
+
+		return err
+	})
+}
@@ -0,0 +1,671 @@
+package services
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/f97/gofire/pkg/core"
+	"github.com/f97/gofire/pkg/datastore"
+	"github.com/f97/gofire/pkg/errs"
+	"github.com/f97/gofire/pkg/models"
+	"github.com/f97/gofire/pkg/uuid"
+)
+
+// InvestmentService represents investment holding service
+type InvestmentService struct {
+	ServiceUsingDB
+	ServiceUsingUuid
+}
+
+// Initialize an investment holding service singleton instance
+var (
+	Investments = &InvestmentService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+		ServiceUsingUuid: ServiceUsingUuid{
+			container: uuid.Container,
+		},
+	}
+)
+
+// GetAllHoldingsByUid returns all investment holding models of user
+func (s *InvestmentService) GetAllHoldingsByUid(c *core.Context, uid int64) ([]*models.InvestmentHolding, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	var holdings []*models.InvestmentHolding
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false).OrderBy("account_id asc, symbol asc").Find(&holdings)
+
+	return holdings, err
+}
+
+// GetHoldingsByAccountId returns all investment holding models of the specified account
+func (s *InvestmentService) GetHoldingsByAccountId(c *core.Context, uid int64, accountId int64) ([]*models.InvestmentHolding, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if accountId <= 0 {
+		return nil, errs.ErrAccountIdInvalid
+	}
+
+	var holdings []*models.InvestmentHolding
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=? AND account_id=?", uid, false, accountId).OrderBy("symbol asc").Find(&holdings)
+
+	return holdings, err
+}
+
+// GetHoldingById returns an investment holding model according to holding id
+func (s *InvestmentService) GetHoldingById(c *core.Context, uid int64, holdingId int64) (*models.InvestmentHolding, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if holdingId <= 0 {
+		return nil, errs.ErrInvestmentHoldingIdInvalid
+	}
+
+	holding := &models.InvestmentHolding{}
+	has, err := s.UserDataDB(uid).NewSession(c).ID(holdingId).Where("uid=? AND deleted=?", uid, false).Get(holding)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrInvestmentHoldingNotFound
+	}
+
+	return holding, nil
+}
+
+// CreateHolding saves a new investment holding model to database
+func (s *InvestmentService) CreateHolding(c *core.Context, operatorUid int64, holding *models.InvestmentHolding) error {
+	if holding.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	err := s.CheckBookMemberRole(c, holding.Uid, operatorUid, models.BOOK_MEMBER_ROLE_EDITOR)
+
+	if err != nil {
+		return err
+	}
+
+	if !holding.CostMethod.IsValid() {
+		return errs.ErrInvestmentCostMethodInvalid
+	}
+
+	now := time.Now().Unix()
+
+	holding.HoldingId = s.GenerateUuid(uuid.UUID_TYPE_DEFAULT)
+	holding.Deleted = false
+	holding.CreatedUnixTime = now
+	holding.UpdatedUnixTime = now
+
+	return s.UserDataDB(holding.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		account := &models.Account{}
+		has, err := sess.ID(holding.AccountId).Where("uid=? AND deleted=?", holding.Uid, false).Get(account)
+
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrAccountNotFound
+		} else if account.Category != models.ACCOUNT_CATEGORY_INVESTMENT || account.Type != models.ACCOUNT_TYPE_SINGLE_ACCOUNT {
+			return errs.ErrInvestmentAccountInvalid
+		}
+
+		err = s.isSymbolAvailable(sess, holding)
+
+		if err != nil {
+			return err
+		}
+
+		_, err = sess.Insert(holding)
+		return err
+	})
+}
+
+// ModifyHolding saves an existed investment holding model to database
+func (s *InvestmentService) ModifyHolding(c *core.Context, operatorUid int64, holding *models.InvestmentHolding) error {
+	if holding.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	err := s.CheckBookMemberRole(c, holding.Uid, operatorUid, models.BOOK_MEMBER_ROLE_EDITOR)
+
+	if err != nil {
+		return err
+	}
+
+	if !holding.CostMethod.IsValid() {
+		return errs.ErrInvestmentCostMethodInvalid
+	}
+
+	holding.UpdatedUnixTime = time.Now().Unix()
+
+	return s.UserDataDB(holding.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		err := s.isSymbolAvailable(sess, holding)
+
+		if err != nil {
+			return err
+		}
+
+		updatedRows, err := sess.ID(holding.HoldingId).Cols("symbol", "name", "cost_method", "comment", "updated_unix_time").Where("uid=? AND deleted=?", holding.Uid, false).Update(holding)
+
+		if err != nil {
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrInvestmentHoldingNotFound
+		}
+
+		return err
+	})
+}
+
+// DeleteHolding deletes an existed investment holding and all its trades and prices from database
+func (s *InvestmentService) DeleteHolding(c *core.Context, uid int64, operatorUid int64, holdingId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	err := s.CheckBookMemberRole(c, uid, operatorUid, models.BOOK_MEMBER_ROLE_EDITOR)
+
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.InvestmentHolding{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	updateTradeModel := &models.InvestmentTrade{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	updatePriceModel := &models.InvestmentPrice{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		deletedRows, err := sess.ID(holdingId).Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrInvestmentHoldingNotFound
+		}
+
+		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=? AND holding_id=?", uid, false, holdingId).Update(updateTradeModel)
+
+		if err != nil {
+			return err
+		}
+
+		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=? AND holding_id=?", uid, false, holdingId).Update(updatePriceModel)
+
+		return err
+	})
+}
+
+// DeleteAllHoldings deletes all existed investment holdings, trades and prices from database
+func (s *InvestmentService) DeleteAllHoldings(c *core.Context, uid int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.InvestmentHolding{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	updateTradeModel := &models.InvestmentTrade{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	updatePriceModel := &models.InvestmentPrice{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+
+		if err != nil {
+			return err
+		}
+
+		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateTradeModel)
+
+		if err != nil {
+			return err
+		}
+
+		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updatePriceModel)
+
+		return err
+	})
+}
+
+// GetAllTradesByHoldingId returns all trade models of the specified investment holding in ascending order of trade time
+func (s *InvestmentService) GetAllTradesByHoldingId(c *core.Context, uid int64, holdingId int64) ([]*models.InvestmentTrade, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if holdingId <= 0 {
+		return nil, errs.ErrInvestmentHoldingIdInvalid
+	}
+
+	var trades []*models.InvestmentTrade
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=? AND holding_id=?", uid, false, holdingId).OrderBy("trade_unix_time asc, trade_id asc").Find(&trades)
+
+	return trades, err
+}
+
+// CreateTrade saves a new trade model to database, the units sold must not exceed the units held at trade time and all later trades must remain valid
+func (s *InvestmentService) CreateTrade(c *core.Context, operatorUid int64, trade *models.InvestmentTrade) error {
+	if trade.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	err := s.CheckBookMemberRole(c, trade.Uid, operatorUid, models.BOOK_MEMBER_ROLE_EDITOR)
+
+	if err != nil {
+		return err
+	}
+
+	if !trade.Type.IsValid() {
+		return errs.ErrInvestmentTradeTypeInvalid
+	}
+
+	now := time.Now().Unix()
+
+	trade.TradeId = s.GenerateUuid(uuid.UUID_TYPE_TRADE)
+	trade.Deleted = false
+	trade.CreatedUnixTime = now
+	trade.UpdatedUnixTime = now
+
+	return s.UserDataDB(trade.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		holding := &models.InvestmentHolding{}
+		has, err := sess.ID(trade.HoldingId).Where("uid=? AND deleted=?", trade.Uid, false).Get(holding)
+
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrInvestmentHoldingNotFound
+		}
+
+		var trades []*models.InvestmentTrade
+		err = sess.Where("uid=? AND deleted=? AND holding_id=?", trade.Uid, false, trade.HoldingId).OrderBy("trade_unix_time asc, trade_id asc").Find(&trades)
+
+		if err != nil {
+			return err
+		}
+
+		trades = append(trades, trade)
+		sort.SliceStable(trades, func(i, j int) bool {
+			return trades[i].TradeUnixTime < trades[j].TradeUnixTime
+		})
+
+		_, err = holding.GetPerformance(trades, nil, math.MaxInt64)
+
+		if err != nil {
+			return err
+		}
+
+		_, err = sess.Insert(trade)
+		return err
+	})
+}
+
+// DeleteTrade deletes an existed trade from database, the remaining sell trades must not exceed the units held
+func (s *InvestmentService) DeleteTrade(c *core.Context, uid int64, operatorUid int64, tradeId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	err := s.CheckBookMemberRole(c, uid, operatorUid, models.BOOK_MEMBER_ROLE_EDITOR)
+
+	if err != nil {
+		return err
+	}
+
+	if tradeId <= 0 {
+		return errs.ErrInvestmentTradeIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.InvestmentTrade{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		trade := &models.InvestmentTrade{}
+		has, err := sess.ID(tradeId).Where("uid=? AND deleted=?", uid, false).Get(trade)
+
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrInvestmentTradeNotFound
+		}
+
+		holding := &models.InvestmentHolding{}
+		has, err = sess.ID(trade.HoldingId).Where("uid=? AND deleted=?", uid, false).Get(holding)
+
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrInvestmentHoldingNotFound
+		}
+
+		var remainingTrades []*models.InvestmentTrade
+		err = sess.Where("uid=? AND deleted=? AND holding_id=? AND trade_id<>?", uid, false, trade.HoldingId, tradeId).OrderBy("trade_unix_time asc, trade_id asc").Find(&remainingTrades)
+
+		if err != nil {
+			return err
+		}
+
+		_, err = holding.GetPerformance(remainingTrades, nil, math.MaxInt64)
+
+		if err != nil {
+			return err
+		}
+
+		deletedRows, err := sess.ID(tradeId).Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrInvestmentTradeNotFound
+		}
+
+		return err
+	})
+}
+
+// GetAllPricesByHoldingId returns all price models of the specified investment holding in descending order of price time
+func (s *InvestmentService) GetAllPricesByHoldingId(c *core.Context, uid int64, holdingId int64) ([]*models.InvestmentPrice, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if holdingId <= 0 {
+		return nil, errs.ErrInvestmentHoldingIdInvalid
+	}
+
+	var prices []*models.InvestmentPrice
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=? AND holding_id=?", uid, false, holdingId).OrderBy("price_unix_time desc").Find(&prices)
+
+	return prices, err
+}
+
+// CreatePrice saves a new price model to database, the existed price of the same holding and time is replaced
+func (s *InvestmentService) CreatePrice(c *core.Context, operatorUid int64, price *models.InvestmentPrice) error {
+	if price.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	err := s.CheckBookMemberRole(c, price.Uid, operatorUid, models.BOOK_MEMBER_ROLE_EDITOR)
+
+	if err != nil {
+		return err
+	}
+
+	return s.UserDataDB(price.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		exists, err := sess.Cols("uid", "deleted", "holding_id").Where("uid=? AND deleted=? AND holding_id=?", price.Uid, false, price.HoldingId).Exist(&models.InvestmentHolding{})
+
+		if err != nil {
+			return err
+		} else if !exists {
+			return errs.ErrInvestmentHoldingNotFound
+		}
+
+		return s.savePrices(sess, price.Uid, []*models.InvestmentPrice{price})
+	})
+}
+
+// DeletePrice deletes an existed price from database
+func (s *InvestmentService) DeletePrice(c *core.Context, uid int64, operatorUid int64, priceId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	err := s.CheckBookMemberRole(c, uid, operatorUid, models.BOOK_MEMBER_ROLE_EDITOR)
+
+	if err != nil {
+		return err
+	}
+
+	if priceId <= 0 {
+		return errs.ErrInvestmentPriceIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.InvestmentPrice{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		deletedRows, err := sess.ID(priceId).Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrInvestmentPriceNotFound
+		}
+
+		return err
+	})
+}
+
+// ImportPrices saves the imported prices to the holdings of the specified account whose symbol matches case-insensitively,
+// and returns the count of imported and skipped prices
+func (s *InvestmentService) ImportPrices(c *core.Context, uid int64, operatorUid int64, accountId int64, importPrices []*models.ImportInvestmentPrice) (int, int, error) {
+	err := s.CheckBookMemberRole(c, uid, operatorUid, models.BOOK_MEMBER_ROLE_EDITOR)
+
+	if err != nil {
+		return 0, 0, err
+	}
+
+	holdings, err := s.GetHoldingsByAccountId(c, uid, accountId)
+
+	if err != nil {
+		return 0, 0, err
+	}
+
+	holdingIds := make(map[string]int64, len(holdings))
+
+	for i := 0; i < len(holdings); i++ {
+		holdingIds[strings.ToUpper(holdings[i].Symbol)] = holdings[i].HoldingId
+	}
+
+	prices := make([]*models.InvestmentPrice, 0, len(importPrices))
+
+	for i := 0; i < len(importPrices); i++ {
+		importPrice := importPrices[i]
+		holdingId, exists := holdingIds[strings.ToUpper(importPrice.Symbol)]
+
+		if !exists {
+			continue
+		}
+
+		prices = append(prices, &models.InvestmentPrice{
+			Uid:           uid,
+			HoldingId:     holdingId,
+			PriceUnixTime: importPrice.PriceUnixTime,
+			Price:         importPrice.Price,
+		})
+	}
+
+	if len(prices) < 1 {
+		return 0, len(importPrices), nil
+	}
+
+	err = s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		return s.savePrices(sess, uid, prices)
+	})
+
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return len(prices), len(importPrices) - len(prices), nil
+}
+
+// GetHoldingPerformances returns the performance of every specified holding at each unix time,
+// the performances of i-th unix time are returned in i-th map whose key is holding id
+func (s *InvestmentService) GetHoldingPerformances(c *core.Context, uid int64, holdings []*models.InvestmentHolding, unixTimes []int64) ([]map[int64]*models.InvestmentHoldingPerformance, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	allPerformances := make([]map[int64]*models.InvestmentHoldingPerformance, len(unixTimes))
+
+	for i := 0; i < len(unixTimes); i++ {
+		allPerformances[i] = make(map[int64]*models.InvestmentHoldingPerformance, len(holdings))
+	}
+
+	if len(holdings) < 1 {
+		return allPerformances, nil
+	}
+
+	holdingIds := make([]int64, len(holdings))
+
+	for i := 0; i < len(holdings); i++ {
+		holdingIds[i] = holdings[i].HoldingId
+	}
+
+	var trades []*models.InvestmentTrade
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false).In("holding_id", holdingIds).OrderBy("trade_unix_time asc, trade_id asc").Find(&trades)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var prices []*models.InvestmentPrice
+	err = s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false).In("holding_id", holdingIds).Find(&prices)
+
+	if err != nil {
+		return nil, err
+	}
+
+	holdingTrades := make(map[int64][]*models.InvestmentTrade, len(holdings))
+	holdingPrices := make(map[int64][]*models.InvestmentPrice, len(holdings))
+
+	for i := 0; i < len(trades); i++ {
+		holdingTrades[trades[i].HoldingId] = append(holdingTrades[trades[i].HoldingId], trades[i])
+	}
+
+	for i := 0; i < len(prices); i++ {
+		holdingPrices[prices[i].HoldingId] = append(holdingPrices[prices[i].HoldingId], prices[i])
+	}
+
+	for i := 0; i < len(unixTimes); i++ {
+		for j := 0; j < len(holdings); j++ {
+			holding := holdings[j]
+			performance, err := holding.GetPerformance(holdingTrades[holding.HoldingId], holdingPrices[holding.HoldingId], unixTimes[i])
+
+			if err != nil {
+				return nil, err
+			}
+
+			allPerformances[i][holding.HoldingId] = performance
+		}
+	}
+
+	return allPerformances, nil
+}
+
+// GetAccountsMarketValuesByUnixTimes returns the total market value of holdings of every investment account at each unix time,
+// the market values of i-th unix time are returned in i-th map whose key is account id, and accounts without holdings are not included
+func (s *InvestmentService) GetAccountsMarketValuesByUnixTimes(c *core.Context, uid int64, unixTimes []int64) ([]map[int64]int64, error) {
+	holdings, err := s.GetAllHoldingsByUid(c, uid)
+
+	if err != nil {
+		return nil, err
+	}
+
+	allPerformances, err := s.GetHoldingPerformances(c, uid, holdings, unixTimes)
+
+	if err != nil {
+		return nil, err
+	}
+
+	allMarketValues := make([]map[int64]int64, len(unixTimes))
+
+	for i := 0; i < len(unixTimes); i++ {
+		allMarketValues[i] = make(map[int64]int64)
+
+		for j := 0; j < len(holdings); j++ {
+			holding := holdings[j]
+			allMarketValues[i][holding.AccountId] += allPerformances[i][holding.HoldingId].MarketValue
+		}
+	}
+
+	return allMarketValues, nil
+}
+
+func (s *InvestmentService) savePrices(sess *xorm.Session, uid int64, prices []*models.InvestmentPrice) error {
+	now := time.Now().Unix()
+
+	updateModel := &models.InvestmentPrice{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	for i := 0; i < len(prices); i++ {
+		price := prices[i]
+
+		_, err := sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=? AND holding_id=? AND price_unix_time=?", uid, false, price.HoldingId, price.PriceUnixTime).Update(updateModel)
+
+		if err != nil {
+			return err
+		}
+
+		price.PriceId = s.GenerateUuid(uuid.UUID_TYPE_PRICE)
+		price.Deleted = false
+		price.CreatedUnixTime = now
+		price.UpdatedUnixTime = now
+
+		_, err = sess.Insert(price)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *InvestmentService) isSymbolAvailable(sess *xorm.Session, holding *models.InvestmentHolding) error {
+	var holdings []*models.InvestmentHolding
+	err := sess.Cols("holding_id", "symbol").Where("uid=? AND deleted=? AND account_id=?", holding.Uid, false, holding.AccountId).Find(&holdings)
+
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < len(holdings); i++ {
+		if holdings[i].HoldingId != holding.HoldingId && strings.EqualFold(holdings[i].Symbol, holding.Symbol) {
+			return errs.ErrInvestmentHoldingSymbolAlreadyExists
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,369 @@
+package services
+
+import (
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/f97/gofire/pkg/core"
+	"github.com/f97/gofire/pkg/datastore"
+	"github.com/f97/gofire/pkg/errs"
+	"github.com/f97/gofire/pkg/models"
+	"github.com/f97/gofire/pkg/uuid"
+)
+
+// LoanService represents loan service
+type LoanService struct {
+	ServiceUsingDB
+	ServiceUsingUuid
+	transactions *TransactionService
+}
+
+// Initialize a loan service singleton instance
+var (
+	Loans = &LoanService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+		ServiceUsingUuid: ServiceUsingUuid{
+			container: uuid.Container,
+		},
+		transactions: Transactions,
+	}
+)
+
+// GetAllLoansByUid returns all loan models of user
+func (s *LoanService) GetAllLoansByUid(c *core.Context, uid int64) ([]*models.Loan, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	var loans []*models.Loan
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false).OrderBy("start_time asc").Find(&loans)
+
+	return loans, err
+}
+
+// GetLoanById returns a loan model according to loan id
+func (s *LoanService) GetLoanById(c *core.Context, uid int64, loanId int64) (*models.Loan, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if loanId <= 0 {
+		return nil, errs.ErrLoanIdInvalid
+	}
+
+	loan := &models.Loan{}
+	has, err := s.UserDataDB(uid).NewSession(c).ID(loanId).Where("uid=? AND deleted=?", uid, false).Get(loan)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrLoanNotFound
+	}
+
+	return loan, nil
+}
+
+// CreateLoan saves a new loan model and the transaction schedule of its payments to database, the transactions of payments before now are not created automatically
+func (s *LoanService) CreateLoan(c *core.Context, operatorUid int64, loan *models.Loan) error {
+	if loan.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	err := s.CheckBookMemberRole(c, loan.Uid, operatorUid, models.BOOK_MEMBER_ROLE_EDITOR)
+
+	if err != nil {
+		return err
+	}
+
+	err = s.isLoanValid(loan)
+
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().Unix()
+
+	loan.LoanId = s.GenerateUuid(uuid.UUID_TYPE_DEFAULT)
+	loan.Deleted = false
+	loan.CreatedUnixTime = now
+	loan.UpdatedUnixTime = now
+
+	return s.UserDataDB(loan.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		exists, err := sess.Cols("uid", "deleted", "account_id").Where("uid=? AND deleted=? AND account_id=?", loan.Uid, false, loan.AccountId).Exist(&models.Loan{})
+
+		if err != nil {
+			return err
+		} else if exists {
+			return errs.ErrLoanAlreadyExists
+		}
+
+		err = s.isLoanRelatedDataValid(sess, loan)
+
+		if err != nil {
+			return err
+		}
+
+		_, err = sess.Insert(loan)
+
+		if err != nil {
+			return err
+		}
+
+		return s.saveLoanSchedule(sess, loan, now)
+	})
+}
+
+// ModifyLoan saves an existed loan model and the transaction schedule of its payments to database, the transactions of payments before now are not created automatically
+func (s *LoanService) ModifyLoan(c *core.Context, operatorUid int64, loan *models.Loan) error {
+	if loan.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	err := s.CheckBookMemberRole(c, loan.Uid, operatorUid, models.BOOK_MEMBER_ROLE_EDITOR)
+
+	if err != nil {
+		return err
+	}
+
+	err = s.isLoanValid(loan)
+
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().Unix()
+
+	loan.UpdatedUnixTime = now
+
+	return s.UserDataDB(loan.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		err := s.isLoanRelatedDataValid(sess, loan)
+
+		if err != nil {
+			return err
+		}
+
+		updatedRows, err := sess.ID(loan.LoanId).Cols("principal", "interest_rate", "term_months", "start_time", "timezone_utc_offset", "auto_create_transactions", "payment_account_id", "principal_category_id", "interest_category_id", "comment", "updated_unix_time").Where("uid=? AND deleted=?", loan.Uid, false).Update(loan)
+
+		if err != nil {
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrLoanNotFound
+		}
+
+		return s.saveLoanSchedule(sess, loan, now)
+	})
+}
+
+// DeleteLoan deletes an existed loan and the transaction schedule of its payments from database, the transactions created by this loan are kept
+func (s *LoanService) DeleteLoan(c *core.Context, uid int64, operatorUid int64, loanId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	err := s.CheckBookMemberRole(c, uid, operatorUid, models.BOOK_MEMBER_ROLE_EDITOR)
+
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.Loan{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	updateScheduleModel := &models.TransactionSchedule{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		deletedRows, err := sess.ID(loanId).Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrLoanNotFound
+		}
+
+		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=? AND loan_id=?", uid, false, loanId).Update(updateScheduleModel)
+
+		return err
+	})
+}
+
+// DeleteAllLoans deletes all existed loans and the transaction schedules of their payments from database
+func (s *LoanService) DeleteAllLoans(c *core.Context, uid int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.Loan{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	updateScheduleModel := &models.TransactionSchedule{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+
+		if err != nil {
+			return err
+		}
+
+		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=? AND loan_id>?", uid, false, 0).Update(updateScheduleModel)
+
+		return err
+	})
+}
+
+// createPaymentTransactions creates the principal transfer transaction and the interest expense transaction of the loan payment at the given unix time in the given session,
+// it is called by the transaction schedule of loan, so the payment transactions and the schedule run are saved in the same database transaction
+func (s *LoanService) createPaymentTransactions(c *core.Context, sess *xorm.Session, uid int64, loanId int64, paymentUnixTime int64) ([]*models.Transaction, error) {
+	loan := &models.Loan{}
+	has, err := sess.ID(loanId).Where("uid=? AND deleted=?", uid, false).Get(loan)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrLoanNotFound
+	}
+
+	period := loan.GetPaymentPeriod(paymentUnixTime)
+	items := loan.GetAmortizationSchedule()
+
+	if period < 1 || int(period) > len(items) {
+		return nil, errs.ErrLoanPaymentNotFound
+	}
+
+	item := items[period-1]
+	transactions := make([]*models.Transaction, 0, 2)
+
+	if item.Principal > 0 {
+		transactions = append(transactions, loan.ToPrincipalTransaction(item))
+	}
+
+	if item.Interest > 0 {
+		transactions = append(transactions, loan.ToInterestTransaction(item))
+	}
+
+	for i := 0; i < len(transactions); i++ {
+		err = s.transactions.createTransaction(c, sess, transactions[i], nil, nil)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return transactions, nil
+}
+
+// saveLoanSchedule creates, updates or deletes the monthly transaction schedule which creates the payment transactions of the loan,
+// the payments before now are not created automatically
+func (s *LoanService) saveLoanSchedule(sess *xorm.Session, loan *models.Loan, now int64) error {
+	oldSchedule := &models.TransactionSchedule{}
+	has, err := sess.Where("uid=? AND deleted=? AND loan_id=?", loan.Uid, false, loan.LoanId).Get(oldSchedule)
+
+	if err != nil {
+		return err
+	}
+
+	if !loan.AutoCreateTransactions {
+		if !has {
+			return nil
+		}
+
+		_, err = sess.ID(oldSchedule.ScheduleId).Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", loan.Uid, false).Update(&models.TransactionSchedule{
+			Deleted:         true,
+			DeletedUnixTime: now,
+		})
+
+		return err
+	}
+
+	schedule := loan.ToTransactionSchedule()
+	schedule.UpdatedUnixTime = now
+	afterTime := now
+
+	if has && oldSchedule.LastRunTime > afterTime {
+		afterTime = oldSchedule.LastRunTime
+	}
+
+	schedule.NextRunTime = schedule.GetNextRunTime(afterTime)
+
+	if has {
+		schedule.ScheduleId = oldSchedule.ScheduleId
+		_, err = sess.ID(schedule.ScheduleId).Cols("category_id", "account_id", "amount", "related_account_id", "related_account_amount", "comment", "start_time", "end_time", "timezone_utc_offset", "next_run_time", "updated_unix_time").Where("uid=? AND deleted=?", loan.Uid, false).Update(schedule)
+
+		return err
+	}
+
+	schedule.ScheduleId = s.GenerateUuid(uuid.UUID_TYPE_SCHEDULE)
+	schedule.CreatedUnixTime = now
+
+	_, err = sess.Insert(schedule)
+
+	return err
+}
+
+func (s *LoanService) isLoanValid(loan *models.Loan) error {
+	if loan.AutoCreateTransactions && (loan.PaymentAccountId <= 0 || loan.PrincipalCategoryId <= 0 || loan.InterestCategoryId <= 0) {
+		return errs.ErrLoanPaymentSettingsInvalid
+	}
+
+	if loan.AutoCreateTransactions && loan.PaymentAccountId == loan.AccountId {
+		return errs.ErrLoanPaymentAccountInvalid
+	}
+
+	return nil
+}
+
+func (s *LoanService) isLoanRelatedDataValid(sess *xorm.Session, loan *models.Loan) error {
+	account := &models.Account{}
+	has, err := sess.ID(loan.AccountId).Where("uid=? AND deleted=?", loan.Uid, false).Get(account)
+
+	if err != nil {
+		return err
+	} else if !has {
+		return errs.ErrAccountNotFound
+	} else if account.Category != models.ACCOUNT_CATEGORY_DEBT || account.Type != models.ACCOUNT_TYPE_SINGLE_ACCOUNT {
+		return errs.ErrLoanAccountInvalid
+	}
+
+	if !loan.AutoCreateTransactions {
+		return nil
+	}
+
+	paymentAccount := &models.Account{}
+	has, err = sess.ID(loan.PaymentAccountId).Where("uid=? AND deleted=?", loan.Uid, false).Get(paymentAccount)
+
+	if err != nil {
+		return err
+	} else if !has || paymentAccount.Type != models.ACCOUNT_TYPE_SINGLE_ACCOUNT {
+		return errs.ErrLoanPaymentAccountInvalid
+	} else if paymentAccount.Currency != account.Currency {
+		return errs.ErrLoanPaymentAccountCurrencyNotEqual
+	}
+
+	sampleItem := &models.LoanAmortizationItem{
+		PaymentUnixTime: loan.StartTime,
+	}
+
+	err = s.transactions.isCategoryValid(sess, loan.ToPrincipalTransaction(sampleItem))
+
+	if err != nil {
+		return err
+	}
+
+	return s.transactions.isCategoryValid(sess, loan.ToInterestTransaction(sampleItem))
+}
@@ -0,0 +1,234 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/f97/gofire/pkg/core"
+	"github.com/f97/gofire/pkg/datastore"
+	"github.com/f97/gofire/pkg/errs"
+	"github.com/f97/gofire/pkg/models"
+	"github.com/f97/gofire/pkg/uuid"
+)
+
+// createTestLoan saves a new loan which creates payment transactions automatically from the cash account to a new debt account
+func createTestLoan(t *testing.T, c *core.Context, uid int64, paymentAccount *models.Account, startTime int64) (*models.Loan, *models.Account) {
+	debtAccount := &models.Account{
+		AccountId: Accounts.GenerateUuid(uuid.UUID_TYPE_ACCOUNT),
+		Uid:       uid,
+		Name:      "Mortgage",
+		Category:  models.ACCOUNT_CATEGORY_DEBT,
+		Type:      models.ACCOUNT_TYPE_SINGLE_ACCOUNT,
+		Currency:  "USD",
+		Balance:   -1000000,
+	}
+
+	primaryTransferCategory := &models.TransactionCategory{
+		CategoryId: TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:        uid,
+		Name:       "Transfer",
+		Type:       models.CATEGORY_TYPE_TRANSFER,
+	}
+
+	primaryExpenseCategory := &models.TransactionCategory{
+		CategoryId: TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:        uid,
+		Name:       "Expense",
+		Type:       models.CATEGORY_TYPE_EXPENSE,
+	}
+
+	principalCategory := &models.TransactionCategory{
+		CategoryId:       TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:              uid,
+		Name:             "Principal Category",
+		Type:             models.CATEGORY_TYPE_TRANSFER,
+		ParentCategoryId: primaryTransferCategory.CategoryId,
+	}
+
+	interestCategory := &models.TransactionCategory{
+		CategoryId:       TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:              uid,
+		Name:             "Interest Category",
+		Type:             models.CATEGORY_TYPE_EXPENSE,
+		ParentCategoryId: primaryExpenseCategory.CategoryId,
+	}
+
+	_, err := datastore.Container.UserDataStore.Choose(uid).NewSession(c).Insert(debtAccount, primaryTransferCategory, primaryExpenseCategory, principalCategory, interestCategory)
+	assert.Equal(t, nil, err)
+
+	loan := &models.Loan{
+		Uid:                    uid,
+		AccountId:              debtAccount.AccountId,
+		Principal:              1000000,
+		InterestRate:           6000,
+		TermMonths:             12,
+		StartTime:              startTime,
+		AutoCreateTransactions: true,
+		PaymentAccountId:       paymentAccount.AccountId,
+		PrincipalCategoryId:    principalCategory.CategoryId,
+		InterestCategoryId:     interestCategory.CategoryId,
+	}
+
+	err = Loans.CreateLoan(c, uid, loan)
+	assert.Equal(t, nil, err)
+
+	return loan, debtAccount
+}
+
+// getTestLoanSchedule returns the transaction schedule of the given loan
+func getTestLoanSchedule(t *testing.T, c *core.Context, uid int64, loanId int64) *models.TransactionSchedule {
+	schedules, err := TransactionSchedules.GetAllSchedulesByUid(c, uid)
+	assert.Equal(t, nil, err)
+
+	for i := 0; i < len(schedules); i++ {
+		if schedules[i].LoanId == loanId {
+			return schedules[i]
+		}
+	}
+
+	return nil
+}
+
+func TestCreateLoan_CreateTransactionSchedule(t *testing.T) {
+	c := initializeTestDataStore(t)
+	uid := int64(1001)
+	startTime := time.Now().Add(24 * time.Hour).Unix()
+
+	paymentAccount := &models.Account{
+		AccountId: Accounts.GenerateUuid(uuid.UUID_TYPE_ACCOUNT),
+		Uid:       uid,
+		Name:      "Cash",
+		Category:  models.ACCOUNT_CATEGORY_CASH,
+		Type:      models.ACCOUNT_TYPE_SINGLE_ACCOUNT,
+		Currency:  "USD",
+		Balance:   10000000,
+	}
+
+	_, err := datastore.Container.UserDataStore.Choose(uid).NewSession(c).Insert(paymentAccount)
+	assert.Equal(t, nil, err)
+	loan, debtAccount := createTestLoan(t, c, uid, paymentAccount, startTime)
+
+	schedule := getTestLoanSchedule(t, c, uid, loan.LoanId)
+	assert.NotNil(t, schedule)
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_TRANSFER_OUT, schedule.Type)
+	assert.Equal(t, paymentAccount.AccountId, schedule.AccountId)
+	assert.Equal(t, debtAccount.AccountId, schedule.RelatedAccountId)
+	assert.Equal(t, int64(86066), schedule.Amount)
+	assert.Equal(t, startTime, schedule.NextRunTime)
+	assert.Equal(t, loan.GetPaymentUnixTime(12), schedule.EndTime)
+
+	// The schedule created by loan can only be changed by loan
+	err = TransactionSchedules.ModifySchedule(c, uid, schedule)
+	assert.Equal(t, errs.ErrTransactionScheduleCreatedByLoan, err)
+
+	err = TransactionSchedules.DeleteSchedule(c, uid, uid, schedule.ScheduleId)
+	assert.Equal(t, errs.ErrTransactionScheduleCreatedByLoan, err)
+
+	loan.AutoCreateTransactions = false
+	err = Loans.ModifyLoan(c, uid, loan)
+	assert.Equal(t, nil, err)
+	assert.Nil(t, getTestLoanSchedule(t, c, uid, loan.LoanId))
+
+	loan.AutoCreateTransactions = true
+	err = Loans.ModifyLoan(c, uid, loan)
+	assert.Equal(t, nil, err)
+	assert.NotNil(t, getTestLoanSchedule(t, c, uid, loan.LoanId))
+
+	err = Loans.DeleteLoan(c, uid, uid, loan.LoanId)
+	assert.Equal(t, nil, err)
+	assert.Nil(t, getTestLoanSchedule(t, c, uid, loan.LoanId))
+}
+
+func TestCreateLoan_PaymentsBeforeNowNotCreated(t *testing.T) {
+	c := initializeTestDataStore(t)
+	uid := int64(1001)
+	now := time.Now()
+	startTime := time.Date(now.Year(), now.Month()-3, 1, 0, 0, 0, 0, time.UTC).Unix()
+
+	paymentAccount := &models.Account{
+		AccountId: Accounts.GenerateUuid(uuid.UUID_TYPE_ACCOUNT),
+		Uid:       uid,
+		Name:      "Cash",
+		Category:  models.ACCOUNT_CATEGORY_CASH,
+		Type:      models.ACCOUNT_TYPE_SINGLE_ACCOUNT,
+		Currency:  "USD",
+		Balance:   10000000,
+	}
+
+	_, err := datastore.Container.UserDataStore.Choose(uid).NewSession(c).Insert(paymentAccount)
+	assert.Equal(t, nil, err)
+	loan, _ := createTestLoan(t, c, uid, paymentAccount, startTime)
+
+	schedule := getTestLoanSchedule(t, c, uid, loan.LoanId)
+	assert.NotNil(t, schedule)
+
+	for period := int32(1); period <= loan.TermMonths; period++ {
+		if paymentTime := loan.GetPaymentUnixTime(period); paymentTime > now.Unix() {
+			assert.Equal(t, paymentTime, schedule.NextRunTime)
+			break
+		}
+	}
+
+	assert.Equal(t, int64(0), schedule.LastRunTime)
+}
+
+func TestCreateDueScheduledTransactions_CreateLoanPayments(t *testing.T) {
+	c := initializeTestDataStore(t)
+	uid := int64(1001)
+	startTime := time.Now().Add(24 * time.Hour).Unix()
+
+	paymentAccount := &models.Account{
+		AccountId: Accounts.GenerateUuid(uuid.UUID_TYPE_ACCOUNT),
+		Uid:       uid,
+		Name:      "Cash",
+		Category:  models.ACCOUNT_CATEGORY_CASH,
+		Type:      models.ACCOUNT_TYPE_SINGLE_ACCOUNT,
+		Currency:  "USD",
+		Balance:   10000000,
+	}
+
+	_, err := datastore.Container.UserDataStore.Choose(uid).NewSession(c).Insert(paymentAccount)
+	assert.Equal(t, nil, err)
+	loan, debtAccount := createTestLoan(t, c, uid, paymentAccount, startTime)
+
+	// Transactions cannot be added to hidden account, so both principal and interest transactions are not created
+	_, err = datastore.Container.UserDataStore.Choose(uid).NewSession(c).ID(paymentAccount.AccountId).Cols("hidden").Update(&models.Account{Hidden: true})
+	assert.Equal(t, nil, err)
+
+	createdCount, err := TransactionSchedules.CreateDueScheduledTransactions(c, loan.GetPaymentUnixTime(2))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 0, createdCount)
+
+	schedule := getTestLoanSchedule(t, c, uid, loan.LoanId)
+	assert.Equal(t, startTime, schedule.NextRunTime)
+
+	transactionCount, err := Transactions.GetAllTransactionCount(c, uid)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(0), transactionCount)
+
+	_, err = datastore.Container.UserDataStore.Choose(uid).NewSession(c).ID(paymentAccount.AccountId).Cols("hidden").Update(&models.Account{Hidden: false})
+	assert.Equal(t, nil, err)
+
+	createdCount, err = TransactionSchedules.CreateDueScheduledTransactions(c, loan.GetPaymentUnixTime(2))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 4, createdCount)
+
+	schedule = getTestLoanSchedule(t, c, uid, loan.LoanId)
+	assert.Equal(t, loan.GetPaymentUnixTime(3), schedule.NextRunTime)
+	assert.Equal(t, loan.GetPaymentUnixTime(2), schedule.LastRunTime)
+
+	// Principal 81066 + 81471 and interest 5000 + 4595
+	savedAccounts, err := Accounts.GetAccountsByAccountIds(c, uid, []int64{paymentAccount.AccountId})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(10000000-86066*2), savedAccounts[paymentAccount.AccountId].Balance)
+	savedAccounts, err = Accounts.GetAccountsByAccountIds(c, uid, []int64{debtAccount.AccountId})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(-1000000+81066+81471), savedAccounts[debtAccount.AccountId].Balance)
+
+	// Payments which have already been created will not be created again
+	createdCount, err = TransactionSchedules.CreateDueScheduledTransactions(c, loan.GetPaymentUnixTime(2))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 0, createdCount)
+}
@@ -0,0 +1,259 @@
+package services
+
+import (
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/f97/gofire/pkg/core"
+	"github.com/f97/gofire/pkg/datastore"
+	"github.com/f97/gofire/pkg/errs"
+	"github.com/f97/gofire/pkg/models"
+	"github.com/f97/gofire/pkg/utils"
+	"github.com/f97/gofire/pkg/uuid"
+)
+
+// SavingsGoalService represents savings goal service
+type SavingsGoalService struct {
+	ServiceUsingDB
+	ServiceUsingUuid
+}
+
+// Initialize a savings goal service singleton instance
+var (
+	SavingsGoals = &SavingsGoalService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+		ServiceUsingUuid: ServiceUsingUuid{
+			container: uuid.Container,
+		},
+	}
+)
+
+// GetAllGoalsByUid returns all savings goal models of user
+func (s *SavingsGoalService) GetAllGoalsByUid(c *core.Context, uid int64) ([]*models.SavingsGoal, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	var goals []*models.SavingsGoal
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false).OrderBy("target_date asc").Find(&goals)
+
+	return goals, err
+}
+
+// GetGoalById returns a savings goal model according to goal id
+func (s *SavingsGoalService) GetGoalById(c *core.Context, uid int64, goalId int64) (*models.SavingsGoal, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if goalId <= 0 {
+		return nil, errs.ErrSavingsGoalIdInvalid
+	}
+
+	goal := &models.SavingsGoal{}
+	has, err := s.UserDataDB(uid).NewSession(c).ID(goalId).Where("uid=? AND deleted=?", uid, false).Get(goal)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrSavingsGoalNotFound
+	}
+
+	return goal, nil
+}
+
+// CreateGoal saves a new savings goal model to database, the currency of goal is set to the currency of linked accounts
+func (s *SavingsGoalService) CreateGoal(c *core.Context, operatorUid int64, goal *models.SavingsGoal) error {
+	if goal.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	err := s.CheckBookMemberRole(c, goal.Uid, operatorUid, models.BOOK_MEMBER_ROLE_EDITOR)
+
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().Unix()
+
+	goal.GoalId = s.GenerateUuid(uuid.UUID_TYPE_DEFAULT)
+	goal.Deleted = false
+	goal.CreatedUnixTime = now
+	goal.UpdatedUnixTime = now
+
+	return s.UserDataDB(goal.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		err := s.setGoalCurrency(sess, goal)
+
+		if err != nil {
+			return err
+		}
+
+		_, err = sess.Insert(goal)
+		return err
+	})
+}
+
+// ModifyGoal saves an existed savings goal model to database, the currency of goal is set to the currency of linked accounts
+func (s *SavingsGoalService) ModifyGoal(c *core.Context, operatorUid int64, goal *models.SavingsGoal) error {
+	if goal.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	err := s.CheckBookMemberRole(c, goal.Uid, operatorUid, models.BOOK_MEMBER_ROLE_EDITOR)
+
+	if err != nil {
+		return err
+	}
+
+	goal.UpdatedUnixTime = time.Now().Unix()
+
+	return s.UserDataDB(goal.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		err := s.setGoalCurrency(sess, goal)
+
+		if err != nil {
+			return err
+		}
+
+		updatedRows, err := sess.ID(goal.GoalId).Cols("name", "currency", "target_amount", "target_date", "account_ids", "comment", "updated_unix_time").Where("uid=? AND deleted=?", goal.Uid, false).Update(goal)
+
+		if err != nil {
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrSavingsGoalNotFound
+		}
+
+		return err
+	})
+}
+
+// DeleteGoal deletes an existed savings goal from database
+func (s *SavingsGoalService) DeleteGoal(c *core.Context, uid int64, operatorUid int64, goalId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	err := s.CheckBookMemberRole(c, uid, operatorUid, models.BOOK_MEMBER_ROLE_EDITOR)
+
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.SavingsGoal{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		deletedRows, err := sess.ID(goalId).Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrSavingsGoalNotFound
+		}
+
+		return err
+	})
+}
+
+// DeleteAllGoals deletes all existed savings goals from database
+func (s *SavingsGoalService) DeleteAllGoals(c *core.Context, uid int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.SavingsGoal{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+		return err
+	})
+}
+
+// GetContributionAmount returns the net amount transferred from other accounts into the specified accounts between the start and end unix time,
+// the transfers between the specified accounts are not counted
+func (s *SavingsGoalService) GetContributionAmount(c *core.Context, uid int64, accountIds []int64, startUnixTime int64, endUnixTime int64) (int64, error) {
+	if uid <= 0 {
+		return 0, errs.ErrUserIdInvalid
+	}
+
+	if len(accountIds) < 1 {
+		return 0, nil
+	}
+
+	var transactions []*models.Transaction
+	err := s.UserDataDB(uid).NewSession(c).Select("type, amount, related_account_id").Where("uid=? AND deleted=? AND (type=? OR type=?) AND transaction_time>=? AND transaction_time<=?", uid, false, models.TRANSACTION_DB_TYPE_TRANSFER_IN, models.TRANSACTION_DB_TYPE_TRANSFER_OUT, utils.GetMinTransactionTimeFromUnixTime(startUnixTime), utils.GetMaxTransactionTimeFromUnixTime(endUnixTime)).In("account_id", accountIds).Find(&transactions)
+
+	if err != nil {
+		return 0, err
+	}
+
+	linkedAccountIds := make(map[int64]bool, len(accountIds))
+
+	for i := 0; i < len(accountIds); i++ {
+		linkedAccountIds[accountIds[i]] = true
+	}
+
+	contributionAmount := int64(0)
+
+	for i := 0; i < len(transactions); i++ {
+		transaction := transactions[i]
+
+		if linkedAccountIds[transaction.RelatedAccountId] {
+			continue
+		}
+
+		if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+			contributionAmount += transaction.Amount
+		} else {
+			contributionAmount -= transaction.Amount
+		}
+	}
+
+	return contributionAmount, nil
+}
+
+func (s *SavingsGoalService) setGoalCurrency(sess *xorm.Session, goal *models.SavingsGoal) error {
+	accountIds := goal.GetAccountIds()
+
+	if len(accountIds) < 1 {
+		return errs.ErrSavingsGoalAccountInvalid
+	}
+
+	var accounts []*models.Account
+	err := sess.Where("uid=? AND deleted=?", goal.Uid, false).In("account_id", accountIds).Find(&accounts)
+
+	if err != nil {
+		return err
+	}
+
+	if len(accounts) != len(accountIds) {
+		return errs.ErrSavingsGoalAccountInvalid
+	}
+
+	for i := 0; i < len(accounts); i++ {
+		account := accounts[i]
+
+		if account.Type != models.ACCOUNT_TYPE_SINGLE_ACCOUNT {
+			return errs.ErrSavingsGoalAccountInvalid
+		}
+
+		if i > 0 && account.Currency != accounts[0].Currency {
+			return errs.ErrSavingsGoalAccountCurrencyNotEqual
+		}
+	}
+
+	goal.Currency = accounts[0].Currency
+
+	return nil
+}
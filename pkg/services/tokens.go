@@ -63,6 +63,20 @@ func (s *TokenService) GetAllUnexpiredNormalTokensByUid(c *core.Context, uid int
 	return tokenRecords, err
 }
 
+// GetAllUnexpiredPersonalAccessTokensByUid returns all available personal access token models of given user
+func (s *TokenService) GetAllUnexpiredPersonalAccessTokensByUid(c *core.Context, uid int64) ([]*models.TokenRecord, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	var tokenRecords []*models.TokenRecord
+	err := s.TokenDB(uid).NewSession(c).Cols("uid", "user_token_id", "token_type", "user_agent", "name", "scopes", "created_unix_time", "expired_unix_time").Where("uid=? AND token_type=? AND expired_unix_time>?", uid, core.USER_TOKEN_TYPE_PERSONAL_ACCESS, now).Find(&tokenRecords)
+
+	return tokenRecords, err
+}
+
 // ParseTokenByHeader returns the token model according to request data
 func (s *TokenService) ParseTokenByHeader(c *core.Context) (*jwt.Token, *core.UserTokenClaims, error) {
 	return s.parseToken(c, request.BearerExtractor{})
@@ -98,6 +112,37 @@ func (s *TokenService) CreatePasswordResetToken(c *core.Context, user *models.Us
 	return s.createToken(c, user, core.USER_TOKEN_TYPE_PASSWORD_RESET, s.getUserAgent(c), s.CurrentConfig().PasswordResetTokenExpiredTimeDuration)
 }
 
+// CreatePersonalAccessToken generates a new personal access token with specified name and scopes and saves to database
+func (s *TokenService) CreatePersonalAccessToken(c *core.Context, user *models.User, name string, scopes []string, expiryDate time.Duration) (string, *models.TokenRecord, error) {
+	for i := 0; i < len(scopes); i++ {
+		if !models.ALL_TOKEN_SCOPES[scopes[i]] {
+			return "", nil, errs.ErrTokenScopeInvalid
+		}
+	}
+
+	now := time.Now()
+
+	tokenRecord := &models.TokenRecord{
+		Uid:             user.Uid,
+		UserTokenId:     s.getUserTokenId(),
+		TokenType:       core.USER_TOKEN_TYPE_PERSONAL_ACCESS,
+		UserAgent:       s.getUserAgent(c),
+		Name:            name,
+		CreatedUnixTime: now.Unix(),
+		ExpiredUnixTime: now.Add(expiryDate).Unix(),
+	}
+
+	tokenRecord.SetScopes(scopes)
+
+	tokenString, _, err := s.createTokenByRecord(c, user, tokenRecord)
+
+	if err != nil {
+		return "", nil, err
+	}
+
+	return tokenString, tokenRecord, nil
+}
+
 // DeleteToken deletes given token from database
 func (s *TokenService) DeleteToken(c *core.Context, tokenRecord *models.TokenRecord) error {
 	if tokenRecord.Uid <= 0 {
@@ -279,7 +324,6 @@ func (s *TokenService) parseToken(c *core.Context, extractor request.Extractor)
 }
 
 func (s *TokenService) createToken(c *core.Context, user *models.User, tokenType core.TokenType, userAgent string, expiryDate time.Duration) (string, *core.UserTokenClaims, error) {
-	var err error
 	now := time.Now()
 
 	tokenRecord := &models.TokenRecord{
@@ -291,6 +335,12 @@ func (s *TokenService) createToken(c *core.Context, user *models.User, tokenType
 		ExpiredUnixTime: now.Add(expiryDate).Unix(),
 	}
 
+	return s.createTokenByRecord(c, user, tokenRecord)
+}
+
+func (s *TokenService) createTokenByRecord(c *core.Context, user *models.User, tokenRecord *models.TokenRecord) (string, *core.UserTokenClaims, error) {
+	var err error
+
 	if tokenRecord.Secret, err = utils.GetRandomString(10); err != nil {
 		return "", nil, err
 	}
@@ -300,6 +350,7 @@ func (s *TokenService) createToken(c *core.Context, user *models.User, tokenType
 		Uid:         tokenRecord.Uid,
 		Username:    user.Username,
 		Type:        tokenRecord.TokenType,
+		Scopes:      tokenRecord.GetScopes(),
 		IssuedAt:    tokenRecord.CreatedUnixTime,
 		ExpiresAt:   tokenRecord.ExpiredUnixTime,
 	}
@@ -0,0 +1,225 @@
+package services
+
+import (
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/f97/gofire/pkg/core"
+	"github.com/f97/gofire/pkg/datastore"
+	"github.com/f97/gofire/pkg/errs"
+	"github.com/f97/gofire/pkg/log"
+	"github.com/f97/gofire/pkg/models"
+	"github.com/f97/gofire/pkg/storage"
+	"github.com/f97/gofire/pkg/uuid"
+)
+
+const maxTransactionAttachmentCount = 10
+
+// TransactionAttachmentService represents transaction attachment service
+type TransactionAttachmentService struct {
+	ServiceUsingDB
+	ServiceUsingUuid
+	storage *storage.ObjectStorageContainer
+}
+
+// Initialize a transaction attachment service singleton instance
+var (
+	TransactionAttachments = &TransactionAttachmentService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+		ServiceUsingUuid: ServiceUsingUuid{
+			container: uuid.Container,
+		},
+		storage: storage.Container,
+	}
+)
+
+// GetAllAttachmentsByUid returns all transaction attachment models of user
+func (s *TransactionAttachmentService) GetAllAttachmentsByUid(c *core.Context, uid int64) ([]*models.TransactionAttachment, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	var attachments []*models.TransactionAttachment
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false).OrderBy("created_unix_time asc").Find(&attachments)
+
+	return attachments, err
+}
+
+// GetAttachmentsByTransactionId returns all attachment models of the specified transaction
+func (s *TransactionAttachmentService) GetAttachmentsByTransactionId(c *core.Context, uid int64, transactionId int64) ([]*models.TransactionAttachment, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if transactionId <= 0 {
+		return nil, errs.ErrTransactionIdInvalid
+	}
+
+	var attachments []*models.TransactionAttachment
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=? AND transaction_id=?", uid, false, transactionId).OrderBy("created_unix_time asc").Find(&attachments)
+
+	return attachments, err
+}
+
+// GetAttachmentByAttachmentId returns a transaction attachment model according to attachment id
+func (s *TransactionAttachmentService) GetAttachmentByAttachmentId(c *core.Context, uid int64, attachmentId int64) (*models.TransactionAttachment, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if attachmentId <= 0 {
+		return nil, errs.ErrTransactionAttachmentIdInvalid
+	}
+
+	attachment := &models.TransactionAttachment{}
+	has, err := s.UserDataDB(uid).NewSession(c).ID(attachmentId).Where("uid=? AND deleted=?", uid, false).Get(attachment)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrTransactionAttachmentNotFound
+	}
+
+	return attachment, nil
+}
+
+// GetAttachmentContent returns the file content of the specified transaction attachment from object storage
+func (s *TransactionAttachmentService) GetAttachmentContent(c *core.Context, attachment *models.TransactionAttachment) ([]byte, error) {
+	data, err := s.storage.ReadTransactionAttachment(attachment.Uid, attachment.AttachmentId)
+
+	if err == errs.ErrStorageObjectNotFound {
+		return nil, errs.ErrTransactionAttachmentNotFound
+	}
+
+	return data, err
+}
+
+// CreateAttachment saves the file content to object storage and saves a new transaction attachment model to database
+func (s *TransactionAttachmentService) CreateAttachment(c *core.Context, operatorUid int64, attachment *models.TransactionAttachment, data []byte) error {
+	if attachment.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	err := s.CheckBookMemberRole(c, attachment.Uid, operatorUid, models.BOOK_MEMBER_ROLE_EDITOR)
+
+	if err != nil {
+		return err
+	}
+
+	if len(data) < 1 {
+		return errs.ErrTransactionAttachmentFileIsEmpty
+	}
+
+	now := time.Now().Unix()
+
+	attachment.AttachmentId = s.GenerateUuid(uuid.UUID_TYPE_ATTACHMENT)
+	attachment.Deleted = false
+	attachment.FileSize = int64(len(data))
+	attachment.CreatedUnixTime = now
+	attachment.UpdatedUnixTime = now
+
+	err = s.storage.SaveTransactionAttachment(attachment.Uid, attachment.AttachmentId, data)
+
+	if err != nil {
+		return err
+	}
+
+	err = s.UserDataDB(attachment.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		transaction := &models.Transaction{}
+		has, err := sess.ID(attachment.TransactionId).Where("uid=? AND deleted=?", attachment.Uid, false).Get(transaction)
+
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrTransactionNotFound
+		}
+
+		if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+			attachment.TransactionId = transaction.RelatedId
+		}
+
+		attachmentCount, err := sess.Where("uid=? AND deleted=? AND transaction_id=?", attachment.Uid, false, attachment.TransactionId).Count(&models.TransactionAttachment{})
+
+		if err != nil {
+			return err
+		} else if attachmentCount >= maxTransactionAttachmentCount {
+			return errs.ErrTransactionAttachmentTooMuch
+		}
+
+		_, err = sess.Insert(attachment)
+
+		return err
+	})
+
+	if err != nil {
+		deleteTransactionAttachmentObjects(c, []*models.TransactionAttachment{attachment})
+		return err
+	}
+
+	return nil
+}
+
+// DeleteAttachment deletes an existed transaction attachment from database and object storage
+func (s *TransactionAttachmentService) DeleteAttachment(c *core.Context, uid int64, operatorUid int64, attachmentId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	err := s.CheckBookMemberRole(c, uid, operatorUid, models.BOOK_MEMBER_ROLE_EDITOR)
+
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.TransactionAttachment{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	attachment := &models.TransactionAttachment{}
+
+	err = s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		has, err := sess.ID(attachmentId).Where("uid=? AND deleted=?", uid, false).Get(attachment)
+
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrTransactionAttachmentNotFound
+		}
+
+		deletedRows, err := sess.ID(attachmentId).Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrTransactionAttachmentNotFound
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	deleteTransactionAttachmentObjects(c, []*models.TransactionAttachment{attachment})
+
+	return nil
+}
+
+// deleteTransactionAttachmentObjects deletes the file contents of transaction attachments from object storage, the failure would only be logged
+func deleteTransactionAttachmentObjects(c *core.Context, attachments []*models.TransactionAttachment) {
+	for i := 0; i < len(attachments); i++ {
+		attachment := attachments[i]
+		err := storage.Container.DeleteTransactionAttachment(attachment.Uid, attachment.AttachmentId)
+
+		if err != nil {
+			log.WarnfWithRequestId(c, "[transaction_attachments.deleteTransactionAttachmentObjects] failed to delete attachment \"id:%d\" of user \"uid:%d\" from object storage, because %s", attachment.AttachmentId, attachment.Uid, err.Error())
+		}
+	}
+}
@@ -156,11 +156,17 @@ func (s *TransactionCategoryService) GetMaxSubCategoryDisplayOrder(c *core.Conte
 }
 
 // CreateCategory saves a new transaction category model to database
-func (s *TransactionCategoryService) CreateCategory(c *core.Context, category *models.TransactionCategory) error {
+func (s *TransactionCategoryService) CreateCategory(c *core.Context, operatorUid int64, category *models.TransactionCategory) error {
 	if category.Uid <= 0 {
 		return errs.ErrUserIdInvalid
 	}
 
+	err := s.CheckBookMemberRole(c, category.Uid, operatorUid, models.BOOK_MEMBER_ROLE_EDITOR)
+
+	if err != nil {
+		return err
+	}
+
 	category.CategoryId = s.GenerateUuid(uuid.UUID_TYPE_CATEGORY)
 
 	category.Deleted = false
@@ -169,16 +175,27 @@ func (s *TransactionCategoryService) CreateCategory(c *core.Context, category *m
 
 	return s.UserDataDB(category.Uid).DoTransaction(c, func(sess *xorm.Session) error {
 		_, err := sess.Insert(category)
-		return err
+
+		if err != nil {
+			return err
+		}
+
+		return DataRevisions.insertRevision(c, sess, category.Uid, models.DATA_REVISION_OBJECT_TYPE_CATEGORY, category.CategoryId, models.DATA_REVISION_ACTION_CREATE, nil, getCategoryRevisionFields(category))
 	})
 }
 
 // CreateCategories saves a few transaction category models to database
-func (s *TransactionCategoryService) CreateCategories(c *core.Context, uid int64, categories map[*models.TransactionCategory][]*models.TransactionCategory) ([]*models.TransactionCategory, error) {
+func (s *TransactionCategoryService) CreateCategories(c *core.Context, uid int64, operatorUid int64, categories map[*models.TransactionCategory][]*models.TransactionCategory) ([]*models.TransactionCategory, error) {
 	if uid <= 0 {
 		return nil, errs.ErrUserIdInvalid
 	}
 
+	err := s.CheckBookMemberRole(c, uid, operatorUid, models.BOOK_MEMBER_ROLE_EDITOR)
+
+	if err != nil {
+		return nil, err
+	}
+
 	var allCategories []*models.TransactionCategory
 	primaryCategories := categories[nil]
 
@@ -207,7 +224,7 @@ func (s *TransactionCategoryService) CreateCategories(c *core.Context, uid int64
 		}
 	}
 
-	err := s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+	err = s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
 		for i := 0; i < len(allCategories); i++ {
 			category := allCategories[i]
 			_, err := sess.Insert(category)
@@ -215,6 +232,12 @@ func (s *TransactionCategoryService) CreateCategories(c *core.Context, uid int64
 			if err != nil {
 				return err
 			}
+
+			err = DataRevisions.insertRevision(c, sess, uid, models.DATA_REVISION_OBJECT_TYPE_CATEGORY, category.CategoryId, models.DATA_REVISION_ACTION_CREATE, nil, getCategoryRevisionFields(category))
+
+			if err != nil {
+				return err
+			}
 		}
 
 		return nil
@@ -228,14 +251,29 @@ func (s *TransactionCategoryService) CreateCategories(c *core.Context, uid int64
 }
 
 // ModifyCategory saves an existed transaction category model to database
-func (s *TransactionCategoryService) ModifyCategory(c *core.Context, category *models.TransactionCategory) error {
+func (s *TransactionCategoryService) ModifyCategory(c *core.Context, operatorUid int64, category *models.TransactionCategory) error {
 	if category.Uid <= 0 {
 		return errs.ErrUserIdInvalid
 	}
 
+	err := s.CheckBookMemberRole(c, category.Uid, operatorUid, models.BOOK_MEMBER_ROLE_EDITOR)
+
+	if err != nil {
+		return err
+	}
+
 	category.UpdatedUnixTime = time.Now().Unix()
 
 	return s.UserDataDB(category.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		oldCategory := &models.TransactionCategory{}
+		has, err := sess.ID(category.CategoryId).Where("uid=? AND deleted=?", category.Uid, false).Get(oldCategory)
+
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrTransactionCategoryNotFound
+		}
+
 		updatedRows, err := sess.ID(category.CategoryId).Cols("name", "icon", "color", "comment", "hidden", "updated_unix_time").Where("uid=? AND deleted=?", category.Uid, false).Update(category)
 
 		if err != nil {
@@ -244,16 +282,29 @@ func (s *TransactionCategoryService) ModifyCategory(c *core.Context, category *m
 			return errs.ErrTransactionCategoryNotFound
 		}
 
-		return nil
+		newCategory := *oldCategory
+		newCategory.Name = category.Name
+		newCategory.Icon = category.Icon
+		newCategory.Color = category.Color
+		newCategory.Comment = category.Comment
+		newCategory.Hidden = category.Hidden
+
+		return DataRevisions.insertRevision(c, sess, category.Uid, models.DATA_REVISION_OBJECT_TYPE_CATEGORY, category.CategoryId, models.DATA_REVISION_ACTION_MODIFY, getCategoryRevisionFields(oldCategory), getCategoryRevisionFields(&newCategory))
 	})
 }
 
 // HideCategory updates hidden field of given transaction categories
-func (s *TransactionCategoryService) HideCategory(c *core.Context, uid int64, ids []int64, hidden bool) error {
+func (s *TransactionCategoryService) HideCategory(c *core.Context, uid int64, operatorUid int64, ids []int64, hidden bool) error {
 	if uid <= 0 {
 		return errs.ErrUserIdInvalid
 	}
 
+	err := s.CheckBookMemberRole(c, uid, operatorUid, models.BOOK_MEMBER_ROLE_EDITOR)
+
+	if err != nil {
+		return err
+	}
+
 	now := time.Now().Unix()
 
 	updateModel := &models.TransactionCategory{
@@ -262,6 +313,13 @@ func (s *TransactionCategoryService) HideCategory(c *core.Context, uid int64, id
 	}
 
 	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		var oldCategories []*models.TransactionCategory
+		err := sess.Where("uid=? AND deleted=?", uid, false).In("category_id", ids).Find(&oldCategories)
+
+		if err != nil {
+			return err
+		}
+
 		updatedRows, err := sess.Cols("hidden", "updated_unix_time").Where("uid=? AND deleted=?", uid, false).In("category_id", ids).Update(updateModel)
 
 		if err != nil {
@@ -270,16 +328,34 @@ func (s *TransactionCategoryService) HideCategory(c *core.Context, uid int64, id
 			return errs.ErrTransactionCategoryNotFound
 		}
 
+		for i := 0; i < len(oldCategories); i++ {
+			oldCategory := oldCategories[i]
+			newCategory := *oldCategory
+			newCategory.Hidden = hidden
+
+			err = DataRevisions.insertRevision(c, sess, uid, models.DATA_REVISION_OBJECT_TYPE_CATEGORY, oldCategory.CategoryId, models.DATA_REVISION_ACTION_MODIFY, getCategoryRevisionFields(oldCategory), getCategoryRevisionFields(&newCategory))
+
+			if err != nil {
+				return err
+			}
+		}
+
 		return nil
 	})
 }
 
 // ModifyCategoryDisplayOrders updates display order of given transaction categories
-func (s *TransactionCategoryService) ModifyCategoryDisplayOrders(c *core.Context, uid int64, categories []*models.TransactionCategory) error {
+func (s *TransactionCategoryService) ModifyCategoryDisplayOrders(c *core.Context, uid int64, operatorUid int64, categories []*models.TransactionCategory) error {
 	if uid <= 0 {
 		return errs.ErrUserIdInvalid
 	}
 
+	err := s.CheckBookMemberRole(c, uid, operatorUid, models.BOOK_MEMBER_ROLE_EDITOR)
+
+	if err != nil {
+		return err
+	}
+
 	for i := 0; i < len(categories); i++ {
 		categories[i].UpdatedUnixTime = time.Now().Unix()
 	}
@@ -301,11 +377,17 @@ func (s *TransactionCategoryService) ModifyCategoryDisplayOrders(c *core.Context
 }
 
 // DeleteCategory deletes an existed transaction category from database
-func (s *TransactionCategoryService) DeleteCategory(c *core.Context, uid int64, categoryId int64) error {
+func (s *TransactionCategoryService) DeleteCategory(c *core.Context, uid int64, operatorUid int64, categoryId int64) error {
 	if uid <= 0 {
 		return errs.ErrUserIdInvalid
 	}
 
+	err := s.CheckBookMemberRole(c, uid, operatorUid, models.BOOK_MEMBER_ROLE_EDITOR)
+
+	if err != nil {
+		return err
+	}
+
 	now := time.Now().Unix()
 
 	updateModel := &models.TransactionCategory{
@@ -345,6 +427,15 @@ func (s *TransactionCategoryService) DeleteCategory(c *core.Context, uid int64,
 			return errs.ErrTransactionCategoryNotFound
 		}
 
+		for i := 0; i < len(categoryAndSubCategories); i++ {
+			category := categoryAndSubCategories[i]
+			err = DataRevisions.insertRevision(c, sess, uid, models.DATA_REVISION_OBJECT_TYPE_CATEGORY, category.CategoryId, models.DATA_REVISION_ACTION_DELETE, getCategoryRevisionFields(category), nil)
+
+			if err != nil {
+				return err
+			}
+		}
+
 		return err
 	})
 }
@@ -0,0 +1,912 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/f97/gofire/pkg/core"
+	"github.com/f97/gofire/pkg/datastore"
+	"github.com/f97/gofire/pkg/errs"
+	"github.com/f97/gofire/pkg/models"
+	"github.com/f97/gofire/pkg/utils"
+	"github.com/f97/gofire/pkg/uuid"
+)
+
+const defaultImportedItemIcon = 1
+const defaultImportedItemColor = "000000"
+
+// TransactionImportService represents transaction import service
+type TransactionImportService struct {
+	ServiceUsingDB
+	ServiceUsingUuid
+	transactions *TransactionService
+}
+
+// Initialize a transaction import service singleton instance
+var (
+	TransactionImports = &TransactionImportService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+		ServiceUsingUuid: ServiceUsingUuid{
+			container: uuid.Container,
+		},
+		transactions: Transactions,
+	}
+)
+
+// transactionImportContext represents the existed and new created data of user during importing
+type transactionImportContext struct {
+	user                 *models.User
+	operatorUid          int64
+	createMissing        bool
+	now                  int64
+	accounts             map[string]*models.Account
+	primaryCategories    map[models.TransactionCategoryType]map[string]*models.TransactionCategory
+	secondaryCategories  map[int64]map[string]*models.TransactionCategory
+	tags                 map[string]*models.TransactionTag
+	accountUsed          map[int64]bool
+	accountBalances      map[int64]int64
+	maxAccountOrder      int32
+	maxCategoryOrders    map[string]int32
+	maxTagOrder          int32
+	newAccounts          []*models.Account
+	newCategories        []*models.TransactionCategory
+	newTags              []*models.TransactionTag
+	missingAccountNames  map[string]bool
+	missingCategoryNames map[string]bool
+	missingTagNames      map[string]bool
+	result               *models.DataImportResponse
+}
+
+// ImportTransactions saves the imported transactions to database, the accounts, categories and tags are found by name and would be created if they do not exist and createMissing is true
+func (s *TransactionImportService) ImportTransactions(c *core.Context, user *models.User, operatorUid int64, importTransactions []*models.ImportTransaction, createMissing bool, dryRun bool, clientIp string) (*models.DataImportResponse, error) {
+	if user.Uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	err := s.CheckBookMemberRole(c, user.Uid, operatorUid, models.BOOK_MEMBER_ROLE_EDITOR)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(importTransactions) < 1 {
+		return nil, errs.ErrImportFileIsEmpty
+	}
+
+	sort.SliceStable(importTransactions, func(i, j int) bool {
+		return importTransactions[i].TransactionUnixTime < importTransactions[j].TransactionUnixTime
+	})
+
+	var result *models.DataImportResponse
+
+	err = s.UserDataDB(user.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		ctx, err := s.newTransactionImportContext(sess, user, createMissing, dryRun)
+
+		if err != nil {
+			return err
+		}
+
+		ctx.operatorUid = operatorUid
+		result = ctx.result
+		result.TotalCount = len(importTransactions)
+		transactions := make([]*models.Transaction, len(importTransactions))
+		allTagIds := make([][]int64, len(importTransactions))
+
+		rules, err := getAvailableTransactionRules(sess, user.Uid)
+
+		if err != nil {
+			return err
+		}
+
+		for i := 0; i < len(importTransactions); i++ {
+			transactions[i], allTagIds[i], err = s.toTransaction(ctx, importTransactions[i], clientIp)
+
+			if err != nil {
+				return err
+			}
+
+			allTagIds[i], _ = applyTransactionRules(rules, transactions[i], allTagIds[i], false)
+		}
+
+		if len(ctx.missingAccountNames) > 0 || len(ctx.missingCategoryNames) > 0 || len(ctx.missingTagNames) > 0 {
+			result.MissingAccountNames = s.getSortedNames(ctx.missingAccountNames)
+			result.MissingCategoryNames = s.getSortedNames(ctx.missingCategoryNames)
+			result.MissingTagNames = s.getSortedNames(ctx.missingTagNames)
+
+			if !dryRun {
+				if len(ctx.missingAccountNames) > 0 {
+					return errs.NewErrorWithContext(errs.ErrImportedAccountNotFound, result.MissingAccountNames)
+				} else if len(ctx.missingCategoryNames) > 0 {
+					return errs.NewErrorWithContext(errs.ErrImportedCategoryNotFound, result.MissingCategoryNames)
+				} else {
+					return errs.NewErrorWithContext(errs.ErrImportedTagNotFound, result.MissingTagNames)
+				}
+			}
+
+			return nil
+		}
+
+		err = s.setTransactionTimes(sess, ctx, importTransactions, transactions)
+
+		if err != nil {
+			return err
+		}
+
+		for i := 0; i < len(transactions); i++ {
+			err = s.updateAccountBalance(ctx, importTransactions[i], transactions[i])
+
+			if err != nil {
+				return err
+			}
+		}
+
+		result.ImportedCount = len(transactions)
+
+		if dryRun {
+			return nil
+		}
+
+		return s.saveImportedData(c, sess, ctx, transactions, allTagIds)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ImportStatementTransactions saves the transactions imported from bank statement to specified account, the transactions whose external id have been imported to this account are skipped
+func (s *TransactionImportService) ImportStatementTransactions(c *core.Context, user *models.User, operatorUid int64, accountId int64, incomeCategoryId int64, expenseCategoryId int64, importTransactions []*models.ImportTransaction, dryRun bool, clientIp string) (*models.DataImportResponse, error) {
+	if user.Uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	err := s.CheckBookMemberRole(c, user.Uid, operatorUid, models.BOOK_MEMBER_ROLE_EDITOR)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(importTransactions) < 1 {
+		return nil, errs.ErrImportFileIsEmpty
+	}
+
+	sort.SliceStable(importTransactions, func(i, j int) bool {
+		return importTransactions[i].TransactionUnixTime < importTransactions[j].TransactionUnixTime
+	})
+
+	result := &models.DataImportResponse{
+		DryRun:           dryRun,
+		TotalCount:       len(importTransactions),
+		NewAccountNames:  []string{},
+		NewCategoryNames: []string{},
+		NewTagNames:      []string{},
+		Conflicts:        []*models.DataImportConflictResponse{},
+	}
+
+	err = s.UserDataDB(user.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		account := &models.Account{}
+		has, err := sess.ID(accountId).Where("uid=? AND deleted=?", user.Uid, false).Get(account)
+
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrAccountNotFound
+		}
+
+		if account.Type == models.ACCOUNT_TYPE_MULTI_SUB_ACCOUNTS {
+			return errs.ErrAccountTypeInvalid
+		}
+
+		if account.Hidden {
+			return errs.ErrCannotAddTransactionToHiddenAccount
+		}
+
+		var existedExternalIndexes []*models.TransactionExternalIndex
+		err = sess.Cols("external_id").Where("uid=? AND deleted=? AND account_id=?", user.Uid, false, account.AccountId).Find(&existedExternalIndexes)
+
+		if err != nil {
+			return err
+		}
+
+		existedExternalIds := make(map[string]bool, len(existedExternalIndexes))
+
+		for i := 0; i < len(existedExternalIndexes); i++ {
+			existedExternalIds[existedExternalIndexes[i].ExternalId] = true
+		}
+
+		ctx := &transactionImportContext{
+			user:            user,
+			operatorUid:     operatorUid,
+			now:             time.Now().Unix(),
+			accountUsed:     make(map[int64]bool),
+			accountBalances: make(map[int64]int64),
+			result:          result,
+		}
+
+		rules, err := getAvailableTransactionRules(sess, user.Uid)
+
+		if err != nil {
+			return err
+		}
+
+		newImportTransactions := make([]*models.ImportTransaction, 0, len(importTransactions))
+		transactions := make([]*models.Transaction, 0, len(importTransactions))
+		allTagIds := make([][]int64, 0, len(importTransactions))
+
+		for i := 0; i < len(importTransactions); i++ {
+			importTransaction := importTransactions[i]
+
+			if importTransaction.AccountCurrency != "" && importTransaction.AccountCurrency != account.Currency {
+				return errs.NewErrorWithContext(errs.ErrImportFileCurrencyNotMatchAccount, s.getErrorContext(importTransaction))
+			}
+
+			if existedExternalIds[importTransaction.ExternalId] {
+				result.SkippedCount++
+				continue
+			}
+
+			existedExternalIds[importTransaction.ExternalId] = true
+
+			transaction := &models.Transaction{
+				Uid:               user.Uid,
+				Type:              importTransaction.Type,
+				TimezoneUtcOffset: importTransaction.TimezoneUtcOffset,
+				AccountId:         account.AccountId,
+				Amount:            importTransaction.Amount,
+				Comment:           importTransaction.Comment,
+				CreatedIp:         clientIp,
+				CreatedUnixTime:   ctx.now,
+				UpdatedUnixTime:   ctx.now,
+			}
+
+			if importTransaction.Type != models.TRANSACTION_DB_TYPE_INCOME && importTransaction.Type != models.TRANSACTION_DB_TYPE_EXPENSE {
+				return errs.NewErrorWithContext(errs.ErrImportedTransactionTypeInvalid, s.getErrorContext(importTransaction))
+			}
+
+			tagIds, _ := applyTransactionRules(rules, transaction, nil, false)
+
+			if transaction.CategoryId == 0 && importTransaction.Type == models.TRANSACTION_DB_TYPE_INCOME {
+				transaction.CategoryId = incomeCategoryId
+			} else if transaction.CategoryId == 0 && importTransaction.Type == models.TRANSACTION_DB_TYPE_EXPENSE {
+				transaction.CategoryId = expenseCategoryId
+			}
+
+			err = s.transactions.isCategoryValid(sess, transaction)
+
+			if err != nil {
+				return err
+			}
+
+			newImportTransactions = append(newImportTransactions, importTransaction)
+			transactions = append(transactions, transaction)
+			allTagIds = append(allTagIds, tagIds)
+		}
+
+		if len(transactions) < 1 {
+			return nil
+		}
+
+		err = s.setTransactionTimes(sess, ctx, newImportTransactions, transactions)
+
+		if err != nil {
+			return err
+		}
+
+		result.ImportedCount = len(transactions)
+
+		if dryRun {
+			return nil
+		}
+
+		err = s.saveImportedData(c, sess, ctx, transactions, allTagIds)
+
+		if err != nil {
+			return err
+		}
+
+		for i := 0; i < len(transactions); i++ {
+			externalIndex := &models.TransactionExternalIndex{
+				ExternalIndexId: s.GenerateUuid(uuid.UUID_TYPE_EXTERNAL_INDEX),
+				Uid:             user.Uid,
+				Deleted:         false,
+				AccountId:       account.AccountId,
+				ExternalId:      newImportTransactions[i].ExternalId,
+				TransactionId:   transactions[i].TransactionId,
+				CreatedUnixTime: ctx.now,
+				UpdatedUnixTime: ctx.now,
+			}
+
+			_, err = sess.Insert(externalIndex)
+
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (s *TransactionImportService) newTransactionImportContext(sess *xorm.Session, user *models.User, createMissing bool, dryRun bool) (*transactionImportContext, error) {
+	var accounts []*models.Account
+	err := sess.Where("uid=? AND deleted=?", user.Uid, false).OrderBy("parent_account_id asc, display_order asc").Find(&accounts)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var categories []*models.TransactionCategory
+	err = sess.Where("uid=? AND deleted=?", user.Uid, false).OrderBy("type asc, parent_category_id asc, display_order asc").Find(&categories)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var tags []*models.TransactionTag
+	err = sess.Where("uid=? AND deleted=?", user.Uid, false).OrderBy("display_order asc").Find(&tags)
+
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := &transactionImportContext{
+		user:                 user,
+		createMissing:        createMissing,
+		now:                  time.Now().Unix(),
+		accounts:             make(map[string]*models.Account),
+		primaryCategories:    make(map[models.TransactionCategoryType]map[string]*models.TransactionCategory),
+		secondaryCategories:  make(map[int64]map[string]*models.TransactionCategory),
+		tags:                 make(map[string]*models.TransactionTag),
+		accountUsed:          make(map[int64]bool),
+		accountBalances:      make(map[int64]int64),
+		maxCategoryOrders:    make(map[string]int32),
+		missingAccountNames:  make(map[string]bool),
+		missingCategoryNames: make(map[string]bool),
+		missingTagNames:      make(map[string]bool),
+		result: &models.DataImportResponse{
+			DryRun:           dryRun,
+			NewAccountNames:  []string{},
+			NewCategoryNames: []string{},
+			NewTagNames:      []string{},
+			Conflicts:        []*models.DataImportConflictResponse{},
+		},
+	}
+
+	for i := 0; i < len(accounts); i++ {
+		account := accounts[i]
+		ctx.accountBalances[account.AccountId] = account.Balance
+
+		if account.ParentAccountId == models.LevelOneAccountParentId && account.DisplayOrder > ctx.maxAccountOrder {
+			ctx.maxAccountOrder = account.DisplayOrder
+		}
+
+		if account.Type == models.ACCOUNT_TYPE_MULTI_SUB_ACCOUNTS {
+			continue
+		}
+
+		if _, exists := ctx.accounts[account.Name]; !exists {
+			ctx.accounts[account.Name] = account
+		}
+	}
+
+	for i := 0; i < len(categories); i++ {
+		category := categories[i]
+		orderKey := s.getCategoryOrderKey(category.Type, category.ParentCategoryId)
+
+		if category.DisplayOrder > ctx.maxCategoryOrders[orderKey] {
+			ctx.maxCategoryOrders[orderKey] = category.DisplayOrder
+		}
+
+		if category.ParentCategoryId == models.LevelOneTransactionParentId {
+			if _, exists := ctx.primaryCategories[category.Type]; !exists {
+				ctx.primaryCategories[category.Type] = make(map[string]*models.TransactionCategory)
+			}
+
+			if _, exists := ctx.primaryCategories[category.Type][category.Name]; !exists {
+				ctx.primaryCategories[category.Type][category.Name] = category
+			}
+		} else {
+			if _, exists := ctx.secondaryCategories[category.ParentCategoryId]; !exists {
+				ctx.secondaryCategories[category.ParentCategoryId] = make(map[string]*models.TransactionCategory)
+			}
+
+			if _, exists := ctx.secondaryCategories[category.ParentCategoryId][category.Name]; !exists {
+				ctx.secondaryCategories[category.ParentCategoryId][category.Name] = category
+			}
+		}
+	}
+
+	for i := 0; i < len(tags); i++ {
+		tag := tags[i]
+		ctx.tags[tag.Name] = tag
+
+		if tag.DisplayOrder > ctx.maxTagOrder {
+			ctx.maxTagOrder = tag.DisplayOrder
+		}
+	}
+
+	var usedAccounts []*models.Transaction
+	err = sess.Cols("account_id").Where("uid=? AND deleted=?", user.Uid, false).GroupBy("account_id").Find(&usedAccounts)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < len(usedAccounts); i++ {
+		ctx.accountUsed[usedAccounts[i].AccountId] = true
+	}
+
+	return ctx, nil
+}
+
+func (s *TransactionImportService) toTransaction(ctx *transactionImportContext, importTransaction *models.ImportTransaction, clientIp string) (*models.Transaction, []int64, error) {
+	transaction := &models.Transaction{
+		Uid:               ctx.user.Uid,
+		Type:              importTransaction.Type,
+		TimezoneUtcOffset: importTransaction.TimezoneUtcOffset,
+		Amount:            importTransaction.Amount,
+		Comment:           importTransaction.Comment,
+		CreatedIp:         clientIp,
+		CreatedUnixTime:   ctx.now,
+		UpdatedUnixTime:   ctx.now,
+	}
+
+	account := s.getOrCreateAccount(ctx, importTransaction.AccountName, importTransaction.AccountCurrency)
+
+	if account != nil {
+		if account.Hidden {
+			return nil, nil, errs.NewErrorWithContext(errs.ErrCannotAddTransactionToHiddenAccount, s.getErrorContext(importTransaction))
+		}
+
+		transaction.AccountId = account.AccountId
+	}
+
+	if importTransaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
+		relatedAccount := s.getOrCreateAccount(ctx, importTransaction.RelatedAccountName, importTransaction.RelatedAccountCurrency)
+
+		if relatedAccount != nil {
+			if relatedAccount.Hidden {
+				return nil, nil, errs.NewErrorWithContext(errs.ErrCannotAddTransactionToHiddenAccount, s.getErrorContext(importTransaction))
+			}
+
+			transaction.RelatedAccountId = relatedAccount.AccountId
+			transaction.RelatedAccountAmount = importTransaction.RelatedAccountAmount
+		}
+
+		if account != nil && relatedAccount != nil {
+			if account.AccountId == relatedAccount.AccountId {
+				return nil, nil, errs.NewErrorWithContext(errs.ErrTransactionSourceAndDestinationIdCannotBeEqual, s.getErrorContext(importTransaction))
+			}
+
+			if account.Currency == relatedAccount.Currency && transaction.Amount != transaction.RelatedAccountAmount {
+				return nil, nil, errs.NewErrorWithContext(errs.ErrTransactionSourceAndDestinationAmountNotEqual, s.getErrorContext(importTransaction))
+			}
+		}
+	}
+
+	if importTransaction.Type != models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
+		category := s.getOrCreateCategory(ctx, importTransaction)
+
+		if category != nil {
+			transaction.CategoryId = category.CategoryId
+		}
+	}
+
+	tagIds := make([]int64, 0, len(importTransaction.TagNames))
+
+	for i := 0; i < len(importTransaction.TagNames); i++ {
+		tag := s.getOrCreateTag(ctx, importTransaction.TagNames[i])
+
+		if tag != nil {
+			tagIds = append(tagIds, tag.TagId)
+		}
+	}
+
+	return transaction, utils.ToUniqueInt64Slice(tagIds), nil
+}
+
+func (s *TransactionImportService) getOrCreateAccount(ctx *transactionImportContext, name string, currency string) *models.Account {
+	if account, exists := ctx.accounts[name]; exists {
+		return account
+	}
+
+	if !ctx.createMissing {
+		ctx.missingAccountNames[name] = true
+		return nil
+	}
+
+	if currency == "" {
+		currency = ctx.user.DefaultCurrency
+	}
+
+	ctx.maxAccountOrder++
+
+	account := &models.Account{
+		AccountId:       s.GenerateUuid(uuid.UUID_TYPE_ACCOUNT),
+		Uid:             ctx.user.Uid,
+		Category:        models.ACCOUNT_CATEGORY_CASH,
+		Type:            models.ACCOUNT_TYPE_SINGLE_ACCOUNT,
+		ParentAccountId: models.LevelOneAccountParentId,
+		Name:            name,
+		DisplayOrder:    ctx.maxAccountOrder,
+		Icon:            defaultImportedItemIcon,
+		Color:           defaultImportedItemColor,
+		Currency:        currency,
+		CreatedUnixTime: ctx.now,
+		UpdatedUnixTime: ctx.now,
+	}
+
+	ctx.accounts[name] = account
+	ctx.newAccounts = append(ctx.newAccounts, account)
+	ctx.result.NewAccountNames = append(ctx.result.NewAccountNames, name)
+
+	return account
+}
+
+func (s *TransactionImportService) getOrCreateCategory(ctx *transactionImportContext, importTransaction *models.ImportTransaction) *models.TransactionCategory {
+	var categoryType models.TransactionCategoryType
+
+	if importTransaction.Type == models.TRANSACTION_DB_TYPE_INCOME {
+		categoryType = models.CATEGORY_TYPE_INCOME
+	} else if importTransaction.Type == models.TRANSACTION_DB_TYPE_EXPENSE {
+		categoryType = models.CATEGORY_TYPE_EXPENSE
+	} else {
+		categoryType = models.CATEGORY_TYPE_TRANSFER
+	}
+
+	primaryName := importTransaction.CategoryName
+
+	if primaryName == "" {
+		primaryName = importTransaction.SubCategoryName
+	}
+
+	primaryCategory := ctx.primaryCategories[categoryType][primaryName]
+
+	if primaryCategory != nil {
+		if category, exists := ctx.secondaryCategories[primaryCategory.CategoryId][importTransaction.SubCategoryName]; exists {
+			return category
+		}
+	}
+
+	if !ctx.createMissing {
+		ctx.missingCategoryNames[primaryName+"/"+importTransaction.SubCategoryName] = true
+		return nil
+	}
+
+	if primaryCategory == nil {
+		primaryCategory = s.createCategory(ctx, categoryType, models.LevelOneTransactionParentId, primaryName)
+
+		if _, exists := ctx.primaryCategories[categoryType]; !exists {
+			ctx.primaryCategories[categoryType] = make(map[string]*models.TransactionCategory)
+		}
+
+		ctx.primaryCategories[categoryType][primaryName] = primaryCategory
+	}
+
+	category := s.createCategory(ctx, categoryType, primaryCategory.CategoryId, importTransaction.SubCategoryName)
+
+	if _, exists := ctx.secondaryCategories[primaryCategory.CategoryId]; !exists {
+		ctx.secondaryCategories[primaryCategory.CategoryId] = make(map[string]*models.TransactionCategory)
+	}
+
+	ctx.secondaryCategories[primaryCategory.CategoryId][importTransaction.SubCategoryName] = category
+	ctx.result.NewCategoryNames = append(ctx.result.NewCategoryNames, primaryName+"/"+importTransaction.SubCategoryName)
+
+	return category
+}
+
+func (s *TransactionImportService) createCategory(ctx *transactionImportContext, categoryType models.TransactionCategoryType, parentCategoryId int64, name string) *models.TransactionCategory {
+	orderKey := s.getCategoryOrderKey(categoryType, parentCategoryId)
+	ctx.maxCategoryOrders[orderKey]++
+
+	category := &models.TransactionCategory{
+		CategoryId:       s.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:              ctx.user.Uid,
+		Type:             categoryType,
+		ParentCategoryId: parentCategoryId,
+		Name:             name,
+		DisplayOrder:     ctx.maxCategoryOrders[orderKey],
+		Icon:             defaultImportedItemIcon,
+		Color:            defaultImportedItemColor,
+		CreatedUnixTime:  ctx.now,
+		UpdatedUnixTime:  ctx.now,
+	}
+
+	ctx.newCategories = append(ctx.newCategories, category)
+
+	return category
+}
+
+func (s *TransactionImportService) getOrCreateTag(ctx *transactionImportContext, name string) *models.TransactionTag {
+	if tag, exists := ctx.tags[name]; exists {
+		return tag
+	}
+
+	if !ctx.createMissing {
+		ctx.missingTagNames[name] = true
+		return nil
+	}
+
+	ctx.maxTagOrder++
+
+	tag := &models.TransactionTag{
+		TagId:           s.GenerateUuid(uuid.UUID_TYPE_TAG),
+		Uid:             ctx.user.Uid,
+		Name:            name,
+		DisplayOrder:    ctx.maxTagOrder,
+		CreatedUnixTime: ctx.now,
+		UpdatedUnixTime: ctx.now,
+	}
+
+	ctx.tags[name] = tag
+	ctx.newTags = append(ctx.newTags, tag)
+	ctx.result.NewTagNames = append(ctx.result.NewTagNames, name)
+
+	return tag
+}
+
+// setTransactionTimes sets the transaction time of each imported transaction, the transaction which has the same time as existed transactions is placed after them and reported as conflict
+func (s *TransactionImportService) setTransactionTimes(sess *xorm.Session, ctx *transactionImportContext, importTransactions []*models.ImportTransaction, transactions []*models.Transaction) error {
+	minTransactionTime := utils.GetMinTransactionTimeFromUnixTime(importTransactions[0].TransactionUnixTime)
+	maxTransactionTime := utils.GetMaxTransactionTimeFromUnixTime(importTransactions[len(importTransactions)-1].TransactionUnixTime)
+
+	var existedTransactions []*models.Transaction
+	err := sess.Cols("transaction_id", "transaction_time").Where("uid=? AND transaction_time>=? AND transaction_time<=?", ctx.user.Uid, minTransactionTime, maxTransactionTime).Find(&existedTransactions)
+
+	if err != nil {
+		return err
+	}
+
+	existedTransactionIds := make(map[int64][]int64)
+	nextTransactionTimes := make(map[int64]int64)
+
+	for i := 0; i < len(existedTransactions); i++ {
+		existedTransaction := existedTransactions[i]
+		unixTime := utils.GetUnixTimeFromTransactionTime(existedTransaction.TransactionTime)
+		existedTransactionIds[unixTime] = append(existedTransactionIds[unixTime], existedTransaction.TransactionId)
+
+		if existedTransaction.TransactionTime >= nextTransactionTimes[unixTime] {
+			nextTransactionTimes[unixTime] = existedTransaction.TransactionTime + 1
+		}
+	}
+
+	for i := 0; i < len(transactions); i++ {
+		importTransaction := importTransactions[i]
+		transaction := transactions[i]
+		unixTime := importTransaction.TransactionUnixTime
+
+		if ids, exists := existedTransactionIds[unixTime]; exists {
+			ctx.result.Conflicts = append(ctx.result.Conflicts, &models.DataImportConflictResponse{
+				LineNumber:            importTransaction.LineNumber,
+				Time:                  unixTime,
+				ExistedTransactionIds: utils.Int64ArrayToStringArray(ids),
+			})
+		}
+
+		transactionTime, exists := nextTransactionTimes[unixTime]
+
+		if !exists {
+			transactionTime = utils.GetMinTransactionTimeFromUnixTime(unixTime)
+		}
+
+		transaction.TransactionTime = transactionTime
+		transactionTime++
+
+		if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
+			transactionTime++
+		}
+
+		if transactionTime-1 > utils.GetMaxTransactionTimeFromUnixTime(unixTime) {
+			return errs.NewErrorWithContext(errs.ErrTooMuchTransactionInOneSecond, s.getErrorContext(importTransaction))
+		}
+
+		nextTransactionTimes[unixTime] = transactionTime
+	}
+
+	return nil
+}
+
+func (s *TransactionImportService) updateAccountBalance(ctx *transactionImportContext, importTransaction *models.ImportTransaction, transaction *models.Transaction) error {
+	if transaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
+		if ctx.accountUsed[transaction.AccountId] {
+			return errs.NewErrorWithContext(errs.ErrBalanceModificationTransactionCannotAddWhenNotEmpty, s.getErrorContext(importTransaction))
+		}
+
+		transaction.RelatedAccountId = transaction.AccountId
+		transaction.RelatedAccountAmount = transaction.Amount - ctx.accountBalances[transaction.AccountId]
+		ctx.accountBalances[transaction.AccountId] += transaction.RelatedAccountAmount
+	} else if transaction.Type == models.TRANSACTION_DB_TYPE_INCOME {
+		ctx.accountBalances[transaction.AccountId] += transaction.Amount
+	} else if transaction.Type == models.TRANSACTION_DB_TYPE_EXPENSE {
+		ctx.accountBalances[transaction.AccountId] -= transaction.Amount
+	} else if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
+		ctx.accountBalances[transaction.AccountId] -= transaction.Amount
+		ctx.accountBalances[transaction.RelatedAccountId] += transaction.RelatedAccountAmount
+		ctx.accountUsed[transaction.RelatedAccountId] = true
+	}
+
+	ctx.accountUsed[transaction.AccountId] = true
+
+	return nil
+}
+
+func (s *TransactionImportService) saveImportedData(c *core.Context, sess *xorm.Session, ctx *transactionImportContext, transactions []*models.Transaction, allTagIds [][]int64) error {
+	bookLocks, err := getBookLocks(sess, ctx.user.Uid)
+
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < len(transactions); i++ {
+		if isTransactionInClosedPeriod(bookLocks, transactions[i]) {
+			return errs.ErrTransactionInClosedPeriod
+		}
+	}
+
+	for i := 0; i < len(ctx.newAccounts); i++ {
+		_, err := sess.Insert(ctx.newAccounts[i])
+
+		if err != nil {
+			return err
+		}
+
+		err = DataRevisions.insertRevision(c, sess, ctx.user.Uid, models.DATA_REVISION_OBJECT_TYPE_ACCOUNT, ctx.newAccounts[i].AccountId, models.DATA_REVISION_ACTION_CREATE, nil, getAccountRevisionFields(ctx.newAccounts[i]))
+
+		if err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < len(ctx.newCategories); i++ {
+		_, err := sess.Insert(ctx.newCategories[i])
+
+		if err != nil {
+			return err
+		}
+
+		err = DataRevisions.insertRevision(c, sess, ctx.user.Uid, models.DATA_REVISION_OBJECT_TYPE_CATEGORY, ctx.newCategories[i].CategoryId, models.DATA_REVISION_ACTION_CREATE, nil, getCategoryRevisionFields(ctx.newCategories[i]))
+
+		if err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < len(ctx.newTags); i++ {
+		_, err := sess.Insert(ctx.newTags[i])
+
+		if err != nil {
+			return err
+		}
+
+		err = DataRevisions.insertRevision(c, sess, ctx.user.Uid, models.DATA_REVISION_OBJECT_TYPE_TAG, ctx.newTags[i].TagId, models.DATA_REVISION_ACTION_CREATE, nil, getTagRevisionFields(ctx.newTags[i]))
+
+		if err != nil {
+			return err
+		}
+	}
+
+	balanceChanges := make(map[int64]int64)
+
+	for i := 0; i < len(transactions); i++ {
+		transaction := transactions[i]
+		transaction.CreatedUid = ctx.operatorUid
+		needUuidCount := 1
+
+		if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
+			needUuidCount = 2
+		}
+
+		uuids := s.GenerateUuids(uuid.UUID_TYPE_TRANSACTION, uint8(needUuidCount))
+		transaction.TransactionId = uuids[0]
+
+		if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
+			transaction.RelatedId = uuids[1]
+		}
+
+		_, err := sess.Insert(transaction)
+
+		if err != nil {
+			return err
+		}
+
+		if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
+			relatedTransaction := s.transactions.GetRelatedTransferTransaction(transaction)
+			_, err = sess.Insert(relatedTransaction)
+
+			if err != nil {
+				return err
+			}
+
+			balanceChanges[transaction.AccountId] -= transaction.Amount
+			balanceChanges[transaction.RelatedAccountId] += transaction.RelatedAccountAmount
+		} else if transaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
+			balanceChanges[transaction.AccountId] += transaction.RelatedAccountAmount
+		} else if transaction.Type == models.TRANSACTION_DB_TYPE_INCOME {
+			balanceChanges[transaction.AccountId] += transaction.Amount
+		} else if transaction.Type == models.TRANSACTION_DB_TYPE_EXPENSE {
+			balanceChanges[transaction.AccountId] -= transaction.Amount
+		}
+
+		tagIds := allTagIds[i]
+
+		for j := 0; j < len(tagIds); j++ {
+			tagIndex := &models.TransactionTagIndex{
+				TagIndexId:      s.GenerateUuid(uuid.UUID_TYPE_TAG_INDEX),
+				Uid:             ctx.user.Uid,
+				Deleted:         false,
+				TagId:           tagIds[j],
+				TransactionId:   transaction.TransactionId,
+				TransactionTime: transaction.TransactionTime,
+				CreatedUnixTime: ctx.now,
+				UpdatedUnixTime: ctx.now,
+			}
+
+			_, err = sess.Insert(tagIndex)
+
+			if err != nil {
+				return err
+			}
+		}
+
+		err = DataRevisions.insertRevision(c, sess, ctx.user.Uid, models.DATA_REVISION_OBJECT_TYPE_TRANSACTION, transaction.TransactionId, models.DATA_REVISION_ACTION_CREATE, nil, getTransactionRevisionFields(transaction, tagIds, nil))
+
+		if err != nil {
+			return err
+		}
+	}
+
+	for accountId, balanceChange := range balanceChanges {
+		if balanceChange == 0 {
+			continue
+		}
+
+		account := &models.Account{
+			UpdatedUnixTime: time.Now().Unix(),
+		}
+
+		updatedRows, err := sess.ID(accountId).SetExpr("balance", fmt.Sprintf("balance+(%d)", balanceChange)).Cols("updated_unix_time").Where("uid=? AND deleted=?", ctx.user.Uid, false).Update(account)
+
+		if err != nil {
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrDatabaseOperationFailed
+		}
+	}
+
+	return nil
+}
+
+func (s *TransactionImportService) getCategoryOrderKey(categoryType models.TransactionCategoryType, parentCategoryId int64) string {
+	return fmt.Sprintf("%d_%d", categoryType, parentCategoryId)
+}
+
+func (s *TransactionImportService) getSortedNames(names map[string]bool) []string {
+	ret := make([]string, 0, len(names))
+
+	for name := range names {
+		ret = append(ret, name)
+	}
+
+	sort.Strings(ret)
+
+	return ret
+}
+
+func (s *TransactionImportService) getErrorContext(importTransaction *models.ImportTransaction) map[string]string {
+	return map[string]string{
+		"lineNumber": utils.IntToString(importTransaction.LineNumber),
+	}
+}
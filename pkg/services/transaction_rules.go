@@ -0,0 +1,545 @@
+package services
+
+import (
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/f97/gofire/pkg/core"
+	"github.com/f97/gofire/pkg/datastore"
+	"github.com/f97/gofire/pkg/errs"
+	"github.com/f97/gofire/pkg/models"
+	"github.com/f97/gofire/pkg/utils"
+	"github.com/f97/gofire/pkg/uuid"
+)
+
+// TransactionRuleService represents transaction rule service
+type TransactionRuleService struct {
+	ServiceUsingDB
+	ServiceUsingUuid
+}
+
+// Initialize a transaction rule service singleton instance
+var (
+	TransactionRules = &TransactionRuleService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+		ServiceUsingUuid: ServiceUsingUuid{
+			container: uuid.Container,
+		},
+	}
+)
+
+// GetAllRulesByUid returns all transaction rule models of user
+func (s *TransactionRuleService) GetAllRulesByUid(c *core.Context, uid int64) ([]*models.TransactionRule, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	var rules []*models.TransactionRule
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false).OrderBy("display_order asc").Find(&rules)
+
+	return rules, err
+}
+
+// GetRuleByRuleId returns a transaction rule model according to transaction rule id
+func (s *TransactionRuleService) GetRuleByRuleId(c *core.Context, uid int64, ruleId int64) (*models.TransactionRule, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if ruleId <= 0 {
+		return nil, errs.ErrTransactionRuleIdInvalid
+	}
+
+	rule := &models.TransactionRule{}
+	has, err := s.UserDataDB(uid).NewSession(c).ID(ruleId).Where("uid=? AND deleted=?", uid, false).Get(rule)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrTransactionRuleNotFound
+	}
+
+	return rule, nil
+}
+
+// GetMaxDisplayOrder returns the max display order
+func (s *TransactionRuleService) GetMaxDisplayOrder(c *core.Context, uid int64) (int32, error) {
+	if uid <= 0 {
+		return 0, errs.ErrUserIdInvalid
+	}
+
+	rule := &models.TransactionRule{}
+	has, err := s.UserDataDB(uid).NewSession(c).Cols("uid", "deleted", "display_order").Where("uid=? AND deleted=?", uid, false).OrderBy("display_order desc").Limit(1).Get(rule)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if has {
+		return rule.DisplayOrder, nil
+	} else {
+		return 0, nil
+	}
+}
+
+// CreateRule saves a new transaction rule model to database
+func (s *TransactionRuleService) CreateRule(c *core.Context, operatorUid int64, rule *models.TransactionRule) error {
+	if rule.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	err := s.CheckBookMemberRole(c, rule.Uid, operatorUid, models.BOOK_MEMBER_ROLE_EDITOR)
+
+	if err != nil {
+		return err
+	}
+
+	rule.RuleId = s.GenerateUuid(uuid.UUID_TYPE_RULE)
+
+	rule.Deleted = false
+	rule.CreatedUnixTime = time.Now().Unix()
+	rule.UpdatedUnixTime = time.Now().Unix()
+
+	return s.UserDataDB(rule.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		err := s.isRuleValid(sess, rule)
+
+		if err != nil {
+			return err
+		}
+
+		_, err = sess.Insert(rule)
+		return err
+	})
+}
+
+// ModifyRule saves an existed transaction rule model to database
+func (s *TransactionRuleService) ModifyRule(c *core.Context, operatorUid int64, rule *models.TransactionRule) error {
+	if rule.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	err := s.CheckBookMemberRole(c, rule.Uid, operatorUid, models.BOOK_MEMBER_ROLE_EDITOR)
+
+	if err != nil {
+		return err
+	}
+
+	rule.UpdatedUnixTime = time.Now().Unix()
+
+	return s.UserDataDB(rule.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		err := s.isRuleValid(sess, rule)
+
+		if err != nil {
+			return err
+		}
+
+		updatedRows, err := sess.ID(rule.RuleId).Cols("name", "disabled", "transaction_type", "comment_keyword", "min_amount", "max_amount", "account_id", "target_category_id", "target_tag_ids", "target_comment", "updated_unix_time").Where("uid=? AND deleted=?", rule.Uid, false).Update(rule)
+
+		if err != nil {
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrTransactionRuleNotFound
+		}
+
+		return err
+	})
+}
+
+// ModifyRuleDisplayOrders updates display order of given transaction rules
+func (s *TransactionRuleService) ModifyRuleDisplayOrders(c *core.Context, uid int64, operatorUid int64, rules []*models.TransactionRule) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	err := s.CheckBookMemberRole(c, uid, operatorUid, models.BOOK_MEMBER_ROLE_EDITOR)
+
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < len(rules); i++ {
+		rules[i].UpdatedUnixTime = time.Now().Unix()
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		for i := 0; i < len(rules); i++ {
+			rule := rules[i]
+			updatedRows, err := sess.ID(rule.RuleId).Cols("display_order", "updated_unix_time").Where("uid=? AND deleted=?", uid, false).Update(rule)
+
+			if err != nil {
+				return err
+			} else if updatedRows < 1 {
+				return errs.ErrTransactionRuleNotFound
+			}
+		}
+
+		return nil
+	})
+}
+
+// DeleteRule deletes an existed transaction rule from database
+func (s *TransactionRuleService) DeleteRule(c *core.Context, uid int64, operatorUid int64, ruleId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	err := s.CheckBookMemberRole(c, uid, operatorUid, models.BOOK_MEMBER_ROLE_EDITOR)
+
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.TransactionRule{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		deletedRows, err := sess.ID(ruleId).Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrTransactionRuleNotFound
+		}
+
+		return err
+	})
+}
+
+// DeleteAllRules deletes all existed transaction rules from database
+func (s *TransactionRuleService) DeleteAllRules(c *core.Context, uid int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.TransactionRule{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+		return err
+	})
+}
+
+// ApplyRulesToTransactions applies all enabled transaction rules to the existed transactions of user since the specified unix time, and returns the count of updated transactions
+func (s *TransactionRuleService) ApplyRulesToTransactions(c *core.Context, user *models.User, operatorUid int64, startUnixTime int64) (int, error) {
+	if user.Uid <= 0 {
+		return 0, errs.ErrUserIdInvalid
+	}
+
+	err := s.CheckBookMemberRole(c, user.Uid, operatorUid, models.BOOK_MEMBER_ROLE_EDITOR)
+
+	if err != nil {
+		return 0, err
+	}
+
+	uid := user.Uid
+	minTransactionTime := utils.GetMinTransactionTimeFromUnixTime(startUnixTime)
+	updatedCount := 0
+
+	err = s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		rules, err := getAvailableTransactionRules(sess, uid)
+
+		if err != nil {
+			return err
+		} else if len(rules) < 1 {
+			return nil
+		}
+
+		var transactions []*models.Transaction
+		err = sess.Where("uid=? AND deleted=? AND transaction_time>=?", uid, false, minTransactionTime).In("type", models.TRANSACTION_DB_TYPE_INCOME, models.TRANSACTION_DB_TYPE_EXPENSE, models.TRANSACTION_DB_TYPE_TRANSFER_OUT).Find(&transactions)
+
+		if err != nil {
+			return err
+		} else if len(transactions) < 1 {
+			return nil
+		}
+
+		transactionIds := make([]int64, len(transactions))
+
+		for i := 0; i < len(transactions); i++ {
+			transactionIds[i] = transactions[i].TransactionId
+		}
+
+		var tagIndexs []*models.TransactionTagIndex
+		err = sess.Where("uid=? AND deleted=?", uid, false).In("transaction_id", transactionIds).Find(&tagIndexs)
+
+		if err != nil {
+			return err
+		}
+
+		allTransactionTagIds := TransactionTags.getGroupedTransactionTagIds(tagIndexs)
+		bookLocks, err := getBookLocks(sess, uid)
+
+		if err != nil {
+			return err
+		}
+
+		now := time.Now().Unix()
+
+		for i := 0; i < len(transactions); i++ {
+			transaction := transactions[i]
+
+			if !user.CanEditTransactionByTransactionTime(transaction.TransactionTime, transaction.TimezoneUtcOffset) || isTransactionInClosedPeriod(bookLocks, transaction) {
+				continue
+			}
+
+			oldTagIds := allTransactionTagIds[transaction.TransactionId]
+			newTransaction := *transaction
+			newTagIds, changed := applyTransactionRules(rules, &newTransaction, oldTagIds, true)
+
+			if !changed {
+				continue
+			}
+
+			newTransaction.UpdatedUnixTime = now
+			updatedRows, err := sess.ID(newTransaction.TransactionId).Cols("category_id", "comment", "updated_unix_time").Where("uid=? AND deleted=?", uid, false).Update(&newTransaction)
+
+			if err != nil {
+				return err
+			} else if updatedRows < 1 {
+				return errs.ErrTransactionNotFound
+			}
+
+			if newTransaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
+				_, err = sess.ID(newTransaction.RelatedId).Cols("category_id", "comment", "updated_unix_time").Where("uid=? AND deleted=?", uid, false).Update(&models.Transaction{
+					CategoryId:      newTransaction.CategoryId,
+					Comment:         newTransaction.Comment,
+					UpdatedUnixTime: now,
+				})
+
+				if err != nil {
+					return err
+				}
+			}
+
+			for j := len(oldTagIds); j < len(newTagIds); j++ {
+				_, err = sess.Insert(&models.TransactionTagIndex{
+					TagIndexId:      s.GenerateUuid(uuid.UUID_TYPE_TAG_INDEX),
+					Uid:             uid,
+					Deleted:         false,
+					TagId:           newTagIds[j],
+					TransactionId:   newTransaction.TransactionId,
+					TransactionTime: newTransaction.TransactionTime,
+					CreatedUnixTime: now,
+					UpdatedUnixTime: now,
+				})
+
+				if err != nil {
+					return err
+				}
+			}
+
+			err = DataRevisions.insertRevision(c, sess, uid, models.DATA_REVISION_OBJECT_TYPE_TRANSACTION, newTransaction.TransactionId, models.DATA_REVISION_ACTION_MODIFY, getTransactionRevisionFields(transaction, oldTagIds, nil), getTransactionRevisionFields(&newTransaction, newTagIds, nil))
+
+			if err != nil {
+				return err
+			}
+
+			updatedCount++
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	return updatedCount, nil
+}
+
+func (s *TransactionRuleService) isRuleValid(sess *xorm.Session, rule *models.TransactionRule) error {
+	if !rule.HasCondition() {
+		return errs.ErrTransactionRuleConditionIsEmpty
+	}
+
+	if !rule.HasAction() {
+		return errs.ErrTransactionRuleActionIsEmpty
+	}
+
+	if rule.MaxAmount > 0 && rule.MaxAmount < rule.MinAmount {
+		return errs.ErrTransactionRuleAmountRangeInvalid
+	}
+
+	if rule.AccountId > 0 {
+		exists, err := sess.ID(rule.AccountId).Where("uid=? AND deleted=?", rule.Uid, false).Exist(&models.Account{})
+
+		if err != nil {
+			return err
+		} else if !exists {
+			return errs.ErrAccountNotFound
+		}
+	}
+
+	if rule.TargetCategoryId > 0 {
+		if rule.TransactionType == 0 {
+			return errs.ErrTransactionRuleCategoryRequiresTransactionType
+		}
+
+		category := &models.TransactionCategory{}
+		has, err := sess.ID(rule.TargetCategoryId).Where("uid=? AND deleted=?", rule.Uid, false).Get(category)
+
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrTransactionCategoryNotFound
+		}
+
+		if category.ParentCategoryId < 1 {
+			return errs.ErrCannotUsePrimaryCategoryForTransaction
+		}
+
+		if (rule.TransactionType == models.TRANSACTION_DB_TYPE_INCOME && category.Type != models.CATEGORY_TYPE_INCOME) ||
+			(rule.TransactionType == models.TRANSACTION_DB_TYPE_EXPENSE && category.Type != models.CATEGORY_TYPE_EXPENSE) ||
+			(rule.TransactionType == models.TRANSACTION_DB_TYPE_TRANSFER_OUT && category.Type != models.CATEGORY_TYPE_TRANSFER) {
+			return errs.ErrTransactionCategoryTypeInvalid
+		}
+	}
+
+	tagIds := rule.GetTargetTagIds()
+
+	if len(tagIds) > 0 {
+		count, err := sess.Where("uid=? AND deleted=?", rule.Uid, false).In("tag_id", tagIds).Count(&models.TransactionTag{})
+
+		if err != nil {
+			return err
+		} else if count != int64(len(tagIds)) {
+			return errs.ErrTransactionTagNotFound
+		}
+	}
+
+	return nil
+}
+
+// getAvailableTransactionRules returns all enabled transaction rules of user in display order, the category and tags which have been deleted are removed from the actions
+func getAvailableTransactionRules(sess *xorm.Session, uid int64) ([]*models.TransactionRule, error) {
+	var rules []*models.TransactionRule
+	err := sess.Where("uid=? AND deleted=? AND disabled=?", uid, false, false).OrderBy("display_order asc").Find(&rules)
+
+	if err != nil || len(rules) < 1 {
+		return rules, err
+	}
+
+	var categoryIds []int64
+	var tagIds []int64
+
+	for i := 0; i < len(rules); i++ {
+		if rules[i].TargetCategoryId > 0 {
+			categoryIds = append(categoryIds, rules[i].TargetCategoryId)
+		}
+
+		tagIds = append(tagIds, rules[i].GetTargetTagIds()...)
+	}
+
+	existedCategoryIds := make(map[int64]bool)
+	existedTagIds := make(map[int64]bool)
+
+	if len(categoryIds) > 0 {
+		var categories []*models.TransactionCategory
+		err = sess.Cols("category_id").Where("uid=? AND deleted=?", uid, false).In("category_id", utils.ToUniqueInt64Slice(categoryIds)).Find(&categories)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for i := 0; i < len(categories); i++ {
+			existedCategoryIds[categories[i].CategoryId] = true
+		}
+	}
+
+	if len(tagIds) > 0 {
+		var tags []*models.TransactionTag
+		err = sess.Cols("tag_id").Where("uid=? AND deleted=?", uid, false).In("tag_id", utils.ToUniqueInt64Slice(tagIds)).Find(&tags)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for i := 0; i < len(tags); i++ {
+			existedTagIds[tags[i].TagId] = true
+		}
+	}
+
+	for i := 0; i < len(rules); i++ {
+		rule := rules[i]
+
+		if rule.TargetCategoryId > 0 && !existedCategoryIds[rule.TargetCategoryId] {
+			rule.TargetCategoryId = 0
+		}
+
+		ruleTagIds := rule.GetTargetTagIds()
+		availableTagIds := make([]int64, 0, len(ruleTagIds))
+
+		for j := 0; j < len(ruleTagIds); j++ {
+			if existedTagIds[ruleTagIds[j]] {
+				availableTagIds = append(availableTagIds, ruleTagIds[j])
+			}
+		}
+
+		rule.SetTargetTagIds(availableTagIds)
+	}
+
+	return rules, nil
+}
+
+// applyTransactionRules applies the matched rules to the transaction in order and returns the new tag ids and whether the transaction is changed.
+// The category and comment are set by the first matched rule which has the action, the category is only set when it is empty unless overwriteCategory is true,
+// and the comment is only set when it is empty. The tags of all matched rules are appended to the end of the original tag ids.
+func applyTransactionRules(rules []*models.TransactionRule, transaction *models.Transaction, tagIds []int64, overwriteCategory bool) ([]int64, bool) {
+	newTagIds := make([]int64, len(tagIds), len(tagIds)+1)
+	copy(newTagIds, tagIds)
+
+	existedTagIds := make(map[int64]bool, len(tagIds))
+
+	for i := 0; i < len(tagIds); i++ {
+		existedTagIds[tagIds[i]] = true
+	}
+
+	categorySet := false
+	changed := false
+
+	for i := 0; i < len(rules); i++ {
+		rule := rules[i]
+
+		if !rule.IsMatched(transaction) {
+			continue
+		}
+
+		if rule.TargetCategoryId > 0 && !categorySet && (overwriteCategory || transaction.CategoryId == 0) {
+			if transaction.CategoryId != rule.TargetCategoryId {
+				transaction.CategoryId = rule.TargetCategoryId
+				changed = true
+			}
+
+			categorySet = true
+		}
+
+		if rule.TargetComment != "" && transaction.Comment == "" {
+			transaction.Comment = rule.TargetComment
+			changed = true
+		}
+
+		ruleTagIds := rule.GetTargetTagIds()
+
+		for j := 0; j < len(ruleTagIds); j++ {
+			if !existedTagIds[ruleTagIds[j]] {
+				existedTagIds[ruleTagIds[j]] = true
+				newTagIds = append(newTagIds, ruleTagIds[j])
+				changed = true
+			}
+		}
+	}
+
+	return newTagIds, changed
+}
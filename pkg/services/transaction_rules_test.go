@@ -0,0 +1,348 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/f97/gofire/pkg/datastore"
+	"github.com/f97/gofire/pkg/models"
+	"github.com/f97/gofire/pkg/utils"
+	"github.com/f97/gofire/pkg/uuid"
+)
+
+func TestApplyTransactionRules_FirstMatchedRuleSetsCategoryAndComment(t *testing.T) {
+	rules := []*models.TransactionRule{
+		{TransactionType: models.TRANSACTION_DB_TYPE_INCOME, TargetCategoryId: 1, TargetComment: "Income"},
+		{CommentKeyword: "coffee", TargetCategoryId: 2, TargetComment: "Coffee"},
+		{TransactionType: models.TRANSACTION_DB_TYPE_EXPENSE, TargetCategoryId: 3, TargetComment: "Expense"},
+	}
+	transaction := &models.Transaction{Type: models.TRANSACTION_DB_TYPE_EXPENSE, Comment: ""}
+
+	tagIds, changed := applyTransactionRules(rules, transaction, nil, false)
+	assert.Equal(t, true, changed)
+	assert.Equal(t, []int64{}, tagIds)
+	assert.Equal(t, int64(3), transaction.CategoryId)
+	assert.Equal(t, "Expense", transaction.Comment)
+
+	transaction = &models.Transaction{Type: models.TRANSACTION_DB_TYPE_EXPENSE, Comment: "coffee"}
+
+	_, changed = applyTransactionRules(rules, transaction, nil, false)
+	assert.Equal(t, true, changed)
+	assert.Equal(t, int64(2), transaction.CategoryId)
+	assert.Equal(t, "coffee", transaction.Comment)
+}
+
+func TestApplyTransactionRules_OverwriteCategory(t *testing.T) {
+	rules := []*models.TransactionRule{
+		{TransactionType: models.TRANSACTION_DB_TYPE_EXPENSE, TargetCategoryId: 2},
+	}
+
+	transaction := &models.Transaction{Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 1}
+	_, changed := applyTransactionRules(rules, transaction, nil, false)
+	assert.Equal(t, false, changed)
+	assert.Equal(t, int64(1), transaction.CategoryId)
+
+	_, changed = applyTransactionRules(rules, transaction, nil, true)
+	assert.Equal(t, true, changed)
+	assert.Equal(t, int64(2), transaction.CategoryId)
+
+	_, changed = applyTransactionRules(rules, transaction, nil, true)
+	assert.Equal(t, false, changed)
+	assert.Equal(t, int64(2), transaction.CategoryId)
+}
+
+func TestApplyTransactionRules_AppendTagsOfAllMatchedRules(t *testing.T) {
+	rules := []*models.TransactionRule{
+		{TransactionType: models.TRANSACTION_DB_TYPE_EXPENSE, TargetTagIds: "2,3"},
+		{Disabled: true, TransactionType: models.TRANSACTION_DB_TYPE_EXPENSE, TargetTagIds: "4"},
+		{MinAmount: 100, TargetTagIds: "3,5"},
+		{MinAmount: 10000, TargetTagIds: "6"},
+	}
+	transaction := &models.Transaction{Type: models.TRANSACTION_DB_TYPE_EXPENSE, Amount: 100}
+	originalTagIds := []int64{1, 2}
+
+	tagIds, changed := applyTransactionRules(rules, transaction, originalTagIds, false)
+	assert.Equal(t, true, changed)
+	assert.Equal(t, []int64{1, 2, 3, 5}, tagIds)
+	assert.Equal(t, []int64{1, 2}, originalTagIds)
+
+	tagIds, changed = applyTransactionRules(rules, transaction, []int64{2, 3, 5}, false)
+	assert.Equal(t, false, changed)
+	assert.Equal(t, []int64{2, 3, 5}, tagIds)
+}
+
+func TestCreateTransaction_ApplyTransactionRules(t *testing.T) {
+	c := initializeTestDataStore(t)
+	uid := int64(1001)
+	unixTime := int64(1700000000)
+
+	primaryExpenseCategory := &models.TransactionCategory{
+		CategoryId: TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:        uid,
+		Name:       "Expense",
+		Type:       models.CATEGORY_TYPE_EXPENSE,
+	}
+
+	account := &models.Account{
+		AccountId: Accounts.GenerateUuid(uuid.UUID_TYPE_ACCOUNT),
+		Uid:       uid,
+		Name:      "Cash",
+		Category:  models.ACCOUNT_CATEGORY_CASH,
+		Type:      models.ACCOUNT_TYPE_SINGLE_ACCOUNT,
+		Currency:  "USD",
+		Balance:   10000,
+	}
+
+	category := &models.TransactionCategory{
+		CategoryId:       TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:              uid,
+		Name:             "Category",
+		Type:             models.CATEGORY_TYPE_EXPENSE,
+		ParentCategoryId: primaryExpenseCategory.CategoryId,
+	}
+
+	tag := &models.TransactionTag{
+		TagId: TransactionTags.GenerateUuid(uuid.UUID_TYPE_TAG),
+		Uid:   uid,
+		Name:  "Coffee",
+	}
+
+	_, err := datastore.Container.UserDataStore.Choose(uid).NewSession(c).Insert(primaryExpenseCategory, account, category, tag)
+	assert.Equal(t, nil, err)
+
+	rule := &models.TransactionRule{
+		Uid:              uid,
+		Name:             "Coffee",
+		TransactionType:  models.TRANSACTION_DB_TYPE_EXPENSE,
+		CommentKeyword:   "coffee",
+		TargetCategoryId: category.CategoryId,
+	}
+	rule.SetTargetTagIds([]int64{tag.TagId})
+
+	err = TransactionRules.CreateRule(c, uid, rule)
+	assert.Equal(t, nil, err)
+
+	transaction := &models.Transaction{
+		Uid:             uid,
+		Type:            models.TRANSACTION_DB_TYPE_EXPENSE,
+		AccountId:       account.AccountId,
+		Amount:          500,
+		Comment:         "Coffee with friends",
+		TransactionTime: utils.GetMinTransactionTimeFromUnixTime(unixTime),
+	}
+
+	err = Transactions.CreateTransaction(c, uid, transaction, nil, nil)
+	assert.Equal(t, nil, err)
+
+	savedTransaction, err := Transactions.GetTransactionByTransactionId(c, uid, transaction.TransactionId)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, category.CategoryId, savedTransaction.CategoryId)
+
+	allTagIds, err := TransactionTags.GetAllTagIdsOfTransactions(c, uid, []int64{transaction.TransactionId})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []int64{tag.TagId}, allTagIds[transaction.TransactionId])
+
+	// Transaction which does not match the rule still requires category
+	transaction = &models.Transaction{
+		Uid:             uid,
+		Type:            models.TRANSACTION_DB_TYPE_EXPENSE,
+		AccountId:       account.AccountId,
+		Amount:          500,
+		Comment:         "Lunch",
+		TransactionTime: utils.GetMinTransactionTimeFromUnixTime(unixTime),
+	}
+
+	err = Transactions.CreateTransaction(c, uid, transaction, nil, nil)
+	assert.NotEqual(t, nil, err)
+}
+
+func TestApplyRulesToTransactions(t *testing.T) {
+	c := initializeTestDataStore(t)
+	uid := int64(1001)
+	unixTime := int64(1700000000)
+	user := &models.User{Uid: uid, TransactionEditScope: models.TRANSACTION_EDIT_SCOPE_ALL}
+
+	primaryExpenseCategory := &models.TransactionCategory{
+		CategoryId: TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:        uid,
+		Name:       "Expense",
+		Type:       models.CATEGORY_TYPE_EXPENSE,
+	}
+
+	account := &models.Account{
+		AccountId: Accounts.GenerateUuid(uuid.UUID_TYPE_ACCOUNT),
+		Uid:       uid,
+		Name:      "Cash",
+		Category:  models.ACCOUNT_CATEGORY_CASH,
+		Type:      models.ACCOUNT_TYPE_SINGLE_ACCOUNT,
+		Currency:  "USD",
+		Balance:   10000,
+	}
+
+	oldCategory := &models.TransactionCategory{
+		CategoryId:       TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:              uid,
+		Name:             "Old Category",
+		Type:             models.CATEGORY_TYPE_EXPENSE,
+		ParentCategoryId: primaryExpenseCategory.CategoryId,
+	}
+
+	newCategory := &models.TransactionCategory{
+		CategoryId:       TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:              uid,
+		Name:             "New Category",
+		Type:             models.CATEGORY_TYPE_EXPENSE,
+		ParentCategoryId: primaryExpenseCategory.CategoryId,
+	}
+
+	tag := &models.TransactionTag{
+		TagId: TransactionTags.GenerateUuid(uuid.UUID_TYPE_TAG),
+		Uid:   uid,
+		Name:  "Large",
+	}
+
+	_, err := datastore.Container.UserDataStore.Choose(uid).NewSession(c).Insert(primaryExpenseCategory, account, oldCategory, newCategory, tag)
+	assert.Equal(t, nil, err)
+
+	earlierTransaction := &models.Transaction{
+		Uid:             uid,
+		Type:            models.TRANSACTION_DB_TYPE_EXPENSE,
+		CategoryId:      oldCategory.CategoryId,
+		AccountId:       account.AccountId,
+		Amount:          2000,
+		TransactionTime: utils.GetMinTransactionTimeFromUnixTime(unixTime - 86400),
+	}
+
+	matchedTransaction := &models.Transaction{
+		Uid:             uid,
+		Type:            models.TRANSACTION_DB_TYPE_EXPENSE,
+		CategoryId:      oldCategory.CategoryId,
+		AccountId:       account.AccountId,
+		Amount:          2000,
+		TransactionTime: utils.GetMinTransactionTimeFromUnixTime(unixTime),
+	}
+
+	unmatchedTransaction := &models.Transaction{
+		Uid:             uid,
+		Type:            models.TRANSACTION_DB_TYPE_EXPENSE,
+		CategoryId:      oldCategory.CategoryId,
+		AccountId:       account.AccountId,
+		Amount:          100,
+		TransactionTime: utils.GetMinTransactionTimeFromUnixTime(unixTime),
+	}
+
+	err = Transactions.CreateTransaction(c, uid, earlierTransaction, nil, nil)
+	assert.Equal(t, nil, err)
+
+	err = Transactions.CreateTransaction(c, uid, matchedTransaction, nil, nil)
+	assert.Equal(t, nil, err)
+
+	err = Transactions.CreateTransaction(c, uid, unmatchedTransaction, nil, nil)
+	assert.Equal(t, nil, err)
+
+	rule := &models.TransactionRule{
+		Uid:              uid,
+		Name:             "Large expense",
+		TransactionType:  models.TRANSACTION_DB_TYPE_EXPENSE,
+		MinAmount:        1000,
+		TargetCategoryId: newCategory.CategoryId,
+		TargetComment:    "Large expense",
+	}
+	rule.SetTargetTagIds([]int64{tag.TagId})
+
+	err = TransactionRules.CreateRule(c, uid, rule)
+	assert.Equal(t, nil, err)
+
+	updatedCount, err := TransactionRules.ApplyRulesToTransactions(c, user, uid, unixTime)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, updatedCount)
+
+	savedTransaction, err := Transactions.GetTransactionByTransactionId(c, uid, matchedTransaction.TransactionId)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, newCategory.CategoryId, savedTransaction.CategoryId)
+	assert.Equal(t, "Large expense", savedTransaction.Comment)
+
+	savedTransaction, err = Transactions.GetTransactionByTransactionId(c, uid, earlierTransaction.TransactionId)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, oldCategory.CategoryId, savedTransaction.CategoryId)
+
+	savedTransaction, err = Transactions.GetTransactionByTransactionId(c, uid, unmatchedTransaction.TransactionId)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, oldCategory.CategoryId, savedTransaction.CategoryId)
+
+	allTagIds, err := TransactionTags.GetAllTagIdsOfTransactions(c, uid, []int64{earlierTransaction.TransactionId, matchedTransaction.TransactionId, unmatchedTransaction.TransactionId})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []int64{tag.TagId}, allTagIds[matchedTransaction.TransactionId])
+	assert.Equal(t, 0, len(allTagIds[earlierTransaction.TransactionId]))
+	assert.Equal(t, 0, len(allTagIds[unmatchedTransaction.TransactionId]))
+
+	revisions, err := DataRevisions.GetTransactionRevisions(c, uid, matchedTransaction.TransactionId)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2, len(revisions))
+
+	// Transactions which have already been applied will not be updated again
+	updatedCount, err = TransactionRules.ApplyRulesToTransactions(c, user, uid, unixTime)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 0, updatedCount)
+}
+
+func TestApplyRulesToTransactions_SkipNotEditableTransactions(t *testing.T) {
+	c := initializeTestDataStore(t)
+	uid := int64(1001)
+	unixTime := int64(1700000000)
+	user := &models.User{Uid: uid, TransactionEditScope: models.TRANSACTION_EDIT_SCOPE_NONE}
+
+	primaryExpenseCategory := &models.TransactionCategory{
+		CategoryId: TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:        uid,
+		Name:       "Expense",
+		Type:       models.CATEGORY_TYPE_EXPENSE,
+	}
+
+	account := &models.Account{
+		AccountId: Accounts.GenerateUuid(uuid.UUID_TYPE_ACCOUNT),
+		Uid:       uid,
+		Name:      "Cash",
+		Category:  models.ACCOUNT_CATEGORY_CASH,
+		Type:      models.ACCOUNT_TYPE_SINGLE_ACCOUNT,
+		Currency:  "USD",
+		Balance:   10000,
+	}
+
+	category := &models.TransactionCategory{
+		CategoryId:       TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:              uid,
+		Name:             "Category",
+		Type:             models.CATEGORY_TYPE_EXPENSE,
+		ParentCategoryId: primaryExpenseCategory.CategoryId,
+	}
+
+	_, err := datastore.Container.UserDataStore.Choose(uid).NewSession(c).Insert(primaryExpenseCategory, account, category)
+	assert.Equal(t, nil, err)
+	transaction := &models.Transaction{
+		Uid:             uid,
+		Type:            models.TRANSACTION_DB_TYPE_EXPENSE,
+		CategoryId:      category.CategoryId,
+		AccountId:       account.AccountId,
+		Amount:          2000,
+		TransactionTime: utils.GetMinTransactionTimeFromUnixTime(unixTime),
+	}
+
+	err = Transactions.CreateTransaction(c, uid, transaction, nil, nil)
+	assert.Equal(t, nil, err)
+
+	rule := &models.TransactionRule{
+		Uid:           uid,
+		Name:          "Comment",
+		MinAmount:     1000,
+		TargetComment: "Large expense",
+	}
+
+	err = TransactionRules.CreateRule(c, uid, rule)
+	assert.Equal(t, nil, err)
+
+	updatedCount, err := TransactionRules.ApplyRulesToTransactions(c, user, uid, unixTime)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 0, updatedCount)
+}
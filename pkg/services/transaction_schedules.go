@@ -0,0 +1,400 @@
+package services
+
+import (
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/f97/gofire/pkg/core"
+	"github.com/f97/gofire/pkg/datastore"
+	"github.com/f97/gofire/pkg/errs"
+	"github.com/f97/gofire/pkg/log"
+	"github.com/f97/gofire/pkg/models"
+	"github.com/f97/gofire/pkg/uuid"
+)
+
+// maxScheduledTransactionCountPerRun is the max count of occurrences created by one schedule in one run
+const maxScheduledTransactionCountPerRun = 31
+
+// TransactionScheduleService represents transaction schedule service
+type TransactionScheduleService struct {
+	ServiceUsingDB
+	ServiceUsingUuid
+	transactions *TransactionService
+	loans        *LoanService
+}
+
+// Initialize a transaction schedule service singleton instance
+var (
+	TransactionSchedules = &TransactionScheduleService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+		ServiceUsingUuid: ServiceUsingUuid{
+			container: uuid.Container,
+		},
+		transactions: Transactions,
+		loans:        Loans,
+	}
+)
+
+// GetAllSchedulesByUid returns all transaction schedule models of user
+func (s *TransactionScheduleService) GetAllSchedulesByUid(c *core.Context, uid int64) ([]*models.TransactionSchedule, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	var schedules []*models.TransactionSchedule
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false).Find(&schedules)
+
+	return schedules, err
+}
+
+// GetScheduleByScheduleId returns a transaction schedule model according to transaction schedule id
+func (s *TransactionScheduleService) GetScheduleByScheduleId(c *core.Context, uid int64, scheduleId int64) (*models.TransactionSchedule, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if scheduleId <= 0 {
+		return nil, errs.ErrTransactionScheduleIdInvalid
+	}
+
+	schedule := &models.TransactionSchedule{}
+	has, err := s.UserDataDB(uid).NewSession(c).ID(scheduleId).Where("uid=? AND deleted=?", uid, false).Get(schedule)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrTransactionScheduleNotFound
+	}
+
+	return schedule, nil
+}
+
+// CreateSchedule saves a new transaction schedule model to database
+func (s *TransactionScheduleService) CreateSchedule(c *core.Context, operatorUid int64, schedule *models.TransactionSchedule) error {
+	if schedule.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	err := s.CheckBookMemberRole(c, schedule.Uid, operatorUid, models.BOOK_MEMBER_ROLE_EDITOR)
+
+	if err != nil {
+		return err
+	}
+
+	err = s.isScheduleValid(schedule)
+
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().Unix()
+
+	schedule.ScheduleId = s.GenerateUuid(uuid.UUID_TYPE_SCHEDULE)
+	schedule.NextRunTime = schedule.GetNextRunTime(schedule.StartTime - 1)
+	schedule.LastRunTime = 0
+
+	if schedule.NextRunTime <= 0 {
+		return errs.ErrTransactionScheduleHasNoMoreOccurrence
+	}
+
+	schedule.Deleted = false
+	schedule.CreatedUnixTime = now
+	schedule.UpdatedUnixTime = now
+
+	return s.UserDataDB(schedule.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		err := s.isScheduleRelatedDataValid(sess, schedule)
+
+		if err != nil {
+			return err
+		}
+
+		_, err = sess.Insert(schedule)
+		return err
+	})
+}
+
+// ModifySchedule saves an existed transaction schedule model to database, the schedule created by loan cannot be modified
+func (s *TransactionScheduleService) ModifySchedule(c *core.Context, operatorUid int64, schedule *models.TransactionSchedule) error {
+	if schedule.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	err := s.CheckBookMemberRole(c, schedule.Uid, operatorUid, models.BOOK_MEMBER_ROLE_EDITOR)
+
+	if err != nil {
+		return err
+	}
+
+	if schedule.LoanId > 0 {
+		return errs.ErrTransactionScheduleCreatedByLoan
+	}
+
+	err = s.isScheduleValid(schedule)
+
+	if err != nil {
+		return err
+	}
+
+	schedule.UpdatedUnixTime = time.Now().Unix()
+
+	return s.UserDataDB(schedule.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		err := s.isScheduleRelatedDataValid(sess, schedule)
+
+		if err != nil {
+			return err
+		}
+
+		oldSchedule := &models.TransactionSchedule{}
+		has, err := sess.ID(schedule.ScheduleId).Cols("last_run_time", "loan_id").Where("uid=? AND deleted=?", schedule.Uid, false).Get(oldSchedule)
+
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrTransactionScheduleNotFound
+		} else if oldSchedule.LoanId > 0 {
+			return errs.ErrTransactionScheduleCreatedByLoan
+		}
+
+		// Occurrences which have already been created will never be created again
+		afterTime := schedule.StartTime - 1
+
+		if oldSchedule.LastRunTime > afterTime {
+			afterTime = oldSchedule.LastRunTime
+		}
+
+		schedule.LastRunTime = oldSchedule.LastRunTime
+		schedule.NextRunTime = schedule.GetNextRunTime(afterTime)
+
+		// The schedule may have run after reading it, then the next run time computed above would be stale
+		updatedRows, err := sess.ID(schedule.ScheduleId).Cols("disabled", "category_id", "account_id", "amount", "related_account_id", "related_account_amount", "hide_amount", "tag_ids", "comment", "frequency", "day_of_month", "start_time", "end_time", "timezone_utc_offset", "next_run_time", "updated_unix_time").Where("uid=? AND deleted=? AND loan_id=? AND last_run_time=?", schedule.Uid, false, 0, oldSchedule.LastRunTime).Update(schedule)
+
+		if err != nil {
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrTransactionScheduleRunConcurrently
+		}
+
+		return err
+	})
+}
+
+// DeleteSchedule deletes an existed transaction schedule from database, the schedule created by loan cannot be deleted
+func (s *TransactionScheduleService) DeleteSchedule(c *core.Context, uid int64, operatorUid int64, scheduleId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	err := s.CheckBookMemberRole(c, uid, operatorUid, models.BOOK_MEMBER_ROLE_EDITOR)
+
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.TransactionSchedule{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		schedule := &models.TransactionSchedule{}
+		has, err := sess.ID(scheduleId).Cols("loan_id").Where("uid=? AND deleted=?", uid, false).Get(schedule)
+
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrTransactionScheduleNotFound
+		} else if schedule.LoanId > 0 {
+			return errs.ErrTransactionScheduleCreatedByLoan
+		}
+
+		deletedRows, err := sess.ID(scheduleId).Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrTransactionScheduleNotFound
+		}
+
+		return err
+	})
+}
+
+// DeleteAllSchedules deletes all existed transaction schedules from database
+func (s *TransactionScheduleService) DeleteAllSchedules(c *core.Context, uid int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.TransactionSchedule{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+		return err
+	})
+}
+
+// CreateDueScheduledTransactions creates transactions for all enabled schedules whose next run time is not later than the given unix time
+func (s *TransactionScheduleService) CreateDueScheduledTransactions(c *core.Context, currentUnixTime int64) (int, error) {
+	totalCreatedCount := 0
+	databases := s.AllUserDataDBs()
+
+	for i := 0; i < len(databases); i++ {
+		var schedules []*models.TransactionSchedule
+		err := databases[i].NewSession(c).Where("deleted=? AND disabled=? AND next_run_time>? AND next_run_time<=?", false, false, 0, currentUnixTime).Find(&schedules)
+
+		if err != nil {
+			return totalCreatedCount, err
+		}
+
+		for j := 0; j < len(schedules); j++ {
+			createdCount := s.createScheduledTransactions(c, schedules[j], currentUnixTime)
+			totalCreatedCount += createdCount
+		}
+	}
+
+	return totalCreatedCount, nil
+}
+
+func (s *TransactionScheduleService) createScheduledTransactions(c *core.Context, schedule *models.TransactionSchedule, currentUnixTime int64) int {
+	runCount := 0
+	createdCount := 0
+
+	for runCount < maxScheduledTransactionCountPerRun && schedule.NextRunTime > 0 && schedule.NextRunTime <= currentUnixTime {
+		runTime := schedule.NextRunTime
+		tagIds, err := schedule.GetTagIds()
+
+		if err != nil {
+			log.Warnf("[transaction_schedules.createScheduledTransactions] failed to parse tag ids of schedule \"id:%d\" for user \"uid:%d\", because %s", schedule.ScheduleId, schedule.Uid, err.Error())
+			tagIds = nil
+		}
+
+		var transactions []*models.Transaction
+		claimed := false
+
+		err = s.UserDataDB(schedule.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+			claimed, err = s.claimScheduleRun(sess, schedule, runTime)
+
+			if err != nil || !claimed {
+				return err
+			}
+
+			// The principal and interest of loan payment are different in each period
+			if schedule.LoanId > 0 {
+				transactions, err = s.loans.createPaymentTransactions(c, sess, schedule.Uid, schedule.LoanId, runTime)
+				return err
+			}
+
+			transaction := schedule.ToTransaction(runTime)
+			transactions = []*models.Transaction{transaction}
+
+			return s.transactions.createTransaction(c, sess, transaction, tagIds, nil)
+		})
+
+		if err != nil {
+			log.Errorf("[transaction_schedules.createScheduledTransactions] failed to create transaction of schedule \"id:%d\" at \"%d\" for user \"uid:%d\", because %s", schedule.ScheduleId, runTime, schedule.Uid, err.Error())
+			return createdCount
+		} else if !claimed { // this occurrence has been created by another instance
+			return createdCount
+		}
+
+		schedule.NextRunTime = schedule.GetNextRunTime(runTime)
+		schedule.LastRunTime = runTime
+
+		for i := 0; i < len(transactions); i++ {
+			log.Infof("[transaction_schedules.createScheduledTransactions] transaction \"id:%d\" of schedule \"id:%d\" has been created for user \"uid:%d\"", transactions[i].TransactionId, schedule.ScheduleId, schedule.Uid)
+		}
+
+		runCount++
+		createdCount += len(transactions)
+	}
+
+	return createdCount
+}
+
+// claimScheduleRun moves next run time of the schedule forward only if it has not been changed, so each occurrence is created at most once.
+// It must be called in the same database transaction which creates the occurrence, so the occurrence will be created again next time if creating fails
+func (s *TransactionScheduleService) claimScheduleRun(sess *xorm.Session, schedule *models.TransactionSchedule, runTime int64) (bool, error) {
+	updateModel := &models.TransactionSchedule{
+		NextRunTime:     schedule.GetNextRunTime(runTime),
+		LastRunTime:     runTime,
+		UpdatedUnixTime: time.Now().Unix(),
+	}
+
+	updatedRows, err := sess.ID(schedule.ScheduleId).Cols("next_run_time", "last_run_time", "updated_unix_time").Where("uid=? AND deleted=? AND disabled=? AND next_run_time=?", schedule.Uid, false, false, runTime).Update(updateModel)
+
+	if err != nil {
+		return false, err
+	}
+
+	return updatedRows > 0, nil
+}
+
+func (s *TransactionScheduleService) isScheduleValid(schedule *models.TransactionSchedule) error {
+	if schedule.Type != models.TRANSACTION_DB_TYPE_INCOME &&
+		schedule.Type != models.TRANSACTION_DB_TYPE_EXPENSE &&
+		schedule.Type != models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
+		return errs.ErrTransactionScheduleTypeInvalid
+	}
+
+	if schedule.Frequency < models.TRANSACTION_SCHEDULE_FREQUENCY_DAILY || schedule.Frequency > models.TRANSACTION_SCHEDULE_FREQUENCY_LAST_BUSINESS_DAY {
+		return errs.ErrTransactionScheduleFrequencyInvalid
+	}
+
+	if schedule.DayOfMonth < 0 || schedule.DayOfMonth > 31 ||
+		(schedule.DayOfMonth > 0 && schedule.Frequency != models.TRANSACTION_SCHEDULE_FREQUENCY_MONTHLY) {
+		return errs.ErrTransactionScheduleDayOfMonthInvalid
+	}
+
+	if schedule.EndTime > 0 && schedule.EndTime < schedule.StartTime {
+		return errs.ErrTransactionScheduleEndTimeInvalid
+	}
+
+	return s.transactions.isAccountIdValid(schedule.ToTransaction(schedule.StartTime))
+}
+
+func (s *TransactionScheduleService) isScheduleRelatedDataValid(sess *xorm.Session, schedule *models.TransactionSchedule) error {
+	transaction := schedule.ToTransaction(schedule.StartTime)
+
+	sourceAccount, destinationAccount, err := s.transactions.getAccountModels(sess, transaction)
+
+	if err != nil {
+		return err
+	}
+
+	if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT &&
+		sourceAccount.Currency == destinationAccount.Currency && transaction.Amount != transaction.RelatedAccountAmount {
+		return errs.ErrTransactionSourceAndDestinationAmountNotEqual
+	}
+
+	err = s.transactions.isCategoryValid(sess, transaction)
+
+	if err != nil {
+		return err
+	}
+
+	tagIds, err := schedule.GetTagIds()
+
+	if err != nil {
+		return errs.ErrTransactionTagIdInvalid
+	}
+
+	transactionTagIndexs := make([]*models.TransactionTagIndex, len(tagIds))
+
+	for i := 0; i < len(tagIds); i++ {
+		transactionTagIndexs[i] = &models.TransactionTagIndex{
+			TagId: tagIds[i],
+		}
+	}
+
+	return s.transactions.isTagsValid(sess, transaction, transactionTagIndexs, tagIds)
+}
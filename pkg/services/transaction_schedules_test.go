@@ -0,0 +1,170 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/f97/gofire/pkg/datastore"
+	"github.com/f97/gofire/pkg/models"
+	"github.com/f97/gofire/pkg/uuid"
+)
+
+func TestCreateDueScheduledTransactions_CreateFailedAndRetry(t *testing.T) {
+	c := initializeTestDataStore(t)
+	uid := int64(1001)
+	startTime := int64(1700000000)
+
+	primaryExpenseCategory := &models.TransactionCategory{
+		CategoryId: TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:        uid,
+		Name:       "Expense",
+		Type:       models.CATEGORY_TYPE_EXPENSE,
+	}
+
+	account := &models.Account{
+		AccountId: Accounts.GenerateUuid(uuid.UUID_TYPE_ACCOUNT),
+		Uid:       uid,
+		Name:      "Cash",
+		Category:  models.ACCOUNT_CATEGORY_CASH,
+		Type:      models.ACCOUNT_TYPE_SINGLE_ACCOUNT,
+		Currency:  "USD",
+		Balance:   10000,
+	}
+
+	category := &models.TransactionCategory{
+		CategoryId:       TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:              uid,
+		Name:             "Category",
+		Type:             models.CATEGORY_TYPE_EXPENSE,
+		ParentCategoryId: primaryExpenseCategory.CategoryId,
+	}
+
+	_, err := datastore.Container.UserDataStore.Choose(uid).NewSession(c).Insert(primaryExpenseCategory, account, category)
+	assert.Equal(t, nil, err)
+
+	schedule := &models.TransactionSchedule{
+		Uid:        uid,
+		Type:       models.TRANSACTION_DB_TYPE_EXPENSE,
+		CategoryId: category.CategoryId,
+		AccountId:  account.AccountId,
+		Amount:     100,
+		Frequency:  models.TRANSACTION_SCHEDULE_FREQUENCY_DAILY,
+		StartTime:  startTime,
+	}
+
+	err = TransactionSchedules.CreateSchedule(c, uid, schedule)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, startTime, schedule.NextRunTime)
+
+	// Transactions cannot be added to hidden account, so creating will fail
+	_, err = datastore.Container.UserDataStore.Choose(uid).NewSession(c).ID(account.AccountId).Cols("hidden").Update(&models.Account{Hidden: true})
+	assert.Equal(t, nil, err)
+
+	createdCount, err := TransactionSchedules.CreateDueScheduledTransactions(c, startTime+2*86400)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 0, createdCount)
+
+	savedSchedule, err := TransactionSchedules.GetScheduleByScheduleId(c, uid, schedule.ScheduleId)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, startTime, savedSchedule.NextRunTime)
+	assert.Equal(t, int64(0), savedSchedule.LastRunTime)
+
+	transactionCount, err := Transactions.GetAllTransactionCount(c, uid)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(0), transactionCount)
+
+	// All missed occurrences are created in the next run
+	_, err = datastore.Container.UserDataStore.Choose(uid).NewSession(c).ID(account.AccountId).Cols("hidden").Update(&models.Account{Hidden: false})
+	assert.Equal(t, nil, err)
+
+	createdCount, err = TransactionSchedules.CreateDueScheduledTransactions(c, startTime+2*86400)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 3, createdCount)
+
+	savedSchedule, err = TransactionSchedules.GetScheduleByScheduleId(c, uid, schedule.ScheduleId)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, startTime+3*86400, savedSchedule.NextRunTime)
+	assert.Equal(t, startTime+2*86400, savedSchedule.LastRunTime)
+
+	transactionCount, err = Transactions.GetAllTransactionCount(c, uid)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(3), transactionCount)
+	savedAccounts, err := Accounts.GetAccountsByAccountIds(c, uid, []int64{account.AccountId})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(9700), savedAccounts[account.AccountId].Balance)
+
+	// Occurrences which have already been created will not be created again
+	createdCount, err = TransactionSchedules.CreateDueScheduledTransactions(c, startTime+2*86400)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 0, createdCount)
+}
+
+func TestModifySchedule_ScheduleRunAfterReading(t *testing.T) {
+	c := initializeTestDataStore(t)
+	uid := int64(1001)
+	startTime := int64(1700000000)
+
+	primaryExpenseCategory := &models.TransactionCategory{
+		CategoryId: TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:        uid,
+		Name:       "Expense",
+		Type:       models.CATEGORY_TYPE_EXPENSE,
+	}
+
+	account := &models.Account{
+		AccountId: Accounts.GenerateUuid(uuid.UUID_TYPE_ACCOUNT),
+		Uid:       uid,
+		Name:      "Cash",
+		Category:  models.ACCOUNT_CATEGORY_CASH,
+		Type:      models.ACCOUNT_TYPE_SINGLE_ACCOUNT,
+		Currency:  "USD",
+		Balance:   10000,
+	}
+
+	category := &models.TransactionCategory{
+		CategoryId:       TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:              uid,
+		Name:             "Category",
+		Type:             models.CATEGORY_TYPE_EXPENSE,
+		ParentCategoryId: primaryExpenseCategory.CategoryId,
+	}
+
+	_, err := datastore.Container.UserDataStore.Choose(uid).NewSession(c).Insert(primaryExpenseCategory, account, category)
+	assert.Equal(t, nil, err)
+
+	schedule := &models.TransactionSchedule{
+		Uid:        uid,
+		Type:       models.TRANSACTION_DB_TYPE_EXPENSE,
+		CategoryId: category.CategoryId,
+		AccountId:  account.AccountId,
+		Amount:     100,
+		Frequency:  models.TRANSACTION_SCHEDULE_FREQUENCY_DAILY,
+		StartTime:  startTime,
+	}
+
+	err = TransactionSchedules.CreateSchedule(c, uid, schedule)
+	assert.Equal(t, nil, err)
+
+	staleSchedule, err := TransactionSchedules.GetScheduleByScheduleId(c, uid, schedule.ScheduleId)
+	assert.Equal(t, nil, err)
+
+	createdCount, err := TransactionSchedules.CreateDueScheduledTransactions(c, startTime+86400)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2, createdCount)
+
+	// Occurrences created after reading the schedule will not be created again
+	staleSchedule.Amount = 200
+	err = TransactionSchedules.ModifySchedule(c, uid, staleSchedule)
+	assert.Equal(t, nil, err)
+
+	expectedValue := startTime + 2*86400
+	actualValue := staleSchedule.NextRunTime
+	assert.Equal(t, expectedValue, actualValue)
+
+	savedSchedule, err := TransactionSchedules.GetScheduleByScheduleId(c, uid, schedule.ScheduleId)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, startTime+2*86400, savedSchedule.NextRunTime)
+	assert.Equal(t, startTime+86400, savedSchedule.LastRunTime)
+	assert.Equal(t, int64(200), savedSchedule.Amount)
+}
@@ -145,11 +145,17 @@ func (s *TransactionTagService) GetAllTagIdsOfTransactions(c *core.Context, uid
 }
 
 // CreateTag saves a new transaction tag model to database
-func (s *TransactionTagService) CreateTag(c *core.Context, tag *models.TransactionTag) error {
+func (s *TransactionTagService) CreateTag(c *core.Context, operatorUid int64, tag *models.TransactionTag) error {
 	if tag.Uid <= 0 {
 		return errs.ErrUserIdInvalid
 	}
 
+	err := s.CheckBookMemberRole(c, tag.Uid, operatorUid, models.BOOK_MEMBER_ROLE_EDITOR)
+
+	if err != nil {
+		return err
+	}
+
 	exists, err := s.ExistsTagName(c, tag.Uid, tag.Name)
 
 	if err != nil {
@@ -166,16 +172,27 @@ func (s *TransactionTagService) CreateTag(c *core.Context, tag *models.Transacti
 
 	return s.UserDataDB(tag.Uid).DoTransaction(c, func(sess *xorm.Session) error {
 		_, err := sess.Insert(tag)
-		return err
+
+		if err != nil {
+			return err
+		}
+
+		return DataRevisions.insertRevision(c, sess, tag.Uid, models.DATA_REVISION_OBJECT_TYPE_TAG, tag.TagId, models.DATA_REVISION_ACTION_CREATE, nil, getTagRevisionFields(tag))
 	})
 }
 
 // ModifyTag saves an existed transaction tag model to database
-func (s *TransactionTagService) ModifyTag(c *core.Context, tag *models.TransactionTag) error {
+func (s *TransactionTagService) ModifyTag(c *core.Context, operatorUid int64, tag *models.TransactionTag) error {
 	if tag.Uid <= 0 {
 		return errs.ErrUserIdInvalid
 	}
 
+	err := s.CheckBookMemberRole(c, tag.Uid, operatorUid, models.BOOK_MEMBER_ROLE_EDITOR)
+
+	if err != nil {
+		return err
+	}
+
 	exists, err := s.ExistsTagName(c, tag.Uid, tag.Name)
 
 	if err != nil {
@@ -187,6 +204,15 @@ func (s *TransactionTagService) ModifyTag(c *core.Context, tag *models.Transacti
 	tag.UpdatedUnixTime = time.Now().Unix()
 
 	return s.UserDataDB(tag.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		oldTag := &models.TransactionTag{}
+		has, err := sess.ID(tag.TagId).Where("uid=? AND deleted=?", tag.Uid, false).Get(oldTag)
+
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrTransactionTagNotFound
+		}
+
 		updatedRows, err := sess.ID(tag.TagId).Cols("name", "updated_unix_time").Where("uid=? AND deleted=?", tag.Uid, false).Update(tag)
 
 		if err != nil {
@@ -195,16 +221,25 @@ func (s *TransactionTagService) ModifyTag(c *core.Context, tag *models.Transacti
 			return errs.ErrTransactionTagNotFound
 		}
 
-		return err
+		newTag := *oldTag
+		newTag.Name = tag.Name
+
+		return DataRevisions.insertRevision(c, sess, tag.Uid, models.DATA_REVISION_OBJECT_TYPE_TAG, tag.TagId, models.DATA_REVISION_ACTION_MODIFY, getTagRevisionFields(oldTag), getTagRevisionFields(&newTag))
 	})
 }
 
 // HideTag updates hidden field of given transaction tags
-func (s *TransactionTagService) HideTag(c *core.Context, uid int64, ids []int64, hidden bool) error {
+func (s *TransactionTagService) HideTag(c *core.Context, uid int64, operatorUid int64, ids []int64, hidden bool) error {
 	if uid <= 0 {
 		return errs.ErrUserIdInvalid
 	}
 
+	err := s.CheckBookMemberRole(c, uid, operatorUid, models.BOOK_MEMBER_ROLE_EDITOR)
+
+	if err != nil {
+		return err
+	}
+
 	now := time.Now().Unix()
 
 	updateModel := &models.TransactionTag{
@@ -213,6 +248,13 @@ func (s *TransactionTagService) HideTag(c *core.Context, uid int64, ids []int64,
 	}
 
 	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		var oldTags []*models.TransactionTag
+		err := sess.Where("uid=? AND deleted=?", uid, false).In("tag_id", ids).Find(&oldTags)
+
+		if err != nil {
+			return err
+		}
+
 		updatedRows, err := sess.Cols("hidden", "updated_unix_time").Where("uid=? AND deleted=?", uid, false).In("tag_id", ids).Update(updateModel)
 
 		if err != nil {
@@ -221,16 +263,34 @@ func (s *TransactionTagService) HideTag(c *core.Context, uid int64, ids []int64,
 			return errs.ErrTransactionTagNotFound
 		}
 
+		for i := 0; i < len(oldTags); i++ {
+			oldTag := oldTags[i]
+			newTag := *oldTag
+			newTag.Hidden = hidden
+
+			err = DataRevisions.insertRevision(c, sess, uid, models.DATA_REVISION_OBJECT_TYPE_TAG, oldTag.TagId, models.DATA_REVISION_ACTION_MODIFY, getTagRevisionFields(oldTag), getTagRevisionFields(&newTag))
+
+			if err != nil {
+				return err
+			}
+		}
+
 		return err
 	})
 }
 
 // ModifyTagDisplayOrders updates display order of given transaction tags
-func (s *TransactionTagService) ModifyTagDisplayOrders(c *core.Context, uid int64, tags []*models.TransactionTag) error {
+func (s *TransactionTagService) ModifyTagDisplayOrders(c *core.Context, uid int64, operatorUid int64, tags []*models.TransactionTag) error {
 	if uid <= 0 {
 		return errs.ErrUserIdInvalid
 	}
 
+	err := s.CheckBookMemberRole(c, uid, operatorUid, models.BOOK_MEMBER_ROLE_EDITOR)
+
+	if err != nil {
+		return err
+	}
+
 	for i := 0; i < len(tags); i++ {
 		tags[i].UpdatedUnixTime = time.Now().Unix()
 	}
@@ -252,11 +312,17 @@ func (s *TransactionTagService) ModifyTagDisplayOrders(c *core.Context, uid int6
 }
 
 // DeleteTag deletes an existed transaction tag from database
-func (s *TransactionTagService) DeleteTag(c *core.Context, uid int64, tagId int64) error {
+func (s *TransactionTagService) DeleteTag(c *core.Context, uid int64, operatorUid int64, tagId int64) error {
 	if uid <= 0 {
 		return errs.ErrUserIdInvalid
 	}
 
+	err := s.CheckBookMemberRole(c, uid, operatorUid, models.BOOK_MEMBER_ROLE_EDITOR)
+
+	if err != nil {
+		return err
+	}
+
 	now := time.Now().Unix()
 
 	updateModel := &models.TransactionTag{
@@ -273,6 +339,15 @@ func (s *TransactionTagService) DeleteTag(c *core.Context, uid int64, tagId int6
 			return errs.ErrTransactionTagInUseCannotBeDeleted
 		}
 
+		oldTag := &models.TransactionTag{}
+		has, err := sess.ID(tagId).Where("uid=? AND deleted=?", uid, false).Get(oldTag)
+
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrTransactionTagNotFound
+		}
+
 		deletedRows, err := sess.ID(tagId).Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
 
 		if err != nil {
@@ -281,7 +356,7 @@ func (s *TransactionTagService) DeleteTag(c *core.Context, uid int64, tagId int6
 			return errs.ErrTransactionTagNotFound
 		}
 
-		return err
+		return DataRevisions.insertRevision(c, sess, uid, models.DATA_REVISION_OBJECT_TYPE_TAG, tagId, models.DATA_REVISION_ACTION_DELETE, getTagRevisionFields(oldTag), nil)
 	})
 }
 
@@ -15,6 +15,9 @@ import (
 	"github.com/f97/n/pkg/uuid"
 )
 
+const maxTransactionSplitCount = 50
+const maxPurgeTransactionCountPerBatch = 500
+
 // TransactionService represents transaction service
 type TransactionService struct {
 	ServiceUsingDB
@@ -201,13 +204,41 @@ func (s *TransactionService) GetTransactionCount(c *core.Context, uid int64, max
 }
 
 // CreateTransaction saves a new transaction to database
-func (s *TransactionService) CreateTransaction(c *core.Context, transaction *models.Transaction, tagIds []int64) error {
+func (s *TransactionService) CreateTransaction(c *core.Context, operatorUid int64, transaction *models.Transaction, tagIds []int64, splits []*models.TransactionSplit) error {
 	if transaction.Uid <= 0 {
 		return errs.ErrUserIdInvalid
 	}
 
+	err := s.CheckBookMemberRole(c, transaction.Uid, operatorUid, models.BOOK_MEMBER_ROLE_EDITOR)
+
+	if err != nil {
+		return err
+	}
+
+	transaction.CreatedUid = operatorUid
+
+	return s.UserDataDB(transaction.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		return s.createTransaction(c, sess, transaction, tagIds, splits)
+	})
+}
+
+// createTransaction saves a new transaction by the given session, so that it can be a part of another database transaction
+func (s *TransactionService) createTransaction(c *core.Context, sess *xorm.Session, transaction *models.Transaction, tagIds []int64, splits []*models.TransactionSplit) error {
+	if len(splits) > maxTransactionSplitCount {
+		return errs.ErrTransactionSplitsTooMuch
+	}
+
+	// Apply transaction rules
+	rules, err := getAvailableTransactionRules(sess, transaction.Uid)
+
+	if err != nil {
+		return err
+	}
+
+	tagIds, _ = applyTransactionRules(rules, transaction, tagIds, false)
+
 	// Check whether account id is valid
-	err := s.isAccountIdValid(transaction)
+	err = s.isAccountIdValid(transaction)
 
 	if err != nil {
 		return err
@@ -230,6 +261,10 @@ func (s *TransactionService) CreateTransaction(c *core.Context, transaction *mod
 
 	transaction.TransactionTime = utils.GetMinTransactionTimeFromUnixTime(utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime))
 
+	if transaction.CreatedUid <= 0 {
+		transaction.CreatedUid = transaction.Uid
+	}
+
 	transaction.CreatedUnixTime = now
 	transaction.UpdatedUnixTime = now
 
@@ -248,175 +283,213 @@ func (s *TransactionService) CreateTransaction(c *core.Context, transaction *mod
 		}
 	}
 
-	return s.UserDataDB(transaction.Uid).DoTransaction(c, func(sess *xorm.Session) error {
-		// Get and verify source and destination account
-		sourceAccount, destinationAccount, err := s.getAccountModels(sess, transaction)
+	s.prepareTransactionSplits(transaction, splits, now)
 
-		if err != nil {
-			return err
-		}
+	// Get and verify source and destination account
+	sourceAccount, destinationAccount, err := s.getAccountModels(sess, transaction)
 
-		if sourceAccount.Hidden || (destinationAccount != nil && destinationAccount.Hidden) {
-			return errs.ErrCannotAddTransactionToHiddenAccount
-		}
+	if err != nil {
+		return err
+	}
 
-		if (transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN) &&
-			sourceAccount.Currency == destinationAccount.Currency && transaction.Amount != transaction.RelatedAccountAmount {
-			return errs.ErrTransactionSourceAndDestinationAmountNotEqual
-		}
+	if sourceAccount.Hidden || (destinationAccount != nil && destinationAccount.Hidden) {
+		return errs.ErrCannotAddTransactionToHiddenAccount
+	}
 
-		// Get and verify category
-		err = s.isCategoryValid(sess, transaction)
+	if (transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN) &&
+		sourceAccount.Currency == destinationAccount.Currency && transaction.Amount != transaction.RelatedAccountAmount {
+		return errs.ErrTransactionSourceAndDestinationAmountNotEqual
+	}
 
-		if err != nil {
-			return err
-		}
+	// Verify whether transaction is in closed period
+	err = s.isTransactionPeriodOpen(sess, transaction.Uid, transaction)
 
-		// Get and verify tags
-		err = s.isTagsValid(sess, transaction, transactionTagIndexs, tagIds)
+	if err != nil {
+		return err
+	}
+
+	// Get and verify category
+	err = s.isCategoryValid(sess, transaction)
+
+	if err != nil {
+		return err
+	}
+
+	// Get and verify tags
+	err = s.isTagsValid(sess, transaction, transactionTagIndexs, tagIds)
+
+	if err != nil {
+		return err
+	}
+
+	// Verify split lines
+	err = s.isSplitsValid(sess, transaction, splits)
+
+	if err != nil {
+		return err
+	}
+
+	// Verify balance modification transaction and calculate real amount
+	if transaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
+		otherTransactionExists, err := sess.Cols("uid", "deleted", "account_id").Where("uid=? AND deleted=? AND account_id=?", transaction.Uid, false, sourceAccount.AccountId).Limit(1).Exist(&models.Transaction{})
 
 		if err != nil {
 			return err
+		} else if otherTransactionExists {
+			return errs.ErrBalanceModificationTransactionCannotAddWhenNotEmpty
 		}
 
-		// Verify balance modification transaction and calculate real amount
-		if transaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
-			otherTransactionExists, err := sess.Cols("uid", "deleted", "account_id").Where("uid=? AND deleted=? AND account_id=?", transaction.Uid, false, sourceAccount.AccountId).Limit(1).Exist(&models.Transaction{})
+		transaction.RelatedAccountId = transaction.AccountId
+		transaction.RelatedAccountAmount = transaction.Amount - sourceAccount.Balance
+	}
 
-			if err != nil {
-				return err
-			} else if otherTransactionExists {
-				return errs.ErrBalanceModificationTransactionCannotAddWhenNotEmpty
-			}
+	// Insert transaction row
+	var relatedTransaction *models.Transaction
 
-			transaction.RelatedAccountId = transaction.AccountId
-			transaction.RelatedAccountAmount = transaction.Amount - sourceAccount.Balance
-		}
+	if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+		relatedTransaction = s.GetRelatedTransferTransaction(transaction)
+	}
 
-		// Insert transaction row
-		var relatedTransaction *models.Transaction
+	createdRows, err := sess.Insert(transaction)
 
-		if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
-			relatedTransaction = s.GetRelatedTransferTransaction(transaction)
+	if err != nil || createdRows < 1 { // maybe another transaction has same time
+		sameSecondLatestTransaction := &models.Transaction{}
+		minTransactionTime := utils.GetMinTransactionTimeFromUnixTime(utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime))
+		maxTransactionTime := utils.GetMaxTransactionTimeFromUnixTime(utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime))
+
+		has, err := sess.Where("uid=? AND deleted=? AND transaction_time>=? AND transaction_time<=?", transaction.Uid, false, minTransactionTime, maxTransactionTime).OrderBy("transaction_time desc").Limit(1).Get(sameSecondLatestTransaction)
+
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrDatabaseOperationFailed
+		} else if sameSecondLatestTransaction.TransactionTime == maxTransactionTime-1 {
+			return errs.ErrTooMuchTransactionInOneSecond
 		}
 
+		transaction.TransactionTime = sameSecondLatestTransaction.TransactionTime + 1
 		createdRows, err := sess.Insert(transaction)
 
-		if err != nil || createdRows < 1 { // maybe another transaction has same time
-			sameSecondLatestTransaction := &models.Transaction{}
-			minTransactionTime := utils.GetMinTransactionTimeFromUnixTime(utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime))
-			maxTransactionTime := utils.GetMaxTransactionTimeFromUnixTime(utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime))
+		if err != nil {
+			return err
+		} else if createdRows < 1 {
+			return errs.ErrDatabaseOperationFailed
+		}
+	}
 
-			has, err := sess.Where("uid=? AND deleted=? AND transaction_time>=? AND transaction_time<=?", transaction.Uid, false, minTransactionTime, maxTransactionTime).OrderBy("transaction_time desc").Limit(1).Get(sameSecondLatestTransaction)
+	if relatedTransaction != nil {
+		relatedTransaction.TransactionTime = transaction.TransactionTime + 1
 
-			if err != nil {
-				return err
-			} else if !has {
-				return errs.ErrDatabaseOperationFailed
-			} else if sameSecondLatestTransaction.TransactionTime == maxTransactionTime-1 {
-				return errs.ErrTooMuchTransactionInOneSecond
-			}
+		if utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime) != utils.GetUnixTimeFromTransactionTime(relatedTransaction.TransactionTime) {
+			return errs.ErrTooMuchTransactionInOneSecond
+		}
 
-			transaction.TransactionTime = sameSecondLatestTransaction.TransactionTime + 1
-			createdRows, err := sess.Insert(transaction)
+		createdRows, err := sess.Insert(relatedTransaction)
 
-			if err != nil {
-				return err
-			} else if createdRows < 1 {
-				return errs.ErrDatabaseOperationFailed
-			}
+		if err != nil {
+			return err
+		} else if createdRows < 1 {
+			return errs.ErrDatabaseOperationFailed
 		}
+	}
 
-		if relatedTransaction != nil {
-			relatedTransaction.TransactionTime = transaction.TransactionTime + 1
-
-			if utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime) != utils.GetUnixTimeFromTransactionTime(relatedTransaction.TransactionTime) {
-				return errs.ErrTooMuchTransactionInOneSecond
-			}
+	err = nil
 
-			createdRows, err := sess.Insert(relatedTransaction)
+	// Insert transaction tag index
+	if len(transactionTagIndexs) > 0 {
+		for i := 0; i < len(transactionTagIndexs); i++ {
+			transactionTagIndex := transactionTagIndexs[i]
+			_, err := sess.Insert(transactionTagIndex)
 
 			if err != nil {
 				return err
-			} else if createdRows < 1 {
-				return errs.ErrDatabaseOperationFailed
 			}
 		}
+	}
 
-		err = nil
+	// Insert transaction splits
+	err = s.insertTransactionSplits(sess, splits, transaction.Type, transaction.AccountId, transaction.TransactionTime)
 
-		// Insert transaction tag index
-		if len(transactionTagIndexs) > 0 {
-			for i := 0; i < len(transactionTagIndexs); i++ {
-				transactionTagIndex := transactionTagIndexs[i]
-				_, err := sess.Insert(transactionTagIndex)
+	if err != nil {
+		return err
+	}
 
-				if err != nil {
-					return err
-				}
-			}
-		}
+	// Insert revision record
+	err = DataRevisions.insertRevision(c, sess, transaction.Uid, models.DATA_REVISION_OBJECT_TYPE_TRANSACTION, getTransactionRevisionObjectId(transaction), models.DATA_REVISION_ACTION_CREATE, nil, getTransactionRevisionFields(transaction, tagIds, splits))
 
-		// Update account table
-		if transaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
-			sourceAccount.UpdatedUnixTime = time.Now().Unix()
-			updatedRows, err := sess.ID(sourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance+(%d)", transaction.RelatedAccountAmount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", sourceAccount.Uid, false).Update(sourceAccount)
+	if err != nil {
+		return err
+	}
 
-			if err != nil {
-				return err
-			} else if updatedRows < 1 {
-				return errs.ErrDatabaseOperationFailed
-			}
-		} else if transaction.Type == models.TRANSACTION_DB_TYPE_INCOME {
-			sourceAccount.UpdatedUnixTime = time.Now().Unix()
-			updatedRows, err := sess.ID(sourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance+(%d)", transaction.Amount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", sourceAccount.Uid, false).Update(sourceAccount)
+	// Update account table
+	if transaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
+		sourceAccount.UpdatedUnixTime = time.Now().Unix()
+		updatedRows, err := sess.ID(sourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance+(%d)", transaction.RelatedAccountAmount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", sourceAccount.Uid, false).Update(sourceAccount)
 
-			if err != nil {
-				return err
-			} else if updatedRows < 1 {
-				return errs.ErrDatabaseOperationFailed
-			}
-		} else if transaction.Type == models.TRANSACTION_DB_TYPE_EXPENSE {
-			sourceAccount.UpdatedUnixTime = time.Now().Unix()
-			updatedRows, err := sess.ID(sourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance-(%d)", transaction.Amount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", sourceAccount.Uid, false).Update(sourceAccount)
+		if err != nil {
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrDatabaseOperationFailed
+		}
+	} else if transaction.Type == models.TRANSACTION_DB_TYPE_INCOME {
+		sourceAccount.UpdatedUnixTime = time.Now().Unix()
+		updatedRows, err := sess.ID(sourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance+(%d)", transaction.Amount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", sourceAccount.Uid, false).Update(sourceAccount)
 
-			if err != nil {
-				return err
-			} else if updatedRows < 1 {
-				return errs.ErrDatabaseOperationFailed
-			}
-		} else if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
-			sourceAccount.UpdatedUnixTime = time.Now().Unix()
-			updatedSourceRows, err := sess.ID(sourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance-(%d)", transaction.Amount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", sourceAccount.Uid, false).Update(sourceAccount)
+		if err != nil {
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrDatabaseOperationFailed
+		}
+	} else if transaction.Type == models.TRANSACTION_DB_TYPE_EXPENSE {
+		sourceAccount.UpdatedUnixTime = time.Now().Unix()
+		updatedRows, err := sess.ID(sourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance-(%d)", transaction.Amount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", sourceAccount.Uid, false).Update(sourceAccount)
 
-			if err != nil {
-				return err
-			} else if updatedSourceRows < 1 {
-				return errs.ErrDatabaseOperationFailed
-			}
+		if err != nil {
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrDatabaseOperationFailed
+		}
+	} else if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
+		sourceAccount.UpdatedUnixTime = time.Now().Unix()
+		updatedSourceRows, err := sess.ID(sourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance-(%d)", transaction.Amount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", sourceAccount.Uid, false).Update(sourceAccount)
 
-			destinationAccount.UpdatedUnixTime = time.Now().Unix()
-			updatedDestinationRows, err := sess.ID(destinationAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance+(%d)", transaction.RelatedAccountAmount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", destinationAccount.Uid, false).Update(destinationAccount)
+		if err != nil {
+			return err
+		} else if updatedSourceRows < 1 {
+			return errs.ErrDatabaseOperationFailed
+		}
 
-			if err != nil {
-				return err
-			} else if updatedDestinationRows < 1 {
-				return errs.ErrDatabaseOperationFailed
-			}
-		} else if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
-			return errs.ErrTransactionTypeInvalid
+		destinationAccount.UpdatedUnixTime = time.Now().Unix()
+		updatedDestinationRows, err := sess.ID(destinationAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance+(%d)", transaction.RelatedAccountAmount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", destinationAccount.Uid, false).Update(destinationAccount)
+
+		if err != nil {
+			return err
+		} else if updatedDestinationRows < 1 {
+			return errs.ErrDatabaseOperationFailed
 		}
+	} else if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+		return errs.ErrTransactionTypeInvalid
+	}
 
-		return err
-	})
+	return err
 }
 
-// ModifyTransaction saves an existed transaction to database
-func (s *TransactionService) ModifyTransaction(c *core.Context, transaction *models.Transaction, addTagIds []int64, removeTagIds []int64) error {
+// ModifyTransaction saves an existed transaction to database, the split lines are kept unchanged if splits is nil and cleared if splits is empty
+func (s *TransactionService) ModifyTransaction(c *core.Context, operatorUid int64, transaction *models.Transaction, addTagIds []int64, removeTagIds []int64, splits []*models.TransactionSplit) error {
 	if transaction.Uid <= 0 {
 		return errs.ErrUserIdInvalid
 	}
 
+	err := s.CheckBookMemberRole(c, transaction.Uid, operatorUid, models.BOOK_MEMBER_ROLE_EDITOR)
+
+	if err != nil {
+		return err
+	}
+
+	if len(splits) > maxTransactionSplitCount {
+		return errs.ErrTransactionSplitsTooMuch
+	}
+
 	updateCols := make([]string, 0, 16)
 
 	now := time.Now().Unix()
@@ -442,7 +515,14 @@ func (s *TransactionService) ModifyTransaction(c *core.Context, transaction *mod
 		}
 	}
 
-	err := s.UserDataDB(transaction.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+	s.prepareTransactionSplits(transaction, splits, now)
+
+	splitUpdateModel := &models.TransactionSplit{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	err = s.UserDataDB(transaction.Uid).DoTransaction(c, func(sess *xorm.Session) error {
 		// Get and verify current transaction
 		oldTransaction := &models.Transaction{}
 		has, err := sess.ID(transaction.TransactionId).Where("uid=? AND deleted=?", transaction.Uid, false).Get(oldTransaction)
@@ -459,6 +539,12 @@ func (s *TransactionService) ModifyTransaction(c *core.Context, transaction *mod
 			transaction.RelatedId = oldTransaction.RelatedId
 		}
 
+		oldTagIds, oldSplits, err := s.getTransactionTagIdsAndSplits(sess, transaction.Uid, transaction.TransactionId)
+
+		if err != nil {
+			return err
+		}
+
 		// Check whether account id is valid
 		err = s.isAccountIdValid(transaction)
 
@@ -568,6 +654,19 @@ func (s *TransactionService) ModifyTransaction(c *core.Context, transaction *mod
 			updateCols = append(updateCols, "geo_latitude")
 		}
 
+		// Reset cleared status when the fields which affect account balance are modified
+		if s.isBalanceAffectedByUpdateColumns(updateCols) {
+			transaction.Cleared = false
+			updateCols = append(updateCols, "cleared")
+		}
+
+		// Verify whether original and modified transaction are in closed period
+		err = s.isTransactionPeriodOpen(sess, transaction.Uid, oldTransaction, transaction)
+
+		if err != nil {
+			return err
+		}
+
 		// Get and verify tags
 		err = s.isTagsValid(sess, transaction, transactionTagIndexs, addTagIds)
 
@@ -575,6 +674,17 @@ func (s *TransactionService) ModifyTransaction(c *core.Context, transaction *mod
 			return err
 		}
 
+		// Verify split lines
+		if splits != nil {
+			err = s.isSplitsValid(sess, transaction, splits)
+		} else {
+			err = s.isSplitsTotalAmountValid(transaction, oldSplits)
+		}
+
+		if err != nil {
+			return err
+		}
+
 		// Update transaction row
 		updatedRows, err := sess.ID(transaction.TransactionId).Cols(updateCols...).Where("uid=? AND deleted=?", transaction.Uid, false).Update(transaction)
 
@@ -628,6 +738,51 @@ func (s *TransactionService) ModifyTransaction(c *core.Context, transaction *mod
 			}
 		}
 
+		// Update transaction splits
+		transactionTime := oldTransaction.TransactionTime
+
+		if utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime) != utils.GetUnixTimeFromTransactionTime(oldTransaction.TransactionTime) {
+			transactionTime = transaction.TransactionTime
+		}
+
+		newSplits := splits
+
+		if splits != nil {
+			_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=? AND transaction_id=?", transaction.Uid, false, transaction.TransactionId).Update(splitUpdateModel)
+
+			if err != nil {
+				return err
+			}
+
+			err = s.insertTransactionSplits(sess, splits, transaction.Type, transaction.AccountId, transactionTime)
+
+			if err != nil {
+				return err
+			}
+		} else if len(oldSplits) > 0 {
+			newSplits = oldSplits
+			splitTimeUpdateModel := &models.TransactionSplit{
+				AccountId:       transaction.AccountId,
+				TransactionTime: transactionTime,
+				UpdatedUnixTime: now,
+			}
+
+			_, err = sess.Cols("account_id", "transaction_time", "updated_unix_time").Where("uid=? AND deleted=? AND transaction_id=?", transaction.Uid, false, transaction.TransactionId).Update(splitTimeUpdateModel)
+
+			if err != nil {
+				return err
+			}
+		}
+
+		// Insert revision record
+		newTagIds := utils.ToUniqueInt64Slice(append(utils.Int64SliceMinus(oldTagIds, removeTagIds), addTagIds...))
+
+		err = DataRevisions.insertRevision(c, sess, transaction.Uid, models.DATA_REVISION_OBJECT_TYPE_TRANSACTION, getTransactionRevisionObjectId(transaction), models.DATA_REVISION_ACTION_MODIFY, getTransactionRevisionFields(oldTransaction, oldTagIds, oldSplits), getTransactionRevisionFields(transaction, newTagIds, newSplits))
+
+		if err != nil {
+			return err
+		}
+
 		// Update account table
 		if oldTransaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
 			if transaction.AccountId != oldTransaction.AccountId {
@@ -782,12 +937,19 @@ func (s *TransactionService) ModifyTransaction(c *core.Context, transaction *mod
 	return nil
 }
 
-// DeleteTransaction deletes an existed transaction from database
-func (s *TransactionService) DeleteTransaction(c *core.Context, uid int64, transactionId int64) error {
+// DeleteTransaction deletes an existed transaction from database, the deleted transaction stays in trash bin and can be restored until it is purged,
+// so the attachment objects of the transaction are kept in object storage and are removed by PurgeTrashTransactions
+func (s *TransactionService) DeleteTransaction(c *core.Context, uid int64, operatorUid int64, transactionId int64) error {
 	if uid <= 0 {
 		return errs.ErrUserIdInvalid
 	}
 
+	err := s.CheckBookMemberRole(c, uid, operatorUid, models.BOOK_MEMBER_ROLE_EDITOR)
+
+	if err != nil {
+		return err
+	}
+
 	now := time.Now().Unix()
 
 	updateModel := &models.Transaction{
@@ -800,15 +962,30 @@ func (s *TransactionService) DeleteTransaction(c *core.Context, uid int64, trans
 		DeletedUnixTime: now,
 	}
 
-	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
-		// Get and verify current transaction
-		oldTransaction := &models.Transaction{}
-		has, err := sess.ID(transactionId).Where("uid=? AND deleted=?", uid, false).Get(oldTransaction)
+	externalIndexUpdateModel := &models.TransactionExternalIndex{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
 
-		if err != nil {
-			return err
-		} else if !has {
-			return errs.ErrTransactionNotFound
+	splitUpdateModel := &models.TransactionSplit{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	attachmentUpdateModel := &models.TransactionAttachment{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		// Get and verify current transaction
+		oldTransaction := &models.Transaction{}
+		has, err := sess.ID(transactionId).Where("uid=? AND deleted=?", uid, false).Get(oldTransaction)
+
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrTransactionNotFound
 		}
 
 		// Get and verify source and destination account
@@ -822,6 +999,19 @@ func (s *TransactionService) DeleteTransaction(c *core.Context, uid int64, trans
 			return errs.ErrCannotDeleteTransactionInHiddenAccount
 		}
 
+		// Verify whether transaction is in closed period
+		err = s.isTransactionPeriodOpen(sess, uid, oldTransaction)
+
+		if err != nil {
+			return err
+		}
+
+		oldTagIds, oldSplits, err := s.getTransactionTagIdsAndSplits(sess, uid, oldTransaction.TransactionId)
+
+		if err != nil {
+			return err
+		}
+
 		// Update transaction row to deleted
 		deletedRows, err := sess.ID(oldTransaction.TransactionId).Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
 
@@ -831,18 +1021,454 @@ func (s *TransactionService) DeleteTransaction(c *core.Context, uid int64, trans
 			return errs.ErrTransactionNotFound
 		}
 
-		if oldTransaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || oldTransaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
-			deletedRows, err = sess.ID(oldTransaction.RelatedId).Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+		if oldTransaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || oldTransaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+			deletedRows, err = sess.ID(oldTransaction.RelatedId).Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+
+			if err != nil {
+				return err
+			} else if deletedRows < 1 {
+				return errs.ErrTransactionNotFound
+			}
+		}
+
+		// Update transaction tag index
+		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=? AND transaction_id=?", uid, false, oldTransaction.TransactionId).Update(tagIndexUpdateModel)
+
+		if err != nil {
+			return err
+		}
+
+		// Update transaction external index
+		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=? AND transaction_id=?", uid, false, oldTransaction.TransactionId).Update(externalIndexUpdateModel)
+
+		if err != nil {
+			return err
+		}
+
+		// Update transaction splits
+		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=? AND transaction_id=?", uid, false, oldTransaction.TransactionId).Update(splitUpdateModel)
+
+		if err != nil {
+			return err
+		}
+
+		// Update transaction attachments to deleted, the attachment objects are removed when the transaction is purged from trash bin
+		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=? AND transaction_id=?", uid, false, oldTransaction.TransactionId).Update(attachmentUpdateModel)
+
+		if err != nil {
+			return err
+		}
+
+		// Insert revision record
+		err = DataRevisions.insertRevision(c, sess, uid, models.DATA_REVISION_OBJECT_TYPE_TRANSACTION, getTransactionRevisionObjectId(oldTransaction), models.DATA_REVISION_ACTION_DELETE, getTransactionRevisionFields(oldTransaction, oldTagIds, oldSplits), nil)
+
+		if err != nil {
+			return err
+		}
+
+		// Update account table
+		if oldTransaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
+			sourceAccount.UpdatedUnixTime = time.Now().Unix()
+			updatedRows, err := sess.ID(sourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance-(%d)", oldTransaction.RelatedAccountAmount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", sourceAccount.Uid, false).Update(sourceAccount)
+
+			if err != nil {
+				return err
+			} else if updatedRows < 1 {
+				return errs.ErrDatabaseOperationFailed
+			}
+		} else if oldTransaction.Type == models.TRANSACTION_DB_TYPE_INCOME {
+			sourceAccount.UpdatedUnixTime = time.Now().Unix()
+			updatedRows, err := sess.ID(sourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance-(%d)", oldTransaction.Amount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", sourceAccount.Uid, false).Update(sourceAccount)
+
+			if err != nil {
+				return err
+			} else if updatedRows < 1 {
+				return errs.ErrDatabaseOperationFailed
+			}
+		} else if oldTransaction.Type == models.TRANSACTION_DB_TYPE_EXPENSE {
+			sourceAccount.UpdatedUnixTime = time.Now().Unix()
+			updatedRows, err := sess.ID(sourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance+(%d)", oldTransaction.Amount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", sourceAccount.Uid, false).Update(sourceAccount)
+
+			if err != nil {
+				return err
+			} else if updatedRows < 1 {
+				return errs.ErrDatabaseOperationFailed
+			}
+		} else if oldTransaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
+			sourceAccount.UpdatedUnixTime = time.Now().Unix()
+			updatedSourceRows, err := sess.ID(sourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance+(%d)", oldTransaction.Amount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", sourceAccount.Uid, false).Update(sourceAccount)
+
+			if err != nil {
+				return err
+			} else if updatedSourceRows < 1 {
+				return errs.ErrDatabaseOperationFailed
+			}
+
+			destinationAccount.UpdatedUnixTime = time.Now().Unix()
+			updatedDestinationRows, err := sess.ID(destinationAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance-(%d)", oldTransaction.RelatedAccountAmount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", destinationAccount.Uid, false).Update(destinationAccount)
+
+			if err != nil {
+				return err
+			} else if updatedDestinationRows < 1 {
+				return errs.ErrDatabaseOperationFailed
+			}
+		} else if oldTransaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+			return errs.ErrTransactionTypeInvalid
+		}
+
+		return err
+	})
+}
+
+// DeleteAllTransactions deletes all existed transactions from database, the deleted transactions stay in trash bin,
+// so their attachment objects are kept in object storage and are removed by PurgeTrashTransactions
+func (s *TransactionService) DeleteAllTransactions(c *core.Context, uid int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.Transaction{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	tagIndexUpdateModel := &models.TransactionTagIndex{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	externalIndexUpdateModel := &models.TransactionExternalIndex{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	splitUpdateModel := &models.TransactionSplit{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	attachmentUpdateModel := &models.TransactionAttachment{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	accountUpdateModel := &models.Account{
+		Balance:         0,
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		// Update all transaction to deleted
+		_, err := sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+
+		if err != nil {
+			return err
+		}
+
+		// Update all transaction tag index to deleted
+		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(tagIndexUpdateModel)
+
+		if err != nil {
+			return err
+		}
+
+		// Update all transaction external index to deleted
+		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(externalIndexUpdateModel)
+
+		if err != nil {
+			return err
+		}
+
+		// Update all transaction splits to deleted
+		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(splitUpdateModel)
+
+		if err != nil {
+			return err
+		}
+
+		// Update all transaction attachments to deleted, the attachment objects are removed when the transactions are purged from trash bin
+		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(attachmentUpdateModel)
+
+		if err != nil {
+			return err
+		}
+
+		// Update all account table to deleted
+		_, err = sess.Cols("balance", "deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(accountUpdateModel)
+
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+// GetTrashTransactionCount returns total count of deleted transactions in trash bin which are deleted after the specified unix time
+func (s *TransactionService) GetTrashTransactionCount(c *core.Context, uid int64, minDeletedUnixTime int64) (int64, error) {
+	if uid <= 0 {
+		return 0, errs.ErrUserIdInvalid
+	}
+
+	condition, conditionParams := s.getTrashTransactionQueryCondition(uid, minDeletedUnixTime)
+
+	return s.UserDataDB(uid).NewSession(c).Where(condition, conditionParams...).Count(&models.Transaction{})
+}
+
+// GetTrashTransactionsByPage returns deleted transactions in trash bin which are deleted after the specified unix time, the latest deleted transactions are returned first
+func (s *TransactionService) GetTrashTransactionsByPage(c *core.Context, uid int64, minDeletedUnixTime int64, page int32, count int32) ([]*models.Transaction, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if page <= 0 {
+		return nil, errs.ErrPageIndexInvalid
+	}
+
+	if count < 1 {
+		return nil, errs.ErrPageCountInvalid
+	}
+
+	condition, conditionParams := s.getTrashTransactionQueryCondition(uid, minDeletedUnixTime)
+
+	var transactions []*models.Transaction
+	err := s.UserDataDB(uid).NewSession(c).Where(condition, conditionParams...).Limit(int(count), int(count*(page-1))).OrderBy("deleted_unix_time desc, transaction_time desc").Find(&transactions)
+
+	return transactions, err
+}
+
+// GetTrashTransactionByTransactionId returns a deleted transaction model in trash bin according to transaction id
+func (s *TransactionService) GetTrashTransactionByTransactionId(c *core.Context, uid int64, transactionId int64, minDeletedUnixTime int64) (*models.Transaction, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if transactionId <= 0 {
+		return nil, errs.ErrTransactionIdInvalid
+	}
+
+	condition, conditionParams := s.getTrashTransactionQueryCondition(uid, minDeletedUnixTime)
+
+	transaction := &models.Transaction{}
+	has, err := s.UserDataDB(uid).NewSession(c).ID(transactionId).Where(condition, conditionParams...).Get(transaction)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrTransactionNotFound
+	}
+
+	return transaction, nil
+}
+
+// RestoreTransaction restores a deleted transaction in trash bin, the balance of accounts, the related transfer transaction, tags, external ids, split lines and attachments which are deleted with the transaction are restored as well
+func (s *TransactionService) RestoreTransaction(c *core.Context, uid int64, operatorUid int64, transactionId int64, minDeletedUnixTime int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	err := s.CheckBookMemberRole(c, uid, operatorUid, models.BOOK_MEMBER_ROLE_EDITOR)
+
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().Unix()
+
+	restoreModel := &models.Transaction{
+		Deleted:         false,
+		DeletedUnixTime: 0,
+		UpdatedUnixTime: now,
+	}
+
+	tagIndexRestoreModel := &models.TransactionTagIndex{
+		Deleted:         false,
+		DeletedUnixTime: 0,
+		UpdatedUnixTime: now,
+	}
+
+	externalIndexRestoreModel := &models.TransactionExternalIndex{
+		Deleted:         false,
+		DeletedUnixTime: 0,
+		UpdatedUnixTime: now,
+	}
+
+	splitRestoreModel := &models.TransactionSplit{
+		Deleted:         false,
+		DeletedUnixTime: 0,
+		UpdatedUnixTime: now,
+	}
+
+	attachmentRestoreModel := &models.TransactionAttachment{
+		Deleted:         false,
+		DeletedUnixTime: 0,
+		UpdatedUnixTime: now,
+	}
+
+	condition, conditionParams := s.getTrashTransactionQueryCondition(uid, minDeletedUnixTime)
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		// Get and verify deleted transaction
+		oldTransaction := &models.Transaction{}
+		has, err := sess.ID(transactionId).Where(condition, conditionParams...).Get(oldTransaction)
+
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrTransactionNotFound
+		}
+
+		deletedUnixTime := oldTransaction.DeletedUnixTime
+
+		// Get and verify source and destination account
+		sourceAccount, destinationAccount, err := s.getAccountModels(sess, oldTransaction)
+
+		if err != nil {
+			return err
+		}
+
+		if sourceAccount.Hidden || (destinationAccount != nil && destinationAccount.Hidden) {
+			return errs.ErrCannotAddTransactionToHiddenAccount
+		}
+
+		// Verify whether transaction is in closed period
+		err = s.isTransactionPeriodOpen(sess, uid, oldTransaction)
+
+		if err != nil {
+			return err
+		}
+
+		// Get and verify category
+		err = s.isCategoryValid(sess, oldTransaction)
+
+		if err != nil {
+			return err
+		}
+
+		if oldTransaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
+			otherTransactionExists, err := sess.Cols("uid", "deleted", "account_id").Where("uid=? AND deleted=? AND account_id=?", uid, false, sourceAccount.AccountId).Limit(1).Exist(&models.Transaction{})
+
+			if err != nil {
+				return err
+			} else if otherTransactionExists {
+				return errs.ErrBalanceModificationTransactionCannotAddWhenNotEmpty
+			}
+		}
+
+		// Update transaction row to not deleted
+		restoredRows, err := sess.ID(oldTransaction.TransactionId).Cols("deleted", "deleted_unix_time", "updated_unix_time").Where("uid=? AND deleted=?", uid, true).Update(restoreModel)
+
+		if err != nil {
+			return err
+		} else if restoredRows < 1 {
+			return errs.ErrTransactionNotFound
+		}
+
+		if oldTransaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
+			restoredRows, err = sess.ID(oldTransaction.RelatedId).Cols("deleted", "deleted_unix_time", "updated_unix_time").Where("uid=? AND deleted=? AND deleted_unix_time=?", uid, true, deletedUnixTime).Update(restoreModel)
+
+			if err != nil {
+				return err
+			} else if restoredRows < 1 {
+				return errs.ErrTransactionNotFound
+			}
+		}
+
+		// Restore transaction tag index whose tag still exists
+		var tagIndexs []*models.TransactionTagIndex
+		err = sess.Where("uid=? AND deleted=? AND transaction_id=? AND deleted_unix_time=?", uid, true, oldTransaction.TransactionId, deletedUnixTime).Find(&tagIndexs)
+
+		if err != nil {
+			return err
+		}
+
+		if len(tagIndexs) > 0 {
+			tagIds := make([]int64, len(tagIndexs))
+
+			for i := 0; i < len(tagIndexs); i++ {
+				tagIds[i] = tagIndexs[i].TagId
+			}
+
+			var tags []*models.TransactionTag
+			err = sess.Cols("tag_id").Where("uid=? AND deleted=?", uid, false).In("tag_id", utils.ToUniqueInt64Slice(tagIds)).Find(&tags)
+
+			if err != nil {
+				return err
+			}
+
+			existedTagIds := make(map[int64]bool, len(tags))
+
+			for i := 0; i < len(tags); i++ {
+				existedTagIds[tags[i].TagId] = true
+			}
+
+			tagIndexIds := make([]int64, 0, len(tagIndexs))
+
+			for i := 0; i < len(tagIndexs); i++ {
+				if existedTagIds[tagIndexs[i].TagId] {
+					tagIndexIds = append(tagIndexIds, tagIndexs[i].TagIndexId)
+				}
+			}
+
+			if len(tagIndexIds) > 0 {
+				_, err = sess.Cols("deleted", "deleted_unix_time", "updated_unix_time").Where("uid=? AND deleted=?", uid, true).In("tag_index_id", tagIndexIds).Update(tagIndexRestoreModel)
+
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		// Restore transaction external index whose external id has not been imported again
+		var externalIndexes []*models.TransactionExternalIndex
+		err = sess.Where("uid=? AND deleted=? AND transaction_id=? AND deleted_unix_time=?", uid, true, oldTransaction.TransactionId, deletedUnixTime).Find(&externalIndexes)
+
+		if err != nil {
+			return err
+		}
+
+		for i := 0; i < len(externalIndexes); i++ {
+			externalIndex := externalIndexes[i]
+			exists, err := sess.Cols("uid", "deleted", "account_id", "external_id").Where("uid=? AND deleted=? AND account_id=? AND external_id=?", uid, false, externalIndex.AccountId, externalIndex.ExternalId).Exist(&models.TransactionExternalIndex{})
+
+			if err != nil {
+				return err
+			} else if exists {
+				continue
+			}
+
+			_, err = sess.ID(externalIndex.ExternalIndexId).Cols("deleted", "deleted_unix_time", "updated_unix_time").Where("uid=? AND deleted=?", uid, true).Update(externalIndexRestoreModel)
+
+			if err != nil {
+				return err
+			}
+		}
+
+		// Restore transaction splits
+		_, err = sess.Cols("deleted", "deleted_unix_time", "updated_unix_time").Where("uid=? AND deleted=? AND transaction_id=? AND deleted_unix_time=?", uid, true, oldTransaction.TransactionId, deletedUnixTime).Update(splitRestoreModel)
+
+		if err != nil {
+			return err
+		}
+
+		// Restore transaction attachments
+		_, err = sess.Cols("deleted", "deleted_unix_time", "updated_unix_time").Where("uid=? AND deleted=? AND transaction_id=? AND deleted_unix_time=?", uid, true, oldTransaction.TransactionId, deletedUnixTime).Update(attachmentRestoreModel)
+
+		if err != nil {
+			return err
+		}
+
+		// Insert revision record
+		restoredTagIds, restoredSplits, err := s.getTransactionTagIdsAndSplits(sess, uid, oldTransaction.TransactionId)
 
-			if err != nil {
-				return err
-			} else if deletedRows < 1 {
-				return errs.ErrTransactionNotFound
-			}
+		if err != nil {
+			return err
 		}
 
-		// Update transaction tag index
-		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=? AND transaction_id=?", uid, false, oldTransaction.TransactionId).Update(tagIndexUpdateModel)
+		err = DataRevisions.insertRevision(c, sess, uid, models.DATA_REVISION_OBJECT_TYPE_TRANSACTION, getTransactionRevisionObjectId(oldTransaction), models.DATA_REVISION_ACTION_RESTORE, nil, getTransactionRevisionFields(oldTransaction, restoredTagIds, restoredSplits))
 
 		if err != nil {
 			return err
@@ -851,7 +1477,7 @@ func (s *TransactionService) DeleteTransaction(c *core.Context, uid int64, trans
 		// Update account table
 		if oldTransaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
 			sourceAccount.UpdatedUnixTime = time.Now().Unix()
-			updatedRows, err := sess.ID(sourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance-(%d)", oldTransaction.RelatedAccountAmount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", sourceAccount.Uid, false).Update(sourceAccount)
+			updatedRows, err := sess.ID(sourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance+(%d)", oldTransaction.RelatedAccountAmount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", sourceAccount.Uid, false).Update(sourceAccount)
 
 			if err != nil {
 				return err
@@ -860,7 +1486,7 @@ func (s *TransactionService) DeleteTransaction(c *core.Context, uid int64, trans
 			}
 		} else if oldTransaction.Type == models.TRANSACTION_DB_TYPE_INCOME {
 			sourceAccount.UpdatedUnixTime = time.Now().Unix()
-			updatedRows, err := sess.ID(sourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance-(%d)", oldTransaction.Amount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", sourceAccount.Uid, false).Update(sourceAccount)
+			updatedRows, err := sess.ID(sourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance+(%d)", oldTransaction.Amount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", sourceAccount.Uid, false).Update(sourceAccount)
 
 			if err != nil {
 				return err
@@ -869,7 +1495,7 @@ func (s *TransactionService) DeleteTransaction(c *core.Context, uid int64, trans
 			}
 		} else if oldTransaction.Type == models.TRANSACTION_DB_TYPE_EXPENSE {
 			sourceAccount.UpdatedUnixTime = time.Now().Unix()
-			updatedRows, err := sess.ID(sourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance+(%d)", oldTransaction.Amount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", sourceAccount.Uid, false).Update(sourceAccount)
+			updatedRows, err := sess.ID(sourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance-(%d)", oldTransaction.Amount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", sourceAccount.Uid, false).Update(sourceAccount)
 
 			if err != nil {
 				return err
@@ -878,7 +1504,7 @@ func (s *TransactionService) DeleteTransaction(c *core.Context, uid int64, trans
 			}
 		} else if oldTransaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
 			sourceAccount.UpdatedUnixTime = time.Now().Unix()
-			updatedSourceRows, err := sess.ID(sourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance+(%d)", oldTransaction.Amount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", sourceAccount.Uid, false).Update(sourceAccount)
+			updatedSourceRows, err := sess.ID(sourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance-(%d)", oldTransaction.Amount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", sourceAccount.Uid, false).Update(sourceAccount)
 
 			if err != nil {
 				return err
@@ -887,69 +1513,128 @@ func (s *TransactionService) DeleteTransaction(c *core.Context, uid int64, trans
 			}
 
 			destinationAccount.UpdatedUnixTime = time.Now().Unix()
-			updatedDestinationRows, err := sess.ID(destinationAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance-(%d)", oldTransaction.RelatedAccountAmount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", destinationAccount.Uid, false).Update(destinationAccount)
+			updatedDestinationRows, err := sess.ID(destinationAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance+(%d)", oldTransaction.RelatedAccountAmount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", destinationAccount.Uid, false).Update(destinationAccount)
 
 			if err != nil {
 				return err
 			} else if updatedDestinationRows < 1 {
 				return errs.ErrDatabaseOperationFailed
 			}
-		} else if oldTransaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
-			return errs.ErrTransactionTypeInvalid
 		}
 
-		return err
+		return nil
 	})
 }
 
-// DeleteAllTransactions deletes all existed transactions from database
-func (s *TransactionService) DeleteAllTransactions(c *core.Context, uid int64) error {
+// PurgeTrashTransactions permanently deletes the deleted transactions and their tags, external ids, split lines and attachments from database,
+// and removes the attachment objects from object storage after the database transaction is committed,
+// only the specified transaction is purged if transactionId is greater than 0, and only the transactions deleted before maxDeletedUnixTime are purged if it is greater than 0
+func (s *TransactionService) PurgeTrashTransactions(c *core.Context, uid int64, operatorUid int64, transactionId int64, maxDeletedUnixTime int64) (int, error) {
 	if uid <= 0 {
-		return errs.ErrUserIdInvalid
+		return 0, errs.ErrUserIdInvalid
 	}
 
-	now := time.Now().Unix()
+	err := s.CheckBookMemberRole(c, uid, operatorUid, models.BOOK_MEMBER_ROLE_EDITOR)
 
-	updateModel := &models.Transaction{
-		Deleted:         true,
-		DeletedUnixTime: now,
+	if err != nil {
+		return 0, err
 	}
 
-	tagIndexUpdateModel := &models.TransactionTagIndex{
-		Deleted:         true,
-		DeletedUnixTime: now,
-	}
+	var attachments []*models.TransactionAttachment
+	purgedCount := 0
 
-	accountUpdateModel := &models.Account{
-		Balance:         0,
-		Deleted:         true,
-		DeletedUnixTime: now,
-	}
+	err = s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		var transactions []*models.Transaction
+		var err error
 
-	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
-		// Update all transaction to deleted
-		_, err := sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+		if transactionId > 0 {
+			err = sess.Cols("transaction_id", "type", "related_id").Where("uid=? AND deleted=? AND transaction_id=?", uid, true, transactionId).Find(&transactions)
+		} else if maxDeletedUnixTime > 0 {
+			err = sess.Cols("transaction_id", "type", "related_id").Where("uid=? AND deleted=? AND deleted_unix_time<?", uid, true, maxDeletedUnixTime).Find(&transactions)
+		} else {
+			err = sess.Cols("transaction_id", "type", "related_id").Where("uid=? AND deleted=?", uid, true).Find(&transactions)
+		}
 
 		if err != nil {
 			return err
+		} else if transactionId > 0 && len(transactions) < 1 {
+			return errs.ErrTransactionNotFound
 		}
 
-		// Update all transaction tag index to deleted
-		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(tagIndexUpdateModel)
+		transactionIds := make([]int64, 0, len(transactions)+1)
 
-		if err != nil {
-			return err
+		for i := 0; i < len(transactions); i++ {
+			transaction := transactions[i]
+			transactionIds = append(transactionIds, transaction.TransactionId)
+
+			if transactionId > 0 && (transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN) {
+				transactionIds = append(transactionIds, transaction.RelatedId)
+			}
+
+			if transaction.Type != models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+				purgedCount++
+			}
 		}
 
-		// Update all account table to deleted
-		_, err = sess.Cols("balance", "deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(accountUpdateModel)
+		for i := 0; i < len(transactionIds); i += maxPurgeTransactionCountPerBatch {
+			batchEndIndex := i + maxPurgeTransactionCountPerBatch
 
-		if err != nil {
-			return err
+			if batchEndIndex > len(transactionIds) {
+				batchEndIndex = len(transactionIds)
+			}
+
+			batchTransactionIds := transactionIds[i:batchEndIndex]
+
+			var batchAttachments []*models.TransactionAttachment
+			err = sess.Where("uid=? AND deleted=?", uid, true).In("transaction_id", batchTransactionIds).Find(&batchAttachments)
+
+			if err != nil {
+				return err
+			}
+
+			attachments = append(attachments, batchAttachments...)
+
+			_, err = sess.Where("uid=? AND deleted=?", uid, true).In("transaction_id", batchTransactionIds).Delete(&models.TransactionAttachment{})
+
+			if err != nil {
+				return err
+			}
+
+			_, err = sess.Where("uid=? AND deleted=?", uid, true).In("transaction_id", batchTransactionIds).Delete(&models.TransactionSplit{})
+
+			if err != nil {
+				return err
+			}
+
+			_, err = sess.Where("uid=? AND deleted=?", uid, true).In("transaction_id", batchTransactionIds).Delete(&models.TransactionExternalIndex{})
+
+			if err != nil {
+				return err
+			}
+
+			_, err = sess.Where("uid=? AND deleted=?", uid, true).In("transaction_id", batchTransactionIds).Delete(&models.TransactionTagIndex{})
+
+			if err != nil {
+				return err
+			}
+
+			_, err = sess.Where("uid=? AND deleted=?", uid, true).In("transaction_id", batchTransactionIds).Delete(&models.Transaction{})
+
+			if err != nil {
+				return err
+			}
 		}
 
 		return nil
 	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	deleteTransactionAttachmentObjects(c, attachments)
+
+	return purgedCount, nil
 }
 
 // GetRelatedTransferTransaction returns the related transaction for transfer transaction
@@ -983,6 +1668,7 @@ func (s *TransactionService) GetRelatedTransferTransaction(originalTransaction *
 		Comment:              originalTransaction.Comment,
 		GeoLongitude:         originalTransaction.GeoLongitude,
 		GeoLatitude:          originalTransaction.GeoLatitude,
+		CreatedUid:           originalTransaction.CreatedUid,
 		CreatedIp:            originalTransaction.CreatedIp,
 		CreatedUnixTime:      originalTransaction.CreatedUnixTime,
 		UpdatedUnixTime:      originalTransaction.UpdatedUnixTime,
@@ -1093,6 +1779,19 @@ func (s *TransactionService) GetAccountsMonthTotalIncomeAndExpense(c *core.Conte
 
 // GetAccountsAndCategoriesTotalIncomeAndExpense returns the every accounts and categories total income and expense amount by specific date range
 func (s *TransactionService) GetAccountsAndCategoriesTotalIncomeAndExpense(c *core.Context, uid int64, startUnixTime int64, endUnixTime int64) ([]*models.Transaction, error) {
+	return s.getAccountsAndCategoriesTotalIncomeAndExpense(c, uid, 0, startUnixTime, endUnixTime)
+}
+
+// GetAccountsAndCategoriesTotalIncomeAndExpenseByTag returns the every accounts and categories total income and expense amount of transactions which have specific tag by specific date range
+func (s *TransactionService) GetAccountsAndCategoriesTotalIncomeAndExpenseByTag(c *core.Context, uid int64, tagId int64, startUnixTime int64, endUnixTime int64) ([]*models.Transaction, error) {
+	if tagId <= 0 {
+		return nil, errs.ErrTransactionTagIdInvalid
+	}
+
+	return s.getAccountsAndCategoriesTotalIncomeAndExpense(c, uid, tagId, startUnixTime, endUnixTime)
+}
+
+func (s *TransactionService) getAccountsAndCategoriesTotalIncomeAndExpense(c *core.Context, uid int64, tagId int64, startUnixTime int64, endUnixTime int64) ([]*models.Transaction, error) {
 	if uid <= 0 {
 		return nil, errs.ErrUserIdInvalid
 	}
@@ -1114,16 +1813,117 @@ func (s *TransactionService) GetAccountsAndCategoriesTotalIncomeAndExpense(c *co
 		conditionParams = append(conditionParams, utils.GetMaxTransactionTimeFromUnixTime(endUnixTime))
 	}
 
+	// The amounts of split transactions are counted by their split lines instead
+	transactionCondition := condition + " AND transaction_id NOT IN (SELECT transaction_id FROM transaction_split WHERE uid=? AND deleted=?)"
+	transactionConditionParams := make([]interface{}, 0, len(conditionParams)+5)
+	transactionConditionParams = append(transactionConditionParams, conditionParams...)
+	transactionConditionParams = append(transactionConditionParams, uid, false)
+
+	if tagId > 0 {
+		transactionCondition = transactionCondition + " AND transaction_id IN (SELECT transaction_id FROM transaction_tag_index WHERE uid=? AND deleted=? AND tag_id=?)"
+		transactionConditionParams = append(transactionConditionParams, uid, false, tagId)
+	}
+
 	var transactionTotalAmounts []*models.Transaction
-	err := s.UserDataDB(uid).NewSession(c).Select("category_id, account_id, SUM(amount) as amount").Where(condition, conditionParams...).GroupBy("category_id, account_id").Find(&transactionTotalAmounts)
+	err := s.UserDataDB(uid).NewSession(c).Select("category_id, account_id, SUM(amount) as amount").Where(transactionCondition, transactionConditionParams...).GroupBy("category_id, account_id").Find(&transactionTotalAmounts)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var splitTotalAmounts []*models.TransactionSplit
+
+	if tagId > 0 {
+		// Split lines have their own tags, which are stored in split line rows instead of tag index
+		splitTotalAmounts, err = s.getSplitTotalAmountsByTag(c, uid, tagId, condition, conditionParams)
+	} else {
+		err = s.UserDataDB(uid).NewSession(c).Select("category_id, account_id, SUM(amount) as amount").Where(condition, conditionParams...).GroupBy("category_id, account_id").Find(&splitTotalAmounts)
+	}
 
 	if err != nil {
 		return nil, err
 	}
 
+	for i := 0; i < len(splitTotalAmounts); i++ {
+		transactionTotalAmounts = append(transactionTotalAmounts, &models.Transaction{
+			CategoryId: splitTotalAmounts[i].CategoryId,
+			AccountId:  splitTotalAmounts[i].AccountId,
+			Amount:     splitTotalAmounts[i].Amount,
+		})
+	}
+
 	return transactionTotalAmounts, nil
 }
 
+func (s *TransactionService) getSplitTotalAmountsByTag(c *core.Context, uid int64, tagId int64, condition string, conditionParams []interface{}) ([]*models.TransactionSplit, error) {
+	var splits []*models.TransactionSplit
+	err := s.UserDataDB(uid).NewSession(c).Select("category_id, account_id, amount, tag_ids").Where(condition, conditionParams...).Find(&splits)
+
+	if err != nil {
+		return nil, err
+	}
+
+	splitTotalAmountMap := make(map[string]*models.TransactionSplit)
+	splitTotalAmounts := make([]*models.TransactionSplit, 0)
+
+	for i := 0; i < len(splits); i++ {
+		split := splits[i]
+		splitTagIds := split.GetTagIds()
+		hasTag := false
+
+		for j := 0; j < len(splitTagIds); j++ {
+			if splitTagIds[j] == tagId {
+				hasTag = true
+				break
+			}
+		}
+
+		if !hasTag {
+			continue
+		}
+
+		key := fmt.Sprintf("%d_%d", split.CategoryId, split.AccountId)
+		splitTotalAmount, exists := splitTotalAmountMap[key]
+
+		if !exists {
+			splitTotalAmount = &models.TransactionSplit{
+				CategoryId: split.CategoryId,
+				AccountId:  split.AccountId,
+			}
+
+			splitTotalAmountMap[key] = splitTotalAmount
+			splitTotalAmounts = append(splitTotalAmounts, splitTotalAmount)
+		}
+
+		splitTotalAmount.Amount += split.Amount
+	}
+
+	return splitTotalAmounts, nil
+}
+
+// GetAllSplitsOfTransactions returns transaction split lines for given transactions
+func (s *TransactionService) GetAllSplitsOfTransactions(c *core.Context, uid int64, transactionIds []int64) (map[int64][]*models.TransactionSplit, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	var splits []*models.TransactionSplit
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false).In("transaction_id", transactionIds).OrderBy("display_order asc").Find(&splits)
+
+	if err != nil {
+		return nil, err
+	}
+
+	allTransactionSplits := make(map[int64][]*models.TransactionSplit)
+
+	for i := 0; i < len(splits); i++ {
+		split := splits[i]
+		allTransactionSplits[split.TransactionId] = append(allTransactionSplits[split.TransactionId], split)
+	}
+
+	return allTransactionSplits, nil
+}
+
 // GetTransactionMapByList returns a transaction map by a list
 func (s *TransactionService) GetTransactionMapByList(transactions []*models.Transaction) map[int64]*models.Transaction {
 	transactionMap := make(map[int64]*models.Transaction)
@@ -1212,6 +2012,26 @@ func (s *TransactionService) getTransactionQueryCondition(uid int64, maxTransact
 	return condition, conditionParams
 }
 
+func (s *TransactionService) getTrashTransactionQueryCondition(uid int64, minDeletedUnixTime int64) (string, []interface{}) {
+	condition := "uid=? AND deleted=? AND (type=? OR type=? OR type=? OR type=?) AND account_id IN (SELECT account_id FROM account WHERE uid=? AND deleted=?)"
+	conditionParams := make([]interface{}, 0, 8)
+	conditionParams = append(conditionParams, uid)
+	conditionParams = append(conditionParams, true)
+	conditionParams = append(conditionParams, models.TRANSACTION_DB_TYPE_MODIFY_BALANCE)
+	conditionParams = append(conditionParams, models.TRANSACTION_DB_TYPE_INCOME)
+	conditionParams = append(conditionParams, models.TRANSACTION_DB_TYPE_EXPENSE)
+	conditionParams = append(conditionParams, models.TRANSACTION_DB_TYPE_TRANSFER_OUT)
+	conditionParams = append(conditionParams, uid)
+	conditionParams = append(conditionParams, false)
+
+	if minDeletedUnixTime > 0 {
+		condition = condition + " AND deleted_unix_time>=?"
+		conditionParams = append(conditionParams, minDeletedUnixTime)
+	}
+
+	return condition, conditionParams
+}
+
 func (s *TransactionService) isAccountIdValid(transaction *models.Transaction) error {
 	if transaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
 		if transaction.RelatedAccountId != 0 && transaction.RelatedAccountId != transaction.AccountId {
@@ -1308,6 +2128,16 @@ func (s *TransactionService) getOldAccountModels(sess *xorm.Session, transaction
 	return oldSourceAccount, oldDestinationAccount, nil
 }
 
+func (s *TransactionService) isBalanceAffectedByUpdateColumns(updateCols []string) bool {
+	for i := 0; i < len(updateCols); i++ {
+		if updateCols[i] == "account_id" || updateCols[i] == "amount" || updateCols[i] == "related_account_id" || updateCols[i] == "related_account_amount" || updateCols[i] == "transaction_time" {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (s *TransactionService) getRelatedUpdateColumns(updateCols []string) []string {
 	relatedUpdateCols := make([]string, len(updateCols))
 
@@ -1357,6 +2187,22 @@ func (s *TransactionService) isCategoryValid(sess *xorm.Session, transaction *mo
 	return nil
 }
 
+func (s *TransactionService) isTransactionPeriodOpen(sess *xorm.Session, uid int64, transactions ...*models.Transaction) error {
+	bookLocks, err := getBookLocks(sess, uid)
+
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < len(transactions); i++ {
+		if isTransactionInClosedPeriod(bookLocks, transactions[i]) {
+			return errs.ErrTransactionInClosedPeriod
+		}
+	}
+
+	return nil
+}
+
 func (s *TransactionService) isTagsValid(sess *xorm.Session, transaction *models.Transaction, transactionTagIndexs []*models.TransactionTagIndex, tagIds []int64) error {
 	if len(transactionTagIndexs) > 0 {
 		var tags []*models.TransactionTag
@@ -1381,3 +2227,137 @@ func (s *TransactionService) isTagsValid(sess *xorm.Session, transaction *models
 
 	return nil
 }
+
+func (s *TransactionService) isSplitsValid(sess *xorm.Session, transaction *models.Transaction, splits []*models.TransactionSplit) error {
+	if len(splits) < 1 {
+		return nil
+	}
+
+	if transaction.Type != models.TRANSACTION_DB_TYPE_INCOME && transaction.Type != models.TRANSACTION_DB_TYPE_EXPENSE {
+		return errs.ErrTransactionSplitsNotAllowed
+	}
+
+	if len(splits) < 2 {
+		return errs.ErrTransactionSplitsTooFew
+	}
+
+	var totalAmount int64 = 0
+	tagIds := make([]int64, 0)
+
+	for i := 0; i < len(splits); i++ {
+		split := splits[i]
+
+		if split.Amount <= 0 {
+			return errs.ErrTransactionSplitAmountInvalid
+		}
+
+		totalAmount += split.Amount
+
+		err := s.isCategoryValid(sess, &models.Transaction{
+			Uid:        transaction.Uid,
+			Type:       transaction.Type,
+			CategoryId: split.CategoryId,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		tagIds = append(tagIds, split.GetTagIds()...)
+	}
+
+	if totalAmount != transaction.Amount {
+		return errs.ErrTransactionSplitAmountsNotEqualToTotal
+	}
+
+	tagIds = utils.ToUniqueInt64Slice(tagIds)
+
+	if len(tagIds) > 0 {
+		tagCount, err := sess.Where("uid=? AND deleted=?", transaction.Uid, false).In("tag_id", tagIds).Count(&models.TransactionTag{})
+
+		if err != nil {
+			return err
+		} else if tagCount != int64(len(tagIds)) {
+			return errs.ErrTransactionTagNotFound
+		}
+	}
+
+	return nil
+}
+
+func (s *TransactionService) isSplitsTotalAmountValid(transaction *models.Transaction, splits []*models.TransactionSplit) error {
+	if len(splits) < 1 {
+		return nil
+	}
+
+	var totalAmount int64 = 0
+
+	for i := 0; i < len(splits); i++ {
+		totalAmount += splits[i].Amount
+	}
+
+	if totalAmount != transaction.Amount {
+		return errs.ErrTransactionSplitAmountsNotEqualToTotal
+	}
+
+	return nil
+}
+
+func (s *TransactionService) getTransactionTagIdsAndSplits(sess *xorm.Session, uid int64, transactionId int64) ([]int64, []*models.TransactionSplit, error) {
+	var tagIndexs []*models.TransactionTagIndex
+	err := sess.Where("uid=? AND deleted=? AND transaction_id=?", uid, false, transactionId).Find(&tagIndexs)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tagIds := make([]int64, len(tagIndexs))
+
+	for i := 0; i < len(tagIndexs); i++ {
+		tagIds[i] = tagIndexs[i].TagId
+	}
+
+	var splits []*models.TransactionSplit
+	err = sess.Where("uid=? AND deleted=? AND transaction_id=?", uid, false, transactionId).OrderBy("display_order asc").Find(&splits)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return tagIds, splits, nil
+}
+
+func (s *TransactionService) prepareTransactionSplits(transaction *models.Transaction, splits []*models.TransactionSplit, now int64) {
+	if len(splits) < 1 {
+		return
+	}
+
+	splitIds := s.GenerateUuids(uuid.UUID_TYPE_SPLIT, uint8(len(splits)))
+
+	for i := 0; i < len(splits); i++ {
+		splits[i].SplitId = splitIds[i]
+		splits[i].Uid = transaction.Uid
+		splits[i].Deleted = false
+		splits[i].TransactionId = transaction.TransactionId
+		splits[i].DisplayOrder = int32(i + 1)
+		splits[i].CreatedUnixTime = now
+		splits[i].UpdatedUnixTime = now
+	}
+}
+
+func (s *TransactionService) insertTransactionSplits(sess *xorm.Session, splits []*models.TransactionSplit, transactionType models.TransactionDbType, accountId int64, transactionTime int64) error {
+	for i := 0; i < len(splits); i++ {
+		split := splits[i]
+		split.Type = transactionType
+		split.AccountId = accountId
+		split.TransactionTime = transactionTime
+
+		_, err := sess.Insert(split)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
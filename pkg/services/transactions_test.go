@@ -0,0 +1,1084 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/f97/gofire/pkg/datastore"
+	"github.com/f97/gofire/pkg/errs"
+	"github.com/f97/gofire/pkg/models"
+	"github.com/f97/gofire/pkg/settings"
+	"github.com/f97/gofire/pkg/storage"
+	"github.com/f97/gofire/pkg/utils"
+	"github.com/f97/gofire/pkg/uuid"
+)
+
+func getTestTotalAmountMap(totalAmounts []*models.Transaction) map[int64]int64 {
+	amounts := make(map[int64]int64)
+
+	for i := 0; i < len(totalAmounts); i++ {
+		amounts[totalAmounts[i].CategoryId] += totalAmounts[i].Amount
+	}
+
+	return amounts
+}
+
+func TestGetAccountsAndCategoriesTotalIncomeAndExpense_PeriodBoundaries(t *testing.T) {
+	c := initializeTestDataStore(t)
+	uid := int64(1001)
+	startTime := int64(1704067200)
+	endTime := int64(1706745599)
+
+	primaryExpenseCategory := &models.TransactionCategory{
+		CategoryId: TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:        uid,
+		Name:       "Expense",
+		Type:       models.CATEGORY_TYPE_EXPENSE,
+	}
+
+	account := &models.Account{
+		AccountId: Accounts.GenerateUuid(uuid.UUID_TYPE_ACCOUNT),
+		Uid:       uid,
+		Name:      "Cash",
+		Category:  models.ACCOUNT_CATEGORY_CASH,
+		Type:      models.ACCOUNT_TYPE_SINGLE_ACCOUNT,
+		Currency:  "USD",
+		Balance:   0,
+	}
+
+	category := &models.TransactionCategory{
+		CategoryId:       TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:              uid,
+		Name:             "Category",
+		Type:             models.CATEGORY_TYPE_EXPENSE,
+		ParentCategoryId: primaryExpenseCategory.CategoryId,
+	}
+
+	_, err := datastore.Container.UserDataStore.Choose(uid).NewSession(c).Insert(primaryExpenseCategory, account, category)
+	assert.Equal(t, nil, err)
+
+	transactions := []*models.Transaction{
+		{Uid: uid, Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: category.CategoryId, AccountId: account.AccountId, Amount: 1, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(startTime - 1)},
+		{Uid: uid, Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: category.CategoryId, AccountId: account.AccountId, Amount: 10, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(startTime)},
+		{Uid: uid, Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: category.CategoryId, AccountId: account.AccountId, Amount: 100, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(endTime)},
+		{Uid: uid, Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: category.CategoryId, AccountId: account.AccountId, Amount: 1000, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(endTime + 1)},
+	}
+
+	for i := 0; i < len(transactions); i++ {
+		err := Transactions.CreateTransaction(c, uid, transactions[i], nil, nil)
+		assert.Equal(t, nil, err)
+	}
+
+	totalAmounts, err := Transactions.GetAccountsAndCategoriesTotalIncomeAndExpense(c, uid, startTime, endTime)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, map[int64]int64{category.CategoryId: 110}, getTestTotalAmountMap(totalAmounts))
+}
+
+func TestGetAccountsAndCategoriesTotalIncomeAndExpense_AggregateByCategory(t *testing.T) {
+	c := initializeTestDataStore(t)
+	uid := int64(1001)
+
+	primaryExpenseCategory := &models.TransactionCategory{
+		CategoryId: TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:        uid,
+		Name:       "Expense",
+		Type:       models.CATEGORY_TYPE_EXPENSE,
+	}
+
+	account := &models.Account{
+		AccountId: Accounts.GenerateUuid(uuid.UUID_TYPE_ACCOUNT),
+		Uid:       uid,
+		Name:      "Cash",
+		Category:  models.ACCOUNT_CATEGORY_CASH,
+		Type:      models.ACCOUNT_TYPE_SINGLE_ACCOUNT,
+		Currency:  "USD",
+		Balance:   0,
+	}
+
+	category1 := &models.TransactionCategory{
+		CategoryId:       TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:              uid,
+		Name:             "Category 1",
+		Type:             models.CATEGORY_TYPE_EXPENSE,
+		ParentCategoryId: primaryExpenseCategory.CategoryId,
+	}
+
+	category2 := &models.TransactionCategory{
+		CategoryId:       TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:              uid,
+		Name:             "Category 2",
+		Type:             models.CATEGORY_TYPE_EXPENSE,
+		ParentCategoryId: primaryExpenseCategory.CategoryId,
+	}
+
+	_, err := datastore.Container.UserDataStore.Choose(uid).NewSession(c).Insert(primaryExpenseCategory, account, category1, category2)
+	assert.Equal(t, nil, err)
+
+	transactions := []*models.Transaction{
+		{Uid: uid, Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: category1.CategoryId, AccountId: account.AccountId, Amount: 10, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(1704067200)},
+		{Uid: uid, Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: category1.CategoryId, AccountId: account.AccountId, Amount: 20, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(1704067300)},
+		{Uid: uid, Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: category2.CategoryId, AccountId: account.AccountId, Amount: 40, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(1704067400)},
+	}
+
+	for i := 0; i < len(transactions); i++ {
+		err := Transactions.CreateTransaction(c, uid, transactions[i], nil, nil)
+		assert.Equal(t, nil, err)
+	}
+
+	totalAmounts, err := Transactions.GetAccountsAndCategoriesTotalIncomeAndExpense(c, uid, 1704067200, 1704067400)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, map[int64]int64{category1.CategoryId: 30, category2.CategoryId: 40}, getTestTotalAmountMap(totalAmounts))
+}
+
+func TestGetAccountsAndCategoriesTotalIncomeAndExpenseByTag(t *testing.T) {
+	c := initializeTestDataStore(t)
+	uid := int64(1001)
+
+	primaryExpenseCategory := &models.TransactionCategory{
+		CategoryId: TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:        uid,
+		Name:       "Expense",
+		Type:       models.CATEGORY_TYPE_EXPENSE,
+	}
+
+	account := &models.Account{
+		AccountId: Accounts.GenerateUuid(uuid.UUID_TYPE_ACCOUNT),
+		Uid:       uid,
+		Name:      "Cash",
+		Category:  models.ACCOUNT_CATEGORY_CASH,
+		Type:      models.ACCOUNT_TYPE_SINGLE_ACCOUNT,
+		Currency:  "USD",
+		Balance:   0,
+	}
+
+	category := &models.TransactionCategory{
+		CategoryId:       TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:              uid,
+		Name:             "Category",
+		Type:             models.CATEGORY_TYPE_EXPENSE,
+		ParentCategoryId: primaryExpenseCategory.CategoryId,
+	}
+
+	tag1 := &models.TransactionTag{
+		TagId: TransactionTags.GenerateUuid(uuid.UUID_TYPE_TAG),
+		Uid:   uid,
+		Name:  "Tag1",
+	}
+
+	tag2 := &models.TransactionTag{
+		TagId: TransactionTags.GenerateUuid(uuid.UUID_TYPE_TAG),
+		Uid:   uid,
+		Name:  "Tag2",
+	}
+
+	_, err := datastore.Container.UserDataStore.Choose(uid).NewSession(c).Insert(primaryExpenseCategory, account, category, tag1, tag2)
+	assert.Equal(t, nil, err)
+
+	transactions := []*models.Transaction{
+		{Uid: uid, Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: category.CategoryId, AccountId: account.AccountId, Amount: 10, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(1704067200)},
+		{Uid: uid, Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: category.CategoryId, AccountId: account.AccountId, Amount: 20, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(1704067300)},
+		{Uid: uid, Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: category.CategoryId, AccountId: account.AccountId, Amount: 40, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(1704067400)},
+		{Uid: uid, Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: category.CategoryId, AccountId: account.AccountId, Amount: 80, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(1704067500)},
+	}
+
+	transactionTagIds := [][]int64{{tag1.TagId}, {tag1.TagId, tag2.TagId}, {tag2.TagId}, nil}
+
+	for i := 0; i < len(transactions); i++ {
+		err := Transactions.CreateTransaction(c, uid, transactions[i], transactionTagIds[i], nil)
+		assert.Equal(t, nil, err)
+	}
+
+	totalAmounts, err := Transactions.GetAccountsAndCategoriesTotalIncomeAndExpenseByTag(c, uid, tag1.TagId, 1704067200, 1704067500)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, map[int64]int64{category.CategoryId: 30}, getTestTotalAmountMap(totalAmounts))
+
+	totalAmounts, err = Transactions.GetAccountsAndCategoriesTotalIncomeAndExpenseByTag(c, uid, tag2.TagId, 1704067200, 1704067500)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, map[int64]int64{category.CategoryId: 60}, getTestTotalAmountMap(totalAmounts))
+}
+
+func newTestTransactionSplit(categoryId int64, amount int64, tagIds []int64) *models.TransactionSplit {
+	split := &models.TransactionSplit{
+		CategoryId: categoryId,
+		Amount:     amount,
+	}
+
+	split.SetTagIds(tagIds)
+
+	return split
+}
+
+func TestCreateTransaction_InvalidSplits(t *testing.T) {
+	c := initializeTestDataStore(t)
+	uid := int64(1001)
+
+	primaryExpenseCategory := &models.TransactionCategory{
+		CategoryId: TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:        uid,
+		Name:       "Expense",
+		Type:       models.CATEGORY_TYPE_EXPENSE,
+	}
+
+	primaryIncomeCategory := &models.TransactionCategory{
+		CategoryId: TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:        uid,
+		Name:       "Income",
+		Type:       models.CATEGORY_TYPE_INCOME,
+	}
+
+	primaryTransferCategory := &models.TransactionCategory{
+		CategoryId: TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:        uid,
+		Name:       "Transfer",
+		Type:       models.CATEGORY_TYPE_TRANSFER,
+	}
+
+	account1 := &models.Account{
+		AccountId: Accounts.GenerateUuid(uuid.UUID_TYPE_ACCOUNT),
+		Uid:       uid,
+		Name:      "Cash",
+		Category:  models.ACCOUNT_CATEGORY_CASH,
+		Type:      models.ACCOUNT_TYPE_SINGLE_ACCOUNT,
+		Currency:  "USD",
+		Balance:   0,
+	}
+
+	account2 := &models.Account{
+		AccountId: Accounts.GenerateUuid(uuid.UUID_TYPE_ACCOUNT),
+		Uid:       uid,
+		Name:      "Bank",
+		Category:  models.ACCOUNT_CATEGORY_CASH,
+		Type:      models.ACCOUNT_TYPE_SINGLE_ACCOUNT,
+		Currency:  "USD",
+		Balance:   0,
+	}
+
+	expenseCategory := &models.TransactionCategory{
+		CategoryId:       TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:              uid,
+		Name:             "Expense Category",
+		Type:             models.CATEGORY_TYPE_EXPENSE,
+		ParentCategoryId: primaryExpenseCategory.CategoryId,
+	}
+
+	incomeCategory := &models.TransactionCategory{
+		CategoryId:       TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:              uid,
+		Name:             "Income Category",
+		Type:             models.CATEGORY_TYPE_INCOME,
+		ParentCategoryId: primaryIncomeCategory.CategoryId,
+	}
+
+	transferCategory := &models.TransactionCategory{
+		CategoryId:       TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:              uid,
+		Name:             "Transfer Category",
+		Type:             models.CATEGORY_TYPE_TRANSFER,
+		ParentCategoryId: primaryTransferCategory.CategoryId,
+	}
+
+	_, err := datastore.Container.UserDataStore.Choose(uid).NewSession(c).Insert(primaryExpenseCategory, primaryIncomeCategory, primaryTransferCategory, account1, account2, expenseCategory, incomeCategory, transferCategory)
+	assert.Equal(t, nil, err)
+
+	newExpense := func(amount int64) *models.Transaction {
+		return &models.Transaction{
+			Uid:             uid,
+			Type:            models.TRANSACTION_DB_TYPE_EXPENSE,
+			CategoryId:      expenseCategory.CategoryId,
+			AccountId:       account1.AccountId,
+			Amount:          amount,
+			TransactionTime: utils.GetMinTransactionTimeFromUnixTime(1704067200),
+		}
+	}
+
+	err = Transactions.CreateTransaction(c, uid, newExpense(100), nil, []*models.TransactionSplit{
+		newTestTransactionSplit(expenseCategory.CategoryId, 100, nil),
+	})
+	assert.Equal(t, errs.ErrTransactionSplitsTooFew, err)
+
+	err = Transactions.CreateTransaction(c, uid, newExpense(100), nil, []*models.TransactionSplit{
+		newTestTransactionSplit(expenseCategory.CategoryId, 100, nil),
+		newTestTransactionSplit(expenseCategory.CategoryId, 0, nil),
+	})
+	assert.Equal(t, errs.ErrTransactionSplitAmountInvalid, err)
+
+	err = Transactions.CreateTransaction(c, uid, newExpense(100), nil, []*models.TransactionSplit{
+		newTestTransactionSplit(expenseCategory.CategoryId, 60, nil),
+		newTestTransactionSplit(incomeCategory.CategoryId, 40, nil),
+	})
+	assert.Equal(t, errs.ErrTransactionCategoryTypeInvalid, err)
+
+	err = Transactions.CreateTransaction(c, uid, newExpense(100), nil, []*models.TransactionSplit{
+		newTestTransactionSplit(expenseCategory.CategoryId, 60, nil),
+		newTestTransactionSplit(expenseCategory.CategoryId, 40, []int64{123456}),
+	})
+	assert.Equal(t, errs.ErrTransactionTagNotFound, err)
+
+	err = Transactions.CreateTransaction(c, uid, &models.Transaction{
+		Uid:                  uid,
+		Type:                 models.TRANSACTION_DB_TYPE_TRANSFER_OUT,
+		CategoryId:           transferCategory.CategoryId,
+		AccountId:            account1.AccountId,
+		Amount:               100,
+		RelatedAccountId:     account2.AccountId,
+		RelatedAccountAmount: 100,
+		TransactionTime:      utils.GetMinTransactionTimeFromUnixTime(1704067200),
+	}, nil, []*models.TransactionSplit{
+		newTestTransactionSplit(transferCategory.CategoryId, 60, nil),
+		newTestTransactionSplit(transferCategory.CategoryId, 40, nil),
+	})
+	assert.Equal(t, errs.ErrTransactionSplitsNotAllowed, err)
+
+	transactionCount, err := Transactions.GetAllTransactionCount(c, uid)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(0), transactionCount)
+}
+
+func TestCreateTransaction_SplitAmountsNotEqualToTotal(t *testing.T) {
+	c := initializeTestDataStore(t)
+	uid := int64(1001)
+
+	primaryExpenseCategory := &models.TransactionCategory{
+		CategoryId: TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:        uid,
+		Name:       "Expense",
+		Type:       models.CATEGORY_TYPE_EXPENSE,
+	}
+
+	account := &models.Account{
+		AccountId: Accounts.GenerateUuid(uuid.UUID_TYPE_ACCOUNT),
+		Uid:       uid,
+		Name:      "Cash",
+		Category:  models.ACCOUNT_CATEGORY_CASH,
+		Type:      models.ACCOUNT_TYPE_SINGLE_ACCOUNT,
+		Currency:  "USD",
+		Balance:   0,
+	}
+
+	category := &models.TransactionCategory{
+		CategoryId:       TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:              uid,
+		Name:             "Category",
+		Type:             models.CATEGORY_TYPE_EXPENSE,
+		ParentCategoryId: primaryExpenseCategory.CategoryId,
+	}
+
+	_, err := datastore.Container.UserDataStore.Choose(uid).NewSession(c).Insert(primaryExpenseCategory, account, category)
+	assert.Equal(t, nil, err)
+
+	err = Transactions.CreateTransaction(c, uid, &models.Transaction{
+		Uid:             uid,
+		Type:            models.TRANSACTION_DB_TYPE_EXPENSE,
+		CategoryId:      category.CategoryId,
+		AccountId:       account.AccountId,
+		Amount:          100,
+		TransactionTime: utils.GetMinTransactionTimeFromUnixTime(1704067200),
+	}, nil, []*models.TransactionSplit{
+		newTestTransactionSplit(category.CategoryId, 60, nil),
+		newTestTransactionSplit(category.CategoryId, 30, nil),
+	})
+	assert.Equal(t, errs.ErrTransactionSplitAmountsNotEqualToTotal, err)
+	savedAccounts, err := Accounts.GetAccountsByAccountIds(c, uid, []int64{account.AccountId})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(0), savedAccounts[account.AccountId].Balance)
+}
+
+func TestModifyTransaction_NilSplitsKeepSplitLines(t *testing.T) {
+	c := initializeTestDataStore(t)
+	uid := int64(1001)
+
+	primaryExpenseCategory := &models.TransactionCategory{
+		CategoryId: TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:        uid,
+		Name:       "Expense",
+		Type:       models.CATEGORY_TYPE_EXPENSE,
+	}
+
+	account := &models.Account{
+		AccountId: Accounts.GenerateUuid(uuid.UUID_TYPE_ACCOUNT),
+		Uid:       uid,
+		Name:      "Cash",
+		Category:  models.ACCOUNT_CATEGORY_CASH,
+		Type:      models.ACCOUNT_TYPE_SINGLE_ACCOUNT,
+		Currency:  "USD",
+		Balance:   0,
+	}
+
+	category := &models.TransactionCategory{
+		CategoryId:       TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:              uid,
+		Name:             "Category",
+		Type:             models.CATEGORY_TYPE_EXPENSE,
+		ParentCategoryId: primaryExpenseCategory.CategoryId,
+	}
+
+	_, err := datastore.Container.UserDataStore.Choose(uid).NewSession(c).Insert(primaryExpenseCategory, account, category)
+	assert.Equal(t, nil, err)
+
+	transaction := &models.Transaction{
+		Uid:             uid,
+		Type:            models.TRANSACTION_DB_TYPE_EXPENSE,
+		CategoryId:      category.CategoryId,
+		AccountId:       account.AccountId,
+		Amount:          100,
+		TransactionTime: utils.GetMinTransactionTimeFromUnixTime(1704067200),
+	}
+
+	err = Transactions.CreateTransaction(c, uid, transaction, nil, []*models.TransactionSplit{
+		newTestTransactionSplit(category.CategoryId, 60, nil),
+		newTestTransactionSplit(category.CategoryId, 40, nil),
+	})
+	assert.Equal(t, nil, err)
+
+	newTransaction := &models.Transaction{
+		TransactionId:   transaction.TransactionId,
+		Uid:             uid,
+		CategoryId:      category.CategoryId,
+		AccountId:       account.AccountId,
+		Amount:          100,
+		Comment:         "modified",
+		TransactionTime: utils.GetMinTransactionTimeFromUnixTime(1704067300),
+	}
+
+	err = Transactions.ModifyTransaction(c, uid, newTransaction, nil, nil, nil)
+	assert.Equal(t, nil, err)
+
+	allSplits, err := Transactions.GetAllSplitsOfTransactions(c, uid, []int64{transaction.TransactionId})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2, len(allSplits[transaction.TransactionId]))
+	assert.Equal(t, int64(60), allSplits[transaction.TransactionId][0].Amount)
+	assert.Equal(t, newTransaction.TransactionTime, allSplits[transaction.TransactionId][0].TransactionTime)
+
+	// The amount cannot be changed without changing split lines
+	newTransaction.Amount = 200
+	err = Transactions.ModifyTransaction(c, uid, newTransaction, nil, nil, nil)
+	assert.Equal(t, errs.ErrTransactionSplitAmountsNotEqualToTotal, err)
+}
+
+func TestModifyTransaction_EmptySplitsClearSplitLines(t *testing.T) {
+	c := initializeTestDataStore(t)
+	uid := int64(1001)
+
+	primaryExpenseCategory := &models.TransactionCategory{
+		CategoryId: TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:        uid,
+		Name:       "Expense",
+		Type:       models.CATEGORY_TYPE_EXPENSE,
+	}
+
+	account := &models.Account{
+		AccountId: Accounts.GenerateUuid(uuid.UUID_TYPE_ACCOUNT),
+		Uid:       uid,
+		Name:      "Cash",
+		Category:  models.ACCOUNT_CATEGORY_CASH,
+		Type:      models.ACCOUNT_TYPE_SINGLE_ACCOUNT,
+		Currency:  "USD",
+		Balance:   0,
+	}
+
+	category := &models.TransactionCategory{
+		CategoryId:       TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:              uid,
+		Name:             "Category",
+		Type:             models.CATEGORY_TYPE_EXPENSE,
+		ParentCategoryId: primaryExpenseCategory.CategoryId,
+	}
+
+	_, err := datastore.Container.UserDataStore.Choose(uid).NewSession(c).Insert(primaryExpenseCategory, account, category)
+	assert.Equal(t, nil, err)
+
+	transaction := &models.Transaction{
+		Uid:             uid,
+		Type:            models.TRANSACTION_DB_TYPE_EXPENSE,
+		CategoryId:      category.CategoryId,
+		AccountId:       account.AccountId,
+		Amount:          100,
+		TransactionTime: utils.GetMinTransactionTimeFromUnixTime(1704067200),
+	}
+
+	err = Transactions.CreateTransaction(c, uid, transaction, nil, []*models.TransactionSplit{
+		newTestTransactionSplit(category.CategoryId, 60, nil),
+		newTestTransactionSplit(category.CategoryId, 40, nil),
+	})
+	assert.Equal(t, nil, err)
+
+	err = Transactions.ModifyTransaction(c, uid, &models.Transaction{
+		TransactionId:   transaction.TransactionId,
+		Uid:             uid,
+		CategoryId:      category.CategoryId,
+		AccountId:       account.AccountId,
+		Amount:          200,
+		TransactionTime: transaction.TransactionTime,
+	}, nil, nil, []*models.TransactionSplit{})
+	assert.Equal(t, nil, err)
+
+	allSplits, err := Transactions.GetAllSplitsOfTransactions(c, uid, []int64{transaction.TransactionId})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 0, len(allSplits[transaction.TransactionId]))
+	savedAccounts, err := Accounts.GetAccountsByAccountIds(c, uid, []int64{account.AccountId})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(-200), savedAccounts[account.AccountId].Balance)
+}
+
+func TestGetAccountsAndCategoriesTotalIncomeAndExpense_CountSplitLines(t *testing.T) {
+	c := initializeTestDataStore(t)
+	uid := int64(1001)
+
+	primaryExpenseCategory := &models.TransactionCategory{
+		CategoryId: TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:        uid,
+		Name:       "Expense",
+		Type:       models.CATEGORY_TYPE_EXPENSE,
+	}
+
+	account := &models.Account{
+		AccountId: Accounts.GenerateUuid(uuid.UUID_TYPE_ACCOUNT),
+		Uid:       uid,
+		Name:      "Cash",
+		Category:  models.ACCOUNT_CATEGORY_CASH,
+		Type:      models.ACCOUNT_TYPE_SINGLE_ACCOUNT,
+		Currency:  "USD",
+		Balance:   0,
+	}
+
+	category1 := &models.TransactionCategory{
+		CategoryId:       TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:              uid,
+		Name:             "Category 1",
+		Type:             models.CATEGORY_TYPE_EXPENSE,
+		ParentCategoryId: primaryExpenseCategory.CategoryId,
+	}
+
+	category2 := &models.TransactionCategory{
+		CategoryId:       TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:              uid,
+		Name:             "Category 2",
+		Type:             models.CATEGORY_TYPE_EXPENSE,
+		ParentCategoryId: primaryExpenseCategory.CategoryId,
+	}
+
+	_, err := datastore.Container.UserDataStore.Choose(uid).NewSession(c).Insert(primaryExpenseCategory, account, category1, category2)
+	assert.Equal(t, nil, err)
+
+	transaction1 := &models.Transaction{
+		Uid:             uid,
+		Type:            models.TRANSACTION_DB_TYPE_EXPENSE,
+		CategoryId:      category1.CategoryId,
+		AccountId:       account.AccountId,
+		Amount:          100,
+		TransactionTime: utils.GetMinTransactionTimeFromUnixTime(1704067200),
+	}
+
+	transaction2 := &models.Transaction{
+		Uid:             uid,
+		Type:            models.TRANSACTION_DB_TYPE_EXPENSE,
+		CategoryId:      category1.CategoryId,
+		AccountId:       account.AccountId,
+		Amount:          5,
+		TransactionTime: utils.GetMinTransactionTimeFromUnixTime(1704067300),
+	}
+
+	err = Transactions.CreateTransaction(c, uid, transaction1, nil, []*models.TransactionSplit{
+		newTestTransactionSplit(category1.CategoryId, 70, nil),
+		newTestTransactionSplit(category2.CategoryId, 30, nil),
+	})
+	assert.Equal(t, nil, err)
+
+	err = Transactions.CreateTransaction(c, uid, transaction2, nil, nil)
+	assert.Equal(t, nil, err)
+
+	totalAmounts, err := Transactions.GetAccountsAndCategoriesTotalIncomeAndExpense(c, uid, 1704067200, 1704067300)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, map[int64]int64{category1.CategoryId: 75, category2.CategoryId: 30}, getTestTotalAmountMap(totalAmounts))
+}
+
+func TestGetAccountsAndCategoriesTotalIncomeAndExpenseByTag_FilterSplitLinesByOwnTags(t *testing.T) {
+	c := initializeTestDataStore(t)
+	uid := int64(1001)
+
+	primaryExpenseCategory := &models.TransactionCategory{
+		CategoryId: TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:        uid,
+		Name:       "Expense",
+		Type:       models.CATEGORY_TYPE_EXPENSE,
+	}
+
+	account := &models.Account{
+		AccountId: Accounts.GenerateUuid(uuid.UUID_TYPE_ACCOUNT),
+		Uid:       uid,
+		Name:      "Cash",
+		Category:  models.ACCOUNT_CATEGORY_CASH,
+		Type:      models.ACCOUNT_TYPE_SINGLE_ACCOUNT,
+		Currency:  "USD",
+		Balance:   0,
+	}
+
+	category1 := &models.TransactionCategory{
+		CategoryId:       TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:              uid,
+		Name:             "Category 1",
+		Type:             models.CATEGORY_TYPE_EXPENSE,
+		ParentCategoryId: primaryExpenseCategory.CategoryId,
+	}
+
+	category2 := &models.TransactionCategory{
+		CategoryId:       TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:              uid,
+		Name:             "Category 2",
+		Type:             models.CATEGORY_TYPE_EXPENSE,
+		ParentCategoryId: primaryExpenseCategory.CategoryId,
+	}
+
+	parentTag := &models.TransactionTag{
+		TagId: TransactionTags.GenerateUuid(uuid.UUID_TYPE_TAG),
+		Uid:   uid,
+		Name:  "Parent",
+	}
+
+	splitTag := &models.TransactionTag{
+		TagId: TransactionTags.GenerateUuid(uuid.UUID_TYPE_TAG),
+		Uid:   uid,
+		Name:  "Split",
+	}
+
+	_, err := datastore.Container.UserDataStore.Choose(uid).NewSession(c).Insert(primaryExpenseCategory, account, category1, category2, parentTag, splitTag)
+	assert.Equal(t, nil, err)
+
+	transaction := &models.Transaction{
+		Uid:             uid,
+		Type:            models.TRANSACTION_DB_TYPE_EXPENSE,
+		CategoryId:      category1.CategoryId,
+		AccountId:       account.AccountId,
+		Amount:          100,
+		TransactionTime: utils.GetMinTransactionTimeFromUnixTime(1704067200),
+	}
+
+	err = Transactions.CreateTransaction(c, uid, transaction, []int64{parentTag.TagId}, []*models.TransactionSplit{
+		newTestTransactionSplit(category1.CategoryId, 70, []int64{splitTag.TagId}),
+		newTestTransactionSplit(category2.CategoryId, 20, nil),
+		newTestTransactionSplit(category2.CategoryId, 10, []int64{splitTag.TagId, parentTag.TagId}),
+	})
+	assert.Equal(t, nil, err)
+
+	totalAmounts, err := Transactions.GetAccountsAndCategoriesTotalIncomeAndExpenseByTag(c, uid, splitTag.TagId, 1704067200, 1704067200)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, map[int64]int64{category1.CategoryId: 70, category2.CategoryId: 10}, getTestTotalAmountMap(totalAmounts))
+
+	totalAmounts, err = Transactions.GetAccountsAndCategoriesTotalIncomeAndExpenseByTag(c, uid, parentTag.TagId, 1704067200, 1704067200)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, map[int64]int64{category2.CategoryId: 10}, getTestTotalAmountMap(totalAmounts))
+}
+
+func TestDeleteAllTransactions_KeepAttachmentObjectsUntilPurged(t *testing.T) {
+	c := initializeTestDataStore(t)
+	uid := int64(1001)
+
+	err := storage.InitializeStorageContainer(&settings.Config{
+		StorageType:                settings.LocalFileSystemObjectStorageType,
+		LocalFileSystemStoragePath: t.TempDir(),
+	})
+	assert.Equal(t, nil, err)
+
+	primaryExpenseCategory := &models.TransactionCategory{
+		CategoryId: TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:        uid,
+		Name:       "Expense",
+		Type:       models.CATEGORY_TYPE_EXPENSE,
+	}
+
+	account := &models.Account{
+		AccountId: Accounts.GenerateUuid(uuid.UUID_TYPE_ACCOUNT),
+		Uid:       uid,
+		Name:      "Cash",
+		Category:  models.ACCOUNT_CATEGORY_CASH,
+		Type:      models.ACCOUNT_TYPE_SINGLE_ACCOUNT,
+		Currency:  "USD",
+		Balance:   0,
+	}
+
+	category := &models.TransactionCategory{
+		CategoryId:       TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:              uid,
+		Name:             "Category",
+		Type:             models.CATEGORY_TYPE_EXPENSE,
+		ParentCategoryId: primaryExpenseCategory.CategoryId,
+	}
+
+	_, err = datastore.Container.UserDataStore.Choose(uid).NewSession(c).Insert(primaryExpenseCategory, account, category)
+	assert.Equal(t, nil, err)
+
+	transaction := &models.Transaction{
+		Uid:             uid,
+		Type:            models.TRANSACTION_DB_TYPE_EXPENSE,
+		CategoryId:      category.CategoryId,
+		AccountId:       account.AccountId,
+		Amount:          100,
+		TransactionTime: utils.GetMinTransactionTimeFromUnixTime(1704067200),
+	}
+
+	err = Transactions.CreateTransaction(c, uid, transaction, nil, nil)
+	assert.Equal(t, nil, err)
+
+	attachment := &models.TransactionAttachment{
+		AttachmentId:  TransactionAttachments.GenerateUuid(uuid.UUID_TYPE_ATTACHMENT),
+		Uid:           uid,
+		TransactionId: transaction.TransactionId,
+		FileName:      "receipt.txt",
+		ContentType:   "text/plain",
+		FileSize:      4,
+	}
+
+	_, err = datastore.Container.UserDataStore.Choose(uid).NewSession(c).Insert(attachment)
+	assert.Equal(t, nil, err)
+
+	err = storage.Container.SaveTransactionAttachment(uid, attachment.AttachmentId, []byte("test"))
+	assert.Equal(t, nil, err)
+
+	err = Transactions.DeleteAllTransactions(c, uid)
+	assert.Equal(t, nil, err)
+
+	data, err := storage.Container.ReadTransactionAttachment(uid, attachment.AttachmentId)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []byte("test"), data)
+
+	purgedCount, err := Transactions.PurgeTrashTransactions(c, uid, uid, 0, 0)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, purgedCount)
+
+	_, err = storage.Container.ReadTransactionAttachment(uid, attachment.AttachmentId)
+	assert.NotEqual(t, nil, err)
+}
+
+func TestDeleteTransaction_KeepAttachmentObjectsUntilPurged(t *testing.T) {
+	c := initializeTestDataStore(t)
+	uid := int64(1001)
+
+	err := storage.InitializeStorageContainer(&settings.Config{
+		StorageType:                settings.LocalFileSystemObjectStorageType,
+		LocalFileSystemStoragePath: t.TempDir(),
+	})
+	assert.Equal(t, nil, err)
+
+	primaryExpenseCategory := &models.TransactionCategory{
+		CategoryId: TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:        uid,
+		Name:       "Expense",
+		Type:       models.CATEGORY_TYPE_EXPENSE,
+	}
+
+	account := &models.Account{
+		AccountId: Accounts.GenerateUuid(uuid.UUID_TYPE_ACCOUNT),
+		Uid:       uid,
+		Name:      "Cash",
+		Category:  models.ACCOUNT_CATEGORY_CASH,
+		Type:      models.ACCOUNT_TYPE_SINGLE_ACCOUNT,
+		Currency:  "USD",
+		Balance:   0,
+	}
+
+	category := &models.TransactionCategory{
+		CategoryId:       TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:              uid,
+		Name:             "Category",
+		Type:             models.CATEGORY_TYPE_EXPENSE,
+		ParentCategoryId: primaryExpenseCategory.CategoryId,
+	}
+
+	_, err = datastore.Container.UserDataStore.Choose(uid).NewSession(c).Insert(primaryExpenseCategory, account, category)
+	assert.Equal(t, nil, err)
+
+	transaction := &models.Transaction{
+		Uid:             uid,
+		Type:            models.TRANSACTION_DB_TYPE_EXPENSE,
+		CategoryId:      category.CategoryId,
+		AccountId:       account.AccountId,
+		Amount:          100,
+		TransactionTime: utils.GetMinTransactionTimeFromUnixTime(1704067200),
+	}
+
+	err = Transactions.CreateTransaction(c, uid, transaction, nil, nil)
+	assert.Equal(t, nil, err)
+
+	attachment := &models.TransactionAttachment{
+		AttachmentId:  TransactionAttachments.GenerateUuid(uuid.UUID_TYPE_ATTACHMENT),
+		Uid:           uid,
+		TransactionId: transaction.TransactionId,
+		FileName:      "receipt.txt",
+		ContentType:   "text/plain",
+		FileSize:      4,
+	}
+
+	_, err = datastore.Container.UserDataStore.Choose(uid).NewSession(c).Insert(attachment)
+	assert.Equal(t, nil, err)
+
+	err = storage.Container.SaveTransactionAttachment(uid, attachment.AttachmentId, []byte("test"))
+	assert.Equal(t, nil, err)
+
+	err = Transactions.DeleteTransaction(c, uid, uid, transaction.TransactionId)
+	assert.Equal(t, nil, err)
+
+	data, err := storage.Container.ReadTransactionAttachment(uid, attachment.AttachmentId)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []byte("test"), data)
+
+	purgedCount, err := Transactions.PurgeTrashTransactions(c, uid, uid, transaction.TransactionId, 0)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, purgedCount)
+
+	_, err = storage.Container.ReadTransactionAttachment(uid, attachment.AttachmentId)
+	assert.NotEqual(t, nil, err)
+}
+
+func TestRestoreTransaction_ReapplyBalanceAndRestoreTags(t *testing.T) {
+	c := initializeTestDataStore(t)
+	uid := int64(1001)
+
+	primaryExpenseCategory := &models.TransactionCategory{
+		CategoryId: TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:        uid,
+		Name:       "Expense",
+		Type:       models.CATEGORY_TYPE_EXPENSE,
+	}
+
+	account := &models.Account{
+		AccountId: Accounts.GenerateUuid(uuid.UUID_TYPE_ACCOUNT),
+		Uid:       uid,
+		Name:      "Cash",
+		Category:  models.ACCOUNT_CATEGORY_CASH,
+		Type:      models.ACCOUNT_TYPE_SINGLE_ACCOUNT,
+		Currency:  "USD",
+		Balance:   1000,
+	}
+
+	category := &models.TransactionCategory{
+		CategoryId:       TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:              uid,
+		Name:             "Category",
+		Type:             models.CATEGORY_TYPE_EXPENSE,
+		ParentCategoryId: primaryExpenseCategory.CategoryId,
+	}
+
+	tag1 := &models.TransactionTag{
+		TagId: TransactionTags.GenerateUuid(uuid.UUID_TYPE_TAG),
+		Uid:   uid,
+		Name:  "Tag1",
+	}
+
+	tag2 := &models.TransactionTag{
+		TagId: TransactionTags.GenerateUuid(uuid.UUID_TYPE_TAG),
+		Uid:   uid,
+		Name:  "Tag2",
+	}
+
+	_, err := datastore.Container.UserDataStore.Choose(uid).NewSession(c).Insert(primaryExpenseCategory, account, category, tag1, tag2)
+	assert.Equal(t, nil, err)
+
+	transaction := &models.Transaction{
+		Uid:             uid,
+		Type:            models.TRANSACTION_DB_TYPE_EXPENSE,
+		CategoryId:      category.CategoryId,
+		AccountId:       account.AccountId,
+		Amount:          100,
+		TransactionTime: utils.GetMinTransactionTimeFromUnixTime(1704067200),
+	}
+
+	err = Transactions.CreateTransaction(c, uid, transaction, []int64{tag1.TagId, tag2.TagId}, nil)
+	assert.Equal(t, nil, err)
+
+	savedAccounts, err := Accounts.GetAccountsByAccountIds(c, uid, []int64{account.AccountId})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(900), savedAccounts[account.AccountId].Balance)
+
+	err = Transactions.DeleteTransaction(c, uid, uid, transaction.TransactionId)
+	assert.Equal(t, nil, err)
+	savedAccounts, err = Accounts.GetAccountsByAccountIds(c, uid, []int64{account.AccountId})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(1000), savedAccounts[account.AccountId].Balance)
+
+	// The tag which has been deleted after the transaction is deleted will not be restored
+	_, err = datastore.Container.UserDataStore.Choose(uid).NewSession(c).ID(tag2.TagId).Cols("deleted").Update(&models.TransactionTag{Deleted: true})
+	assert.Equal(t, nil, err)
+
+	trashCount, err := Transactions.GetTrashTransactionCount(c, uid, 0)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(1), trashCount)
+
+	err = Transactions.RestoreTransaction(c, uid, uid, transaction.TransactionId, 0)
+	assert.Equal(t, nil, err)
+	savedAccounts, err = Accounts.GetAccountsByAccountIds(c, uid, []int64{account.AccountId})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(900), savedAccounts[account.AccountId].Balance)
+
+	savedTransaction, err := Transactions.GetTransactionByTransactionId(c, uid, transaction.TransactionId)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(100), savedTransaction.Amount)
+
+	allTagIds, err := TransactionTags.GetAllTagIdsOfTransactions(c, uid, []int64{transaction.TransactionId})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []int64{tag1.TagId}, allTagIds[transaction.TransactionId])
+
+	trashCount, err = Transactions.GetTrashTransactionCount(c, uid, 0)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(0), trashCount)
+
+	// Restored transaction cannot be restored again
+	err = Transactions.RestoreTransaction(c, uid, uid, transaction.TransactionId, 0)
+	assert.Equal(t, errs.ErrTransactionNotFound, err)
+	savedAccounts, err = Accounts.GetAccountsByAccountIds(c, uid, []int64{account.AccountId})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(900), savedAccounts[account.AccountId].Balance)
+}
+
+func TestRestoreTransaction_RestoreTransferHalf(t *testing.T) {
+	c := initializeTestDataStore(t)
+	uid := int64(1001)
+
+	primaryTransferCategory := &models.TransactionCategory{
+		CategoryId: TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:        uid,
+		Name:       "Transfer",
+		Type:       models.CATEGORY_TYPE_TRANSFER,
+	}
+
+	account1 := &models.Account{
+		AccountId: Accounts.GenerateUuid(uuid.UUID_TYPE_ACCOUNT),
+		Uid:       uid,
+		Name:      "Cash",
+		Category:  models.ACCOUNT_CATEGORY_CASH,
+		Type:      models.ACCOUNT_TYPE_SINGLE_ACCOUNT,
+		Currency:  "USD",
+		Balance:   1000,
+	}
+
+	account2 := &models.Account{
+		AccountId: Accounts.GenerateUuid(uuid.UUID_TYPE_ACCOUNT),
+		Uid:       uid,
+		Name:      "Bank",
+		Category:  models.ACCOUNT_CATEGORY_CASH,
+		Type:      models.ACCOUNT_TYPE_SINGLE_ACCOUNT,
+		Currency:  "USD",
+		Balance:   500,
+	}
+
+	category := &models.TransactionCategory{
+		CategoryId:       TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:              uid,
+		Name:             "Category",
+		Type:             models.CATEGORY_TYPE_TRANSFER,
+		ParentCategoryId: primaryTransferCategory.CategoryId,
+	}
+
+	_, err := datastore.Container.UserDataStore.Choose(uid).NewSession(c).Insert(primaryTransferCategory, account1, account2, category)
+	assert.Equal(t, nil, err)
+
+	transaction := &models.Transaction{
+		Uid:                  uid,
+		Type:                 models.TRANSACTION_DB_TYPE_TRANSFER_OUT,
+		CategoryId:           category.CategoryId,
+		AccountId:            account1.AccountId,
+		Amount:               300,
+		RelatedAccountId:     account2.AccountId,
+		RelatedAccountAmount: 300,
+		TransactionTime:      utils.GetMinTransactionTimeFromUnixTime(1704067200),
+	}
+
+	err = Transactions.CreateTransaction(c, uid, transaction, nil, nil)
+	assert.Equal(t, nil, err)
+	savedAccounts, err := Accounts.GetAccountsByAccountIds(c, uid, []int64{account1.AccountId, account2.AccountId})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(700), savedAccounts[account1.AccountId].Balance)
+	assert.Equal(t, int64(800), savedAccounts[account2.AccountId].Balance)
+
+	err = Transactions.DeleteTransaction(c, uid, uid, transaction.TransactionId)
+	assert.Equal(t, nil, err)
+	savedAccounts, err = Accounts.GetAccountsByAccountIds(c, uid, []int64{account1.AccountId, account2.AccountId})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(1000), savedAccounts[account1.AccountId].Balance)
+	assert.Equal(t, int64(500), savedAccounts[account2.AccountId].Balance)
+
+	_, err = Transactions.GetTransactionByTransactionId(c, uid, transaction.RelatedId)
+	assert.Equal(t, errs.ErrTransactionNotFound, err)
+
+	err = Transactions.RestoreTransaction(c, uid, uid, transaction.TransactionId, 0)
+	assert.Equal(t, nil, err)
+	savedAccounts, err = Accounts.GetAccountsByAccountIds(c, uid, []int64{account1.AccountId, account2.AccountId})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(700), savedAccounts[account1.AccountId].Balance)
+	assert.Equal(t, int64(800), savedAccounts[account2.AccountId].Balance)
+
+	relatedTransaction, err := Transactions.GetTransactionByTransactionId(c, uid, transaction.RelatedId)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_TRANSFER_IN, relatedTransaction.Type)
+	assert.Equal(t, account2.AccountId, relatedTransaction.AccountId)
+	assert.Equal(t, transaction.TransactionId, relatedTransaction.RelatedId)
+}
+
+func TestRestoreTransaction_HiddenAccount(t *testing.T) {
+	c := initializeTestDataStore(t)
+	uid := int64(1001)
+
+	primaryIncomeCategory := &models.TransactionCategory{
+		CategoryId: TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:        uid,
+		Name:       "Income",
+		Type:       models.CATEGORY_TYPE_INCOME,
+	}
+
+	account := &models.Account{
+		AccountId: Accounts.GenerateUuid(uuid.UUID_TYPE_ACCOUNT),
+		Uid:       uid,
+		Name:      "Cash",
+		Category:  models.ACCOUNT_CATEGORY_CASH,
+		Type:      models.ACCOUNT_TYPE_SINGLE_ACCOUNT,
+		Currency:  "USD",
+		Balance:   1000,
+	}
+
+	category := &models.TransactionCategory{
+		CategoryId:       TransactionCategories.GenerateUuid(uuid.UUID_TYPE_CATEGORY),
+		Uid:              uid,
+		Name:             "Category",
+		Type:             models.CATEGORY_TYPE_INCOME,
+		ParentCategoryId: primaryIncomeCategory.CategoryId,
+	}
+
+	_, err := datastore.Container.UserDataStore.Choose(uid).NewSession(c).Insert(primaryIncomeCategory, account, category)
+	assert.Equal(t, nil, err)
+
+	transaction := &models.Transaction{
+		Uid:             uid,
+		Type:            models.TRANSACTION_DB_TYPE_INCOME,
+		CategoryId:      category.CategoryId,
+		AccountId:       account.AccountId,
+		Amount:          100,
+		TransactionTime: utils.GetMinTransactionTimeFromUnixTime(1704067200),
+	}
+
+	err = Transactions.CreateTransaction(c, uid, transaction, nil, nil)
+	assert.Equal(t, nil, err)
+
+	err = Transactions.DeleteTransaction(c, uid, uid, transaction.TransactionId)
+	assert.Equal(t, nil, err)
+	savedAccounts, err := Accounts.GetAccountsByAccountIds(c, uid, []int64{account.AccountId})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(1000), savedAccounts[account.AccountId].Balance)
+
+	_, err = datastore.Container.UserDataStore.Choose(uid).NewSession(c).ID(account.AccountId).Cols("hidden").Update(&models.Account{Hidden: true})
+	assert.Equal(t, nil, err)
+
+	err = Transactions.RestoreTransaction(c, uid, uid, transaction.TransactionId, 0)
+	assert.Equal(t, errs.ErrCannotAddTransactionToHiddenAccount, err)
+	savedAccounts, err = Accounts.GetAccountsByAccountIds(c, uid, []int64{account.AccountId})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(1000), savedAccounts[account.AccountId].Balance)
+
+	_, err = datastore.Container.UserDataStore.Choose(uid).NewSession(c).ID(account.AccountId).Cols("hidden").Update(&models.Account{Hidden: false})
+	assert.Equal(t, nil, err)
+
+	err = Transactions.RestoreTransaction(c, uid, uid, transaction.TransactionId, 0)
+	assert.Equal(t, nil, err)
+	savedAccounts, err = Accounts.GetAccountsByAccountIds(c, uid, []int64{account.AccountId})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(1100), savedAccounts[account.AccountId].Balance)
+}
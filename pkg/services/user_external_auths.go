@@ -0,0 +1,60 @@
+package services
+
+import (
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/f97/gofire/pkg/core"
+	"github.com/f97/gofire/pkg/datastore"
+	"github.com/f97/gofire/pkg/errs"
+	"github.com/f97/gofire/pkg/models"
+	"github.com/f97/gofire/pkg/uuid"
+)
+
+// UserExternalAuthService represents user external identity provider link service
+type UserExternalAuthService struct {
+	ServiceUsingDB
+	ServiceUsingUuid
+}
+
+// Initialize a user external identity provider link service singleton instance
+var (
+	UserExternalAuths = &UserExternalAuthService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+		ServiceUsingUuid: ServiceUsingUuid{
+			container: uuid.Container,
+		},
+	}
+)
+
+// GetExternalAuthByIssuerAndSubject returns the link model according to the issuer and subject of external identity provider account
+func (s *UserExternalAuthService) GetExternalAuthByIssuerAndSubject(c *core.Context, issuer string, subject string) (*models.UserExternalAuth, error) {
+	externalAuth := &models.UserExternalAuth{}
+	has, err := s.UserDB().NewSession(c).Where("issuer=? AND subject=?", issuer, subject).Get(externalAuth)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrOIDCUserNotLinked
+	}
+
+	return externalAuth, nil
+}
+
+// CreateExternalAuth saves a new link model between user and external identity provider account to database
+func (s *UserExternalAuthService) CreateExternalAuth(c *core.Context, externalAuth *models.UserExternalAuth) error {
+	if externalAuth.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	externalAuth.ExternalAuthId = s.GenerateUuid(uuid.UUID_TYPE_DEFAULT)
+	externalAuth.CreatedUnixTime = time.Now().Unix()
+
+	return s.UserDB().DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Insert(externalAuth)
+		return err
+	})
+}
@@ -0,0 +1,207 @@
+package services
+
+import (
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/f97/gofire/pkg/core"
+	"github.com/f97/gofire/pkg/datastore"
+	"github.com/f97/gofire/pkg/errs"
+	"github.com/f97/gofire/pkg/models"
+	"github.com/f97/gofire/pkg/utils"
+	"github.com/f97/gofire/pkg/uuid"
+)
+
+const webAuthnChallengeIdLength = 32
+
+// UserWebAuthnCredentialService represents user webauthn credential service
+type UserWebAuthnCredentialService struct {
+	ServiceUsingDB
+	ServiceUsingUuid
+}
+
+// Initialize a user webauthn credential service singleton instance
+var (
+	UserWebAuthnCredentials = &UserWebAuthnCredentialService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+		ServiceUsingUuid: ServiceUsingUuid{
+			container: uuid.Container,
+		},
+	}
+)
+
+// GetAllCredentialsByUid returns all webauthn credential models of user
+func (s *UserWebAuthnCredentialService) GetAllCredentialsByUid(c *core.Context, uid int64) ([]*models.UserWebAuthnCredential, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	var credentials []*models.UserWebAuthnCredential
+	err := s.UserDB().NewSession(c).Where("uid=?", uid).OrderBy("created_unix_time desc").Find(&credentials)
+
+	return credentials, err
+}
+
+// GetCredentialByRawId returns the webauthn credential model according to the credential id generated by authenticator
+func (s *UserWebAuthnCredentialService) GetCredentialByRawId(c *core.Context, rawId string) (*models.UserWebAuthnCredential, error) {
+	if rawId == "" {
+		return nil, errs.ErrWebAuthnCredentialIdInvalid
+	}
+
+	credential := &models.UserWebAuthnCredential{}
+	has, err := s.UserDB().NewSession(c).Where("raw_id=?", rawId).Get(credential)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrWebAuthnCredentialNotFound
+	}
+
+	return credential, nil
+}
+
+// ExistsCredentials returns whether the given user has registered webauthn credentials
+func (s *UserWebAuthnCredentialService) ExistsCredentials(c *core.Context, uid int64) (bool, error) {
+	if uid <= 0 {
+		return false, errs.ErrUserIdInvalid
+	}
+
+	return s.UserDB().NewSession(c).Cols("uid").Where("uid=?", uid).Exist(&models.UserWebAuthnCredential{})
+}
+
+// CreateCredential saves a new webauthn credential model to database
+func (s *UserWebAuthnCredentialService) CreateCredential(c *core.Context, credential *models.UserWebAuthnCredential) error {
+	if credential.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if credential.RawId == "" {
+		return errs.ErrWebAuthnCredentialIdInvalid
+	}
+
+	credential.CredentialId = s.GenerateUuid(uuid.UUID_TYPE_DEFAULT)
+	credential.CreatedUnixTime = time.Now().Unix()
+
+	return s.UserDB().DoTransaction(c, func(sess *xorm.Session) error {
+		exists, err := sess.Cols("raw_id").Where("raw_id=?", credential.RawId).Exist(&models.UserWebAuthnCredential{})
+
+		if err != nil {
+			return err
+		} else if exists {
+			return errs.ErrWebAuthnCredentialAlreadyExists
+		}
+
+		_, err = sess.Insert(credential)
+		return err
+	})
+}
+
+// UpdateCredentialLastUsed saves the signature counter and last used time of webauthn credential after user logins by it
+func (s *UserWebAuthnCredentialService) UpdateCredentialLastUsed(c *core.Context, credential *models.UserWebAuthnCredential, signCount uint32) error {
+	if credential.CredentialId <= 0 {
+		return errs.ErrWebAuthnCredentialNotFound
+	}
+
+	credential.SignCount = int64(signCount)
+	credential.LastUsedUnixTime = time.Now().Unix()
+
+	return s.UserDB().DoTransaction(c, func(sess *xorm.Session) error {
+		updatedRows, err := sess.ID(credential.CredentialId).Cols("sign_count", "last_used_unix_time").Where("uid=?", credential.Uid).Update(credential)
+
+		if err != nil {
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrWebAuthnCredentialNotFound
+		}
+
+		return nil
+	})
+}
+
+// DeleteCredential deletes an existed webauthn credential of user from database
+func (s *UserWebAuthnCredentialService) DeleteCredential(c *core.Context, uid int64, credentialId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if credentialId <= 0 {
+		return errs.ErrWebAuthnCredentialIdInvalid
+	}
+
+	return s.UserDB().DoTransaction(c, func(sess *xorm.Session) error {
+		deletedRows, err := sess.ID(credentialId).Where("uid=?", uid).Delete(&models.UserWebAuthnCredential{})
+
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrWebAuthnCredentialNotFound
+		}
+
+		return nil
+	})
+}
+
+// CreateChallenge saves a new webauthn challenge model with a random id to database
+func (s *UserWebAuthnCredentialService) CreateChallenge(c *core.Context, challenge *models.UserWebAuthnChallenge) error {
+	if challenge.Challenge == "" {
+		return errs.ErrWebAuthnChallengeInvalid
+	}
+
+	challengeId, err := utils.GetRandomNumberOrLetter(webAuthnChallengeIdLength)
+
+	if err != nil {
+		return err
+	}
+
+	challenge.ChallengeId = challengeId
+	challenge.CreatedUnixTime = time.Now().Unix()
+
+	return s.UserDB().DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Insert(challenge)
+		return err
+	})
+}
+
+// GetAndDeleteChallenge returns the webauthn challenge model according to challenge id and deletes it from database,
+// so that each challenge can only be used in one verification attempt
+func (s *UserWebAuthnCredentialService) GetAndDeleteChallenge(c *core.Context, challengeId string) (*models.UserWebAuthnChallenge, error) {
+	if challengeId == "" {
+		return nil, errs.ErrWebAuthnChallengeInvalid
+	}
+
+	challenge := &models.UserWebAuthnChallenge{}
+
+	err := s.UserDB().DoTransaction(c, func(sess *xorm.Session) error {
+		has, err := sess.ID(challengeId).Get(challenge)
+
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrWebAuthnChallengeInvalid
+		}
+
+		deletedRows, err := sess.ID(challengeId).Delete(&models.UserWebAuthnChallenge{})
+
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrWebAuthnChallengeInvalid
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return challenge, nil
+}
+
+// DeleteExpiredChallenges deletes all webauthn challenges which expired before the specified unix time from database
+func (s *UserWebAuthnCredentialService) DeleteExpiredChallenges(c *core.Context, unixTime int64) (int64, error) {
+	return s.UserDB().NewSession(c).Where("expired_unix_time<?", unixTime).Delete(&models.UserWebAuthnChallenge{})
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/f97/gofire/pkg/errs"
+	"github.com/f97/gofire/pkg/models"
+)
+
+func TestGetAndDeleteChallenge_OnlyUsedOnce(t *testing.T) {
+	c := initializeTestDataStore(t)
+
+	challenge := &models.UserWebAuthnChallenge{
+		Purpose:         "login",
+		Uid:             1001,
+		Challenge:       "challenge",
+		ExpiredUnixTime: 1700000300,
+	}
+
+	err := UserWebAuthnCredentials.CreateChallenge(c, challenge)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, webAuthnChallengeIdLength, len(challenge.ChallengeId))
+
+	anotherChallenge := &models.UserWebAuthnChallenge{
+		Purpose:         "login",
+		Challenge:       "another challenge",
+		ExpiredUnixTime: 1700000300,
+	}
+
+	err = UserWebAuthnCredentials.CreateChallenge(c, anotherChallenge)
+	assert.Equal(t, nil, err)
+	assert.NotEqual(t, challenge.ChallengeId, anotherChallenge.ChallengeId)
+
+	savedChallenge, err := UserWebAuthnCredentials.GetAndDeleteChallenge(c, challenge.ChallengeId)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "login", savedChallenge.Purpose)
+	assert.Equal(t, int64(1001), savedChallenge.Uid)
+	assert.Equal(t, "challenge", savedChallenge.Challenge)
+	assert.Equal(t, int64(1700000300), savedChallenge.ExpiredUnixTime)
+
+	_, err = UserWebAuthnCredentials.GetAndDeleteChallenge(c, challenge.ChallengeId)
+	assert.Equal(t, errs.ErrWebAuthnChallengeInvalid, err)
+
+	_, err = UserWebAuthnCredentials.GetAndDeleteChallenge(c, "")
+	assert.Equal(t, errs.ErrWebAuthnChallengeInvalid, err)
+
+	savedChallenge, err = UserWebAuthnCredentials.GetAndDeleteChallenge(c, anotherChallenge.ChallengeId)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "another challenge", savedChallenge.Challenge)
+}
+
+func TestCreateChallenge_EmptyChallenge(t *testing.T) {
+	c := initializeTestDataStore(t)
+
+	err := UserWebAuthnCredentials.CreateChallenge(c, &models.UserWebAuthnChallenge{Purpose: "login"})
+	assert.Equal(t, errs.ErrWebAuthnChallengeInvalid, err)
+}
+
+func TestDeleteExpiredChallenges(t *testing.T) {
+	c := initializeTestDataStore(t)
+
+	expiredChallenge := &models.UserWebAuthnChallenge{Purpose: "login", Challenge: "expired", ExpiredUnixTime: 1700000000}
+	err := UserWebAuthnCredentials.CreateChallenge(c, expiredChallenge)
+	assert.Equal(t, nil, err)
+
+	validChallenge := &models.UserWebAuthnChallenge{Purpose: "login", Challenge: "valid", ExpiredUnixTime: 1700000300}
+	err = UserWebAuthnCredentials.CreateChallenge(c, validChallenge)
+	assert.Equal(t, nil, err)
+
+	deletedCount, err := UserWebAuthnCredentials.DeleteExpiredChallenges(c, 1700000100)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(1), deletedCount)
+
+	_, err = UserWebAuthnCredentials.GetAndDeleteChallenge(c, expiredChallenge.ChallengeId)
+	assert.Equal(t, errs.ErrWebAuthnChallengeInvalid, err)
+
+	savedChallenge, err := UserWebAuthnCredentials.GetAndDeleteChallenge(c, validChallenge.ChallengeId)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "valid", savedChallenge.Challenge)
+}
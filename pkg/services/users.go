@@ -50,16 +50,7 @@ var (
 
 // GetUserByUsernameOrEmailAndPassword returns the user model according to login name and password
 func (s *UserService) GetUserByUsernameOrEmailAndPassword(c *core.Context, loginname string, password string) (*models.User, error) {
-	var user *models.User
-	var err error
-
-	if utils.IsValidUsername(loginname) {
-		user, err = s.GetUserByUsername(c, loginname)
-	} else if utils.IsValidEmail(loginname) {
-		user, err = s.GetUserByEmail(c, loginname)
-	} else {
-		err = errs.ErrLoginNameInvalid
-	}
+	user, err := s.GetUserByUsernameOrEmail(c, loginname)
 
 	if err != nil {
 		return nil, err
@@ -72,6 +63,17 @@ func (s *UserService) GetUserByUsernameOrEmailAndPassword(c *core.Context, login
 	return user, nil
 }
 
+// GetUserByUsernameOrEmail returns the user model according to login name
+func (s *UserService) GetUserByUsernameOrEmail(c *core.Context, loginname string) (*models.User, error) {
+	if utils.IsValidUsername(loginname) {
+		return s.GetUserByUsername(c, loginname)
+	} else if utils.IsValidEmail(loginname) {
+		return s.GetUserByEmail(c, loginname)
+	}
+
+	return nil, errs.ErrLoginNameInvalid
+}
+
 // GetUserById returns the user model according to user uid
 func (s *UserService) GetUserById(c *core.Context, uid int64) (*models.User, error) {
 	if uid <= 0 {
@@ -2,6 +2,7 @@ package settings
 
 import (
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -97,6 +98,12 @@ const (
 	MonetaryAuthorityOfSingaporeDataSource string = "monetary_authority_of_singapore"
 )
 
+// Object storage types
+const (
+	LocalFileSystemObjectStorageType string = "local_filesystem"
+	S3ObjectStorageType              string = "s3"
+)
+
 const (
 	defaultAppName string = "gofire"
 
@@ -119,7 +126,19 @@ const (
 	defaultEmailVerifyTokenExpiredTime   uint32 = 3600   // 60 minutes
 	defaultPasswordResetTokenExpiredTime uint32 = 3600   // 60 minutes
 
+	defaultOIDCScopes          string = "openid profile email"
+	defaultOIDCRequestTimeout  uint32 = 10000 // 10 seconds
+	defaultOIDCDefaultLanguage string = "en"
+	defaultOIDCDefaultCurrency string = "USD"
+
 	defaultExchangeRatesDataRequestTimeout uint32 = 10000 // 10 seconds
+	defaultExchangeRatesDataCacheTTL       uint32 = 3600  // 60 minutes
+
+	defaultTrashRetentionDays uint32 = 30
+
+	defaultLocalFileSystemStoragePath string = "storage"
+	defaultS3Location                 string = "us-east-1"
+	defaultMaxAttachmentFileSize      uint32 = 10485760 // 10 MB
 )
 
 // DatabaseConfig represents the database setting config
@@ -148,6 +167,18 @@ type SMTPConfig struct {
 	FromAddress       string
 }
 
+// S3Config represents the s3 compatible object storage setting config
+type S3Config struct {
+	Endpoint        string
+	Location        string
+	UseSSL          bool
+	SkipTLSVerify   bool
+	AccessKeyID     string
+	SecretAccessKey string
+	Bucket          string
+	RootPath        string
+}
+
 // Config represents the global setting config
 type Config struct {
 	// Global
@@ -215,8 +246,33 @@ type Config struct {
 	ForgetPasswordRequireVerifyEmail bool
 	AvatarProvider                   string
 
+	// Auth
+	EnableWebAuthn      bool
+	WebAuthnRPId        string
+	WebAuthnRPName      string
+	WebAuthnOrigin      string
+	EnableOIDC          bool
+	OIDCProviderName    string
+	OIDCIssuerUrl       string
+	OIDCClientId        string
+	OIDCClientSecret    string
+	OIDCRedirectUrl     string
+	OIDCScopes          []string
+	OIDCRequestTimeout  uint32
+	OIDCAutoRegister    bool
+	OIDCDefaultLanguage string
+	OIDCDefaultCurrency string
+
 	// Data
-	EnableDataExport bool
+	EnableDataExport   bool
+	EnableDataImport   bool
+	TrashRetentionDays uint32
+
+	// Storage
+	StorageType                string
+	LocalFileSystemStoragePath string
+	S3Config                   *S3Config
+	MaxAttachmentFileSize      uint32
 
 	// Map
 	MapProvider                    string
@@ -231,7 +287,9 @@ type Config struct {
 
 	// Exchange Rates
 	ExchangeRatesDataSource     string
+	ExchangeRatesDataSources    []string
 	ExchangeRatesRequestTimeout uint32
+	ExchangeRatesCacheTTL       uint32
 }
 
 // LoadConfiguration loads setting config from given config file path
@@ -299,12 +357,24 @@ func LoadConfiguration(configFilePath string) (*Config, error) {
 		return nil, err
 	}
 
+	err = loadAuthConfiguration(config, cfgFile, "auth")
+
+	if err != nil {
+		return nil, err
+	}
+
 	err = loadDataConfiguration(config, cfgFile, "data")
 
 	if err != nil {
 		return nil, err
 	}
 
+	err = loadStorageConfiguration(config, cfgFile, "storage")
+
+	if err != nil {
+		return nil, err
+	}
+
 	err = loadMapConfiguration(config, cfgFile, "map")
 
 	if err != nil {
@@ -514,8 +584,83 @@ func loadUserConfiguration(config *Config, configFile *ini.File, sectionName str
 	return nil
 }
 
+func loadAuthConfiguration(config *Config, configFile *ini.File, sectionName string) error {
+	config.EnableWebAuthn = getConfigItemBoolValue(configFile, sectionName, "enable_webauthn", false)
+
+	if config.EnableWebAuthn {
+		config.WebAuthnRPId = getConfigItemStringValue(configFile, sectionName, "webauthn_rp_id", config.Domain)
+		config.WebAuthnRPName = getConfigItemStringValue(configFile, sectionName, "webauthn_rp_name", config.AppName)
+		config.WebAuthnOrigin = strings.TrimRight(getConfigItemStringValue(configFile, sectionName, "webauthn_origin", getRootUrlOrigin(config.RootUrl)), "/")
+
+		if config.WebAuthnRPId == "" || config.WebAuthnOrigin == "" {
+			return errs.ErrInvalidWebAuthnConfig
+		}
+	}
+
+	config.EnableOIDC = getConfigItemBoolValue(configFile, sectionName, "enable_oidc", false)
+
+	if !config.EnableOIDC {
+		return nil
+	}
+
+	config.OIDCProviderName = getConfigItemStringValue(configFile, sectionName, "oidc_provider_name", "OpenID Connect")
+	config.OIDCIssuerUrl = strings.TrimRight(getConfigItemStringValue(configFile, sectionName, "oidc_issuer_url"), "/")
+	config.OIDCClientId = getConfigItemStringValue(configFile, sectionName, "oidc_client_id")
+	config.OIDCClientSecret = getConfigItemStringValue(configFile, sectionName, "oidc_client_secret")
+	config.OIDCRedirectUrl = getConfigItemStringValue(configFile, sectionName, "oidc_redirect_url", config.RootUrl+"desktop/")
+
+	if config.OIDCIssuerUrl == "" || config.OIDCClientId == "" {
+		return errs.ErrInvalidOIDCConfig
+	}
+
+	config.OIDCScopes = strings.Fields(getConfigItemStringValue(configFile, sectionName, "oidc_scopes", defaultOIDCScopes))
+	config.OIDCRequestTimeout = getConfigItemUint32Value(configFile, sectionName, "oidc_request_timeout", defaultOIDCRequestTimeout)
+	config.OIDCAutoRegister = getConfigItemBoolValue(configFile, sectionName, "oidc_auto_register", false)
+	config.OIDCDefaultLanguage = getConfigItemStringValue(configFile, sectionName, "oidc_default_language", defaultOIDCDefaultLanguage)
+	config.OIDCDefaultCurrency = getConfigItemStringValue(configFile, sectionName, "oidc_default_currency", defaultOIDCDefaultCurrency)
+
+	return nil
+}
+
 func loadDataConfiguration(config *Config, configFile *ini.File, sectionName string) error {
 	config.EnableDataExport = getConfigItemBoolValue(configFile, sectionName, "enable_export", false)
+	config.EnableDataImport = getConfigItemBoolValue(configFile, sectionName, "enable_import", false)
+	config.TrashRetentionDays = getConfigItemUint32Value(configFile, sectionName, "trash_retention_days", defaultTrashRetentionDays)
+
+	return nil
+}
+
+func loadStorageConfiguration(config *Config, configFile *ini.File, sectionName string) error {
+	storageType := getConfigItemStringValue(configFile, sectionName, "type", LocalFileSystemObjectStorageType)
+
+	if storageType == LocalFileSystemObjectStorageType {
+		config.StorageType = LocalFileSystemObjectStorageType
+	} else if storageType == S3ObjectStorageType {
+		config.StorageType = S3ObjectStorageType
+	} else {
+		return errs.ErrInvalidStorageType
+	}
+
+	localFileSystemPath := getConfigItemStringValue(configFile, sectionName, "local_filesystem_path", defaultLocalFileSystemStoragePath)
+	finalLocalFileSystemPath, _ := getFinalPath(config.WorkingPath, localFileSystemPath)
+	config.LocalFileSystemStoragePath = finalLocalFileSystemPath
+
+	s3Config := &S3Config{}
+	s3Config.Endpoint = getConfigItemStringValue(configFile, sectionName, "s3_endpoint")
+	s3Config.Location = getConfigItemStringValue(configFile, sectionName, "s3_location", defaultS3Location)
+	s3Config.UseSSL = getConfigItemBoolValue(configFile, sectionName, "s3_use_ssl", true)
+	s3Config.SkipTLSVerify = getConfigItemBoolValue(configFile, sectionName, "s3_skip_tls_verify", false)
+	s3Config.AccessKeyID = getConfigItemStringValue(configFile, sectionName, "s3_access_key_id")
+	s3Config.SecretAccessKey = getConfigItemStringValue(configFile, sectionName, "s3_secret_access_key")
+	s3Config.Bucket = getConfigItemStringValue(configFile, sectionName, "s3_bucket")
+	s3Config.RootPath = strings.Trim(getConfigItemStringValue(configFile, sectionName, "s3_root_path"), "/")
+	config.S3Config = s3Config
+
+	if config.StorageType == S3ObjectStorageType && (s3Config.Endpoint == "" || s3Config.Bucket == "") {
+		return errs.ErrInvalidStorageType
+	}
+
+	config.MaxAttachmentFileSize = getConfigItemUint32Value(configFile, sectionName, "max_attachment_file_size", defaultMaxAttachmentFileSize)
 
 	return nil
 }
@@ -571,25 +716,46 @@ func loadMapConfiguration(config *Config, configFile *ini.File, sectionName stri
 	return nil
 }
 func loadExchangeRatesConfiguration(config *Config, configFile *ini.File, sectionName string) error {
-	dataSource := getConfigItemStringValue(configFile, sectionName, "data_source")
-
-	if dataSource == EuroCentralBankDataSource {
-		config.ExchangeRatesDataSource = EuroCentralBankDataSource
-	} else if dataSource == BankOfCanadaDataSource {
-		config.ExchangeRatesDataSource = BankOfCanadaDataSource
-	} else if dataSource == ReserveBankOfAustraliaDataSource {
-		config.ExchangeRatesDataSource = ReserveBankOfAustraliaDataSource
-	} else if dataSource == CzechNationalBankDataSource {
-		config.ExchangeRatesDataSource = CzechNationalBankDataSource
-	} else if dataSource == NationalBankOfPolandDataSource {
-		config.ExchangeRatesDataSource = NationalBankOfPolandDataSource
-	} else if dataSource == MonetaryAuthorityOfSingaporeDataSource {
-		config.ExchangeRatesDataSource = MonetaryAuthorityOfSingaporeDataSource
-	} else {
+	dataSources := strings.Split(getConfigItemStringValue(configFile, sectionName, "data_source"), ",")
+	config.ExchangeRatesDataSources = make([]string, 0, len(dataSources))
+
+	for i := 0; i < len(dataSources); i++ {
+		dataSource := strings.TrimSpace(dataSources[i])
+
+		if dataSource == "" {
+			continue
+		}
+
+		if dataSource != EuroCentralBankDataSource &&
+			dataSource != BankOfCanadaDataSource &&
+			dataSource != ReserveBankOfAustraliaDataSource &&
+			dataSource != CzechNationalBankDataSource &&
+			dataSource != NationalBankOfPolandDataSource &&
+			dataSource != MonetaryAuthorityOfSingaporeDataSource {
+			return errs.ErrInvalidExchangeRatesDataSource
+		}
+
+		duplicated := false
+
+		for j := 0; j < len(config.ExchangeRatesDataSources); j++ {
+			if config.ExchangeRatesDataSources[j] == dataSource {
+				duplicated = true
+				break
+			}
+		}
+
+		if !duplicated {
+			config.ExchangeRatesDataSources = append(config.ExchangeRatesDataSources, dataSource)
+		}
+	}
+
+	if len(config.ExchangeRatesDataSources) < 1 {
 		return errs.ErrInvalidExchangeRatesDataSource
 	}
 
+	config.ExchangeRatesDataSource = config.ExchangeRatesDataSources[0]
 	config.ExchangeRatesRequestTimeout = getConfigItemUint32Value(configFile, sectionName, "request_timeout", defaultExchangeRatesDataRequestTimeout)
+	config.ExchangeRatesCacheTTL = getConfigItemUint32Value(configFile, sectionName, "cache_ttl", defaultExchangeRatesDataCacheTTL)
 
 	return nil
 }
@@ -723,6 +889,16 @@ func getConfigItemBoolValue(configFile *ini.File, sectionName string, itemName s
 	return section.Key(itemName).MustBool(defaultValue)
 }
 
+func getRootUrlOrigin(rootUrl string) string {
+	parsedUrl, err := url.Parse(rootUrl)
+
+	if err != nil || parsedUrl.Scheme == "" || parsedUrl.Host == "" {
+		return ""
+	}
+
+	return parsedUrl.Scheme + "://" + parsedUrl.Host
+}
+
 func getEnvironmentKey(sectionName string, itemName string) string {
 	return fmt.Sprintf("%s_%s_%s", ebkEnvNamePrefix, strings.ToUpper(sectionName), strings.ToUpper(itemName))
 }
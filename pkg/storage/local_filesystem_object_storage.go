@@ -0,0 +1,135 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/f97/gofire/pkg/errs"
+)
+
+// LocalFileSystemObjectStorage represents the object storage which stores objects in local filesystem
+type LocalFileSystemObjectStorage struct {
+	rootPath string
+}
+
+// NewLocalFileSystemObjectStorage returns a local filesystem object storage with the specified root path
+func NewLocalFileSystemObjectStorage(rootPath string) (*LocalFileSystemObjectStorage, error) {
+	absRootPath, err := filepath.Abs(rootPath)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = os.MkdirAll(absRootPath, 0755)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &LocalFileSystemObjectStorage{
+		rootPath: absRootPath,
+	}, nil
+}
+
+// Exists returns whether the file of specified path exists
+func (s *LocalFileSystemObjectStorage) Exists(path string) (bool, error) {
+	fullPath, err := s.getFullPath(path)
+
+	if err != nil {
+		return false, err
+	}
+
+	_, err = os.Stat(fullPath)
+
+	if err == nil {
+		return true, nil
+	} else if os.IsNotExist(err) {
+		return false, nil
+	}
+
+	return false, err
+}
+
+// Read returns the content of the file of specified path
+func (s *LocalFileSystemObjectStorage) Read(path string) ([]byte, error) {
+	fullPath, err := s.getFullPath(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(fullPath)
+
+	if os.IsNotExist(err) {
+		return nil, errs.ErrStorageObjectNotFound
+	}
+
+	return data, err
+}
+
+// Save writes the content to the file of specified path
+func (s *LocalFileSystemObjectStorage) Save(path string, data []byte) error {
+	fullPath, err := s.getFullPath(path)
+
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(fullPath), 0755)
+
+	if err != nil {
+		return err
+	}
+
+	tempFile, err := os.CreateTemp(filepath.Dir(fullPath), filepath.Base(fullPath)+".*.tmp")
+
+	if err != nil {
+		return err
+	}
+
+	tempFilePath := tempFile.Name()
+	_, err = tempFile.Write(data)
+
+	if closeErr := tempFile.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		os.Remove(tempFilePath)
+		return err
+	}
+
+	return os.Rename(tempFilePath, fullPath)
+}
+
+// Delete removes the file of specified path
+func (s *LocalFileSystemObjectStorage) Delete(path string) error {
+	fullPath, err := s.getFullPath(path)
+
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(fullPath)
+
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+func (s *LocalFileSystemObjectStorage) getFullPath(path string) (string, error) {
+	if path == "" {
+		return "", errs.ErrStorageObjectPathInvalid
+	}
+
+	fullPath := filepath.Join(s.rootPath, filepath.FromSlash(path))
+
+	if !strings.HasPrefix(fullPath, s.rootPath+string(filepath.Separator)) {
+		return "", errs.ErrStorageObjectPathInvalid
+	}
+
+	return fullPath, nil
+}
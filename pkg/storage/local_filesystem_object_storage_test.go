@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/f97/gofire/pkg/errs"
+)
+
+func TestLocalFileSystemObjectStorage_SaveReadAndDelete(t *testing.T) {
+	storage, err := NewLocalFileSystemObjectStorage(t.TempDir())
+	assert.Equal(t, nil, err)
+
+	exists, err := storage.Exists("transaction_attachments/1/2")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, false, exists)
+
+	err = storage.Save("transaction_attachments/1/2", []byte("content"))
+	assert.Equal(t, nil, err)
+
+	exists, err = storage.Exists("transaction_attachments/1/2")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, true, exists)
+
+	data, err := storage.Read("transaction_attachments/1/2")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "content", string(data))
+
+	err = storage.Delete("transaction_attachments/1/2")
+	assert.Equal(t, nil, err)
+
+	_, err = storage.Read("transaction_attachments/1/2")
+	assert.Equal(t, errs.ErrStorageObjectNotFound, err)
+
+	err = storage.Delete("transaction_attachments/1/2")
+	assert.Equal(t, nil, err)
+}
+
+func TestLocalFileSystemObjectStorage_InvalidPath(t *testing.T) {
+	storage, err := NewLocalFileSystemObjectStorage(t.TempDir())
+	assert.Equal(t, nil, err)
+
+	err = storage.Save("../outside", []byte("content"))
+	assert.Equal(t, errs.ErrStorageObjectPathInvalid, err)
+
+	_, err = storage.Read("")
+	assert.Equal(t, errs.ErrStorageObjectPathInvalid, err)
+}
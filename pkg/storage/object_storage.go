@@ -0,0 +1,16 @@
+package storage
+
+// ObjectStorage is the interface of object storage which saves and reads binary objects by path
+type ObjectStorage interface {
+	// Exists returns whether the object of specified path exists
+	Exists(path string) (bool, error)
+
+	// Read returns the content of the object of specified path
+	Read(path string) ([]byte, error)
+
+	// Save saves the content to the object of specified path, the existed object would be overwritten
+	Save(path string, data []byte) error
+
+	// Delete deletes the object of specified path, it would not return error if the object does not exist
+	Delete(path string) error
+}
@@ -0,0 +1,76 @@
+package storage
+
+import (
+	"fmt"
+
+	"github.com/f97/gofire/pkg/errs"
+	"github.com/f97/gofire/pkg/settings"
+)
+
+const transactionAttachmentPathPrefix = "transaction_attachments"
+
+// ObjectStorageContainer contains the current object storage
+type ObjectStorageContainer struct {
+	Current ObjectStorage
+}
+
+// Initialize an object storage container singleton instance
+var (
+	Container = &ObjectStorageContainer{}
+)
+
+// InitializeStorageContainer initializes the current object storage according to the config
+func InitializeStorageContainer(config *settings.Config) error {
+	if config.StorageType == settings.LocalFileSystemObjectStorageType {
+		storage, err := NewLocalFileSystemObjectStorage(config.LocalFileSystemStoragePath)
+
+		if err != nil {
+			return err
+		}
+
+		Container.Current = storage
+		return nil
+	} else if config.StorageType == settings.S3ObjectStorageType {
+		storage, err := NewS3ObjectStorage(config.S3Config)
+
+		if err != nil {
+			return err
+		}
+
+		Container.Current = storage
+		return nil
+	}
+
+	return errs.ErrInvalidStorageType
+}
+
+// ReadTransactionAttachment returns the content of the specified transaction attachment
+func (s *ObjectStorageContainer) ReadTransactionAttachment(uid int64, attachmentId int64) ([]byte, error) {
+	if s.Current == nil {
+		return nil, errs.ErrStorageNotInitialized
+	}
+
+	return s.Current.Read(s.getTransactionAttachmentPath(uid, attachmentId))
+}
+
+// SaveTransactionAttachment saves the content of the specified transaction attachment
+func (s *ObjectStorageContainer) SaveTransactionAttachment(uid int64, attachmentId int64, data []byte) error {
+	if s.Current == nil {
+		return errs.ErrStorageNotInitialized
+	}
+
+	return s.Current.Save(s.getTransactionAttachmentPath(uid, attachmentId), data)
+}
+
+// DeleteTransactionAttachment deletes the content of the specified transaction attachment
+func (s *ObjectStorageContainer) DeleteTransactionAttachment(uid int64, attachmentId int64) error {
+	if s.Current == nil {
+		return errs.ErrStorageNotInitialized
+	}
+
+	return s.Current.Delete(s.getTransactionAttachmentPath(uid, attachmentId))
+}
+
+func (s *ObjectStorageContainer) getTransactionAttachmentPath(uid int64, attachmentId int64) string {
+	return fmt.Sprintf("%s/%d/%d", transactionAttachmentPathPrefix, uid, attachmentId)
+}
@@ -0,0 +1,224 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/f97/gofire/pkg/errs"
+	"github.com/f97/gofire/pkg/settings"
+)
+
+const s3RequestTimeout = 60 * time.Second
+const s3SignatureAlgorithm = "AWS4-HMAC-SHA256"
+const s3ServiceName = "s3"
+const s3SignedHeaders = "host;x-amz-content-sha256;x-amz-date"
+
+// S3ObjectStorage represents the object storage which stores objects in amazon s3 or s3 compatible object storage (e.g. minio),
+// the requests are signed by aws signature version 4 and use path-style bucket addressing
+type S3ObjectStorage struct {
+	config *settings.S3Config
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewS3ObjectStorage returns a s3 compatible object storage with the specified config
+func NewS3ObjectStorage(config *settings.S3Config) (*S3ObjectStorage, error) {
+	if config == nil || config.Endpoint == "" || config.Bucket == "" {
+		return nil, errs.ErrInvalidStorageType
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if config.SkipTLSVerify {
+		transport.TLSClientConfig = &tls.Config{
+			InsecureSkipVerify: true,
+		}
+	}
+
+	return &S3ObjectStorage{
+		config: config,
+		client: &http.Client{
+			Timeout:   s3RequestTimeout,
+			Transport: transport,
+		},
+		now: time.Now,
+	}, nil
+}
+
+// Exists returns whether the object of specified path exists in the bucket
+func (s *S3ObjectStorage) Exists(path string) (bool, error) {
+	resp, err := s.doRequest(http.MethodHead, path, nil)
+
+	if err != nil {
+		return false, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		return true, nil
+	} else if resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+
+	return false, errs.ErrStorageRequestFailed
+}
+
+// Read returns the content of the object of specified path in the bucket
+func (s *S3ObjectStorage) Read(path string) ([]byte, error) {
+	resp, err := s.doRequest(http.MethodGet, path, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, errs.ErrStorageObjectNotFound
+	} else if resp.StatusCode != http.StatusOK {
+		return nil, errs.ErrStorageRequestFailed
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
+// Save uploads the content to the object of specified path in the bucket
+func (s *S3ObjectStorage) Save(path string, data []byte) error {
+	resp, err := s.doRequest(http.MethodPut, path, data)
+
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return errs.ErrStorageRequestFailed
+	}
+
+	return nil
+}
+
+// Delete deletes the object of specified path in the bucket
+func (s *S3ObjectStorage) Delete(path string) error {
+	resp, err := s.doRequest(http.MethodDelete, path, nil)
+
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusNotFound {
+		return errs.ErrStorageRequestFailed
+	}
+
+	return nil
+}
+
+func (s *S3ObjectStorage) doRequest(method string, path string, data []byte) (*http.Response, error) {
+	if path == "" || strings.Contains(path, "..") {
+		return nil, errs.ErrStorageObjectPathInvalid
+	}
+
+	objectKey := strings.Trim(path, "/")
+
+	if s.config.RootPath != "" {
+		objectKey = s.config.RootPath + "/" + objectKey
+	}
+
+	scheme := "http"
+
+	if s.config.UseSSL {
+		scheme = "https"
+	}
+
+	canonicalUri := "/" + s.uriEncode(s.config.Bucket, true) + "/" + s.uriEncode(objectKey, false)
+	req, err := http.NewRequest(method, fmt.Sprintf("%s://%s%s", scheme, s.config.Endpoint, canonicalUri), bytes.NewReader(data))
+
+	if err != nil {
+		return nil, err
+	}
+
+	req.ContentLength = int64(len(data))
+	s.signRequest(req, canonicalUri, data, s.now().UTC())
+
+	return s.client.Do(req)
+}
+
+func (s *S3ObjectStorage) signRequest(req *http.Request, canonicalUri string, data []byte, requestTime time.Time) {
+	payloadHash := s.sha256Hex(data)
+	amzDate := requestTime.Format("20060102T150405Z")
+	shortDate := requestTime.Format("20060102")
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	canonicalHeaders := "host:" + req.URL.Host + "\n" +
+		"x-amz-content-sha256:" + payloadHash + "\n" +
+		"x-amz-date:" + amzDate + "\n"
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		canonicalUri,
+		"",
+		canonicalHeaders,
+		s3SignedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := strings.Join([]string{shortDate, s.config.Location, s3ServiceName, "aws4_request"}, "/")
+	stringToSign := strings.Join([]string{
+		s3SignatureAlgorithm,
+		amzDate,
+		scope,
+		s.sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	signingKey := s.hmacSha256([]byte("AWS4"+s.config.SecretAccessKey), shortDate)
+	signingKey = s.hmacSha256(signingKey, s.config.Location)
+	signingKey = s.hmacSha256(signingKey, s3ServiceName)
+	signingKey = s.hmacSha256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(s.hmacSha256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s", s3SignatureAlgorithm, s.config.AccessKeyID, scope, s3SignedHeaders, signature))
+}
+
+// uriEncode encodes the value according to the uri encoding rule of aws signature version 4
+func (s *S3ObjectStorage) uriEncode(value string, encodeSlash bool) string {
+	var builder strings.Builder
+
+	for i := 0; i < len(value); i++ {
+		ch := value[i]
+
+		if (ch >= 'A' && ch <= 'Z') || (ch >= 'a' && ch <= 'z') || (ch >= '0' && ch <= '9') || ch == '-' || ch == '_' || ch == '.' || ch == '~' {
+			builder.WriteByte(ch)
+		} else if ch == '/' && !encodeSlash {
+			builder.WriteByte(ch)
+		} else {
+			builder.WriteString(fmt.Sprintf("%%%02X", ch))
+		}
+	}
+
+	return builder.String()
+}
+
+func (s *S3ObjectStorage) sha256Hex(data []byte) string {
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
+
+func (s *S3ObjectStorage) hmacSha256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
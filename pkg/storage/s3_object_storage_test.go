@@ -0,0 +1,121 @@
+package storage
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/f97/gofire/pkg/errs"
+	"github.com/f97/gofire/pkg/settings"
+)
+
+func TestS3ObjectStorage_SaveReadAndDelete(t *testing.T) {
+	objects := make(map[string][]byte)
+	authorizations := make([]string, 0)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorizations = append(authorizations, r.Header.Get("Authorization"))
+
+		switch r.Method {
+		case http.MethodPut:
+			data, _ := io.ReadAll(r.Body)
+			objects[r.URL.Path] = data
+		case http.MethodGet, http.MethodHead:
+			data, exists := objects[r.URL.Path]
+
+			if !exists {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+
+			_, _ = w.Write(data)
+		case http.MethodDelete:
+			delete(objects, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	storage, err := NewS3ObjectStorage(&settings.S3Config{
+		Endpoint:        strings.TrimPrefix(server.URL, "http://"),
+		Location:        "us-east-1",
+		AccessKeyID:     "access_key",
+		SecretAccessKey: "secret_key",
+		Bucket:          "gofire",
+		RootPath:        "data",
+	})
+	assert.Equal(t, nil, err)
+
+	storage.now = func() time.Time {
+		return time.Date(2023, 8, 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	err = storage.Save("transaction_attachments/1/2", []byte("content"))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []byte("content"), objects["/gofire/data/transaction_attachments/1/2"])
+
+	exists, err := storage.Exists("transaction_attachments/1/2")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, true, exists)
+
+	data, err := storage.Read("transaction_attachments/1/2")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "content", string(data))
+
+	err = storage.Delete("transaction_attachments/1/2")
+	assert.Equal(t, nil, err)
+
+	_, err = storage.Read("transaction_attachments/1/2")
+	assert.Equal(t, errs.ErrStorageObjectNotFound, err)
+
+	for i := 0; i < len(authorizations); i++ {
+		assert.True(t, strings.HasPrefix(authorizations[i], "AWS4-HMAC-SHA256 Credential=access_key/20230801/us-east-1/s3/aws4_request, SignedHeaders=host;x-amz-content-sha256;x-amz-date, Signature="))
+	}
+}
+
+func TestS3ObjectStorage_UriEncode(t *testing.T) {
+	storage := &S3ObjectStorage{}
+
+	assert.Equal(t, "transaction_attachments/1/2", storage.uriEncode("transaction_attachments/1/2", false))
+	assert.Equal(t, "a%20b%2Fc~d", storage.uriEncode("a b/c~d", true))
+}
+
+// TestS3ObjectStorage_MinIO runs against a real s3 compatible object storage (e.g. a local minio server),
+// it is skipped unless GOFIRE_TEST_S3_ENDPOINT, GOFIRE_TEST_S3_ACCESS_KEY_ID, GOFIRE_TEST_S3_SECRET_ACCESS_KEY and GOFIRE_TEST_S3_BUCKET are set
+func TestS3ObjectStorage_MinIO(t *testing.T) {
+	endpoint := os.Getenv("GOFIRE_TEST_S3_ENDPOINT")
+
+	if endpoint == "" {
+		t.Skip("s3 compatible object storage is not configured")
+	}
+
+	storage, err := NewS3ObjectStorage(&settings.S3Config{
+		Endpoint:        endpoint,
+		Location:        "us-east-1",
+		UseSSL:          os.Getenv("GOFIRE_TEST_S3_USE_SSL") == "true",
+		AccessKeyID:     os.Getenv("GOFIRE_TEST_S3_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("GOFIRE_TEST_S3_SECRET_ACCESS_KEY"),
+		Bucket:          os.Getenv("GOFIRE_TEST_S3_BUCKET"),
+	})
+	assert.Equal(t, nil, err)
+
+	err = storage.Save("gofire_test/object", []byte("content"))
+	assert.Equal(t, nil, err)
+
+	data, err := storage.Read("gofire_test/object")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "content", string(data))
+
+	err = storage.Delete("gofire_test/object")
+	assert.Equal(t, nil, err)
+
+	exists, err := storage.Exists("gofire_test/object")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, false, exists)
+}
@@ -1,6 +1,11 @@
 package utils
 
-import "strconv"
+import (
+	"strconv"
+	"strings"
+
+	"github.com/f97/gofire/pkg/errs"
+)
 
 // IntToString returns the textual representation of this number
 func IntToString(num int) string {
@@ -94,3 +99,61 @@ func Float64ToString(num float64) string {
 func StringToFloat64(str string) (float64, error) {
 	return strconv.ParseFloat(str, 64)
 }
+
+// StringToAmount parses a textual representation of the amount (e.g. -123.45) to int64 amount in cents
+func StringToAmount(str string) (int64, error) {
+	return StringToDecimal(str, 2)
+}
+
+// StringToDecimal parses a textual representation of the decimal number (e.g. -123.4567) to int64 value which is multiplied by 10 to the power of decimal places
+func StringToDecimal(str string, decimalPlaces int) (int64, error) {
+	str = strings.TrimSpace(str)
+	sign := int64(1)
+
+	if strings.HasPrefix(str, "-") {
+		sign = -1
+		str = str[1:]
+	} else if strings.HasPrefix(str, "+") {
+		str = str[1:]
+	}
+
+	integer := str
+	decimals := ""
+
+	if index := strings.Index(str, "."); index >= 0 {
+		integer = str[0:index]
+		decimals = str[index+1:]
+	}
+
+	if integer == "" && decimals == "" {
+		return 0, errs.ErrFormatInvalid
+	}
+
+	if len(decimals) > decimalPlaces {
+		if strings.Trim(decimals[decimalPlaces:], "0") != "" {
+			return 0, errs.ErrFormatInvalid
+		}
+
+		decimals = decimals[0:decimalPlaces]
+	}
+
+	for len(decimals) < decimalPlaces {
+		decimals = decimals + "0"
+	}
+
+	if integer == "" {
+		integer = "0"
+	}
+
+	if strings.ContainsAny(integer, "+-") || strings.ContainsAny(decimals, "+-") {
+		return 0, errs.ErrFormatInvalid
+	}
+
+	value, err := StringToInt64(integer + decimals)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return sign * value, nil
+}
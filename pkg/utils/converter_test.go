@@ -122,3 +122,69 @@ func TestStringToFloat64(t *testing.T) {
 	assert.Equal(t, nil, err)
 	assert.Equal(t, expectedValue, actualValue)
 }
+
+func TestStringToAmount(t *testing.T) {
+	actualValue, err := StringToAmount("123.45")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(12345), actualValue)
+
+	actualValue, err = StringToAmount("-0.05")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(-5), actualValue)
+
+	actualValue, err = StringToAmount("12")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(1200), actualValue)
+
+	actualValue, err = StringToAmount("+12.3")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(1230), actualValue)
+
+	actualValue, err = StringToAmount(".5")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(50), actualValue)
+
+	actualValue, err = StringToAmount("-7.100")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(-710), actualValue)
+}
+
+func TestStringToAmount_InvalidAmount(t *testing.T) {
+	_, err := StringToAmount("")
+	assert.NotEqual(t, nil, err)
+
+	_, err = StringToAmount("1.234")
+	assert.NotEqual(t, nil, err)
+
+	_, err = StringToAmount("--1.00")
+	assert.NotEqual(t, nil, err)
+
+	_, err = StringToAmount("abc")
+	assert.NotEqual(t, nil, err)
+}
+
+func TestStringToDecimal(t *testing.T) {
+	actualValue, err := StringToDecimal("1.5", 8)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(150000000), actualValue)
+
+	actualValue, err = StringToDecimal("-0.00012345", 8)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(-12345), actualValue)
+
+	actualValue, err = StringToDecimal("123.456700", 4)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(1234567), actualValue)
+
+	actualValue, err = StringToDecimal("42", 0)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(42), actualValue)
+}
+
+func TestStringToDecimal_InvalidValue(t *testing.T) {
+	_, err := StringToDecimal("1.23456", 4)
+	assert.NotEqual(t, nil, err)
+
+	_, err = StringToDecimal("1.-5", 4)
+	assert.NotEqual(t, nil, err)
+}
@@ -162,6 +162,60 @@ func ParseFromTimezoneOffset(tzOffset string) (*time.Location, error) {
 	return time.FixedZone("Timezone", totalOffset), nil
 }
 
+// GetDaysInMonth returns the total count of days in specified year and month
+func GetDaysInMonth(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// GetLastBusinessDayOfMonth returns the last day from monday to friday in specified year and month
+func GetLastBusinessDayOfMonth(year int, month time.Month) int {
+	day := GetDaysInMonth(year, month)
+	weekDay := time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Weekday()
+
+	if weekDay == time.Saturday {
+		day = day - 1
+	} else if weekDay == time.Sunday {
+		day = day - 2
+	}
+
+	return day
+}
+
+// GetLastMonthDayEndUnixTime returns the unix time of the end of the specified day of month which is not later than the specified unix time
+func GetLastMonthDayEndUnixTime(unixTime int64, day int, timezone *time.Location) int64 {
+	t := parseFromUnixTime(unixTime).In(timezone)
+	dayEnd := time.Date(t.Year(), t.Month(), day, 23, 59, 59, 0, timezone)
+
+	if dayEnd.Unix() > unixTime {
+		dayEnd = time.Date(t.Year(), t.Month()-1, day, 23, 59, 59, 0, timezone)
+	}
+
+	return dayEnd.Unix()
+}
+
+// GetNextMonthDayEndUnixTime returns the unix time of the end of the specified day of month which is later than the specified unix time
+func GetNextMonthDayEndUnixTime(unixTime int64, day int, timezone *time.Location) int64 {
+	t := parseFromUnixTime(unixTime).In(timezone)
+	dayEnd := time.Date(t.Year(), t.Month(), day, 23, 59, 59, 0, timezone)
+
+	if dayEnd.Unix() <= unixTime {
+		dayEnd = time.Date(t.Year(), t.Month()+1, day, 23, 59, 59, 0, timezone)
+	}
+
+	return dayEnd.Unix()
+}
+
+// GetDaysBetweenUnixTimes returns the count of calendar days from the date of the first unix time to the date of the second unix time
+func GetDaysBetweenUnixTimes(fromUnixTime int64, toUnixTime int64, timezone *time.Location) int {
+	from := parseFromUnixTime(fromUnixTime).In(timezone)
+	to := parseFromUnixTime(toUnixTime).In(timezone)
+
+	fromDate := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	toDate := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+
+	return int(toDate.Sub(fromDate).Hours() / 24)
+}
+
 // GetMinTransactionTimeFromUnixTime returns the minimum transaction time from unix time
 func GetMinTransactionTimeFromUnixTime(unixTime int64) int64 {
 	return unixTime * 1000
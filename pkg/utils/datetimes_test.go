@@ -198,6 +198,71 @@ func TestParseFromTimezoneOffset(t *testing.T) {
 	assert.NotEqual(t, nil, err)
 }
 
+func TestGetDaysInMonth(t *testing.T) {
+	assert.Equal(t, 31, GetDaysInMonth(2023, time.January))
+	assert.Equal(t, 28, GetDaysInMonth(2023, time.February))
+	assert.Equal(t, 29, GetDaysInMonth(2024, time.February))
+	assert.Equal(t, 30, GetDaysInMonth(2023, time.April))
+	assert.Equal(t, 31, GetDaysInMonth(2023, time.December))
+}
+
+func TestGetLastBusinessDayOfMonth(t *testing.T) {
+	assert.Equal(t, 31, GetLastBusinessDayOfMonth(2023, time.January))   // Tuesday
+	assert.Equal(t, 31, GetLastBusinessDayOfMonth(2023, time.July))      // Monday
+	assert.Equal(t, 29, GetLastBusinessDayOfMonth(2023, time.September)) // 2023-09-30 is Saturday
+	assert.Equal(t, 28, GetLastBusinessDayOfMonth(2023, time.April))     // 2023-04-30 is Sunday
+	assert.Equal(t, 29, GetLastBusinessDayOfMonth(2024, time.March))     // 2024-03-31 is Sunday
+}
+
+func TestGetLastMonthDayEndUnixTime(t *testing.T) {
+	timezone := time.FixedZone("Test Timezone", 8*60*60)
+
+	expectedValue := time.Date(2023, time.August, 15, 23, 59, 59, 0, timezone).Unix()
+	actualValue := GetLastMonthDayEndUnixTime(time.Date(2023, time.August, 20, 10, 0, 0, 0, timezone).Unix(), 15, timezone)
+	assert.Equal(t, expectedValue, actualValue)
+
+	expectedValue = time.Date(2023, time.July, 25, 23, 59, 59, 0, timezone).Unix()
+	actualValue = GetLastMonthDayEndUnixTime(time.Date(2023, time.August, 20, 10, 0, 0, 0, timezone).Unix(), 25, timezone)
+	assert.Equal(t, expectedValue, actualValue)
+
+	expectedValue = time.Date(2022, time.December, 28, 23, 59, 59, 0, timezone).Unix()
+	actualValue = GetLastMonthDayEndUnixTime(time.Date(2023, time.January, 28, 23, 59, 58, 0, timezone).Unix(), 28, timezone)
+	assert.Equal(t, expectedValue, actualValue)
+
+	expectedValue = time.Date(2023, time.January, 28, 23, 59, 59, 0, timezone).Unix()
+	actualValue = GetLastMonthDayEndUnixTime(time.Date(2023, time.January, 28, 23, 59, 59, 0, timezone).Unix(), 28, timezone)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestGetNextMonthDayEndUnixTime(t *testing.T) {
+	timezone := time.FixedZone("Test Timezone", -5*60*60)
+
+	expectedValue := time.Date(2023, time.August, 25, 23, 59, 59, 0, timezone).Unix()
+	actualValue := GetNextMonthDayEndUnixTime(time.Date(2023, time.August, 20, 10, 0, 0, 0, timezone).Unix(), 25, timezone)
+	assert.Equal(t, expectedValue, actualValue)
+
+	expectedValue = time.Date(2024, time.January, 15, 23, 59, 59, 0, timezone).Unix()
+	actualValue = GetNextMonthDayEndUnixTime(time.Date(2023, time.December, 20, 10, 0, 0, 0, timezone).Unix(), 15, timezone)
+	assert.Equal(t, expectedValue, actualValue)
+
+	expectedValue = time.Date(2023, time.September, 15, 23, 59, 59, 0, timezone).Unix()
+	actualValue = GetNextMonthDayEndUnixTime(time.Date(2023, time.August, 15, 23, 59, 59, 0, timezone).Unix(), 15, timezone)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestGetDaysBetweenUnixTimes(t *testing.T) {
+	timezone := time.FixedZone("Test Timezone", 8*60*60)
+
+	actualValue := GetDaysBetweenUnixTimes(time.Date(2023, time.August, 20, 23, 0, 0, 0, timezone).Unix(), time.Date(2023, time.September, 5, 1, 0, 0, 0, timezone).Unix(), timezone)
+	assert.Equal(t, 16, actualValue)
+
+	actualValue = GetDaysBetweenUnixTimes(time.Date(2023, time.August, 20, 1, 0, 0, 0, timezone).Unix(), time.Date(2023, time.August, 20, 23, 0, 0, 0, timezone).Unix(), timezone)
+	assert.Equal(t, 0, actualValue)
+
+	actualValue = GetDaysBetweenUnixTimes(time.Date(2023, time.March, 2, 1, 0, 0, 0, timezone).Unix(), time.Date(2023, time.February, 27, 23, 0, 0, 0, timezone).Unix(), timezone)
+	assert.Equal(t, -3, actualValue)
+}
+
 func TestGetMinTransactionTimeFromUnixTime(t *testing.T) {
 	expectedValue := int64(1617228083000)
 	actualValue := GetMinTransactionTimeFromUnixTime(1617228083)
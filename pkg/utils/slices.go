@@ -57,3 +57,20 @@ func ToUniqueInt64Slice(items []int64) []int64 {
 
 	return uniqueItems
 }
+
+// ToUniqueStringSlice returns a string array which does not have duplicated items
+func ToUniqueStringSlice(items []string) []string {
+	uniqueItems := make([]string, 0, len(items))
+	itemExistMap := make(map[string]bool)
+
+	for i := 0; i < len(items); i++ {
+		item := items[i]
+
+		if _, exists := itemExistMap[item]; !exists {
+			uniqueItems = append(uniqueItems, item)
+			itemExistMap[item] = true
+		}
+	}
+
+	return uniqueItems
+}
@@ -130,3 +130,22 @@ func TestToUniqueInt64Slice_NilOrEmpty(t *testing.T) {
 	actualValue = ToUniqueInt64Slice(arr)
 	assert.Equal(t, expectedValue, actualValue)
 }
+
+func TestToUniqueStringSlice(t *testing.T) {
+	arr := []string{"a", "b", "c", "b", "d", "a"}
+	expectedValue := []string{"a", "b", "c", "d"}
+	actualValue := ToUniqueStringSlice(arr)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestToUniqueStringSlice_NilOrEmpty(t *testing.T) {
+	var arr []string = nil
+	expectedValue := []string{}
+	actualValue := ToUniqueStringSlice(arr)
+	assert.Equal(t, expectedValue, actualValue)
+
+	arr = []string{}
+	expectedValue = []string{}
+	actualValue = ToUniqueStringSlice(arr)
+	assert.Equal(t, expectedValue, actualValue)
+}
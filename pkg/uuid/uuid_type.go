@@ -5,11 +5,20 @@ type UuidType uint8
 
 // Types of uuid
 const (
-	UUID_TYPE_DEFAULT     UuidType = 0
-	UUID_TYPE_USER        UuidType = 1
-	UUID_TYPE_ACCOUNT     UuidType = 2
-	UUID_TYPE_TRANSACTION UuidType = 3
-	UUID_TYPE_CATEGORY    UuidType = 4
-	UUID_TYPE_TAG         UuidType = 5
-	UUID_TYPE_TAG_INDEX   UuidType = 6
+	UUID_TYPE_DEFAULT        UuidType = 0
+	UUID_TYPE_USER           UuidType = 1
+	UUID_TYPE_ACCOUNT        UuidType = 2
+	UUID_TYPE_TRANSACTION    UuidType = 3
+	UUID_TYPE_CATEGORY       UuidType = 4
+	UUID_TYPE_TAG            UuidType = 5
+	UUID_TYPE_TAG_INDEX      UuidType = 6
+	UUID_TYPE_SCHEDULE       UuidType = 7
+	UUID_TYPE_BUDGET         UuidType = 8
+	UUID_TYPE_EXTERNAL_INDEX UuidType = 9
+	UUID_TYPE_SPLIT          UuidType = 10
+	UUID_TYPE_ATTACHMENT     UuidType = 11
+	UUID_TYPE_RULE           UuidType = 12
+	UUID_TYPE_REVISION       UuidType = 13
+	UUID_TYPE_TRADE          UuidType = 14
+	UUID_TYPE_PRICE          UuidType = 15
 )
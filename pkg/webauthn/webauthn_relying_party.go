@@ -0,0 +1,413 @@
+package webauthn
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"math/big"
+	"strings"
+
+	"github.com/ugorji/go/codec"
+
+	"github.com/f97/gofire/pkg/core"
+	"github.com/f97/gofire/pkg/errs"
+	"github.com/f97/gofire/pkg/log"
+)
+
+// COSE algorithms supported by webauthn relying party
+const (
+	COSE_ALGORITHM_ES256 int = -7
+	COSE_ALGORITHM_EDDSA int = -8
+	COSE_ALGORITHM_RS256 int = -257
+)
+
+// SupportedAlgorithms represents all COSE algorithms supported by webauthn relying party in order of preference
+var SupportedAlgorithms = []int{
+	COSE_ALGORITHM_ES256,
+	COSE_ALGORITHM_EDDSA,
+	COSE_ALGORITHM_RS256,
+}
+
+const (
+	clientDataTypeCreate = "webauthn.create"
+	clientDataTypeGet    = "webauthn.get"
+
+	authenticatorDataMinLength = 37
+
+	authenticatorDataFlagUserPresent            byte = 0x01
+	authenticatorDataFlagUserVerified           byte = 0x04
+	authenticatorDataFlagAttestedCredentialData byte = 0x40
+
+	coseKeyTypeOKP = 1
+	coseKeyTypeEC2 = 2
+	coseKeyTypeRSA = 3
+
+	coseCurveP256    = 1
+	coseCurveEd25519 = 6
+
+	coseKeyParameterKeyType   = 1
+	coseKeyParameterAlgorithm = 3
+	coseKeyParameterCurve     = -1
+	coseKeyParameterX         = -2
+	coseKeyParameterY         = -3
+	coseKeyParameterN         = -1
+	coseKeyParameterE         = -2
+
+	challengeSize         = 32   // bytes
+	rsaPublicKeyMinSize   = 2048 // bits
+	credentialIdMaxLength = 384  // bytes
+)
+
+var cborHandle = func() *codec.CborHandle {
+	handle := &codec.CborHandle{}
+	handle.SignedInteger = true
+	return handle
+}()
+
+// WebAuthnRelyingParty represents the webauthn relying party which verifies the credentials created by authenticators and the assertions signed by them
+type WebAuthnRelyingParty struct {
+	Id     string
+	Name   string
+	Origin string
+}
+
+// WebAuthnCredential represents a public key credential created by authenticator
+type WebAuthnCredential struct {
+	RawId     []byte
+	PublicKey []byte
+	Algorithm int
+	SignCount uint32
+}
+
+type webAuthnClientData struct {
+	Type      string `json:"type"`
+	Challenge string `json:"challenge"`
+	Origin    string `json:"origin"`
+}
+
+type webAuthnAttestationObject struct {
+	Format               string                 `codec:"fmt"`
+	AttestationStatement map[string]interface{} `codec:"attStmt"`
+	AuthenticatorData    []byte                 `codec:"authData"`
+}
+
+type webAuthnAuthenticatorData struct {
+	RpIdHash            []byte
+	Flags               byte
+	SignCount           uint32
+	CredentialId        []byte
+	CredentialPublicKey map[int64]interface{}
+}
+
+// GenerateChallenge returns a new random challenge which is encoded by base64url
+func GenerateChallenge() (string, error) {
+	challenge := make([]byte, challengeSize)
+	_, err := rand.Read(challenge)
+
+	if err != nil {
+		return "", err
+	}
+
+	return EncodeBase64Url(challenge), nil
+}
+
+// EncodeBase64Url returns the base64url encoded string without padding of the given data
+func EncodeBase64Url(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeBase64Url returns the data of the given base64url encoded string with or without padding
+func DecodeBase64Url(value string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(value, "="))
+}
+
+// VerifyRegistration verifies the client data and attestation object returned by navigator.credentials.create and returns the created credential,
+// the attestation statement is not verified because the relying party requests "none" attestation conveyance
+func (p *WebAuthnRelyingParty) VerifyRegistration(c *core.Context, challenge string, rawId []byte, clientDataJSON []byte, attestationObjectData []byte) (*WebAuthnCredential, error) {
+	err := p.verifyClientData(c, clientDataJSON, clientDataTypeCreate, challenge)
+
+	if err != nil {
+		return nil, err
+	}
+
+	attestationObject := &webAuthnAttestationObject{}
+	err = codec.NewDecoderBytes(attestationObjectData, cborHandle).Decode(attestationObject)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[webauthn_relying_party.VerifyRegistration] failed to parse attestation object, because %s", err.Error())
+		return nil, errs.ErrWebAuthnCredentialInvalid
+	}
+
+	authenticatorData, err := p.parseAuthenticatorData(c, attestationObject.AuthenticatorData)
+
+	if err != nil {
+		return nil, errs.ErrWebAuthnCredentialInvalid
+	}
+
+	if authenticatorData.Flags&authenticatorDataFlagAttestedCredentialData == 0 || authenticatorData.CredentialPublicKey == nil {
+		log.WarnfWithRequestId(c, "[webauthn_relying_party.VerifyRegistration] authenticator data does not contain attested credential data")
+		return nil, errs.ErrWebAuthnCredentialInvalid
+	}
+
+	if len(authenticatorData.CredentialId) > credentialIdMaxLength || !bytes.Equal(authenticatorData.CredentialId, rawId) {
+		log.WarnfWithRequestId(c, "[webauthn_relying_party.VerifyRegistration] credential id of authenticator data is invalid")
+		return nil, errs.ErrWebAuthnCredentialIdInvalid
+	}
+
+	publicKey, algorithm, err := parseCredentialPublicKey(authenticatorData.CredentialPublicKey)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[webauthn_relying_party.VerifyRegistration] failed to parse credential public key, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrWebAuthnCredentialInvalid)
+	}
+
+	publicKeyData, err := x509.MarshalPKIXPublicKey(publicKey)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[webauthn_relying_party.VerifyRegistration] failed to encode credential public key, because %s", err.Error())
+		return nil, errs.ErrWebAuthnCredentialInvalid
+	}
+
+	credential := &WebAuthnCredential{
+		RawId:     authenticatorData.CredentialId,
+		PublicKey: publicKeyData,
+		Algorithm: algorithm,
+		SignCount: authenticatorData.SignCount,
+	}
+
+	return credential, nil
+}
+
+// VerifyAssertion verifies the client data, authenticator data and signature returned by navigator.credentials.get and returns the new signature counter
+func (p *WebAuthnRelyingParty) VerifyAssertion(c *core.Context, challenge string, credential *WebAuthnCredential, clientDataJSON []byte, authenticatorDataData []byte, signature []byte, requireUserVerification bool) (uint32, error) {
+	err := p.verifyClientData(c, clientDataJSON, clientDataTypeGet, challenge)
+
+	if err != nil {
+		return 0, err
+	}
+
+	authenticatorData, err := p.parseAuthenticatorData(c, authenticatorDataData)
+
+	if err != nil {
+		return 0, errs.ErrWebAuthnAssertionInvalid
+	}
+
+	if requireUserVerification && authenticatorData.Flags&authenticatorDataFlagUserVerified == 0 {
+		log.WarnfWithRequestId(c, "[webauthn_relying_party.VerifyAssertion] user is not verified by authenticator")
+		return 0, errs.ErrWebAuthnAssertionInvalid
+	}
+
+	publicKey, err := x509.ParsePKIXPublicKey(credential.PublicKey)
+
+	if err != nil {
+		log.ErrorfWithRequestId(c, "[webauthn_relying_party.VerifyAssertion] failed to parse credential public key, because %s", err.Error())
+		return 0, errs.ErrWebAuthnAssertionInvalid
+	}
+
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	signedData := make([]byte, 0, len(authenticatorDataData)+len(clientDataHash))
+	signedData = append(signedData, authenticatorDataData...)
+	signedData = append(signedData, clientDataHash[:]...)
+
+	if !verifySignature(publicKey, credential.Algorithm, signedData, signature) {
+		log.WarnfWithRequestId(c, "[webauthn_relying_party.VerifyAssertion] signature of assertion is invalid")
+		return 0, errs.ErrWebAuthnAssertionInvalid
+	}
+
+	// authenticators which do not support signature counter always return zero, otherwise the counter must be increased, or the authenticator may be cloned
+	if (authenticatorData.SignCount != 0 || credential.SignCount != 0) && authenticatorData.SignCount <= credential.SignCount {
+		log.WarnfWithRequestId(c, "[webauthn_relying_party.VerifyAssertion] signature counter %d is not greater than stored counter %d, the authenticator may be cloned", authenticatorData.SignCount, credential.SignCount)
+		return 0, errs.ErrWebAuthnAssertionInvalid
+	}
+
+	return authenticatorData.SignCount, nil
+}
+
+func (p *WebAuthnRelyingParty) verifyClientData(c *core.Context, clientDataJSON []byte, expectedType string, challenge string) error {
+	clientData := &webAuthnClientData{}
+	err := json.Unmarshal(clientDataJSON, clientData)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[webauthn_relying_party.verifyClientData] failed to parse client data, because %s", err.Error())
+		return errs.ErrWebAuthnChallengeInvalid
+	}
+
+	if clientData.Type != expectedType {
+		log.WarnfWithRequestId(c, "[webauthn_relying_party.verifyClientData] client data type \"%s\" is not \"%s\"", clientData.Type, expectedType)
+		return errs.ErrWebAuthnChallengeInvalid
+	}
+
+	if challenge == "" || strings.TrimRight(clientData.Challenge, "=") != challenge {
+		log.WarnfWithRequestId(c, "[webauthn_relying_party.verifyClientData] challenge of client data does not match")
+		return errs.ErrWebAuthnChallengeInvalid
+	}
+
+	if clientData.Origin != p.Origin {
+		log.WarnfWithRequestId(c, "[webauthn_relying_party.verifyClientData] origin \"%s\" of client data is not \"%s\"", clientData.Origin, p.Origin)
+		return errs.ErrWebAuthnChallengeInvalid
+	}
+
+	return nil
+}
+
+func (p *WebAuthnRelyingParty) parseAuthenticatorData(c *core.Context, data []byte) (*webAuthnAuthenticatorData, error) {
+	if len(data) < authenticatorDataMinLength {
+		log.WarnfWithRequestId(c, "[webauthn_relying_party.parseAuthenticatorData] authenticator data is too short")
+		return nil, errs.ErrWebAuthnCredentialInvalid
+	}
+
+	authenticatorData := &webAuthnAuthenticatorData{
+		RpIdHash:  data[0:32],
+		Flags:     data[32],
+		SignCount: binary.BigEndian.Uint32(data[33:37]),
+	}
+
+	rpIdHash := sha256.Sum256([]byte(p.Id))
+
+	if !bytes.Equal(authenticatorData.RpIdHash, rpIdHash[:]) {
+		log.WarnfWithRequestId(c, "[webauthn_relying_party.parseAuthenticatorData] relying party id hash of authenticator data does not match \"%s\"", p.Id)
+		return nil, errs.ErrWebAuthnCredentialInvalid
+	}
+
+	if authenticatorData.Flags&authenticatorDataFlagUserPresent == 0 {
+		log.WarnfWithRequestId(c, "[webauthn_relying_party.parseAuthenticatorData] user is not present")
+		return nil, errs.ErrWebAuthnCredentialInvalid
+	}
+
+	if authenticatorData.Flags&authenticatorDataFlagAttestedCredentialData == 0 {
+		return authenticatorData, nil
+	}
+
+	// attested credential data contains aaguid (16 bytes), credential id length (2 bytes), credential id and credential public key
+	attestedCredentialData := data[authenticatorDataMinLength:]
+
+	if len(attestedCredentialData) < 18 {
+		log.WarnfWithRequestId(c, "[webauthn_relying_party.parseAuthenticatorData] attested credential data is too short")
+		return nil, errs.ErrWebAuthnCredentialInvalid
+	}
+
+	credentialIdLength := int(binary.BigEndian.Uint16(attestedCredentialData[16:18]))
+
+	if len(attestedCredentialData) < 18+credentialIdLength {
+		log.WarnfWithRequestId(c, "[webauthn_relying_party.parseAuthenticatorData] credential id of attested credential data is too short")
+		return nil, errs.ErrWebAuthnCredentialInvalid
+	}
+
+	authenticatorData.CredentialId = attestedCredentialData[18 : 18+credentialIdLength]
+
+	// the credential public key may be followed by extensions, the decoder only reads the first cbor item
+	credentialPublicKey := make(map[int64]interface{})
+	err := codec.NewDecoderBytes(attestedCredentialData[18+credentialIdLength:], cborHandle).Decode(&credentialPublicKey)
+
+	if err != nil {
+		log.WarnfWithRequestId(c, "[webauthn_relying_party.parseAuthenticatorData] failed to parse credential public key, because %s", err.Error())
+		return nil, errs.ErrWebAuthnCredentialInvalid
+	}
+
+	authenticatorData.CredentialPublicKey = credentialPublicKey
+
+	return authenticatorData, nil
+}
+
+func parseCredentialPublicKey(coseKey map[int64]interface{}) (crypto.PublicKey, int, error) {
+	keyType, _ := coseKey[coseKeyParameterKeyType].(int64)
+	algorithm, _ := coseKey[coseKeyParameterAlgorithm].(int64)
+
+	if keyType == coseKeyTypeEC2 && int(algorithm) == COSE_ALGORITHM_ES256 {
+		curve, _ := coseKey[coseKeyParameterCurve].(int64)
+		x, _ := coseKey[coseKeyParameterX].([]byte)
+		y, _ := coseKey[coseKeyParameterY].([]byte)
+
+		if curve != coseCurveP256 || len(x) != 32 || len(y) != 32 {
+			return nil, 0, errs.ErrWebAuthnCredentialInvalid
+		}
+
+		uncompressedPoint := make([]byte, 0, 65)
+		uncompressedPoint = append(uncompressedPoint, 0x04)
+		uncompressedPoint = append(uncompressedPoint, x...)
+		uncompressedPoint = append(uncompressedPoint, y...)
+
+		// make sure the point is on the curve
+		_, err := ecdh.P256().NewPublicKey(uncompressedPoint)
+
+		if err != nil {
+			return nil, 0, err
+		}
+
+		publicKey := &ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}
+
+		return publicKey, COSE_ALGORITHM_ES256, nil
+	} else if keyType == coseKeyTypeOKP && int(algorithm) == COSE_ALGORITHM_EDDSA {
+		curve, _ := coseKey[coseKeyParameterCurve].(int64)
+		x, _ := coseKey[coseKeyParameterX].([]byte)
+
+		if curve != coseCurveEd25519 || len(x) != ed25519.PublicKeySize {
+			return nil, 0, errs.ErrWebAuthnCredentialInvalid
+		}
+
+		return ed25519.PublicKey(x), COSE_ALGORITHM_EDDSA, nil
+	} else if keyType == coseKeyTypeRSA && int(algorithm) == COSE_ALGORITHM_RS256 {
+		n, _ := coseKey[coseKeyParameterN].([]byte)
+		e, _ := coseKey[coseKeyParameterE].([]byte)
+
+		if len(n)*8 < rsaPublicKeyMinSize || len(e) < 1 || len(e) > 4 {
+			return nil, 0, errs.ErrWebAuthnCredentialInvalid
+		}
+
+		publicKey := &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+
+		return publicKey, COSE_ALGORITHM_RS256, nil
+	}
+
+	return nil, 0, errs.ErrWebAuthnCredentialAlgorithmNotSupported
+}
+
+func verifySignature(publicKey crypto.PublicKey, algorithm int, data []byte, signature []byte) bool {
+	switch algorithm {
+	case COSE_ALGORITHM_ES256:
+		ecdsaPublicKey, ok := publicKey.(*ecdsa.PublicKey)
+
+		if !ok {
+			return false
+		}
+
+		hash := sha256.Sum256(data)
+		return ecdsa.VerifyASN1(ecdsaPublicKey, hash[:], signature)
+	case COSE_ALGORITHM_EDDSA:
+		ed25519PublicKey, ok := publicKey.(ed25519.PublicKey)
+
+		if !ok {
+			return false
+		}
+
+		return ed25519.Verify(ed25519PublicKey, data, signature)
+	case COSE_ALGORITHM_RS256:
+		rsaPublicKey, ok := publicKey.(*rsa.PublicKey)
+
+		if !ok {
+			return false
+		}
+
+		hash := sha256.Sum256(data)
+		return rsa.VerifyPKCS1v15(rsaPublicKey, crypto.SHA256, hash[:], signature) == nil
+	}
+
+	return false
+}
@@ -0,0 +1,31 @@
+package webauthn
+
+import (
+	"github.com/f97/gofire/pkg/settings"
+)
+
+// WebAuthnRelyingPartyContainer contains the current webauthn relying party
+type WebAuthnRelyingPartyContainer struct {
+	Current *WebAuthnRelyingParty
+}
+
+// Initialize a webauthn relying party container singleton instance
+var (
+	Container = &WebAuthnRelyingPartyContainer{}
+)
+
+// InitializeWebAuthnRelyingParty initializes the webauthn relying party according to the config, the relying party is not set if webauthn is disabled
+func InitializeWebAuthnRelyingParty(config *settings.Config) error {
+	if !config.EnableWebAuthn {
+		Container.Current = nil
+		return nil
+	}
+
+	Container.Current = &WebAuthnRelyingParty{
+		Id:     config.WebAuthnRPId,
+		Name:   config.WebAuthnRPName,
+		Origin: config.WebAuthnOrigin,
+	}
+
+	return nil
+}
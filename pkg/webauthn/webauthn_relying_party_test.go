@@ -0,0 +1,320 @@
+package webauthn
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/ugorji/go/codec"
+
+	"github.com/f97/gofire/pkg/core"
+	"github.com/f97/gofire/pkg/errs"
+)
+
+const testRelyingPartyId = "gofire.example.com"
+const testRelyingPartyOrigin = "https://gofire.example.com"
+
+type testAuthenticator struct {
+	t            *testing.T
+	credentialId []byte
+	algorithm    int
+	privateKey   crypto.Signer
+	coseKey      map[int64]interface{}
+	signCount    uint32
+}
+
+func newTestAuthenticator(t *testing.T, algorithm int) *testAuthenticator {
+	authenticator := &testAuthenticator{
+		t:            t,
+		credentialId: []byte("test_credential_id"),
+		algorithm:    algorithm,
+	}
+
+	switch algorithm {
+	case COSE_ALGORITHM_ES256:
+		privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		assert.Equal(t, nil, err)
+
+		authenticator.privateKey = privateKey
+		authenticator.coseKey = map[int64]interface{}{
+			coseKeyParameterKeyType:   int64(coseKeyTypeEC2),
+			coseKeyParameterAlgorithm: int64(COSE_ALGORITHM_ES256),
+			coseKeyParameterCurve:     int64(coseCurveP256),
+			coseKeyParameterX:         privateKey.X.FillBytes(make([]byte, 32)),
+			coseKeyParameterY:         privateKey.Y.FillBytes(make([]byte, 32)),
+		}
+	case COSE_ALGORITHM_EDDSA:
+		publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+		assert.Equal(t, nil, err)
+
+		authenticator.privateKey = privateKey
+		authenticator.coseKey = map[int64]interface{}{
+			coseKeyParameterKeyType:   int64(coseKeyTypeOKP),
+			coseKeyParameterAlgorithm: int64(COSE_ALGORITHM_EDDSA),
+			coseKeyParameterCurve:     int64(coseCurveEd25519),
+			coseKeyParameterX:         []byte(publicKey),
+		}
+	case COSE_ALGORITHM_RS256:
+		privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		assert.Equal(t, nil, err)
+
+		authenticator.privateKey = privateKey
+		authenticator.coseKey = map[int64]interface{}{
+			coseKeyParameterKeyType:   int64(coseKeyTypeRSA),
+			coseKeyParameterAlgorithm: int64(COSE_ALGORITHM_RS256),
+			coseKeyParameterN:         privateKey.N.Bytes(),
+			coseKeyParameterE:         big.NewInt(int64(privateKey.E)).Bytes(),
+		}
+	}
+
+	return authenticator
+}
+
+func (a *testAuthenticator) getClientData(clientDataType string, challenge string, origin string) []byte {
+	clientDataJSON, err := json.Marshal(map[string]interface{}{
+		"type":        clientDataType,
+		"challenge":   challenge,
+		"origin":      origin,
+		"crossOrigin": false,
+	})
+	assert.Equal(a.t, nil, err)
+
+	return clientDataJSON
+}
+
+func (a *testAuthenticator) getAuthenticatorData(rpId string, flags byte, withAttestedCredentialData bool) []byte {
+	rpIdHash := sha256.Sum256([]byte(rpId))
+	data := make([]byte, 0, 256)
+	data = append(data, rpIdHash[:]...)
+	data = append(data, flags)
+	data = binary.BigEndian.AppendUint32(data, a.signCount)
+
+	if withAttestedCredentialData {
+		var coseKey []byte
+		err := codec.NewEncoderBytes(&coseKey, cborHandle).Encode(a.coseKey)
+		assert.Equal(a.t, nil, err)
+
+		data = append(data, make([]byte, 16)...)
+		data = binary.BigEndian.AppendUint16(data, uint16(len(a.credentialId)))
+		data = append(data, a.credentialId...)
+		data = append(data, coseKey...)
+	}
+
+	return data
+}
+
+func (a *testAuthenticator) createCredential(rpId string, challenge string, origin string) (clientDataJSON []byte, attestationObject []byte) {
+	clientDataJSON = a.getClientData(clientDataTypeCreate, challenge, origin)
+	authenticatorData := a.getAuthenticatorData(rpId, authenticatorDataFlagUserPresent|authenticatorDataFlagUserVerified|authenticatorDataFlagAttestedCredentialData, true)
+
+	err := codec.NewEncoderBytes(&attestationObject, cborHandle).Encode(&webAuthnAttestationObject{
+		Format:               "none",
+		AttestationStatement: map[string]interface{}{},
+		AuthenticatorData:    authenticatorData,
+	})
+	assert.Equal(a.t, nil, err)
+
+	return clientDataJSON, attestationObject
+}
+
+func (a *testAuthenticator) getAssertion(rpId string, challenge string, origin string, flags byte) (clientDataJSON []byte, authenticatorData []byte, signature []byte) {
+	clientDataJSON = a.getClientData(clientDataTypeGet, challenge, origin)
+	authenticatorData = a.getAuthenticatorData(rpId, flags, false)
+
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	signedData := append(append([]byte{}, authenticatorData...), clientDataHash[:]...)
+
+	var err error
+
+	if a.algorithm == COSE_ALGORITHM_EDDSA {
+		signature, err = a.privateKey.Sign(rand.Reader, signedData, crypto.Hash(0))
+	} else {
+		hash := sha256.Sum256(signedData)
+		signature, err = a.privateKey.Sign(rand.Reader, hash[:], crypto.SHA256)
+	}
+
+	assert.Equal(a.t, nil, err)
+
+	return clientDataJSON, authenticatorData, signature
+}
+
+func newTestRelyingParty() *WebAuthnRelyingParty {
+	return &WebAuthnRelyingParty{
+		Id:     testRelyingPartyId,
+		Name:   "gofire",
+		Origin: testRelyingPartyOrigin,
+	}
+}
+
+func newTestContext() *core.Context {
+	return &core.Context{
+		Context: &gin.Context{},
+	}
+}
+
+func TestDecodeBase64Url(t *testing.T) {
+	expectedValue := []byte{0xfb, 0xff, 0xbf}
+
+	actualValue, err := DecodeBase64Url("-_-_")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, expectedValue, actualValue)
+
+	actualValue, err = DecodeBase64Url("AQ==")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []byte{0x01}, actualValue)
+
+	assert.Equal(t, "-_-_", EncodeBase64Url(expectedValue))
+}
+
+func TestWebAuthnRelyingPartyVerifyRegistrationAndAssertion(t *testing.T) {
+	algorithms := []int{COSE_ALGORITHM_ES256, COSE_ALGORITHM_EDDSA, COSE_ALGORITHM_RS256}
+
+	for i := 0; i < len(algorithms); i++ {
+		relyingParty := newTestRelyingParty()
+		authenticator := newTestAuthenticator(t, algorithms[i])
+
+		challenge, err := GenerateChallenge()
+		assert.Equal(t, nil, err)
+
+		clientDataJSON, attestationObject := authenticator.createCredential(testRelyingPartyId, challenge, testRelyingPartyOrigin)
+		credential, err := relyingParty.VerifyRegistration(newTestContext(), challenge, authenticator.credentialId, clientDataJSON, attestationObject)
+		assert.Equal(t, nil, err)
+		assert.Equal(t, authenticator.credentialId, credential.RawId)
+		assert.Equal(t, algorithms[i], credential.Algorithm)
+		assert.Equal(t, uint32(0), credential.SignCount)
+
+		challenge, err = GenerateChallenge()
+		assert.Equal(t, nil, err)
+
+		authenticator.signCount = 1
+		clientDataJSON, authenticatorData, signature := authenticator.getAssertion(testRelyingPartyId, challenge, testRelyingPartyOrigin, authenticatorDataFlagUserPresent|authenticatorDataFlagUserVerified)
+		signCount, err := relyingParty.VerifyAssertion(newTestContext(), challenge, credential, clientDataJSON, authenticatorData, signature, true)
+		assert.Equal(t, nil, err)
+		assert.Equal(t, uint32(1), signCount)
+	}
+}
+
+func TestWebAuthnRelyingPartyVerifyRegistration_InvalidClientData(t *testing.T) {
+	relyingParty := newTestRelyingParty()
+	authenticator := newTestAuthenticator(t, COSE_ALGORITHM_ES256)
+
+	clientDataJSON, attestationObject := authenticator.createCredential(testRelyingPartyId, "challenge", testRelyingPartyOrigin)
+	_, err := relyingParty.VerifyRegistration(newTestContext(), "other_challenge", authenticator.credentialId, clientDataJSON, attestationObject)
+	assert.Equal(t, errs.ErrWebAuthnChallengeInvalid, err)
+
+	clientDataJSON, attestationObject = authenticator.createCredential(testRelyingPartyId, "challenge", "https://evil.example.com")
+	_, err = relyingParty.VerifyRegistration(newTestContext(), "challenge", authenticator.credentialId, clientDataJSON, attestationObject)
+	assert.Equal(t, errs.ErrWebAuthnChallengeInvalid, err)
+
+	_, attestationObject = authenticator.createCredential(testRelyingPartyId, "challenge", testRelyingPartyOrigin)
+	clientDataJSON = authenticator.getClientData(clientDataTypeGet, "challenge", testRelyingPartyOrigin)
+	_, err = relyingParty.VerifyRegistration(newTestContext(), "challenge", authenticator.credentialId, clientDataJSON, attestationObject)
+	assert.Equal(t, errs.ErrWebAuthnChallengeInvalid, err)
+}
+
+func TestWebAuthnRelyingPartyVerifyRegistration_InvalidRelyingPartyId(t *testing.T) {
+	relyingParty := newTestRelyingParty()
+	authenticator := newTestAuthenticator(t, COSE_ALGORITHM_ES256)
+
+	clientDataJSON, attestationObject := authenticator.createCredential("evil.example.com", "challenge", testRelyingPartyOrigin)
+	_, err := relyingParty.VerifyRegistration(newTestContext(), "challenge", authenticator.credentialId, clientDataJSON, attestationObject)
+	assert.Equal(t, errs.ErrWebAuthnCredentialInvalid, err)
+}
+
+func TestWebAuthnRelyingPartyVerifyRegistration_InvalidCredentialId(t *testing.T) {
+	relyingParty := newTestRelyingParty()
+	authenticator := newTestAuthenticator(t, COSE_ALGORITHM_ES256)
+
+	clientDataJSON, attestationObject := authenticator.createCredential(testRelyingPartyId, "challenge", testRelyingPartyOrigin)
+	_, err := relyingParty.VerifyRegistration(newTestContext(), "challenge", []byte("other_credential_id"), clientDataJSON, attestationObject)
+	assert.Equal(t, errs.ErrWebAuthnCredentialIdInvalid, err)
+}
+
+func TestWebAuthnRelyingPartyVerifyRegistration_UnsupportedAlgorithm(t *testing.T) {
+	relyingParty := newTestRelyingParty()
+	authenticator := newTestAuthenticator(t, COSE_ALGORITHM_ES256)
+	authenticator.coseKey[coseKeyParameterAlgorithm] = int64(-36)
+
+	clientDataJSON, attestationObject := authenticator.createCredential(testRelyingPartyId, "challenge", testRelyingPartyOrigin)
+	_, err := relyingParty.VerifyRegistration(newTestContext(), "challenge", authenticator.credentialId, clientDataJSON, attestationObject)
+	assert.Equal(t, errs.ErrWebAuthnCredentialAlgorithmNotSupported, err)
+}
+
+func TestWebAuthnRelyingPartyVerifyAssertion_InvalidSignature(t *testing.T) {
+	relyingParty := newTestRelyingParty()
+	authenticator := newTestAuthenticator(t, COSE_ALGORITHM_ES256)
+
+	clientDataJSON, attestationObject := authenticator.createCredential(testRelyingPartyId, "challenge", testRelyingPartyOrigin)
+	credential, err := relyingParty.VerifyRegistration(newTestContext(), "challenge", authenticator.credentialId, clientDataJSON, attestationObject)
+	assert.Equal(t, nil, err)
+
+	clientDataJSON, authenticatorData, signature := authenticator.getAssertion(testRelyingPartyId, "challenge", testRelyingPartyOrigin, authenticatorDataFlagUserPresent)
+	signature[len(signature)-1] ^= 0xff
+	_, err = relyingParty.VerifyAssertion(newTestContext(), "challenge", credential, clientDataJSON, authenticatorData, signature, false)
+	assert.Equal(t, errs.ErrWebAuthnAssertionInvalid, err)
+
+	otherAuthenticator := newTestAuthenticator(t, COSE_ALGORITHM_ES256)
+	clientDataJSON, authenticatorData, signature = otherAuthenticator.getAssertion(testRelyingPartyId, "challenge", testRelyingPartyOrigin, authenticatorDataFlagUserPresent)
+	_, err = relyingParty.VerifyAssertion(newTestContext(), "challenge", credential, clientDataJSON, authenticatorData, signature, false)
+	assert.Equal(t, errs.ErrWebAuthnAssertionInvalid, err)
+}
+
+func TestWebAuthnRelyingPartyVerifyAssertion_UserNotVerified(t *testing.T) {
+	relyingParty := newTestRelyingParty()
+	authenticator := newTestAuthenticator(t, COSE_ALGORITHM_ES256)
+
+	clientDataJSON, attestationObject := authenticator.createCredential(testRelyingPartyId, "challenge", testRelyingPartyOrigin)
+	credential, err := relyingParty.VerifyRegistration(newTestContext(), "challenge", authenticator.credentialId, clientDataJSON, attestationObject)
+	assert.Equal(t, nil, err)
+
+	authenticator.signCount = 1
+	clientDataJSON, authenticatorData, signature := authenticator.getAssertion(testRelyingPartyId, "challenge", testRelyingPartyOrigin, authenticatorDataFlagUserPresent)
+	_, err = relyingParty.VerifyAssertion(newTestContext(), "challenge", credential, clientDataJSON, authenticatorData, signature, true)
+	assert.Equal(t, errs.ErrWebAuthnAssertionInvalid, err)
+
+	signCount, err := relyingParty.VerifyAssertion(newTestContext(), "challenge", credential, clientDataJSON, authenticatorData, signature, false)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, uint32(1), signCount)
+}
+
+func TestWebAuthnRelyingPartyVerifyAssertion_SignCountNotIncreased(t *testing.T) {
+	relyingParty := newTestRelyingParty()
+	authenticator := newTestAuthenticator(t, COSE_ALGORITHM_ES256)
+
+	clientDataJSON, attestationObject := authenticator.createCredential(testRelyingPartyId, "challenge", testRelyingPartyOrigin)
+	credential, err := relyingParty.VerifyRegistration(newTestContext(), "challenge", authenticator.credentialId, clientDataJSON, attestationObject)
+	assert.Equal(t, nil, err)
+
+	credential.SignCount = 5
+	authenticator.signCount = 5
+
+	clientDataJSON, authenticatorData, signature := authenticator.getAssertion(testRelyingPartyId, "challenge", testRelyingPartyOrigin, authenticatorDataFlagUserPresent)
+	_, err = relyingParty.VerifyAssertion(newTestContext(), "challenge", credential, clientDataJSON, authenticatorData, signature, false)
+	assert.Equal(t, errs.ErrWebAuthnAssertionInvalid, err)
+}
+
+func TestWebAuthnRelyingPartyVerifyAssertion_ZeroSignCount(t *testing.T) {
+	relyingParty := newTestRelyingParty()
+	authenticator := newTestAuthenticator(t, COSE_ALGORITHM_ES256)
+
+	clientDataJSON, attestationObject := authenticator.createCredential(testRelyingPartyId, "challenge", testRelyingPartyOrigin)
+	credential, err := relyingParty.VerifyRegistration(newTestContext(), "challenge", authenticator.credentialId, clientDataJSON, attestationObject)
+	assert.Equal(t, nil, err)
+
+	for i := 0; i < 2; i++ {
+		clientDataJSON, authenticatorData, signature := authenticator.getAssertion(testRelyingPartyId, "challenge", testRelyingPartyOrigin, authenticatorDataFlagUserPresent)
+		signCount, err := relyingParty.VerifyAssertion(newTestContext(), "challenge", credential, clientDataJSON, authenticatorData, signature, false)
+		assert.Equal(t, nil, err)
+		assert.Equal(t, uint32(0), signCount)
+	}
+}
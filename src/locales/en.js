@@ -559,6 +559,8 @@ export default {
         'not implemented': 'Not implemented',
         'database operation failed': 'Database operation failed',
         'SMTP server is not enabled': 'SMTP server is not enabled',
+        'object is not found in object storage': 'File is not found in storage',
+        'failed to request object storage': 'Failed to request storage',
         'incomplete or incorrect submission': 'Incomplete or incorrect submission',
         'operation failed': 'Operation failed',
         'nothing will be updated': 'Nothing will be updated',
@@ -600,6 +602,8 @@ export default {
         'token is empty': 'Token is empty',
         'email verify token is invalid or expired': 'Email verify token is invalid or expired',
         'password reset token is invalid or expired': 'Password reset token is invalid or expired',
+        'token scope is invalid': 'Token scope is invalid',
+        'current token does not have the scope required by this operation': 'Current token does not have the scope required by this operation',
         'passcode is invalid': 'Passcode is invalid',
         'two factor backup code is invalid': 'Two factor backup code is invalid',
         'two factor is not enabled': 'Two factor is not enabled',
@@ -619,6 +623,11 @@ export default {
         'source account not found': 'Source account is not found',
         'destination account not found': 'Destination account is not found',
         'account is in use and cannot be deleted': 'Account is in use and it cannot be deleted',
+        'there are too many data points in net worth series': 'There are too many data points in net worth series, please choose a shorter time range or a longer interval',
+        'only credit card account can set credit card settings': 'Only credit card account can set statement closing day, payment due day and credit limit',
+        'account is not a credit card account': 'Account is not a credit card account',
+        'statement closing day of credit card is not set': 'Statement closing day of credit card is not set',
+        'credit card payment amount is invalid': 'Credit card payment amount is invalid',
         'transaction id is invalid': 'Transaction ID is invalid',
         'transaction not found': 'Transaction is not found',
         'transaction type is invalid': 'Transaction type is invalid',
@@ -636,6 +645,85 @@ export default {
         'cannot add transaction with this transaction time': 'You cannot add transaction with this transaction time',
         'cannot modify transaction with this transaction time': 'You cannot modify this transaction with this transaction time',
         'cannot delete transaction with this transaction time': 'You cannot delete this transaction with this transaction time',
+        'only income or expense transaction can be split': 'Only income or expense transaction can be split',
+        'transaction must have at least two split lines': 'Transaction must have at least two split lines',
+        'transaction has too many split lines': 'Transaction has too many split lines',
+        'transaction split line amount is invalid': 'Transaction split line amount is invalid',
+        'sum of transaction split line amounts does not equal to transaction amount': 'Sum of split line amounts does not equal to transaction amount',
+        'cannot restore transaction with this transaction time': 'You cannot restore this transaction with this transaction time',
+        'cannot add, modify or delete transaction in closed period': 'You cannot add, modify or delete transaction in closed period',
+        'transaction attachment id is invalid': 'Attachment ID is invalid',
+        'transaction attachment not found': 'Attachment is not found',
+        'uploaded attachment file is empty': 'Uploaded attachment file is empty',
+        'uploaded attachment file size exceeds the limit': 'Uploaded attachment file size exceeds the limit',
+        'uploaded attachment file type is not supported': 'Only image or PDF file can be uploaded as attachment',
+        'transaction has too many attachments': 'Transaction has too many attachments',
+        'transaction rule id is invalid': 'Transaction rule ID is invalid',
+        'transaction rule not found': 'Transaction rule is not found',
+        'transaction rule must have at least one condition': 'Transaction rule must have at least one condition',
+        'transaction rule must have at least one action': 'Transaction rule must have at least one action',
+        'transaction rule transaction type is invalid': 'Transaction rule transaction type is invalid',
+        'transaction rule max amount must not be less than min amount': 'Maximum amount of transaction rule must not be less than minimum amount',
+        'transaction rule must specify transaction type when setting category': 'Transaction rule must specify transaction type when setting category',
+        'book lock not found': 'Book lock is not found',
+        'book lock closed time is invalid': 'Closed time of book lock is invalid',
+        'cannot close books of account with sub accounts': 'You cannot close books of account which has sub-accounts',
+        'cannot close books before current closed time': 'You cannot close books before current closed time',
+        'cannot reopen books after current closed time': 'You cannot reopen books after current closed time',
+        'reconciliation id is invalid': 'Reconciliation ID is invalid',
+        'reconciliation not found': 'Reconciliation is not found',
+        'account already has a reconciliation in progress': 'This account already has a reconciliation in progress',
+        'reconciliation has been completed': 'Reconciliation has been completed',
+        'cleared balance does not equal to statement ending balance': 'Cleared balance does not equal to statement ending balance',
+        'statement time must be later than last reconciliation': 'Statement date must be later than last reconciliation',
+        'cannot reconcile account with sub accounts': 'You cannot reconcile account which has sub-accounts',
+        'transaction is not in reconciliation period': 'Transaction is not in reconciliation period',
+        'loan id is invalid': 'Loan ID is invalid',
+        'loan not found': 'Loan is not found',
+        'loan can only be set on debt account without sub accounts': 'Loan can only be set on debt account which has no sub-accounts',
+        'account already has a loan': 'This account already has a loan',
+        'payment account and categories must be set when creating loan transactions automatically': 'Payment account and categories must be set when creating loan transactions automatically',
+        'loan payment account is invalid': 'Loan payment account is invalid',
+        'currency of loan payment account must be equal to loan account': 'Currency of loan payment account must be equal to loan account',
+        'loan payment not found': 'Loan payment is not found',
+        'investment holding id is invalid': 'Investment holding ID is invalid',
+        'investment holding not found': 'Investment holding is not found',
+        'investment holding symbol already exists': 'Investment holding symbol already exists',
+        'holdings can only be added to investment account without sub accounts': 'Holdings can only be added to investment account which has no sub-accounts',
+        'investment cost method is invalid': 'Investment cost method is invalid',
+        'investment trade id is invalid': 'Investment trade ID is invalid',
+        'investment trade not found': 'Investment trade is not found',
+        'investment trade type is invalid': 'Investment trade type is invalid',
+        'sold units exceed held units': 'Sold units exceed held units',
+        'investment price id is invalid': 'Investment price ID is invalid',
+        'investment price not found': 'Investment price is not found',
+        'savings goal id is invalid': 'Savings goal ID is invalid',
+        'savings goal not found': 'Savings goal is not found',
+        'savings goal account is invalid': 'Savings goal account is invalid',
+        'currencies of savings goal accounts must be equal': 'Currencies of savings goal accounts must be equal',
+        'book id is invalid': 'Book ID is invalid',
+        'book not found or you are not a member': 'Book not found or you are not a member',
+        'you do not have permission to perform this operation in this book': 'You do not have permission to perform this operation in this book',
+        'book member role is invalid': 'Book member role is invalid',
+        'book member not found': 'Book member not found',
+        'user is already a member of this book': 'User is already a member of this book',
+        'cannot invite book owner': 'Cannot invite book owner',
+        'book invitation not found': 'Book invitation not found',
+        'cannot modify or remove book owner': 'Cannot modify or remove book owner',
+        'single sign-on is not enabled': 'Single sign-on is not enabled',
+        'failed to request identity provider': 'Failed to request identity provider',
+        'single sign-on state is invalid or expired': 'Single sign-on state is invalid or expired',
+        'identity token is invalid': 'Identity token is invalid',
+        'email of identity provider account is not verified': 'Email of identity provider account is not verified',
+        'no user is linked to this identity provider account': 'No user is linked to this identity provider account',
+        'webauthn is not enabled': 'Passkey is not enabled',
+        'webauthn challenge is invalid or expired': 'Passkey challenge is invalid or expired, please try again',
+        'webauthn credential is invalid': 'Passkey is invalid',
+        'webauthn assertion is invalid': 'Passkey verification failed',
+        'webauthn credential id is invalid': 'Passkey ID is invalid',
+        'webauthn credential not found': 'Passkey is not found',
+        'webauthn credential already exists': 'Passkey already exists',
+        'webauthn credential algorithm is not supported': 'The algorithm of passkey is not supported',
         'transaction category id is invalid': 'Transaction category ID is invalid',
         'transaction category not found': 'Transaction category is not found',
         'transaction category type is invalid': 'Transaction category type is invalid',
@@ -648,7 +736,33 @@ export default {
         'transaction tag name is empty': 'Transaction tag title is empty',
         'transaction tag name already exists': 'Transaction tag title already exists',
         'transaction tag is in use and cannot be deleted': 'Transaction tag is in use and it cannot be deleted',
+        'transaction schedule id is invalid': 'Transaction schedule ID is invalid',
+        'transaction schedule not found': 'Transaction schedule is not found',
+        'transaction schedule type is invalid': 'Transaction schedule type is invalid',
+        'transaction schedule frequency is invalid': 'Transaction schedule frequency is invalid',
+        'transaction schedule day of month is invalid': 'Transaction schedule day of month is invalid',
+        'transaction schedule end time must be later than start time': 'Transaction schedule end time must be later than start time',
+        'transaction schedule has no more occurrence': 'Transaction schedule has no more occurrence',
+        'transaction schedule created by loan can only be changed by loan': 'Transaction schedule created by loan can only be changed in the loan settings',
+        'transaction schedule has run during modification, please try again': 'Transaction schedule has run during modification, please try again',
+        'budget id is invalid': 'Budget ID is invalid',
+        'budget not found': 'Budget is not found',
+        'budget category must be expense category': 'Budget category must be an expense category',
+        'budget with same scope already exists': 'Budget with the same scope already exists',
+        'historical exchange rates not supported by current data source': 'Historical exchange rates are not supported by current data source',
+        'historical exchange rates not found': 'Historical exchange rates are not found',
+        'exchange rate date is invalid': 'Exchange rate date is invalid',
         'data export not allowed': 'User data export is not allowed',
+        'data import not allowed': 'User data import is not allowed',
+        'import file is empty': 'Import file is empty',
+        'import file format is invalid': 'Import file format is invalid',
+        'account in import file not found': 'Account in import file is not found',
+        'transaction category in import file not found': 'Transaction category in import file is not found',
+        'transaction tag in import file not found': 'Transaction tag in import file is not found',
+        'transaction type in import file is invalid': 'Transaction type in import file is invalid',
+        'import file is too large': 'Import file is too large',
+        'currency of import file does not match account': 'Currency of import file does not match the account',
+        'data export format is invalid': 'Data export format is invalid',
         'query items cannot be empty': 'There are no query items',
         'query items too much': 'There are too many query items',
         'query items have invalid item': 'There is invalid item in query items',